@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package monthtask
+
+import (
+	"strings"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+)
+
+func init() {
+	monthTaskDescriberRegistry[enumor.TCloud] = NewTCloudMonthDescriber
+}
+
+// NewTCloudMonthDescriber ...
+func NewTCloudMonthDescriber(rootAccountCloudID string) MonthTaskDescriber {
+	describer := &tcloudMonthDescriber{
+		RootAccountCloudID: rootAccountCloudID,
+	}
+	// set exclude account id
+	commonExpenseConfig := cc.AccountServer().BillAllocation.TCloudCommonExpense
+	describer.CommonExpenseExcludeCloudIDs = commonExpenseConfig.ExcludeAccountCloudIDs
+
+	return describer
+}
+
+// tcloudMonthDescriber tcloud month task describer
+type tcloudMonthDescriber struct {
+	RootAccountCloudID           string
+	CommonExpenseExcludeCloudIDs []string
+}
+
+// GetMonthTaskTypes tcloud month tasks
+func (tcloud *tcloudMonthDescriber) GetMonthTaskTypes() []enumor.MonthTaskType {
+	// 集团管理账号自身的支出需要分摊到各个成员账号
+	return []enumor.MonthTaskType{enumor.TCloudCommonExpenseMonthTask}
+}
+
+// GetTaskExtension extension for task
+func (tcloud *tcloudMonthDescriber) GetTaskExtension() (map[string]string, error) {
+
+	return map[string]string{
+		constant.TCloudCommonExpenseExcludeCloudIDKey: strings.Join(tcloud.CommonExpenseExcludeCloudIDs, ","),
+	}, nil
+}
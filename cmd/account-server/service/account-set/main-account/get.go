@@ -69,6 +69,12 @@ func (s *service) Get(cts *rest.Contexts) (interface{}, error) {
 			account.Extension.CloudInitPassword = ""
 		}
 		return account, err
+	case enumor.TCloud:
+		account, err := s.client.DataService().TCloud.MainAccount.Get(cts.Kit, accountID)
+		if account != nil {
+			account.Extension.CloudInitPassword = ""
+		}
+		return account, err
 	case enumor.Zenlayer:
 		account, err := s.client.DataService().Zenlayer.MainAccount.Get(cts.Kit, accountID)
 		if account != nil {
@@ -75,6 +75,8 @@ func (s *service) QueryRootAccountBySecret(cts *rest.Contexts) (interface{}, err
 	}
 
 	switch vendor {
+	case enumor.TCloud:
+		return s.getTCloudAccountInfo(cts)
 	case enumor.HuaWei:
 		return s.getHuaWeiAccountInfo(cts)
 	case enumor.Aws:
@@ -88,6 +90,24 @@ func (s *service) QueryRootAccountBySecret(cts *rest.Contexts) (interface{}, err
 	}
 }
 
+func (s *service) getTCloudAccountInfo(cts *rest.Contexts) (*cloud.TCloudInfoBySecret, error) {
+	req := new(accountset.TCloudAccountInfoBySecretReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	info, err := s.client.HCService().TCloud.Account.GetBySecret(cts.Kit.Ctx, cts.Kit.Header(), req.TCloudSecret)
+	if err != nil {
+		logs.Errorf("fail to get tcloud account info, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return info, nil
+}
+
 func (s *service) getHuaWeiAccountInfo(cts *rest.Contexts) (*cloud.HuaWeiInfoBySecret, error) {
 	req := new(accountset.HuaWeiAccountInfoBySecretReq)
 	if err := cts.DecodeInto(req); err != nil {
@@ -68,6 +68,12 @@ func (s *service) GetRootAccount(cts *rest.Contexts) (interface{}, error) {
 			account.Extension.CloudSecretKey = ""
 		}
 		return account, err
+	case enumor.TCloud:
+		account, err := s.client.DataService().TCloud.RootAccount.Get(cts.Kit, accountID)
+		if account != nil {
+			account.Extension.CloudSecretKey = ""
+		}
+		return account, err
 	case enumor.Zenlayer:
 		account, err := s.client.DataService().Zenlayer.RootAccount.Get(cts.Kit, accountID)
 		// zenlayer not support store secret info
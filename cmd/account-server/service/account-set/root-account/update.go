@@ -63,6 +63,8 @@ func (s *service) UpdateRootAccount(cts *rest.Contexts) (interface{}, error) {
 		result, err = s.updateForAws(cts, req, accountID)
 	case enumor.HuaWei:
 		result, err = s.updateForHuaWei(cts, req, accountID)
+	case enumor.TCloud:
+		result, err = s.updateForTCloud(cts, req, accountID)
 	case enumor.Gcp:
 		result, err = s.updateForGcp(cts, req, accountID)
 	case enumor.Azure:
@@ -274,6 +276,52 @@ func (s *service) updateForHuaWei(cts *rest.Contexts, req *proto.RootAccountUpda
 	return nil, nil
 }
 
+func (s *service) updateForTCloud(cts *rest.Contexts, req *proto.RootAccountUpdateReq, accountID string) (interface{}, error) {
+	var (
+		extension *proto.TCloudRootAccountExtensionUpdateReq
+	)
+	if req.Extension != nil {
+		// 解析Extension
+		extension = new(proto.TCloudRootAccountExtensionUpdateReq)
+		if err := common.DecodeExtension(cts.Kit, req.Extension, extension); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		// 校验Extension
+		err := extension.Validate()
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+	var shouldUpdatedExtension *dataproto.TCloudRootAccountExtensionUpdateReq = nil
+	if req.Extension != nil {
+		shouldUpdatedExtension = &dataproto.TCloudRootAccountExtensionUpdateReq{
+			CloudSubAccountID: extension.CloudSubAccountID,
+			CloudSecretID:     &extension.CloudSecretID,
+			CloudSecretKey:    &extension.CloudSecretKey,
+		}
+	}
+
+	// 更新
+	_, err := s.client.DataService().TCloud.RootAccount.Update(
+		cts.Kit,
+		accountID,
+		&dataproto.RootAccountUpdateReq[dataproto.TCloudRootAccountExtensionUpdateReq]{
+			Name:        req.Name,
+			Managers:    req.Managers,
+			BakManagers: req.BakManagers,
+			Memo:        req.Memo,
+			DeptID:      req.DeptID,
+			Extension:   shouldUpdatedExtension,
+		},
+	)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, nil
+}
+
 func (s *service) updateForZenlayer(cts *rest.Contexts, req *proto.RootAccountUpdateReq, accountID string) (interface{}, error) {
 	var (
 		extension *proto.ZenlayerRootAccountExtensionUpdateReq
@@ -0,0 +1,170 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package allocation
+
+import (
+	"hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateBillAllocationRule 创建账单分摊规则
+func (s *service) CreateBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(bill.AllocationRuleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Create}})
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &dsbill.BatchCreateAllocationRuleReq{
+		Rules: []dsbill.AllocationRuleCreate{{
+			Name:     req.Name,
+			Vendor:   req.Vendor,
+			Priority: req.Priority,
+			Enabled:  req.Enabled,
+			Matcher:  req.Matcher,
+			Method:   req.Method,
+			Config:   req.Config,
+			Memo:     req.Memo,
+		}},
+	}
+	result, err := s.client.DataService().Global.Bill.BatchCreateBillAllocationRule(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("fail to create bill allocation rule, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.IDs) != 1 {
+		return nil, errf.Newf(errf.Unknown, "create bill allocation rule but got %d ids", len(result.IDs))
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// ListBillAllocationRule 查询账单分摊规则
+func (s *service) ListBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.DataService().Global.Bill.ListBillAllocationRule(cts.Kit, req)
+}
+
+// UpdateBillAllocationRule 更新账单分摊规则
+func (s *service) UpdateBillAllocationRule(cts *rest.Contexts) (any, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+	req := new(bill.AllocationRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Update}})
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dsbill.AllocationRuleUpdateReq{
+		ID:       id,
+		Name:     req.Name,
+		Priority: req.Priority,
+		Enabled:  req.Enabled,
+		Matcher:  req.Matcher,
+		Method:   req.Method,
+		Config:   req.Config,
+		Memo:     req.Memo,
+	}
+	if err = s.client.DataService().Global.Bill.UpdateBillAllocationRule(cts.Kit, updateReq); err != nil {
+		logs.Errorf("fail to update bill allocation rule, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteBillAllocationRule 删除账单分摊规则，已产生的分摊结果保留
+func (s *service) DeleteBillAllocationRule(cts *rest.Contexts) (any, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Delete}})
+	if err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err = s.client.DataService().Global.Bill.BatchDeleteBillAllocationRule(cts.Kit, delReq); err != nil {
+		logs.Errorf("fail to delete bill allocation rule, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillAllocationResult 查询账单分摊结果
+func (s *service) ListBillAllocationResult(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.DataService().Global.Bill.ListBillAllocationResult(cts.Kit, req)
+}
@@ -86,7 +86,7 @@ func (b *billItemSvc) PullBillItemForThirdParty(cts *rest.Contexts) (any, error)
 
 func isSupportedVendor(vendor enumor.Vendor) bool {
 	switch vendor {
-	case enumor.Aws, enumor.HuaWei, enumor.Gcp, enumor.Zenlayer:
+	case enumor.Aws, enumor.HuaWei, enumor.Gcp, enumor.Zenlayer, enumor.TCloud:
 		return true
 
 	default:
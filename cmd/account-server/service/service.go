@@ -32,6 +32,7 @@ import (
 	"hcm/cmd/account-server/logics/bill"
 	mainaccount "hcm/cmd/account-server/service/account-set/main-account"
 	rootaccount "hcm/cmd/account-server/service/account-set/root-account"
+	"hcm/cmd/account-server/service/bill/allocation"
 	"hcm/cmd/account-server/service/bill/billadjustment"
 	"hcm/cmd/account-server/service/bill/billitem"
 	"hcm/cmd/account-server/service/bill/billsummarybiz"
@@ -126,9 +127,7 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.NewFromConfig(cryptoConfig)
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -212,6 +211,7 @@ func (s *Service) apiSet() *restful.Container {
 	billadjustment.InitBillAdjustmentService(c)
 	billsyncrecord.InitService(c)
 	exchangerate.InitService(c)
+	allocation.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
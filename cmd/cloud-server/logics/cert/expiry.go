@@ -0,0 +1,267 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	corecert "hcm/pkg/api/core/cloud/cert"
+	gccore "hcm/pkg/api/core/global-config"
+	datagconf "hcm/pkg/api/data-service/global_config"
+	"hcm/pkg/cc"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/slice"
+)
+
+const day = 24 * time.Hour
+
+// ExpiryNotifier 扫描即将过期的证书，在证书剩余有效期到达配置的阈值时发送邮件通知
+type ExpiryNotifier struct {
+	client   *dataservice.Client
+	cmsiCli  cmsi.Client
+	bkHcmUrl string
+	conf     cc.CertExpiry
+}
+
+// NewExpiryNotifier new cert expiry notifier.
+func NewExpiryNotifier(client *dataservice.Client, cmsiCli cmsi.Client, bkHcmUrl string,
+	conf cc.CertExpiry) *ExpiryNotifier {
+
+	return &ExpiryNotifier{
+		client:   client,
+		cmsiCli:  cmsiCli,
+		bkHcmUrl: bkHcmUrl,
+		conf:     conf,
+	}
+}
+
+// expiringCert 到达通知阈值的证书
+type expiringCert struct {
+	Cert      corecert.BaseCert
+	ExpiredAt time.Time
+	Threshold uint
+}
+
+// Notify 通知在 (lastScan, now] 区间内到达通知阈值的证书，每个阈值只会在一个扫描区间内命中，从而避免重复通知
+func (n *ExpiryNotifier) Notify(kt *kit.Kit, lastScan, now time.Time) error {
+	expiring := make([]expiringCert, 0)
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("vendor", enumor.TCloud)),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		Fields: []string{"id", "cloud_id", "name", "vendor", "bk_biz_id", "account_id", "domain", "cert_type",
+			"cloud_expired_time", "creator"},
+	}
+	for {
+		result, err := n.client.Global.ListCert(kt, listReq)
+		if err != nil {
+			logs.Errorf("list cert for expiry notice failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		for _, one := range result.Details {
+			if len(one.CloudExpiredTime) == 0 {
+				continue
+			}
+
+			expiredAt, err := time.Parse(constant.TimeStdFormat, one.CloudExpiredTime)
+			if err != nil {
+				logs.Errorf("parse cert(%s) expired time failed, err: %v, time: %s, rid: %s", one.ID, err,
+					one.CloudExpiredTime, kt.Rid)
+				continue
+			}
+
+			threshold, hit := HitNoticeThreshold(expiredAt, lastScan, now, n.conf.NoticeDays)
+			if !hit {
+				continue
+			}
+			expiring = append(expiring, expiringCert{Cert: one, ExpiredAt: expiredAt, Threshold: threshold})
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	// 按证书创建人汇总通知，后台同步的证书没有实际的创建者，只通知配置的接收人
+	creatorCerts := make(map[string][]expiringCert)
+	for _, one := range expiring {
+		creator := one.Cert.Creator
+		if creator == constant.BackendOperationUserKey {
+			creator = ""
+		}
+		creatorCerts[creator] = append(creatorCerts[creator], one)
+	}
+
+	var sendErr error
+	for creator, certs := range creatorCerts {
+		receivers := make([]string, 0, len(n.conf.Receivers)+1)
+		if len(creator) != 0 {
+			receivers = append(receivers, creator)
+		}
+		receivers = slice.Unique(append(receivers, n.conf.Receivers...))
+		if len(receivers) == 0 {
+			logs.Warnf("no receiver for cert expiry notice, certs: %d, rid: %s", len(certs), kt.Rid)
+			continue
+		}
+		if err := n.sendNotice(kt, receivers, certs); err != nil {
+			sendErr = err
+		}
+	}
+
+	return sendErr
+}
+
+// NotifyFromWatermark 通知租户在 (水位, now] 区间内到达通知阈值的证书，水位持久化在全局配置中，全部通知发送成功后才推进水位，
+// 避免服务重启、主节点切换或发送失败导致漏发通知。发送部分失败时下次扫描会重新发送该区间的通知，部分接收人可能收到重复通知
+func (n *ExpiryNotifier) NotifyFromWatermark(kt *kit.Kit, now time.Time) error {
+	watermark, err := n.getWatermark(kt)
+	if err != nil {
+		return err
+	}
+
+	// 首次扫描从当前时间开始，不补发历史通知
+	if watermark == nil {
+		return n.saveWatermark(kt, "", now)
+	}
+
+	if err = n.Notify(kt, watermark.Time, now); err != nil {
+		return err
+	}
+
+	return n.saveWatermark(kt, watermark.ID, now)
+}
+
+// noticeWatermark 租户证书过期通知的扫描水位
+type noticeWatermark struct {
+	ID   string
+	Time time.Time
+}
+
+// getWatermark 获取租户证书过期通知的扫描水位，不存在时返回nil
+func (n *ExpiryNotifier) getWatermark(kt *kit.Kit) (*noticeWatermark, error) {
+	listReq := &datagconf.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("config_type", constant.CertExpiryNoticeWatermark),
+			tools.RuleEqual("config_key", kt.TenantID),
+		),
+		Page: &core.BasePage{Limit: 1},
+	}
+	result, err := n.client.Global.GlobalConfig.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list cert expiry notice watermark failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	sec, err := strconv.ParseInt(string(result.Details[0].ConfigValue), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse cert expiry notice watermark failed, err: %v, value: %s", err,
+			result.Details[0].ConfigValue)
+	}
+
+	return &noticeWatermark{ID: result.Details[0].ID, Time: time.Unix(sec, 0)}, nil
+}
+
+// saveWatermark 保存租户证书过期通知的扫描水位，id 为空时创建
+func (n *ExpiryNotifier) saveWatermark(kt *kit.Kit, id string, scanAt time.Time) error {
+	if len(id) == 0 {
+		createReq := &datagconf.BatchCreateReq{
+			Configs: []gccore.GlobalConfigT[any]{{
+				ConfigType:  constant.CertExpiryNoticeWatermark,
+				ConfigKey:   kt.TenantID,
+				ConfigValue: scanAt.Unix(),
+			}},
+		}
+		if _, err := n.client.Global.GlobalConfig.BatchCreate(kt, createReq); err != nil {
+			logs.Errorf("create cert expiry notice watermark failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+		return nil
+	}
+
+	updateReq := &datagconf.BatchUpdateReq{
+		Configs: []gccore.GlobalConfigT[any]{{ID: id, ConfigValue: scanAt.Unix()}},
+	}
+	if err := n.client.Global.GlobalConfig.BatchUpdate(kt, updateReq); err != nil {
+		logs.Errorf("update cert expiry notice watermark failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// HitNoticeThreshold 判断证书是否在 (lastScan, now] 区间内到达某个通知阈值，即过期前阈值天数的时间点落在该区间内，
+// 同时命中多个阈值时返回最小的阈值
+func HitNoticeThreshold(expiredAt, lastScan, now time.Time, noticeDays []uint) (uint, bool) {
+	days := append([]uint(nil), noticeDays...)
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	for _, threshold := range days {
+		noticeAt := expiredAt.Add(-time.Duration(threshold) * day)
+		if noticeAt.After(lastScan) && !noticeAt.After(now) {
+			return threshold, true
+		}
+	}
+	return 0, false
+}
+
+func (n *ExpiryNotifier) sendNotice(kt *kit.Kit, receivers []string, certs []expiringCert) error {
+	sort.Slice(certs, func(i, j int) bool { return certs[i].ExpiredAt.Before(certs[j].ExpiredAt) })
+
+	items := make([]string, 0, len(certs))
+	for _, one := range certs {
+		domains := make([]string, 0, len(one.Cert.Domain))
+		for _, domain := range one.Cert.Domain {
+			if domain != nil {
+				domains = append(domains, *domain)
+			}
+		}
+		items = append(items, fmt.Sprintf("<li>%s（%s），域名：%s，业务：%d，过期时间：%s，剩余不足%d天</li>",
+			one.Cert.Name, one.Cert.CloudID, strings.Join(domains, ","), one.Cert.BkBizID, one.Cert.CloudExpiredTime,
+			one.Threshold))
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(receivers, ","),
+		Title:            fmt.Sprintf("【HCM】 SSL证书即将过期：共%d个", len(certs)),
+		Content: fmt.Sprintf(`<p>您好：</p><p>以下SSL证书即将过期，过期后使用该证书的负载均衡监听器将无法正常提供HTTPS服务，`+
+			`请尽快在 <a href="%s">HCM</a> 中上传新证书并替换监听器上的证书。</p><ul>%s</ul>`, n.bkHcmUrl,
+			strings.Join(items, "")),
+	}
+	if err := n.cmsiCli.SendMail(kt, mail); err != nil {
+		logs.Errorf("send cert expiry notice failed, err: %v, receivers: %v, rid: %s", err, receivers, kt.Rid)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"testing"
+	"time"
+
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitNoticeThreshold(t *testing.T) {
+	expiredAt := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	noticeDays := []uint{30, 7, 1}
+
+	// 过期前30天的时间点落在扫描区间内
+	lastScan := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+	threshold, hit := HitNoticeThreshold(expiredAt, lastScan, lastScan.Add(time.Hour), noticeDays)
+	assert.True(t, hit)
+	assert.Equal(t, uint(30), threshold)
+
+	// 下一个扫描区间不再重复命中
+	_, hit = HitNoticeThreshold(expiredAt, lastScan.Add(time.Hour), lastScan.Add(2*time.Hour), noticeDays)
+	assert.False(t, hit)
+
+	// 扫描区间同时覆盖多个阈值时返回最小的阈值
+	threshold, hit = HitNoticeThreshold(expiredAt, lastScan, expiredAt.Add(-time.Hour), noticeDays)
+	assert.True(t, hit)
+	assert.Equal(t, uint(1), threshold)
+
+	// 已过期的证书不再通知
+	_, hit = HitNoticeThreshold(expiredAt, expiredAt, expiredAt.Add(time.Hour), noticeDays)
+	assert.False(t, hit)
+}
+
+func TestReplaceCertificate(t *testing.T) {
+	info := &corelb.TCloudCertificateInfo{
+		SSLMode:      converter.ValToPtr("MUTUAL"),
+		CaCloudID:    converter.ValToPtr("ca-old"),
+		CertCloudIDs: []string{"svr-old", "svr-ecc"},
+	}
+
+	assert.True(t, info.UsesCert("svr-old"))
+	assert.True(t, info.UsesCert("ca-old"))
+	assert.False(t, info.UsesCert("svr-new"))
+
+	replaced := info.ReplaceCert("svr-old", "svr-new")
+	assert.Equal(t, []string{"svr-new", "svr-ecc"}, replaced.CertCloudIDs)
+	assert.Equal(t, "ca-old", *replaced.CaCloudID)
+	assert.Equal(t, []string{"svr-old", "svr-ecc"}, info.CertCloudIDs)
+
+	replaced = info.ReplaceCert("ca-old", "ca-new")
+	assert.Equal(t, "ca-new", *replaced.CaCloudID)
+	assert.Equal(t, "ca-old", *info.CaCloudID)
+
+	var empty *corelb.TCloudCertificateInfo
+	assert.False(t, empty.UsesCert("svr-old"))
+	assert.Nil(t, empty.ReplaceCert("svr-old", "svr-new"))
+}
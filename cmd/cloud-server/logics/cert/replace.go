@@ -0,0 +1,210 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"sort"
+
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	proto "hcm/pkg/api/cloud-server/cert"
+	"hcm/pkg/api/core"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/slice"
+)
+
+// certListenerProtocols 可以使用证书的监听器协议
+var certListenerProtocols = []enumor.ProtocolType{enumor.HttpsProtocol, enumor.TcpSslProtocol, enumor.QuicProtocol}
+
+// Usage 证书在负载均衡上的使用情况，未开启SNI的监听器证书配置在监听器上，开启SNI的监听器证书配置在域名上
+type Usage struct {
+	// Listeners 在监听器上使用证书的监听器，key为负载均衡ID
+	Listeners map[string][]actionlb.ReplaceCertListener
+	// Domains 在域名上使用证书的域名，key为负载均衡ID
+	Domains map[string][]actionlb.ReplaceCertDomain
+}
+
+// ListenerCount 使用证书的监听器数量
+func (u *Usage) ListenerCount() int {
+	count := 0
+	for _, one := range u.Listeners {
+		count += len(one)
+	}
+	return count
+}
+
+// DomainCount 使用证书的域名数量
+func (u *Usage) DomainCount() int {
+	count := 0
+	for _, one := range u.Domains {
+		count += len(one)
+	}
+	return count
+}
+
+// ListTCloudUsage 查询账号下指定业务中使用了证书的腾讯云监听器和域名
+func ListTCloudUsage(kt *kit.Kit, cli *dataservice.Client, accountID string, bizID int64, certCloudID string) (
+	*Usage, error) {
+
+	usage := &Usage{
+		Listeners: make(map[string][]actionlb.ReplaceCertListener),
+		Domains:   make(map[string][]actionlb.ReplaceCertDomain),
+	}
+
+	sniLblIDs := make([]string, 0)
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("bk_biz_id", bizID),
+			tools.RuleIn("protocol", certListenerProtocols),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	for {
+		lblResp, err := cli.TCloud.LoadBalancer.ListListener(kt, listReq)
+		if err != nil {
+			logs.Errorf("list listener for cert usage failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+			return nil, err
+		}
+
+		for _, lbl := range lblResp.Details {
+			if lbl.SniSwitch == enumor.SniTypeOpen {
+				sniLblIDs = append(sniLblIDs, lbl.ID)
+				continue
+			}
+
+			if lbl.Extension == nil || !lbl.Extension.Certificate.UsesCert(certCloudID) {
+				continue
+			}
+			usage.Listeners[lbl.LbID] = append(usage.Listeners[lbl.LbID], actionlb.ReplaceCertListener{
+				ListenerID:  lbl.ID,
+				Certificate: lbl.Extension.Certificate,
+			})
+		}
+
+		if uint(len(lblResp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	for _, ids := range slice.Split(sniLblIDs, constant.BatchOperationMaxLimit) {
+		if err := listDomainUsage(kt, cli, ids, certCloudID, usage); err != nil {
+			return nil, err
+		}
+	}
+
+	return usage, nil
+}
+
+// listDomainUsage 查询开启SNI的监听器下使用了证书的域名，同一域名下的规则共用域名的证书配置
+func listDomainUsage(kt *kit.Kit, cli *dataservice.Client, lblIDs []string, certCloudID string,
+	usage *Usage) error {
+
+	visited := make(map[string]struct{})
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("lbl_id", lblIDs),
+			tools.RuleEqual("rule_type", enumor.Layer7RuleType),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	for {
+		ruleResp, err := cli.TCloud.LoadBalancer.ListUrlRule(kt, listReq)
+		if err != nil {
+			logs.Errorf("list url rule for cert usage failed, err: %v, lbl ids: %v, rid: %s", err, lblIDs, kt.Rid)
+			return err
+		}
+
+		for _, rule := range ruleResp.Details {
+			key := rule.LblID + "/" + rule.Domain
+			if _, exist := visited[key]; exist {
+				continue
+			}
+			visited[key] = struct{}{}
+
+			if !rule.Certificate.UsesCert(certCloudID) {
+				continue
+			}
+			usage.Domains[rule.LbID] = append(usage.Domains[rule.LbID], actionlb.ReplaceCertDomain{
+				ListenerID:  rule.LblID,
+				Domain:      rule.Domain,
+				Certificate: rule.Certificate,
+			})
+		}
+
+		if uint(len(ruleResp.Details)) < listReq.Page.Limit {
+			return nil
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+}
+
+// BuildReplaceTasks 按负载均衡拆分证书替换任务，同一个负载均衡下的监听器、域名在一个任务中串行替换，避免云上并发变更冲突。
+// 各负载均衡的任务相互独立，某个负载均衡替换失败时只会恢复该负载均衡已替换的证书，不会回滚其他已完成的负载均衡，
+// 因此同时返回负载均衡与子任务的对应关系，调用方可按子任务状态确认部分成功的结果
+func BuildReplaceTasks(vendor enumor.Vendor, usage *Usage, oldCloudID, newCloudID string) ([]ts.CustomFlowTask,
+	[]proto.ReplaceCertTask) {
+
+	lbIDs := make([]string, 0, len(usage.Listeners)+len(usage.Domains))
+	for lbID := range usage.Listeners {
+		lbIDs = append(lbIDs, lbID)
+	}
+	for lbID := range usage.Domains {
+		lbIDs = append(lbIDs, lbID)
+	}
+	lbIDs = slice.Unique(lbIDs)
+	sort.Strings(lbIDs)
+
+	getNextID := counter.NewNumStringCounter(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0, len(lbIDs))
+	lbTasks := make([]proto.ReplaceCertTask, 0, len(lbIDs))
+	for _, lbID := range lbIDs {
+		actionID := getNextID()
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(actionID),
+			ActionName: enumor.ActionListenerReplaceCert,
+			Params: &actionlb.ListenerReplaceCertOption{
+				Vendor:         vendor,
+				LbID:           lbID,
+				OldCertCloudID: oldCloudID,
+				NewCertCloudID: newCloudID,
+				Listeners:      usage.Listeners[lbID],
+				Domains:        usage.Domains[lbID],
+			},
+			Retry: tableasync.NewRetryWithPolicy(3, 1000, 5000),
+		})
+		lbTasks = append(lbTasks, proto.ReplaceCertTask{
+			LbID:          lbID,
+			ActionID:      actionID,
+			ListenerCount: len(usage.Listeners[lbID]),
+			DomainCount:   len(usage.Domains[lbID]),
+		})
+	}
+	return tasks, lbTasks
+}
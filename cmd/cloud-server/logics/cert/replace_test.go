@@ -0,0 +1,62 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"testing"
+
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	proto "hcm/pkg/api/cloud-server/cert"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReplaceTasks(t *testing.T) {
+	usage := &Usage{
+		Listeners: map[string][]actionlb.ReplaceCertListener{
+			"lb-2": {{ListenerID: "lbl-1"}, {ListenerID: "lbl-2"}},
+			"lb-1": {{ListenerID: "lbl-3"}},
+		},
+		Domains: map[string][]actionlb.ReplaceCertDomain{
+			"lb-1": {{ListenerID: "lbl-4", Domain: "a.example.com"}},
+			"lb-3": {{ListenerID: "lbl-5", Domain: "b.example.com"}},
+		},
+	}
+
+	tasks, lbTasks := BuildReplaceTasks(enumor.TCloud, usage, "old", "new")
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, []proto.ReplaceCertTask{
+		{LbID: "lb-1", ActionID: "1", ListenerCount: 1, DomainCount: 1},
+		{LbID: "lb-2", ActionID: "2", ListenerCount: 2, DomainCount: 0},
+		{LbID: "lb-3", ActionID: "3", ListenerCount: 0, DomainCount: 1},
+	}, lbTasks)
+
+	for i, task := range tasks {
+		assert.Equal(t, action.ActIDType(lbTasks[i].ActionID), task.ActionID)
+		opt, ok := task.Params.(*actionlb.ListenerReplaceCertOption)
+		assert.True(t, ok)
+		assert.Equal(t, lbTasks[i].LbID, opt.LbID)
+		assert.Equal(t, "old", opt.OldCertCloudID)
+		assert.Equal(t, "new", opt.NewCertCloudID)
+		assert.Empty(t, task.DependOn)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	databill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// resCost 统计周期内资源的账单费用
+type resCost struct {
+	Amount   decimal.Decimal
+	Currency enumor.CurrencyCode
+}
+
+// billPeriod 账单明细按月分表，统计周期跨月时需要按月份分别查询
+type billPeriod struct {
+	Year     int
+	Month    int
+	BeginDay int
+	EndDay   int
+}
+
+// splitBillPeriods 将截止到当天的最近days天按自然月拆分为账单查询周期
+func splitBillPeriods(now time.Time, days uint) []billPeriod {
+	if days == 0 {
+		return nil
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	begin := end.AddDate(0, 0, -int(days-1))
+
+	periods := make([]billPeriod, 0)
+	for cur := begin; !cur.After(end); {
+		monthEnd := time.Date(cur.Year(), cur.Month()+1, 0, 0, 0, 0, 0, cur.Location())
+		if monthEnd.After(end) {
+			monthEnd = end
+		}
+		periods = append(periods, billPeriod{
+			Year:     cur.Year(),
+			Month:    int(cur.Month()),
+			BeginDay: cur.Day(),
+			EndDay:   monthEnd.Day(),
+		})
+		cur = monthEnd.AddDate(0, 0, 1)
+	}
+
+	return periods
+}
+
+// billResourceID 从账单明细扩展字段中解析资源ID
+func billResourceID(vendor enumor.Vendor, extension []byte) (string, error) {
+	key, exists := bill.BillItemResIDKeys[vendor]
+	if !exists || len(extension) == 0 {
+		return "", nil
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(extension, &fields); err != nil {
+		return "", err
+	}
+
+	id, ok := fields[key].(string)
+	if !ok {
+		return "", nil
+	}
+	return id, nil
+}
+
+// sumCost 按云厂商统计闲置资源候选最近一段时间的账单费用，返回云厂商到云资源ID和费用的映射
+func (i *idleResource) sumCost(kt *kit.Kit, candidates []candidate, now time.Time) (
+	map[enumor.Vendor]map[string]*resCost, error) {
+
+	vendorCloudIDs := make(map[enumor.Vendor][]string)
+	for _, one := range candidates {
+		if _, exists := bill.BillItemResIDKeys[one.Vendor]; !exists {
+			continue
+		}
+		vendorCloudIDs[one.Vendor] = append(vendorCloudIDs[one.Vendor], one.CloudResID)
+	}
+
+	periods := splitBillPeriods(now, i.conf.CostDays)
+	result := make(map[enumor.Vendor]map[string]*resCost, len(vendorCloudIDs))
+	for vendor, cloudIDs := range vendorCloudIDs {
+		costs := make(map[string]*resCost)
+		for _, period := range periods {
+			for _, batch := range slice.Split(slice.Unique(cloudIDs), int(filter.DefaultMaxInLimit)) {
+				if err := i.sumPeriodCost(kt, vendor, period, batch, costs); err != nil {
+					return nil, err
+				}
+			}
+		}
+		result[vendor] = costs
+	}
+
+	return result, nil
+}
+
+// sumPeriodCost 统计指定云资源在一个账单周期内的费用，累加到costs中
+func (i *idleResource) sumPeriodCost(kt *kit.Kit, vendor enumor.Vendor, period billPeriod, cloudIDs []string,
+	costs map[string]*resCost) error {
+
+	resIDField := fmt.Sprintf("extension.%s", bill.BillItemResIDKeys[vendor])
+	lastID := ""
+	for {
+		listReq := &databill.BillItemListReq{
+			ItemCommonOpt: &databill.ItemCommonOpt{Vendor: vendor, Year: period.Year, Month: period.Month},
+			ListReq: &core.ListReq{
+				Filter: tools.ExpressionAnd(
+					tools.RuleGreaterThanEqual("bill_day", period.BeginDay),
+					tools.RuleLessThanEqual("bill_day", period.EndDay),
+					tools.RuleJsonIn(resIDField, cloudIDs),
+					tools.RuleIDGreaterThan(lastID),
+				),
+				Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+				Fields: []string{"id", "currency", "cost", "extension"},
+			},
+		}
+		result, err := i.client.DataService().Global.Bill.ListBillItemRaw(kt, listReq)
+		if err != nil {
+			logs.Errorf("list bill item failed, err: %v, vendor: %s, period: %+v, rid: %s", err, vendor, period,
+				kt.Rid)
+			return err
+		}
+
+		for _, item := range result.Details {
+			addItemCost(kt, vendor, item, costs)
+		}
+
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			return nil
+		}
+		lastID = result.Details[len(result.Details)-1].ID
+	}
+}
+
+func addItemCost(kt *kit.Kit, vendor enumor.Vendor, item *bill.BillItemRaw, costs map[string]*resCost) {
+	if item == nil || item.BaseBillItem == nil {
+		return
+	}
+
+	cloudID, err := billResourceID(vendor, item.Extension)
+	if err != nil {
+		logs.Errorf("parse bill item(%s) resource id failed, err: %v, rid: %s", item.ID, err, kt.Rid)
+		return
+	}
+	if len(cloudID) == 0 {
+		return
+	}
+
+	cost, exists := costs[cloudID]
+	if !exists {
+		cost = &resCost{Amount: decimal.Zero, Currency: item.Currency}
+		costs[cloudID] = cost
+	}
+	cost.Amount = cost.Amount.Add(item.Cost)
+}
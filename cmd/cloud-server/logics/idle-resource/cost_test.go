@@ -0,0 +1,93 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBillPeriods(t *testing.T) {
+	now := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []billPeriod{{Year: 2026, Month: 3, BeginDay: 1, EndDay: 5}}, splitBillPeriods(now, 5))
+
+	assert.Equal(t, []billPeriod{
+		{Year: 2026, Month: 2, BeginDay: 4, EndDay: 28},
+		{Year: 2026, Month: 3, BeginDay: 1, EndDay: 5},
+	}, splitBillPeriods(now, 30))
+
+	newYear := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []billPeriod{
+		{Year: 2025, Month: 11, BeginDay: 30, EndDay: 30},
+		{Year: 2025, Month: 12, BeginDay: 1, EndDay: 31},
+		{Year: 2026, Month: 1, BeginDay: 1, EndDay: 2},
+	}, splitBillPeriods(newYear, 34))
+
+	assert.Empty(t, splitBillPeriods(now, 0))
+}
+
+func TestBillResourceID(t *testing.T) {
+	id, err := billResourceID(enumor.TCloud, []byte(`{"ResourceId":"ins-1","ProductCode":"p_cvm"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ins-1", id)
+
+	id, err = billResourceID(enumor.Aws, []byte(`{"line_item_resource_id":"vol-1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "vol-1", id)
+
+	id, err = billResourceID(enumor.Gcp, []byte(`{"resource_global_name":"disk-1"}`))
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+
+	id, err = billResourceID(enumor.HuaWei, []byte(`{"resource_id":null}`))
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+
+	_, err = billResourceID(enumor.HuaWei, []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestAddItemCost(t *testing.T) {
+	kt := kit.New()
+	costs := make(map[string]*resCost)
+	items := []*bill.BillItemRaw{
+		{BaseBillItem: &bill.BaseBillItem{ID: "1", Currency: enumor.CurrencyCNY, Cost: decimal.NewFromFloat(1.5)},
+			Extension: []byte(`{"ResourceId":"disk-1"}`)},
+		{BaseBillItem: &bill.BaseBillItem{ID: "2", Currency: enumor.CurrencyCNY, Cost: decimal.NewFromFloat(2.25)},
+			Extension: []byte(`{"ResourceId":"disk-1"}`)},
+		{BaseBillItem: &bill.BaseBillItem{ID: "3", Currency: enumor.CurrencyCNY, Cost: decimal.NewFromFloat(3)},
+			Extension: []byte(`{"ResourceId":""}`)},
+	}
+	for _, item := range items {
+		addItemCost(kt, enumor.TCloud, item, costs)
+	}
+
+	assert.Len(t, costs, 1)
+	assert.True(t, decimal.NewFromFloat(3.75).Equal(costs["disk-1"].Amount))
+	assert.Equal(t, enumor.CurrencyCNY, costs["disk-1"].Currency)
+}
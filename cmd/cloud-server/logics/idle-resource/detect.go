@@ -0,0 +1,367 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// stoppedCvmStatus 各云厂商主机的关机状态
+var stoppedCvmStatus = map[enumor.Vendor][]string{
+	enumor.TCloud: {"STOPPED"},
+	enumor.HuaWei: {"SHUTOFF"},
+	enumor.Gcp:    {"TERMINATED", "SUSPENDED"},
+	enumor.Aws:    {"stopped"},
+	enumor.Azure:  {"PowerState/stopped", "PowerState/deallocated"},
+}
+
+// listAll 按ID分页查询满足条件的全部资源
+func listAll[T any](kt *kit.Kit, rules []*filter.AtomRule, fields []string,
+	list func(kt *kit.Kit, req *core.ListReq) ([]T, error), getID func(T) string) ([]T, error) {
+
+	result := make([]T, 0)
+	lastID := ""
+	for {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(append(rules, tools.RuleIDGreaterThan(lastID))...),
+			Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+			Fields: fields,
+		}
+		details, err := list(kt, listReq)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, details...)
+
+		if uint(len(details)) < core.DefaultMaxPageLimit {
+			return result, nil
+		}
+		lastID = getID(details[len(details)-1])
+	}
+}
+
+// detectUnattachedDisk 识别未挂载到主机的云盘
+func (i *idleResource) detectUnattachedDisk(kt *kit.Kit) ([]candidate, error) {
+	disks, err := listAll(kt, []*filter.AtomRule{tools.RuleNotEqual("recycle_status", enumor.RecycleStatus)},
+		[]string{"id", "vendor", "account_id", "name", "bk_biz_id", "cloud_id", "region"},
+		func(kt *kit.Kit, req *core.ListReq) ([]*coredisk.BaseDisk, error) {
+			result, err := i.client.DataService().Global.ListDisk(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one *coredisk.BaseDisk) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list disk failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	candidates := make([]candidate, 0)
+	for _, batch := range slice.Split(disks, int(core.DefaultMaxPageLimit)) {
+		diskIDs := slice.Map(batch, func(one *coredisk.BaseDisk) string { return one.ID })
+		attached, err := i.listRelResIDs(kt, "disk_id", diskIDs, func(kt *kit.Kit, req *core.ListReq) ([]string,
+			error) {
+
+			result, err := i.client.DataService().Global.ListDiskCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(result.Details, func(one *dataproto.DiskCvmRelResult) string { return one.DiskID }), nil
+		})
+		if err != nil {
+			logs.Errorf("list disk cvm rel failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, disk := range batch {
+			if _, exists := attached[disk.ID]; exists {
+				continue
+			}
+			candidates = append(candidates, candidate{
+				Vendor:     enumor.Vendor(disk.Vendor),
+				ResType:    enumor.DiskCloudResType,
+				ResID:      disk.ID,
+				CloudResID: disk.CloudID,
+				ResName:    disk.Name,
+				AccountID:  disk.AccountID,
+				BkBizID:    disk.BkBizID,
+				Region:     disk.Region,
+				Reason:     enumor.IdleUnattachedDisk,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// detectUnboundEip 识别未绑定任何实例的弹性IP
+func (i *idleResource) detectUnboundEip(kt *kit.Kit) ([]candidate, error) {
+	eips, err := listAll(kt, []*filter.AtomRule{tools.RuleNotEqual("recycle_status", enumor.RecycleStatus)},
+		[]string{"id", "vendor", "account_id", "name", "bk_biz_id", "cloud_id", "region", "instance_id"},
+		func(kt *kit.Kit, req *core.ListReq) ([]*dataeip.EipResult, error) {
+			result, err := i.client.DataService().Global.ListEip(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one *dataeip.EipResult) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list eip failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	candidates := make([]candidate, 0)
+	for _, batch := range slice.Split(eips, int(core.DefaultMaxPageLimit)) {
+		eipIDs := slice.Map(batch, func(one *dataeip.EipResult) string { return one.ID })
+		bound, err := i.listRelResIDs(kt, "eip_id", eipIDs, func(kt *kit.Kit, req *core.ListReq) ([]string, error) {
+			result, err := i.client.DataService().Global.ListEipCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(result.Details, func(one *dataproto.EipCvmRelResult) string { return one.EipID }), nil
+		})
+		if err != nil {
+			logs.Errorf("list eip cvm rel failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, eip := range batch {
+			// 弹性IP可能绑定在主机以外的实例上，如网卡、负载均衡，此时只有实例ID没有主机关联关系
+			if _, exists := bound[eip.ID]; exists || len(converter.PtrToVal(eip.InstanceID)) != 0 {
+				continue
+			}
+			candidates = append(candidates, candidate{
+				Vendor:     enumor.Vendor(eip.Vendor),
+				ResType:    enumor.EipCloudResType,
+				ResID:      eip.ID,
+				CloudResID: eip.CloudID,
+				ResName:    converter.PtrToVal(eip.Name),
+				AccountID:  eip.AccountID,
+				BkBizID:    eip.BkBizID,
+				Region:     eip.Region,
+				Reason:     enumor.IdleUnboundEip,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// listRelResIDs 查询存在关联关系的资源ID
+func (i *idleResource) listRelResIDs(kt *kit.Kit, field string, resIDs []string,
+	list func(kt *kit.Kit, req *core.ListReq) ([]string, error)) (map[string]struct{}, error) {
+
+	result := make(map[string]struct{})
+	for start := uint32(0); ; start += uint32(core.DefaultMaxPageLimit) {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression(field, resIDs),
+			Page:   &core.BasePage{Start: start, Limit: core.DefaultMaxPageLimit},
+			Fields: []string{field},
+		}
+		ids, err := list(kt, listReq)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			result[id] = struct{}{}
+		}
+
+		if uint(len(ids)) < core.DefaultMaxPageLimit {
+			return result, nil
+		}
+	}
+}
+
+// detectStoppedCvm 识别已关机的主机，是否仍在计费需要结合账单判断
+func (i *idleResource) detectStoppedCvm(kt *kit.Kit) ([]candidate, error) {
+	statuses := make([]string, 0)
+	for _, one := range stoppedCvmStatus {
+		statuses = append(statuses, one...)
+	}
+
+	cvms, err := listAll(kt, []*filter.AtomRule{
+		tools.RuleNotEqual("recycle_status", enumor.RecycleStatus),
+		tools.RuleIn("status", statuses),
+	}, []string{"id", "vendor", "account_id", "name", "bk_biz_id", "cloud_id", "region", "status"},
+		func(kt *kit.Kit, req *core.ListReq) ([]corecvm.BaseCvm, error) {
+			result, err := i.client.DataService().Global.Cvm.ListCvm(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one corecvm.BaseCvm) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list stopped cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	candidates := make([]candidate, 0)
+	for _, cvm := range cvms {
+		// 查询条件包含了所有云厂商的关机状态，需要按主机所属云厂商再次确认
+		if !slice.IsItemInSlice(stoppedCvmStatus[cvm.Vendor], cvm.Status) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			Vendor:     cvm.Vendor,
+			ResType:    enumor.CvmCloudResType,
+			ResID:      cvm.ID,
+			CloudResID: cvm.CloudID,
+			ResName:    cvm.Name,
+			AccountID:  cvm.AccountID,
+			BkBizID:    cvm.BkBizID,
+			Region:     cvm.Region,
+			Reason:     enumor.IdleStoppedCvm,
+		})
+	}
+
+	return candidates, nil
+}
+
+// detectIdleLoadBalancer 识别没有监听器，或者监听器都没有绑定后端服务的负载均衡
+func (i *idleResource) detectIdleLoadBalancer(kt *kit.Kit) ([]candidate, error) {
+	lbs, err := listAll(kt, []*filter.AtomRule{tools.RuleNotEqual("recycle_status", enumor.RecycleStatus)},
+		[]string{"id", "vendor", "account_id", "name", "bk_biz_id", "cloud_id", "region"},
+		func(kt *kit.Kit, req *core.ListReq) ([]corelb.BaseLoadBalancer, error) {
+			result, err := i.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one corelb.BaseLoadBalancer) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list load balancer failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	candidates := make([]candidate, 0)
+	for _, batch := range slice.Split(lbs, int(core.DefaultMaxPageLimit)) {
+		lbIDs := slice.Map(batch, func(one corelb.BaseLoadBalancer) string { return one.ID })
+		withListener, withTarget, err := i.listLbBinding(kt, lbIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lb := range batch {
+			var reason enumor.IdleResourceReason
+			switch {
+			case !withListener[lb.ID]:
+				reason = enumor.IdleLbNoListener
+			case !withTarget[lb.ID]:
+				reason = enumor.IdleLbNoTarget
+			default:
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				Vendor:     lb.Vendor,
+				ResType:    enumor.LoadBalancerCloudResType,
+				ResID:      lb.ID,
+				CloudResID: lb.CloudID,
+				ResName:    lb.Name,
+				AccountID:  lb.AccountID,
+				BkBizID:    lb.BkBizID,
+				Region:     lb.Region,
+				Reason:     reason,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// listLbBinding 查询负载均衡是否存在监听器，以及监听器绑定的目标组中是否存在后端服务
+func (i *idleResource) listLbBinding(kt *kit.Kit, lbIDs []string) (withListener map[string]bool,
+	withTarget map[string]bool, err error) {
+
+	lbRule := []*filter.AtomRule{tools.RuleIn("lb_id", lbIDs)}
+	listeners, err := listAll(kt, lbRule, []string{"id", "lb_id"},
+		func(kt *kit.Kit, req *core.ListReq) ([]corelb.BaseListener, error) {
+			result, err := i.client.DataService().Global.LoadBalancer.ListListener(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one corelb.BaseListener) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, nil, err
+	}
+	withListener = make(map[string]bool, len(listeners))
+	for _, one := range listeners {
+		withListener[one.LbID] = true
+	}
+
+	rels, err := listAll(kt, lbRule, []string{"id", "lb_id", "target_group_id"},
+		func(kt *kit.Kit, req *core.ListReq) ([]corelb.BaseTargetListenerRuleRel, error) {
+			result, err := i.client.DataService().Global.LoadBalancer.ListTargetGroupListenerRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return result.Details, nil
+		}, func(one corelb.BaseTargetListenerRuleRel) string { return one.ID })
+	if err != nil {
+		logs.Errorf("list target group listener rel failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, nil, err
+	}
+
+	tgIDs := slice.Unique(slice.Map(rels, func(one corelb.BaseTargetListenerRuleRel) string {
+		return one.TargetGroupID
+	}))
+	tgWithTarget := make(map[string]struct{})
+	for _, batch := range slice.Split(tgIDs, int(core.DefaultMaxPageLimit)) {
+		ids, err := i.listRelResIDs(kt, "target_group_id", batch, func(kt *kit.Kit, req *core.ListReq) ([]string,
+			error) {
+
+			result, err := i.client.DataService().Global.LoadBalancer.ListTarget(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return slice.Map(result.Details, func(one corelb.BaseTarget) string { return one.TargetGroupID }), nil
+		})
+		if err != nil {
+			logs.Errorf("list target failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, nil, err
+		}
+		for id := range ids {
+			tgWithTarget[id] = struct{}{}
+		}
+	}
+
+	withTarget = make(map[string]bool)
+	for _, rel := range rels {
+		if _, exists := tgWithTarget[rel.TargetGroupID]; exists {
+			withTarget[rel.LbID] = true
+		}
+	}
+
+	return withListener, withTarget, nil
+}
@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package idleresource 结合资源状态与账单费用识别闲置资源
+package idleresource
+
+import (
+	"time"
+
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// Interface define idle resource analyze interface.
+type Interface interface {
+	// Analyze detect idle resources of current tenant, join them with the bill cost of the last days and save the
+	// result, resources that are no longer idle will be removed.
+	Analyze(kt *kit.Kit) error
+}
+
+type idleResource struct {
+	client *client.ClientSet
+	conf   cc.IdleResource
+}
+
+// NewIdleResource new idle resource analyzer.
+func NewIdleResource(client *client.ClientSet, conf cc.IdleResource) Interface {
+	return &idleResource{
+		client: client,
+		conf:   conf,
+	}
+}
+
+// candidate 根据资源状态和关联关系识别出的闲置资源
+type candidate struct {
+	Vendor     enumor.Vendor
+	ResType    enumor.CloudResourceType
+	ResID      string
+	CloudResID string
+	ResName    string
+	AccountID  string
+	BkBizID    int64
+	Region     string
+	Reason     enumor.IdleResourceReason
+}
+
+// Analyze detect idle resources of current tenant, join them with the bill cost of the last days and save the
+// result, resources that are no longer idle will be removed.
+func (i *idleResource) Analyze(kt *kit.Kit) error {
+	detectors := []func(kt *kit.Kit) ([]candidate, error){
+		i.detectUnattachedDisk,
+		i.detectUnboundEip,
+		i.detectStoppedCvm,
+		i.detectIdleLoadBalancer,
+	}
+
+	// 任一类资源识别失败时不更新分析结果，避免误删仍然闲置的资源记录
+	candidates := make([]candidate, 0)
+	for _, detect := range detectors {
+		result, err := detect(kt)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, result...)
+	}
+
+	costs, err := i.sumCost(kt, candidates, time.Now())
+	if err != nil {
+		return err
+	}
+
+	items := make([]dataproto.IdleResourceCreateReq, 0, len(candidates))
+	for _, one := range candidates {
+		cost := costs[one.Vendor][one.CloudResID]
+
+		// 已关机的主机只有仍在产生费用时才视为闲置，按量计费的主机关机后不收费
+		if one.Reason == enumor.IdleStoppedCvm && (cost == nil || !cost.Amount.IsPositive()) {
+			continue
+		}
+
+		item := dataproto.IdleResourceCreateReq{
+			Vendor:     one.Vendor,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			ResName:    one.ResName,
+			AccountID:  one.AccountID,
+			BkBizID:    one.BkBizID,
+			Region:     one.Region,
+			Reason:     one.Reason,
+		}
+		if cost != nil {
+			item.Cost = cost.Amount
+			item.Currency = cost.Currency
+		}
+		items = append(items, item)
+	}
+
+	if err = i.save(kt, items); err != nil {
+		return err
+	}
+
+	logs.Infof("analyze idle resource success, count: %d, rid: %s", len(items), kt.Rid)
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coreidle "hcm/pkg/api/core/cloud/idle-resource"
+	dsapi "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// ListAllIdleResource list all idle resources that match the filter.
+func ListAllIdleResource(kt *kit.Kit, cli *dataservice.Client, expr *filter.Expression) (
+	[]coreidle.IdleResource, error) {
+
+	result := make([]coreidle.IdleResource, 0)
+	lastID := ""
+	for {
+		pageExpr, err := tools.And(expr, tools.RuleIDGreaterThan(lastID))
+		if err != nil {
+			return nil, err
+		}
+		listReq := &core.ListReq{
+			Filter: pageExpr,
+			Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		}
+		resp, err := cli.Global.ListIdleResource(kt, listReq)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+
+		if uint(len(resp.Details)) < core.DefaultMaxPageLimit {
+			return result, nil
+		}
+		lastID = resp.Details[len(resp.Details)-1].ID
+	}
+}
+
+func idleResKey(resType enumor.CloudResourceType, resID string) string {
+	return fmt.Sprintf("%s/%s", resType, resID)
+}
+
+// save 保存本次分析结果，新增识别出的闲置资源，更新已有记录的原因和费用，删除不再闲置的资源记录
+func (i *idleResource) save(kt *kit.Kit, items []dataproto.IdleResourceCreateReq) error {
+	existing, err := ListAllIdleResource(kt, i.client.DataService(), tools.AllExpression())
+	if err != nil {
+		logs.Errorf("list idle resource failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	existingMap := make(map[string]coreidle.IdleResource, len(existing))
+	for _, one := range existing {
+		existingMap[idleResKey(one.ResType, one.ResID)] = one
+	}
+
+	creates := make([]dataproto.IdleResourceCreateReq, 0)
+	updates := make([]dataproto.IdleResourceUpdateReq, 0)
+	for _, item := range items {
+		key := idleResKey(item.ResType, item.ResID)
+		old, exists := existingMap[key]
+		if !exists {
+			creates = append(creates, item)
+			continue
+		}
+		delete(existingMap, key)
+
+		if old.ResName == item.ResName && old.BkBizID == item.BkBizID && old.Reason == item.Reason &&
+			old.Cost.Equal(item.Cost) && old.Currency == item.Currency {
+			continue
+		}
+		cost := item.Cost
+		updates = append(updates, dataproto.IdleResourceUpdateReq{
+			ID:       old.ID,
+			ResName:  item.ResName,
+			BkBizID:  item.BkBizID,
+			Reason:   item.Reason,
+			Cost:     &cost,
+			Currency: item.Currency,
+		})
+	}
+
+	for _, batch := range slice.Split(creates, constant.BatchOperationMaxLimit) {
+		createReq := &dataproto.IdleResourceBatchCreateReq{Items: batch}
+		if _, err = i.client.DataService().Global.BatchCreateIdleResource(kt, createReq); err != nil {
+			logs.Errorf("create idle resource failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	for _, batch := range slice.Split(updates, constant.BatchOperationMaxLimit) {
+		updateReq := &dataproto.IdleResourceBatchUpdateReq{Items: batch}
+		if err = i.client.DataService().Global.BatchUpdateIdleResource(kt, updateReq); err != nil {
+			logs.Errorf("update idle resource failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	staleIDs := make([]string, 0, len(existingMap))
+	for _, one := range existingMap {
+		staleIDs = append(staleIDs, one.ID)
+	}
+	for _, batch := range slice.Split(staleIDs, int(core.DefaultMaxPageLimit)) {
+		deleteReq := &dsapi.BatchDeleteReq{Filter: tools.ContainersExpression("id", batch)}
+		if err = i.client.DataService().Global.BatchDeleteIdleResource(kt, deleteReq); err != nil {
+			logs.Errorf("delete stale idle resource failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -216,10 +216,17 @@ func (c *Layer4ListenerBindRSExecutor) buildFlow(kt *kit.Kit, lb corelb.LoadBala
 		logs.Errorf("check resource flow relation failed, lbID: %s, err: %v, rid: %s", lb.ID, err, kt.Rid)
 		return "", err
 	}
+	// 变更前保存负载均衡配置快照，用于回滚
+	snapshotID, err := SnapshotLoadBalancer(kt, c.dataServiceCli, lb.ID, enumor.LbSnapshotBatchBindRs)
+	if err != nil {
+		logs.Errorf("snapshot load balancer failed, lbID: %s, err: %v, rid: %s", lb.ID, err, kt.Rid)
+		return "", err
+	}
 	flowID, err := c.createFlowTask(kt, lb.ID, flowTasks)
 	if err != nil {
 		return "", err
 	}
+	BindSnapshotFlow(kt, c.dataServiceCli, snapshotID, flowID)
 	err = lockResFlowStatus(kt, c.dataServiceCli, c.taskCli, lb.ID,
 		enumor.LoadBalancerCloudResType, flowID, enumor.AddRSTaskType)
 	if err != nil {
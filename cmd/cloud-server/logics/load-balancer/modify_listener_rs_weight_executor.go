@@ -358,11 +358,19 @@ func (c *BatchListenerModifyRsWeightExecutor) buildFlow(kt *kit.Kit, lbID string
 		return "", err
 	}
 
+	// 变更前保存负载均衡配置快照，用于回滚
+	snapshotID, err := SnapshotLoadBalancer(kt, c.dataServiceCli, lbID, enumor.LbSnapshotBatchModifyRsWeight)
+	if err != nil {
+		logs.Errorf("snapshot load balancer failed, lbID: %s, err: %v, rid: %s", lbID, err, kt.Rid)
+		return "", err
+	}
+
 	flowID, err := c.createFlowTask(kt, lbID, flowTasks)
 	if err != nil {
 		logs.Errorf("create flow task failed, err: %v, lbID: %s, rid: %s", err, lbID, kt.Rid)
 		return "", err
 	}
+	BindSnapshotFlow(kt, c.dataServiceCli, snapshotID, flowID)
 
 	err = lockResFlowStatus(kt, c.dataServiceCli, c.taskCli, lbID,
 		enumor.LoadBalancerCloudResType, flowID, enumor.ListenerModifyRsWeightTaskType)
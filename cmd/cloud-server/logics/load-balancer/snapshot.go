@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// SnapshotLoadBalancer 加载负载均衡当前的监听器、规则、目标组及RS权重并保存为快照，返回快照ID
+func SnapshotLoadBalancer(kt *kit.Kit, cli *dataservice.Client, lbID string, source enumor.LbSnapshotSource) (
+	string, error) {
+
+	state, err := LoadTCloudSpecState(kt, cli, lbID)
+	if err != nil {
+		logs.Errorf("load load balancer state for snapshot failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+		return "", err
+	}
+	return CreateTCloudSnapshot(kt, cli, state, source)
+}
+
+// CreateTCloudSnapshot 将已加载的负载均衡状态保存为快照，返回快照ID
+func CreateTCloudSnapshot(kt *kit.Kit, cli *dataservice.Client, state *TCloudSpecState,
+	source enumor.LbSnapshotSource) (string, error) {
+
+	lb := state.LoadBalancer
+	req := &dataproto.LbSnapshotCreateReq{
+		Vendor:    lb.Vendor,
+		LbID:      lb.ID,
+		AccountID: lb.AccountID,
+		BkBizID:   lb.BkBizID,
+		Source:    source,
+		Spec:      ExportTCloudSpec(state),
+	}
+	result, err := cli.Global.LoadBalancer.CreateLoadBalancerSnapshot(kt, req)
+	if err != nil {
+		logs.Errorf("create load balancer snapshot failed, err: %v, lb: %s, source: %s, rid: %s", err, lb.ID,
+			source, kt.Rid)
+		return "", err
+	}
+	return result.ID, nil
+}
+
+// BindSnapshotFlow 记录快照之后执行变更的异步任务，异步任务已创建，失败时仅记录日志
+func BindSnapshotFlow(kt *kit.Kit, cli *dataservice.Client, snapshotID, flowID string) {
+	if len(snapshotID) == 0 || len(flowID) == 0 {
+		return
+	}
+	req := &dataproto.LbSnapshotUpdateReq{FlowID: flowID}
+	if err := cli.Global.LoadBalancer.UpdateLoadBalancerSnapshot(kt, snapshotID, req); err != nil {
+		logs.Errorf("bind load balancer snapshot flow failed, err: %v, snapshot: %s, flow: %s, rid: %s", err,
+			snapshotID, flowID, kt.Rid)
+	}
+}
@@ -0,0 +1,689 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// TCloudSpecState 负载均衡当前配置状态，监听器按 协议:端口 索引
+type TCloudSpecState struct {
+	LoadBalancer corelb.BaseLoadBalancer
+	Listeners    map[string]*TCloudListenerState
+	// TGRuleCount 目标组绑定的监听器/规则数量，目标组被共用时不能按单个监听器/规则调整RS
+	TGRuleCount map[string]int
+}
+
+// TCloudListenerState 监听器当前状态，四层监听器的调度、会话保持、健康检查保存在四层规则上
+type TCloudListenerState struct {
+	Listener      corelb.TCloudListener
+	Layer4Rule    *corelb.TCloudLbUrlRule
+	TargetGroupID string
+	Targets       []corelb.BaseTarget
+	// Rules 七层规则，按 域名+URL 索引
+	Rules map[string]*TCloudRuleState
+}
+
+// TCloudRuleState 七层规则当前状态
+type TCloudRuleState struct {
+	Rule          corelb.TCloudLbUrlRule
+	TargetGroupID string
+	Targets       []corelb.BaseTarget
+}
+
+// LoadTCloudSpecState 从DB中加载负载均衡的监听器、规则、目标组及RS
+func LoadTCloudSpecState(kt *kit.Kit, cli *dataservice.Client, lbID string) (*TCloudSpecState, error) {
+	lbResp, err := cli.Global.LoadBalancer.ListLoadBalancer(kt, &core.ListReq{
+		Filter: tools.EqualExpression("id", lbID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list load balancer failed, err: %v, id: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, err
+	}
+	if len(lbResp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "load balancer(%s) not found", lbID)
+	}
+	lb := lbResp.Details[0]
+	if lb.Vendor != enumor.TCloud {
+		return nil, errf.Newf(errf.InvalidParameter, "vendor(%s) not supported for load balancer spec", lb.Vendor)
+	}
+
+	state := &TCloudSpecState{
+		LoadBalancer: lb,
+		Listeners:    make(map[string]*TCloudListenerState),
+		TGRuleCount:  make(map[string]int),
+	}
+	lblByID, err := state.loadListeners(kt, cli)
+	if err != nil {
+		return nil, err
+	}
+	l4ByRuleID, l7ByRuleID, err := state.loadRules(kt, cli, lblByID)
+	if err != nil {
+		return nil, err
+	}
+	if err = state.loadTargets(kt, cli, l4ByRuleID, l7ByRuleID); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *TCloudSpecState) loadListeners(kt *kit.Kit, cli *dataservice.Client) (
+	map[string]*TCloudListenerState, error) {
+
+	lblByID := make(map[string]*TCloudListenerState)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("lb_id", s.LoadBalancer.ID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.TCloud.LoadBalancer.ListListener(kt, listReq)
+		if err != nil {
+			logs.Errorf("list listener failed, err: %v, lb: %s, rid: %s", err, s.LoadBalancer.ID, kt.Rid)
+			return nil, err
+		}
+		for _, lbl := range resp.Details {
+			one := &TCloudListenerState{Listener: lbl, Rules: make(map[string]*TCloudRuleState)}
+			s.Listeners[listenerKey(lbl.Protocol, lbl.Port)] = one
+			lblByID[lbl.ID] = one
+		}
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return lblByID, nil
+}
+
+func (s *TCloudSpecState) loadRules(kt *kit.Kit, cli *dataservice.Client,
+	lblByID map[string]*TCloudListenerState) (map[string]*TCloudListenerState, map[string]*TCloudRuleState, error) {
+
+	l4ByRuleID := make(map[string]*TCloudListenerState)
+	l7ByRuleID := make(map[string]*TCloudRuleState)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("lb_id", s.LoadBalancer.ID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.TCloud.LoadBalancer.ListUrlRule(kt, listReq)
+		if err != nil {
+			logs.Errorf("list url rule failed, err: %v, lb: %s, rid: %s", err, s.LoadBalancer.ID, kt.Rid)
+			return nil, nil, err
+		}
+		for i := range resp.Details {
+			rule := resp.Details[i]
+			lbl, ok := lblByID[rule.LblID]
+			if !ok {
+				continue
+			}
+			if rule.RuleType == enumor.Layer4RuleType {
+				lbl.Layer4Rule = &rule
+				l4ByRuleID[rule.ID] = lbl
+				continue
+			}
+			one := &TCloudRuleState{Rule: rule}
+			lbl.Rules[rule.Domain+rule.URL] = one
+			l7ByRuleID[rule.ID] = one
+		}
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return l4ByRuleID, l7ByRuleID, nil
+}
+
+func (s *TCloudSpecState) loadTargets(kt *kit.Kit, cli *dataservice.Client,
+	l4ByRuleID map[string]*TCloudListenerState, l7ByRuleID map[string]*TCloudRuleState) error {
+
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("lb_id", s.LoadBalancer.ID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := cli.Global.LoadBalancer.ListTargetGroupListenerRel(kt, relReq)
+		if err != nil {
+			logs.Errorf("list target group rel failed, err: %v, lb: %s, rid: %s", err, s.LoadBalancer.ID, kt.Rid)
+			return err
+		}
+		for _, rel := range resp.Details {
+			s.TGRuleCount[rel.TargetGroupID]++
+			if lbl, ok := l4ByRuleID[rel.ListenerRuleID]; ok {
+				lbl.TargetGroupID = rel.TargetGroupID
+			}
+			if rule, ok := l7ByRuleID[rel.ListenerRuleID]; ok {
+				rule.TargetGroupID = rel.TargetGroupID
+			}
+		}
+		if uint(len(resp.Details)) < relReq.Page.Limit {
+			break
+		}
+		relReq.Page.Start += uint32(relReq.Page.Limit)
+	}
+	if len(s.TGRuleCount) == 0 {
+		return nil
+	}
+
+	targetMap := make(map[string][]corelb.BaseTarget)
+	for _, tgIDs := range slice.Split(cvt.MapKeyToSlice(s.TGRuleCount), int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("target_group_id", tgIDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			resp, err := cli.Global.LoadBalancer.ListTarget(kt, listReq)
+			if err != nil {
+				logs.Errorf("list target failed, err: %v, tg ids: %v, rid: %s", err, tgIDs, kt.Rid)
+				return err
+			}
+			for _, target := range resp.Details {
+				targetMap[target.TargetGroupID] = append(targetMap[target.TargetGroupID], target)
+			}
+			if uint(len(resp.Details)) < listReq.Page.Limit {
+				break
+			}
+			listReq.Page.Start += uint32(listReq.Page.Limit)
+		}
+	}
+	for _, lbl := range s.Listeners {
+		lbl.Targets = targetMap[lbl.TargetGroupID]
+		for _, rule := range lbl.Rules {
+			rule.Targets = targetMap[rule.TargetGroupID]
+		}
+	}
+	return nil
+}
+
+func listenerKey(protocol enumor.ProtocolType, port int64) string {
+	return corelb.TCloudListenerSpec{Protocol: protocol, Port: port}.Key()
+}
+
+// ExportTCloudSpec 将负载均衡当前状态导出为声明式配置，导出的配置开启 prune，描述负载均衡完整状态
+func ExportTCloudSpec(state *TCloudSpecState) *corelb.TCloudLoadBalancerSpec {
+	spec := &corelb.TCloudLoadBalancerSpec{
+		CloudID:   state.LoadBalancer.CloudID,
+		Prune:     true,
+		Listeners: make([]corelb.TCloudListenerSpec, 0, len(state.Listeners)),
+	}
+	for _, key := range sortedKeys(state.Listeners) {
+		cur := state.Listeners[key]
+		lbl := corelb.TCloudListenerSpec{
+			Name:     cur.Listener.Name,
+			Protocol: cur.Listener.Protocol,
+			Port:     cur.Listener.Port,
+		}
+		if cur.Listener.Extension != nil {
+			lbl.EndPort = cvt.PtrToVal(cur.Listener.Extension.EndPort)
+			lbl.Certificate = cur.Listener.Extension.Certificate
+		}
+		if cur.Listener.Protocol.IsLayer7Protocol() {
+			lbl.SniSwitch = cvt.ValToPtr(cur.Listener.SniSwitch)
+			for _, ruleKey := range sortedKeys(cur.Rules) {
+				rule := cur.Rules[ruleKey].Rule
+				ruleSpec := corelb.TCloudRuleSpec{
+					Domain:        rule.Domain,
+					Url:           rule.URL,
+					Scheduler:     rule.Scheduler,
+					SessionExpire: cvt.ValToPtr(rule.SessionExpire),
+					HealthCheck:   rule.HealthCheck,
+					Targets:       exportTargets(cur.Rules[ruleKey].Targets),
+				}
+				if cur.Listener.SniSwitch == enumor.SniTypeOpen {
+					ruleSpec.Certificate = rule.Certificate
+				}
+				lbl.Rules = append(lbl.Rules, ruleSpec)
+			}
+		} else {
+			if cur.Layer4Rule != nil {
+				lbl.Scheduler = cur.Layer4Rule.Scheduler
+				lbl.SessionType = cur.Layer4Rule.SessionType
+				lbl.SessionExpire = cvt.ValToPtr(cur.Layer4Rule.SessionExpire)
+				lbl.HealthCheck = cur.Layer4Rule.HealthCheck
+			}
+			lbl.Targets = exportTargets(cur.Targets)
+		}
+		spec.Listeners = append(spec.Listeners, lbl)
+	}
+	return spec
+}
+
+func exportTargets(targets []corelb.BaseTarget) []corelb.TCloudTargetSpec {
+	result := slice.Map(targets, targetToSpec)
+	sort.Slice(result, func(i, j int) bool { return result[i].Key() < result[j].Key() })
+	return result
+}
+
+func targetToSpec(target corelb.BaseTarget) corelb.TCloudTargetSpec {
+	return corelb.TCloudTargetSpec{
+		InstType:    target.InstType,
+		CloudInstID: target.CloudInstID,
+		IP:          target.IP,
+		Port:        target.Port,
+		Weight:      target.Weight,
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := cvt.MapKeyToSlice(m)
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateTCloudSpec 校验声明式配置
+func ValidateTCloudSpec(spec *corelb.TCloudLoadBalancerSpec) error {
+	lblKeys := make(map[string]struct{}, len(spec.Listeners))
+	for _, lbl := range spec.Listeners {
+		if err := lbl.Protocol.Validate(); err != nil {
+			return err
+		}
+		if len(lbl.Name) == 0 || lbl.Port <= 0 {
+			return fmt.Errorf("listener(%s) name and port are required", lbl.Key())
+		}
+		if _, ok := lblKeys[lbl.Key()]; ok {
+			return fmt.Errorf("listener(%s) is duplicated", lbl.Key())
+		}
+		lblKeys[lbl.Key()] = struct{}{}
+		if lbl.SniSwitch != nil {
+			if err := lbl.SniSwitch.Validate(); err != nil {
+				return fmt.Errorf("listener(%s) %v", lbl.Key(), err)
+			}
+		}
+
+		if !lbl.Protocol.IsLayer7Protocol() {
+			if len(lbl.Rules) > 0 {
+				return fmt.Errorf("layer4 listener(%s) can not declare rules", lbl.Key())
+			}
+			if err := validateTargetSpecs(lbl.Key(), lbl.Targets); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(lbl.Targets) > 0 {
+			return fmt.Errorf("layer7 listener(%s) should declare targets in rules", lbl.Key())
+		}
+		ruleKeys := make(map[string]struct{}, len(lbl.Rules))
+		for _, rule := range lbl.Rules {
+			if len(rule.Domain) == 0 || len(rule.Url) == 0 {
+				return fmt.Errorf("rule of listener(%s) domain and url are required", lbl.Key())
+			}
+			if _, ok := ruleKeys[rule.Key()]; ok {
+				return fmt.Errorf("rule(%s) of listener(%s) is duplicated", rule.Key(), lbl.Key())
+			}
+			ruleKeys[rule.Key()] = struct{}{}
+			if err := validateTargetSpecs(lbl.Key()+"/"+rule.Key(), rule.Targets); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateTargetSpecs(owner string, targets []corelb.TCloudTargetSpec) error {
+	keys := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		switch target.InstType {
+		case enumor.CvmInstType:
+			if len(target.CloudInstID) == 0 {
+				return fmt.Errorf("target of %s cloud_inst_id is required for CVM", owner)
+			}
+		case enumor.EniInstType:
+			if len(target.IP) == 0 {
+				return fmt.Errorf("target of %s ip is required for ENI", owner)
+			}
+		default:
+			return fmt.Errorf("target of %s inst_type(%s) is not supported", owner, target.InstType)
+		}
+		if target.Port <= 0 {
+			return fmt.Errorf("target(%s) of %s port is required", target.Key(), owner)
+		}
+		if target.Weight == nil || *target.Weight < 0 || *target.Weight > 100 {
+			return fmt.Errorf("target(%s) of %s weight should be between 0 and 100", target.Key(), owner)
+		}
+		if _, ok := keys[target.Key()]; ok {
+			return fmt.Errorf("target(%s) of %s is duplicated", target.Key(), owner)
+		}
+		keys[target.Key()] = struct{}{}
+	}
+	return nil
+}
+
+// DiffTCloudSpec 对比声明式配置与当前状态，生成按执行顺序排列的变更列表。
+// 已声明监听器下的规则、RS以配置为准，未声明的监听器仅在开启 prune 时删除
+func DiffTCloudSpec(state *TCloudSpecState, spec *corelb.TCloudLoadBalancerSpec) (
+	*cslb.TCloudSpecPlanResult, error) {
+
+	if err := ValidateTCloudSpec(spec); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if len(spec.CloudID) > 0 && spec.CloudID != state.LoadBalancer.CloudID {
+		return nil, errf.Newf(errf.InvalidParameter, "spec cloud_id(%s) not match load balancer(%s)",
+			spec.CloudID, state.LoadBalancer.CloudID)
+	}
+
+	d := &specDiffer{
+		state: state,
+		plan: &cslb.TCloudSpecPlanResult{
+			LbID:     state.LoadBalancer.ID,
+			Changes:  make([]corelb.TCloudSpecChange, 0),
+			Warnings: make([]string, 0),
+		},
+	}
+	declared := make(map[string]struct{}, len(spec.Listeners))
+	for _, lbl := range spec.Listeners {
+		declared[lbl.Key()] = struct{}{}
+		cur, ok := state.Listeners[lbl.Key()]
+		if !ok {
+			d.createListener(lbl)
+			continue
+		}
+		if err := d.diffListener(cur, lbl); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Prune {
+		for _, key := range sortedKeys(state.Listeners) {
+			if _, ok := declared[key]; ok {
+				continue
+			}
+			cur := state.Listeners[key].Listener
+			d.add(corelb.TCloudSpecChange{Action: corelb.SpecChangeDelete, ResType: corelb.SpecResListener,
+				Protocol: cur.Protocol, Port: cur.Port, ListenerID: cur.ID})
+		}
+	}
+
+	sort.SliceStable(d.plan.Changes, func(i, j int) bool {
+		return d.plan.Changes[i].Phase() < d.plan.Changes[j].Phase()
+	})
+	return d.plan, nil
+}
+
+type specDiffer struct {
+	state *TCloudSpecState
+	plan  *cslb.TCloudSpecPlanResult
+}
+
+func (d *specDiffer) add(change corelb.TCloudSpecChange) {
+	d.plan.Changes = append(d.plan.Changes, change)
+}
+
+func (d *specDiffer) warn(format string, args ...any) {
+	d.plan.Warnings = append(d.plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (d *specDiffer) createListener(lbl corelb.TCloudListenerSpec) {
+	d.add(corelb.TCloudSpecChange{Action: corelb.SpecChangeCreate, ResType: corelb.SpecResListener,
+		Protocol: lbl.Protocol, Port: lbl.Port, Listener: stripListenerSpec(lbl)})
+
+	base := corelb.TCloudSpecChange{Protocol: lbl.Protocol, Port: lbl.Port}
+	for _, target := range lbl.Targets {
+		d.addTarget(corelb.SpecChangeCreate, base, target)
+	}
+	for _, rule := range lbl.Rules {
+		d.createRule(base, rule)
+	}
+}
+
+func (d *specDiffer) createRule(base corelb.TCloudSpecChange, rule corelb.TCloudRuleSpec) {
+	base.Domain, base.Url = rule.Domain, rule.Url
+	change := base
+	change.Action, change.ResType, change.Rule = corelb.SpecChangeCreate, corelb.SpecResUrlRule, stripRuleSpec(rule)
+	d.add(change)
+	for _, target := range rule.Targets {
+		d.addTarget(corelb.SpecChangeCreate, base, target)
+	}
+}
+
+func (d *specDiffer) addTarget(action corelb.SpecChangeAction, base corelb.TCloudSpecChange,
+	target corelb.TCloudTargetSpec) {
+
+	base.Action, base.ResType, base.Target = action, corelb.SpecResTarget, cvt.ValToPtr(target)
+	d.add(base)
+}
+
+func (d *specDiffer) diffListener(cur *TCloudListenerState, want corelb.TCloudListenerSpec) error {
+	lbl := cur.Listener
+	fields := make([]string, 0)
+	if want.Name != lbl.Name {
+		fields = append(fields, "name")
+	}
+	if want.SniSwitch != nil && *want.SniSwitch != lbl.SniSwitch {
+		fields = append(fields, "sni_switch")
+	}
+	var curCert *corelb.TCloudCertificateInfo
+	var curEndPort int64
+	if lbl.Extension != nil {
+		curCert, curEndPort = lbl.Extension.Certificate, cvt.PtrToVal(lbl.Extension.EndPort)
+	}
+	if want.Certificate != nil && !certEqual(want.Certificate, curCert) {
+		fields = append(fields, "certificate")
+	}
+	if want.EndPort > 0 && want.EndPort != curEndPort {
+		d.warn("listener(%s) end_port can not be modified, recreate the listener to change it", want.Key())
+	}
+
+	if lbl.Protocol.IsLayer7Protocol() {
+		if want.HealthCheck != nil || len(want.Scheduler) > 0 || want.SessionExpire != nil {
+			d.warn("health_check, scheduler and session of layer7 listener(%s) should be declared on rules",
+				want.Key())
+		}
+	} else if rule := cur.Layer4Rule; rule != nil {
+		if want.HealthCheck != nil && !jsonSubset(want.HealthCheck, rule.HealthCheck) {
+			fields = append(fields, "health_check")
+		}
+		if (len(want.Scheduler) > 0 && want.Scheduler != rule.Scheduler) ||
+			(len(want.SessionType) > 0 && want.SessionType != rule.SessionType) ||
+			(want.SessionExpire != nil && *want.SessionExpire != rule.SessionExpire) {
+			d.warn("scheduler and session of layer4 listener(%s) can not be modified, recreate the listener "+
+				"to change them", want.Key())
+		}
+	}
+
+	if len(fields) > 0 {
+		d.add(corelb.TCloudSpecChange{Action: corelb.SpecChangeUpdate, ResType: corelb.SpecResListener,
+			Protocol: want.Protocol, Port: want.Port, Fields: fields, ListenerID: lbl.ID,
+			Listener: stripListenerSpec(want)})
+	}
+
+	base := corelb.TCloudSpecChange{Protocol: want.Protocol, Port: want.Port, ListenerID: lbl.ID}
+	if !lbl.Protocol.IsLayer7Protocol() {
+		if cur.Layer4Rule != nil {
+			base.RuleID = cur.Layer4Rule.ID
+		}
+		return d.diffTargets(base, cur.TargetGroupID, cur.Targets, want.Targets)
+	}
+
+	wantRules := make(map[string]struct{}, len(want.Rules))
+	for _, rule := range want.Rules {
+		wantRules[rule.Key()] = struct{}{}
+		curRule, ok := cur.Rules[rule.Key()]
+		if !ok {
+			d.createRule(base, rule)
+			continue
+		}
+		if err := d.diffRule(base, lbl.SniSwitch, curRule, rule); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(cur.Rules) {
+		if _, ok := wantRules[key]; ok {
+			continue
+		}
+		rule := cur.Rules[key].Rule
+		change := base
+		change.Action, change.ResType = corelb.SpecChangeDelete, corelb.SpecResUrlRule
+		change.Domain, change.Url, change.RuleID = rule.Domain, rule.URL, rule.ID
+		d.add(change)
+	}
+	return nil
+}
+
+func (d *specDiffer) diffRule(base corelb.TCloudSpecChange, sniSwitch enumor.SniType, cur *TCloudRuleState,
+	want corelb.TCloudRuleSpec) error {
+
+	rule := cur.Rule
+	fields := make([]string, 0)
+	if len(want.Scheduler) > 0 && want.Scheduler != rule.Scheduler {
+		fields = append(fields, "scheduler")
+	}
+	if want.SessionExpire != nil && *want.SessionExpire != rule.SessionExpire {
+		fields = append(fields, "session_expire")
+	}
+	if want.HealthCheck != nil && !jsonSubset(want.HealthCheck, rule.HealthCheck) {
+		fields = append(fields, "health_check")
+	}
+	if want.Certificate != nil {
+		if sniSwitch != enumor.SniTypeOpen {
+			d.warn("certificate of rule(%s) only takes effect on sni listener(%s)", want.Key(),
+				listenerKey(base.Protocol, base.Port))
+		} else if !certEqual(want.Certificate, rule.Certificate) {
+			fields = append(fields, "certificate")
+		}
+	}
+
+	base.Domain, base.Url, base.RuleID = want.Domain, want.Url, rule.ID
+	if len(fields) > 0 {
+		change := base
+		change.Action, change.ResType, change.Fields, change.Rule = corelb.SpecChangeUpdate, corelb.SpecResUrlRule,
+			fields, stripRuleSpec(want)
+		d.add(change)
+	}
+	return d.diffTargets(base, cur.TargetGroupID, cur.Targets, want.Targets)
+}
+
+// diffTargets 对比监听器/规则下的RS，RS的解绑、权重调整通过其所在目标组完成
+func (d *specDiffer) diffTargets(base corelb.TCloudSpecChange, tgID string, cur []corelb.BaseTarget,
+	want []corelb.TCloudTargetSpec) error {
+
+	curMap := make(map[string]corelb.BaseTarget, len(cur))
+	for _, target := range cur {
+		curMap[targetToSpec(target).Key()] = target
+	}
+	changed := false
+	wantKeys := make(map[string]struct{}, len(want))
+	for _, target := range want {
+		wantKeys[target.Key()] = struct{}{}
+		exist, ok := curMap[target.Key()]
+		if !ok {
+			d.addTarget(corelb.SpecChangeCreate, base, target)
+			changed = true
+			continue
+		}
+		if cvt.PtrToVal(exist.Weight) == cvt.PtrToVal(target.Weight) {
+			continue
+		}
+		change := base
+		change.TargetID, change.TargetGroupID, change.OldWeight = exist.ID, tgID, exist.Weight
+		target.IP = exist.IP
+		d.addTarget(corelb.SpecChangeUpdate, change, target)
+		changed = true
+	}
+	for _, target := range cur {
+		spec := targetToSpec(target)
+		if _, ok := wantKeys[spec.Key()]; ok {
+			continue
+		}
+		change := base
+		change.TargetID, change.TargetGroupID = target.ID, tgID
+		d.addTarget(corelb.SpecChangeDelete, change, spec)
+		changed = true
+	}
+
+	if changed && d.state.TGRuleCount[tgID] > 1 {
+		owner := listenerKey(base.Protocol, base.Port) + base.Domain + base.Url
+		return errf.Newf(errf.InvalidParameter, "target group(%s) of %s is shared by multiple listeners or rules, "+
+			"its targets can not be managed by spec", tgID, owner)
+	}
+	return nil
+}
+
+// stripListenerSpec 去掉RS、规则，变更中的监听器只保留监听器自身属性
+func stripListenerSpec(lbl corelb.TCloudListenerSpec) *corelb.TCloudListenerSpec {
+	lbl.Targets, lbl.Rules = nil, nil
+	return &lbl
+}
+
+func stripRuleSpec(rule corelb.TCloudRuleSpec) *corelb.TCloudRuleSpec {
+	rule.Targets = nil
+	return &rule
+}
+
+// certEqual 证书中配置的字段是否与当前证书一致，证书ID列表不区分顺序
+func certEqual(want, have *corelb.TCloudCertificateInfo) bool {
+	if have == nil {
+		return false
+	}
+	wantCopy, haveCopy := *want, *have
+	wantCopy.CertCloudIDs = slice.Unique(wantCopy.CertCloudIDs)
+	haveCopy.CertCloudIDs = slice.Unique(haveCopy.CertCloudIDs)
+	sort.Strings(wantCopy.CertCloudIDs)
+	sort.Strings(haveCopy.CertCloudIDs)
+	return jsonSubset(&wantCopy, &haveCopy)
+}
+
+// jsonSubset want中非空的字段是否都与have一致，未配置的字段不参与对比
+func jsonSubset(want, have any) bool {
+	wantMap, haveMap := make(map[string]any), make(map[string]any)
+	if raw, err := json.Marshal(want); err != nil || json.Unmarshal(raw, &wantMap) != nil {
+		return false
+	}
+	if raw, err := json.Marshal(have); err != nil || json.Unmarshal(raw, &haveMap) != nil {
+		return false
+	}
+	for key, val := range wantMap {
+		if val == nil {
+			continue
+		}
+		if !reflect.DeepEqual(val, haveMap[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitTCloudSpecChanges 将按执行顺序排列的变更拆分为多个批次，每个批次只包含同一阶段的变更且不超过 size 个
+func SplitTCloudSpecChanges(changes []corelb.TCloudSpecChange, size int) [][]corelb.TCloudSpecChange {
+	result := make([][]corelb.TCloudSpecChange, 0)
+	for start := 0; start < len(changes); {
+		end := start + 1
+		for end < len(changes) && end-start < size && changes[end].Phase() == changes[start].Phase() {
+			end++
+		}
+		result = append(result, changes[start:end])
+		start = end
+	}
+	return result
+}
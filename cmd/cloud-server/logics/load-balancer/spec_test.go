@@ -0,0 +1,232 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"testing"
+
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestSpecState() *TCloudSpecState {
+	tcp := &TCloudListenerState{
+		Listener: corelb.TCloudListener{BaseListener: &corelb.BaseListener{ID: "lbl-tcp", Name: "tcp",
+			Protocol: enumor.TcpProtocol, Port: 80}},
+		Layer4Rule: &corelb.TCloudLbUrlRule{ID: "rule-tcp", RuleType: enumor.Layer4RuleType, Scheduler: "WRR",
+			HealthCheck: &corelb.TCloudHealthCheckInfo{HealthSwitch: cvt.ValToPtr(int64(1)),
+				CheckType: cvt.ValToPtr("TCP")}},
+		TargetGroupID: "tg-tcp",
+		Targets: []corelb.BaseTarget{
+			{ID: "rs-1", InstType: enumor.CvmInstType, CloudInstID: "ins-1", IP: "10.0.0.1", Port: 8080,
+				Weight: cvt.ValToPtr(int64(10)), TargetGroupID: "tg-tcp"},
+			{ID: "rs-2", InstType: enumor.CvmInstType, CloudInstID: "ins-2", IP: "10.0.0.2", Port: 8080,
+				Weight: cvt.ValToPtr(int64(10)), TargetGroupID: "tg-tcp"},
+		},
+		Rules: map[string]*TCloudRuleState{},
+	}
+	http := &TCloudListenerState{
+		Listener: corelb.TCloudListener{BaseListener: &corelb.BaseListener{ID: "lbl-http", Name: "http",
+			Protocol: enumor.HttpProtocol, Port: 8000}},
+		Rules: map[string]*TCloudRuleState{
+			"a.com/": {Rule: corelb.TCloudLbUrlRule{ID: "rule-a", Domain: "a.com", URL: "/", Scheduler: "WRR"},
+				TargetGroupID: "tg-a"},
+			"b.com/": {Rule: corelb.TCloudLbUrlRule{ID: "rule-b", Domain: "b.com", URL: "/", Scheduler: "WRR"}},
+		},
+	}
+	udp := &TCloudListenerState{
+		Listener: corelb.TCloudListener{BaseListener: &corelb.BaseListener{ID: "lbl-udp", Name: "udp",
+			Protocol: enumor.UdpProtocol, Port: 53}},
+		Rules: map[string]*TCloudRuleState{},
+	}
+	return &TCloudSpecState{
+		LoadBalancer: corelb.BaseLoadBalancer{ID: "lb-1", CloudID: "lb-cloud-1", Vendor: enumor.TCloud},
+		Listeners:    map[string]*TCloudListenerState{"TCP:80": tcp, "HTTP:8000": http, "UDP:53": udp},
+		TGRuleCount:  map[string]int{"tg-tcp": 1, "tg-a": 1},
+	}
+}
+
+func TestDiffTCloudSpec(t *testing.T) {
+	state := buildTestSpecState()
+	spec := ExportTCloudSpec(state)
+
+	// 导出的配置与当前状态一致，不产生变更
+	plan, err := DiffTCloudSpec(state, spec)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+
+	spec.Listeners = []corelb.TCloudListenerSpec{
+		{
+			Name: "tcp-new", Protocol: enumor.TcpProtocol, Port: 80,
+			HealthCheck: &corelb.TCloudHealthCheckInfo{CheckType: cvt.ValToPtr("HTTP")},
+			Targets: []corelb.TCloudTargetSpec{
+				{InstType: enumor.CvmInstType, CloudInstID: "ins-1", Port: 8080, Weight: cvt.ValToPtr(int64(20))},
+				{InstType: enumor.CvmInstType, CloudInstID: "ins-3", Port: 8080, Weight: cvt.ValToPtr(int64(10))},
+			},
+		},
+		{
+			Name: "http", Protocol: enumor.HttpProtocol, Port: 8000,
+			Rules: []corelb.TCloudRuleSpec{{Domain: "a.com", Url: "/", Scheduler: "WRR"},
+				{Domain: "c.com", Url: "/", Targets: []corelb.TCloudTargetSpec{{InstType: enumor.EniInstType,
+					IP: "10.0.1.1", Port: 80, Weight: cvt.ValToPtr(int64(10))}}}},
+		},
+		{Name: "https", Protocol: enumor.HttpsProtocol, Port: 443, SniSwitch: cvt.ValToPtr(enumor.SniTypeOpen)},
+	}
+	plan, err = DiffTCloudSpec(state, spec)
+	assert.NoError(t, err)
+
+	type brief struct {
+		action  corelb.SpecChangeAction
+		resType corelb.SpecChangeResType
+		id      string
+	}
+	got := make([]brief, 0, len(plan.Changes))
+	for _, c := range plan.Changes {
+		id := c.ListenerID
+		switch {
+		case c.ResType == corelb.SpecResTarget:
+			id = c.Target.Key()
+		case c.ResType == corelb.SpecResUrlRule:
+			id = c.Domain + c.Url
+		case c.Action == corelb.SpecChangeCreate:
+			id = c.Listener.Key()
+		}
+		got = append(got, brief{c.Action, c.ResType, id})
+	}
+	assert.Equal(t, []brief{
+		{corelb.SpecChangeDelete, corelb.SpecResTarget, "CVM/ins-2:8080"},
+		{corelb.SpecChangeDelete, corelb.SpecResUrlRule, "b.com/"},
+		{corelb.SpecChangeDelete, corelb.SpecResListener, "lbl-udp"},
+		{corelb.SpecChangeUpdate, corelb.SpecResListener, "lbl-tcp"},
+		{corelb.SpecChangeCreate, corelb.SpecResListener, "HTTPS:443"},
+		{corelb.SpecChangeCreate, corelb.SpecResUrlRule, "c.com/"},
+		{corelb.SpecChangeUpdate, corelb.SpecResTarget, "CVM/ins-1:8080"},
+		{corelb.SpecChangeCreate, corelb.SpecResTarget, "CVM/ins-3:8080"},
+		{corelb.SpecChangeCreate, corelb.SpecResTarget, "ENI/10.0.1.1:80"},
+	}, got)
+	assert.Equal(t, []string{"name", "health_check"}, plan.Changes[3].Fields)
+	assert.Equal(t, "tg-tcp", plan.Changes[6].TargetGroupID)
+
+	// 不开启 prune 时不删除未声明的监听器
+	spec.Prune = false
+	plan, err = DiffTCloudSpec(state, spec)
+	assert.NoError(t, err)
+	for _, c := range plan.Changes {
+		assert.NotEqual(t, "lbl-udp", c.ListenerID)
+	}
+
+	// 目标组被多个规则共用时不能按规则调整RS
+	state.TGRuleCount["tg-tcp"] = 2
+	_, err = DiffTCloudSpec(state, spec)
+	assert.Error(t, err)
+}
+
+func TestValidateTCloudSpec(t *testing.T) {
+	weight := cvt.ValToPtr(int64(10))
+	tests := []struct {
+		name string
+		spec corelb.TCloudLoadBalancerSpec
+	}{
+		{name: "重复的监听器", spec: corelb.TCloudLoadBalancerSpec{Listeners: []corelb.TCloudListenerSpec{
+			{Name: "a", Protocol: enumor.TcpProtocol, Port: 80}, {Name: "b", Protocol: enumor.TcpProtocol, Port: 80}}}},
+		{name: "四层监听器声明规则", spec: corelb.TCloudLoadBalancerSpec{Listeners: []corelb.TCloudListenerSpec{
+			{Name: "a", Protocol: enumor.TcpProtocol, Port: 80,
+				Rules: []corelb.TCloudRuleSpec{{Domain: "a", Url: "/"}}}}}},
+		{name: "七层监听器直接声明RS", spec: corelb.TCloudLoadBalancerSpec{Listeners: []corelb.TCloudListenerSpec{
+			{Name: "a", Protocol: enumor.HttpProtocol, Port: 80, Targets: []corelb.TCloudTargetSpec{
+				{InstType: enumor.CvmInstType, CloudInstID: "ins-1", Port: 80, Weight: weight}}}}}},
+		{name: "CVM缺少实例ID", spec: corelb.TCloudLoadBalancerSpec{Listeners: []corelb.TCloudListenerSpec{
+			{Name: "a", Protocol: enumor.TcpProtocol, Port: 80, Targets: []corelb.TCloudTargetSpec{
+				{InstType: enumor.CvmInstType, IP: "10.0.0.1", Port: 80, Weight: weight}}}}}},
+		{name: "缺少权重", spec: corelb.TCloudLoadBalancerSpec{Listeners: []corelb.TCloudListenerSpec{
+			{Name: "a", Protocol: enumor.TcpProtocol, Port: 80, Targets: []corelb.TCloudTargetSpec{
+				{InstType: enumor.EniInstType, IP: "10.0.0.1", Port: 80}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, ValidateTCloudSpec(&tt.spec))
+		})
+	}
+}
+
+func TestParseTCloudSpecYAML(t *testing.T) {
+	req := &cslb.TCloudSpecReq{Format: cslb.SpecFormatYAML, Content: `
+cloud_id: lb-cloud-1
+prune: true
+listeners:
+  - name: web
+    protocol: HTTPS
+    port: 443
+    sni_switch: 0
+    certificate:
+      ssl_mode: UNIDIRECTIONAL
+      cert_cloud_ids: [cert-1]
+    rules:
+      - domain: a.com
+        url: /
+        targets:
+          - {inst_type: CVM, cloud_inst_id: ins-1, port: 8080, weight: 10}
+`}
+	assert.NoError(t, req.Validate())
+	spec, err := req.Parse()
+	assert.NoError(t, err)
+	assert.True(t, spec.Prune)
+	assert.Equal(t, "HTTPS:443", spec.Listeners[0].Key())
+	assert.Equal(t, []string{"cert-1"}, spec.Listeners[0].Certificate.CertCloudIDs)
+	assert.Equal(t, int64(10), *spec.Listeners[0].Rules[0].Targets[0].Weight)
+	assert.NoError(t, ValidateTCloudSpec(spec))
+}
+
+func TestSplitTCloudSpecChanges(t *testing.T) {
+	create := corelb.TCloudSpecChange{Action: corelb.SpecChangeCreate, ResType: corelb.SpecResTarget}
+	remove := corelb.TCloudSpecChange{Action: corelb.SpecChangeDelete, ResType: corelb.SpecResTarget}
+	parts := SplitTCloudSpecChanges([]corelb.TCloudSpecChange{remove, create, create, create}, 2)
+	assert.Equal(t, [][]corelb.TCloudSpecChange{{remove}, {create, create}, {create}}, parts)
+}
+
+func TestDiffTCloudSnapshotRollback(t *testing.T) {
+	state := buildTestSpecState()
+	snapshot := ExportTCloudSpec(state)
+
+	// 快照后调整了RS权重、解绑了RS并新建了监听器
+	tcp := state.Listeners["TCP:80"]
+	tcp.Targets[0].Weight = cvt.ValToPtr(int64(0))
+	tcp.Targets = tcp.Targets[:1]
+	state.Listeners["TCP:8080"] = &TCloudListenerState{
+		Listener: corelb.TCloudListener{BaseListener: &corelb.BaseListener{ID: "lbl-new", Name: "new",
+			Protocol: enumor.TcpProtocol, Port: 8080}},
+		Rules: map[string]*TCloudRuleState{},
+	}
+
+	plan, err := DiffTCloudSpec(state, snapshot)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Changes, 3)
+	assert.Equal(t, corelb.SpecChangeDelete, plan.Changes[0].Action)
+	assert.Equal(t, "lbl-new", plan.Changes[0].ListenerID)
+	assert.Equal(t, corelb.SpecChangeUpdate, plan.Changes[1].Action)
+	assert.Equal(t, int64(10), *plan.Changes[1].Target.Weight)
+	assert.Equal(t, int64(0), *plan.Changes[1].OldWeight)
+	assert.Equal(t, corelb.SpecChangeCreate, plan.Changes[2].Action)
+	assert.Equal(t, "CVM/ins-2:8080", plan.Changes[2].Target.Key())
+}
@@ -0,0 +1,536 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	ds "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/slice"
+)
+
+// targetHealthQueryLbLimit 单次查询RS健康状态的负载均衡数量上限
+const targetHealthQueryLbLimit = 20
+
+// TargetHealthCollector 定时采集负载均衡下RS的健康状态，记录健康状态变化事件，并在监听器健康RS占比低于阈值时发送告警
+type TargetHealthCollector struct {
+	client   *client.ClientSet
+	cmsiCli  cmsi.Client
+	bkHcmUrl string
+	conf     cc.TargetHealth
+	// states 各负载均衡下RS最近一次采集到的健康状态，key为 tenantID/lbID
+	states map[string]map[string]enumor.TargetHealthStatus
+	// lowRatioListeners 健康RS占比已低于阈值的监听器，恢复前不再重复告警，key为 tenantID/lbID/cloudLblID
+	lowRatioListeners map[string]bool
+}
+
+// NewTargetHealthCollector new target health collector.
+func NewTargetHealthCollector(client *client.ClientSet, cmsiCli cmsi.Client, bkHcmUrl string,
+	conf cc.TargetHealth) *TargetHealthCollector {
+
+	return &TargetHealthCollector{
+		client:            client,
+		cmsiCli:           cmsiCli,
+		bkHcmUrl:          bkHcmUrl,
+		conf:              conf,
+		states:            make(map[string]map[string]enumor.TargetHealthStatus),
+		lowRatioListeners: make(map[string]bool),
+	}
+}
+
+// Reset 清空缓存的健康状态，非主节点期间其他节点可能已记录了新的事件，重新成为主节点后需要从db中重新加载
+func (c *TargetHealthCollector) Reset() {
+	c.states = make(map[string]map[string]enumor.TargetHealthStatus)
+	c.lowRatioListeners = make(map[string]bool)
+}
+
+// lowRatioListener 健康RS占比低于阈值的监听器
+type lowRatioListener struct {
+	Lb           corelb.BaseLoadBalancer
+	CloudLblID   string
+	ListenerName string
+	HealthyNum   int
+	TotalNum     int
+	// key 告警状态的缓存key，告警发送成功后才标记为已告警
+	key string
+}
+
+// Collect 采集当前租户下腾讯云负载均衡的RS健康状态，并清理超出保留时间的事件
+func (c *TargetHealthCollector) Collect(kt *kit.Kit, now time.Time) error {
+	if err := c.pruneEvents(kt, now); err != nil {
+		return err
+	}
+
+	lbs, err := c.listLoadBalancers(kt)
+	if err != nil {
+		return err
+	}
+
+	// 按账号、地域分组查询健康状态
+	groups := make(map[string][]corelb.BaseLoadBalancer)
+	for _, lb := range lbs {
+		groupKey := lb.AccountID + "/" + lb.Region
+		groups[groupKey] = append(groups[groupKey], lb)
+	}
+
+	lowRatios := make([]lowRatioListener, 0)
+	for _, groupLbs := range groups {
+		for _, batch := range slice.Split(groupLbs, targetHealthQueryLbLimit) {
+			batchLowRatios, err := c.collectBatch(kt, batch)
+			if err != nil {
+				// 单个账号地域采集失败不影响其他负载均衡的采集
+				continue
+			}
+			lowRatios = append(lowRatios, batchLowRatios...)
+		}
+	}
+
+	if len(lowRatios) != 0 {
+		c.notifyLowRatio(kt, lowRatios)
+	}
+	return nil
+}
+
+// collectBatch 采集一批负载均衡的RS健康状态并记录变化事件，事件记录成功后才更新缓存的健康状态
+func (c *TargetHealthCollector) collectBatch(kt *kit.Kit, lbs []corelb.BaseLoadBalancer) ([]lowRatioListener,
+	error) {
+
+	lbMap := make(map[string]corelb.BaseLoadBalancer, len(lbs))
+	for _, lb := range lbs {
+		lbMap[lb.CloudID] = lb
+	}
+	req := &hcproto.TCloudTargetHealthReq{
+		AccountID:  lbs[0].AccountID,
+		Region:     lbs[0].Region,
+		CloudLbIDs: slice.Map(lbs, func(lb corelb.BaseLoadBalancer) string { return lb.CloudID }),
+	}
+	resp, err := c.client.HCService().TCloud.Clb.ListTargetHealth(kt, req)
+	if err != nil {
+		logs.Errorf("list target health failed, err: %v, account: %s, region: %s, lbs: %v, rid: %s", err,
+			req.AccountID, req.Region, req.CloudLbIDs, kt.Rid)
+		return nil, err
+	}
+
+	events := make([]dataproto.TargetHealthEventCreate, 0)
+	states := make(map[string]map[string]enumor.TargetHealthStatus)
+	lowRatios := make([]lowRatioListener, 0)
+	for _, result := range resp.Details {
+		lb, exist := lbMap[result.CloudLbID]
+		if !exist {
+			continue
+		}
+
+		stateKey := kt.TenantID + "/" + lb.ID
+		prev, cached := c.states[stateKey]
+		if !cached {
+			if prev, err = c.loadLbHealthState(kt, lb.ID); err != nil {
+				continue
+			}
+		}
+		lbEvents, current := DiffTargetHealth(lb, result, prev)
+		events = append(events, lbEvents...)
+		states[stateKey] = current
+
+		for _, ratio := range listenerHealthyRatios(result) {
+			lblKey := stateKey + "/" + ratio.CloudLblID
+			if !IsHealthyRatioLow(ratio.HealthyNum, ratio.TotalNum, c.threshold()) {
+				delete(c.lowRatioListeners, lblKey)
+				continue
+			}
+			if !c.lowRatioListeners[lblKey] {
+				ratio.Lb = lb
+				ratio.key = lblKey
+				lowRatios = append(lowRatios, ratio)
+			}
+		}
+	}
+
+	for _, batch := range slice.Split(events, constant.BatchOperationMaxLimit) {
+		req := &dataproto.TargetHealthEventBatchCreateReq{Events: batch}
+		if _, err = c.client.DataService().Global.LoadBalancer.BatchCreateTargetHealthEvent(kt, req); err != nil {
+			logs.Errorf("create target health event failed, err: %v, rid: %s", err, kt.Rid)
+			// 部分事件可能已记录，清空缓存下次从db中重新加载，避免重复或遗漏事件
+			for stateKey := range states {
+				delete(c.states, stateKey)
+			}
+			return nil, err
+		}
+	}
+
+	for stateKey, current := range states {
+		c.states[stateKey] = current
+	}
+	return lowRatios, nil
+}
+
+func (c *TargetHealthCollector) threshold() uint {
+	if c.conf.HealthyRatioThreshold == nil {
+		return 0
+	}
+	return *c.conf.HealthyRatioThreshold
+}
+
+func (c *TargetHealthCollector) listLoadBalancers(kt *kit.Kit) ([]corelb.BaseLoadBalancer, error) {
+	lbs := make([]corelb.BaseLoadBalancer, 0)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("vendor", enumor.TCloud),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		Fields: []string{"id", "cloud_id", "name", "vendor", "account_id", "bk_biz_id", "region", "creator"},
+	}
+	for {
+		result, err := c.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt, listReq)
+		if err != nil {
+			logs.Errorf("list load balancer for target health collect failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		lbs = append(lbs, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return lbs, nil
+}
+
+// loadLbHealthState 根据已记录的事件恢复负载均衡下各RS最近一次的健康状态
+func (c *TargetHealthCollector) loadLbHealthState(kt *kit.Kit, lbID string) (map[string]enumor.TargetHealthStatus,
+	error) {
+
+	events, err := ListTargetHealthEvents(kt, c.client.DataService(), tools.EqualExpression("lb_id", lbID))
+	if err != nil {
+		return nil, err
+	}
+
+	state := make(map[string]enumor.TargetHealthStatus)
+	for _, event := range events {
+		state[targetHealthEventKey(event)] = event.Status
+	}
+	return state, nil
+}
+
+// pruneEvents 清理超出保留时间的事件，保留时间内没有事件的RS保留最近一次事件，用于恢复RS的健康状态
+func (c *TargetHealthCollector) pruneEvents(kt *kit.Kit, now time.Time) error {
+	expireAt := now.AddDate(0, 0, -int(c.conf.RetentionDays)).Format(constant.TimeStdFormat)
+	expired, err := ListTargetHealthEvents(kt, c.client.DataService(),
+		tools.ExpressionAnd(tools.RuleLessThan("created_at", expireAt)))
+	if err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	lbIDs := slice.Unique(slice.Map(expired, func(event corelb.TargetHealthEvent) string { return event.LbID }))
+	retainedKeys := make(map[string]bool)
+	for _, batch := range slice.Split(lbIDs, constant.BatchOperationMaxLimit) {
+		expr := tools.ExpressionAnd(tools.RuleGreaterThanEqual("created_at", expireAt), tools.RuleIn("lb_id", batch))
+		retained, err := ListTargetHealthEvents(kt, c.client.DataService(), expr)
+		if err != nil {
+			return err
+		}
+		for _, event := range retained {
+			retainedKeys[targetHealthEventKey(event)] = true
+		}
+	}
+
+	for _, batch := range slice.Split(PrunableTargetHealthEventIDs(expired, retainedKeys),
+		constant.BatchOperationMaxLimit) {
+
+		req := &ds.BatchDeleteReq{Filter: tools.ContainersExpression("id", batch)}
+		if err = c.client.DataService().Global.LoadBalancer.BatchDeleteTargetHealthEvent(kt, req); err != nil {
+			logs.Errorf("delete expired target health event failed, err: %v, expireAt: %s, rid: %s", err,
+				expireAt, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// PrunableTargetHealthEventIDs 返回按创建顺序排列的过期事件中可以删除的事件，保留时间内没有事件的RS保留其最近一次事件
+func PrunableTargetHealthEventIDs(expired []corelb.TargetHealthEvent, retainedKeys map[string]bool) []string {
+	latest := make(map[string]string)
+	for _, event := range expired {
+		key := targetHealthEventKey(event)
+		if !retainedKeys[key] {
+			latest[key] = event.ID
+		}
+	}
+
+	ids := make([]string, 0, len(expired))
+	for _, event := range expired {
+		if latest[targetHealthEventKey(event)] == event.ID {
+			continue
+		}
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func (c *TargetHealthCollector) notifyLowRatio(kt *kit.Kit, listeners []lowRatioListener) {
+	// 按负载均衡创建人汇总告警，后台同步的负载均衡没有实际的创建者，只通知配置的接收人
+	creatorListeners := make(map[string][]lowRatioListener)
+	for _, one := range listeners {
+		creator := one.Lb.Creator
+		if creator == constant.BackendOperationUserKey {
+			creator = ""
+		}
+		creatorListeners[creator] = append(creatorListeners[creator], one)
+	}
+
+	for creator, items := range creatorListeners {
+		receivers := make([]string, 0, len(c.conf.Receivers)+1)
+		if len(creator) != 0 {
+			receivers = append(receivers, creator)
+		}
+		receivers = slice.Unique(append(receivers, c.conf.Receivers...))
+		if len(receivers) == 0 {
+			logs.Warnf("no receiver for target health alarm, listeners: %d, rid: %s", len(items), kt.Rid)
+			continue
+		}
+
+		contents := make([]string, 0, len(items))
+		for _, one := range items {
+			contents = append(contents, fmt.Sprintf("<li>负载均衡：%s（%s），监听器：%s（%s），业务：%d，健康RS：%d/%d</li>",
+				one.Lb.Name, one.Lb.CloudID, one.ListenerName, one.CloudLblID, one.Lb.BkBizID, one.HealthyNum,
+				one.TotalNum))
+		}
+		mail := &cmsi.CmsiMail{
+			ReceiverUserName: strings.Join(receivers, ","),
+			Title:            fmt.Sprintf("【HCM】 负载均衡监听器健康RS占比过低：共%d个", len(items)),
+			Content: fmt.Sprintf(`<p>您好：</p><p>以下负载均衡监听器的健康RS占比低于%d%%，请尽快在 <a href="%s">HCM</a> `+
+				`中检查RS的健康状态。</p><ul>%s</ul>`, c.threshold(), c.bkHcmUrl, strings.Join(contents, "")),
+		}
+		if err := c.cmsiCli.SendMail(kt, mail); err != nil {
+			logs.Errorf("send target health alarm failed, err: %v, receivers: %v, rid: %s", err, receivers, kt.Rid)
+			continue
+		}
+		for _, one := range items {
+			c.lowRatioListeners[one.key] = true
+		}
+	}
+}
+
+func targetHealthKey(cloudLblID, cloudRuleID, cloudInstID, ip string, port int64) string {
+	return fmt.Sprintf("%s/%s/%s/%s:%d", cloudLblID, cloudRuleID, cloudInstID, ip, port)
+}
+
+func targetHealthEventKey(event corelb.TargetHealthEvent) string {
+	return targetHealthKey(event.CloudLblID, event.CloudRuleID, event.CloudInstID, event.IP, event.Port)
+}
+
+func toTargetHealthStatus(healthy bool) enumor.TargetHealthStatus {
+	if healthy {
+		return enumor.TargetHealthy
+	}
+	return enumor.TargetUnhealthy
+}
+
+// DiffTargetHealth 对比负载均衡下各RS本次采集的健康状态与上一次的健康状态，返回健康状态变化以及首次采集到的RS对应的事件，
+// 以及本次采集的健康状态
+func DiffTargetHealth(lb corelb.BaseLoadBalancer, result hcproto.TCloudTargetHealthResult,
+	prev map[string]enumor.TargetHealthStatus) ([]dataproto.TargetHealthEventCreate,
+	map[string]enumor.TargetHealthStatus) {
+
+	events := make([]dataproto.TargetHealthEventCreate, 0)
+	current := make(map[string]enumor.TargetHealthStatus)
+	diff := func(cloudLblID, cloudRuleID string, targets []*hcproto.TCloudTargetHealthRsResult) {
+		for _, target := range targets {
+			if target == nil {
+				continue
+			}
+			key := targetHealthKey(cloudLblID, cloudRuleID, target.CloudInstID, target.IP, target.Port)
+			status := toTargetHealthStatus(target.HealthStatus)
+			current[key] = status
+
+			prevStatus := prev[key]
+			if prevStatus == status {
+				continue
+			}
+			events = append(events, dataproto.TargetHealthEventCreate{
+				Vendor:       lb.Vendor,
+				AccountID:    lb.AccountID,
+				BkBizID:      lb.BkBizID,
+				LbID:         lb.ID,
+				CloudLbID:    lb.CloudID,
+				CloudLblID:   cloudLblID,
+				CloudRuleID:  cloudRuleID,
+				CloudInstID:  target.CloudInstID,
+				IP:           target.IP,
+				Port:         target.Port,
+				Status:       status,
+				PrevStatus:   prevStatus,
+				StatusDetail: target.HealthStatusDetail,
+			})
+		}
+	}
+
+	for _, listener := range result.Listeners {
+		if listener == nil {
+			continue
+		}
+		diff(listener.CloudLblID, "", listener.Targets)
+		for _, rule := range listener.Rules {
+			if rule == nil {
+				continue
+			}
+			diff(listener.CloudLblID, rule.CloudRuleID, rule.Targets)
+		}
+	}
+	return events, current
+}
+
+// listenerHealthyRatios 统计各监听器下健康RS的数量，七层监听器汇总所有规则下的RS
+func listenerHealthyRatios(result hcproto.TCloudTargetHealthResult) []lowRatioListener {
+	ratios := make([]lowRatioListener, 0, len(result.Listeners))
+	for _, listener := range result.Listeners {
+		if listener == nil {
+			continue
+		}
+		ratio := lowRatioListener{CloudLblID: listener.CloudLblID, ListenerName: listener.ListenerName}
+		targets := append([]*hcproto.TCloudTargetHealthRsResult(nil), listener.Targets...)
+		for _, rule := range listener.Rules {
+			if rule != nil {
+				targets = append(targets, rule.Targets...)
+			}
+		}
+		for _, target := range targets {
+			if target == nil {
+				continue
+			}
+			ratio.TotalNum++
+			if target.HealthStatus {
+				ratio.HealthyNum++
+			}
+		}
+		ratios = append(ratios, ratio)
+	}
+	return ratios
+}
+
+// IsHealthyRatioLow 健康RS占比是否低于阈值百分比，没有RS或阈值为0时不告警
+func IsHealthyRatioLow(healthyNum, totalNum int, threshold uint) bool {
+	if totalNum == 0 || threshold == 0 {
+		return false
+	}
+	return uint(healthyNum)*100 < threshold*uint(totalNum)
+}
+
+// ListTargetHealthEvents 按事件的创建顺序查询所有符合条件的RS健康状态变化事件
+func ListTargetHealthEvents(kt *kit.Kit, dataCli *dataservice.Client, expr *filter.Expression) (
+	[]corelb.TargetHealthEvent, error) {
+
+	events := make([]corelb.TargetHealthEvent, 0)
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+	}
+	for {
+		result, err := dataCli.Global.LoadBalancer.ListTargetHealthEvent(kt, listReq)
+		if err != nil {
+			logs.Errorf("list target health event failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		events = append(events, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return events, nil
+}
+
+// StatTargetHealth 根据按创建顺序排列的事件统计各RS在 [start, end] 时间段内的健康状态变化次数和不健康时长，
+// start 之前的事件只用于确定时间段开始时的健康状态
+func StatTargetHealth(events []corelb.TargetHealthEvent, start, end time.Time) ([]corelb.TargetHealthStat,
+	error) {
+
+	type statState struct {
+		stat  *corelb.TargetHealthStat
+		since time.Time
+	}
+	states := make(map[string]*statState)
+	keys := make([]string, 0)
+	for _, event := range events {
+		at, err := time.Parse(constant.TimeStdFormat, event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse target health event(%s) created_at failed, err: %v", event.ID, err)
+		}
+		if at.After(end) {
+			continue
+		}
+
+		key := targetHealthKey(event.CloudLblID, event.CloudRuleID, event.CloudInstID, event.IP, event.Port)
+		state, exist := states[key]
+		if !exist {
+			state = &statState{stat: &corelb.TargetHealthStat{LbID: event.LbID, CloudLbID: event.CloudLbID,
+				CloudLblID: event.CloudLblID, CloudRuleID: event.CloudRuleID, CloudInstID: event.CloudInstID,
+				IP: event.IP, Port: event.Port}}
+			states[key] = state
+			keys = append(keys, key)
+		}
+
+		if at.Before(start) {
+			at = start
+		} else {
+			if state.stat.Status == enumor.TargetUnhealthy {
+				state.stat.DowntimeSec += int64(at.Sub(state.since).Seconds())
+			}
+			if len(event.PrevStatus) != 0 {
+				state.stat.Transitions++
+			}
+			if event.PrevStatus == enumor.TargetHealthy && event.Status == enumor.TargetUnhealthy {
+				state.stat.UnhealthyTimes++
+			}
+		}
+		state.stat.Status = event.Status
+		state.stat.LastChangedAt = event.CreatedAt
+		state.since = at
+	}
+
+	stats := make([]corelb.TargetHealthStat, 0, len(keys))
+	for _, key := range keys {
+		state := states[key]
+		if state.stat.Status == enumor.TargetUnhealthy {
+			state.stat.DowntimeSec += int64(end.Sub(state.since).Seconds())
+		}
+		stats = append(stats, *state.stat)
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].LbID < stats[j].LbID })
+	return stats, nil
+}
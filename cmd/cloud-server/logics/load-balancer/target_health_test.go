@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"testing"
+	"time"
+
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestTargetHealthResult(rs1Healthy, rs2Healthy bool) hcproto.TCloudTargetHealthResult {
+	return hcproto.TCloudTargetHealthResult{
+		CloudLbID: "lb-xxx",
+		Listeners: []*hcproto.TCloudTargetHealthLblResult{
+			{CloudLblID: "lbl-tcp", Protocol: enumor.TcpProtocol, Targets: []*hcproto.TCloudTargetHealthRsResult{
+				{CloudInstID: "ins-1", IP: "10.0.0.1", Port: 80, HealthStatus: rs1Healthy},
+				{CloudInstID: "ins-2", IP: "10.0.0.2", Port: 80, HealthStatus: rs2Healthy},
+			}},
+			{CloudLblID: "lbl-http", Protocol: enumor.HttpProtocol, Rules: []*hcproto.TCloudTargetHealthRuleResult{
+				{CloudRuleID: "loc-1", Targets: []*hcproto.TCloudTargetHealthRsResult{
+					{CloudInstID: "ins-1", IP: "10.0.0.1", Port: 8080, HealthStatus: true},
+				}},
+			}},
+		},
+	}
+}
+
+func TestDiffTargetHealth(t *testing.T) {
+	lb := corelb.BaseLoadBalancer{ID: "lb-1", CloudID: "lb-xxx", Vendor: enumor.TCloud, AccountID: "acc",
+		BkBizID: 100}
+
+	// 首次采集，所有RS都记录事件
+	events, state := DiffTargetHealth(lb, buildTestTargetHealthResult(true, true), nil)
+	assert.Len(t, events, 3)
+	assert.Len(t, state, 3)
+	for _, event := range events {
+		assert.Equal(t, enumor.TargetHealthStatus(""), event.PrevStatus)
+		assert.Equal(t, enumor.TargetHealthy, event.Status)
+		assert.Equal(t, int64(100), event.BkBizID)
+	}
+
+	// 状态未变化，不记录事件
+	events, state = DiffTargetHealth(lb, buildTestTargetHealthResult(true, true), state)
+	assert.Len(t, events, 0)
+
+	// 只记录状态变化的RS
+	events, _ = DiffTargetHealth(lb, buildTestTargetHealthResult(true, false), state)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "lbl-tcp", events[0].CloudLblID)
+	assert.Equal(t, "ins-2", events[0].CloudInstID)
+	assert.Equal(t, enumor.TargetHealthy, events[0].PrevStatus)
+	assert.Equal(t, enumor.TargetUnhealthy, events[0].Status)
+}
+
+func TestListenerHealthyRatio(t *testing.T) {
+	ratios := listenerHealthyRatios(buildTestTargetHealthResult(true, false))
+	assert.Len(t, ratios, 2)
+	assert.Equal(t, "lbl-tcp", ratios[0].CloudLblID)
+	assert.Equal(t, 1, ratios[0].HealthyNum)
+	assert.Equal(t, 2, ratios[0].TotalNum)
+	assert.Equal(t, "lbl-http", ratios[1].CloudLblID)
+	assert.Equal(t, 1, ratios[1].HealthyNum)
+	assert.Equal(t, 1, ratios[1].TotalNum)
+
+	assert.False(t, IsHealthyRatioLow(1, 2, 50))
+	assert.True(t, IsHealthyRatioLow(1, 2, 60))
+	assert.False(t, IsHealthyRatioLow(0, 2, 0))
+	assert.False(t, IsHealthyRatioLow(0, 0, 50))
+}
+
+func TestStatTargetHealth(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	at := func(d time.Duration) string { return start.Add(d).Format(time.RFC3339) }
+	rs1 := corelb.TargetHealthEvent{LbID: "lb-1", CloudLblID: "lbl-1", IP: "10.0.0.1", Port: 80}
+	rs2 := corelb.TargetHealthEvent{LbID: "lb-1", CloudLblID: "lbl-1", IP: "10.0.0.2", Port: 80}
+	event := func(base corelb.TargetHealthEvent, prev, status enumor.TargetHealthStatus,
+		d time.Duration) corelb.TargetHealthEvent {
+
+		base.PrevStatus, base.Status, base.CreatedAt = prev, status, at(d)
+		return base
+	}
+
+	events := []corelb.TargetHealthEvent{
+		// rs1 在统计开始前已不健康，10分钟后恢复，之后又出现一次10分钟的不健康
+		event(rs1, "", enumor.TargetHealthy, -2*time.Hour),
+		event(rs1, enumor.TargetHealthy, enumor.TargetUnhealthy, -time.Hour),
+		event(rs1, enumor.TargetUnhealthy, enumor.TargetHealthy, 10*time.Minute),
+		event(rs1, enumor.TargetHealthy, enumor.TargetUnhealthy, 20*time.Minute),
+		event(rs1, enumor.TargetUnhealthy, enumor.TargetHealthy, 30*time.Minute),
+		// rs2 在统计时间段内首次采集到且不健康，直到统计结束
+		event(rs2, "", enumor.TargetUnhealthy, 45*time.Minute),
+		// 统计结束后的事件不参与统计
+		event(rs2, enumor.TargetUnhealthy, enumor.TargetHealthy, 2*time.Hour),
+	}
+
+	stats, err := StatTargetHealth(events, start, end)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	assert.Equal(t, "10.0.0.1", stats[0].IP)
+	assert.Equal(t, 3, stats[0].Transitions)
+	assert.Equal(t, 1, stats[0].UnhealthyTimes)
+	assert.Equal(t, int64(20*60), stats[0].DowntimeSec)
+	assert.Equal(t, enumor.TargetHealthy, stats[0].Status)
+	assert.Equal(t, at(30*time.Minute), stats[0].LastChangedAt)
+
+	assert.Equal(t, "10.0.0.2", stats[1].IP)
+	assert.Equal(t, 0, stats[1].Transitions)
+	assert.Equal(t, int64(15*60), stats[1].DowntimeSec)
+	assert.Equal(t, enumor.TargetUnhealthy, stats[1].Status)
+
+	_, err = StatTargetHealth([]corelb.TargetHealthEvent{{ID: "1", CreatedAt: "invalid"}}, start, end)
+	assert.Error(t, err)
+}
+
+func TestPrunableTargetHealthEventIDs(t *testing.T) {
+	rs1 := corelb.TargetHealthEvent{LbID: "lb-1", CloudLblID: "lbl-1", IP: "10.0.0.1", Port: 80}
+	rs2 := corelb.TargetHealthEvent{LbID: "lb-1", CloudLblID: "lbl-1", IP: "10.0.0.2", Port: 80}
+	event := func(base corelb.TargetHealthEvent, id string) corelb.TargetHealthEvent {
+		base.ID = id
+		return base
+	}
+	expired := []corelb.TargetHealthEvent{event(rs1, "1"), event(rs2, "2"), event(rs1, "3"), event(rs2, "4")}
+
+	// 保留时间内没有事件的RS保留最近一次事件
+	assert.Equal(t, []string{"1", "2"}, PrunableTargetHealthEventIDs(expired, nil))
+
+	// 保留时间内有事件的RS可以删除全部过期事件
+	retained := map[string]bool{targetHealthEventKey(rs2): true}
+	assert.Equal(t, []string{"1", "2", "4"}, PrunableTargetHealthEventIDs(expired, retained))
+}
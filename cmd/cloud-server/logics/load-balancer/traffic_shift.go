@@ -0,0 +1,337 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"fmt"
+
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	actionflow "hcm/cmd/task-server/logics/flow"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	coretask "hcm/pkg/api/core/task"
+	"hcm/pkg/api/data-service/task"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	dataservice "hcm/pkg/client/data-service"
+	taskserver "hcm/pkg/client/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/json"
+)
+
+// trafficShiftWaitChunkSec 步间观察拆分成多个任务，单个观察任务的最长时间，避免超出异步任务的执行超时时间
+const trafficShiftWaitChunkSec = 60
+
+// BuildTrafficShiftStepParams 根据流量切换计划生成每一步的任务详情参数
+func BuildTrafficShiftStepParams(plan *corelb.TrafficShiftPlan) []corelb.TrafficShiftStepParam {
+	params := make([]corelb.TrafficShiftStepParam, 0, plan.Steps)
+	for step := 1; step <= plan.Steps; step++ {
+		param := corelb.TrafficShiftStepParam{
+			Step:    step,
+			Targets: make([]corelb.TrafficShiftTargetWeight, 0, len(plan.Targets)),
+		}
+		for _, target := range plan.Targets {
+			param.Targets = append(param.Targets, corelb.TrafficShiftTargetWeight{
+				ID:     target.ID,
+				Role:   target.Role,
+				IP:     target.IP,
+				Port:   target.Port,
+				Weight: target.WeightAt(step, plan.Steps),
+			})
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// CreateTrafficShift 创建目标组渐进式流量切换任务，每一步对应一个任务详情，返回任务管理ID
+func CreateTrafficShift(kt *kit.Kit, dataCli *dataservice.Client, taskCli *taskserver.Client, bkBizID int64,
+	plan *corelb.TrafficShiftPlan) (string, error) {
+
+	// 预检测
+	lockRel, err := checkResFlowRel(kt, dataCli, plan.LbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		logs.Errorf("check resource flow relation failed, err: %v, lbID: %s, lockRel: %+v, rid: %s", err,
+			plan.LbID, lockRel, kt.Rid)
+		return "", err
+	}
+
+	createReq := &task.CreateManagementReq{
+		Items: []task.CreateManagementField{{
+			BkBizID:    bkBizID,
+			Source:     enumor.TaskManagementSourceAPI,
+			Vendors:    []enumor.Vendor{plan.Vendor},
+			AccountIDs: []string{plan.AccountID},
+			Resource:   enumor.TaskManagementResClb,
+			State:      enumor.TaskManagementRunning,
+			Operations: []enumor.TaskOperation{enumor.TaskTargetGroupTrafficShift},
+			Extension: &coretask.ManagementExt{
+				RegionIDs:            []string{plan.Region},
+				TrafficShift:         plan,
+				TrafficShiftProgress: &corelb.TrafficShiftProgress{State: enumor.TrafficShiftRunning},
+			},
+		}},
+	}
+	mgmtResult, err := dataCli.Global.TaskManagement.Create(kt, createReq)
+	if err != nil {
+		logs.Errorf("create traffic shift task management failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+	if len(mgmtResult.IDs) == 0 {
+		return "", fmt.Errorf("create task management failed")
+	}
+	mgmtID := mgmtResult.IDs[0]
+
+	detailReq := &task.CreateDetailReq{}
+	for _, param := range BuildTrafficShiftStepParams(plan) {
+		detailReq.Items = append(detailReq.Items, task.CreateDetailField{
+			BkBizID:          bkBizID,
+			TaskManagementID: mgmtID,
+			Operation:        enumor.TaskTargetGroupTrafficShift,
+			State:            enumor.TaskDetailInit,
+			Param:            param,
+		})
+	}
+	detailResult, err := dataCli.Global.TaskDetail.Create(kt, detailReq)
+	if err != nil {
+		logs.Errorf("create traffic shift task details failed, err: %v, mgmtID: %s, rid: %s", err, mgmtID, kt.Rid)
+		return "", err
+	}
+	if len(detailResult.IDs) != plan.Steps {
+		return "", fmt.Errorf("create task details failed, expect created[%d] task details, but got [%d]",
+			plan.Steps, len(detailResult.IDs))
+	}
+
+	// 变更前保存负载均衡配置快照，用于回滚
+	snapshotID, err := SnapshotLoadBalancer(kt, dataCli, plan.LbID, enumor.LbSnapshotTrafficShift)
+	if err != nil {
+		logs.Errorf("snapshot load balancer failed, lbID: %s, err: %v, rid: %s", plan.LbID, err, kt.Rid)
+		_ = updateTaskDetailState(kt, dataCli, enumor.TaskDetailFailed, detailResult.IDs, err.Error())
+		return "", err
+	}
+
+	flowID, err := startTrafficShiftFlow(kt, dataCli, taskCli, mgmtID, plan, detailResult.IDs, 1)
+	if err != nil {
+		_ = updateTaskDetailState(kt, dataCli, enumor.TaskDetailFailed, detailResult.IDs, err.Error())
+		return "", err
+	}
+	BindSnapshotFlow(kt, dataCli, snapshotID, flowID)
+
+	if err = updateTaskManagement(kt, dataCli, mgmtID, []string{flowID}); err != nil {
+		return "", err
+	}
+	return mgmtID, nil
+}
+
+// ControlTrafficShift 暂停、恢复、提前完成或中止流量切换。切换进行中时由异步任务在下一次检查时处理控制指令；
+// 已暂停时异步任务已经结束，恢复、提前完成和中止需要从下一步重新创建异步任务
+func ControlTrafficShift(kt *kit.Kit, dataCli *dataservice.Client, taskCli *taskserver.Client,
+	mgmt *coretask.Management, control enumor.TrafficShiftControl) error {
+
+	if mgmt.Extension == nil || mgmt.Extension.TrafficShift == nil {
+		return errf.Newf(errf.InvalidParameter, "task management %s is not a traffic shift task", mgmt.ID)
+	}
+	if mgmt.State != enumor.TaskManagementRunning {
+		return errf.Newf(errf.InvalidParameter, "task management %s is %s", mgmt.ID, mgmt.State)
+	}
+	ext := mgmt.Extension
+	progress := ext.TrafficShiftProgress
+	if progress == nil {
+		progress = &corelb.TrafficShiftProgress{State: enumor.TrafficShiftRunning}
+	}
+	if progress.State.IsFinished() {
+		return errf.Newf(errf.InvalidParameter, "traffic shift %s is already %s", mgmt.ID, progress.State)
+	}
+
+	paused := progress.State == enumor.TrafficShiftPaused
+	switch control {
+	case enumor.TrafficShiftControlPause:
+		if paused || ext.TrafficShiftControl == enumor.TrafficShiftControlPause {
+			return errf.Newf(errf.InvalidParameter, "traffic shift %s is already paused", mgmt.ID)
+		}
+	case enumor.TrafficShiftControlResume:
+		if !paused && ext.TrafficShiftControl != enumor.TrafficShiftControlPause {
+			return errf.Newf(errf.InvalidParameter, "traffic shift %s is not paused", mgmt.ID)
+		}
+	}
+
+	updateExt := &coretask.ManagementExt{RegionIDs: ext.RegionIDs, TrafficShiftControl: control}
+	if paused && control != enumor.TrafficShiftControlPause {
+		// 暂停后异步任务已结束，不会与此处并发更新进度
+		updateExt.TrafficShiftProgress = &corelb.TrafficShiftProgress{
+			Step:  progress.Step,
+			State: enumor.TrafficShiftRunning,
+		}
+	}
+	updateReq := &task.UpdateManagementReq{
+		Items: []task.UpdateTaskManagementField{{ID: mgmt.ID, Extension: updateExt}},
+	}
+	if err := dataCli.Global.TaskManagement.Update(kt, updateReq); err != nil {
+		logs.Errorf("update traffic shift control failed, err: %v, id: %s, control: %s, rid: %s", err, mgmt.ID,
+			control, kt.Rid)
+		return err
+	}
+	if !paused || control == enumor.TrafficShiftControlPause {
+		return nil
+	}
+
+	return resumeTrafficShift(kt, dataCli, taskCli, mgmt, progress.Step+1)
+}
+
+// resumeTrafficShift 从指定步骤开始重新创建流量切换的异步任务
+func resumeTrafficShift(kt *kit.Kit, dataCli *dataservice.Client, taskCli *taskserver.Client,
+	mgmt *coretask.Management, fromStep int) error {
+
+	plan := mgmt.Extension.TrafficShift
+	lockRel, err := checkResFlowRel(kt, dataCli, plan.LbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		logs.Errorf("check resource flow relation failed, err: %v, lbID: %s, lockRel: %+v, rid: %s", err,
+			plan.LbID, lockRel, kt.Rid)
+		return err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("task_management_id", mgmt.ID),
+		Fields: []string{"id", "param"},
+		Page:   core.NewDefaultBasePage(),
+	}
+	details, err := dataCli.Global.TaskDetail.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list traffic shift task details failed, err: %v, mgmtID: %s, rid: %s", err, mgmt.ID, kt.Rid)
+		return err
+	}
+	detailIDs := make([]string, plan.Steps)
+	for _, detail := range details.Details {
+		param := new(corelb.TrafficShiftStepParam)
+		if err = json.UnmarshalFromString(string(detail.Param), param); err != nil {
+			logs.Errorf("unmarshal traffic shift step param failed, err: %v, detail: %s, rid: %s", err, detail.ID,
+				kt.Rid)
+			return err
+		}
+		if param.Step >= 1 && param.Step <= plan.Steps {
+			detailIDs[param.Step-1] = detail.ID
+		}
+	}
+
+	flowID, err := startTrafficShiftFlow(kt, dataCli, taskCli, mgmt.ID, plan, detailIDs, fromStep)
+	if err != nil {
+		return err
+	}
+	return updateTaskManagement(kt, dataCli, mgmt.ID, append(mgmt.FlowIDs, flowID))
+}
+
+// startTrafficShiftFlow 创建从fromStep开始的流量切换Flow并锁定负载均衡，detailIDs按步骤顺序排列
+func startTrafficShiftFlow(kt *kit.Kit, dataCli *dataservice.Client, taskCli *taskserver.Client, mgmtID string,
+	plan *corelb.TrafficShiftPlan, detailIDs []string, fromStep int) (string, error) {
+
+	if fromStep > plan.Steps {
+		// 最后一步已完成但尚未标记完成时暂停，重新执行最后一步即可
+		fromStep = plan.Steps
+	}
+
+	tasks := make([]ts.CustomFlowTask, 0)
+	detailUpdates := make([]task.UpdateTaskDetailField, 0)
+	getActionID := counter.NewNumberCounterWithPrev(1, 10)
+	addTask := func(opt *actionlb.TrafficShiftOption) string {
+		cur, prev := getActionID()
+		tmpTask := ts.CustomFlowTask{
+			ActionID:   action.ActIDType(cur),
+			ActionName: enumor.ActionTargetGroupTrafficShift,
+			Params:     opt,
+			Retry:      tableasync.NewRetryWithPolicy(3, 1000, 5000),
+		}
+		if prev != "" {
+			tmpTask.DependOn = []action.ActIDType{action.ActIDType(prev)}
+		}
+		tasks = append(tasks, tmpTask)
+		return cur
+	}
+	for step := fromStep; step <= plan.Steps; step++ {
+		// 恢复后的第一步不再等待
+		if step > fromStep {
+			for remain := plan.StepIntervalSec; remain > 0; remain -= trafficShiftWaitChunkSec {
+				addTask(&actionlb.TrafficShiftOption{TaskManagementID: mgmtID, Step: step,
+					WaitSec: min(remain, trafficShiftWaitChunkSec)})
+			}
+		}
+		detailID := detailIDs[step-1]
+		actionID := addTask(&actionlb.TrafficShiftOption{TaskManagementID: mgmtID, Step: step,
+			ManagementDetailID: detailID})
+		detailUpdates = append(detailUpdates, task.UpdateTaskDetailField{ID: detailID,
+			TaskActionIDs: []string{actionID}})
+	}
+
+	addReq := &ts.AddCustomFlowReq{
+		Name:        enumor.FlowTargetGroupTrafficShift,
+		ShareData:   tableasync.NewShareData(map[string]string{"lb_id": plan.LbID}),
+		Tasks:       tasks,
+		IsInitState: true,
+	}
+	result, err := taskCli.CreateCustomFlow(kt, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create traffic shift flow failed, err: %v, lbID: %s, rid: %s", err,
+			plan.LbID, kt.Rid)
+		return "", err
+	}
+	flowID := result.ID
+
+	// 从Flow，负责监听主Flow的状态
+	flowWatchReq := &ts.AddTemplateFlowReq{
+		Name: enumor.FlowLoadBalancerOperateWatch,
+		Tasks: []ts.TemplateFlowTask{{
+			ActionID: "1",
+			Params: &actionflow.LoadBalancerOperateWatchOption{
+				FlowID:     flowID,
+				ResID:      plan.LbID,
+				ResType:    enumor.LoadBalancerCloudResType,
+				SubResIDs:  []string{plan.TargetGroupID},
+				SubResType: enumor.TargetGroupCloudResType,
+				TaskType:   enumor.TrafficShiftTaskType,
+			},
+		}},
+	}
+	if _, err = taskCli.CreateTemplateFlow(kt, flowWatchReq); err != nil {
+		logs.Errorf("call taskserver to create res flow status watch task failed, err: %v, flowID: %s, rid: %s",
+			err, flowID, kt.Rid)
+		return "", err
+	}
+
+	err = lockResFlowStatus(kt, dataCli, taskCli, plan.LbID, enumor.LoadBalancerCloudResType, flowID,
+		enumor.TrafficShiftTaskType)
+	if err != nil {
+		logs.Errorf("lock resource flow status failed, err: %v, lbID: %s, rid: %s", err, plan.LbID, kt.Rid)
+		return "", err
+	}
+
+	for i := range detailUpdates {
+		detailUpdates[i].FlowID = flowID
+	}
+	if err = dataCli.Global.TaskDetail.Update(kt, &task.UpdateDetailReq{Items: detailUpdates}); err != nil {
+		logs.Errorf("update traffic shift task details failed, err: %v, flowID: %s, rid: %s", err, flowID, kt.Rid)
+		return "", err
+	}
+	return flowID, nil
+}
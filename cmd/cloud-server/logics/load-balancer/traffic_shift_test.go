@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lblogic
+
+import (
+	"testing"
+
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficShiftTargetWeightAt(t *testing.T) {
+	oldRs := corelb.TrafficShiftTarget{Role: enumor.TrafficShiftTargetOld, FromWeight: 10, ToWeight: 0}
+	newRs := corelb.TrafficShiftTarget{Role: enumor.TrafficShiftTargetNew, FromWeight: 0, ToWeight: 50}
+
+	assert.Equal(t, int64(10), oldRs.WeightAt(0, 4))
+	assert.Equal(t, int64(0), newRs.WeightAt(-1, 4))
+	assert.Equal(t, int64(8), oldRs.WeightAt(1, 4))
+	assert.Equal(t, int64(12), newRs.WeightAt(1, 4))
+	assert.Equal(t, int64(25), newRs.WeightAt(2, 4))
+	assert.Equal(t, int64(0), oldRs.WeightAt(4, 4))
+	assert.Equal(t, int64(50), newRs.WeightAt(5, 4))
+	// 一次性切换
+	assert.Equal(t, int64(0), oldRs.WeightAt(1, 1))
+	assert.Equal(t, int64(50), newRs.WeightAt(1, 1))
+}
+
+func TestBuildTrafficShiftStepParams(t *testing.T) {
+	plan := &corelb.TrafficShiftPlan{
+		Steps: 3,
+		Targets: []corelb.TrafficShiftTarget{
+			{ID: "rs-old", Role: enumor.TrafficShiftTargetOld, IP: "10.0.0.1", Port: 80, FromWeight: 30,
+				ToWeight: 0},
+			{ID: "rs-new", Role: enumor.TrafficShiftTargetNew, IP: "10.0.0.2", Port: 80, FromWeight: 0,
+				ToWeight: 30},
+		},
+	}
+
+	params := BuildTrafficShiftStepParams(plan)
+	assert.Len(t, params, 3)
+	expected := [][2]int64{{20, 10}, {10, 20}, {0, 30}}
+	for i, param := range params {
+		assert.Equal(t, i+1, param.Step)
+		assert.Len(t, param.Targets, 2)
+		assert.Equal(t, "rs-old", param.Targets[0].ID)
+		assert.Equal(t, expected[i][0], param.Targets[0].Weight)
+		assert.Equal(t, "rs-new", param.Targets[1].ID)
+		assert.Equal(t, expected[i][1], param.Targets[1].Weight)
+	}
+}
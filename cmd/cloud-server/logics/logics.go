@@ -26,7 +26,10 @@ import (
 	"hcm/cmd/cloud-server/logics/cvm"
 	"hcm/cmd/cloud-server/logics/disk"
 	"hcm/cmd/cloud-server/logics/eip"
+	"hcm/cmd/cloud-server/logics/reachability"
 	securitygroup "hcm/cmd/cloud-server/logics/security-group"
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/thirdparty/api-gateway/cmdb"
 )
@@ -38,6 +41,8 @@ type Logics struct {
 	Cvm           cvm.Interface
 	Eip           eip.Interface
 	SecurityGroup securitygroup.Interface
+	SGCompliance  sgcompliance.Interface
+	Reachability  reachability.Interface
 	Admin         logicsadmin.Interface
 }
 
@@ -52,6 +57,8 @@ func NewLogics(c *client.ClientSet, cmdbClient cmdb.Client) *Logics {
 		Cvm:           cvm.NewCvm(c, auditLogics, eipLogics, diskLogics, cmdbClient),
 		Eip:           eip.NewEip(c, auditLogics),
 		SecurityGroup: securitygroup.NewSecurityGroup(c, auditLogics),
+		SGCompliance:  sgcompliance.NewCompliance(c, cc.CloudServer().SGCompliance),
+		Reachability:  reachability.NewReachability(c),
 		Admin:         logicsadmin.NewAdminLogic(c),
 	}
 }
@@ -0,0 +1,348 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package reachability
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// endpoint 解析后的端点，包含绑定的安全组规则及所在子网的路由表
+type endpoint struct {
+	corereach.ResolvedEndpoint
+	routeTableID string
+	sgs          []securityGroup
+	listener     *corelb.BaseListener
+}
+
+func (e *endpoint) inVpc() bool {
+	return e.CloudVpcID != ""
+}
+
+func (e *endpoint) privateIP() string {
+	if len(e.PrivateIPs) == 0 {
+		return ""
+	}
+	return e.PrivateIPs[0]
+}
+
+func (e *endpoint) publicIP() string {
+	if len(e.PublicIPs) == 0 {
+		return ""
+	}
+	return e.PublicIPs[0]
+}
+
+func (e *endpoint) sgCloudIDs() map[string]struct{} {
+	ids := make(map[string]struct{}, len(e.sgs))
+	for _, sg := range e.sgs {
+		ids[sg.CloudID] = struct{}{}
+	}
+	return ids
+}
+
+// resolveEndpoint 解析端点，bizID 不为 0 时 IP 地址只匹配该业务下的主机
+func (r *reachability) resolveEndpoint(kt *kit.Kit, bizID int64, e corereach.Endpoint) (*endpoint, error) {
+	var result *endpoint
+	var err error
+	switch e.Type {
+	case enumor.CvmEndpoint:
+		cvm, getErr := r.getCvm(kt, tools.EqualExpression("id", e.ID))
+		if getErr != nil {
+			return nil, getErr
+		}
+		if cvm == nil {
+			return nil, errf.Newf(errf.RecordNotFound, "cvm %s not found", e.ID)
+		}
+		result, err = r.resolveCvm(kt, cvm)
+	case enumor.ClbListenerEndpoint:
+		result, err = r.resolveListener(kt, e.ID)
+	case enumor.IPEndpoint:
+		result, err = r.resolveIP(kt, bizID, e)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "unsupported endpoint type: %s", e.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Type = e.Type
+	return result, nil
+}
+
+func (r *reachability) resolveCvm(kt *kit.Kit, cvm *corecvm.BaseCvm) (*endpoint, error) {
+	if cvm.Vendor != enumor.TCloud {
+		return nil, errf.Newf(errf.InvalidParameter, "reachability analysis does not support %s cvm", cvm.Vendor)
+	}
+
+	result := &endpoint{ResolvedEndpoint: corereach.ResolvedEndpoint{
+		ResType:    enumor.CvmCloudResType,
+		ResID:      cvm.ID,
+		Vendor:     cvm.Vendor,
+		PrivateIPs: cvm.PrivateIPv4Addresses,
+		PublicIPs:  cvm.PublicIPv4Addresses,
+	}}
+
+	if len(cvm.SubnetIDs) != 0 {
+		if err := r.fillSubnet(kt, result, tools.EqualExpression("id", cvm.SubnetIDs[0])); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.fillSecurityGroups(kt, result, enumor.CvmCloudResType); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *reachability) resolveListener(kt *kit.Kit, listenerID string) (*endpoint, error) {
+	listenerReq := &core.ListReq{Filter: tools.EqualExpression("id", listenerID), Page: core.NewDefaultBasePage()}
+	listeners, err := r.client.DataService().Global.LoadBalancer.ListListener(kt, listenerReq)
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, id: %s, rid: %s", err, listenerID, kt.Rid)
+		return nil, err
+	}
+	if len(listeners.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "listener %s not found", listenerID)
+	}
+	listener := listeners.Details[0]
+
+	lbReq := &core.ListReq{Filter: tools.EqualExpression("id", listener.LbID), Page: core.NewDefaultBasePage()}
+	lbs, err := r.client.DataService().Global.LoadBalancer.ListLoadBalancer(kt, lbReq)
+	if err != nil {
+		logs.Errorf("list load balancer failed, err: %v, id: %s, rid: %s", err, listener.LbID, kt.Rid)
+		return nil, err
+	}
+	if len(lbs.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "load balancer %s of listener %s not found", listener.LbID,
+			listenerID)
+	}
+	lb := lbs.Details[0]
+
+	if lb.Vendor != enumor.TCloud {
+		return nil, errf.Newf(errf.InvalidParameter, "reachability analysis does not support %s load balancer",
+			lb.Vendor)
+	}
+
+	result := &endpoint{
+		ResolvedEndpoint: corereach.ResolvedEndpoint{
+			ResType:       enumor.LoadBalancerCloudResType,
+			ResID:         lb.ID,
+			Vendor:        lb.Vendor,
+			VpcID:         lb.VpcID,
+			CloudVpcID:    lb.CloudVpcID,
+			SubnetID:      lb.SubnetID,
+			CloudSubnetID: lb.CloudSubnetID,
+			PrivateIPs:    lb.PrivateIPv4Addresses,
+			PublicIPs:     lb.PublicIPv4Addresses,
+		},
+		listener: &listener,
+	}
+
+	if lb.SubnetID != "" {
+		if err = r.fillSubnet(kt, result, tools.EqualExpression("id", lb.SubnetID)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = r.fillSecurityGroups(kt, result, enumor.LoadBalancerCloudResType); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resolveIP 解析IP地址，依次匹配已同步主机的内网IP、公网IP以及指定VPC下的子网，均未匹配时视为公网地址
+func (r *reachability) resolveIP(kt *kit.Kit, bizID int64, e corereach.Endpoint) (*endpoint, error) {
+	var vpc *corecloud.BaseVpc
+	if e.VpcID != "" {
+		vpcReq := &core.ListReq{Filter: tools.EqualExpression("id", e.VpcID), Page: core.NewDefaultBasePage()}
+		vpcs, err := r.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(), vpcReq)
+		if err != nil {
+			logs.Errorf("list vpc failed, err: %v, id: %s, rid: %s", err, e.VpcID, kt.Rid)
+			return nil, err
+		}
+		if len(vpcs.Details) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "vpc %s not found", e.VpcID)
+		}
+		vpc = &vpcs.Details[0]
+
+		if vpc.Vendor != enumor.TCloud {
+			return nil, errf.Newf(errf.InvalidParameter, "reachability analysis does not support %s vpc",
+				vpc.Vendor)
+		}
+	}
+
+	cvmRules := []*filter.AtomRule{tools.RuleEqual("vendor", enumor.TCloud)}
+	if bizID != 0 {
+		cvmRules = append(cvmRules, tools.RuleEqual("bk_biz_id", bizID))
+	}
+
+	privateRules := append([]*filter.AtomRule{tools.RuleJsonOverlaps("private_ipv4_addresses", []string{e.IP})},
+		cvmRules...)
+	if vpc != nil {
+		privateRules = append(privateRules, tools.RuleJsonOverlaps("vpc_ids", []string{vpc.ID}))
+	}
+	publicRules := append([]*filter.AtomRule{tools.RuleJsonOverlaps("public_ipv4_addresses", []string{e.IP})},
+		cvmRules...)
+
+	for _, rules := range [][]*filter.AtomRule{privateRules, publicRules} {
+		cvm, err := r.getCvm(kt, tools.ExpressionAnd(rules...))
+		if err != nil {
+			return nil, err
+		}
+		if cvm != nil {
+			return r.resolveCvm(kt, cvm)
+		}
+	}
+
+	if vpc == nil {
+		if net.ParseIP(e.IP).IsPrivate() {
+			return nil, errf.Newf(errf.InvalidParameter, "ip %s does not belong to any cvm, vpc_id is required "+
+				"for private ip", e.IP)
+		}
+
+		return &endpoint{ResolvedEndpoint: corereach.ResolvedEndpoint{PublicIPs: []string{e.IP}}}, nil
+	}
+
+	result := &endpoint{ResolvedEndpoint: corereach.ResolvedEndpoint{
+		Vendor:     vpc.Vendor,
+		VpcID:      vpc.ID,
+		CloudVpcID: vpc.CloudID,
+		PrivateIPs: []string{e.IP},
+	}}
+	if err := r.fillSubnetByIP(kt, result, e.IP); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *reachability) getCvm(kt *kit.Kit, expr *filter.Expression) (*corecvm.BaseCvm, error) {
+	listReq := &core.ListReq{Filter: expr, Page: &core.BasePage{Limit: 1}}
+	result, err := r.client.DataService().Global.Cvm.ListCvm(kt, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	return &result.Details[0], nil
+}
+
+func (r *reachability) fillSubnet(kt *kit.Kit, e *endpoint, expr *filter.Expression) error {
+	listReq := &core.ListReq{Filter: expr, Page: &core.BasePage{Limit: 1}}
+	result, err := r.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list subnet failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return nil
+	}
+
+	subnet := result.Details[0]
+	e.SubnetID = subnet.ID
+	e.CloudSubnetID = subnet.CloudID
+	e.VpcID = subnet.VpcID
+	e.CloudVpcID = subnet.CloudVpcID
+	e.routeTableID = subnet.RouteTableID
+	return nil
+}
+
+// fillSubnetByIP 查找VPC下网段包含该IP的子网
+func (r *reachability) fillSubnetByIP(kt *kit.Kit, e *endpoint, ip string) error {
+	addr := net.ParseIP(ip)
+	listReq := &core.ListReq{Filter: tools.EqualExpression("vpc_id", e.VpcID), Page: core.NewDefaultBasePage()}
+	for {
+		result, err := r.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list subnet failed, err: %v, vpc: %s, rid: %s", err, e.VpcID, kt.Rid)
+			return err
+		}
+
+		for _, subnet := range result.Details {
+			for _, cidr := range subnet.Ipv4Cidr {
+				if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(addr) {
+					e.SubnetID = subnet.ID
+					e.CloudSubnetID = subnet.CloudID
+					e.routeTableID = subnet.RouteTableID
+					return nil
+				}
+			}
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return errf.Newf(errf.InvalidParameter, "ip %s does not belong to any subnet of vpc %s", ip, e.VpcID)
+}
+
+// fillSecurityGroups 查询端点绑定的安全组及其规则，安全组按照绑定优先级排列
+func (r *reachability) fillSecurityGroups(kt *kit.Kit, e *endpoint, resType enumor.CloudResourceType) error {
+	listReq := &dataproto.SGCommonRelWithSecurityGroupListReq{ResIDs: []string{e.ResID}, ResType: resType}
+	rels, err := r.client.DataService().Global.SGCommonRel.ListWithSecurityGroup(kt, listReq)
+	if err != nil {
+		logs.Errorf("list security group of resource failed, err: %v, res: %s, rid: %s", err, e.ResID, kt.Rid)
+		return err
+	}
+
+	sorted := *rels
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	e.SecurityGroupIDs = make([]string, 0, len(sorted))
+	e.sgs = make([]securityGroup, 0, len(sorted))
+	for _, rel := range sorted {
+		rules, err := sgcompliance.ListSGRules(kt, r.client, rel.Vendor, rel.ID)
+		if err != nil {
+			return fmt.Errorf("list rules of security group %s failed, err: %v", rel.ID, err)
+		}
+
+		e.SecurityGroupIDs = append(e.SecurityGroupIDs, rel.ID)
+		e.sgs = append(e.sgs, securityGroup{ID: rel.ID, CloudID: rel.CloudID, Rules: rules})
+	}
+
+	return nil
+}
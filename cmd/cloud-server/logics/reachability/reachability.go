@@ -0,0 +1,329 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package reachability 基于已同步的VPC、子网、路由表、安全组规则及参数模版分析两个端点之间的网络可达性
+package reachability
+
+import (
+	"fmt"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	"hcm/pkg/api/core"
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	routetable "hcm/pkg/api/core/cloud/route-table"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// Interface define network reachability interface.
+type Interface interface {
+	// Analyze analyze whether the source can reach the destination with the protocol and port.
+	Analyze(kt *kit.Kit, opt *AnalyzeOption) (*corereach.Result, error)
+}
+
+// AnalyzeOption define network reachability analyze option.
+type AnalyzeOption struct {
+	// BizID 不为 0 时 IP 类型的端点只匹配该业务下的主机
+	BizID       int64
+	Source      corereach.Endpoint
+	Destination corereach.Endpoint
+	// Protocol 协议，支持 tcp、udp、icmp，目的端为负载均衡监听器时可不指定，使用监听器的协议
+	Protocol string
+	// Port 端口，目的端为负载均衡监听器时可不指定，使用监听器的端口
+	Port uint32
+}
+
+type reachability struct {
+	client *client.ClientSet
+}
+
+// NewReachability new network reachability.
+func NewReachability(client *client.ClientSet) Interface {
+	return &reachability{
+		client: client,
+	}
+}
+
+// Analyze analyze whether the source can reach the destination with the protocol and port. The traffic is checked
+// by the egress rules of the source security groups, the route of the source subnet and the ingress rules of the
+// destination security groups in turn, the first denied hop decides the result.
+func (r *reachability) Analyze(kt *kit.Kit, opt *AnalyzeOption) (*corereach.Result, error) {
+	src, err := r.resolveEndpoint(kt, opt.BizID, opt.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := r.resolveEndpoint(kt, opt.BizID, opt.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := buildTraffic(opt, dst)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	routes := make([]routetable.TCloudRoute, 0)
+	if src.routeTableID != "" {
+		if routes, err = r.listRoutes(kt, src.routeTableID); err != nil {
+			return nil, err
+		}
+	}
+
+	tpls, err := r.listTemplates(kt, src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return analyze(src, dst, t, routes, tpls), nil
+}
+
+// buildTraffic 确定待分析流量的协议端口，目的端为负载均衡监听器时使用监听器的协议端口
+func buildTraffic(opt *AnalyzeOption, dst *endpoint) (traffic, error) {
+	t := traffic{Port: opt.Port}
+	if opt.Protocol != "" {
+		t.Protocol = sgcompliance.NormalizeProtocol(opt.Protocol)
+	}
+
+	if dst.listener != nil {
+		protocol := "tcp"
+		if dst.listener.Protocol == enumor.UdpProtocol || dst.listener.Protocol == enumor.QuicProtocol {
+			protocol = "udp"
+		}
+		if t.Protocol != "" && t.Protocol != protocol {
+			return traffic{}, fmt.Errorf("protocol %s mismatches the listener protocol %s", opt.Protocol,
+				dst.listener.Protocol)
+		}
+		if t.Port != 0 && int64(t.Port) != dst.listener.Port {
+			return traffic{}, fmt.Errorf("port %d mismatches the listener port %d", t.Port, dst.listener.Port)
+		}
+		t.Protocol, t.Port = protocol, uint32(dst.listener.Port)
+	}
+
+	switch t.Protocol {
+	case "tcp", "udp":
+		if t.Port == 0 || t.Port > 65535 {
+			return traffic{}, fmt.Errorf("port must be in range [1, 65535] for protocol %s", t.Protocol)
+		}
+	case "icmp":
+		t.Port = 0
+	default:
+		return traffic{}, fmt.Errorf("protocol %s is not supported, should be one of tcp, udp, icmp", opt.Protocol)
+	}
+
+	return t, nil
+}
+
+// path 源端到目的端的网络路径
+type path struct {
+	hop corereach.Hop
+	// srcAddr 目的端看到的源地址，经过NAT网关时为 anyIPv4
+	srcAddr string
+	dstAddr string
+	// private 是否通过内网访问，内网访问时引用安全组的规则按照对端绑定的安全组匹配
+	private bool
+}
+
+// analyze 依次检查源端安全组出站规则、源端子网路由、目的端安全组入站规则
+func analyze(src, dst *endpoint, t traffic, routes []routetable.TCloudRoute, tpls templates) *corereach.Result {
+	result := &corereach.Result{
+		Protocol:    t.Protocol,
+		Port:        t.Port,
+		Source:      src.ResolvedEndpoint,
+		Destination: dst.ResolvedEndpoint,
+		Hops:        make([]corereach.Hop, 0),
+	}
+
+	p := resolvePath(src, dst, routes)
+
+	srcPeer := peer{IP: p.dstAddr}
+	dstPeer := peer{IP: p.srcAddr}
+	if p.private {
+		srcPeer.SecurityGroups = dst.sgCloudIDs()
+		dstPeer.SecurityGroups = src.sgCloudIDs()
+	}
+
+	hops := []func() corereach.Hop{
+		func() corereach.Hop {
+			return evaluateSecurityGroups(enumor.SourceSGHop, enumor.Egress, src.sgs, t, srcPeer, tpls)
+		},
+		func() corereach.Hop {
+			return p.hop
+		},
+		func() corereach.Hop {
+			return evaluateSecurityGroups(enumor.DestinationSGHop, enumor.Ingress, dst.sgs, t, dstPeer, tpls)
+		},
+	}
+
+	for _, evaluate := range hops {
+		hop := evaluate()
+		result.Hops = append(result.Hops, hop)
+		if !hop.Allowed {
+			result.DecidedBy = &hop
+			return result
+		}
+	}
+
+	last := result.Hops[len(result.Hops)-1]
+	result.Allowed = true
+	result.DecidedBy = &last
+	return result
+}
+
+// resolvePath 确定源端访问目的端使用的地址及命中的路由，同VPC内网访问使用 local 路由，跨VPC及访问公网按照源端子网
+// 路由表最长前缀匹配，未命中路由时源端有公网IP可直接访问目的端公网IP
+func resolvePath(src, dst *endpoint, routes []routetable.TCloudRoute) path {
+	hop := corereach.Hop{Type: enumor.RouteHop}
+
+	if !src.inVpc() {
+		dstPublic := dst.publicIP()
+		if dstPublic == "" {
+			hop.Message = "destination has no public ip, not reachable from public network"
+			return path{hop: hop}
+		}
+
+		hop.Allowed = true
+		hop.Address = dstPublic
+		hop.Message = "access the public ip of destination from public network"
+		return path{hop: hop, srcAddr: src.publicIP(), dstAddr: dstPublic}
+	}
+
+	hop.ResType = enumor.RouteTableCloudResType
+	hop.ResID = src.routeTableID
+
+	if dst.inVpc() && dst.CloudVpcID == src.CloudVpcID && dst.privateIP() != "" {
+		hop.Allowed = true
+		hop.Address = dst.privateIP()
+		hop.Message = fmt.Sprintf("routed by the local route of vpc %s", src.CloudVpcID)
+		return path{hop: hop, srcAddr: src.privateIP(), dstAddr: dst.privateIP(), private: true}
+	}
+
+	if dst.inVpc() && dst.privateIP() != "" {
+		if route := lookupRoute(routes, dst.privateIP()); route != nil {
+			fillRouteHop(&hop, route, dst.privateIP())
+			return path{hop: hop, srcAddr: src.privateIP(), dstAddr: dst.privateIP(), private: true}
+		}
+	}
+
+	dstPublic := dst.publicIP()
+	if dstPublic == "" {
+		hop.Address = dst.privateIP()
+		hop.Message = "no route matches the private ip of destination and destination has no public ip"
+		return path{hop: hop}
+	}
+
+	if route := lookupRoute(routes, dstPublic); route != nil {
+		fillRouteHop(&hop, route, dstPublic)
+		srcAddr := src.publicIP()
+		if route.GatewayType == gatewayTypeNat || srcAddr == "" {
+			srcAddr = anyIPv4
+		}
+		return path{hop: hop, srcAddr: srcAddr, dstAddr: dstPublic}
+	}
+
+	hop.Address = dstPublic
+	if src.publicIP() == "" {
+		hop.Message = "no route matches the public ip of destination and source has no public ip"
+		return path{hop: hop}
+	}
+
+	hop.Allowed = true
+	hop.Message = fmt.Sprintf("access public network through the public ip %s of source", src.publicIP())
+	return path{hop: hop, srcAddr: src.publicIP(), dstAddr: dstPublic}
+}
+
+func fillRouteHop(hop *corereach.Hop, route *routetable.TCloudRoute, address string) {
+	hop.Allowed = true
+	hop.RuleID = route.ID
+	hop.Address = address
+	hop.Message = fmt.Sprintf("routed by route %s (%s) to %s %s", route.ID, route.DestinationCidrBlock,
+		route.GatewayType, route.CloudGatewayID)
+}
+
+func (r *reachability) listRoutes(kt *kit.Kit, routeTableID string) ([]routetable.TCloudRoute, error) {
+	listReq := &core.ListReq{Filter: tools.AllExpression(), Page: core.NewDefaultBasePage()}
+	routes := make([]routetable.TCloudRoute, 0)
+	for {
+		result, err := r.client.DataService().TCloud.RouteTable.ListRoute(kt.Ctx, kt.Header(), routeTableID,
+			listReq)
+		if err != nil {
+			logs.Errorf("list tcloud route failed, err: %v, route table: %s, rid: %s", err, routeTableID, kt.Rid)
+			return nil, err
+		}
+
+		routes = append(routes, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return routes, nil
+}
+
+// listTemplates 查询端点安全组规则引用的参数模版，模版组引用的模版一并查询
+func (r *reachability) listTemplates(kt *kit.Kit, endpoints ...*endpoint) (templates, error) {
+	cloudIDs := make([]string, 0)
+	for _, e := range endpoints {
+		for _, sg := range e.sgs {
+			for _, rule := range sg.Rules {
+				cloudIDs = append(cloudIDs, rule.TemplateIDs...)
+			}
+		}
+	}
+
+	tpls := make(templates)
+	// 模版组只能包含模版，最多查询两层
+	for depth := 0; depth < 2 && len(cloudIDs) != 0; depth++ {
+		members := make([]string, 0)
+		for _, ids := range slice.Split(slice.Unique(cloudIDs), int(core.DefaultMaxPageLimit)) {
+			listReq := &core.ListReq{
+				Filter: tools.ExpressionAnd(tools.RuleEqual("vendor", enumor.TCloud),
+					tools.RuleIn("cloud_id", ids)),
+				Page: core.NewDefaultBasePage(),
+			}
+			result, err := r.client.DataService().Global.ArgsTpl.ListArgsTpl(kt, listReq)
+			if err != nil {
+				logs.Errorf("list argument template failed, err: %v, cloud_ids: %v, rid: %s", err, ids, kt.Rid)
+				return nil, err
+			}
+
+			for _, one := range result.Details {
+				tpls[one.CloudID] = one
+				if one.GroupTemplates == nil {
+					continue
+				}
+				for _, member := range *one.GroupTemplates {
+					if _, exists := tpls[member]; !exists {
+						members = append(members, member)
+					}
+				}
+			}
+		}
+		cloudIDs = members
+	}
+
+	return tpls, nil
+}
@@ -0,0 +1,164 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package reachability
+
+import (
+	"testing"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	corecloud "hcm/pkg/api/core/cloud"
+	coreargstpl "hcm/pkg/api/core/cloud/argument-template"
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	routetable "hcm/pkg/api/core/cloud/route-table"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRule(t *testing.T, id string, ruleType enumor.SecurityGroupRuleType, index int64, protocol, port, cidr,
+	action string) sgcompliance.Rule {
+
+	rule, err := sgcompliance.FromTCloudRule(corecloud.TCloudSecurityGroupRule{
+		ID:               id,
+		CloudPolicyIndex: index,
+		Protocol:         converter.ValToPtr(protocol),
+		Port:             converter.ValToPtr(port),
+		IPv4Cidr:         converter.ValToPtr(cidr),
+		Action:           action,
+		Type:             ruleType,
+	})
+	assert.NoError(t, err)
+	return rule
+}
+
+func newEndpoint(vpc, privateIP, publicIP string, sgs ...securityGroup) *endpoint {
+	e := &endpoint{
+		ResolvedEndpoint: corereach.ResolvedEndpoint{CloudVpcID: vpc},
+		routeTableID:     "rt-1",
+		sgs:              sgs,
+	}
+	if privateIP != "" {
+		e.PrivateIPs = []string{privateIP}
+	}
+	if publicIP != "" {
+		e.PublicIPs = []string{publicIP}
+	}
+	return e
+}
+
+func TestAnalyze_SameVpc(t *testing.T) {
+	egressAll := newRule(t, "e1", enumor.Egress, 0, "ALL", "ALL", "0.0.0.0/0", "ACCEPT")
+	src := newEndpoint("vpc-1", "10.0.0.1", "", securityGroup{ID: "sg1", CloudID: "sg-src",
+		Rules: []sgcompliance.Rule{egressAll}})
+
+	dstSG := securityGroup{ID: "sg2", CloudID: "sg-dst", Rules: []sgcompliance.Rule{
+		newRule(t, "i1", enumor.Ingress, 1, "tcp", "443", "10.0.0.0/16", "ACCEPT"),
+		newRule(t, "i0", enumor.Ingress, 0, "tcp", "443", "10.0.0.1", "DROP"),
+	}}
+	dst := newEndpoint("vpc-1", "10.0.1.1", "", dstSG)
+
+	// 优先级更高的拒绝规则先命中
+	result := analyze(src, dst, traffic{Protocol: "tcp", Port: 443}, nil, templates{})
+	assert.False(t, result.Allowed)
+	assert.Len(t, result.Hops, 3)
+	assert.Equal(t, enumor.DestinationSGHop, result.DecidedBy.Type)
+	assert.Equal(t, "i0", result.DecidedBy.RuleID)
+
+	// 端口未放通时默认拒绝
+	dst.sgs[0].Rules = dst.sgs[0].Rules[:1]
+	result = analyze(src, dst, traffic{Protocol: "tcp", Port: 80}, nil, templates{})
+	assert.False(t, result.Allowed)
+	assert.Empty(t, result.DecidedBy.RuleID)
+
+	result = analyze(src, dst, traffic{Protocol: "tcp", Port: 443}, nil, templates{})
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "i1", result.DecidedBy.RuleID)
+	assert.Contains(t, result.Hops[1].Message, "local route")
+}
+
+func TestAnalyze_SecurityGroupReference(t *testing.T) {
+	egressAll := newRule(t, "e1", enumor.Egress, 0, "ALL", "ALL", "0.0.0.0/0", "ACCEPT")
+	src := newEndpoint("vpc-1", "10.0.0.1", "1.1.1.1", securityGroup{ID: "sg1", CloudID: "sg-src",
+		Rules: []sgcompliance.Rule{egressAll}})
+
+	ref := sgcompliance.Rule{ID: "i1", Type: enumor.Ingress, Allow: true, Protocol: "all", AddressRef: "sg-src"}
+	dst := newEndpoint("vpc-1", "10.0.1.1", "2.2.2.2", securityGroup{ID: "sg2", CloudID: "sg-dst",
+		Rules: []sgcompliance.Rule{ref}})
+
+	result := analyze(src, dst, traffic{Protocol: "udp", Port: 53}, nil, templates{})
+	assert.True(t, result.Allowed)
+
+	// 跨VPC通过公网访问时引用安全组的规则不生效
+	dst.CloudVpcID = "vpc-2"
+	result = analyze(src, dst, traffic{Protocol: "udp", Port: 53}, nil, templates{})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, enumor.DestinationSGHop, result.DecidedBy.Type)
+	assert.Equal(t, "1.1.1.1", result.DecidedBy.Address)
+}
+
+func TestAnalyze_Route(t *testing.T) {
+	src := newEndpoint("vpc-1", "10.0.0.1", "")
+	dst := newEndpoint("vpc-2", "172.16.0.1", "")
+
+	result := analyze(src, dst, traffic{Protocol: "icmp"}, nil, templates{})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, enumor.RouteHop, result.DecidedBy.Type)
+
+	routes := []routetable.TCloudRoute{
+		{ID: "r1", DestinationCidrBlock: "172.16.0.0/12", GatewayType: "CCN", Enabled: true},
+		{ID: "r2", DestinationCidrBlock: "172.16.0.0/24", GatewayType: "PEERCONNECTION", Enabled: true},
+		{ID: "r3", DestinationCidrBlock: "172.16.0.0/28", GatewayType: "VPN", Enabled: false},
+	}
+	result = analyze(src, dst, traffic{Protocol: "icmp"}, routes, templates{})
+	assert.True(t, result.Allowed)
+	assert.Equal(t, "r2", result.Hops[1].RuleID)
+
+	// 经过NAT网关访问公网时源地址未知，只有放通全部地址的规则可以匹配
+	dst = newEndpoint("", "", "8.8.8.8", securityGroup{ID: "sg2", CloudID: "sg-dst", Rules: []sgcompliance.Rule{
+		newRule(t, "i1", enumor.Ingress, 0, "tcp", "80", "1.1.1.1", "ACCEPT"),
+	}})
+	routes = append(routes, routetable.TCloudRoute{ID: "r4", DestinationCidrBlock: "0.0.0.0/0",
+		GatewayType: gatewayTypeNat, Enabled: true})
+	result = analyze(src, dst, traffic{Protocol: "tcp", Port: 80}, routes, templates{})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, "r4", result.Hops[1].RuleID)
+	assert.Equal(t, anyIPv4, result.DecidedBy.Address)
+}
+
+func TestRuleMatches_Templates(t *testing.T) {
+	tpls := templates{
+		"ppm-1": {CloudID: "ppm-1", Type: enumor.ServiceType, Templates: &[]coreargstpl.TemplateInfo{
+			{Address: converter.ValToPtr("tcp:80,443")}, {Address: converter.ValToPtr("udp:5000-5100")}}},
+		"ppmg-1": {CloudID: "ppmg-1", Type: enumor.ServiceGroupType, GroupTemplates: &[]string{"ppm-1"}},
+		"ipm-1": {CloudID: "ipm-1", Type: enumor.AddressType, Templates: &[]coreargstpl.TemplateInfo{
+			{Address: converter.ValToPtr("10.0.0.0/24")}}},
+		"ipmg-1": {CloudID: "ipmg-1", Type: enumor.AddressGroupType, GroupTemplates: &[]string{"ipm-1"}},
+	}
+
+	rule := &sgcompliance.Rule{Type: enumor.Ingress, Allow: true, PortRef: "ppmg-1", AddressRef: "ipmg-1"}
+	assert.True(t, ruleMatches(rule, traffic{Protocol: "udp", Port: 5050}, peer{IP: "10.0.0.8"}, tpls))
+	assert.False(t, ruleMatches(rule, traffic{Protocol: "tcp", Port: 5050}, peer{IP: "10.0.0.8"}, tpls))
+	assert.False(t, ruleMatches(rule, traffic{Protocol: "tcp", Port: 443}, peer{IP: "10.0.1.8"}, tpls))
+
+	// 引用的模版不存在时不匹配
+	rule.PortRef = "ppm-2"
+	assert.False(t, ruleMatches(rule, traffic{Protocol: "tcp", Port: 443}, peer{IP: "10.0.0.8"}, tpls))
+}
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package reachability
+
+import (
+	"net"
+
+	routetable "hcm/pkg/api/core/cloud/route-table"
+)
+
+const (
+	// gatewayTypeNat 腾讯云NAT网关路由的下一跳类型
+	gatewayTypeNat = "NAT"
+)
+
+// lookupRoute 在启用的路由中按照最长前缀匹配查找目的地址命中的路由，未命中时返回 nil
+func lookupRoute(routes []routetable.TCloudRoute, ip string) *routetable.TCloudRoute {
+	dst := net.ParseIP(ip)
+	if dst == nil {
+		return nil
+	}
+
+	var matched *routetable.TCloudRoute
+	matchedOnes := -1
+	for idx := range routes {
+		route := &routes[idx]
+		if !route.Enabled {
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(route.DestinationCidrBlock)
+		if err != nil || !ipNet.Contains(dst) {
+			continue
+		}
+
+		if ones, _ := ipNet.Mask.Size(); ones > matchedOnes {
+			matched = route
+			matchedOnes = ones
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package reachability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	coreargstpl "hcm/pkg/api/core/cloud/argument-template"
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+// anyIPv4 对端地址未知时使用的地址，只有放通全部地址的规则可以匹配
+const anyIPv4 = "0.0.0.0/0"
+
+// securityGroup 端点绑定的安全组及其规则
+type securityGroup struct {
+	ID      string
+	CloudID string
+	Rules   []sgcompliance.Rule
+}
+
+// traffic 待分析的流量
+type traffic struct {
+	Protocol string
+	Port     uint32
+}
+
+// peer 安全组规则匹配的对端
+type peer struct {
+	IP string
+	// SecurityGroups 对端绑定的安全组云ID，仅内网访问时用于匹配引用安全组的规则
+	SecurityGroups map[string]struct{}
+}
+
+// templates 规则引用的参数模版，key 为参数模版云ID
+type templates map[string]coreargstpl.BaseArgsTpl
+
+// addresses 解析地址模版及地址组模版中的地址
+func (t templates) addresses(cloudID string) []string {
+	tpl, exists := t[cloudID]
+	if !exists {
+		return nil
+	}
+
+	result := make([]string, 0)
+	switch tpl.Type {
+	case enumor.AddressType:
+		for _, one := range converter.PtrToVal(tpl.Templates) {
+			if address := converter.PtrToVal(one.Address); address != "" {
+				result = append(result, address)
+			}
+		}
+	case enumor.AddressGroupType:
+		for _, member := range converter.PtrToVal(tpl.GroupTemplates) {
+			if member != cloudID {
+				result = append(result, t.addresses(member)...)
+			}
+		}
+	}
+
+	return result
+}
+
+// services 解析协议端口模版及协议端口组模版，返回仅包含协议端口的规则
+func (t templates) services(cloudID string) []sgcompliance.Rule {
+	tpl, exists := t[cloudID]
+	if !exists {
+		return nil
+	}
+
+	result := make([]sgcompliance.Rule, 0)
+	switch tpl.Type {
+	case enumor.ServiceType:
+		for _, one := range converter.PtrToVal(tpl.Templates) {
+			rule, err := parseService(converter.PtrToVal(one.Address))
+			if err != nil {
+				continue
+			}
+			result = append(result, rule)
+		}
+	case enumor.ServiceGroupType:
+		for _, member := range converter.PtrToVal(tpl.GroupTemplates) {
+			if member != cloudID {
+				result = append(result, t.services(member)...)
+			}
+		}
+	}
+
+	return result
+}
+
+// parseService 解析协议端口模版中的协议端口，支持 tcp:80、udp:53,123、tcp:3306-3310、icmp、ALL 格式
+func parseService(service string) (sgcompliance.Rule, error) {
+	protocol, ports, _ := strings.Cut(strings.TrimSpace(service), ":")
+	if protocol == "" {
+		return sgcompliance.Rule{}, fmt.Errorf("invalid service: %s", service)
+	}
+
+	portRanges, err := sgcompliance.ParsePorts(ports, ",")
+	if err != nil {
+		return sgcompliance.Rule{}, err
+	}
+
+	return sgcompliance.Rule{Protocol: sgcompliance.NormalizeProtocol(protocol), Ports: portRanges}, nil
+}
+
+// evaluateSecurityGroups 按照安全组优先级及规则优先级依次匹配规则，命中的第一条规则决定是否放通，
+// 绑定了安全组但没有命中任何规则时默认拒绝
+func evaluateSecurityGroups(hopType enumor.ReachabilityHopType, ruleType enumor.SecurityGroupRuleType,
+	sgs []securityGroup, t traffic, p peer, tpls templates) corereach.Hop {
+
+	hop := corereach.Hop{Type: hopType, Address: p.IP}
+	if len(sgs) == 0 {
+		hop.Allowed = true
+		hop.Message = "no security group is bound, skipped"
+		return hop
+	}
+
+	for _, sg := range sgs {
+		rules := make([]sgcompliance.Rule, 0, len(sg.Rules))
+		for _, rule := range sg.Rules {
+			if rule.Type == ruleType {
+				rules = append(rules, rule)
+			}
+		}
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].Priority < rules[j].Priority
+		})
+
+		for idx := range rules {
+			if !ruleMatches(&rules[idx], t, p, tpls) {
+				continue
+			}
+
+			hop.Allowed = rules[idx].Allow
+			hop.ResType = enumor.SecurityGroupCloudResType
+			hop.ResID = sg.ID
+			hop.RuleID = rules[idx].ID
+			action := "denied"
+			if hop.Allowed {
+				action = "allowed"
+			}
+			hop.Message = fmt.Sprintf("%s by %s rule %s of security group %s", action, ruleType, rules[idx].ID,
+				sg.CloudID)
+			return hop
+		}
+	}
+
+	hop.Allowed = false
+	hop.ResType = enumor.SecurityGroupCloudResType
+	hop.ResID = sgs[len(sgs)-1].ID
+	hop.Message = fmt.Sprintf("no %s rule of the bound security groups matches, denied by default", ruleType)
+	return hop
+}
+
+// ruleMatches 规则是否匹配流量及对端，引用的参数模版、安全组按照已同步的数据进行解析
+func ruleMatches(rule *sgcompliance.Rule, t traffic, p peer, tpls templates) bool {
+	if rule.PortRef != "" {
+		matched := false
+		for _, service := range tpls.services(rule.PortRef) {
+			if service.MatchProtocolPort(t.Protocol, t.Port) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	} else if !rule.MatchProtocolPort(t.Protocol, t.Port) {
+		return false
+	}
+
+	if rule.AddressRef == "" {
+		return rule.MatchAddress(p.IP)
+	}
+
+	if strings.HasPrefix(rule.AddressRef, "sg-") {
+		_, exists := p.SecurityGroups[rule.AddressRef]
+		return exists
+	}
+
+	resolved := sgcompliance.Rule{Addresses: tpls.addresses(rule.AddressRef)}
+	return resolved.MatchAddress(p.IP)
+}
@@ -20,13 +20,20 @@
 package logicsrecycle
 
 import (
+	"fmt"
+
+	"hcm/pkg/api/core"
 	corerr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/api/data-service/recycle-record"
 	"hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/slice"
 )
 
@@ -63,3 +70,60 @@ func MarkRecordSuccess(kt *kit.Kit, ds *dataservice.Client, recordIDs []string)
 			constant.RecycleUpdateRecordFailed, recordIDs, err, kt.Rid)
 	}
 }
+
+// ValidateRecoverRecords 校验待恢复的回收记录，只能处理指定资源类型的、等待回收的、非关联回收的记录
+func ValidateRecoverRecords(records []corerr.RecycleRecord, resType enumor.CloudResourceType) error {
+	for _, one := range records {
+		if one.Status != enumor.WaitingRecycleRecordStatus {
+			return fmt.Errorf("record: %s not is wait_recycle status", one.ID)
+		}
+
+		if one.ResType != resType {
+			return fmt.Errorf("record: %s not is %s recycle record", one.ID, resType)
+		}
+
+		if one.RecycleType == enumor.RecycleTypeRelated {
+			return fmt.Errorf("related recycled %s(%s) can not be operated", resType, one.ResID)
+		}
+	}
+
+	return nil
+}
+
+// CheckResNotRecycling 校验资源均不处于回收状态
+func CheckResNotRecycling(basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	for _, info := range basicInfoMap {
+		if info.RecycleStatus == enumor.RecycleStatus {
+			return errf.Newf(errf.InvalidParameter, "%s(%s) is already in recycle bin", info.ResType, info.ID)
+		}
+	}
+
+	return nil
+}
+
+// ListAllRecyclePolicy 获取符合条件的全部回收站保留策略
+func ListAllRecyclePolicy(kt *kit.Kit, ds *dataservice.Client, expr *filter.Expression) ([]corerr.Policy, error) {
+	policies := make([]corerr.Policy, 0)
+	lastID := ""
+	for {
+		listFilter, err := tools.And(expr, tools.RuleIDGreaterThan(lastID))
+		if err != nil {
+			return nil, err
+		}
+		listReq := &core.ListReq{
+			Filter: listFilter,
+			Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		}
+		result, err := ds.Global.RecycleRecord.ListRecyclePolicy(kt, listReq)
+		if err != nil {
+			logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		policies = append(policies, result.Details...)
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			return policies, nil
+		}
+		lastID = result.Details[len(result.Details)-1].ID
+	}
+}
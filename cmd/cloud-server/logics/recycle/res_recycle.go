@@ -0,0 +1,212 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"hcm/cmd/cloud-server/logics/audit"
+	csrecycle "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	corerr "hcm/pkg/api/core/recycle-record"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/api/data-service/cloud"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ResRecycleOption 资源放入回收站、从回收站恢复的通用参数，适用于没有回收选项的资源，如eip、负载均衡、安全组
+type ResRecycleOption struct {
+	Client       *client.ClientSet
+	Authorizer   auth.Authorizer
+	Audit        audit.Interface
+	ResType      enumor.CloudResourceType
+	AuditResType enumor.AuditResourceType
+	AuthResType  meta.ResourceType
+	ValidHandler handler.ValidWithAuthHandler
+	// PreCheck 资源放入回收站前的检查，如是否存在绑定关系、是否开启删除保护等，为空表示不检查
+	PreCheck func(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error
+}
+
+// RecycleRes 将资源放入回收站，资源在回收站中保留至回收策略指定的时间后由定时任务销毁
+func RecycleRes(cts *rest.Contexts, opt *ResRecycleOption) (interface{}, error) {
+	req := new(csrecycle.ResRecycleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return RecycleResByIDs(cts, opt, req.IDs)
+}
+
+// RecycleResByIDs 将指定ID的资源放入回收站，调用方需要保证ID数量不超过批量操作的上限
+func RecycleResByIDs(cts *rest.Contexts, opt *ResRecycleOption, ids []string) (*csrecycle.RecycleResult, error) {
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: opt.ResType,
+		IDs:          ids,
+		Fields:       append(types.CommonFieldsWithRegion, "recycle_status"),
+	}
+	basicInfoMap, err := opt.Client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(basicInfoMap) != len(ids) {
+		return nil, errf.Newf(errf.RecordNotFound, "some %s can not be found", opt.ResType)
+	}
+
+	// validate biz and authorize
+	err = opt.ValidHandler(cts, &handler.ValidWithAuthOption{Authorizer: opt.Authorizer, ResType: opt.AuthResType,
+		Action: meta.Recycle, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = CheckResNotRecycling(basicInfoMap); err != nil {
+		return nil, err
+	}
+
+	if opt.PreCheck != nil {
+		if err = opt.PreCheck(cts.Kit, basicInfoMap); err != nil {
+			return nil, err
+		}
+	}
+
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(ids))
+	recycleInfos := make([]dsrr.RecycleReq, 0, len(ids))
+	for _, id := range ids {
+		detail := &corerr.BaseRecycleDetail{}
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: id, Data: detail})
+		recycleInfos = append(recycleInfos, dsrr.RecycleReq{ID: id, Detail: detail})
+	}
+
+	// create recycle audit
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: opt.AuditResType,
+		Action:  protoaudit.Recycle,
+		Infos:   auditInfos,
+	}
+	if err = opt.Audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create recycle audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	recycleReq := &dsrr.BatchRecycleReq{
+		ResType:            opt.ResType,
+		DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
+		Infos:              recycleInfos,
+	}
+	taskID, err := opt.Client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(cts.Kit, recycleReq)
+	if err != nil {
+		logs.Errorf("recycle %s failed, err: %v, ids: %v, rid: %s", opt.ResType, err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &csrecycle.RecycleResult{TaskID: taskID}, nil
+}
+
+// RecoverRes 从回收站中恢复资源
+func RecoverRes(cts *rest.Contexts, opt *ResRecycleOption) (interface{}, error) {
+	req := new(csrecycle.ResRecoverReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.RecordIDs),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	records, err := opt.Client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records.Details) != len(req.RecordIDs) {
+		return nil, errf.New(errf.InvalidParameter, "some record_ids are not in recycle bin")
+	}
+
+	if err = ValidateRecoverRecords(records.Details, opt.ResType); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resIDs := make([]string, 0, len(records.Details))
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(records.Details))
+	for _, record := range records.Details {
+		resIDs = append(resIDs, record.ResID)
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: record.ResID, Data: record.Detail})
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: opt.ResType,
+		IDs:          resIDs,
+		Fields:       append(types.CommonBasicInfoFields, "recycle_status"),
+	}
+	basicInfoMap, err := opt.Client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = opt.ValidHandler(cts, &handler.ValidWithAuthOption{Authorizer: opt.Authorizer, ResType: opt.AuthResType,
+		Action: meta.Recover, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	// create recover audit
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: opt.AuditResType,
+		Action:  protoaudit.Recover,
+		Infos:   auditInfos,
+	}
+	if err = opt.Audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create recover audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	recoverReq := &dsrr.BatchRecoverReq{
+		ResType:   opt.ResType,
+		RecordIDs: req.RecordIDs,
+	}
+	if err = opt.Client.DataService().Global.RecycleRecord.BatchRecoverCloudResource(cts.Kit, recoverReq); err != nil {
+		logs.Errorf("recover %s failed, err: %v, record ids: %v, rid: %s", opt.ResType, err, req.RecordIDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
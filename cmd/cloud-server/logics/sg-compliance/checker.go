@@ -0,0 +1,274 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgcompliance
+
+import (
+	"fmt"
+
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/slice"
+)
+
+// BuiltinCheckSeverity 内置检查项的风险等级
+var BuiltinCheckSeverity = map[enumor.SGComplianceBuiltinCheck]enumor.SGComplianceSeverity{
+	enumor.SGCheckPublicSensitivePort: enumor.SGComplianceHigh,
+	enumor.SGCheckWidePortRange:       enumor.SGComplianceMedium,
+	enumor.SGCheckDuplicateRule:       enumor.SGComplianceLow,
+	enumor.SGCheckShadowedRule:        enumor.SGComplianceLow,
+	enumor.SGCheckInvalidArgsTpl:      enumor.SGComplianceMedium,
+}
+
+// Option defines the option of evaluating security group rules.
+type Option struct {
+	DisabledBuiltinChecks []enumor.SGComplianceBuiltinCheck
+	SensitivePorts        []uint32
+	MaxPortRangeSize      uint32
+	// Templates 存在的参数模版云ID，为 nil 时不检查规则引用的参数模版
+	Templates map[string]struct{}
+	// Checks 需要执行的自定义检查项
+	Checks []coresgcompliance.Check
+}
+
+func (opt *Option) builtinEnabled(check enumor.SGComplianceBuiltinCheck) bool {
+	return !slice.IsItemInSlice(opt.DisabledBuiltinChecks, check)
+}
+
+// Evaluate evaluate the rules with builtin checks and custom checks, rules of the same group are evaluated together
+// to find out duplicate and shadowed rules.
+func Evaluate(rules []Rule, opt *Option) []coresgcompliance.Finding {
+	findings := make([]coresgcompliance.Finding, 0)
+
+	groups := make(map[string][]*Rule)
+	groupKeys := make([]string, 0)
+	for idx := range rules {
+		rule := &rules[idx]
+		key := fmt.Sprintf("%s/%s/%s", rule.Vendor, rule.GroupID, rule.Type)
+		if _, exists := groups[key]; !exists {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], rule)
+
+		findings = append(findings, evaluateRule(rule, opt)...)
+	}
+
+	for _, key := range groupKeys {
+		findings = append(findings, evaluateGroup(groups[key], opt)...)
+	}
+
+	return findings
+}
+
+func newFinding(rule *Rule, severity enumor.SGComplianceSeverity, message string) coresgcompliance.Finding {
+	finding := coresgcompliance.Finding{
+		Severity: severity,
+		Vendor:   rule.Vendor,
+		RuleType: rule.Type,
+		RuleID:   rule.ID,
+		Message:  message,
+	}
+
+	if rule.Vendor == enumor.Gcp {
+		finding.CloudVpcID = rule.GroupID
+	} else {
+		finding.SecurityGroupID = rule.GroupID
+	}
+
+	return finding
+}
+
+func newBuiltinFinding(rule *Rule, check enumor.SGComplianceBuiltinCheck, message string) coresgcompliance.Finding {
+	finding := newFinding(rule, BuiltinCheckSeverity[check], message)
+	finding.CheckID = string(check)
+	finding.CheckName = string(check)
+	finding.Builtin = true
+	return finding
+}
+
+// evaluateRule 执行针对单条规则的检查项
+func evaluateRule(rule *Rule, opt *Option) []coresgcompliance.Finding {
+	findings := make([]coresgcompliance.Finding, 0)
+
+	if opt.builtinEnabled(enumor.SGCheckPublicSensitivePort) {
+		if ports := publicSensitivePorts(rule, opt.SensitivePorts); len(ports) != 0 {
+			findings = append(findings, newBuiltinFinding(rule, enumor.SGCheckPublicSensitivePort,
+				fmt.Sprintf("sensitive port %v is open to any address", ports)))
+		}
+	}
+
+	if opt.builtinEnabled(enumor.SGCheckWidePortRange) && opt.MaxPortRangeSize > 0 && rule.Allow &&
+		rule.hasPorts() && rule.PortRef == "" {
+
+		if size := portsSize(rule.Ports); size > opt.MaxPortRangeSize {
+			findings = append(findings, newBuiltinFinding(rule, enumor.SGCheckWidePortRange,
+				fmt.Sprintf("rule opens %d ports, exceeds the limit %d", size, opt.MaxPortRangeSize)))
+		}
+	}
+
+	if opt.builtinEnabled(enumor.SGCheckInvalidArgsTpl) && opt.Templates != nil {
+		for _, tplID := range rule.TemplateIDs {
+			if _, exists := opt.Templates[tplID]; !exists {
+				findings = append(findings, newBuiltinFinding(rule, enumor.SGCheckInvalidArgsTpl,
+					fmt.Sprintf("argument template %s does not exist", tplID)))
+			}
+		}
+	}
+
+	for idx := range opt.Checks {
+		check := &opt.Checks[idx]
+		if !matchCheck(rule, check) {
+			continue
+		}
+
+		finding := newFinding(rule, check.Severity, fmt.Sprintf("rule matches compliance check %s", check.Name))
+		finding.CheckID = check.ID
+		finding.CheckName = check.Name
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// publicSensitivePorts 返回对全部地址开放的放通入站规则中包含的高危端口
+func publicSensitivePorts(rule *Rule, sensitivePorts []uint32) []uint32 {
+	if !rule.Allow || rule.Type != enumor.Ingress || !rule.hasPorts() || rule.PortRef != "" || !rule.anyAddress() {
+		return nil
+	}
+
+	ports := make([]uint32, 0)
+	for _, port := range sensitivePorts {
+		if portsOverlap(rule.Ports, []coresgcompliance.PortRange{{From: port, To: port}}) {
+			ports = append(ports, port)
+		}
+	}
+
+	return ports
+}
+
+// matchCheck 放通规则是否命中自定义检查项的全部条件
+func matchCheck(rule *Rule, check *coresgcompliance.Check) bool {
+	if !check.Enabled || !rule.Allow {
+		return false
+	}
+
+	cond := check.Conditions
+	if len(cond.RuleTypes) != 0 && !slice.IsItemInSlice(cond.RuleTypes, rule.Type) {
+		return false
+	}
+
+	if len(cond.Vendors) != 0 && !slice.IsItemInSlice(cond.Vendors, rule.Vendor) {
+		return false
+	}
+
+	if len(cond.Protocols) != 0 || len(cond.PortRanges) != 0 {
+		if rule.PortRef != "" {
+			return false
+		}
+	}
+
+	if len(cond.Protocols) != 0 && rule.Protocol != protocolAll {
+		matched := false
+		for _, protocol := range cond.Protocols {
+			protocol = NormalizeProtocol(protocol)
+			if protocol == protocolAll || protocol == rule.Protocol {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(cond.PortRanges) != 0 && (!rule.hasPorts() || !portsOverlap(rule.Ports, cond.PortRanges)) {
+		return false
+	}
+
+	if cond.AnyAddress && !rule.anyAddress() {
+		return false
+	}
+
+	if len(cond.CIDRs) != 0 && (rule.AddressRef != "" || !addressesOverlap(rule.Addresses, cond.CIDRs)) {
+		return false
+	}
+
+	return true
+}
+
+// evaluateGroup 执行同一安全组内同方向规则之间的检查项，规则需按照原有顺序排列
+func evaluateGroup(rules []*Rule, opt *Option) []coresgcompliance.Finding {
+	findings := make([]coresgcompliance.Finding, 0)
+
+	checkDuplicate := opt.builtinEnabled(enumor.SGCheckDuplicateRule)
+	checkShadowed := opt.builtinEnabled(enumor.SGCheckShadowedRule)
+	if !checkDuplicate && !checkShadowed {
+		return findings
+	}
+
+	signatures := make(map[string]*Rule)
+	for idx, rule := range rules {
+		signature := rule.signature()
+		if first, exists := signatures[signature]; exists {
+			if checkDuplicate {
+				finding := newBuiltinFinding(rule, enumor.SGCheckDuplicateRule, "rule duplicates another rule")
+				finding.RelatedRuleID = first.ID
+				findings = append(findings, finding)
+			}
+			continue
+		}
+		signatures[signature] = rule
+
+		if !checkShadowed {
+			continue
+		}
+
+		for prevIdx, other := range rules {
+			if prevIdx == idx || !matchesBefore(other, prevIdx, rule, idx) || !other.covers(rule) {
+				continue
+			}
+
+			// 重复规则已单独报告
+			if other.signature() == signature {
+				continue
+			}
+
+			finding := newBuiltinFinding(rule, enumor.SGCheckShadowedRule,
+				"rule is shadowed by a higher priority rule and will never take effect")
+			finding.RelatedRuleID = other.ID
+			findings = append(findings, finding)
+			break
+		}
+	}
+
+	return findings
+}
+
+// matchesBefore 规则 a 是否先于规则 b 匹配，优先级相同时拒绝规则优先匹配，动作也相同时按照规则顺序匹配
+func matchesBefore(a *Rule, aIdx int, b *Rule, bIdx int) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+
+	if a.Allow != b.Allow {
+		return !a.Allow
+	}
+
+	return aIdx < bIdx
+}
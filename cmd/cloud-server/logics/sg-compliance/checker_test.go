@@ -0,0 +1,189 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgcompliance
+
+import (
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOption() *Option {
+	return &Option{
+		SensitivePorts:   []uint32{22, 3389, 3306},
+		MaxPortRangeSize: 1000,
+	}
+}
+
+func newTCloudRule(t *testing.T, id string, index int64, port, cidr, action string) Rule {
+	rule, err := FromTCloudRule(corecloud.TCloudSecurityGroupRule{
+		ID:               id,
+		CloudPolicyIndex: index,
+		Protocol:         converter.ValToPtr("tcp"),
+		Port:             converter.ValToPtr(port),
+		IPv4Cidr:         converter.ValToPtr(cidr),
+		Action:           action,
+		Type:             enumor.Ingress,
+		SecurityGroupID:  "sg-1",
+	})
+	assert.NoError(t, err)
+	return rule
+}
+
+func findingsOf(findings []coresgcompliance.Finding,
+	checkID enumor.SGComplianceBuiltinCheck) []coresgcompliance.Finding {
+
+	result := make([]coresgcompliance.Finding, 0)
+	for _, one := range findings {
+		if one.CheckID == string(checkID) {
+			result = append(result, one)
+		}
+	}
+	return result
+}
+
+func TestEvaluate_PublicSensitivePort(t *testing.T) {
+	rules := []Rule{
+		newTCloudRule(t, "1", 0, "22,80", "0.0.0.0/0", "ACCEPT"),
+		newTCloudRule(t, "2", 1, "22", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "3", 2, "3306", "0.0.0.0/0", "DROP"),
+	}
+
+	findings := findingsOf(Evaluate(rules, newTestOption()), enumor.SGCheckPublicSensitivePort)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "1", findings[0].RuleID)
+	assert.Equal(t, enumor.SGComplianceHigh, findings[0].Severity)
+	assert.Equal(t, "sg-1", findings[0].SecurityGroupID)
+
+	// 禁用内置检查项后不再检查
+	opt := newTestOption()
+	opt.DisabledBuiltinChecks = []enumor.SGComplianceBuiltinCheck{enumor.SGCheckPublicSensitivePort}
+	assert.Empty(t, findingsOf(Evaluate(rules, opt), enumor.SGCheckPublicSensitivePort))
+}
+
+func TestEvaluate_WidePortRange(t *testing.T) {
+	rules := []Rule{
+		newTCloudRule(t, "1", 0, "1-2000", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "2", 1, "8000-8100", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "3", 2, "ALL", "10.0.0.0/8", "DROP"),
+	}
+
+	findings := findingsOf(Evaluate(rules, newTestOption()), enumor.SGCheckWidePortRange)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "1", findings[0].RuleID)
+}
+
+func TestEvaluate_DuplicateAndShadowed(t *testing.T) {
+	rules := []Rule{
+		newTCloudRule(t, "1", 0, "80-90", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "2", 1, "80", "10.1.0.0/16", "ACCEPT"),
+		newTCloudRule(t, "3", 2, "80-90", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "4", 3, "443", "10.0.0.0/8", "ACCEPT"),
+	}
+
+	findings := Evaluate(rules, newTestOption())
+	duplicates := findingsOf(findings, enumor.SGCheckDuplicateRule)
+	assert.Len(t, duplicates, 1)
+	assert.Equal(t, "3", duplicates[0].RuleID)
+	assert.Equal(t, "1", duplicates[0].RelatedRuleID)
+
+	shadowed := findingsOf(findings, enumor.SGCheckShadowedRule)
+	assert.Len(t, shadowed, 1)
+	assert.Equal(t, "2", shadowed[0].RuleID)
+	assert.Equal(t, "1", shadowed[0].RelatedRuleID)
+}
+
+func TestEvaluate_InvalidArgsTpl(t *testing.T) {
+	rule, err := FromTCloudRule(corecloud.TCloudSecurityGroupRule{
+		ID:              "1",
+		CloudServiceID:  converter.ValToPtr("ppm-1"),
+		CloudAddressID:  converter.ValToPtr("ipm-1"),
+		Action:          "ACCEPT",
+		Type:            enumor.Ingress,
+		SecurityGroupID: "sg-1",
+	})
+	assert.NoError(t, err)
+
+	opt := newTestOption()
+	opt.Templates = map[string]struct{}{"ppm-1": {}}
+	findings := findingsOf(Evaluate([]Rule{rule}, opt), enumor.SGCheckInvalidArgsTpl)
+	assert.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "ipm-1")
+}
+
+func TestEvaluate_CustomCheck(t *testing.T) {
+	rules := []Rule{
+		newTCloudRule(t, "1", 0, "8080", "0.0.0.0/0", "ACCEPT"),
+		newTCloudRule(t, "2", 1, "8080", "10.0.0.0/8", "ACCEPT"),
+		newTCloudRule(t, "3", 2, "9090", "0.0.0.0/0", "ACCEPT"),
+	}
+
+	opt := newTestOption()
+	opt.Checks = []coresgcompliance.Check{{
+		ID:       "00000001",
+		Name:     "no public 8080",
+		Severity: enumor.SGComplianceMedium,
+		Enabled:  true,
+		Conditions: coresgcompliance.CheckCondition{
+			Protocols:  []string{"tcp"},
+			AnyAddress: true,
+			PortRanges: []coresgcompliance.PortRange{{From: 8000, To: 8999}},
+		},
+	}}
+
+	findings := make([]coresgcompliance.Finding, 0)
+	for _, one := range Evaluate(rules, opt) {
+		if !one.Builtin {
+			findings = append(findings, one)
+		}
+	}
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "1", findings[0].RuleID)
+	assert.Equal(t, "00000001", findings[0].CheckID)
+}
+
+func TestFromGcpFirewallRule(t *testing.T) {
+	rules, err := FromGcpFirewallRule(corecloud.GcpFirewallRule{
+		ID:         "1",
+		CloudVpcID: "vpc-1",
+		Type:       "INGRESS",
+		Allowed: []corecloud.GcpProtocolSet{
+			{Protocol: "tcp", Port: []string{"22", "8000-9000"}},
+			{Protocol: "icmp"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, []string{"0.0.0.0/0"}, rules[0].Addresses)
+
+	findings := findingsOf(Evaluate(rules, newTestOption()), enumor.SGCheckPublicSensitivePort)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "vpc-1", findings[0].CloudVpcID)
+
+	rules, err = FromGcpFirewallRule(corecloud.GcpFirewallRule{ID: "2", Disabled: true,
+		Allowed: []corecloud.GcpProtocolSet{{Protocol: "all"}}})
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
@@ -0,0 +1,396 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sgcompliance 安全组规则风险分析与合规检查
+package sgcompliance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// Interface define security group rule compliance interface.
+type Interface interface {
+	// ListFindings evaluate the rules of security groups and gcp firewall rules, returns all findings.
+	ListFindings(kt *kit.Kit, bizID int64, sgs []corecloud.BaseSecurityGroup,
+		firewallRules []corecloud.GcpFirewallRule) ([]coresgcompliance.Finding, error)
+	// CheckSGRules evaluate the security group with the rules to be created or updated, returns error if
+	// enforcement is enabled and the new rules violate the compliance checks.
+	CheckSGRules(kt *kit.Kit, sg *types.CloudResourceBasicInfo, newRules []Rule) error
+	// CheckGcpFirewallRule evaluate the gcp firewall rule to be created or updated with other firewall rules in
+	// the same vpc, returns error if enforcement is enabled and the rule violates the compliance checks.
+	CheckGcpFirewallRule(kt *kit.Kit, bizID int64, rule corecloud.GcpFirewallRule) error
+}
+
+type compliance struct {
+	client *client.ClientSet
+	config cc.SGCompliance
+}
+
+// NewCompliance new security group rule compliance.
+func NewCompliance(client *client.ClientSet, config cc.SGCompliance) Interface {
+	return &compliance{
+		client: client,
+		config: config,
+	}
+}
+
+// ListFindings evaluate the rules of security groups and gcp firewall rules, returns all findings.
+func (c *compliance) ListFindings(kt *kit.Kit, bizID int64, sgs []corecloud.BaseSecurityGroup,
+	firewallRules []corecloud.GcpFirewallRule) ([]coresgcompliance.Finding, error) {
+
+	rules := make([]Rule, 0)
+	for _, sg := range sgs {
+		sgRules, err := ListSGRules(kt, c.client, sg.Vendor, sg.ID)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, sgRules...)
+	}
+
+	for _, one := range firewallRules {
+		converted, err := FromGcpFirewallRule(one)
+		if err != nil {
+			logs.Errorf("convert gcp firewall rule failed, err: %v, id: %s, rid: %s", err, one.ID, kt.Rid)
+			return nil, err
+		}
+		rules = append(rules, converted...)
+	}
+
+	opt, err := c.buildOption(kt, bizID, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return Evaluate(rules, opt), nil
+}
+
+// CheckSGRules evaluate the security group with the rules to be created or updated, returns error if enforcement
+// is enabled and the new rules violate the compliance checks.
+func (c *compliance) CheckSGRules(kt *kit.Kit, sg *types.CloudResourceBasicInfo, newRules []Rule) error {
+	if !c.config.Enforce || len(newRules) == 0 {
+		return nil
+	}
+
+	existing, err := ListSGRules(kt, c.client, sg.Vendor, sg.ID)
+	if err != nil {
+		return err
+	}
+
+	existingByID := make(map[string]Rule, len(existing))
+	existingByPriority := make(map[string]Rule, len(existing))
+	for _, one := range existing {
+		existingByID[one.ID] = one
+		existingByPriority[fmt.Sprintf("%s/%d", one.Type, one.Priority)] = one
+	}
+
+	newRuleIDs := make(map[string]struct{})
+	for idx := range newRules {
+		rule := &newRules[idx]
+		rule.GroupID = sg.ID
+		rule.Vendor = sg.Vendor
+
+		if rule.ReplaceByPriority {
+			if old, exists := existingByPriority[fmt.Sprintf("%s/%d", rule.Type, rule.Priority)]; exists {
+				rule.ID = old.ID
+			}
+		}
+
+		if rule.ID == "" {
+			continue
+		}
+
+		// 更新请求中未指定的方向、优先级沿用已有规则
+		if old, exists := existingByID[rule.ID]; exists {
+			if rule.Type == "" {
+				rule.Type = old.Type
+			}
+			if rule.Priority == 0 {
+				rule.Priority = old.Priority
+			}
+		}
+		newRuleIDs[rule.ID] = struct{}{}
+	}
+
+	rules := make([]Rule, 0, len(existing)+len(newRules))
+	for _, one := range existing {
+		// 待更新的规则使用更新后的内容进行检查
+		if _, exists := newRuleIDs[one.ID]; exists {
+			continue
+		}
+		rules = append(rules, one)
+	}
+	rules = append(rules, newRules...)
+
+	return c.enforce(kt, sg.BkBizID, rules, newRuleIDs)
+}
+
+// CheckGcpFirewallRule evaluate the gcp firewall rule to be created or updated with other firewall rules in the same
+// vpc, returns error if enforcement is enabled and the rule violates the compliance checks.
+func (c *compliance) CheckGcpFirewallRule(kt *kit.Kit, bizID int64, rule corecloud.GcpFirewallRule) error {
+	if !c.config.Enforce {
+		return nil
+	}
+
+	newRules, err := FromGcpFirewallRule(rule)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if len(newRules) == 0 {
+		return nil
+	}
+
+	listReq := &dataproto.GcpFirewallRuleListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("cloud_vpc_id", rule.CloudVpcID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	rules := make([]Rule, 0)
+	for {
+		result, err := c.client.DataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list gcp firewall rule failed, err: %v, vpc: %s, rid: %s", err, rule.CloudVpcID, kt.Rid)
+			return err
+		}
+
+		for _, one := range result.Details {
+			if rule.ID != "" && one.ID == rule.ID {
+				continue
+			}
+			converted, err := FromGcpFirewallRule(one)
+			if err != nil {
+				logs.Errorf("convert gcp firewall rule failed, err: %v, id: %s, rid: %s", err, one.ID, kt.Rid)
+				return err
+			}
+			rules = append(rules, converted...)
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	newRuleIDs := make(map[string]struct{})
+	if rule.ID != "" {
+		newRuleIDs[rule.ID] = struct{}{}
+	}
+	rules = append(rules, newRules...)
+
+	return c.enforce(kt, bizID, rules, newRuleIDs)
+}
+
+// enforce 检查规则，待创建或更新的规则存在等级不低于拦截等级的问题时返回错误
+func (c *compliance) enforce(kt *kit.Kit, bizID int64, rules []Rule, newRuleIDs map[string]struct{}) error {
+	opt, err := c.buildOption(kt, bizID, rules)
+	if err != nil {
+		return err
+	}
+
+	violations := make([]string, 0)
+	for _, finding := range Evaluate(rules, opt) {
+		if _, exists := newRuleIDs[finding.RuleID]; !exists && finding.RuleID != "" {
+			continue
+		}
+
+		if finding.Severity.Level() < c.config.EnforceSeverity.Level() {
+			continue
+		}
+
+		violations = append(violations, fmt.Sprintf("[%s] %s", finding.CheckName, finding.Message))
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return errf.Newf(errf.InvalidParameter, "security group rule violates compliance checks: %s",
+		strings.Join(violations, "; "))
+}
+
+// buildOption 构建检查参数，查询业务下生效的自定义检查项以及规则引用的参数模版
+func (c *compliance) buildOption(kt *kit.Kit, bizID int64, rules []Rule) (*Option, error) {
+	opt := &Option{
+		DisabledBuiltinChecks: c.config.DisabledBuiltinChecks,
+		SensitivePorts:        c.config.SensitivePorts,
+		MaxPortRangeSize:      c.config.MaxPortRangeSize,
+	}
+
+	checks, err := c.listEnabledChecks(kt, bizID)
+	if err != nil {
+		return nil, err
+	}
+	opt.Checks = checks
+
+	tplIDs := make([]string, 0)
+	for _, rule := range rules {
+		tplIDs = append(tplIDs, rule.TemplateIDs...)
+	}
+	tplIDs = slice.Unique(tplIDs)
+	if len(tplIDs) == 0 {
+		return opt, nil
+	}
+
+	opt.Templates = make(map[string]struct{}, len(tplIDs))
+	for _, ids := range slice.Split(tplIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("cloud_id", ids),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"cloud_id"},
+		}
+		result, err := c.client.DataService().Global.ArgsTpl.ListArgsTpl(kt, listReq)
+		if err != nil {
+			logs.Errorf("list argument template failed, err: %v, cloud_ids: %v, rid: %s", err, ids, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			opt.Templates[one.CloudID] = struct{}{}
+		}
+	}
+
+	return opt, nil
+}
+
+// listEnabledChecks 查询对业务生效的自定义检查项
+func (c *compliance) listEnabledChecks(kt *kit.Kit, bizID int64) ([]coresgcompliance.Check, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("enabled", true),
+			tools.RuleIn("bk_biz_id", []int64{constant.UnassignedBiz, bizID}),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+
+	checks := make([]coresgcompliance.Check, 0)
+	for {
+		result, err := c.client.DataService().Global.ListSGComplianceCheck(kt, listReq)
+		if err != nil {
+			logs.Errorf("list sg compliance check failed, err: %v, biz: %d, rid: %s", err, bizID, kt.Rid)
+			return nil, err
+		}
+
+		checks = append(checks, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return checks, nil
+}
+
+// ListSGRules 查询安全组下的全部规则并转换为归一化的规则
+func ListSGRules(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, sgID string) ([]Rule, error) {
+	var rules []Rule
+	var err error
+	switch vendor {
+	case enumor.TCloud:
+		rules, err = listAndConvert(kt, sgID, cli.DataService().TCloud.SecurityGroup.ListSecurityGroupRule,
+			func(page *core.BasePage) *dataproto.TCloudSGRuleListReq {
+				return &dataproto.TCloudSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			},
+			func(result *dataproto.TCloudSGRuleListResult) []corecloud.TCloudSecurityGroupRule {
+				return result.Details
+			}, FromTCloudRule)
+	case enumor.Aws:
+		rules, err = listAndConvert(kt, sgID, cli.DataService().Aws.SecurityGroup.ListSecurityGroupRule,
+			func(page *core.BasePage) *dataproto.AwsSGRuleListReq {
+				return &dataproto.AwsSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			},
+			func(result *dataproto.AwsSGRuleListResult) []corecloud.AwsSecurityGroupRule {
+				return result.Details
+			}, FromAwsRule)
+	case enumor.HuaWei:
+		rules, err = listAndConvert(kt, sgID, cli.DataService().HuaWei.SecurityGroup.ListSecurityGroupRule,
+			func(page *core.BasePage) *dataproto.HuaWeiSGRuleListReq {
+				return &dataproto.HuaWeiSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			},
+			func(result *dataproto.HuaWeiSGRuleListResult) []corecloud.HuaWeiSecurityGroupRule {
+				return result.Details
+			}, FromHuaWeiRule)
+	case enumor.Azure:
+		rules, err = listAndConvert(kt, sgID, cli.DataService().Azure.SecurityGroup.ListSecurityGroupRule,
+			func(page *core.BasePage) *dataproto.AzureSGRuleListReq {
+				return &dataproto.AzureSGRuleListReq{Filter: tools.AllExpression(), Page: page}
+			},
+			func(result *dataproto.AzureSGRuleListResult) []corecloud.AzureSecurityGroupRule {
+				return result.Details
+			}, FromAzureRule)
+	default:
+		return nil, fmt.Errorf("unsupported vendor %s for security group rule compliance check", vendor)
+	}
+	if err != nil {
+		logs.Errorf("list security group rules failed, err: %v, sgID: %s, rid: %s", err, sgID, kt.Rid)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+type listRuleFunc[Req any, Result any] func(ctx context.Context, h http.Header, req *Req, sgID string) (
+	*Result, error)
+
+// listAndConvert 分页查询安全组规则并进行转换
+func listAndConvert[Req any, Result any, T any](kt *kit.Kit, sgID string, list listRuleFunc[Req, Result],
+	newReq func(page *core.BasePage) *Req, details func(result *Result) []T, convert func(T) (Rule, error)) (
+	[]Rule, error) {
+
+	rules := make([]Rule, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		result, err := list(kt.Ctx, kt.Header(), newReq(page), sgID)
+		if err != nil {
+			return nil, err
+		}
+
+		items := details(result)
+		for _, item := range items {
+			rule, err := convert(item)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+
+		if uint(len(items)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return rules, nil
+}
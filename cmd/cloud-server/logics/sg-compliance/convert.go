@@ -0,0 +1,270 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgcompliance
+
+import (
+	"sort"
+	"strings"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+// FromTCloudRule convert tcloud security group rule to normalized rule.
+func FromTCloudRule(rule corecloud.TCloudSecurityGroupRule) (Rule, error) {
+	result := Rule{
+		ID:       rule.ID,
+		GroupID:  rule.SecurityGroupID,
+		Vendor:   enumor.TCloud,
+		Type:     rule.Type,
+		Priority: rule.CloudPolicyIndex,
+		Allow:    strings.EqualFold(rule.Action, "ACCEPT"),
+		Protocol: NormalizeProtocol(converter.PtrToVal(rule.Protocol)),
+	}
+
+	for _, serviceID := range []string{converter.PtrToVal(rule.CloudServiceID),
+		converter.PtrToVal(rule.CloudServiceGroupID)} {
+
+		if serviceID != "" {
+			result.PortRef = serviceID
+			result.TemplateIDs = append(result.TemplateIDs, serviceID)
+		}
+	}
+
+	if result.PortRef == "" && result.hasPorts() {
+		ports, err := ParsePorts(converter.PtrToVal(rule.Port), ",")
+		if err != nil {
+			return Rule{}, err
+		}
+		result.Ports = ports
+	}
+
+	for _, addressID := range []string{converter.PtrToVal(rule.CloudAddressID),
+		converter.PtrToVal(rule.CloudAddressGroupID)} {
+
+		if addressID != "" {
+			result.AddressRef = addressID
+			result.TemplateIDs = append(result.TemplateIDs, addressID)
+		}
+	}
+
+	if sgID := converter.PtrToVal(rule.CloudTargetSecurityGroupID); sgID != "" {
+		result.AddressRef = sgID
+	}
+
+	for _, cidr := range []string{converter.PtrToVal(rule.IPv4Cidr), converter.PtrToVal(rule.IPv6Cidr)} {
+		if cidr != "" {
+			result.Addresses = append(result.Addresses, cidr)
+		}
+	}
+
+	return result, nil
+}
+
+// FromAwsRule convert aws security group rule to normalized rule, aws security group rule only supports allow.
+func FromAwsRule(rule corecloud.AwsSecurityGroupRule) (Rule, error) {
+	result := Rule{
+		ID:       rule.ID,
+		GroupID:  rule.SecurityGroupID,
+		Vendor:   enumor.Aws,
+		Type:     rule.Type,
+		Allow:    true,
+		Protocol: NormalizeProtocol(converter.PtrToVal(rule.Protocol)),
+	}
+
+	from, to := converter.PtrToVal(rule.FromPort), converter.PtrToVal(rule.ToPort)
+	if result.hasPorts() && from >= 0 && to >= 0 {
+		portRange := coresgcompliance.PortRange{From: uint32(from), To: uint32(to)}
+		if err := portRange.Validate(); err != nil {
+			return Rule{}, err
+		}
+		if portRange.From != 0 || portRange.To != 65535 {
+			result.Ports = []coresgcompliance.PortRange{portRange}
+		}
+	}
+
+	for _, ref := range []string{converter.PtrToVal(rule.CloudTargetSecurityGroupID),
+		converter.PtrToVal(rule.CloudPrefixListID)} {
+
+		if ref != "" {
+			result.AddressRef = ref
+		}
+	}
+
+	for _, cidr := range []string{converter.PtrToVal(rule.IPv4Cidr), converter.PtrToVal(rule.IPv6Cidr)} {
+		if cidr != "" {
+			result.Addresses = append(result.Addresses, cidr)
+		}
+	}
+
+	return result, nil
+}
+
+// FromHuaWeiRule convert huawei security group rule to normalized rule.
+func FromHuaWeiRule(rule corecloud.HuaWeiSecurityGroupRule) (Rule, error) {
+	result := Rule{
+		ID:       rule.ID,
+		GroupID:  rule.SecurityGroupID,
+		Vendor:   enumor.HuaWei,
+		Type:     rule.Type,
+		Priority: rule.Priority,
+		Allow:    !strings.EqualFold(rule.Action, "deny"),
+		Protocol: NormalizeProtocol(rule.Protocol),
+	}
+
+	if result.hasPorts() {
+		ports, err := ParsePorts(rule.Port, ",")
+		if err != nil {
+			return Rule{}, err
+		}
+		result.Ports = ports
+	}
+
+	switch {
+	case rule.CloudRemoteGroupID != "":
+		result.AddressRef = rule.CloudRemoteGroupID
+	case rule.CloudRemoteAddressGroupID != "":
+		result.AddressRef = rule.CloudRemoteAddressGroupID
+	case rule.RemoteIPPrefix != "":
+		result.Addresses = []string{rule.RemoteIPPrefix}
+	case strings.EqualFold(rule.Ethertype, "IPv6"):
+		// 未指定远端时表示全部地址
+		result.Addresses = []string{"::/0"}
+	default:
+		result.Addresses = []string{"0.0.0.0/0"}
+	}
+
+	return result, nil
+}
+
+// FromAzureRule convert azure security group rule to normalized rule.
+func FromAzureRule(rule corecloud.AzureSecurityGroupRule) (Rule, error) {
+	result := Rule{
+		ID:       rule.ID,
+		GroupID:  rule.SecurityGroupID,
+		Vendor:   enumor.Azure,
+		Type:     rule.Type,
+		Priority: int64(rule.Priority),
+		Allow:    strings.EqualFold(rule.Access, "Allow"),
+		Protocol: NormalizeProtocol(rule.Protocol),
+	}
+
+	if result.hasPorts() {
+		portStrs := converter.PtrToSlice(rule.DestinationPortRanges)
+		if rule.DestinationPortRange != nil {
+			portStrs = append(portStrs, *rule.DestinationPortRange)
+		}
+		ports, err := ParsePorts(strings.Join(portStrs, ","), ",")
+		if err != nil {
+			return Rule{}, err
+		}
+		result.Ports = ports
+	}
+
+	prefix, prefixes, asgIDs := rule.SourceAddressPrefix, rule.SourceAddressPrefixes,
+		rule.CloudSourceAppSecurityGroupIDs
+	if rule.Type == enumor.Egress {
+		prefix, prefixes, asgIDs = rule.DestinationAddressPrefix, rule.DestinationAddressPrefixes,
+			rule.CloudDestinationAppSecurityGroupIDs
+	}
+
+	if len(asgIDs) != 0 {
+		ids := converter.PtrToSlice(asgIDs)
+		sort.Strings(ids)
+		result.AddressRef = strings.Join(ids, ",")
+	}
+
+	addresses := converter.PtrToSlice(prefixes)
+	if prefix != nil {
+		addresses = append(addresses, *prefix)
+	}
+	for _, address := range addresses {
+		// 服务标签(如 VirtualNetwork)无法确定具体地址
+		if _, ok := parseCIDR(address); !ok {
+			result.AddressRef = address
+			continue
+		}
+		result.Addresses = append(result.Addresses, address)
+	}
+
+	return result, nil
+}
+
+// FromGcpFirewallRule convert gcp firewall rule to normalized rules, each protocol of the firewall rule will be
+// converted to one rule, disabled firewall rule will be ignored.
+func FromGcpFirewallRule(rule corecloud.GcpFirewallRule) ([]Rule, error) {
+	if rule.Disabled {
+		return make([]Rule, 0), nil
+	}
+
+	base := Rule{
+		ID:       rule.ID,
+		GroupID:  rule.CloudVpcID,
+		Vendor:   enumor.Gcp,
+		Type:     enumor.Ingress,
+		Priority: rule.Priority,
+	}
+
+	targets := append(append([]string{}, rule.TargetTags...), rule.TargetServiceAccounts...)
+	sort.Strings(targets)
+	base.Scope = strings.Join(targets, ",")
+
+	if strings.EqualFold(rule.Type, string(enumor.Egress)) {
+		base.Type = enumor.Egress
+		base.Addresses = rule.DestinationRanges
+	} else {
+		base.Addresses = rule.SourceRanges
+		if len(rule.SourceRanges) == 0 && (len(rule.SourceTags) != 0 || len(rule.SourceServiceAccounts) != 0) {
+			sources := append(append([]string{}, rule.SourceTags...), rule.SourceServiceAccounts...)
+			sort.Strings(sources)
+			base.AddressRef = strings.Join(sources, ",")
+		}
+	}
+
+	// 未指定地址时表示全部地址
+	if len(base.Addresses) == 0 && base.AddressRef == "" {
+		base.Addresses = []string{"0.0.0.0/0"}
+	}
+
+	result := make([]Rule, 0, len(rule.Allowed)+len(rule.Denied))
+	for _, sets := range []struct {
+		allow     bool
+		protocols []corecloud.GcpProtocolSet
+	}{{allow: true, protocols: rule.Allowed}, {allow: false, protocols: rule.Denied}} {
+
+		for _, set := range sets.protocols {
+			one := base
+			one.Allow = sets.allow
+			one.Protocol = NormalizeProtocol(set.Protocol)
+			if one.hasPorts() {
+				ports, err := ParsePorts(strings.Join(set.Port, ","), ",")
+				if err != nil {
+					return nil, err
+				}
+				one.Ports = ports
+			}
+			result = append(result, one)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,374 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sgcompliance
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+)
+
+const (
+	protocolAll  = "all"
+	protocolTCP  = "tcp"
+	protocolUDP  = "udp"
+	protocolICMP = "icmp"
+)
+
+// Rule 归一化后的安全组规则，用于跨云厂商统一进行合规检查
+type Rule struct {
+	// ID 规则ID，待创建的规则为空
+	ID string
+	// GroupID 规则所属安全组ID，gcp 防火墙规则为所属VPC的云ID
+	GroupID string
+	Vendor  enumor.Vendor
+	Type    enumor.SecurityGroupRuleType
+	// Priority 规则匹配顺序，数值越小越先匹配
+	Priority int64
+	Allow    bool
+	// Protocol 归一化后的协议，all 表示全部协议
+	Protocol string
+	// Ports 规则端口，为空表示全部端口
+	Ports []coresgcompliance.PortRange
+	// PortRef 端口无法确定时引用的对象，如协议端口模版
+	PortRef string
+	// Addresses 入站规则的来源地址或出站规则的目的地址
+	Addresses []string
+	// AddressRef 地址无法确定时引用的对象，如安全组、地址模版、服务标签
+	AddressRef string
+	// TemplateIDs 规则引用的参数模版云ID
+	TemplateIDs []string
+	// Scope 规则生效范围，gcp 防火墙规则的目标标签、服务账号不同时规则之间互不影响
+	Scope string
+	// ReplaceByPriority 待更新的规则是否按照方向和优先级替换已有规则，用于按照规则索引批量更新的场景
+	ReplaceByPriority bool
+}
+
+// covers 规则 r 匹配的流量是否完全包含规则 other 匹配的流量
+func (r *Rule) covers(other *Rule) bool {
+	if r.Type != other.Type || r.Scope != other.Scope {
+		return false
+	}
+
+	if r.Protocol != protocolAll && r.Protocol != other.Protocol {
+		return false
+	}
+
+	if r.PortRef != "" || other.PortRef != "" {
+		if r.PortRef != other.PortRef {
+			return false
+		}
+	} else if !portsContain(r.Ports, other.Ports) {
+		return false
+	}
+
+	if r.AddressRef != "" || other.AddressRef != "" {
+		return r.AddressRef == other.AddressRef
+	}
+
+	return addressesContain(r.Addresses, other.Addresses)
+}
+
+// signature 规则匹配内容的签名，签名相同的规则视为重复规则
+func (r *Rule) signature() string {
+	ports := make([]string, 0, len(r.Ports))
+	for _, one := range r.Ports {
+		ports = append(ports, one.String())
+	}
+	sort.Strings(ports)
+
+	addresses := make([]string, len(r.Addresses))
+	copy(addresses, r.Addresses)
+	sort.Strings(addresses)
+
+	return fmt.Sprintf("%s|%s|%t|%s|%s|%s|%s|%s", r.Type, r.Scope, r.Allow, r.Protocol, strings.Join(ports, ","),
+		r.PortRef, strings.Join(addresses, ","), r.AddressRef)
+}
+
+// anyAddress 规则地址是否包含全部地址
+func (r *Rule) anyAddress() bool {
+	if r.AddressRef != "" {
+		return false
+	}
+
+	for _, address := range r.Addresses {
+		if isAnyAddress(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchProtocolPort 规则的协议端口是否匹配指定流量，不区分端口的协议忽略端口，port 为 0 时表示全部端口，
+// 规则引用了协议端口模版时需要先解析模版
+func (r *Rule) MatchProtocolPort(protocol string, port uint32) bool {
+	protocol = NormalizeProtocol(protocol)
+	if r.Protocol != protocolAll && r.Protocol != protocol {
+		return false
+	}
+
+	if !r.hasPorts() || len(r.Ports) == 0 {
+		return true
+	}
+
+	if port == 0 {
+		return false
+	}
+
+	return portsContain(r.Ports, []coresgcompliance.PortRange{{From: port, To: port}})
+}
+
+// MatchAddress 规则地址是否包含指定IP，规则引用了安全组、地址模版时需要先解析引用
+func (r *Rule) MatchAddress(ip string) bool {
+	return addressesContain(r.Addresses, []string{ip})
+}
+
+// hasPorts 规则协议是否区分端口
+func (r *Rule) hasPorts() bool {
+	return r.Protocol == protocolAll || r.Protocol == protocolTCP || r.Protocol == protocolUDP
+}
+
+// NormalizeProtocol 将各云厂商的协议表示转换为统一格式
+func NormalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	switch protocol {
+	case "", "-1", "*", "all", "any":
+		return protocolAll
+	case "6":
+		return protocolTCP
+	case "17":
+		return protocolUDP
+	case "1", "icmpv6", "58":
+		return protocolICMP
+	default:
+		return protocol
+	}
+}
+
+// ParsePorts 解析以 sep 分隔的端口，支持 22、80-90 格式，all、* 或空表示全部端口
+func ParsePorts(ports string, sep string) ([]coresgcompliance.PortRange, error) {
+	ports = strings.TrimSpace(ports)
+	switch strings.ToLower(ports) {
+	case "", "all", "*", "-1":
+		return nil, nil
+	}
+
+	result := make([]coresgcompliance.PortRange, 0)
+	for _, one := range strings.Split(ports, sep) {
+		portRange, err := parsePortRange(strings.TrimSpace(one))
+		if err != nil {
+			return nil, err
+		}
+		// 包含全部端口时按全部端口处理
+		if portRange.From == 0 && portRange.To == 65535 {
+			return nil, nil
+		}
+		result = append(result, portRange)
+	}
+
+	return result, nil
+}
+
+func parsePortRange(port string) (coresgcompliance.PortRange, error) {
+	from, to, found := strings.Cut(port, "-")
+	if !found {
+		to = from
+	}
+
+	fromPort, err := strconv.ParseUint(strings.TrimSpace(from), 10, 32)
+	if err != nil {
+		return coresgcompliance.PortRange{}, fmt.Errorf("invalid port: %s", port)
+	}
+
+	toPort, err := strconv.ParseUint(strings.TrimSpace(to), 10, 32)
+	if err != nil {
+		return coresgcompliance.PortRange{}, fmt.Errorf("invalid port: %s", port)
+	}
+
+	portRange := coresgcompliance.PortRange{From: uint32(fromPort), To: uint32(toPort)}
+	if err = portRange.Validate(); err != nil {
+		return coresgcompliance.PortRange{}, err
+	}
+
+	return portRange, nil
+}
+
+// portsContain ports 是否包含 other 中的全部端口，为空表示全部端口
+func portsContain(ports, other []coresgcompliance.PortRange) bool {
+	if len(ports) == 0 {
+		return true
+	}
+
+	if len(other) == 0 {
+		return false
+	}
+
+	for _, one := range other {
+		contained := false
+		for _, port := range ports {
+			if port.Contains(one) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+
+	return true
+}
+
+// portsOverlap ports 与 other 是否有交集，为空表示全部端口
+func portsOverlap(ports, other []coresgcompliance.PortRange) bool {
+	if len(ports) == 0 || len(other) == 0 {
+		return true
+	}
+
+	for _, one := range ports {
+		for _, port := range other {
+			if one.Overlaps(port) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// portsSize 端口数量，为空表示全部端口
+func portsSize(ports []coresgcompliance.PortRange) uint32 {
+	if len(ports) == 0 {
+		return 65535
+	}
+
+	var size uint32
+	for _, one := range ports {
+		size += one.Size()
+	}
+
+	return size
+}
+
+func isAnyAddress(address string) bool {
+	switch strings.ToLower(strings.TrimSpace(address)) {
+	case "0.0.0.0/0", "::/0", "*", "internet", "any":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseCIDR 解析地址，单个IP按照 /32 或 /128 处理，全部地址按照 0.0.0.0/0 处理
+func parseCIDR(address string) (*net.IPNet, bool) {
+	address = strings.TrimSpace(address)
+	if isAnyAddress(address) {
+		if strings.Contains(address, ":") {
+			_, ipNet, _ := net.ParseCIDR("::/0")
+			return ipNet, true
+		}
+		_, ipNet, _ := net.ParseCIDR("0.0.0.0/0")
+		return ipNet, true
+	}
+
+	if _, ipNet, err := net.ParseCIDR(address); err == nil {
+		return ipNet, true
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, false
+	}
+
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, true
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, true
+}
+
+// cidrContains a 是否包含 b
+func cidrContains(a, b *net.IPNet) bool {
+	aOnes, aBits := a.Mask.Size()
+	bOnes, bBits := b.Mask.Size()
+	if aBits != bBits {
+		return false
+	}
+
+	return aOnes <= bOnes && a.Contains(b.IP)
+}
+
+// cidrOverlap a 与 b 是否有交集
+func cidrOverlap(a, b *net.IPNet) bool {
+	return cidrContains(a, b) || cidrContains(b, a)
+}
+
+// addressesContain addresses 是否包含 other 中的全部地址
+func addressesContain(addresses, other []string) bool {
+	if len(other) == 0 {
+		return false
+	}
+
+	for _, one := range other {
+		otherNet, ok := parseCIDR(one)
+		if !ok {
+			return false
+		}
+
+		contained := false
+		for _, address := range addresses {
+			ipNet, ok := parseCIDR(address)
+			if ok && cidrContains(ipNet, otherNet) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+
+	return true
+}
+
+// addressesOverlap addresses 与 cidrs 是否有交集
+func addressesOverlap(addresses, cidrs []string) bool {
+	for _, address := range addresses {
+		ipNet, ok := parseCIDR(address)
+		if !ok {
+			continue
+		}
+
+		for _, cidr := range cidrs {
+			cidrNet, ok := parseCIDR(cidr)
+			if ok && cidrOverlap(ipNet, cidrNet) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
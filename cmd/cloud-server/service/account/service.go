@@ -63,6 +63,8 @@ func InitAccountService(c *capability.Capability) {
 	h.Add("SyncBizCloudResourceByCond", http.MethodPost,
 		"/bizs/{bk_biz_id}/vendors/{vendor}/accounts/{account_id}/resources/{res}/sync_by_cond",
 		svc.SyncBizCloudResourceByCond)
+	h.Add("SyncCloudResourcePlan", http.MethodPost,
+		"/vendors/{vendor}/accounts/{account_id}/resources/{res}/sync_plan", svc.SyncCloudResourcePlan)
 
 	// 获取账号配额
 	h.Add("GetBizTCloudZoneQuota", http.MethodPost,
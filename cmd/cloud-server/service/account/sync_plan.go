@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package account
+
+import (
+	"fmt"
+
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// syncPlanPaths 支持计划模式的资源在 hc-service 中的同步接口路径，负载均衡的同步还不支持计划模式
+var syncPlanPaths = map[enumor.Vendor]map[enumor.CloudResourceType]string{
+	enumor.TCloud: {
+		enumor.VpcCloudResType:           "/vpcs/sync",
+		enumor.SubnetCloudResType:        "/subnets/sync",
+		enumor.DiskCloudResType:          "/disks/sync",
+		enumor.CvmCloudResType:           "/cvms/with/relation_resources/sync",
+		enumor.SecurityGroupCloudResType: "/security_groups/sync",
+		enumor.EipCloudResType:           "/eips/sync",
+		enumor.RouteTableCloudResType:    "/route_tables/sync",
+		enumor.ZoneCloudResType:          "/zones/sync",
+		enumor.CertCloudResType:          "/certs/sync",
+	},
+	enumor.Aws: {
+		enumor.VpcCloudResType:           "/vpcs/sync",
+		enumor.SubnetCloudResType:        "/subnets/sync",
+		enumor.DiskCloudResType:          "/disks/sync",
+		enumor.CvmCloudResType:           "/cvms/with/relation_resources/sync",
+		enumor.SecurityGroupCloudResType: "/security_groups/sync",
+		enumor.EipCloudResType:           "/eips/sync",
+		enumor.RouteTableCloudResType:    "/route_tables/sync",
+		enumor.ZoneCloudResType:          "/zones/sync",
+	},
+	enumor.HuaWei: {
+		enumor.VpcCloudResType:           "/vpcs/sync",
+		enumor.SubnetCloudResType:        "/subnets/sync",
+		enumor.DiskCloudResType:          "/disks/sync",
+		enumor.CvmCloudResType:           "/cvms/with/relation_resources/sync",
+		enumor.SecurityGroupCloudResType: "/security_groups/sync",
+		enumor.EipCloudResType:           "/eips/sync",
+		enumor.RouteTableCloudResType:    "/route_tables/sync",
+		enumor.ZoneCloudResType:          "/zones/sync",
+	},
+}
+
+// SyncCloudResourcePlan 计算同步指定资源会产生的差异，返回各资源类型新增、更新和删除的资源，不修改db数据
+func (a *accountSvc) SyncCloudResourcePlan(cts *rest.Contexts) (any, error) {
+	accountID := cts.PathParameter("account_id").String()
+	resName := enumor.CloudResourceType(cts.PathParameter("res").String())
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+
+	req := new(proto.ResSyncPlanReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 校验用户有该账号的访问权限
+	if err := a.checkPermission(cts, meta.Find, accountID); err != nil {
+		return nil, err
+	}
+
+	baseInfo, err := a.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.AccountCloudResType, accountID)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if baseInfo.Vendor != vendor {
+		return nil, errf.Newf(errf.InvalidParameter, "account not found by vendor: %s", vendor)
+	}
+
+	syncPath, exists := syncPlanPaths[vendor][resName]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "sync plan does not support vendor: %s, resource: %s",
+			vendor, resName)
+	}
+
+	result := &sync.SyncPlanResult{Resources: make(map[string]*sync.ResourcePlan)}
+	for _, region := range req.Regions {
+		syncReq, err := buildSyncPlanReq(vendor, accountID, region, req.CloudIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		plan, err := a.client.HCService().SyncPlan(cts.Kit, vendor, syncPath, syncReq)
+		if err != nil {
+			logs.Errorf("[%s] get sync plan failed, err: %v, account: %s, res: %s, region: %s, rid: %s",
+				vendor, err, accountID, resName, region, cts.Kit.Rid)
+			return nil, err
+		}
+		result.Merge(plan)
+	}
+
+	return result, nil
+}
+
+func buildSyncPlanReq(vendor enumor.Vendor, accountID, region string, cloudIDs []string) (any, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return &sync.TCloudSyncReq{AccountID: accountID, Region: region, CloudIDs: cloudIDs}, nil
+	case enumor.Aws:
+		return &sync.AwsSyncReq{AccountID: accountID, Region: region, CloudIDs: cloudIDs}, nil
+	case enumor.HuaWei:
+		return &sync.HuaWeiSyncReq{AccountID: accountID, Region: region, CloudIDs: cloudIDs}, nil
+	default:
+		return nil, fmt.Errorf("sync plan does not support vendor: %s", vendor)
+	}
+}
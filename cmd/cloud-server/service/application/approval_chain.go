@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// ListApprovalChains list native approval chains.
+func (a *applicationSvc) ListApprovalChains(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return a.client.DataService().Global.ListApprovalChain(cts.Kit, req)
+}
+
+// CreateApprovalChain create native approval chain.
+func (a *applicationSvc) CreateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Update); err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.ApprovalChainCreateReq{
+		ApplicationType: req.ApplicationType,
+		BkBizID:         req.BkBizID,
+		Nodes:           req.Nodes,
+		Memo:            req.Memo,
+	}
+	result, err := a.client.DataService().Global.CreateApprovalChain(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create approval chain failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateApprovalChain update native approval chain, only affect tickets created after update.
+func (a *applicationSvc) UpdateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.ApprovalChainUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Update); err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.ApprovalChainUpdateReq{ID: id, Nodes: req.Nodes, Memo: req.Memo}
+	if err := a.client.DataService().Global.UpdateApprovalChain(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update approval chain failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteApprovalChain delete native approval chain.
+func (a *applicationSvc) DeleteApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := a.client.DataService().Global.BatchDeleteApprovalChain(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete approval chain failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
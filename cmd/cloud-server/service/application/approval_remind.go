@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"time"
+
+	"hcm/cmd/cloud-server/logics/tenant"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+)
+
+// TimingRemindApprovalTicket 定时提醒审批人处理超过提醒间隔仍未审批的内置审批单
+func TimingRemindApprovalTicket(c *client.ClientSet, sd serviced.State, cmsiCli cmsi.Client, bkHcmUrl string,
+	interval time.Duration) {
+
+	svc := &applicationSvc{client: c, cmsiCli: cmsiCli, bkHcmUrl: bkHcmUrl}
+	for {
+		time.Sleep(interval)
+
+		if !sd.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		tenantIDs, err := tenant.ListAllTenantID(kt, c.DataService())
+		if err != nil {
+			logs.Errorf("failed to list all tenant ids, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		for _, tenantID := range tenantIDs {
+			svc.remindApprovalTicket(kt.NewSubKitWithTenant(tenantID), interval)
+		}
+	}
+}
+
+func (a *applicationSvc) remindApprovalTicket(kt *kit.Kit, interval time.Duration) {
+	now := time.Now()
+	deadline := now.Add(-interval).Format(constant.TimeStdFormat)
+
+	lastID := ""
+	for {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("status", enumor.ApprovalTicketPending),
+				tools.RuleLessThanEqual("reminded_at", deadline),
+				tools.RuleIDGreaterThan(lastID),
+			),
+			Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		}
+		result, err := a.client.DataService().Global.ListApprovalTicket(kt, listReq)
+		if err != nil {
+			logs.Errorf("list approval ticket to remind failed, err: %v, rid: %s", err, kt.Rid)
+			return
+		}
+
+		for _, ticket := range result.Details {
+			// 只更新提醒时间，不变更版本号，避免与审批操作冲突
+			updateReq := &dataproto.ApprovalTicketUpdateReq{
+				ID:         ticket.ID,
+				RemindedAt: now.Format(constant.TimeStdFormat),
+			}
+			if err = a.client.DataService().Global.UpdateApprovalTicket(kt, updateReq); err != nil {
+				logs.Errorf("update approval ticket reminded time failed, err: %v, id: %s, rid: %s", err, ticket.ID,
+					kt.Rid)
+				continue
+			}
+
+			a.notifyApprovers(kt, ticket.Title, ticket.ApplicationID, ticket.Approvers)
+		}
+
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			return
+		}
+		lastID = result.Details[len(result.Details)-1].ID
+	}
+}
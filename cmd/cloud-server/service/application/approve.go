@@ -38,6 +38,7 @@ import (
 	"hcm/cmd/cloud-server/service/application/handlers/load_balancer/tcloud"
 	createmainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/create-main-account"
 	updatemainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/update-main-account"
+	sgruletcloud "hcm/cmd/cloud-server/service/application/handlers/security_group_rule/tcloud"
 	awsvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/aws"
 	azurevpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/azure"
 	gcpvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/gcp"
@@ -277,6 +278,21 @@ func (a *applicationSvc) getHandlerOfCreateLoadBalancer(opt *handlers.HandlerOpt
 	}
 }
 
+func (a *applicationSvc) getHandlerOfCreateSGRule(opt *handlers.HandlerOption, vendor enumor.Vendor,
+	application *dataproto.ApplicationResp) (handlers.ApplicationHandler, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		req, err := parseReqFromApplicationContent[proto.TCloudSGRuleCreateReq](application.Content)
+		if err != nil {
+			return nil, err
+		}
+		return sgruletcloud.NewApplicationOfCreateTCloudSGRule(opt, req), nil
+	default:
+		return nil, fmt.Errorf("not support handler of create %s security group rule", vendor)
+	}
+}
+
 func (a *applicationSvc) getHandlerByApplication(cts *rest.Contexts, application *dataproto.ApplicationResp) (
 	handlers.ApplicationHandler, error) {
 
@@ -307,6 +323,8 @@ func (a *applicationSvc) getHandlerByApplication(cts *rest.Contexts, application
 		return a.getHandlerOfCreateDisk(opt, vendor, application)
 	case enumor.CreateLoadBalancer:
 		return a.getHandlerOfCreateLoadBalancer(opt, vendor, application)
+	case enumor.CreateSecurityGroupRule:
+		return a.getHandlerOfCreateSGRule(opt, vendor, application)
 	case enumor.CreateMainAccount:
 		req, err := parseReqFromApplicationContent[proto.MainAccountCreateReq](application.Content)
 		if err != nil {
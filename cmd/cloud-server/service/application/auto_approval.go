@@ -0,0 +1,256 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/cmd/cloud-server/service/application/handlers"
+	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core"
+	coreapproval "hcm/pkg/api/core/approval"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/uuid"
+)
+
+// autoApprovalSNPrefix 自动审批生成的申请单号前缀
+const autoApprovalSNPrefix = "AUTO"
+
+func genAutoApprovalSN() string {
+	return autoApprovalSNPrefix + strings.ReplaceAll(uuid.UUID(), "-", "")
+}
+
+// ListAutoApprovalPolicies list application auto approval policies.
+func (a *applicationSvc) ListAutoApprovalPolicies(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return a.client.DataService().Global.ListAutoApprovalPolicy(cts.Kit, req)
+}
+
+// CreateAutoApprovalPolicy create application auto approval policy.
+func (a *applicationSvc) CreateAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.AutoApprovalPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Update); err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.AutoApprovalPolicyCreateReq{
+		Name:            req.Name,
+		ApplicationType: req.ApplicationType,
+		Priority:        req.Priority,
+		Enabled:         req.Enabled,
+		Conditions:      req.Conditions,
+		Memo:            req.Memo,
+	}
+	result, err := a.client.DataService().Global.CreateAutoApprovalPolicy(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create auto approval policy failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateAutoApprovalPolicy update application auto approval policy.
+func (a *applicationSvc) UpdateAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.AutoApprovalPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Update); err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.AutoApprovalPolicyUpdateReq{
+		ID:         id,
+		Name:       req.Name,
+		Priority:   req.Priority,
+		Enabled:    req.Enabled,
+		Conditions: req.Conditions,
+		Memo:       req.Memo,
+	}
+	if err := a.client.DataService().Global.UpdateAutoApprovalPolicy(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update auto approval policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteAutoApprovalPolicy delete application auto approval policy.
+func (a *applicationSvc) DeleteAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := a.checkActionPermission(cts, meta.Application, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := a.client.DataService().Global.BatchDeleteAutoApprovalPolicy(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete auto approval policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// matchAutoApprovalPolicy 按优先级匹配申请单类型已启用的自动审批策略，未命中时返回nil
+func (a *applicationSvc) matchAutoApprovalPolicy(cts *rest.Contexts, handler handlers.ApplicationHandler) (
+	*coreapproval.AutoApprovalPolicy, error) {
+
+	factsHandler, ok := handler.(handlers.AutoApprovalHandler)
+	if !ok {
+		return nil, nil
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("application_type", handler.GetType()),
+			tools.RuleEqual("enabled", true),
+		),
+		Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "priority", Order: core.Ascending},
+	}
+	result, err := a.client.DataService().Global.ListAutoApprovalPolicy(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list auto approval policy failed, err: %v, type: %s, rid: %s", err, handler.GetType(),
+			cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	// 只有策略配置了费用条件时才需要询价
+	withCost := false
+	for _, policy := range result.Details {
+		if policy.Conditions.MaxCost != nil {
+			withCost = true
+			break
+		}
+	}
+
+	facts, err := factsHandler.GetAutoApprovalFacts(withCost)
+	if err != nil && withCost {
+		// 询价失败时退化为不带费用的申请信息，仅跳过配置了费用条件的策略
+		logs.Errorf("get auto approval facts with cost failed, err: %v, type: %s, rid: %s", err,
+			handler.GetType(), cts.Kit.Rid)
+		withCost = false
+		facts, err = factsHandler.GetAutoApprovalFacts(false)
+	}
+	if err != nil {
+		// 获取申请信息失败时不影响提单，走正常审批流程
+		logs.Errorf("get auto approval facts failed, err: %v, type: %s, rid: %s", err, handler.GetType(),
+			cts.Kit.Rid)
+		return nil, nil
+	}
+
+	for i := range result.Details {
+		policy := &result.Details[i]
+		if !withCost && policy.Conditions.MaxCost != nil {
+			logs.V(3).Infof("auto approval policy %s skipped, reason: cost is unavailable, rid: %s", policy.ID,
+				cts.Kit.Rid)
+			continue
+		}
+		matched, reason := policy.Conditions.Match(facts)
+		if matched {
+			logs.Infof("application matched auto approval policy: %s(%s), type: %s, applicant: %s, rid: %s",
+				policy.Name, policy.ID, handler.GetType(), cts.Kit.User, cts.Kit.Rid)
+			return policy, nil
+		}
+		logs.V(3).Infof("auto approval policy %s not matched, reason: %s, rid: %s", policy.ID, reason, cts.Kit.Rid)
+	}
+
+	return nil, nil
+}
+
+// createWithAutoApproval 命中自动审批策略时创建交付中的申请单并直接交付
+func (a *applicationSvc) createWithAutoApproval(cts *rest.Contexts, req *proto.CreateCommonReq,
+	handler handlers.ApplicationHandler, applicationType enumor.ApplicationType,
+	policy *coreapproval.AutoApprovalPolicy) (*core.CreateResult, error) {
+
+	createReq, err := genApplicationCreateReq(cts, req, handler, genAutoApprovalSN(), applicationType,
+		enumor.ApplicationSourceAutoApproval)
+	if err != nil {
+		return nil, err
+	}
+	createReq.Status = enumor.Delivering
+	createReq.AutoApprovalPolicyID = policy.ID
+
+	result, err := a.client.DataService().Global.Application.CreateApplication(cts.Kit.Ctx, cts.Kit.Header(),
+		createReq)
+	if err != nil {
+		logs.Errorf("create auto approved application failed, err: %v, policy: %s, rid: %s", err, policy.ID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	application, err := a.client.DataService().Global.Application.GetApplication(cts.Kit.Ctx, cts.Kit.Header(),
+		result.ID)
+	if err != nil {
+		logs.Errorf("get auto approved application failed, err: %v, id: %s, rid: %s", err, result.ID, cts.Kit.Rid)
+		if updateErr := a.updateStatusWithDetail(cts, result.ID, enumor.DeliverError,
+			fmt.Sprintf(`{"error": "get application failed, err: %v"}`, err)); updateErr != nil {
+			logs.Errorf("update application status failed, err: %v, id: %s, rid: %s", updateErr, result.ID,
+				cts.Kit.Rid)
+		}
+		return nil, err
+	}
+
+	// TODO: 与ITSM回调一致，暂时用goroutine异步执行交付
+	go a.deliver(cts, application)
+
+	return result, nil
+}
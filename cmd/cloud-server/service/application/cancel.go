@@ -43,10 +43,23 @@ func (a *applicationSvc) CancelApplication(cts *rest.Contexts) (interface{}, err
 		)
 	}
 
-	// 根据SN调用ITSM接口撤销单据
-	err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+	// 自动审批的申请单创建后直接交付，不存在可撤销的审批
+	if application.Source == enumor.ApplicationSourceAutoApproval {
+		return nil, errf.Newf(errf.InvalidParameter, "auto approved application %s can not be cancelled",
+			applicationID)
+	}
+
+	if application.Source == enumor.ApplicationSourceNative {
+		// 撤销内置审批单
+		if err = a.cancelApprovalTicket(cts.Kit, applicationID); err != nil {
+			return nil, err
+		}
+	} else {
+		// 根据SN调用ITSM接口撤销单据
+		err = a.itsmCli.WithdrawTicket(cts.Kit, application.SN, cts.Kit.User)
+		if err != nil {
+			return nil, fmt.Errorf("call itsm cancel ticket api failed, err: %v", err)
+		}
 	}
 
 	// 更新状态
@@ -37,6 +37,7 @@ import (
 	lbtcloud "hcm/cmd/cloud-server/service/application/handlers/load_balancer/tcloud"
 	createmainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/create-main-account"
 	updatemainaccount "hcm/cmd/cloud-server/service/application/handlers/main-account/update-main-account"
+	sgruletcloud "hcm/cmd/cloud-server/service/application/handlers/security_group_rule/tcloud"
 	awsvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/aws"
 	azurevpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/azure"
 	gcpvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/gcp"
@@ -87,20 +88,35 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 	if err := handler.CheckReq(); err != nil {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
+	// 匹配自动审批策略，需在预处理数据前执行，预处理会加密敏感信息导致无法询价
+	policy, err := a.matchAutoApprovalPolicy(cts, handler)
+	if err != nil {
+		return nil, err
+	}
 	// 预处理数据
-	if err := handler.PrepareReq(); err != nil {
+	if err = handler.PrepareReq(); err != nil {
 		return nil, err
 	}
 	// 查询审批流程服务ID
 	applicationType := handler.GetType()
 
+	// 命中自动审批策略时无需审批，直接交付
+	if policy != nil {
+		return a.createWithAutoApproval(cts, req, handler, applicationType, policy)
+	}
+
+	// 使用内置审批引擎时，不再依赖ITSM
+	if a.approvalEngine == enumor.ApplicationSourceNative {
+		return a.createWithNativeApproval(cts, req, handler, applicationType)
+	}
+
 	// 调用ITSM创建单据
 	sn, err := a.createItsmTicket(cts, handler, applicationType)
 	if err != nil {
 		return nil, fmt.Errorf("call itsm create ticket api failed, err: %w", err)
 	}
 
-	result, err := a.createApplication(cts, req, handler, sn, applicationType)
+	result, err := a.createApplication(cts, req, handler, sn, applicationType, enumor.ApplicationSourceITSM)
 	if err != nil {
 		return nil, err
 	}
@@ -109,8 +125,22 @@ func (a *applicationSvc) create(cts *rest.Contexts, req *proto.CreateCommonReq,
 
 // createApplicationRequest ...
 func (a *applicationSvc) createApplication(cts *rest.Contexts, req *proto.CreateCommonReq,
-	handler handlers.ApplicationHandler, sn string, applicationType enumor.ApplicationType) (*core.CreateResult, error) {
-	// 调用DB创建单据
+	handler handlers.ApplicationHandler, sn string, applicationType enumor.ApplicationType,
+	source enumor.ApplicationSource) (*core.CreateResult, error) {
+
+	createReq, err := genApplicationCreateReq(cts, req, handler, sn, applicationType, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.client.DataService().Global.Application.CreateApplication(cts.Kit.Ctx, cts.Kit.Header(), createReq)
+}
+
+// genApplicationCreateReq 生成DB创建单据的请求
+func genApplicationCreateReq(cts *rest.Contexts, req *proto.CreateCommonReq, handler handlers.ApplicationHandler,
+	sn string, applicationType enumor.ApplicationType, source enumor.ApplicationSource) (
+	*dataproto.ApplicationCreateReq, error) {
+
 	content, err := json.MarshalToString(handler.GenerateApplicationContent())
 	if err != nil {
 		return nil, errf.NewFromErr(
@@ -119,28 +149,24 @@ func (a *applicationSvc) createApplication(cts *rest.Contexts, req *proto.Create
 		)
 	}
 
-	// 主机、硬盘、VPC、负载均衡需要记录业务ID
+	// 主机、硬盘、VPC、负载均衡、安全组规则需要记录业务ID
 	var bkBizIDs = make([]int64, 0)
 	if applicationType == enumor.CreateCvm || applicationType == enumor.CreateDisk ||
 		applicationType == enumor.CreateVpc || applicationType == enumor.CreateLoadBalancer ||
-		applicationType == enumor.AddAccount {
+		applicationType == enumor.CreateSecurityGroupRule || applicationType == enumor.AddAccount {
 		bkBizIDs = handler.GetBkBizIDs()
 	}
-	return a.client.DataService().Global.Application.CreateApplication(
-		cts.Kit.Ctx,
-		cts.Kit.Header(),
-		&dataproto.ApplicationCreateReq{
-			SN:             sn,
-			Source:         enumor.ApplicationSourceITSM,
-			Type:           applicationType,
-			Status:         enumor.Pending,
-			BkBizIDs:       bkBizIDs,
-			Applicant:      cts.Kit.User,
-			Content:        content,
-			DeliveryDetail: "{}",
-			Memo:           req.Remark,
-		},
-	)
+	return &dataproto.ApplicationCreateReq{
+		SN:             sn,
+		Source:         source,
+		Type:           applicationType,
+		Status:         enumor.Pending,
+		BkBizIDs:       bkBizIDs,
+		Applicant:      cts.Kit.User,
+		Content:        content,
+		DeliveryDetail: "{}",
+		Memo:           req.Remark,
+	}, nil
 }
 
 // createItsmTicket 调用ITSM创建单据
@@ -427,6 +453,37 @@ func (a *applicationSvc) CreateForCreateLB(cts *rest.Contexts) (interface{}, err
 	return nil, nil
 }
 
+// CreateForCreateSGRule 创建安全组规则申请单
+func (a *applicationSvc) CreateForCreateSGRule(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	commReq, err := decodeCommonReqAndValidate(cts)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkApplyResPermission(cts, meta.SecurityGroupRule); err != nil {
+		return nil, err
+	}
+
+	opt := a.getHandlerOption(cts)
+
+	switch vendor {
+	case enumor.TCloud:
+		req, err := parseReqFromRequestBody[proto.TCloudSGRuleCreateReq](cts)
+		if err != nil {
+			return nil, err
+		}
+		handler := sgruletcloud.NewApplicationOfCreateTCloudSGRule(opt, req)
+		return a.create(cts, commReq, handler)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support", vendor)
+	}
+}
+
 // CreateForCreateMainAccount ...
 func (a *applicationSvc) CreateForCreateMainAccount(cts *rest.Contexts) (interface{}, error) {
 	req, err := parseReqFromRequestBody[proto.MainAccountCreateReq](cts)
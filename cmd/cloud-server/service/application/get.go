@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/rest"
@@ -53,14 +54,9 @@ func (a *applicationSvc) GetApplication(cts *rest.Contexts) (interface{}, error)
 		}
 	}
 
-	// 查询审批链接
-	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
-	if err != nil {
-		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
-	}
-
-	return &proto.ApplicationGetResp{
+	resp := &proto.ApplicationGetResp{
 		ID:             application.ID,
+		Source:         application.Source,
 		SN:             application.SN,
 		Type:           application.Type,
 		Status:         application.Status,
@@ -69,6 +65,29 @@ func (a *applicationSvc) GetApplication(cts *rest.Contexts) (interface{}, error)
 		DeliveryDetail: application.DeliveryDetail,
 		Memo:           application.Memo,
 		Revision:       application.Revision,
-		TicketUrl:      ticket.TicketURL,
-	}, nil
+	}
+
+	// 内置审批的申请单返回审批单信息
+	if application.Source == enumor.ApplicationSourceNative {
+		resp.Approval, err = a.getApprovalTicket(cts.Kit, application.ID)
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	// 自动审批的申请单没有审批单据
+	if application.Source == enumor.ApplicationSourceAutoApproval {
+		resp.AutoApprovalPolicyID = application.AutoApprovalPolicyID
+		return resp, nil
+	}
+
+	// 查询审批链接
+	ticket, err := a.itsmCli.GetTicketResult(cts.Kit, application.SN)
+	if err != nil {
+		return nil, fmt.Errorf("call itsm get ticket url failed, err: %v", err)
+	}
+	resp.TicketUrl = ticket.TicketURL
+
+	return resp, nil
 }
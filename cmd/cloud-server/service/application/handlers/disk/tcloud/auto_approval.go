@@ -0,0 +1,44 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/common"
+	coreapproval "hcm/pkg/api/core/approval"
+)
+
+// GetAutoApprovalFacts 获取匹配自动审批策略所需的申请信息
+func (a *ApplicationOfCreateTCloudDisk) GetAutoApprovalFacts(withCost bool) (*coreapproval.AutoApprovalFacts, error) {
+	facts := a.NewAutoApprovalFacts(a.GetBkBizIDs(), a.req.Region, a.req.DiskType, int64(a.req.DiskCount))
+	if !withCost {
+		return facts, nil
+	}
+
+	// 通过询价接口获取云盘费用
+	price, err := a.Client.HCService().TCloud.Disk.InquiryPrice(a.Cts.Kit, common.ConvTCloudDiskCreateReq(a.req))
+	if err != nil {
+		return nil, fmt.Errorf("inquiry price failed, err: %v", err)
+	}
+	facts.Cost = &price.DiscountPrice
+
+	return facts, nil
+}
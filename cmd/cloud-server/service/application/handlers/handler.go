@@ -20,6 +20,7 @@
 package handlers
 
 import (
+	coreapproval "hcm/pkg/api/core/approval"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/api-gateway/itsm"
 )
@@ -59,3 +60,9 @@ type ApplicationHandler interface {
 	// GetBkBizIDs 获取当前的业务IDs
 	GetBkBizIDs() []int64
 }
+
+// AutoApprovalHandler 支持自动审批的申请单Handler需实现该接口，在创建审批单前匹配自动审批策略
+type AutoApprovalHandler interface {
+	// GetAutoApprovalFacts 获取匹配自动审批策略所需的申请信息，withCost 为 true 时需通过询价接口获取费用
+	GetAutoApprovalFacts(withCost bool) (*coreapproval.AutoApprovalFacts, error)
+}
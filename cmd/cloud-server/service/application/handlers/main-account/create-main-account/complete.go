@@ -80,6 +80,8 @@ func (a *ApplicationOfCreateMainAccount) Complete() (enumor.ApplicationStatus, m
 		accountID, err = a.createForZenlayer(&rootAccount.BaseRootAccount)
 	case enumor.Kaopu:
 		accountID, err = a.createForKaopu(&rootAccount.BaseRootAccount)
+	case enumor.TCloud:
+		accountID, err = a.createForTCloud(&rootAccount.BaseRootAccount)
 	}
 	if err != nil {
 		logs.Errorf("create main account for [%s] failed, err: %v, rid: %s", a.req.Vendor, err, a.Cts.Kit.Rid)
@@ -380,6 +382,45 @@ func (a *ApplicationOfCreateMainAccount) createForKaopu(rootAccount *protocore.B
 	return result.ID, nil
 }
 
+// createForTCloud creates a TCloud main account.
+func (a *ApplicationOfCreateMainAccount) createForTCloud(rootAccount *protocore.BaseRootAccount) (string, error) {
+	req := a.req
+	comReq := a.completeReq
+
+	extension := &dataproto.TCloudMainAccountExtensionCreateReq{
+		CloudMainAccountID:   comReq.Extension[a.Vendor().GetMainAccountIDFieldName()],
+		CloudMainAccountName: comReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
+		CloudInitPassword:    comReq.Extension[a.Vendor().GetMainAccountInitPasswordFieldName()],
+	}
+	extension.EncryptSecretKey(a.Cipher)
+
+	result, err := a.Client.DataService().TCloud.MainAccount.Create(
+		a.Cts.Kit,
+		&dataproto.MainAccountCreateReq[dataproto.TCloudMainAccountExtensionCreateReq]{
+			Name:              a.completeReq.Extension[a.Vendor().GetMainAccountNameFieldName()],
+			CloudID:           a.completeReq.Extension[a.Vendor().GetMainAccountIDFieldName()],
+			Email:             req.Email,
+			Managers:          req.Managers,
+			BakManagers:       req.BakManagers,
+			Site:              req.Site,
+			BusinessType:      req.BusinessType,
+			Status:            enumor.MainAccountStatusRUNNING,
+			ParentAccountName: rootAccount.Name,
+			ParentAccountID:   rootAccount.ID,
+			DeptID:            req.DeptID,
+			BkBizID:           req.BkBizID,
+			OpProductID:       req.OpProductID,
+			Memo:              req.Memo,
+			Extension:         extension,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return result.ID, nil
+}
+
 func (a *ApplicationOfCreateMainAccount) sendMail(account *dataproto.MainAccountGetBaseResult) {
 	if account == nil {
 		logs.Errorf("send mail failed, account should not be nil when send email, rid: %s", a.Cts.Kit.Rid)
@@ -403,6 +444,8 @@ func (a *ApplicationOfCreateMainAccount) sendMail(account *dataproto.MainAccount
 		loginUrl = ZenlayerLoginAddress
 	case enumor.Kaopu:
 		loginUrl = KaopuLoginAddress
+	case enumor.TCloud:
+		loginUrl = TCloudLoginAddress
 	default:
 		logs.Errorf("send mail failed, unknown vendor: %s, rid: %s", account.Vendor, a.Cts.Kit.Rid)
 		return
@@ -26,6 +26,7 @@ const (
 	AzureLoginAddress    = "https://portal.azure.com/#blade/Microsoft_AAD_IAM/ActiveDirectoryMenuBlade/Overview"
 	ZenlayerLoginAddress = "https://console.zenlayer.com/auth/login"
 	KaopuLoginAddress    = "https://console.kaopuyun.com/user/#/login"
+	TCloudLoginAddress   = "https://cloud.tencent.com/login"
 
 	EmailTitleTemplate   = "【HCM】 %s账号创建成功通知"
 	EmailContentTemplate = `<!DOCTYPE html>
@@ -36,7 +36,7 @@ func (a *ApplicationOfUpdateMainAccount) Deliver() (enumor.ApplicationStatus, ma
 		err error
 	)
 	switch req.Vendor {
-	case enumor.Aws, enumor.Gcp, enumor.HuaWei, enumor.Azure, enumor.Zenlayer, enumor.Kaopu:
+	case enumor.Aws, enumor.Gcp, enumor.HuaWei, enumor.Azure, enumor.Zenlayer, enumor.Kaopu, enumor.TCloud:
 		err = a.update()
 	default:
 		err = errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("no support vendor: %s", req.Vendor))
@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	coreapproval "hcm/pkg/api/core/approval"
+)
+
+// GetAutoApprovalFacts 获取匹配自动审批策略所需的申请信息，安全组规则不支持询价
+func (a *ApplicationOfCreateTCloudSGRule) GetAutoApprovalFacts(_ bool) (*coreapproval.AutoApprovalFacts, error) {
+	rules, err := a.complianceRules()
+	if err != nil {
+		return nil, err
+	}
+
+	facts := &coreapproval.AutoApprovalFacts{
+		BkBizIDs: a.GetBkBizIDs(),
+		Vendor:   a.Vendor(),
+		Count:    int64(len(rules)),
+	}
+	if a.sg != nil {
+		facts.Regions = []string{a.sg.Region}
+	}
+
+	// 引用了地址模版、安全组或端口模版的规则无法确定具体网段和端口，此时不提供对应信息，配置了网段或端口条件的策略不会命中
+	cidrs := make([]string, 0)
+	portRanges := make([]coreapproval.PortRange, 0)
+	cidrKnown, portKnown := true, true
+	for _, rule := range rules {
+		if len(rule.AddressRef) != 0 || len(rule.Addresses) == 0 {
+			cidrKnown = false
+		}
+		cidrs = append(cidrs, rule.Addresses...)
+
+		if len(rule.PortRef) != 0 {
+			portKnown = false
+		}
+		// 端口为空表示全部端口
+		if len(rule.Ports) == 0 {
+			portRanges = append(portRanges, coreapproval.PortRange{From: 0, To: 65535})
+		}
+		for _, one := range rule.Ports {
+			portRanges = append(portRanges, coreapproval.PortRange{From: one.From, To: one.To})
+		}
+	}
+
+	if cidrKnown {
+		facts.CIDRs = cidrs
+	}
+	if portKnown {
+		facts.PortRanges = portRanges
+	}
+
+	return facts, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"testing"
+
+	"hcm/cmd/cloud-server/service/application/handlers"
+	cloudserver "hcm/pkg/api/cloud-server"
+	proto "hcm/pkg/api/cloud-server/application"
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHandler(rules ...cloudserver.TCloudSecurityGroupRule) *ApplicationOfCreateTCloudSGRule {
+	req := &proto.TCloudSGRuleCreateReq{BkBizID: 100, SecurityGroupID: "sg-id"}
+	req.IngressRuleSet = rules
+	handler := NewApplicationOfCreateTCloudSGRule(&handlers.HandlerOption{}, req)
+	handler.sg = &types.CloudResourceBasicInfo{ID: "sg-id", Vendor: enumor.TCloud, AccountID: "account",
+		BkBizID: 100, Region: "ap-guangzhou"}
+	return handler
+}
+
+func TestGetAutoApprovalFactsMatchCIDRAndPort(t *testing.T) {
+	condition := coreapproval.AutoApprovalCondition{
+		Vendors:    []enumor.Vendor{enumor.TCloud},
+		CIDRs:      []string{"10.0.0.0/8"},
+		PortRanges: []coreapproval.PortRange{{From: 80, To: 80}, {From: 8000, To: 9000}},
+	}
+
+	cases := []struct {
+		name    string
+		rule    cloudserver.TCloudSecurityGroupRule
+		matched bool
+	}{
+		{
+			name: "cidr and ports in range",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"),
+				Port: cvt.ValToPtr("80,8080-8090"), IPv4Cidr: cvt.ValToPtr("10.1.0.0/16"), Action: "ACCEPT"},
+			matched: true,
+		},
+		{
+			name: "single ip in range",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("8080"),
+				IPv4Cidr: cvt.ValToPtr("10.0.0.1"), Action: "ACCEPT"},
+			matched: true,
+		},
+		{
+			name: "cidr out of range",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("80"),
+				IPv4Cidr: cvt.ValToPtr("0.0.0.0/0"), Action: "ACCEPT"},
+			matched: false,
+		},
+		{
+			name: "port out of range",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("22"),
+				IPv4Cidr: cvt.ValToPtr("10.1.0.0/16"), Action: "ACCEPT"},
+			matched: false,
+		},
+		{
+			name: "all ports",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("ALL"),
+				IPv4Cidr: cvt.ValToPtr("10.1.0.0/16"), Action: "ACCEPT"},
+			matched: false,
+		},
+		{
+			name: "address template",
+			rule: cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("80"),
+				CloudAddressID: cvt.ValToPtr("ipm-1"), Action: "ACCEPT"},
+			matched: false,
+		},
+	}
+
+	for _, c := range cases {
+		facts, err := newTestHandler(c.rule).GetAutoApprovalFacts(false)
+		assert.NoError(t, err, c.name)
+
+		matched, reason := condition.Match(facts)
+		assert.Equal(t, c.matched, matched, "%s, reason: %s", c.name, reason)
+	}
+}
+
+func TestGetAutoApprovalFacts(t *testing.T) {
+	handler := newTestHandler(
+		cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("TCP"), Port: cvt.ValToPtr("443"),
+			IPv4Cidr: cvt.ValToPtr("10.0.0.0/24"), Action: "ACCEPT"},
+		cloudserver.TCloudSecurityGroupRule{Protocol: cvt.ValToPtr("UDP"), Port: cvt.ValToPtr("53"),
+			IPv6Cidr: cvt.ValToPtr("fd00::/64"), Action: "ACCEPT"},
+	)
+
+	facts, err := handler.GetAutoApprovalFacts(true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{100}, facts.BkBizIDs)
+	assert.Equal(t, enumor.TCloud, facts.Vendor)
+	assert.Equal(t, []string{"ap-guangzhou"}, facts.Regions)
+	assert.Equal(t, int64(2), facts.Count)
+	assert.Nil(t, facts.Cost)
+	assert.Equal(t, []string{"10.0.0.0/24", "fd00::/64"}, facts.CIDRs)
+	assert.Equal(t, []coreapproval.PortRange{{From: 443, To: 443}, {From: 53, To: 53}}, facts.PortRanges)
+}
@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+	"math"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	cloudserver "hcm/pkg/api/cloud-server"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+)
+
+// CheckReq 检查申请单的数据是否正确
+func (a *ApplicationOfCreateTCloudSGRule) CheckReq() error {
+	if err := a.req.Validate(); err != nil {
+		return err
+	}
+
+	sg, err := a.Client.DataService().Global.Cloud.GetResBasicInfo(a.Cts.Kit, enumor.SecurityGroupCloudResType,
+		a.req.SecurityGroupID, "region")
+	if err != nil {
+		return err
+	}
+
+	if sg.Vendor != enumor.TCloud {
+		return fmt.Errorf("security group %s is not tcloud security group", a.req.SecurityGroupID)
+	}
+
+	if sg.BkBizID != a.req.BkBizID {
+		return fmt.Errorf("security group %s not belongs to biz %d", a.req.SecurityGroupID, a.req.BkBizID)
+	}
+	a.sg = sg
+
+	rules, err := a.complianceRules()
+	if err != nil {
+		return err
+	}
+
+	return sgcompliance.NewCompliance(a.Client, cc.CloudServer().SGCompliance).CheckSGRules(a.Cts.Kit, sg, rules)
+}
+
+// complianceRules 将待创建的规则转换为合规检查使用的规则
+func (a *ApplicationOfCreateTCloudSGRule) complianceRules() ([]sgcompliance.Rule, error) {
+	egressRules, err := convComplianceRules(enumor.Egress, a.req.EgressRuleSet)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressRules, err := convComplianceRules(enumor.Ingress, a.req.IngressRuleSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(egressRules, ingressRules...), nil
+}
+
+func convComplianceRules(ruleType enumor.SecurityGroupRuleType, ruleSet []cloudserver.TCloudSecurityGroupRule) (
+	[]sgcompliance.Rule, error) {
+
+	rules := make([]sgcompliance.Rule, 0, len(ruleSet))
+	for idx, one := range ruleSet {
+		rule, err := sgcompliance.FromTCloudRule(corecloud.TCloudSecurityGroupRule{
+			CloudPolicyIndex:           math.MaxInt32 + int64(idx),
+			Protocol:                   one.Protocol,
+			Port:                       one.Port,
+			CloudServiceID:             one.CloudServiceID,
+			CloudServiceGroupID:        one.CloudServiceGroupID,
+			IPv4Cidr:                   one.IPv4Cidr,
+			IPv6Cidr:                   one.IPv6Cidr,
+			CloudAddressID:             one.CloudAddressID,
+			CloudAddressGroupID:        one.CloudAddressGroupID,
+			CloudTargetSecurityGroupID: one.CloudTargetSecurityGroupID,
+			Action:                     one.Action,
+			Type:                       ruleType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+	"strings"
+
+	cloudserver "hcm/pkg/api/cloud-server"
+	cvt "hcm/pkg/tools/converter"
+)
+
+type formItem struct {
+	Label string
+	Value string
+}
+
+// RenderItsmTitle 渲染ITSM单据标题
+func (a *ApplicationOfCreateTCloudSGRule) RenderItsmTitle() (string, error) {
+	return fmt.Sprintf("申请新增[%s]安全组规则(%s)", a.Vendor().GetNameZh(), a.req.SecurityGroupID), nil
+}
+
+// RenderItsmForm 渲染ITSM表单
+func (a *ApplicationOfCreateTCloudSGRule) RenderItsmForm() (string, error) {
+	formItems := make([]formItem, 0)
+
+	// 业务
+	bizName, err := a.GetBizName(a.req.BkBizID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "业务", Value: bizName})
+
+	// 云账号
+	accountInfo, err := a.GetAccount(a.sg.AccountID)
+	if err != nil {
+		return "", err
+	}
+	formItems = append(formItems, formItem{Label: "云账号", Value: accountInfo.Name})
+
+	// 云厂商
+	formItems = append(formItems, formItem{Label: "云厂商", Value: a.Vendor().GetNameZh()})
+
+	// 云地域
+	formItems = append(formItems, formItem{Label: "云地域", Value: a.sg.Region})
+
+	// 安全组
+	formItems = append(formItems, formItem{Label: "安全组", Value: a.req.SecurityGroupID})
+
+	// 规则
+	for _, one := range a.req.IngressRuleSet {
+		formItems = append(formItems, formItem{Label: "入站规则", Value: renderRule(one)})
+	}
+	for _, one := range a.req.EgressRuleSet {
+		formItems = append(formItems, formItem{Label: "出站规则", Value: renderRule(one)})
+	}
+
+	// 转换为ITSM表单内容数据
+	content := make([]string, 0, len(formItems))
+	for _, i := range formItems {
+		content = append(content, fmt.Sprintf("%s: %s", i.Label, i.Value))
+	}
+	return strings.Join(content, "\n"), nil
+}
+
+func renderRule(rule cloudserver.TCloudSecurityGroupRule) string {
+	fields := make([]string, 0)
+	for _, one := range []struct {
+		name  string
+		value *string
+	}{
+		{name: "协议", value: rule.Protocol},
+		{name: "端口", value: rule.Port},
+		{name: "协议端口模版", value: rule.CloudServiceID},
+		{name: "协议端口模版组", value: rule.CloudServiceGroupID},
+		{name: "IPv4网段", value: rule.IPv4Cidr},
+		{name: "IPv6网段", value: rule.IPv6Cidr},
+		{name: "地址模版", value: rule.CloudAddressID},
+		{name: "地址模版组", value: rule.CloudAddressGroupID},
+		{name: "安全组", value: rule.CloudTargetSecurityGroupID},
+		{name: "备注", value: rule.Memo},
+	} {
+		if len(cvt.PtrToVal(one.value)) != 0 {
+			fields = append(fields, fmt.Sprintf("%s=%s", one.name, cvt.PtrToVal(one.value)))
+		}
+	}
+	fields = append(fields, fmt.Sprintf("策略=%s", rule.Action))
+
+	return strings.Join(fields, ", ")
+}
@@ -31,6 +31,7 @@ import (
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -58,6 +59,8 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 		bkHcmUrl:   bkHcmUrl,
 		cmsiCli:    c.CmsiCli,
 		cmdbCli:    c.CmdbCli,
+
+		approvalEngine: cc.CloudServer().Approval.Engine,
 	}
 	h := rest.NewHandler()
 	h.Add("ListApplications", "POST", "/applications/list", svc.ListApplications)
@@ -65,12 +68,32 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 	h.Add("CancelApplication", "PATCH", "/applications/{application_id}/cancel", svc.CancelApplication)
 	h.Add("ApproveApplication", "POST", "/applications/approve", svc.ApproveApplication)
 
+	// 内置审批引擎
+	h.Add("ApproveNativeApplication", "POST", "/applications/{application_id}/approve",
+		svc.ApproveNativeApplication)
+	h.Add("RejectNativeApplication", "POST", "/applications/{application_id}/reject", svc.RejectNativeApplication)
+	h.Add("TransferNativeApplication", "POST", "/applications/{application_id}/transfer",
+		svc.TransferNativeApplication)
+	h.Add("ListMyApprovalTickets", "POST", "/approval_tickets/my_approval/list", svc.ListMyApprovalTickets)
+	h.Add("ListApprovalChains", "POST", "/approval_chains/list", svc.ListApprovalChains)
+	h.Add("CreateApprovalChain", "POST", "/approval_chains/create", svc.CreateApprovalChain)
+	h.Add("UpdateApprovalChain", "PATCH", "/approval_chains/{id}", svc.UpdateApprovalChain)
+	h.Add("DeleteApprovalChain", "DELETE", "/approval_chains/{id}", svc.DeleteApprovalChain)
+
+	// 自动审批策略
+	h.Add("ListAutoApprovalPolicies", "POST", "/auto_approval_policies/list", svc.ListAutoApprovalPolicies)
+	h.Add("CreateAutoApprovalPolicy", "POST", "/auto_approval_policies/create", svc.CreateAutoApprovalPolicy)
+	h.Add("UpdateAutoApprovalPolicy", "PATCH", "/auto_approval_policies/{id}", svc.UpdateAutoApprovalPolicy)
+	h.Add("DeleteAutoApprovalPolicy", "DELETE", "/auto_approval_policies/{id}", svc.DeleteAutoApprovalPolicy)
+
 	h.Add("CreateForAddAccount", "POST", "/applications/types/add_account", svc.CreateForAddAccount)
 	h.Add("CreateForCreateCvm", "POST", "/vendors/{vendor}/applications/types/create_cvm", svc.CreateForCreateCvm)
 	h.Add("CreateForCreateVpc", "POST", "/vendors/{vendor}/applications/types/create_vpc", svc.CreateForCreateVpc)
 	h.Add("CreateForCreateDisk", "POST", "/vendors/{vendor}/applications/types/create_disk", svc.CreateForCreateDisk)
 	h.Add("CreateForCreateLB", "POST",
 		"/vendors/{vendor}/applications/types/create_load_balancer", svc.CreateForCreateLB)
+	h.Add("CreateForCreateSGRule", "POST",
+		"/vendors/{vendor}/applications/types/create_security_group_rule", svc.CreateForCreateSGRule)
 
 	h.Add("CreateForCreateMainAccount", "POST",
 		"/applications/types/create_main_account", svc.CreateForCreateMainAccount)
@@ -102,6 +125,9 @@ type applicationSvc struct {
 	bkHcmUrl   string
 	cmsiCli    cmsi.Client
 	cmdbCli    cmdb.Client
+
+	// approvalEngine 申请单使用的审批引擎
+	approvalEngine enumor.ApplicationSource
 }
 
 func (a *applicationSvc) getCallbackUrl() string {
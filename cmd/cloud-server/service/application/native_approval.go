@@ -0,0 +1,430 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/cmd/cloud-server/service/application/handlers"
+	proto "hcm/pkg/api/cloud-server/application"
+	"hcm/pkg/api/core"
+	coreapproval "hcm/pkg/api/core/approval"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/uuid"
+)
+
+const (
+	// nativeSNPrefix 内置审批引擎生成的申请单号前缀
+	nativeSNPrefix = "NATIVE"
+	// platformManagerVariable 平台管理员审批人变量，从审批流程配置的管理员中获取
+	platformManagerVariable = "platform_manager"
+)
+
+func genNativeSN() string {
+	return nativeSNPrefix + strings.ReplaceAll(uuid.UUID(), "-", "")
+}
+
+// createWithNativeApproval 使用内置审批引擎创建申请单及对应的审批单
+func (a *applicationSvc) createWithNativeApproval(cts *rest.Contexts, req *proto.CreateCommonReq,
+	handler handlers.ApplicationHandler, applicationType enumor.ApplicationType) (*core.CreateResult, error) {
+
+	chain, err := a.getApprovalChain(cts.Kit, applicationType, handler.GetBkBizIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := a.resolveApprovalNodes(cts, handler, applicationType, chain.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := handler.RenderItsmTitle()
+	if err != nil {
+		return nil, fmt.Errorf("render approval ticket title error: %w", err)
+	}
+
+	result, err := a.createApplication(cts, req, handler, genNativeSN(), applicationType,
+		enumor.ApplicationSourceNative)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketReq := &dataproto.ApprovalTicketCreateReq{
+		ApplicationID:   result.ID,
+		ApplicationType: applicationType,
+		Title:           title,
+		Applicant:       cts.Kit.User,
+		ChainID:         chain.ID,
+		Nodes:           nodes,
+		Approvers:       nodes[0].Approvers,
+		RemindedAt:      time.Now().Format(constant.TimeStdFormat),
+	}
+	if _, err = a.client.DataService().Global.CreateApprovalTicket(cts.Kit, ticketReq); err != nil {
+		logs.Errorf("create approval ticket failed, err: %v, application: %s, rid: %s", err, result.ID, cts.Kit.Rid)
+		// 审批单创建失败时取消申请单，避免申请单一直处于待审批状态
+		if updateErr := a.updateStatusWithDetail(cts, result.ID, enumor.Cancelled, ""); updateErr != nil {
+			logs.Errorf("cancel application failed, err: %v, id: %s, rid: %s", updateErr, result.ID, cts.Kit.Rid)
+		}
+		return nil, err
+	}
+
+	a.notifyApprovers(cts.Kit, title, result.ID, nodes[0].Approvers)
+	return result, nil
+}
+
+// getApprovalChain 获取申请单类型对应的审批链，优先使用业务配置的审批链，未配置时使用默认审批链
+func (a *applicationSvc) getApprovalChain(kt *kit.Kit, applicationType enumor.ApplicationType, bizIDs []int64) (
+	*coreapproval.Chain, error) {
+
+	bizID := int64(constant.UnassignedBiz)
+	if len(bizIDs) > 0 {
+		bizID = bizIDs[0]
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("application_type", applicationType),
+			tools.RuleIn("bk_biz_id", slice.Unique([]int64{bizID, constant.UnassignedBiz})),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := a.client.DataService().Global.ListApprovalChain(kt, listReq)
+	if err != nil {
+		logs.Errorf("list approval chain failed, err: %v, type: %s, biz: %d, rid: %s", err, applicationType, bizID,
+			kt.Rid)
+		return nil, err
+	}
+
+	var defaultChain *coreapproval.Chain
+	for i := range result.Details {
+		if result.Details[i].BkBizID == bizID {
+			return &result.Details[i], nil
+		}
+		defaultChain = &result.Details[i]
+	}
+
+	if defaultChain == nil {
+		return nil, errf.Newf(errf.RecordNotFound, "approval chain of %s not configured", applicationType)
+	}
+	return defaultChain, nil
+}
+
+// resolveApprovalNodes 将审批链节点中的审批人变量解析为具体审批人
+func (a *applicationSvc) resolveApprovalNodes(cts *rest.Contexts, handler handlers.ApplicationHandler,
+	applicationType enumor.ApplicationType, chainNodes []coreapproval.Node) ([]coreapproval.Node, error) {
+
+	managers := make([]string, 0)
+	for _, node := range chainNodes {
+		if !slice.IsItemInSlice(node.Variables, platformManagerVariable) {
+			continue
+		}
+
+		_, processManagers, err := a.getApprovalProcessInfo(cts, applicationType)
+		if err != nil {
+			return nil, fmt.Errorf("get approval process managers failed, err: %v", err)
+		}
+		managers = processManagers
+		break
+	}
+
+	variableApprovers := make(map[string][]string)
+	for _, one := range handler.GetItsmApprover(managers) {
+		variableApprovers[one.Variable] = one.Approvers
+	}
+
+	nodes := make([]coreapproval.Node, 0, len(chainNodes))
+	for _, node := range chainNodes {
+		approvers := append([]string{}, node.Approvers...)
+		for _, variable := range node.Variables {
+			approvers = append(approvers, variableApprovers[variable]...)
+		}
+		approvers = slice.Filter(slice.Unique(approvers), func(s string) bool { return len(s) != 0 })
+
+		if len(approvers) == 0 {
+			return nil, errf.Newf(errf.InvalidParameter, "approval node %s has no approver", node.Name)
+		}
+		nodes = append(nodes, coreapproval.Node{Name: node.Name, Approvers: approvers, Variables: node.Variables})
+	}
+
+	return nodes, nil
+}
+
+// transitApprovalTicket 根据审批操作计算审批单的变更，返回的更新请求带有当前版本号，用于避免并发审批
+func transitApprovalTicket(ticket *coreapproval.Ticket, action enumor.ApprovalAction, operator, target,
+	opinion string, now time.Time) (*dataproto.ApprovalTicketUpdateReq, error) {
+
+	if ticket.Status != enumor.ApprovalTicketPending {
+		return nil, errf.Newf(errf.InvalidParameter, "approval ticket is %s, can not %s", ticket.Status, action)
+	}
+
+	if action != enumor.ApprovalCancel && !slice.IsItemInSlice(ticket.Approvers, operator) {
+		return nil, errf.Newf(errf.PermissionDenied, "%s is not the approver of current node", operator)
+	}
+
+	if int(ticket.CurrentNode) >= len(ticket.Nodes) {
+		return nil, fmt.Errorf("approval ticket current node %d is out of range", ticket.CurrentNode)
+	}
+
+	operatedAt := now.Format(constant.TimeStdFormat)
+	version := ticket.Version
+	updateReq := &dataproto.ApprovalTicketUpdateReq{
+		ID:      ticket.ID,
+		Version: &version,
+		Records: append(append([]coreapproval.Record{}, ticket.Records...), coreapproval.Record{
+			NodeIndex:  ticket.CurrentNode,
+			NodeName:   ticket.Nodes[ticket.CurrentNode].Name,
+			Action:     action,
+			Operator:   operator,
+			Target:     target,
+			Opinion:    opinion,
+			OperatedAt: operatedAt,
+		}),
+	}
+
+	switch action {
+	case enumor.ApprovalApprove:
+		next := ticket.CurrentNode + 1
+		if int(next) >= len(ticket.Nodes) {
+			updateReq.Status = enumor.ApprovalTicketApproved
+			break
+		}
+		updateReq.CurrentNode = &next
+		updateReq.Approvers = ticket.Nodes[next].Approvers
+		updateReq.RemindedAt = operatedAt
+
+	case enumor.ApprovalReject:
+		updateReq.Status = enumor.ApprovalTicketRejected
+
+	case enumor.ApprovalTransfer:
+		if len(target) == 0 || target == operator {
+			return nil, errf.New(errf.InvalidParameter, "transfer target is invalid")
+		}
+		if slice.IsItemInSlice(ticket.Approvers, target) {
+			return nil, errf.Newf(errf.InvalidParameter, "%s is already the approver of current node", target)
+		}
+		updateReq.Approvers = append(slice.Remove(append([]string{}, ticket.Approvers...), operator), target)
+		updateReq.RemindedAt = operatedAt
+
+	case enumor.ApprovalCancel:
+		updateReq.Status = enumor.ApprovalTicketCancelled
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "unsupported approval action: %s", action)
+	}
+
+	return updateReq, nil
+}
+
+// ApproveNativeApplication 同意内置审批单的当前节点
+func (a *applicationSvc) ApproveNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, a.operateNativeApproval(cts, enumor.ApprovalApprove, "", req.Opinion)
+}
+
+// RejectNativeApplication 驳回内置审批单
+func (a *applicationSvc) RejectNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, a.operateNativeApproval(cts, enumor.ApprovalReject, "", req.Opinion)
+}
+
+// TransferNativeApplication 将内置审批单当前节点的审批转给其他人
+func (a *applicationSvc) TransferNativeApplication(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTransferReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, a.operateNativeApproval(cts, enumor.ApprovalTransfer, req.Target, req.Opinion)
+}
+
+func (a *applicationSvc) operateNativeApproval(cts *rest.Contexts, action enumor.ApprovalAction, target,
+	opinion string) error {
+
+	applicationID := cts.PathParameter("application_id").String()
+	application, err := a.client.DataService().Global.Application.GetApplication(
+		cts.Kit.Ctx, cts.Kit.Header(), applicationID)
+	if err != nil {
+		return err
+	}
+
+	if application.Source != enumor.ApplicationSourceNative {
+		return errf.Newf(errf.InvalidParameter, "application %s is not approved by native approval engine",
+			applicationID)
+	}
+
+	ticket, err := a.getApprovalTicket(cts.Kit, applicationID)
+	if err != nil {
+		return err
+	}
+
+	updateReq, err := transitApprovalTicket(ticket, action, cts.Kit.User, target, opinion, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err = a.client.DataService().Global.UpdateApprovalTicket(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update approval ticket failed, err: %v, ticket: %s, action: %s, rid: %s", err, ticket.ID,
+			action, cts.Kit.Rid)
+		return err
+	}
+
+	switch updateReq.Status {
+	case enumor.ApprovalTicketApproved:
+		if err = a.updateStatusWithDetail(cts, application.ID, enumor.Delivering, ""); err != nil {
+			return err
+		}
+		a.notifyApplicant(cts.Kit, ticket, enumor.Pass)
+		// TODO: 与ITSM回调一致，暂时用goroutine异步执行交付
+		go a.deliver(cts, application)
+
+	case enumor.ApprovalTicketRejected:
+		if err = a.updateStatusWithDetail(cts, application.ID, enumor.Rejected, ""); err != nil {
+			return err
+		}
+		a.notifyApplicant(cts.Kit, ticket, enumor.Rejected)
+
+	default:
+		a.notifyApprovers(cts.Kit, ticket.Title, application.ID, updateReq.Approvers)
+	}
+
+	return nil
+}
+
+// cancelApprovalTicket 申请人撤销申请单时同步撤销内置审批单
+func (a *applicationSvc) cancelApprovalTicket(kt *kit.Kit, applicationID string) error {
+	ticket, err := a.getApprovalTicket(kt, applicationID)
+	if err != nil {
+		return err
+	}
+
+	updateReq, err := transitApprovalTicket(ticket, enumor.ApprovalCancel, kt.User, "", "", time.Now())
+	if err != nil {
+		return err
+	}
+
+	return a.client.DataService().Global.UpdateApprovalTicket(kt, updateReq)
+}
+
+func (a *applicationSvc) getApprovalTicket(kt *kit.Kit, applicationID string) (*coreapproval.Ticket, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("application_id", applicationID),
+		Page:   &core.BasePage{Count: false, Start: 0, Limit: 1},
+	}
+	result, err := a.client.DataService().Global.ListApprovalTicket(kt, listReq)
+	if err != nil {
+		logs.Errorf("list approval ticket failed, err: %v, application: %s, rid: %s", err, applicationID, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "approval ticket of application %s not found", applicationID)
+	}
+
+	return &result.Details[0], nil
+}
+
+// ListMyApprovalTickets 查询当前用户待审批的内置审批单
+func (a *applicationSvc) ListMyApprovalTickets(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req.Filter = &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			req.Filter,
+			tools.RuleEqual("status", enumor.ApprovalTicketPending),
+			tools.RuleJSONContains("approvers", cts.Kit.User),
+		},
+	}
+
+	return a.client.DataService().Global.ListApprovalTicket(cts.Kit, req)
+}
+
+// notifyApprovers 通知审批人处理审批单
+func (a *applicationSvc) notifyApprovers(kt *kit.Kit, title, applicationID string, approvers []string) {
+	if len(approvers) == 0 {
+		return
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(approvers, ","),
+		Title:            fmt.Sprintf("【HCM】 待审批：%s", title),
+		Content: fmt.Sprintf(`<p>您好：</p><p>申请单 %s（%s）正在等待您的审批，请登录 <a href="%s">HCM</a> 处理。</p>`,
+			title, applicationID, a.bkHcmUrl),
+	}
+	if err := a.cmsiCli.SendMail(kt, mail); err != nil {
+		logs.Errorf("notify approvers failed, err: %v, application: %s, approvers: %v, rid: %s", err,
+			applicationID, approvers, kt.Rid)
+	}
+}
+
+// notifyApplicant 通知申请人审批结果
+func (a *applicationSvc) notifyApplicant(kt *kit.Kit, ticket *coreapproval.Ticket,
+	status enumor.ApplicationStatus) {
+
+	result := "已通过，正在交付资源"
+	if status == enumor.Rejected {
+		result = "已被驳回"
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: ticket.Applicant,
+		Title:            fmt.Sprintf("【HCM】 审批结果：%s", ticket.Title),
+		Content: fmt.Sprintf(`<p>您好：</p><p>您的申请单 %s（%s）%s，详情请登录 <a href="%s">HCM</a> 查看。</p>`,
+			ticket.Title, ticket.ApplicationID, result, a.bkHcmUrl),
+	}
+	if err := a.cmsiCli.SendMail(kt, mail); err != nil {
+		logs.Errorf("notify applicant failed, err: %v, application: %s, rid: %s", err, ticket.ApplicationID, kt.Rid)
+	}
+}
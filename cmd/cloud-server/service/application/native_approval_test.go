@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"testing"
+	"time"
+
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTicket() *coreapproval.Ticket {
+	return &coreapproval.Ticket{
+		ID:        "00000001",
+		Applicant: "applicant",
+		Nodes: []coreapproval.Node{
+			{Name: "leader", Approvers: []string{"alice", "bob"}},
+			{Name: "platform", Approvers: []string{"carol"}},
+		},
+		CurrentNode: 0,
+		Approvers:   []string{"alice", "bob"},
+		Status:      enumor.ApprovalTicketPending,
+		Version:     3,
+	}
+}
+
+func Test_transitApprovalTicket(t *testing.T) {
+	now := time.Now()
+
+	// 非当前节点审批人不能审批
+	_, err := transitApprovalTicket(newTestTicket(), enumor.ApprovalApprove, "carol", "", "", now)
+	assert.Error(t, err)
+
+	// 同意后流转到下一节点
+	ticket := newTestTicket()
+	req, err := transitApprovalTicket(ticket, enumor.ApprovalApprove, "alice", "", "ok", now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), *req.Version)
+	assert.Equal(t, uint32(1), *req.CurrentNode)
+	assert.Equal(t, []string{"carol"}, req.Approvers)
+	assert.Empty(t, req.Status)
+	assert.Len(t, req.Records, 1)
+	assert.Empty(t, ticket.Records)
+
+	// 最后一个节点同意后审批通过
+	ticket.CurrentNode, ticket.Approvers = 1, []string{"carol"}
+	req, err = transitApprovalTicket(ticket, enumor.ApprovalApprove, "carol", "", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, enumor.ApprovalTicketApproved, req.Status)
+	assert.Nil(t, req.CurrentNode)
+
+	// 驳回
+	req, err = transitApprovalTicket(newTestTicket(), enumor.ApprovalReject, "bob", "", "no", now)
+	assert.NoError(t, err)
+	assert.Equal(t, enumor.ApprovalTicketRejected, req.Status)
+
+	// 转审
+	req, err = transitApprovalTicket(newTestTicket(), enumor.ApprovalTransfer, "alice", "dave", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bob", "dave"}, req.Approvers)
+	assert.Empty(t, req.Status)
+
+	_, err = transitApprovalTicket(newTestTicket(), enumor.ApprovalTransfer, "alice", "bob", "", now)
+	assert.Error(t, err)
+
+	// 已终结的审批单不能再操作
+	ticket = newTestTicket()
+	ticket.Status = enumor.ApprovalTicketRejected
+	_, err = transitApprovalTicket(ticket, enumor.ApprovalCancel, "applicant", "", "", now)
+	assert.Error(t, err)
+}
@@ -53,6 +53,7 @@ func InitService(c *capability.Capability) {
 	h.Add("ListAudit", http.MethodPost, "/audits/list", svc.ListAudit)
 	h.Add("ListAuditAsyncFlow", http.MethodPost, "/audits/async_flow/list", svc.ListAuditAsyncFlow)
 	h.Add("ListAuditAsyncTask", http.MethodPost, "/audits/async_task/list", svc.ListAuditAsyncTask)
+	h.Add("VerifyAuditChain", http.MethodPost, "/audits/chain/verify", svc.VerifyAuditChain)
 
 	// biz audit apis
 	h.Add("GetBizAudit", http.MethodGet, "/bizs/{bk_biz_id}/audits/{id}", svc.GetBizAudit)
@@ -142,6 +143,28 @@ func (svc svc) listAudit(cts *rest.Contexts, authHandler handler.ListAuthResHand
 	return svc.client.DataService().Global.Audit.ListAudit(cts.Kit.Ctx, cts.Kit.Header(), listReq)
 }
 
+// VerifyAuditChain 校验当前租户审计记录的哈希链，检测审计记录是否被删除或篡改.
+func (svc svc) VerifyAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(audit.ChainVerifyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 哈希链覆盖租户下的全部审计记录，使用资源下操作记录的查看权限进行鉴权
+	err := svc.authorizer.AuthorizeWithPerm(cts.Kit, meta.ResourceAttribute{
+		Basic: &meta.Basic{Type: meta.Audit, Action: meta.Find},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.Audit.VerifyAuditChain(cts.Kit, req)
+}
+
 // ListAuditAsyncFlow 查询资源下异步任务的操作记录详情.
 func (svc svc) ListAuditAsyncFlow(cts *rest.Contexts) (interface{}, error) {
 	return svc.listAuditAsyncFlow(cts, handler.ListResourceAuthRes)
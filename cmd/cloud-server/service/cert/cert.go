@@ -44,12 +44,14 @@ func InitCertService(c *capability.Capability) {
 	h.Add("ListBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/certs/list", svc.ListBizCert)
 	h.Add("CreateBizCert", http.MethodPost, "/bizs/{bk_biz_id}/certs/create", svc.CreateBizCert)
 	h.Add("DeleteBizCert", http.MethodDelete, "/bizs/{bk_biz_id}/certs/{id}", svc.DeleteBizCert)
+	h.Add("ReplaceBizCert", http.MethodPost, "/bizs/{bk_biz_id}/certs/replace", svc.ReplaceBizCert)
 
 	// cert apis in resource
 	h.Add("ListCert", http.MethodPost, "/certs/list", svc.ListCert)
 	h.Add("AssignCertToBiz", http.MethodPost, "/certs/assign/bizs", svc.AssignCertToBiz)
 	h.Add("CreateCert", http.MethodPost, "/certs/create", svc.CreateCert)
 	h.Add("DeleteCert", http.MethodDelete, "/certs/{id}", svc.DeleteCert)
+	h.Add("ReplaceCert", http.MethodPost, "/certs/replace", svc.ReplaceCert)
 
 	h.Load(c.WebService)
 }
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"time"
+
+	logicscert "hcm/cmd/cloud-server/logics/cert"
+	"hcm/cmd/cloud-server/logics/tenant"
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+)
+
+// ExpiryNoticeTiming 定时扫描各租户即将过期的证书并发送通知，只在主节点执行
+func ExpiryNoticeTiming(c *client.ClientSet, sd serviced.State, cmsiCli cmsi.Client, bkHcmUrl string,
+	conf cc.CertExpiry) {
+
+	interval := time.Duration(conf.ScanIntervalMin) * time.Minute
+	logs.Infof("cert expiry notice enable, interval: %v, notice days: %v", interval, conf.NoticeDays)
+
+	notifier := logicscert.NewExpiryNotifier(c.DataService(), cmsiCli, bkHcmUrl, conf)
+	for {
+		time.Sleep(interval)
+
+		// 扫描水位持久化在全局配置中，切换主节点后从上次成功通知的时间点继续扫描
+		if !sd.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		tenantIDs, err := tenant.ListAllTenantID(kt, c.DataService())
+		if err != nil {
+			logs.Errorf("failed to list all tenant ids, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		now := time.Now()
+		for _, tenantID := range tenantIDs {
+			subKt := kt.NewSubKitWithTenant(tenantID)
+			if err = notifier.NotifyFromWatermark(subKt, now); err != nil {
+				logs.Errorf("notify expiring cert failed, err: %v, tenant: %s, rid: %s", err, tenantID, subKt.Rid)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cert
+
+import (
+	"fmt"
+
+	logicscert "hcm/cmd/cloud-server/logics/cert"
+	proto "hcm/pkg/api/cloud-server/cert"
+	"hcm/pkg/api/core"
+	corecert "hcm/pkg/api/core/cloud/cert"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ReplaceCert replace the old cert used by resource listeners and domains with new cert.
+func (svc *certSvc) ReplaceCert(cts *rest.Contexts) (interface{}, error) {
+	return svc.replaceCert(cts, handler.ResOperateAuth, constant.UnassignedBiz)
+}
+
+// ReplaceBizCert replace the old cert used by biz listeners and domains with new cert.
+func (svc *certSvc) ReplaceBizCert(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.replaceCert(cts, handler.BizOperateAuth, bizID)
+}
+
+func (svc *certSvc) replaceCert(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler, bizID int64) (
+	interface{}, error) {
+
+	req := new(proto.ReplaceCertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.CertCloudResType,
+		IDs:          []string{req.OldCertID, req.NewCertID},
+		Fields:       types.CommonBasicInfoFields,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list cert basic info failed, req: %+v, err: %v, rid: %s", basicInfoReq, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	for _, id := range basicInfoReq.IDs {
+		if _, exist := basicInfoMap[id]; !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "cert %s not found", id)
+		}
+	}
+
+	oldInfo, newInfo := basicInfoMap[req.OldCertID], basicInfoMap[req.NewCertID]
+	if oldInfo.AccountID != newInfo.AccountID {
+		return nil, errf.New(errf.InvalidParameter, "old cert and new cert should belong to the same account")
+	}
+
+	// 替换证书会修改负载均衡的监听器、域名配置，按负载均衡更新鉴权
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.LoadBalancer,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		logs.Errorf("replace cert auth failed, req: %+v, err: %v, rid: %s", req, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	switch oldInfo.Vendor {
+	case enumor.TCloud:
+		return svc.replaceTCloudCert(cts, req, oldInfo.AccountID, bizID)
+	default:
+		return nil, fmt.Errorf("vendor: %s not support replace cert", oldInfo.Vendor)
+	}
+}
+
+func (svc *certSvc) replaceTCloudCert(cts *rest.Contexts, req *proto.ReplaceCertReq, accountID string,
+	bizID int64) (*proto.ReplaceCertResult, error) {
+
+	certResp, err := svc.client.DataService().Global.ListCert(cts.Kit, &core.ListReq{
+		Filter: tools.ContainersExpression("id", []string{req.OldCertID, req.NewCertID}),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list cert for replace failed, req: %+v, err: %v, rid: %s", req, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	certs := make(map[string]corecert.BaseCert, len(certResp.Details))
+	for _, one := range certResp.Details {
+		certs[one.ID] = one
+	}
+	oldCert, newCert := certs[req.OldCertID], certs[req.NewCertID]
+	if oldCert.CertType != newCert.CertType {
+		return nil, errf.Newf(errf.InvalidParameter, "new cert type %s is different from old cert type %s",
+			newCert.CertType, oldCert.CertType)
+	}
+
+	usage, err := logicscert.ListTCloudUsage(cts.Kit, svc.client.DataService(), accountID, bizID, oldCert.CloudID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &proto.ReplaceCertResult{ListenerCount: usage.ListenerCount(), DomainCount: usage.DomainCount()}
+	if result.ListenerCount == 0 && result.DomainCount == 0 {
+		return result, nil
+	}
+
+	tasks, lbTasks := logicscert.BuildReplaceTasks(enumor.TCloud, usage, oldCert.CloudID, newCert.CloudID)
+	flowReq := &ts.AddCustomFlowReq{Name: enumor.FlowListenerReplaceCert, Tasks: tasks}
+	flowResp, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, flowReq)
+	if err != nil {
+		logs.Errorf("create replace cert flow failed, req: %+v, err: %v, rid: %s", req, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	logs.Infof("create replace cert flow success, flow: %s, old cert: %s, new cert: %s, listener count: %d, "+
+		"domain count: %d, rid: %s", flowResp.ID, oldCert.CloudID, newCert.CloudID, result.ListenerCount,
+		result.DomainCount, cts.Kit.Rid)
+	result.FlowID = flowResp.ID
+	result.Tasks = lbTasks
+	return result, nil
+}
@@ -274,14 +274,15 @@ func ConvHuaWeiCvmCreateReq(req *cscvm.HuaWeiCvmCreateReq) *hcproto.HuaWeiBatchC
 func ConvTCloudDiskCreateReq(req *cloudserver.TCloudDiskCreateReq) *hcprotodisk.TCloudDiskCreateReq {
 	return &hcprotodisk.TCloudDiskCreateReq{
 		DiskBaseCreateReq: &hcprotodisk.DiskBaseCreateReq{
-			AccountID: req.AccountID,
-			DiskName:  &req.DiskName,
-			Region:    req.Region,
-			Zone:      req.Zone,
-			DiskSize:  req.DiskSize,
-			DiskType:  req.DiskType,
-			DiskCount: req.DiskCount,
-			Memo:      req.Memo,
+			AccountID:  req.AccountID,
+			DiskName:   &req.DiskName,
+			Region:     req.Region,
+			Zone:       req.Zone,
+			DiskSize:   req.DiskSize,
+			DiskType:   req.DiskType,
+			DiskCount:  req.DiskCount,
+			Memo:       req.Memo,
+			SnapshotID: req.SnapshotID,
 		},
 		Extension: &hcprotodisk.TCloudDiskExtensionCreateReq{
 			DiskChargeType:    req.DiskChargeType,
@@ -294,14 +295,15 @@ func ConvTCloudDiskCreateReq(req *cloudserver.TCloudDiskCreateReq) *hcprotodisk.
 func ConvHuaWeiDiskCreateReq(req *cloudserver.HuaWeiDiskCreateReq) *hcprotodisk.HuaWeiDiskCreateReq {
 	return &hcprotodisk.HuaWeiDiskCreateReq{
 		DiskBaseCreateReq: &hcprotodisk.DiskBaseCreateReq{
-			AccountID: req.AccountID,
-			DiskName:  req.DiskName,
-			Region:    req.Region,
-			Zone:      req.Zone,
-			DiskSize:  uint64(req.DiskSize),
-			DiskType:  req.DiskType,
-			DiskCount: uint32(req.DiskCount),
-			Memo:      req.Memo,
+			AccountID:  req.AccountID,
+			DiskName:   req.DiskName,
+			Region:     req.Region,
+			Zone:       req.Zone,
+			DiskSize:   uint64(req.DiskSize),
+			DiskType:   req.DiskType,
+			DiskCount:  uint32(req.DiskCount),
+			Memo:       req.Memo,
+			SnapshotID: req.SnapshotID,
 		},
 		Extension: &hcprotodisk.HuaWeiDiskExtensionCreateReq{
 			DiskChargeType:    *req.DiskChargeType,
@@ -314,13 +316,14 @@ func ConvHuaWeiDiskCreateReq(req *cloudserver.HuaWeiDiskCreateReq) *hcprotodisk.
 func ConvAwsDiskCreateReq(req *cloudserver.AwsDiskCreateReq) *hcprotodisk.AwsDiskCreateReq {
 	return &hcprotodisk.AwsDiskCreateReq{
 		DiskBaseCreateReq: &hcprotodisk.DiskBaseCreateReq{
-			AccountID: req.AccountID,
-			Region:    req.Region,
-			Zone:      req.Zone,
-			DiskSize:  uint64(req.DiskSize),
-			DiskType:  req.DiskType,
-			DiskCount: uint32(req.DiskCount),
-			Memo:      req.Memo,
+			AccountID:  req.AccountID,
+			Region:     req.Region,
+			Zone:       req.Zone,
+			DiskSize:   uint64(req.DiskSize),
+			DiskType:   req.DiskType,
+			DiskCount:  uint32(req.DiskCount),
+			Memo:       req.Memo,
+			SnapshotID: req.SnapshotID,
 		},
 	}
 }
@@ -329,14 +332,15 @@ func ConvAwsDiskCreateReq(req *cloudserver.AwsDiskCreateReq) *hcprotodisk.AwsDis
 func ConvGcpDiskCreateReq(req *cloudserver.GcpDiskCreateReq) *hcprotodisk.GcpDiskCreateReq {
 	return &hcprotodisk.GcpDiskCreateReq{
 		DiskBaseCreateReq: &hcprotodisk.DiskBaseCreateReq{
-			AccountID: req.AccountID,
-			DiskName:  &req.DiskName,
-			Region:    req.Region,
-			Zone:      req.Zone,
-			DiskSize:  uint64(req.DiskSize),
-			DiskType:  req.DiskType,
-			DiskCount: uint32(req.DiskCount),
-			Memo:      req.Memo,
+			AccountID:  req.AccountID,
+			DiskName:   &req.DiskName,
+			Region:     req.Region,
+			Zone:       req.Zone,
+			DiskSize:   uint64(req.DiskSize),
+			DiskType:   req.DiskType,
+			DiskCount:  uint32(req.DiskCount),
+			Memo:       req.Memo,
+			SnapshotID: req.SnapshotID,
 		},
 	}
 }
@@ -345,14 +349,15 @@ func ConvGcpDiskCreateReq(req *cloudserver.GcpDiskCreateReq) *hcprotodisk.GcpDis
 func ConvAzureDiskCreateReq(req *cloudserver.AzureDiskCreateReq) *hcprotodisk.AzureDiskCreateReq {
 	return &hcprotodisk.AzureDiskCreateReq{
 		DiskBaseCreateReq: &hcprotodisk.DiskBaseCreateReq{
-			AccountID: req.AccountID,
-			DiskName:  &req.DiskName,
-			Region:    req.Region,
-			Zone:      req.Zone,
-			DiskSize:  uint64(req.DiskSize),
-			DiskType:  req.DiskType,
-			DiskCount: uint32(req.DiskCount),
-			Memo:      req.Memo,
+			AccountID:  req.AccountID,
+			DiskName:   &req.DiskName,
+			Region:     req.Region,
+			Zone:       req.Zone,
+			DiskSize:   uint64(req.DiskSize),
+			DiskType:   req.DiskType,
+			DiskCount:  uint32(req.DiskCount),
+			Memo:       req.Memo,
+			SnapshotID: req.SnapshotID,
 		},
 		Extension: &hcprotodisk.AzureDiskExtensionCreateReq{
 			ResourceGroupName: req.ResourceGroupName,
@@ -64,6 +64,31 @@ func InitDiskService(c *capability.Capability) {
 	h.Add("AttachBizDisk", http.MethodPost, "/bizs/{bk_biz_id}/disks/attach", svc.AttachBizDisk)
 	h.Add("DetachBizDisk", http.MethodPost, "/bizs/{bk_biz_id}/disks/detach", svc.DetachBizDisk)
 
+	// disk snapshot apis in res
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("CreateDiskSnapshot", http.MethodPost, "/disk_snapshots/create", svc.CreateDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.BatchDeleteDiskSnapshot)
+	h.Add("ListDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/list", svc.ListDiskSnapshotPolicy)
+	h.Add("CreateDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/create",
+		svc.CreateDiskSnapshotPolicy)
+	h.Add("UpdateDiskSnapshotPolicy", http.MethodPatch, "/disk_snapshot_policies/{id}", svc.UpdateDiskSnapshotPolicy)
+	h.Add("DeleteDiskSnapshotPolicy", http.MethodDelete, "/disk_snapshot_policies/{id}", svc.DeleteDiskSnapshotPolicy)
+
+	// disk snapshot apis in biz
+	h.Add("ListBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/list", svc.ListBizDiskSnapshot)
+	h.Add("CreateBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/create",
+		svc.CreateBizDiskSnapshot)
+	h.Add("BatchDeleteBizDiskSnapshot", http.MethodDelete, "/bizs/{bk_biz_id}/disk_snapshots/batch",
+		svc.BatchDeleteBizDiskSnapshot)
+	h.Add("ListBizDiskSnapshotPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshot_policies/list",
+		svc.ListBizDiskSnapshotPolicy)
+	h.Add("CreateBizDiskSnapshotPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshot_policies/create",
+		svc.CreateBizDiskSnapshotPolicy)
+	h.Add("UpdateBizDiskSnapshotPolicy", http.MethodPatch, "/bizs/{bk_biz_id}/disk_snapshot_policies/{id}",
+		svc.UpdateBizDiskSnapshotPolicy)
+	h.Add("DeleteBizDiskSnapshotPolicy", http.MethodDelete, "/bizs/{bk_biz_id}/disk_snapshot_policies/{id}",
+		svc.DeleteBizDiskSnapshotPolicy)
+
 	// recycle operation in res
 	h.Add("RecycleDisk", http.MethodPost, "/disks/recycle", svc.RecycleDisk)
 	h.Add("RecoverDisk", http.MethodPost, "/disks/recover", svc.RecoverDisk)
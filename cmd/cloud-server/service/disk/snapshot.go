@@ -0,0 +1,207 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	cloudproto "hcm/pkg/api/cloud-server/disk"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListDiskSnapshot list disk snapshot.
+func (svc *diskSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizDiskSnapshot list biz disk snapshot.
+func (svc *diskSvc) ListBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListBizAuthRes)
+}
+
+func (svc *diskSvc) listDiskSnapshot(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer, ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloud.DiskSnapshotListResult{Details: make([]coredisksnapshot.DiskSnapshot, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.ListDiskSnapshot(cts.Kit, req)
+}
+
+// CreateDiskSnapshot 为云盘创建快照.
+func (svc *diskSvc) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// CreateBizDiskSnapshot 为业务下的云盘创建快照.
+func (svc *diskSvc) CreateBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) createDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudproto.DiskSnapshotCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.CloudResourceType(table.DiskTable), req.DiskID, append(types.CommonBasicInfoFields,
+			"recycle_status")...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建快照需要云盘的更新权限
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := svc.diskSnapshotClient(basicInfo.Vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &hcproto.CreateReq{DiskID: req.DiskID, Name: req.Name, Memo: req.Memo}
+	result, err := cli.Create(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot failed, err: %v, disk: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchDeleteDiskSnapshot 批量删除云盘快照.
+func (svc *diskSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// BatchDeleteBizDiskSnapshot 批量删除业务下的云盘快照.
+func (svc *diskSvc) BatchDeleteBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) batchDeleteDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudproto.DiskSnapshotBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.CloudResourceType(table.DiskSnapshotTable),
+		IDs:          req.IDs,
+		Fields:       types.CommonBasicInfoFields,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Delete, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	vendorIDs := make(map[enumor.Vendor][]string)
+	for id, info := range basicInfoMap {
+		vendorIDs[info.Vendor] = append(vendorIDs[info.Vendor], id)
+	}
+
+	for vendor, ids := range vendorIDs {
+		if err = svc.audit.ResDeleteAudit(cts.Kit, enumor.DiskSnapshotAuditResType, ids); err != nil {
+			logs.Errorf("create disk snapshot delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		cli, err := svc.diskSnapshotClient(vendor)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = cli.BatchDelete(cts.Kit, &hcproto.BatchDeleteReq{IDs: ids}); err != nil {
+			logs.Errorf("delete disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// diskSnapshotClient hc-service 各云厂商快照客户端的公共方法
+type diskSnapshotClient interface {
+	Create(kt *kit.Kit, req *hcproto.CreateReq) (*core.CreateResult, error)
+	BatchDelete(kt *kit.Kit, req *hcproto.BatchDeleteReq) error
+}
+
+func (svc *diskSvc) diskSnapshotClient(vendor enumor.Vendor) (diskSnapshotClient, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.client.HCService().TCloud.DiskSnapshot, nil
+	case enumor.Aws:
+		return svc.client.HCService().Aws.DiskSnapshot, nil
+	case enumor.HuaWei:
+		return svc.client.HCService().HuaWei.DiskSnapshot, nil
+	case enumor.Gcp:
+		return svc.client.HCService().Gcp.DiskSnapshot, nil
+	case enumor.Azure:
+		return svc.client.HCService().Azure.DiskSnapshot, nil
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support disk snapshot", vendor)
+	}
+}
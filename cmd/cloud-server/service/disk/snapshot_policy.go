@@ -0,0 +1,364 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disk
+
+import (
+	"fmt"
+
+	cloudproto "hcm/pkg/api/cloud-server/disk"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataproto "hcm/pkg/api/data-service"
+	"hcm/pkg/api/data-service/cloud"
+	apits "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListDiskSnapshotPolicy list disk snapshot policy.
+func (svc *diskSvc) ListDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshotPolicy(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizDiskSnapshotPolicy list biz disk snapshot policy.
+func (svc *diskSvc) ListBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshotPolicy(cts, handler.ListBizAuthRes)
+}
+
+func (svc *diskSvc) listDiskSnapshotPolicy(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer, ResType: meta.Disk, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloud.DiskSnapshotPolicyListResult{Details: make([]coredisksnapshot.Policy, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.ListDiskSnapshotPolicy(cts.Kit, req)
+}
+
+// CreateDiskSnapshotPolicy 创建快照策略.
+func (svc *diskSvc) CreateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshotPolicy(cts, handler.ResOperateAuth)
+}
+
+// CreateBizDiskSnapshotPolicy 创建业务下的快照策略.
+func (svc *diskSvc) CreateBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshotPolicy(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) createDiskSnapshotPolicy(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudproto.DiskSnapshotPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	diskInfo, err := svc.authPolicyDisks(cts, validHandler, req.DiskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled == nil {
+		req.Enabled = converter.ValToPtr(true)
+	}
+	createReq := &cloud.DiskSnapshotPolicyCreateReq{
+		Name:           req.Name,
+		Vendor:         diskInfo.Vendor,
+		AccountID:      diskInfo.AccountID,
+		BkBizID:        diskInfo.BkBizID,
+		DiskIDs:        req.DiskIDs,
+		CronExpr:       req.CronExpr,
+		TimeZone:       req.TimeZone,
+		RetentionCount: req.RetentionCount,
+		RetentionDays:  req.RetentionDays,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	result, err := svc.client.DataService().Global.CreateDiskSnapshotPolicy(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	scheduleReq := &apits.CreateFlowScheduleReq{
+		Name:     policyScheduleName(result.ID),
+		FlowName: enumor.FlowDiskSnapshotPolicy,
+		CronExpr: req.CronExpr,
+		TimeZone: req.TimeZone,
+		Tasks:    policyScheduleTasks(result.ID),
+		Enabled:  req.Enabled,
+		Memo:     converter.ValToPtr(fmt.Sprintf("disk snapshot policy: %s", req.Name)),
+	}
+	schedule, err := svc.client.TaskServer().CreateFlowSchedule(cts.Kit, scheduleReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot policy schedule failed, err: %v, policy: %s, rid: %s", err, result.ID,
+			cts.Kit.Rid)
+		svc.rollbackDiskSnapshotPolicy(cts.Kit, result.ID)
+		return nil, err
+	}
+
+	updateReq := &cloud.DiskSnapshotPolicyUpdateReq{ID: result.ID, ScheduleID: schedule.ID}
+	if err = svc.client.DataService().Global.UpdateDiskSnapshotPolicy(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update disk snapshot policy schedule id failed, err: %v, policy: %s, rid: %s", err,
+			result.ID, cts.Kit.Rid)
+		// 删除已创建的定时任务流，避免其在策略回滚后继续触发
+		if delErr := svc.client.TaskServer().DeleteFlowSchedule(cts.Kit, schedule.ID); delErr != nil {
+			logs.Errorf("rollback disk snapshot policy schedule failed, err: %v, schedule: %s, rid: %s", delErr,
+				schedule.ID, cts.Kit.Rid)
+		}
+		svc.rollbackDiskSnapshotPolicy(cts.Kit, result.ID)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (svc *diskSvc) rollbackDiskSnapshotPolicy(kt *kit.Kit, id string) {
+	delReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := svc.client.DataService().Global.BatchDeleteDiskSnapshotPolicy(kt, delReq); err != nil {
+		logs.Errorf("rollback disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+	}
+}
+
+// UpdateDiskSnapshotPolicy 更新快照策略.
+func (svc *diskSvc) UpdateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateDiskSnapshotPolicy(cts, handler.ResOperateAuth)
+}
+
+// UpdateBizDiskSnapshotPolicy 更新业务下的快照策略.
+func (svc *diskSvc) UpdateBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateDiskSnapshotPolicy(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) updateDiskSnapshotPolicy(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	id := cts.PathParameter("id").String()
+	req := new(cloudproto.DiskSnapshotPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policy, err := svc.getDiskSnapshotPolicy(cts, validHandler, id, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.DiskIDs) != 0 {
+		diskInfo, err := svc.authPolicyDisks(cts, validHandler, req.DiskIDs)
+		if err != nil {
+			return nil, err
+		}
+		if diskInfo.AccountID != policy.AccountID {
+			return nil, errf.New(errf.InvalidParameter, "disks should belong to the account of policy")
+		}
+	}
+
+	retentionCount, retentionDays := policy.RetentionCount, policy.RetentionDays
+	if req.RetentionCount != nil {
+		retentionCount = *req.RetentionCount
+	}
+	if req.RetentionDays != nil {
+		retentionDays = *req.RetentionDays
+	}
+	if retentionCount == 0 && retentionDays == 0 {
+		return nil, errf.New(errf.InvalidParameter, "one of retention_count and retention_days is required")
+	}
+
+	updateReq := &cloud.DiskSnapshotPolicyUpdateReq{
+		ID:             id,
+		Name:           req.Name,
+		DiskIDs:        req.DiskIDs,
+		CronExpr:       req.CronExpr,
+		TimeZone:       req.TimeZone,
+		RetentionCount: req.RetentionCount,
+		RetentionDays:  req.RetentionDays,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	if err = svc.client.DataService().Global.UpdateDiskSnapshotPolicy(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(policy.ScheduleID) == 0 || (len(req.CronExpr) == 0 && req.TimeZone == nil && req.Enabled == nil) {
+		return nil, nil
+	}
+
+	scheduleReq := &apits.UpdateFlowScheduleReq{CronExpr: req.CronExpr, TimeZone: req.TimeZone,
+		Enabled: req.Enabled}
+	if err = svc.client.TaskServer().UpdateFlowSchedule(cts.Kit, policy.ScheduleID, scheduleReq); err != nil {
+		logs.Errorf("update disk snapshot policy schedule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteDiskSnapshotPolicy 删除快照策略，策略已创建的快照不会被删除.
+func (svc *diskSvc) DeleteDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.deleteDiskSnapshotPolicy(cts, handler.ResOperateAuth)
+}
+
+// DeleteBizDiskSnapshotPolicy 删除业务下的快照策略.
+func (svc *diskSvc) DeleteBizDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.deleteDiskSnapshotPolicy(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSvc) deleteDiskSnapshotPolicy(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	id := cts.PathParameter("id").String()
+	policy, err := svc.getDiskSnapshotPolicy(cts, validHandler, id, meta.Delete)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policy.ScheduleID) != 0 {
+		err = svc.client.TaskServer().DeleteFlowSchedule(cts.Kit, policy.ScheduleID)
+		if err != nil && !errf.IsRecordNotFound(err) {
+			logs.Errorf("delete disk snapshot policy schedule failed, err: %v, id: %s, rid: %s", err, id,
+				cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	if err = svc.audit.ResDeleteAudit(cts.Kit, enumor.SnapshotPolicyAuditResType, []string{id}); err != nil {
+		logs.Errorf("create disk snapshot policy delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	delReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err = svc.client.DataService().Global.BatchDeleteDiskSnapshotPolicy(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *diskSvc) getDiskSnapshotPolicy(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	id string, action meta.Action) (*coredisksnapshot.Policy, error) {
+
+	listReq := &core.ListReq{Filter: tools.EqualExpression("id", id), Page: core.NewDefaultBasePage()}
+	result, err := svc.client.DataService().Global.ListDiskSnapshotPolicy(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot policy: %s not found", id)
+	}
+
+	policy := result.Details[0]
+	basicInfo := &types.CloudResourceBasicInfo{ID: policy.ID, Vendor: policy.Vendor, AccountID: policy.AccountID,
+		BkBizID: policy.BkBizID}
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: action, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// authPolicyDisks 校验策略下的云盘属于同一账号并鉴权，返回云盘的公共信息，云盘分属不同业务时业务为未分配
+func (svc *diskSvc) authPolicyDisks(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	diskIDs []string) (*types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.CloudResourceType(table.DiskTable),
+		IDs:          diskIDs,
+		Fields:       append(types.CommonBasicInfoFields, "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *types.CloudResourceBasicInfo
+	for _, id := range diskIDs {
+		info, exist := basicInfoMap[id]
+		if !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "disk: %s not found", id)
+		}
+
+		if result == nil {
+			result = &types.CloudResourceBasicInfo{Vendor: info.Vendor, AccountID: info.AccountID,
+				BkBizID: info.BkBizID}
+			continue
+		}
+		if info.AccountID != result.AccountID {
+			return nil, errf.New(errf.InvalidParameter, "disks of snapshot policy should belong to one account")
+		}
+		if info.BkBizID != result.BkBizID {
+			result.BkBizID = constant.UnassignedBiz
+		}
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Disk,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func policyScheduleName(policyID string) string {
+	return fmt.Sprintf("disk_snapshot_policy_%s", policyID)
+}
+
+func policyScheduleTasks(policyID string) []apits.TemplateFlowTask {
+	return []apits.TemplateFlowTask{
+		{ActionID: "1", Params: map[string]string{"policy_id": policyID}},
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eip
+
+import (
+	"fmt"
+
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// RecycleEip recycle eip.
+func (svc *eipSvc) RecycleEip(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecycleBizEip recycle biz eip.
+func (svc *eipSvc) RecycleBizEip(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+// RecoverEip recover eip.
+func (svc *eipSvc) RecoverEip(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecoverBizEip recover biz eip.
+func (svc *eipSvc) RecoverBizEip(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+func (svc *eipSvc) recycleOption(validHandler handler.ValidWithAuthHandler) *logicsrecycle.ResRecycleOption {
+	return &logicsrecycle.ResRecycleOption{
+		Client:       svc.client,
+		Authorizer:   svc.authorizer,
+		Audit:        svc.audit,
+		ResType:      enumor.EipCloudResType,
+		AuditResType: enumor.EipAuditResType,
+		AuthResType:  meta.Eip,
+		ValidHandler: validHandler,
+		PreCheck:     svc.checkEipBinding,
+	}
+}
+
+// checkEipBinding 已绑定主机的eip不能放入回收站，需要先解绑
+func (svc *eipSvc) checkEipBinding(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	ids := converter.MapKeyToStringSlice(basicInfoMap)
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("eip_id", ids),
+		Page:   &core.BasePage{Start: 0, Limit: 1},
+	}
+	relResult, err := svc.client.DataService().Global.ListEipCvmRel(kt, listReq)
+	if err != nil {
+		logs.Errorf("list eip cvm rel failed, err: %v, eip ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
+	if len(relResult.Details) != 0 {
+		rel := relResult.Details[0]
+		return fmt.Errorf("eip(%s) is bound to cvm(%s), please disassociate it first", rel.EipID, rel.CvmID)
+	}
+
+	return nil
+}
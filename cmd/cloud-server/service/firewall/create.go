@@ -22,6 +22,7 @@ package firewall
 import (
 	"hcm/cmd/cloud-server/service/common"
 	proto "hcm/pkg/api/cloud-server"
+	corecloud "hcm/pkg/api/core/cloud"
 	hcproto "hcm/pkg/api/hc-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/errf"
@@ -65,6 +66,22 @@ func (svc *firewallSvc) createGcpFirewallRule(cts *rest.Contexts, bizID int64,
 		return nil, err
 	}
 
+	rule := corecloud.GcpFirewallRule{
+		Priority:          req.Priority,
+		CloudVpcID:        req.CloudVpcID,
+		SourceRanges:      req.SourceRanges,
+		DestinationRanges: req.DestinationRanges,
+		SourceTags:        req.SourceTags,
+		TargetTags:        req.TargetTags,
+		Denied:            req.Denied,
+		Allowed:           req.Allowed,
+		Type:              req.Type,
+		Disabled:          req.Disabled,
+	}
+	if err = svc.sgCompliance.CheckGcpFirewallRule(cts.Kit, bizID, rule); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.GcpFirewallRuleCreateReq{
 		BkBizID:           bizID,
 		AccountID:         req.AccountID,
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -32,9 +33,10 @@ import (
 // InitFirewallService initial the security group service
 func InitFirewallService(cap *capability.Capability) {
 	svc := &firewallSvc{
-		client:     cap.ApiClient,
-		authorizer: cap.Authorizer,
-		audit:      cap.Audit,
+		client:       cap.ApiClient,
+		authorizer:   cap.Authorizer,
+		audit:        cap.Audit,
+		sgCompliance: cap.Logics.SGCompliance,
 	}
 
 	h := rest.NewHandler()
@@ -65,7 +67,8 @@ func InitFirewallService(cap *capability.Capability) {
 }
 
 type firewallSvc struct {
-	client     *client.ClientSet
-	authorizer auth.Authorizer
-	audit      audit.Interface
+	client       *client.ClientSet
+	authorizer   auth.Authorizer
+	audit        audit.Interface
+	sgCompliance sgcompliance.Interface
 }
@@ -21,9 +21,12 @@ package firewall
 
 import (
 	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service/cloud"
 	hcproto "hcm/pkg/api/hc-service"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -70,6 +73,10 @@ func (svc *firewallSvc) updateGcpFirewallRule(cts *rest.Contexts, validHandler h
 		return nil, err
 	}
 
+	if err = svc.checkGcpFirewallRuleUpdateCompliance(cts, id, basicInfo.BkBizID, req); err != nil {
+		return nil, err
+	}
+
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
@@ -100,3 +107,34 @@ func (svc *firewallSvc) updateGcpFirewallRule(cts *rest.Contexts, validHandler h
 
 	return nil, nil
 }
+
+// checkGcpFirewallRuleUpdateCompliance check the gcp firewall rule after updated with compliance checks.
+func (svc *firewallSvc) checkGcpFirewallRuleUpdateCompliance(cts *rest.Contexts, id string, bizID int64,
+	req *proto.GcpFirewallRuleUpdateReq) error {
+
+	listReq := &dataproto.GcpFirewallRuleListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Gcp.Firewall.ListFirewallRule(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list firewall rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return errf.Newf(errf.RecordNotFound, "gcp firewall rule: %s not found", id)
+	}
+
+	rule := result.Details[0]
+	rule.Priority = req.Priority
+	rule.SourceTags = req.SourceTags
+	rule.TargetTags = req.TargetTags
+	rule.Denied = req.Denied
+	rule.Allowed = req.Allowed
+	rule.SourceRanges = req.SourceRanges
+	rule.DestinationRanges = req.DestinationRanges
+	rule.Disabled = req.Disabled
+
+	return svc.sgCompliance.CheckGcpFirewallRule(cts.Kit, bizID, rule)
+}
@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	logicsidle "hcm/cmd/cloud-server/logics/idle-resource"
+	cloudserver "hcm/pkg/api/cloud-server"
+	coreidle "hcm/pkg/api/core/cloud/idle-resource"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+var (
+	// bomHeader 兼容windows excel打开csv文件时中文乱码
+	bomHeader = []byte{0xEF, 0xBB, 0xBF}
+
+	exportHeader = []string{"资源类型", "资源ID", "云资源ID", "资源名称", "云厂商", "账号ID", "业务ID", "地域", "闲置原因",
+		"统计周期费用", "币种", "首次识别时间", "更新时间"}
+
+	reasonNames = map[enumor.IdleResourceReason]string{
+		enumor.IdleUnattachedDisk: "未挂载的云盘",
+		enumor.IdleUnboundEip:     "未绑定的弹性IP",
+		enumor.IdleStoppedCvm:     "已关机仍在计费的主机",
+		enumor.IdleLbNoListener:   "没有监听器的负载均衡",
+		enumor.IdleLbNoTarget:     "监听器未绑定后端服务的负载均衡",
+	}
+)
+
+// ExportIdleResource export idle resource to csv file.
+func (svc *idleResSvc) ExportIdleResource(cts *rest.Contexts) (interface{}, error) {
+	return svc.exportIdleResource(cts, 0)
+}
+
+// ExportBizIdleResource export idle resource of biz to csv file.
+func (svc *idleResSvc) ExportBizIdleResource(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.exportIdleResource(cts, bizID)
+}
+
+func (svc *idleResSvc) exportIdleResource(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	req := new(cloudserver.IdleResourceExportReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeFind(cts.Kit, bizID); err != nil {
+		return nil, err
+	}
+
+	expr := tools.AllExpression()
+	if req.Filter != nil {
+		expr = req.Filter
+	}
+	if bizID > 0 {
+		bizExpr, err := tools.And(expr, tools.RuleEqual("bk_biz_id", bizID))
+		if err != nil {
+			return nil, err
+		}
+		expr = bizExpr
+	}
+
+	resources, err := logicsidle.ListAllIdleResource(cts.Kit, svc.client.DataService(), expr)
+	if err != nil {
+		logs.Errorf("list idle resource to export failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	filePath, err := writeIdleResourceCsv(cts.Kit, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rest.FileResp{
+		ContentTypeStr:        "application/octet-stream",
+		ContentDispositionStr: fmt.Sprintf(`attachment; filename="%s"`, filepath.Base(filePath)),
+		FilePath:              filePath,
+	}, nil
+}
+
+func writeIdleResourceCsv(kt *kit.Kit, resources []coreidle.IdleResource) (string, error) {
+	if err := os.MkdirAll(cc.CloudServer().TmpFileDir, 0750); err != nil {
+		logs.Errorf("mkdir tmp file dir failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	filePath := filepath.Join(cc.CloudServer().TmpFileDir,
+		fmt.Sprintf("idle_resource_%s_%s.csv", time.Now().Format("2006-01-02-15_04_05"), kt.Rid))
+	file, err := os.Create(filePath)
+	if err != nil {
+		logs.Errorf("create export file failed, err: %v, path: %s, rid: %s", err, filePath, kt.Rid)
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err = file.Write(bomHeader); err != nil {
+		logs.Errorf("write bom header failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	writer := csv.NewWriter(file)
+	rows := make([][]string, 0, len(resources)+1)
+	rows = append(rows, exportHeader)
+	for _, one := range resources {
+		reason, exists := reasonNames[one.Reason]
+		if !exists {
+			reason = string(one.Reason)
+		}
+
+		row := []string{string(one.ResType), one.ResID, one.CloudResID, one.ResName, string(one.Vendor),
+			one.AccountID, strconv.FormatInt(one.BkBizID, 10), one.Region, reason, one.Cost.String(),
+			string(one.Currency), "", ""}
+		if one.Revision != nil {
+			row[11] = one.CreatedAt
+			row[12] = one.UpdatedAt
+		}
+		rows = append(rows, row)
+	}
+
+	if err = writer.WriteAll(rows); err != nil {
+		logs.Errorf("write idle resource csv failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	return filePath, nil
+}
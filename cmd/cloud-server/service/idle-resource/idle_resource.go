@@ -0,0 +1,151 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package idleresource ...
+package idleresource
+
+import (
+	"net/http"
+	"time"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	logicsidle "hcm/cmd/cloud-server/logics/idle-resource"
+	"hcm/cmd/cloud-server/logics/tenant"
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/serviced"
+)
+
+// InitService initialize the idle resource service.
+func InitService(c *capability.Capability) {
+	svc := &idleResSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		audit:      c.Audit,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("ListIdleResource", http.MethodPost, "/idle_resources/list", svc.ListIdleResource)
+	h.Add("ExportIdleResource", http.MethodPost, "/idle_resources/export", svc.ExportIdleResource)
+
+	h.Add("ListBizIdleResource", http.MethodPost, "/bizs/{bk_biz_id}/idle_resources/list", svc.ListBizIdleResource)
+	h.Add("ExportBizIdleResource", http.MethodPost, "/bizs/{bk_biz_id}/idle_resources/export",
+		svc.ExportBizIdleResource)
+	h.Add("RecycleBizIdleResource", http.MethodPost, "/bizs/{bk_biz_id}/idle_resources/recycle",
+		svc.RecycleBizIdleResource)
+
+	h.Load(c.WebService)
+}
+
+type idleResSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	audit      audit.Interface
+}
+
+// AnalyzeTiming 定时分析各租户的闲置资源，只在主节点执行
+func AnalyzeTiming(c *client.ClientSet, sd serviced.State, conf cc.IdleResource) {
+	interval := time.Duration(conf.AnalyzeIntervalMin) * time.Minute
+	logs.Infof("idle resource analyze enable, interval: %v, cost days: %d", interval, conf.CostDays)
+
+	analyzer := logicsidle.NewIdleResource(c, conf)
+	for {
+		time.Sleep(interval)
+
+		if !sd.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		tenantIDs, err := tenant.ListAllTenantID(kt, c.DataService())
+		if err != nil {
+			logs.Errorf("failed to list all tenant ids, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		for _, tenantID := range tenantIDs {
+			subKt := kt.NewSubKitWithTenant(tenantID)
+			if err = analyzer.Analyze(subKt); err != nil {
+				logs.Errorf("analyze idle resource failed, err: %v, tenant: %s, rid: %s", err, tenantID, subKt.Rid)
+			}
+		}
+	}
+}
+
+// ListIdleResource list idle resource.
+func (svc *idleResSvc) ListIdleResource(cts *rest.Contexts) (interface{}, error) {
+	return svc.listIdleResource(cts, 0)
+}
+
+// ListBizIdleResource list idle resource of biz.
+func (svc *idleResSvc) ListBizIdleResource(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.listIdleResource(cts, bizID)
+}
+
+func (svc *idleResSvc) listIdleResource(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeFind(cts.Kit, bizID); err != nil {
+		return nil, err
+	}
+
+	if bizID > 0 {
+		expr, err := tools.And(req.Filter, tools.RuleEqual("bk_biz_id", bizID))
+		if err != nil {
+			return nil, err
+		}
+		req.Filter = expr
+	}
+
+	return svc.client.DataService().Global.ListIdleResource(cts.Kit, req)
+}
+
+// authorizeFind 查看全部闲置资源需要成本管理的查看权限，查看业务下的闲置资源需要业务访问权限
+func (svc *idleResSvc) authorizeFind(kt *kit.Kit, bizID int64) error {
+	if bizID > 0 {
+		return svc.authorizer.AuthorizeWithPerm(kt, meta.ResourceAttribute{
+			Basic: &meta.Basic{Type: meta.Biz, Action: meta.Access},
+			BizID: bizID,
+		})
+	}
+
+	return svc.authorizer.AuthorizeWithPerm(kt, meta.ResourceAttribute{
+		Basic: &meta.Basic{Type: meta.CostManage, Action: meta.Find},
+	})
+}
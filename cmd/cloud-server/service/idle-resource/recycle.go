@@ -0,0 +1,208 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package idleresource
+
+import (
+	"fmt"
+
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	coreidle "hcm/pkg/api/core/cloud/idle-resource"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// recycleResTypes 支持从闲置资源批量放入回收站的资源类型，主机回收需要指定回收选项，需要通过主机回收接口处理
+var recycleResTypes = []enumor.CloudResourceType{enumor.DiskCloudResType, enumor.EipCloudResType,
+	enumor.LoadBalancerCloudResType}
+
+// RecycleBizIdleResource put the resources of biz idle resources into recycle bin, each resource type is recycled
+// in a separate recycle task, the idle resource records of recycled resources will be removed.
+func (svc *idleResSvc) RecycleBizIdleResource(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(cloudserver.IdleResourceRecycleReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	idleResult, err := svc.client.DataService().Global.ListIdleResource(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list idle resource failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(idleResult.Details) != len(req.IDs) {
+		return nil, errf.Newf(errf.InvalidParameter, "some idle resources are not found in biz %d", bizID)
+	}
+
+	typeResources := make(map[enumor.CloudResourceType][]coreidle.IdleResource)
+	for _, one := range idleResult.Details {
+		typeResources[one.ResType] = append(typeResources[one.ResType], one)
+	}
+
+	result := &cloudserver.IdleResourceRecycleResult{
+		TaskIDs:   make([]string, 0),
+		Succeeded: make([]string, 0),
+		Failed:    make([]cloudserver.IdleResourceRecycleFailed, 0),
+	}
+	for resType, resources := range typeResources {
+		if !slice.IsItemInSlice(recycleResTypes, resType) {
+			for _, one := range resources {
+				result.Failed = append(result.Failed, cloudserver.IdleResourceRecycleFailed{ID: one.ID,
+					Reason: fmt.Sprintf("%s need to be recycled by its own recycle api", resType)})
+			}
+			continue
+		}
+
+		resIDs := slice.Map(resources, func(one coreidle.IdleResource) string { return one.ResID })
+		recycleResult, err := logicsrecycle.RecycleResByIDs(cts, svc.recycleOption(resType), resIDs)
+		if err != nil {
+			logs.Errorf("recycle idle %s failed, err: %v, ids: %v, rid: %s", resType, err, resIDs, cts.Kit.Rid)
+			for _, one := range resources {
+				result.Failed = append(result.Failed, cloudserver.IdleResourceRecycleFailed{ID: one.ID,
+					Reason: err.Error()})
+			}
+			continue
+		}
+
+		result.TaskIDs = append(result.TaskIDs, recycleResult.TaskID)
+		for _, one := range resources {
+			result.Succeeded = append(result.Succeeded, one.ID)
+		}
+	}
+
+	// 已放入回收站的资源不再是闲置资源，删除对应的闲置资源记录，删除失败时由下次分析任务清理
+	if len(result.Succeeded) > 0 {
+		deleteReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", result.Succeeded)}
+		if err = svc.client.DataService().Global.BatchDeleteIdleResource(cts.Kit, deleteReq); err != nil {
+			logs.Errorf("delete recycled idle resource failed, err: %v, ids: %v, rid: %s", err, result.Succeeded,
+				cts.Kit.Rid)
+		}
+	}
+
+	return result, nil
+}
+
+func (svc *idleResSvc) recycleOption(resType enumor.CloudResourceType) *logicsrecycle.ResRecycleOption {
+	opt := &logicsrecycle.ResRecycleOption{
+		Client:       svc.client,
+		Authorizer:   svc.authorizer,
+		Audit:        svc.audit,
+		ResType:      resType,
+		ValidHandler: handler.BizOperateAuth,
+	}
+
+	switch resType {
+	case enumor.DiskCloudResType:
+		opt.AuditResType = enumor.DiskAuditResType
+		opt.AuthResType = meta.Disk
+		opt.PreCheck = svc.checkDiskDetached
+	case enumor.EipCloudResType:
+		opt.AuditResType = enumor.EipAuditResType
+		opt.AuthResType = meta.Eip
+		opt.PreCheck = svc.checkEipUnbound
+	case enumor.LoadBalancerCloudResType:
+		opt.AuditResType = enumor.LoadBalancerAuditResType
+		opt.AuthResType = meta.LoadBalancer
+		opt.PreCheck = svc.checkLbNoListener
+	}
+
+	return opt
+}
+
+// checkDiskDetached 闲置分析后云盘可能已被挂载，已挂载的云盘不能通过闲置资源放入回收站
+func (svc *idleResSvc) checkDiskDetached(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("disk_id", converter.MapKeyToStringSlice(basicInfoMap)),
+		Page:   &core.BasePage{Start: 0, Limit: 1},
+	}
+	relResult, err := svc.client.DataService().Global.ListDiskCvmRel(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk cvm rel failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(relResult.Details) != 0 {
+		rel := relResult.Details[0]
+		return fmt.Errorf("disk(%s) is attached to cvm(%s), it is no longer idle", rel.DiskID, rel.CvmID)
+	}
+	return nil
+}
+
+// checkEipUnbound 闲置分析后弹性IP可能已被绑定，已绑定的弹性IP不能通过闲置资源放入回收站
+func (svc *idleResSvc) checkEipUnbound(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("eip_id", converter.MapKeyToStringSlice(basicInfoMap)),
+		Page:   &core.BasePage{Start: 0, Limit: 1},
+	}
+	relResult, err := svc.client.DataService().Global.ListEipCvmRel(kt, listReq)
+	if err != nil {
+		logs.Errorf("list eip cvm rel failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(relResult.Details) != 0 {
+		rel := relResult.Details[0]
+		return fmt.Errorf("eip(%s) is bound to cvm(%s), it is no longer idle", rel.EipID, rel.CvmID)
+	}
+	return nil
+}
+
+// checkLbNoListener 与删除负载均衡一致，存在监听器的负载均衡需要先删除监听器才能放入回收站
+func (svc *idleResSvc) checkLbNoListener(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("lb_id", converter.MapKeyToStringSlice(basicInfoMap)),
+		Page:   &core.BasePage{Start: 0, Limit: 1},
+	}
+	listenerResult, err := svc.client.DataService().Global.LoadBalancer.ListListener(kt, listReq)
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(listenerResult.Details) != 0 {
+		listener := listenerResult.Details[0]
+		return fmt.Errorf("load balancer(%s) with listener(%s) can not be recycled, please delete the listener first",
+			listener.CloudLbID, listener.CloudID)
+	}
+	return nil
+}
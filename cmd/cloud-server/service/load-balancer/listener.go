@@ -248,6 +248,8 @@ func (svc *lbSvc) getListener(cts *rest.Contexts, validHandler handler.ListAuthR
 	switch basicInfo.Vendor {
 	case enumor.TCloud:
 		return svc.getTCloudListener(cts.Kit, id)
+	case enumor.Aws:
+		return svc.client.DataService().Aws.LoadBalancer.GetListener(cts.Kit, id)
 
 	default:
 		return nil, errf.Newf(errf.InvalidParameter, "id: %s vendor: %s not support", id, basicInfo.Vendor)
@@ -54,10 +54,23 @@ func InitService(c *capability.Capability) {
 	h.Add("TCloudDescribeResources", http.MethodPost,
 		"/vendors/tcloud/load_balancers/resources/describe", svc.TCloudDescribeResources)
 	h.Add("BatchDeleteLoadBalancer", http.MethodDelete, "/load_balancers/batch", svc.BatchDeleteLoadBalancer)
+	h.Add("RecycleLoadBalancer", http.MethodPost, "/load_balancers/recycle", svc.RecycleLoadBalancer)
+	h.Add("RecoverLoadBalancer", http.MethodPost, "/load_balancers/recover", svc.RecoverLoadBalancer)
 	h.Add("ListListenerCountByLbIDs", http.MethodPost, "/load_balancers/listeners/count", svc.ListListenerCountByLbIDs)
 	h.Add("GetLoadBalancerLockStatus", http.MethodGet,
 		"/load_balancers/{id}/lock/status", svc.GetLoadBalancerLockStatus)
 	h.Add("ListResLoadBalancerQuotas", http.MethodPost, "/load_balancers/quotas", svc.ListResLoadBalancerQuotas)
+	h.Add("ExportLoadBalancerSpec", http.MethodGet, "/load_balancers/{id}/spec", svc.ExportLoadBalancerSpec)
+	h.Add("PlanLoadBalancerSpec", http.MethodPost, "/load_balancers/{id}/spec/plan", svc.PlanLoadBalancerSpec)
+	h.Add("ApplyLoadBalancerSpec", http.MethodPost, "/load_balancers/{id}/spec/apply", svc.ApplyLoadBalancerSpec)
+	h.Add("ListLoadBalancerSnapshot", http.MethodPost, "/load_balancers/{id}/snapshots/list",
+		svc.ListLoadBalancerSnapshot)
+	h.Add("GetLoadBalancerSnapshot", http.MethodGet, "/load_balancers/{id}/snapshots/{snapshot_id}",
+		svc.GetLoadBalancerSnapshot)
+	h.Add("PreviewLoadBalancerSnapshotRollback", http.MethodPost,
+		"/load_balancers/{id}/snapshots/{snapshot_id}/rollback/preview", svc.PreviewLoadBalancerSnapshotRollback)
+	h.Add("RollbackLoadBalancerSnapshot", http.MethodPost, "/load_balancers/{id}/snapshots/{snapshot_id}/rollback",
+		svc.RollbackLoadBalancerSnapshot)
 
 	bizH := rest.NewHandler()
 	bizH.Path("/bizs/{bk_biz_id}")
@@ -80,6 +93,8 @@ func bizService(h *rest.Handler, svc *lbSvc) {
 		"/load_balancers/with/delete_protection/list", svc.ListBizLoadBalancerWithDelProtect)
 	h.Add("GetBizLoadBalancer", http.MethodGet, "/load_balancers/{id}", svc.GetBizLoadBalancer)
 	h.Add("BatchDeleteBizLoadBalancer", http.MethodDelete, "/load_balancers/batch", svc.BatchDeleteBizLoadBalancer)
+	h.Add("RecycleBizLoadBalancer", http.MethodPost, "/load_balancers/recycle", svc.RecycleBizLoadBalancer)
+	h.Add("RecoverBizLoadBalancer", http.MethodPost, "/load_balancers/recover", svc.RecoverBizLoadBalancer)
 
 	h.Add("ListBizListener", http.MethodPost, "/load_balancers/{lb_id}/listeners/list", svc.ListBizListener)
 	h.Add("GetBizListener", http.MethodGet, "/listeners/{id}", svc.GetBizListener)
@@ -90,6 +105,24 @@ func bizService(h *rest.Handler, svc *lbSvc) {
 	h.Add("GetBizLoadBalancerLockStatus", http.MethodGet,
 		"/load_balancers/{id}/lock/status", svc.GetBizLoadBalancerLockStatus)
 	h.Add("ListBizLoadBalancerQuotas", http.MethodPost, "/load_balancers/quotas", svc.ListBizLoadBalancerQuotas)
+	h.Add("ExportBizLoadBalancerSpec", http.MethodGet, "/load_balancers/{id}/spec", svc.ExportBizLoadBalancerSpec)
+	h.Add("PlanBizLoadBalancerSpec", http.MethodPost, "/load_balancers/{id}/spec/plan", svc.PlanBizLoadBalancerSpec)
+	h.Add("ApplyBizLoadBalancerSpec", http.MethodPost, "/load_balancers/{id}/spec/apply",
+		svc.ApplyBizLoadBalancerSpec)
+	h.Add("ListBizTargetHealthEvent", http.MethodPost, "/load_balancers/target_health/events/list",
+		svc.ListBizTargetHealthEvent)
+	h.Add("ListBizTargetHealthFlapping", http.MethodPost, "/load_balancers/target_health/flapping/list",
+		svc.ListBizTargetHealthFlapping)
+	h.Add("ListBizTargetHealthDowntime", http.MethodPost, "/load_balancers/target_health/downtime/list",
+		svc.ListBizTargetHealthDowntime)
+	h.Add("ListBizLoadBalancerSnapshot", http.MethodPost, "/load_balancers/{id}/snapshots/list",
+		svc.ListBizLoadBalancerSnapshot)
+	h.Add("GetBizLoadBalancerSnapshot", http.MethodGet, "/load_balancers/{id}/snapshots/{snapshot_id}",
+		svc.GetBizLoadBalancerSnapshot)
+	h.Add("PreviewBizLoadBalancerSnapshotRollback", http.MethodPost,
+		"/load_balancers/{id}/snapshots/{snapshot_id}/rollback/preview", svc.PreviewBizLoadBalancerSnapshotRollback)
+	h.Add("RollbackBizLoadBalancerSnapshot", http.MethodPost,
+		"/load_balancers/{id}/snapshots/{snapshot_id}/rollback", svc.RollbackBizLoadBalancerSnapshot)
 
 	h.Add("TCloudCreateSnatIps", http.MethodPost,
 		"/vendors/tcloud/load_balancers/{lb_id}/snat_ips/create", svc.TCloudCreateSnatIps)
@@ -119,6 +152,8 @@ func bizService(h *rest.Handler, svc *lbSvc) {
 		http.MethodPatch, "/target_groups/{target_group_id}/targets/port", svc.BatchModifyBizTargetsPort)
 	h.Add("BatchModifyBizTargetsWeight", http.MethodPatch,
 		"/targets/weight", svc.BatchModifyBizTargetsWeight)
+	h.Add("CreateBizTargetGroupTrafficShift", http.MethodPost,
+		"/target_groups/{target_group_id}/traffic_shift", svc.CreateBizTargetGroupTrafficShift)
 
 	h.Add("CancelFlow", http.MethodPost, "/load_balancers/{lb_id}/async_flows/terminate", svc.BizTerminateFlow)
 	h.Add("RetryTask", http.MethodPost, "/load_balancers/{lb_id}/async_tasks/retry", svc.BizRetryTask)
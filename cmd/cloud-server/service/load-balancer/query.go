@@ -193,6 +193,8 @@ func (svc *lbSvc) getLoadBalancer(cts *rest.Contexts, validHandler handler.ListA
 	switch basicInfo.Vendor {
 	case enumor.TCloud:
 		return svc.client.DataService().TCloud.LoadBalancer.Get(cts.Kit, id)
+	case enumor.Aws:
+		return svc.client.DataService().Aws.LoadBalancer.Get(cts.Kit, id)
 
 	default:
 		return nil, errf.Newf(errf.Unknown, "id: %s vendor: %s not support", id, basicInfo.Vendor)
@@ -423,7 +425,7 @@ func (svc *lbSvc) getLoadBalancerLockStatus(cts *rest.Contexts, validHandler han
 	}
 
 	switch basicInfo.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws:
 		// 预检测-是否有执行中的负载均衡
 		flowRelResp, err := svc.checkResFlowRel(cts.Kit, id, enumor.LoadBalancerCloudResType)
 		if err != nil {
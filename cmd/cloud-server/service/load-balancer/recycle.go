@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// RecycleLoadBalancer recycle load balancer.
+func (svc *lbSvc) RecycleLoadBalancer(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecycleBizLoadBalancer recycle biz load balancer.
+func (svc *lbSvc) RecycleBizLoadBalancer(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+// RecoverLoadBalancer recover load balancer.
+func (svc *lbSvc) RecoverLoadBalancer(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecoverBizLoadBalancer recover biz load balancer.
+func (svc *lbSvc) RecoverBizLoadBalancer(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+func (svc *lbSvc) recycleOption(validHandler handler.ValidWithAuthHandler) *logicsrecycle.ResRecycleOption {
+	return &logicsrecycle.ResRecycleOption{
+		Client:       svc.client,
+		Authorizer:   svc.authorizer,
+		Audit:        svc.audit,
+		ResType:      enumor.LoadBalancerCloudResType,
+		AuditResType: enumor.LoadBalancerAuditResType,
+		AuthResType:  meta.LoadBalancer,
+		ValidHandler: validHandler,
+		PreCheck:     svc.checkLoadBalancerRecycle,
+	}
+}
+
+// checkLoadBalancerRecycle 放入回收站前的检查与删除一致，开启删除保护或存在监听器的负载均衡不能放入回收站
+func (svc *lbSvc) checkLoadBalancerRecycle(kt *kit.Kit, basicInfoMap map[string]types.CloudResourceBasicInfo) error {
+	return svc.loadBalancerDeleteCheck(kt, converter.MapKeyToStringSlice(basicInfoMap))
+}
@@ -0,0 +1,197 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	lblogic "hcm/cmd/cloud-server/logics/load-balancer"
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// lbSnapshotListFields 快照列表默认返回的字段，不包含快照配置内容
+var lbSnapshotListFields = []string{"id", "vendor", "lb_id", "account_id", "bk_biz_id", "version", "source",
+	"flow_id", "creator", "reviser", "created_at", "updated_at"}
+
+// ListLoadBalancerSnapshot 查询负载均衡配置快照
+func (svc *lbSvc) ListLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.listLoadBalancerSnapshot(cts, handler.ResOperateAuth)
+}
+
+// ListBizLoadBalancerSnapshot 查询业务下负载均衡配置快照
+func (svc *lbSvc) ListBizLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.listLoadBalancerSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) listLoadBalancerSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	any, error) {
+
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(core.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req.Filter, err = tools.And(tools.RuleEqual("lb_id", lbID), req.Filter)
+	if err != nil {
+		logs.Errorf("merge load balancer id rule into request filter failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(req.Fields) == 0 {
+		req.Fields = lbSnapshotListFields
+	}
+	if len(req.Page.Sort) == 0 {
+		req.Page.Sort = "version"
+		req.Page.Order = core.Descending
+	}
+	return svc.client.DataService().Global.LoadBalancer.ListLoadBalancerSnapshot(cts.Kit, req)
+}
+
+// GetLoadBalancerSnapshot 查询负载均衡配置快照详情
+func (svc *lbSvc) GetLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.getLoadBalancerSnapshot(cts, handler.ResOperateAuth)
+}
+
+// GetBizLoadBalancerSnapshot 查询业务下负载均衡配置快照详情
+func (svc *lbSvc) GetBizLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.getLoadBalancerSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) getLoadBalancerSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	any, error) {
+
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+	return svc.getSnapshot(cts.Kit, lbID, cts.PathParameter("snapshot_id").String())
+}
+
+// PreviewLoadBalancerSnapshotRollback 预览回滚到历史快照需要执行的变更
+func (svc *lbSvc) PreviewLoadBalancerSnapshotRollback(cts *rest.Contexts) (any, error) {
+	return svc.previewLoadBalancerSnapshotRollback(cts, handler.ResOperateAuth)
+}
+
+// PreviewBizLoadBalancerSnapshotRollback 预览业务下负载均衡回滚到历史快照需要执行的变更
+func (svc *lbSvc) PreviewBizLoadBalancerSnapshotRollback(cts *rest.Contexts) (any, error) {
+	return svc.previewLoadBalancerSnapshotRollback(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) previewLoadBalancerSnapshotRollback(cts *rest.Contexts,
+	validHandler handler.ValidWithAuthHandler) (any, error) {
+
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	_, plan, err := svc.buildSnapshotRollbackPlan(cts, lbID)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// RollbackLoadBalancerSnapshot 将负载均衡回滚到历史快照
+func (svc *lbSvc) RollbackLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.rollbackLoadBalancerSnapshot(cts, handler.ResOperateAuth)
+}
+
+// RollbackBizLoadBalancerSnapshot 将业务下负载均衡回滚到历史快照
+func (svc *lbSvc) RollbackBizLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	return svc.rollbackLoadBalancerSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) rollbackLoadBalancerSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	any, error) {
+
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	state, plan, err := svc.buildSnapshotRollbackPlan(cts, lbID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.applyLoadBalancerSpecPlan(cts.Kit, state, plan, enumor.LbSnapshotRollback)
+}
+
+// buildSnapshotRollbackPlan 对比快照配置与负载均衡当前状态，快照包含完整配置，快照之后新增的监听器也会被删除
+func (svc *lbSvc) buildSnapshotRollbackPlan(cts *rest.Contexts, lbID string) (*lblogic.TCloudSpecState,
+	*cslb.TCloudSpecPlanResult, error) {
+
+	req := new(cslb.LbSnapshotRollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshot, err := svc.getSnapshot(cts.Kit, lbID, cts.PathParameter("snapshot_id").String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if snapshot.Spec == nil {
+		return nil, nil, errf.Newf(errf.InvalidParameter, "snapshot(%s) has no spec", snapshot.ID)
+	}
+	snapshot.Spec.Prune = true
+
+	return svc.diffLoadBalancerSpec(cts.Kit, lbID, snapshot.Spec, req.Refresh)
+}
+
+// getSnapshot 查询负载均衡下的快照，包含快照配置内容
+func (svc *lbSvc) getSnapshot(kt *kit.Kit, lbID, snapshotID string) (*corelb.LoadBalancerSnapshot, error) {
+	if len(snapshotID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "snapshot_id is required")
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]any{"id": snapshotID, "lb_id": lbID}),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.LoadBalancer.ListLoadBalancerSnapshot(kt, listReq)
+	if err != nil {
+		logs.Errorf("list load balancer snapshot failed, err: %v, lb: %s, snapshot: %s, rid: %s", err, lbID,
+			snapshotID, kt.Rid)
+		return nil, err
+	}
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "snapshot(%s) of load balancer(%s) not found", snapshotID, lbID)
+	}
+	return &result.Details[0], nil
+}
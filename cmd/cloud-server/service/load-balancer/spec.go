@@ -0,0 +1,268 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	lblogic "hcm/cmd/cloud-server/logics/load-balancer"
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/api/hc-service/sync"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/counter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// applySpecBatchSize 应用声明式配置时单个任务包含的最大变更数
+const applySpecBatchSize = 50
+
+// ExportLoadBalancerSpec 导出负载均衡当前配置为声明式配置
+func (svc *lbSvc) ExportLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.exportLoadBalancerSpec(cts, handler.ResOperateAuth)
+}
+
+// ExportBizLoadBalancerSpec 导出业务下负载均衡当前配置为声明式配置
+func (svc *lbSvc) ExportBizLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.exportLoadBalancerSpec(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) exportLoadBalancerSpec(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	any, error) {
+
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := lblogic.LoadTCloudSpecState(cts.Kit, svc.client.DataService(), lbID)
+	if err != nil {
+		logs.Errorf("load load balancer spec state failed, err: %v, lb: %s, rid: %s", err, lbID, cts.Kit.Rid)
+		return nil, err
+	}
+	return lblogic.ExportTCloudSpec(state), nil
+}
+
+// PlanLoadBalancerSpec 对比声明式配置与负载均衡当前状态
+func (svc *lbSvc) PlanLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.planLoadBalancerSpec(cts, handler.ResOperateAuth)
+}
+
+// PlanBizLoadBalancerSpec 对比声明式配置与业务下负载均衡当前状态
+func (svc *lbSvc) PlanBizLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.planLoadBalancerSpec(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) planLoadBalancerSpec(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (any, error) {
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Find)
+	if err != nil {
+		return nil, err
+	}
+
+	_, plan, err := svc.buildLoadBalancerSpecPlan(cts, lbID)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ApplyLoadBalancerSpec 将声明式配置与当前状态的差异作为一个异步任务应用到负载均衡
+func (svc *lbSvc) ApplyLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.applyLoadBalancerSpec(cts, handler.ResOperateAuth)
+}
+
+// ApplyBizLoadBalancerSpec 将声明式配置与当前状态的差异作为一个异步任务应用到业务下负载均衡
+func (svc *lbSvc) ApplyBizLoadBalancerSpec(cts *rest.Contexts) (any, error) {
+	return svc.applyLoadBalancerSpec(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) applyLoadBalancerSpec(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (any, error) {
+	lbID, err := svc.authLoadBalancerSpec(cts, validHandler, meta.Update)
+	if err != nil {
+		return nil, err
+	}
+
+	state, plan, err := svc.buildLoadBalancerSpecPlan(cts, lbID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.applyLoadBalancerSpecPlan(cts.Kit, state, plan, enumor.LbSnapshotSpecApply)
+}
+
+// applyLoadBalancerSpecPlan 记录审计并将变更作为一个异步任务执行，变更前保存负载均衡配置快照
+func (svc *lbSvc) applyLoadBalancerSpecPlan(kt *kit.Kit, state *lblogic.TCloudSpecState,
+	plan *cslb.TCloudSpecPlanResult, source enumor.LbSnapshotSource) (*cslb.TCloudSpecApplyResult, error) {
+
+	lbID := state.LoadBalancer.ID
+	result := &cslb.TCloudSpecApplyResult{TCloudSpecPlanResult: *plan}
+	if len(plan.Changes) == 0 {
+		return result, nil
+	}
+
+	auditFields := map[string]any{"spec_changes": plan.Changes}
+	if err := svc.audit.ResUpdateAudit(kt, enumor.LoadBalancerAuditResType, lbID, auditFields); err != nil {
+		logs.Errorf("create apply spec audit failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, err
+	}
+
+	flowID, err := svc.buildApplySpecFlow(kt, state, plan.Changes, source)
+	if err != nil {
+		logs.Errorf("build apply spec flow failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, err
+	}
+	result.FlowID = flowID
+	return result, nil
+}
+
+// authLoadBalancerSpec 校验负载均衡的业务及权限，返回负载均衡ID
+func (svc *lbSvc) authLoadBalancerSpec(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	act meta.Action) (string, error) {
+
+	lbID := cts.PathParameter("id").String()
+	if len(lbID) == 0 {
+		return "", errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.LoadBalancerCloudResType,
+		lbID)
+	if err != nil {
+		logs.Errorf("get load balancer basic info failed, err: %v, id: %s, rid: %s", err, lbID, cts.Kit.Rid)
+		return "", err
+	}
+	if basicInfo.Vendor != enumor.TCloud {
+		return "", errf.Newf(errf.InvalidParameter, "vendor(%s) not supported for load balancer spec",
+			basicInfo.Vendor)
+	}
+
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.LoadBalancer,
+		Action: act, BasicInfo: basicInfo})
+	if err != nil {
+		return "", err
+	}
+	return lbID, nil
+}
+
+// buildLoadBalancerSpecPlan 解析请求中的声明式配置并与负载均衡当前状态对比，开启 refresh 时先从云上同步
+func (svc *lbSvc) buildLoadBalancerSpecPlan(cts *rest.Contexts, lbID string) (*lblogic.TCloudSpecState,
+	*cslb.TCloudSpecPlanResult, error) {
+
+	req := new(cslb.TCloudSpecReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	spec, err := req.Parse()
+	if err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.diffLoadBalancerSpec(cts.Kit, lbID, spec, req.Refresh)
+}
+
+// diffLoadBalancerSpec 对比声明式配置与负载均衡当前状态，refresh 为 true 时先从云上同步
+func (svc *lbSvc) diffLoadBalancerSpec(kt *kit.Kit, lbID string, spec *corelb.TCloudLoadBalancerSpec,
+	refresh bool) (*lblogic.TCloudSpecState, *cslb.TCloudSpecPlanResult, error) {
+
+	dataCli := svc.client.DataService()
+	state, err := lblogic.LoadTCloudSpecState(kt, dataCli, lbID)
+	if err != nil {
+		logs.Errorf("load load balancer spec state failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, nil, err
+	}
+	if refresh {
+		lb := state.LoadBalancer
+		syncReq := &sync.TCloudSyncReq{AccountID: lb.AccountID, Region: lb.Region, CloudIDs: []string{lb.CloudID}}
+		if err = svc.client.HCService().TCloud.Clb.SyncLoadBalancer(kt, syncReq); err != nil {
+			logs.Errorf("sync load balancer failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+			return nil, nil, err
+		}
+		if state, err = lblogic.LoadTCloudSpecState(kt, dataCli, lbID); err != nil {
+			logs.Errorf("reload load balancer spec state failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+			return nil, nil, err
+		}
+	}
+
+	plan, err := lblogic.DiffTCloudSpec(state, spec)
+	if err != nil {
+		logs.Errorf("diff load balancer spec failed, err: %v, lb: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, nil, err
+	}
+	return state, plan, nil
+}
+
+// buildApplySpecFlow 每个阶段的变更串行执行，最后同步负载均衡，执行期间锁定负载均衡
+func (svc *lbSvc) buildApplySpecFlow(kt *kit.Kit, state *lblogic.TCloudSpecState,
+	changes []corelb.TCloudSpecChange, source enumor.LbSnapshotSource) (string, error) {
+
+	lb := state.LoadBalancer
+	if _, err := svc.checkResFlowRel(kt, lb.ID, enumor.LoadBalancerCloudResType); err != nil {
+		logs.Errorf("check resource flow relation failed, err: %v, lb: %s, rid: %s", err, lb.ID, kt.Rid)
+		return "", err
+	}
+	snapshotID, err := lblogic.CreateTCloudSnapshot(kt, svc.client.DataService(), state, source)
+	if err != nil {
+		return "", err
+	}
+
+	getNextID := counter.NewNumberCounterWithPrev(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0)
+	for _, part := range lblogic.SplitTCloudSpecChanges(changes, applySpecBatchSize) {
+		cur, prev := getNextID()
+		task := ts.CustomFlowTask{
+			ActionID:   action.ActIDType(cur),
+			ActionName: enumor.ActionLoadBalancerApplySpec,
+			Params: &actionlb.ApplyTCloudSpecOption{
+				Vendor:  lb.Vendor,
+				LbID:    lb.ID,
+				Changes: part,
+			},
+			Retry: tableasync.NewRetryWithPolicy(3, 1000, 5000),
+		}
+		if prev != "" {
+			task.DependOn = []action.ActIDType{action.ActIDType(prev)}
+		}
+		tasks = append(tasks, task)
+	}
+	tasks = append(tasks, buildSyncClbFlowTask(lb.Vendor, lb.CloudID, lb.AccountID, lb.Region, getNextID))
+
+	shareData := tableasync.NewShareData(map[string]string{"lb_id": lb.ID})
+	flowID, err := svc.buildFlow(kt, enumor.FlowLoadBalancerApplySpec, shareData, tasks)
+	if err != nil {
+		return "", err
+	}
+	lblogic.BindSnapshotFlow(kt, svc.client.DataService(), snapshotID, flowID)
+	if err = svc.buildSubFlow(kt, flowID, lb.ID, nil, "", enumor.ApplySpecTaskType); err != nil {
+		return "", err
+	}
+	if err = svc.lockResFlowStatus(kt, lb.ID, enumor.LoadBalancerCloudResType, flowID,
+		enumor.ApplySpecTaskType); err != nil {
+		return "", err
+	}
+	return flowID, nil
+}
@@ -189,6 +189,8 @@ func (svc *lbSvc) getTargetGroup(cts *rest.Contexts, validHandler handler.ListAu
 	switch basicInfo.Vendor {
 	case enumor.TCloud:
 		return svc.getTCloudTargetGroup(cts.Kit, id)
+	case enumor.Aws:
+		return svc.getAwsTargetGroup(cts.Kit, id)
 
 	default:
 		return nil, errf.Newf(errf.Unknown, "id: %s vendor: %s not support", id, basicInfo.Vendor)
@@ -216,6 +218,27 @@ func (svc *lbSvc) getTCloudTargetGroup(kt *kit.Kit, tgID string) (*cslb.GetTarge
 	return result, nil
 }
 
+func (svc *lbSvc) getAwsTargetGroup(kt *kit.Kit, tgID string) (*cslb.GetTargetGroupDetail, error) {
+	targetGroupInfo, err := svc.client.DataService().Aws.LoadBalancer.GetTargetGroup(kt, tgID)
+	if err != nil {
+		logs.Errorf("get aws target group detail failed, tgID: %s, err: %v, rid: %s", tgID, err, kt.Rid)
+		return nil, err
+	}
+
+	targetList, err := svc.getTargetByTGIDs(kt, []string{tgID})
+	if err != nil {
+		logs.Errorf("list target db failed, tgID: %s, err: %v, rid: %s", tgID, err, kt.Rid)
+		return nil, err
+	}
+
+	result := &cslb.GetTargetGroupDetail{
+		BaseTargetGroup: targetGroupInfo.BaseTargetGroup,
+		TargetList:      targetList,
+	}
+
+	return result, nil
+}
+
 // 查询目标组，查不到时返回nil
 func (svc *lbSvc) getTargetGroupByID(kt *kit.Kit, targetGroupID string) (*corelb.BaseTargetGroup, error) {
 
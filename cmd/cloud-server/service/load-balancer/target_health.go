@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"sort"
+	"time"
+
+	lblogic "hcm/cmd/cloud-server/logics/load-balancer"
+	"hcm/cmd/cloud-server/logics/tenant"
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// defaultFlappingMinTransitions 默认健康状态变化次数达到该值的RS视为抖动
+const defaultFlappingMinTransitions = 3
+
+// TargetHealthCollectTiming 定时采集各租户负载均衡下RS的健康状态，只在主节点执行
+func TargetHealthCollectTiming(c *client.ClientSet, sd serviced.State, cmsiCli cmsi.Client, bkHcmUrl string,
+	conf cc.TargetHealth) {
+
+	interval := time.Duration(conf.CollectIntervalMin) * time.Minute
+	logs.Infof("target health collect enable, interval: %v, retention days: %d", interval, conf.RetentionDays)
+
+	collector := lblogic.NewTargetHealthCollector(c, cmsiCli, bkHcmUrl, conf)
+	for {
+		time.Sleep(interval)
+
+		if !sd.IsMaster() {
+			collector.Reset()
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		tenantIDs, err := tenant.ListAllTenantID(kt, c.DataService())
+		if err != nil {
+			logs.Errorf("failed to list all tenant ids, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		now := time.Now()
+		for _, tenantID := range tenantIDs {
+			subKt := kt.NewSubKitWithTenant(tenantID)
+			if err = collector.Collect(subKt, now); err != nil {
+				logs.Errorf("collect target health failed, err: %v, tenant: %s, rid: %s", err, tenantID, subKt.Rid)
+			}
+		}
+	}
+}
+
+// ListBizTargetHealthEvent 查询业务下RS健康状态变化事件
+func (svc *lbSvc) ListBizTargetHealthEvent(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer,
+		ResType:    meta.LoadBalancer,
+		Action:     meta.Find,
+		Filter:     req.Filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if noPermFlag {
+		return &core.ListResult{Count: 0, Details: make([]any, 0)}, nil
+	}
+
+	req.Filter = expr
+	if len(req.Page.Sort) == 0 {
+		req.Page.Sort = "id"
+		req.Page.Order = core.Descending
+	}
+	return svc.client.DataService().Global.LoadBalancer.ListTargetHealthEvent(cts.Kit, req)
+}
+
+// ListBizTargetHealthFlapping 查询业务下统计时间段内健康状态频繁变化的RS，按变化次数降序排列
+func (svc *lbSvc) ListBizTargetHealthFlapping(cts *rest.Contexts) (any, error) {
+	req, stats, err := svc.statBizTargetHealth(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	minTransitions := req.MinTransitions
+	if minTransitions == 0 {
+		minTransitions = defaultFlappingMinTransitions
+	}
+	details := make([]corelb.TargetHealthStat, 0)
+	for _, stat := range stats {
+		if stat.Transitions >= minTransitions {
+			details = append(details, stat)
+		}
+	}
+	sort.SliceStable(details, func(i, j int) bool { return details[i].Transitions > details[j].Transitions })
+	return &cslb.TargetHealthStatResult{Details: details}, nil
+}
+
+// ListBizTargetHealthDowntime 查询业务下统计时间段内处于不健康状态的RS，按不健康时长降序排列
+func (svc *lbSvc) ListBizTargetHealthDowntime(cts *rest.Contexts) (any, error) {
+	_, stats, err := svc.statBizTargetHealth(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]corelb.TargetHealthStat, 0)
+	for _, stat := range stats {
+		if stat.DowntimeSec > 0 {
+			details = append(details, stat)
+		}
+	}
+	sort.SliceStable(details, func(i, j int) bool { return details[i].DowntimeSec > details[j].DowntimeSec })
+	return &cslb.TargetHealthStatResult{Details: details}, nil
+}
+
+func (svc *lbSvc) statBizTargetHealth(cts *rest.Contexts) (*cslb.TargetHealthStatReq, []corelb.TargetHealthStat,
+	error) {
+
+	req := new(cslb.TargetHealthStatReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	start, end, err := req.TimeRange()
+	if err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 时间段开始前的事件用于确定开始时RS的健康状态，因此只限制结束时间
+	reqFilter := tools.ExpressionAnd(tools.RuleLessThanEqual("created_at", end.Format(constant.TimeStdFormat)))
+	if len(req.LbIDs) != 0 {
+		reqFilter.Rules = append(reqFilter.Rules, tools.RuleIn("lb_id", req.LbIDs))
+	}
+	expr, noPermFlag, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{
+		Authorizer: svc.authorizer,
+		ResType:    meta.LoadBalancer,
+		Action:     meta.Find,
+		Filter:     reqFilter,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if noPermFlag {
+		return req, make([]corelb.TargetHealthStat, 0), nil
+	}
+
+	events, err := lblogic.ListTargetHealthEvents(cts.Kit, svc.client.DataService(), expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	stats, err := lblogic.StatTargetHealth(events, start, end)
+	if err != nil {
+		logs.Errorf("stat target health failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, nil, err
+	}
+	return req, stats, nil
+}
@@ -418,6 +418,11 @@ func (svc *lbSvc) getLblByCond(kt *kit.Kit, vendor enumor.Vendor, lblCond []filt
 				logs.Errorf("get lbl failed, err: %v, req: %+v, rid: %s", err, lblReq, kt.Rid)
 				return nil, err
 			}
+		case enumor.Aws:
+			resp, err = svc.listAwsListenerForTopo(kt, &lblReq)
+			if err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("vendor: %s not support", vendor)
 		}
@@ -434,6 +439,23 @@ func (svc *lbSvc) getLblByCond(kt *kit.Kit, vendor enumor.Vendor, lblCond []filt
 	return lblMap, nil
 }
 
+// listAwsListenerForTopo list aws listener for topo, topo only uses the base info of listener, so the aws
+// listener is returned without extension in the same result as tcloud.
+func (svc *lbSvc) listAwsListenerForTopo(kt *kit.Kit, req *core.ListReq) (*cloud.TCloudListenerListResult, error) {
+	resp, err := svc.client.DataService().Aws.LoadBalancer.ListListener(kt, req)
+	if err != nil {
+		logs.Errorf("get aws lbl failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+
+	result := &cloud.TCloudListenerListResult{Count: resp.Count, Details: make([]corelb.TCloudListener, 0,
+		len(resp.Details))}
+	for _, lbl := range resp.Details {
+		result.Details = append(result.Details, corelb.TCloudListener{BaseListener: lbl.BaseListener})
+	}
+	return result, nil
+}
+
 // getRuleByCond get rule by condition
 func (svc *lbSvc) getRuleByCond(kt *kit.Kit, vendor enumor.Vendor, ruleCond []filter.RuleFactory) (
 	map[string]corelb.TCloudLbUrlRule, error) {
@@ -457,6 +479,9 @@ func (svc *lbSvc) getRuleByCond(kt *kit.Kit, vendor enumor.Vendor, ruleCond []fi
 				logs.Errorf("get rule failed, err: %v, req: %+v, rid: %s", err, ruleReq, kt.Rid)
 				return nil, err
 			}
+		case enumor.Aws:
+			// aws监听器的转发规则未同步，没有url规则
+			resp.Details = make([]corelb.TCloudLbUrlRule, 0)
 		default:
 			return nil, fmt.Errorf("vendor: %s not support", vendor)
 		}
@@ -620,6 +645,11 @@ func (svc *lbSvc) listListenerByTopo(kt *kit.Kit, bizID int64, vendor enumor.Ven
 			logs.Errorf("get lbl failed, err: %v, req: %+v, rid: %s", err, lblReq, kt.Rid)
 			return nil, err
 		}
+	case enumor.Aws:
+		resp, err = svc.listAwsListenerForTopo(kt, &lblReq)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", vendor)
 	}
@@ -954,6 +984,9 @@ func (svc *lbSvc) listUrlRulesByTopo(kt *kit.Kit, bizID int64, vendor enumor.Ven
 			logs.Errorf("get url rule failed, err: %v, req: %+v, rid: %s", err, ruleReq, kt.Rid)
 			return nil, err
 		}
+	case enumor.Aws:
+		// aws监听器的转发规则未同步，没有url规则
+		resp.Details = make([]corelb.TCloudLbUrlRule, 0)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", vendor)
 	}
@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	lblogic "hcm/cmd/cloud-server/logics/load-balancer"
+	cslb "hcm/pkg/api/cloud-server/load-balancer"
+	"hcm/pkg/api/cloud-server/task"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// CreateBizTargetGroupTrafficShift 将目标组下旧RS的流量分多步切换到新RS，每一步之间检查新RS的健康状态
+func (svc *lbSvc) CreateBizTargetGroupTrafficShift(cts *rest.Contexts) (any, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+	tgID := cts.PathParameter("target_group_id").String()
+	if len(tgID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "target_group_id is required")
+	}
+
+	req := new(cslb.TrafficShiftCreateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		logs.Errorf("create traffic shift request decode failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	targetIDs := slice.Unique(append(slice.Unique(req.OldTargetIDs), req.NewTargetIDs...))
+	targets, err := svc.listTargetsByIDs(cts.Kit, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) != len(targetIDs) {
+		return nil, fmt.Errorf("list target failed, expected: %d, actual: %d", len(targetIDs), len(targets))
+	}
+	for _, target := range targets {
+		if target.TargetGroupID != tgID {
+			return nil, errf.Newf(errf.InvalidParameter, "target: %s not belong to target group: %s", target.ID,
+				tgID)
+		}
+		if target.AccountID != req.AccountID {
+			return nil, errf.Newf(errf.InvalidParameter, "target account_id: %s not match req account_id: %s",
+				target.AccountID, req.AccountID)
+		}
+	}
+
+	if err = svc.authBatchModifyTargetWeight(cts, targets, handler.BizOperateAuth); err != nil {
+		return nil, err
+	}
+
+	plan, err := svc.buildTrafficShiftPlan(cts, tgID, req, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	taskManagementID, err := lblogic.CreateTrafficShift(cts.Kit, svc.client.DataService(),
+		svc.client.TaskServer(), bizID, plan)
+	if err != nil {
+		logs.Errorf("create traffic shift failed, err: %v, tgID: %s, rid: %s", err, tgID, cts.Kit.Rid)
+		return nil, err
+	}
+	return task.CreateTaskManagementResp{TaskManagementID: taskManagementID}, nil
+}
+
+func (svc *lbSvc) buildTrafficShiftPlan(cts *rest.Contexts, tgID string, req *cslb.TrafficShiftCreateReq,
+	targets []corelb.BaseTarget) (*corelb.TrafficShiftPlan, error) {
+
+	relsMap, err := svc.listTGListenerRuleRelMapByTGIDs(cts.Kit, []string{tgID})
+	if err != nil {
+		return nil, err
+	}
+	rel, exist := relsMap[tgID]
+	if !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "target group: %s is not bound to any listener", tgID)
+	}
+	if rel.Vendor != enumor.TCloud {
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support traffic shift", rel.Vendor)
+	}
+
+	plan := &corelb.TrafficShiftPlan{
+		Vendor:              rel.Vendor,
+		AccountID:           req.AccountID,
+		Region:              targets[0].TargetGroupRegion,
+		LbID:                rel.LbID,
+		CloudLbID:           rel.CloudLbID,
+		TargetGroupID:       tgID,
+		CloudLblID:          rel.CloudLblID,
+		CloudRuleID:         rel.CloudListenerRuleID,
+		RuleType:            rel.ListenerRuleType,
+		Steps:               req.Steps,
+		StepIntervalSec:     req.StepIntervalSec,
+		MaxUnhealthyPercent: cvt.PtrToVal(req.MaxUnhealthyPercent),
+		Targets:             make([]corelb.TrafficShiftTarget, 0, len(targets)),
+	}
+	newIDs := make(map[string]struct{}, len(req.NewTargetIDs))
+	for _, id := range req.NewTargetIDs {
+		newIDs[id] = struct{}{}
+	}
+	for _, target := range targets {
+		shiftTarget := corelb.TrafficShiftTarget{
+			ID:          target.ID,
+			Role:        enumor.TrafficShiftTargetOld,
+			InstType:    target.InstType,
+			CloudInstID: target.CloudInstID,
+			IP:          target.IP,
+			Port:        target.Port,
+			FromWeight:  cvt.PtrToVal(target.Weight),
+		}
+		if _, ok := newIDs[target.ID]; ok {
+			shiftTarget.Role = enumor.TrafficShiftTargetNew
+			shiftTarget.ToWeight = cvt.PtrToVal(req.NewWeight)
+		}
+		plan.Targets = append(plan.Targets, shiftTarget)
+	}
+	return plan, nil
+}
@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package reachability ...
+package reachability
+
+import (
+	"net/http"
+
+	logicsreach "hcm/cmd/cloud-server/logics/reachability"
+	"hcm/cmd/cloud-server/service/capability"
+	cloudserver "hcm/pkg/api/cloud-server"
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitService initialize the network reachability service.
+func InitService(c *capability.Capability) {
+	svc := &reachabilitySvc{
+		client:       c.ApiClient,
+		authorizer:   c.Authorizer,
+		reachability: c.Logics.Reachability,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("AnalyzeReachability", http.MethodPost, "/network/reachability/analyze", svc.AnalyzeReachability)
+	h.Add("AnalyzeBizReachability", http.MethodPost, "/bizs/{bk_biz_id}/network/reachability/analyze",
+		svc.AnalyzeBizReachability)
+
+	h.Load(c.WebService)
+}
+
+type reachabilitySvc struct {
+	client       *client.ClientSet
+	authorizer   auth.Authorizer
+	reachability logicsreach.Interface
+}
+
+// AnalyzeReachability analyze network reachability between resources.
+func (svc *reachabilitySvc) AnalyzeReachability(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyze(cts, 0, handler.ResOperateAuth)
+}
+
+// AnalyzeBizReachability analyze network reachability between resources in biz.
+func (svc *reachabilitySvc) AnalyzeBizReachability(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.analyze(cts, bizID, handler.BizOperateAuth)
+}
+
+func (svc *reachabilitySvc) analyze(cts *rest.Contexts, bizID int64, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudserver.ReachabilityAnalyzeReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 指定的VPC需要先鉴权，避免越权查询VPC下的子网
+	for _, one := range []corereach.Endpoint{req.Source, req.Destination} {
+		if one.VpcID == "" {
+			continue
+		}
+		if err := svc.authorize(cts, validHandler, enumor.VpcCloudResType, one.VpcID); err != nil {
+			return nil, err
+		}
+	}
+
+	opt := &logicsreach.AnalyzeOption{
+		BizID:       bizID,
+		Source:      req.Source,
+		Destination: req.Destination,
+		Protocol:    req.Protocol,
+		Port:        req.Port,
+	}
+	result, err := svc.reachability.Analyze(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("analyze reachability failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	// 端点解析出的主机、负载均衡需要有查看权限，否则不返回分析结果
+	for _, one := range []corereach.ResolvedEndpoint{result.Source, result.Destination} {
+		if one.ResID == "" {
+			continue
+		}
+		if err = svc.authorize(cts, validHandler, one.ResType, one.ResID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+var resAuthTypes = map[enumor.CloudResourceType]meta.ResourceType{
+	enumor.VpcCloudResType:          meta.Vpc,
+	enumor.CvmCloudResType:          meta.Cvm,
+	enumor.LoadBalancerCloudResType: meta.LoadBalancer,
+}
+
+func (svc *reachabilitySvc) authorize(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	resType enumor.CloudResourceType, id string) error {
+
+	authType, exists := resAuthTypes[resType]
+	if !exists {
+		return errf.Newf(errf.InvalidParameter, "unsupported resource type %s", resType)
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, resType, id)
+	if err != nil {
+		logs.Errorf("get resource basic info failed, err: %v, type: %s, id: %s, rid: %s", err, resType, id,
+			cts.Kit.Rid)
+		return err
+	}
+
+	return validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: authType,
+		Action: meta.Find, BasicInfo: basicInfo})
+}
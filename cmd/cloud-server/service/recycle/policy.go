@@ -0,0 +1,246 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	proto "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// ListRecyclePolicy list recycle policy.
+func (svc *svc) ListRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.listRecyclePolicy(cts, 0)
+}
+
+// ListBizRecyclePolicy list recycle policy which takes effect in biz, including policies for all biz.
+func (svc *svc) ListBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.listRecyclePolicy(cts, bizID)
+}
+
+func (svc *svc) listRecyclePolicy(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeRecyclePolicy(cts.Kit, meta.Find, bizID); err != nil {
+		return nil, err
+	}
+
+	if bizID > 0 {
+		bizFilter := tools.ContainersExpression("bk_biz_id", []int64{bizID, constant.UnassignedBiz})
+		expr, err := tools.And(req.Filter, bizFilter)
+		if err != nil {
+			return nil, err
+		}
+		req.Filter = expr
+	}
+
+	return svc.client.DataService().Global.RecycleRecord.ListRecyclePolicy(cts.Kit, req)
+}
+
+// CreateRecyclePolicy create recycle policy.
+func (svc *svc) CreateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.createRecyclePolicy(cts, 0)
+}
+
+// CreateBizRecyclePolicy create recycle policy of biz.
+func (svc *svc) CreateBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.createRecyclePolicy(cts, bizID)
+}
+
+func (svc *svc) createRecyclePolicy(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	req := new(proto.PolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 业务下只能创建本业务的策略
+	if bizID > 0 {
+		req.BkBizID = bizID
+	}
+	if req.BkBizID == 0 || req.BkBizID < constant.UnassignedBiz {
+		return nil, errf.New(errf.InvalidParameter, "bk_biz_id should be -1 or a valid biz id")
+	}
+
+	if err := svc.authorizeRecyclePolicy(cts.Kit, meta.Update, bizID); err != nil {
+		return nil, err
+	}
+
+	createReq := &dsrr.PolicyCreateReq{
+		BkBizID:           req.BkBizID,
+		ResType:           req.ResType,
+		RetentionHours:    req.RetentionHours,
+		NotifyBeforeHours: req.NotifyBeforeHours,
+		TimeZone:          req.TimeZone,
+		PurgeWindows:      req.PurgeWindows,
+		Memo:              req.Memo,
+	}
+	result, err := svc.client.DataService().Global.RecycleRecord.CreateRecyclePolicy(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create recycle policy failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (svc *svc) UpdateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.updateRecyclePolicy(cts, 0)
+}
+
+// UpdateBizRecyclePolicy update recycle policy of biz.
+func (svc *svc) UpdateBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.updateRecyclePolicy(cts, bizID)
+}
+
+func (svc *svc) updateRecyclePolicy(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.PolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeRecyclePolicy(cts.Kit, meta.Update, bizID); err != nil {
+		return nil, err
+	}
+
+	if err := svc.checkRecyclePolicyBiz(cts.Kit, id, bizID); err != nil {
+		return nil, err
+	}
+
+	updateReq := &dsrr.PolicyUpdateReq{
+		ID:                id,
+		RetentionHours:    req.RetentionHours,
+		NotifyBeforeHours: req.NotifyBeforeHours,
+		TimeZone:          req.TimeZone,
+		PurgeWindows:      req.PurgeWindows,
+		Memo:              req.Memo,
+	}
+	if err := svc.client.DataService().Global.RecycleRecord.UpdateRecyclePolicy(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update recycle policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteRecyclePolicy delete recycle policy.
+func (svc *svc) DeleteRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	return svc.deleteRecyclePolicy(cts, 0)
+}
+
+// DeleteBizRecyclePolicy delete recycle policy of biz.
+func (svc *svc) DeleteBizRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	return svc.deleteRecyclePolicy(cts, bizID)
+}
+
+func (svc *svc) deleteRecyclePolicy(cts *rest.Contexts, bizID int64) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.authorizeRecyclePolicy(cts.Kit, meta.Update, bizID); err != nil {
+		return nil, err
+	}
+
+	if err := svc.checkRecyclePolicyBiz(cts.Kit, id, bizID); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := svc.client.DataService().Global.RecycleRecord.BatchDeleteRecyclePolicy(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete recycle policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// checkRecyclePolicyBiz 业务下只能操作本业务的策略，对所有业务生效的策略需要在资源下操作
+func (svc *svc) checkRecyclePolicyBiz(kt *kit.Kit, id string, bizID int64) error {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "bk_biz_id"},
+	}
+	result, err := svc.client.DataService().Global.RecycleRecord.ListRecyclePolicy(kt, listReq)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return errf.Newf(errf.RecordNotFound, "recycle policy: %s not found", id)
+	}
+
+	if bizID > 0 && result.Details[0].BkBizID != bizID {
+		return errf.Newf(errf.InvalidParameter, "recycle policy: %s does not belong to biz: %d", id, bizID)
+	}
+
+	return nil
+}
+
+func (svc *svc) authorizeRecyclePolicy(kt *kit.Kit, action meta.Action, bizID int64) error {
+	return svc.authorizer.AuthorizeWithPerm(kt, meta.ResourceAttribute{
+		Basic: &meta.Basic{Type: meta.RecycleBin, Action: action},
+		BizID: bizID,
+	})
+}
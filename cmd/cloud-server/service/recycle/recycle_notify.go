@@ -0,0 +1,176 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	"hcm/cmd/cloud-server/logics/tenant"
+	"hcm/pkg/api/core"
+	recyclerecord "hcm/pkg/api/core/recycle-record"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+const notifyInterval = 10 * time.Minute
+
+// notifyTiming 定时通知回收人即将被销毁的资源，给回收人在销毁前恢复资源的机会
+func (r *recycle) notifyTiming(conf cc.Recycle) {
+	for {
+		time.Sleep(notifyInterval)
+
+		if !r.state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		tenantIDs, err := tenant.ListAllTenantID(kt, r.client.DataService())
+		if err != nil {
+			logs.Errorf("failed to list all tenant ids, err: %v, rid: %s", err, kt.Rid)
+			continue
+		}
+
+		for _, tenantID := range tenantIDs {
+			r.notifyBeforePurge(kt.NewSubKitWithTenant(tenantID), conf)
+		}
+	}
+}
+
+func (r *recycle) notifyBeforePurge(kt *kit.Kit, conf cc.Recycle) {
+	policies, err := logicsrecycle.ListAllRecyclePolicy(kt, r.client.DataService(), tools.AllExpression())
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+
+	maxNotifyHours := conf.NotifyBeforeHour
+	for _, policy := range policies {
+		maxNotifyHours = max(maxNotifyHours, policy.NotifyBeforeHours)
+	}
+	if maxNotifyHours == 0 {
+		return
+	}
+
+	now := time.Now()
+	deadline := times.ConvStdTimeFormat(now.Add(time.Duration(maxNotifyHours) * time.Hour))
+
+	lastID := ""
+	for {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("status", enumor.WaitingRecycleRecordStatus),
+				tools.RuleEqual("notified", false),
+				tools.RuleNotEqual("recycle_type", enumor.RecycleTypeRelated),
+				tools.RuleLessThanEqual("recycled_at", deadline),
+				tools.RuleIDGreaterThan(lastID),
+			),
+			Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+			Fields: []string{"id", "res_type", "res_id", "cloud_res_id", "res_name", "bk_biz_id", "recycled_at",
+				"creator"},
+		}
+		result, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt, listReq)
+		if err != nil {
+			logs.Errorf("list recycle record to notify failed, err: %v, rid: %s", err, kt.Rid)
+			return
+		}
+
+		// 按回收人汇总需要通知的回收记录
+		creatorRecords := make(map[string][]recyclerecord.RecycleRecord)
+		for _, record := range result.Details {
+			if !needNotify(kt, policies, conf, record, now) {
+				continue
+			}
+			creatorRecords[record.Creator] = append(creatorRecords[record.Creator], record)
+		}
+
+		for creator, records := range creatorRecords {
+			r.sendPurgeNotice(kt, creator, records)
+			r.markRecordNotified(kt, records)
+		}
+
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			return
+		}
+		lastID = result.Details[len(result.Details)-1].ID
+	}
+}
+
+// needNotify 判断回收记录是否已进入销毁前通知时间，优先使用适用的回收站保留策略中的通知时间，没有适用的策略时使用默认配置
+func needNotify(kt *kit.Kit, policies []recyclerecord.Policy, conf cc.Recycle, record recyclerecord.RecycleRecord,
+	now time.Time) bool {
+
+	notifyHours := conf.NotifyBeforeHour
+	if policy := recyclerecord.MatchPolicy(policies, record.BkBizID, record.ResType); policy != nil {
+		notifyHours = policy.NotifyBeforeHours
+	}
+	if notifyHours == 0 {
+		return false
+	}
+
+	recycledAt, err := time.Parse(constant.TimeStdFormat, record.RecycledAt)
+	if err != nil {
+		logs.Errorf("parse recycle record(%s) recycled_at failed, err: %v, rid: %s", record.ID, err, kt.Rid)
+		return false
+	}
+
+	return recycledAt.Sub(now) <= time.Duration(notifyHours)*time.Hour
+}
+
+func (r *recycle) sendPurgeNotice(kt *kit.Kit, creator string, records []recyclerecord.RecycleRecord) {
+	items := make([]string, 0, len(records))
+	for _, record := range records {
+		items = append(items, fmt.Sprintf("<li>%s %s（%s），业务：%d，预计销毁时间：%s</li>", record.ResType,
+			record.ResName, record.CloudResID, record.BkBizID, record.RecycledAt))
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: creator,
+		Title:            fmt.Sprintf("【HCM】 回收站资源即将销毁：共%d个", len(records)),
+		Content: fmt.Sprintf(`<p>您好：</p><p>您回收的以下资源即将被销毁，销毁后无法恢复，如需保留请在销毁前登录 `+
+			`<a href="%s">HCM</a> 回收站中恢复。</p><ul>%s</ul>`, r.bkHcmUrl, strings.Join(items, "")),
+	}
+	if err := r.cmsiCli.SendMail(kt, mail); err != nil {
+		logs.Errorf("send recycle purge notice failed, err: %v, creator: %s, rid: %s", err, creator, kt.Rid)
+	}
+}
+
+// markRecordNotified 标记回收记录已通知，通知发送失败时同样标记，避免重复发送
+func (r *recycle) markRecordNotified(kt *kit.Kit, records []recyclerecord.RecycleRecord) {
+	for _, batch := range slice.Split(records, constant.BatchOperationMaxLimit) {
+		updateReq := &dsrr.BatchUpdateReq{Data: slice.Map(batch, func(one recyclerecord.RecycleRecord) dsrr.UpdateReq {
+			return dsrr.UpdateReq{ID: one.ID, Notified: converter.ValToPtr(true)}
+		})}
+		if err := r.client.DataService().Global.RecycleRecord.BatchUpdateRecycleRecord(kt, updateReq); err != nil {
+			logs.Errorf("mark recycle record notified failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
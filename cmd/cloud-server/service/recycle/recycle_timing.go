@@ -24,11 +24,16 @@ import (
 	"time"
 
 	"hcm/cmd/cloud-server/logics"
-	"hcm/cmd/cloud-server/logics/recycle"
+	"hcm/cmd/cloud-server/logics/async"
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
 	"hcm/cmd/cloud-server/logics/tenant"
+	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
+	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	"hcm/pkg/api/core"
 	recyclerecord "hcm/pkg/api/core/recycle-record"
 	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
+	ts "hcm/pkg/api/task-server"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
@@ -40,6 +45,7 @@ import (
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/serviced"
 	"hcm/pkg/thirdparty/api-gateway/cmdb"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/tools/retry"
 	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/times"
@@ -48,21 +54,31 @@ import (
 )
 
 type recycle struct {
-	client *client.ClientSet
-	logics *logics.Logics
-	state  serviced.State
+	client   *client.ClientSet
+	logics   *logics.Logics
+	state    serviced.State
+	cmsiCli  cmsi.Client
+	bkHcmUrl string
 }
 
 // RecycleTiming timing recycle all resource.
-func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, cmdbClient cmdb.Client) {
+func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, cmdbClient cmdb.Client,
+	cmsiCli cmsi.Client, bkHcmUrl string) {
+
 	r := &recycle{
-		client: c,
-		state:  state,
-		logics: logics.NewLogics(c, cmdbClient),
+		client:   c,
+		state:    state,
+		logics:   logics.NewLogics(c, cmdbClient),
+		cmsiCli:  cmsiCli,
+		bkHcmUrl: bkHcmUrl,
 	}
 
 	go r.recycleTiming(enumor.DiskCloudResType, r.recycleDiskWorker, conf)
 	go r.recycleTiming(enumor.CvmCloudResType, r.recycleCvmWorker, conf)
+	go r.recycleTiming(enumor.EipCloudResType, r.recycleEipWorker, conf)
+	go r.recycleTiming(enumor.LoadBalancerCloudResType, r.recycleLoadBalancerWorker, conf)
+	go r.recycleTiming(enumor.SecurityGroupCloudResType, r.recycleSecurityGroupWorker, conf)
+	go r.notifyTiming(conf)
 }
 
 type recycleWorker func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error
@@ -104,30 +120,14 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 			tenantID := id
 			eg.Go(func() error {
 				tenantKt := kt.NewSubKitWithTenant(tenantID)
-				records, basicInfoMap, subErr := r.listRecycleRecord(tenantKt, expr, resType)
+				count, subErr := r.recycleTenantRes(tenantKt, resType, worker, expr)
 				if subErr != nil {
-					logs.Errorf("failed to list recycle record info, err: %v, rid: %s", err, tenantKt.Rid)
 					return subErr
 				}
 
-				atomic.AddInt64(&recordNum, int64(len(records)))
-				// no resource needs recycling
-				if len(records) == 0 {
-					return nil
-				}
-
-				// recycle resources one by one
-				for _, record := range records {
-					if !r.state.IsMaster() {
-						logs.Infof("recycle %s res(id: %s), but is not master, skip, rid: %s", resType, record.ResID,
-							tenantKt.Rid)
-						break
-					}
-					r.execWorker(tenantKt, worker, record, basicInfoMap)
-				}
-
-				logs.Infof("finished recycle %s, count: %d, tenant: %s, rid: %s", resType, len(records),
-					tenantID, tenantKt.Rid)
+				atomic.AddInt64(&recordNum, count)
+				logs.Infof("finished recycle %s, count: %d, tenant: %s, rid: %s", resType, count, tenantID,
+					tenantKt.Rid)
 				return nil
 			})
 		}
@@ -138,7 +138,7 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 			continue
 		}
 
-		// sleep for a while if no resource needs recycling
+		// sleep for a while if no resource needs recycling, including resources that are out of purge windows
 		if recordNum == 0 {
 			logs.Infof("no need to recycle %s, rid: %s", resType, kt.Rid)
 			time.Sleep(time.Minute * 10)
@@ -149,13 +149,84 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 	}
 }
 
+// recycleTenantRes 回收租户下到期的资源，不在回收站保留策略允许销毁时间窗口内的资源跳过，返回实际回收的资源数量
+func (r *recycle) recycleTenantRes(kt *kit.Kit, resType enumor.CloudResourceType, worker recycleWorker,
+	expr *filter.Expression) (int64, error) {
+
+	policies, err := logicsrecycle.ListAllRecyclePolicy(kt, r.client.DataService(),
+		tools.EqualExpression("res_type", resType))
+	if err != nil {
+		logs.Errorf("failed to list %s recycle policy, err: %v, rid: %s", resType, err, kt.Rid)
+		return 0, err
+	}
+
+	count := int64(0)
+	lastID := ""
+	for {
+		pageExpr, err := tools.And(expr, tools.RuleIDGreaterThan(lastID))
+		if err != nil {
+			return count, err
+		}
+
+		records, basicInfoMap, err := r.listRecycleRecord(kt, pageExpr, resType)
+		if err != nil {
+			logs.Errorf("failed to list recycle record info, err: %v, rid: %s", err, kt.Rid)
+			return count, err
+		}
+
+		// no resource needs recycling
+		if len(records) == 0 {
+			return count, nil
+		}
+
+		// recycle resources one by one
+		now := time.Now()
+		for _, record := range records {
+			if !r.state.IsMaster() {
+				logs.Infof("recycle %s res(id: %s), but is not master, skip, rid: %s", resType, record.ResID, kt.Rid)
+				return count, nil
+			}
+
+			if !inPurgeWindow(kt, policies, record, now) {
+				continue
+			}
+			r.execWorker(kt, worker, record, basicInfoMap)
+			count++
+		}
+
+		if uint(len(records)) < core.DefaultMaxPageLimit {
+			return count, nil
+		}
+		lastID = records[len(records)-1].ID
+	}
+}
+
+// inPurgeWindow 判断当前是否处于资源适用的回收站保留策略允许销毁的时间窗口内，没有适用的策略时不限制
+func inPurgeWindow(kt *kit.Kit, policies []recyclerecord.Policy, record recyclerecord.RecycleRecord,
+	now time.Time) bool {
+
+	policy := recyclerecord.MatchPolicy(policies, record.BkBizID, record.ResType)
+	if policy == nil {
+		return true
+	}
+
+	in, err := policy.InPurgeWindow(now)
+	if err != nil {
+		// 时间窗口配置异常时不阻塞回收，按不限制处理
+		logs.Errorf("check recycle policy(%s) purge window failed, err: %v, rid: %s", policy.ID, err, kt.Rid)
+		return true
+	}
+
+	return in
+}
+
 func (r *recycle) listRecycleRecord(kt *kit.Kit, expr *filter.Expression, resType enumor.CloudResourceType) (
 	[]recyclerecord.RecycleRecord, map[string]types.CloudResourceBasicInfo, error) {
 
 	listReq := &core.ListReq{
 		Filter: expr,
-		Page:   core.NewDefaultBasePage(),
-		Fields: []string{"id", "res_id", "bk_biz_id"},
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"},
+		Fields: []string{"id", "res_type", "res_id", "bk_biz_id"},
 	}
 	recordRes, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt, listReq)
 	if err != nil {
@@ -247,3 +318,61 @@ func (r *recycle) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicIn
 	}
 	return nil
 }
+
+func (r *recycle) recycleEipWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+	if err := r.logics.Eip.DeleteEip(kt, info.Vendor, info.ID); err != nil {
+		logs.Errorf("delete eip failed, err: %v, eip: %s, rid: %s", err, info.ID, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+func (r *recycle) recycleLoadBalancerWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+	flowReq := &ts.AddCustomFlowReq{
+		Name: enumor.FlowDeleteLoadBalancer,
+		Tasks: []ts.CustomFlowTask{{
+			ActionID:   "1",
+			ActionName: enumor.ActionDeleteLoadBalancer,
+			Params: actionlb.DeleteLoadBalancerOption{
+				BatchDeleteLoadBalancerReq: hcproto.BatchDeleteLoadBalancerReq{
+					AccountID: info.AccountID,
+					Region:    info.Region,
+					IDs:       []string{info.ID},
+				},
+				Vendor: info.Vendor,
+			},
+		}},
+	}
+	return r.execDeleteFlow(kt, flowReq, info)
+}
+
+func (r *recycle) recycleSecurityGroupWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+	flowReq := &ts.AddCustomFlowReq{
+		Name: enumor.FlowDeleteSecurityGroup,
+		Tasks: []ts.CustomFlowTask{{
+			ActionID:   "1",
+			ActionName: enumor.ActionDeleteSecurityGroup,
+			Params: actionsg.DeleteSGOption{
+				Vendor: info.Vendor,
+				ID:     info.ID,
+			},
+		}},
+	}
+	return r.execDeleteFlow(kt, flowReq, info)
+}
+
+// execDeleteFlow 通过异步任务删除资源，并等待任务结束
+func (r *recycle) execDeleteFlow(kt *kit.Kit, flowReq *ts.AddCustomFlowReq, info *types.CloudResourceBasicInfo) error {
+	result, err := r.client.TaskServer().CreateCustomFlow(kt, flowReq)
+	if err != nil {
+		logs.Errorf("create delete %s flow failed, err: %v, id: %s, rid: %s", info.ResType, err, info.ID, kt.Rid)
+		return err
+	}
+
+	if err = async.WaitTaskToEnd(kt, r.client.TaskServer(), result.ID); err != nil {
+		logs.Errorf("delete %s failed, err: %v, id: %s, flow: %s, rid: %s", info.ResType, err, info.ID, result.ID,
+			kt.Rid)
+		return err
+	}
+	return nil
+}
@@ -40,6 +40,20 @@ func InitService(c *capability.Capability) {
 	h.Add("ListRecycleRecord", http.MethodPost, "/recycle_records/list", svc.ListRecycleRecord)
 	h.Add("ListBizRecycleRecord", http.MethodPost, "/bizs/{bk_biz_id}/recycle_records/list", svc.ListBizRecycleRecord)
 
+	// recycle policy apis
+	h.Add("ListRecyclePolicy", http.MethodPost, "/recycle_policies/list", svc.ListRecyclePolicy)
+	h.Add("CreateRecyclePolicy", http.MethodPost, "/recycle_policies/create", svc.CreateRecyclePolicy)
+	h.Add("UpdateRecyclePolicy", http.MethodPatch, "/recycle_policies/{id}", svc.UpdateRecyclePolicy)
+	h.Add("DeleteRecyclePolicy", http.MethodDelete, "/recycle_policies/{id}", svc.DeleteRecyclePolicy)
+	h.Add("ListBizRecyclePolicy", http.MethodPost, "/bizs/{bk_biz_id}/recycle_policies/list",
+		svc.ListBizRecyclePolicy)
+	h.Add("CreateBizRecyclePolicy", http.MethodPost, "/bizs/{bk_biz_id}/recycle_policies/create",
+		svc.CreateBizRecyclePolicy)
+	h.Add("UpdateBizRecyclePolicy", http.MethodPatch, "/bizs/{bk_biz_id}/recycle_policies/{id}",
+		svc.UpdateBizRecyclePolicy)
+	h.Add("DeleteBizRecyclePolicy", http.MethodDelete, "/bizs/{bk_biz_id}/recycle_policies/{id}",
+		svc.DeleteBizRecyclePolicy)
+
 	h.Load(c.WebService)
 }
 
@@ -0,0 +1,385 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"math"
+
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// ListSGComplianceBuiltinCheck list security group rule compliance builtin checks.
+func (svc *securityGroupSvc) ListSGComplianceBuiltinCheck(cts *rest.Contexts) (interface{}, error) {
+	if err := svc.authorizeSGComplianceCheck(cts, meta.Find); err != nil {
+		return nil, err
+	}
+
+	config := cc.CloudServer().SGCompliance
+	details := make([]proto.SGComplianceBuiltinCheck, 0, len(enumor.SGComplianceBuiltinChecks))
+	for _, check := range enumor.SGComplianceBuiltinChecks {
+		details = append(details, proto.SGComplianceBuiltinCheck{
+			Name:     check,
+			Severity: sgcompliance.BuiltinCheckSeverity[check],
+			Enabled:  !slice.IsItemInSlice(config.DisabledBuiltinChecks, check),
+		})
+	}
+
+	return &proto.SGComplianceBuiltinCheckListResult{
+		Enforce:         config.Enforce,
+		EnforceSeverity: config.EnforceSeverity,
+		Details:         details,
+	}, nil
+}
+
+// ListSGComplianceCheck list security group rule compliance custom checks.
+func (svc *securityGroupSvc) ListSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeSGComplianceCheck(cts, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.ListSGComplianceCheck(cts.Kit, req)
+}
+
+// CreateSGComplianceCheck create security group rule compliance custom check.
+func (svc *securityGroupSvc) CreateSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.SGComplianceCheckCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeSGComplianceCheck(cts, meta.Update); err != nil {
+		return nil, err
+	}
+
+	createReq := &dataproto.SGComplianceCheckCreateReq{
+		Name:       req.Name,
+		BkBizID:    req.BkBizID,
+		Severity:   req.Severity,
+		Enabled:    req.Enabled,
+		Conditions: req.Conditions,
+		Memo:       req.Memo,
+	}
+	result, err := svc.client.DataService().Global.CreateSGComplianceCheck(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create sg compliance check failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateSGComplianceCheck update security group rule compliance custom check.
+func (svc *securityGroupSvc) UpdateSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.SGComplianceCheckUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeSGComplianceCheck(cts, meta.Update); err != nil {
+		return nil, err
+	}
+
+	updateReq := &dataproto.SGComplianceCheckUpdateReq{
+		ID:         id,
+		Name:       req.Name,
+		Severity:   req.Severity,
+		Enabled:    req.Enabled,
+		Conditions: req.Conditions,
+		Memo:       req.Memo,
+	}
+	if err := svc.client.DataService().Global.UpdateSGComplianceCheck(cts.Kit, updateReq); err != nil {
+		logs.Errorf("update sg compliance check failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteSGComplianceCheck delete security group rule compliance custom check.
+func (svc *securityGroupSvc) DeleteSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.authorizeSGComplianceCheck(cts, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := svc.client.DataService().Global.BatchDeleteSGComplianceCheck(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete sg compliance check failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// authorizeSGComplianceCheck 合规检查项为平台级配置，使用安全组规则的权限进行鉴权
+func (svc *securityGroupSvc) authorizeSGComplianceCheck(cts *rest.Contexts, action meta.Action) error {
+	return svc.authorizer.AuthorizeWithPerm(cts.Kit, meta.ResourceAttribute{
+		Basic: &meta.Basic{Type: meta.SecurityGroupRule, Action: action},
+	})
+}
+
+// ListBizSGComplianceFinding list security group rule compliance findings of biz.
+func (svc *securityGroupSvc) ListBizSGComplianceFinding(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SGComplianceFindingListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	checkAll := len(req.SecurityGroupIDs) == 0 && len(req.CloudVpcIDs) == 0
+
+	sgs := make([]corecloud.BaseSecurityGroup, 0)
+	if checkAll || len(req.SecurityGroupIDs) != 0 {
+		sgFilter := tools.AllExpression()
+		if len(req.SecurityGroupIDs) != 0 {
+			sgFilter = tools.ContainersExpression("id", req.SecurityGroupIDs)
+		}
+		if sgs, err = svc.listBizSGForCompliance(cts, sgFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	firewallRules := make([]corecloud.GcpFirewallRule, 0)
+	if checkAll || len(req.CloudVpcIDs) != 0 {
+		ruleFilter := tools.AllExpression()
+		if len(req.CloudVpcIDs) != 0 {
+			ruleFilter = tools.ContainersExpression("cloud_vpc_id", req.CloudVpcIDs)
+		}
+		if firewallRules, err = svc.listBizFirewallRuleForCompliance(cts, ruleFilter); err != nil {
+			return nil, err
+		}
+	}
+
+	findings, err := svc.sgCompliance.ListFindings(cts.Kit, bizID, sgs, firewallRules)
+	if err != nil {
+		logs.Errorf("list sg compliance findings failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(req.Severities) != 0 {
+		findings = slice.Filter(findings, func(one coresgcompliance.Finding) bool {
+			return slice.IsItemInSlice(req.Severities, one.Severity)
+		})
+	}
+
+	return &proto.SGComplianceFindingListResult{Count: uint64(len(findings)), Details: findings}, nil
+}
+
+func (svc *securityGroupSvc) listBizSGForCompliance(cts *rest.Contexts, sgFilter *filter.Expression) (
+	[]corecloud.BaseSecurityGroup, error) {
+
+	expr, noPerm, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.SecurityGroup, Action: meta.Find, Filter: sgFilter})
+	if err != nil {
+		return nil, err
+	}
+	if noPerm {
+		return make([]corecloud.BaseSecurityGroup, 0), nil
+	}
+
+	sgs := make([]corecloud.BaseSecurityGroup, 0)
+	listReq := &dataproto.SecurityGroupListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	for {
+		result, err := svc.client.DataService().Global.SecurityGroup.ListSecurityGroup(cts.Kit.Ctx,
+			cts.Kit.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list security group failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		sgs = append(sgs, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return sgs, nil
+}
+
+func (svc *securityGroupSvc) listBizFirewallRuleForCompliance(cts *rest.Contexts, ruleFilter *filter.Expression) (
+	[]corecloud.GcpFirewallRule, error) {
+
+	expr, noPerm, err := handler.ListBizAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.GcpFirewallRule, Action: meta.Find, Filter: ruleFilter})
+	if err != nil {
+		return nil, err
+	}
+	if noPerm {
+		return make([]corecloud.GcpFirewallRule, 0), nil
+	}
+
+	rules := make([]corecloud.GcpFirewallRule, 0)
+	listReq := &dataproto.GcpFirewallRuleListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	for {
+		result, err := svc.client.DataService().Gcp.Firewall.ListFirewallRule(cts.Kit.Ctx, cts.Kit.Header(),
+			listReq)
+		if err != nil {
+			logs.Errorf("list gcp firewall rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		rules = append(rules, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return rules, nil
+}
+
+// convTCloudComplianceRules convert tcloud security group rules to be created to compliance rules, new rules are
+// checked as appended after existing rules.
+func convTCloudComplianceRules(ruleType enumor.SecurityGroupRuleType, rules []proto.TCloudSecurityGroupRule) (
+	[]sgcompliance.Rule, error) {
+
+	result := make([]sgcompliance.Rule, 0, len(rules))
+	for idx, one := range rules {
+		rule, err := sgcompliance.FromTCloudRule(corecloud.TCloudSecurityGroupRule{
+			CloudPolicyIndex:           math.MaxInt32 + int64(idx),
+			Protocol:                   one.Protocol,
+			Port:                       one.Port,
+			CloudServiceID:             one.CloudServiceID,
+			CloudServiceGroupID:        one.CloudServiceGroupID,
+			IPv4Cidr:                   one.IPv4Cidr,
+			IPv6Cidr:                   one.IPv6Cidr,
+			CloudAddressID:             one.CloudAddressID,
+			CloudAddressGroupID:        one.CloudAddressGroupID,
+			CloudTargetSecurityGroupID: one.CloudTargetSecurityGroupID,
+			Action:                     one.Action,
+			Type:                       ruleType,
+		})
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// convAwsComplianceRules convert aws security group rules to be created to compliance rules.
+func convAwsComplianceRules(ruleType enumor.SecurityGroupRuleType, rules []proto.AwsSecurityGroupRule) (
+	[]sgcompliance.Rule, error) {
+
+	result := make([]sgcompliance.Rule, 0, len(rules))
+	for _, one := range rules {
+		rule, err := sgcompliance.FromAwsRule(corecloud.AwsSecurityGroupRule{
+			IPv4Cidr:                   one.IPv4Cidr,
+			IPv6Cidr:                   one.IPv6Cidr,
+			FromPort:                   one.FromPort,
+			ToPort:                     one.ToPort,
+			Type:                       ruleType,
+			Protocol:                   one.Protocol,
+			CloudTargetSecurityGroupID: one.CloudTargetSecurityGroupID,
+		})
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// convHuaWeiComplianceRules convert huawei security group rules to be created to compliance rules.
+func convHuaWeiComplianceRules(ruleType enumor.SecurityGroupRuleType, rules []proto.HuaWeiSecurityGroupRule) (
+	[]sgcompliance.Rule, error) {
+
+	result := make([]sgcompliance.Rule, 0, len(rules))
+	for _, one := range rules {
+		rule, err := sgcompliance.FromHuaWeiRule(corecloud.HuaWeiSecurityGroupRule{
+			Protocol:           converter.PtrToVal(one.Protocol),
+			Ethertype:          converter.PtrToVal(one.Ethertype),
+			CloudRemoteGroupID: converter.PtrToVal(one.CloudRemoteGroupID),
+			RemoteIPPrefix:     converter.PtrToVal(one.RemoteIPPrefix),
+			Port:               converter.PtrToVal(one.Port),
+			Priority:           one.Priority,
+			Action:             converter.PtrToVal(one.Action),
+			Type:               ruleType,
+		})
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// convAzureComplianceRule convert azure security group rule to be created or updated to compliance rule.
+func convAzureComplianceRule(rule corecloud.AzureSecurityGroupRule) (sgcompliance.Rule, error) {
+	result, err := sgcompliance.FromAzureRule(rule)
+	if err != nil {
+		return sgcompliance.Rule{}, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return result, nil
+}
@@ -21,8 +21,10 @@ package securitygroup
 
 import (
 	"hcm/cmd/cloud-server/logics/async"
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
 	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	proto "hcm/pkg/api/cloud-server"
+	corecloud "hcm/pkg/api/core/cloud"
 	hcproto "hcm/pkg/api/hc-service"
 	ts "hcm/pkg/api/task-server"
 	"hcm/pkg/async/action"
@@ -105,6 +107,10 @@ func (svc *securityGroupSvc) createTCloudSGRule(cts *rest.Contexts, sgBaseInfo *
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.checkTCloudSGRuleCompliance(cts, sgBaseInfo, req); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.TCloudSGRuleCreateReq{
 		AccountID: sgBaseInfo.AccountID,
 	}
@@ -168,6 +174,10 @@ func (svc *securityGroupSvc) createAwsSGRule(cts *rest.Contexts, sgBaseInfo *typ
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.checkAwsSGRuleCompliance(cts, sgBaseInfo, req); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.AwsSGRuleCreateReq{
 		AccountID: sgBaseInfo.AccountID,
 	}
@@ -223,6 +233,10 @@ func (svc *securityGroupSvc) createHuaWeiSGRule(cts *rest.Contexts, sgBaseInfo *
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.checkHuaWeiSGRuleCompliance(cts, sgBaseInfo, req); err != nil {
+		return nil, err
+	}
+
 	getTaskID := counter.NewNumStringCounter(1, 10)
 	tasks := slice.Map(req.EgressRuleSet, func(r proto.HuaWeiSecurityGroupRule) ts.CustomFlowTask {
 		return ts.CustomFlowTask{
@@ -369,6 +383,10 @@ func (svc *securityGroupSvc) createAzureSGRule(cts *rest.Contexts, sgBaseInfo *t
 		}
 	}
 
+	if err := svc.checkAzureSGRuleCompliance(cts, sgBaseInfo, createReq); err != nil {
+		return nil, err
+	}
+
 	result, err := svc.client.HCService().Azure.SecurityGroup.BatchCreateSecurityGroupRule(cts.Kit.Ctx,
 		cts.Kit.Header(), sgBaseInfo.ID, createReq)
 	if err != nil {
@@ -386,3 +404,81 @@ func (svc *securityGroupSvc) checkCreateAzureSGRuleParams(req hcproto.AzureSGRul
 
 	return nil
 }
+
+// checkTCloudSGRuleCompliance check the tcloud security group rules to be created with compliance checks.
+func (svc *securityGroupSvc) checkTCloudSGRuleCompliance(cts *rest.Contexts, sgBaseInfo *types.CloudResourceBasicInfo,
+	req *proto.SecurityGroupRuleCreateReq[proto.TCloudSecurityGroupRule]) error {
+
+	egressRules, err := convTCloudComplianceRules(enumor.Egress, req.EgressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	ingressRules, err := convTCloudComplianceRules(enumor.Ingress, req.IngressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, append(egressRules, ingressRules...))
+}
+
+// checkAwsSGRuleCompliance check the aws security group rules to be created with compliance checks.
+func (svc *securityGroupSvc) checkAwsSGRuleCompliance(cts *rest.Contexts, sgBaseInfo *types.CloudResourceBasicInfo,
+	req *proto.SecurityGroupRuleCreateReq[proto.AwsSecurityGroupRule]) error {
+
+	egressRules, err := convAwsComplianceRules(enumor.Egress, req.EgressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	ingressRules, err := convAwsComplianceRules(enumor.Ingress, req.IngressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, append(egressRules, ingressRules...))
+}
+
+// checkHuaWeiSGRuleCompliance check the huawei security group rules to be created with compliance checks.
+func (svc *securityGroupSvc) checkHuaWeiSGRuleCompliance(cts *rest.Contexts, sgBaseInfo *types.CloudResourceBasicInfo,
+	req *proto.SecurityGroupRuleCreateReq[proto.HuaWeiSecurityGroupRule]) error {
+
+	egressRules, err := convHuaWeiComplianceRules(enumor.Egress, req.EgressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	ingressRules, err := convHuaWeiComplianceRules(enumor.Ingress, req.IngressRuleSet)
+	if err != nil {
+		return err
+	}
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, append(egressRules, ingressRules...))
+}
+
+// checkAzureSGRuleCompliance check the azure security group rules to be created with compliance checks.
+func (svc *securityGroupSvc) checkAzureSGRuleCompliance(cts *rest.Contexts, sgBaseInfo *types.CloudResourceBasicInfo,
+	req *hcproto.AzureSGRuleCreateReq) error {
+
+	rules := make([]sgcompliance.Rule, 0, len(req.EgressRuleSet)+len(req.IngressRuleSet))
+	for _, one := range append(req.EgressRuleSet, req.IngressRuleSet...) {
+		rule, err := convAzureComplianceRule(corecloud.AzureSecurityGroupRule{
+			DestinationAddressPrefix:   one.DestinationAddressPrefix,
+			DestinationAddressPrefixes: one.DestinationAddressPrefixes,
+			DestinationPortRange:       one.DestinationPortRange,
+			DestinationPortRanges:      one.DestinationPortRanges,
+			Protocol:                   one.Protocol,
+			SourceAddressPrefix:        one.SourceAddressPrefix,
+			SourceAddressPrefixes:      one.SourceAddressPrefixes,
+			Priority:                   one.Priority,
+			Type:                       one.Type,
+			Access:                     one.Access,
+		})
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, rules)
+}
@@ -24,6 +24,7 @@ import (
 
 	"hcm/cmd/cloud-server/logics/audit"
 	securitygroup "hcm/cmd/cloud-server/logics/security-group"
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -34,11 +35,12 @@ import (
 // InitSecurityGroupService initial the security group service
 func InitSecurityGroupService(c *capability.Capability) {
 	svc := &securityGroupSvc{
-		client:     c.ApiClient,
-		authorizer: c.Authorizer,
-		audit:      c.Audit,
-		sgLogic:    c.Logics.SecurityGroup,
-		cmdbClient: c.CmdbCli,
+		client:       c.ApiClient,
+		authorizer:   c.Authorizer,
+		audit:        c.Audit,
+		sgLogic:      c.Logics.SecurityGroup,
+		sgCompliance: c.Logics.SGCompliance,
+		cmdbClient:   c.CmdbCli,
 	}
 
 	h := rest.NewHandler()
@@ -52,6 +54,8 @@ func InitSecurityGroupService(c *capability.Capability) {
 	h.Add("BatchUpdateSGMgmtAttr", http.MethodPatch, "/security_groups/mgmt_attrs/batch",
 		svc.BatchUpdateSGMgmtAttr)
 	h.Add("BatchDeleteSecurityGroup", http.MethodDelete, "/security_groups/batch", svc.BatchDeleteSecurityGroup)
+	h.Add("RecycleSecurityGroup", http.MethodPost, "/security_groups/recycle", svc.RecycleSecurityGroup)
+	h.Add("RecoverSecurityGroup", http.MethodPost, "/security_groups/recover", svc.RecoverSecurityGroup)
 	h.Add("ListSecurityGroup", http.MethodPost, "/security_groups/list", svc.ListSecurityGroup)
 
 	h.Add("AssociateCvm", http.MethodPost, "/security_groups/associate/cvms", svc.AssociateCvm)
@@ -109,6 +113,18 @@ func InitSecurityGroupService(c *capability.Capability) {
 		"/security_groups/{sg_id}/related_resources/load_balancers/list",
 		svc.ListSGRelLB)
 
+	// 安全组规则合规检查项
+	h.Add("ListSGComplianceBuiltinCheck", http.MethodGet, "/security_groups/compliance_checks/builtin",
+		svc.ListSGComplianceBuiltinCheck)
+	h.Add("ListSGComplianceCheck", http.MethodPost, "/security_groups/compliance_checks/list",
+		svc.ListSGComplianceCheck)
+	h.Add("CreateSGComplianceCheck", http.MethodPost, "/security_groups/compliance_checks/create",
+		svc.CreateSGComplianceCheck)
+	h.Add("UpdateSGComplianceCheck", http.MethodPatch, "/security_groups/compliance_checks/{id}",
+		svc.UpdateSGComplianceCheck)
+	h.Add("DeleteSGComplianceCheck", http.MethodDelete, "/security_groups/compliance_checks/{id}",
+		svc.DeleteSGComplianceCheck)
+
 	bizService(h, svc)
 	initSecurityGroupServiceHooks(svc, h)
 
@@ -126,6 +142,10 @@ func bizService(h *rest.Handler, svc *securityGroupSvc) {
 		svc.UpdateBizSGMgmtAttr)
 	h.Add("BatchDeleteBizSecurityGroup", http.MethodDelete, "/bizs/{bk_biz_id}/security_groups/batch",
 		svc.BatchDeleteBizSecurityGroup)
+	h.Add("RecycleBizSecurityGroup", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/recycle",
+		svc.RecycleBizSecurityGroup)
+	h.Add("RecoverBizSecurityGroup", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/recover",
+		svc.RecoverBizSecurityGroup)
 	h.Add("ListBizSecurityGroup", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/list", svc.ListBizSecurityGroup)
 
 	h.Add("AssociateBizCvm", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/associate/cvms", svc.AssociateBizCvm)
@@ -187,12 +207,16 @@ func bizService(h *rest.Handler, svc *securityGroupSvc) {
 
 	h.Add("BizBatchListResSecurityGroups", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/res/{res_type}/batch",
 		svc.BizBatchListResSecurityGroups)
+
+	h.Add("ListBizSGComplianceFinding", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/compliance_findings/list",
+		svc.ListBizSGComplianceFinding)
 }
 
 type securityGroupSvc struct {
-	client     *client.ClientSet
-	authorizer auth.Authorizer
-	audit      audit.Interface
-	cmdbClient cmdb.Client
-	sgLogic    securitygroup.Interface
+	client       *client.ClientSet
+	authorizer   auth.Authorizer
+	audit        audit.Interface
+	cmdbClient   cmdb.Client
+	sgLogic      securitygroup.Interface
+	sgCompliance sgcompliance.Interface
 }
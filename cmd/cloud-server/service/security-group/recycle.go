@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// RecycleSecurityGroup recycle security group.
+func (svc *securityGroupSvc) RecycleSecurityGroup(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecycleBizSecurityGroup recycle biz security group.
+func (svc *securityGroupSvc) RecycleBizSecurityGroup(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecycleRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+// RecoverSecurityGroup recover security group.
+func (svc *securityGroupSvc) RecoverSecurityGroup(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.ResOperateAuth))
+}
+
+// RecoverBizSecurityGroup recover biz security group.
+func (svc *securityGroupSvc) RecoverBizSecurityGroup(cts *rest.Contexts) (interface{}, error) {
+	return logicsrecycle.RecoverRes(cts, svc.recycleOption(handler.BizOperateAuth))
+}
+
+func (svc *securityGroupSvc) recycleOption(validHandler handler.ValidWithAuthHandler) *logicsrecycle.ResRecycleOption {
+	return &logicsrecycle.ResRecycleOption{
+		Client:       svc.client,
+		Authorizer:   svc.authorizer,
+		Audit:        svc.audit,
+		ResType:      enumor.SecurityGroupCloudResType,
+		AuditResType: enumor.SecurityGroupAuditResType,
+		AuthResType:  meta.SecurityGroup,
+		ValidHandler: validHandler,
+		PreCheck:     svc.checkSGBinding,
+	}
+}
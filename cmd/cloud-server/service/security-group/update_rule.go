@@ -20,10 +20,15 @@
 package securitygroup
 
 import (
+	sgcompliance "hcm/cmd/cloud-server/logics/sg-compliance"
 	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
 	hcproto "hcm/pkg/api/hc-service"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
@@ -126,6 +131,9 @@ func (svc *securityGroupSvc) updateTCloudSGRule(cts *rest.Contexts, sgBaseInfo *
 		Action:                     req.Action,
 		Memo:                       req.Memo,
 	}
+	if err = svc.checkTCloudSGRuleUpdateCompliance(cts, sgBaseInfo, id, updateReq); err != nil {
+		return nil, err
+	}
 	if err = svc.client.HCService().TCloud.SecurityGroup.UpdateSecurityGroupRule(cts.Kit.Ctx, cts.Kit.Header(),
 		sgBaseInfo.ID, id, updateReq); err != nil {
 		return nil, err
@@ -146,6 +154,22 @@ func (svc *securityGroupSvc) updateAwsSGRule(cts *rest.Contexts, sgBaseInfo *typ
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	rule, err := sgcompliance.FromAwsRule(corecloud.AwsSecurityGroupRule{
+		ID:                         id,
+		IPv4Cidr:                   req.IPv4Cidr,
+		IPv6Cidr:                   req.IPv6Cidr,
+		FromPort:                   req.FromPort,
+		ToPort:                     req.ToPort,
+		Protocol:                   req.Protocol,
+		CloudTargetSecurityGroupID: req.CloudTargetSecurityGroupID,
+	})
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	if err = svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, []sgcompliance.Rule{rule}); err != nil {
+		return nil, err
+	}
+
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
@@ -219,6 +243,30 @@ func (svc *securityGroupSvc) updateAzureSGRule(cts *rest.Contexts, sgBaseInfo *t
 		return nil, err
 	}
 
+	azureRule, err := svc.getAzureSGRule(cts, sgBaseInfo.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := convAzureComplianceRule(corecloud.AzureSecurityGroupRule{
+		ID:                         id,
+		DestinationAddressPrefix:   req.DestinationAddressPrefix,
+		DestinationAddressPrefixes: req.DestinationAddressPrefixes,
+		DestinationPortRange:       req.DestinationPortRange,
+		DestinationPortRanges:      req.DestinationPortRanges,
+		Protocol:                   req.Protocol,
+		SourceAddressPrefix:        req.SourceAddressPrefix,
+		SourceAddressPrefixes:      req.SourceAddressPrefixes,
+		Priority:                   req.Priority,
+		Type:                       azureRule.Type,
+		Access:                     req.Access,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, []sgcompliance.Rule{rule}); err != nil {
+		return nil, err
+	}
+
 	if err := svc.client.HCService().Azure.SecurityGroup.UpdateSecurityGroupRule(cts.Kit.Ctx, cts.Kit.Header(),
 		sgBaseInfo.ID, id, updateReq); err != nil {
 		return nil, err
@@ -292,6 +340,10 @@ func (svc *securityGroupSvc) batchUpdateTCloudSGRule(cts *rest.Contexts, sgBaseI
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.checkTCloudSGRuleBatchUpdateCompliance(cts, sgBaseInfo, req); err != nil {
+		return nil, err
+	}
+
 	updateReq := &hcproto.TCloudSGRuleBatchUpdateReq{
 		AccountID:      sgBaseInfo.AccountID,
 		EgressRuleSet:  req.EgressRuleSet,
@@ -304,3 +356,79 @@ func (svc *securityGroupSvc) batchUpdateTCloudSGRule(cts *rest.Contexts, sgBaseI
 
 	return nil, nil
 }
+
+// getAzureSGRule get azure security group rule by id.
+func (svc *securityGroupSvc) getAzureSGRule(cts *rest.Contexts, sgID, id string) (
+	*corecloud.AzureSecurityGroupRule, error) {
+
+	listReq := &dataproto.AzureSGRuleListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Azure.SecurityGroup.ListSecurityGroupRule(cts.Kit.Ctx, cts.Kit.Header(),
+		listReq, sgID)
+	if err != nil {
+		logs.Errorf("list azure security group rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "security group rule: %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// checkTCloudSGRuleUpdateCompliance check the tcloud security group rule to be updated with compliance checks.
+func (svc *securityGroupSvc) checkTCloudSGRuleUpdateCompliance(cts *rest.Contexts,
+	sgBaseInfo *types.CloudResourceBasicInfo, id string, req *hcproto.TCloudSGRuleUpdateReq) error {
+
+	rule, err := sgcompliance.FromTCloudRule(convTCloudUpdateToRule(req))
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	rule.ID = id
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, []sgcompliance.Rule{rule})
+}
+
+// checkTCloudSGRuleBatchUpdateCompliance check the tcloud security group rules to be updated by policy index with
+// compliance checks.
+func (svc *securityGroupSvc) checkTCloudSGRuleBatchUpdateCompliance(cts *rest.Contexts,
+	sgBaseInfo *types.CloudResourceBasicInfo, req *proto.TCloudSGRuleBatchUpdateReq) error {
+
+	rules := make([]sgcompliance.Rule, 0, len(req.EgressRuleSet)+len(req.IngressRuleSet))
+	for ruleType, ruleSet := range map[enumor.SecurityGroupRuleType][]proto.TCloudSGRuleUpdateReqWithPolicyIndex{
+		enumor.Egress: req.EgressRuleSet, enumor.Ingress: req.IngressRuleSet} {
+
+		for _, one := range ruleSet {
+			tcloudRule := convTCloudUpdateToRule(&one.TCloudSGRuleUpdateReq)
+			tcloudRule.Type = ruleType
+			tcloudRule.CloudPolicyIndex = converter.PtrToVal(one.CloudPolicyIndex)
+
+			rule, err := sgcompliance.FromTCloudRule(tcloudRule)
+			if err != nil {
+				return errf.NewFromErr(errf.InvalidParameter, err)
+			}
+			rule.ReplaceByPriority = true
+			rules = append(rules, rule)
+		}
+	}
+
+	return svc.sgCompliance.CheckSGRules(cts.Kit, sgBaseInfo, rules)
+}
+
+func convTCloudUpdateToRule(req *hcproto.TCloudSGRuleUpdateReq) corecloud.TCloudSecurityGroupRule {
+	return corecloud.TCloudSecurityGroupRule{
+		Protocol:                   req.Protocol,
+		Port:                       req.Port,
+		CloudServiceID:             req.CloudServiceID,
+		CloudServiceGroupID:        req.CloudServiceGroupID,
+		IPv4Cidr:                   req.IPv4Cidr,
+		IPv6Cidr:                   req.IPv6Cidr,
+		CloudAddressID:             req.CloudAddressID,
+		CloudAddressGroupID:        req.CloudAddressGroupID,
+		CloudTargetSecurityGroupID: req.CloudTargetSecurityGroupID,
+		Action:                     req.Action,
+	}
+}
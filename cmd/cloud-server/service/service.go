@@ -49,10 +49,12 @@ import (
 	"hcm/cmd/cloud-server/service/disk"
 	"hcm/cmd/cloud-server/service/eip"
 	"hcm/cmd/cloud-server/service/firewall"
+	idleresource "hcm/cmd/cloud-server/service/idle-resource"
 	"hcm/cmd/cloud-server/service/image"
 	instancetype "hcm/cmd/cloud-server/service/instance-type"
 	loadbalancer "hcm/cmd/cloud-server/service/load-balancer"
 	networkinterface "hcm/cmd/cloud-server/service/network-interface"
+	"hcm/cmd/cloud-server/service/reachability"
 	"hcm/cmd/cloud-server/service/recycle"
 	"hcm/cmd/cloud-server/service/region"
 	resourcegroup "hcm/cmd/cloud-server/service/resource-group"
@@ -139,12 +141,34 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		go bill.CloudBillConfigCreate(interval, sd, apiClientSet)
 	}
 
-	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, svr.cmdbCli)
+	if cc.CloudServer().IdleResource.Enable {
+		go idleresource.AnalyzeTiming(apiClientSet, sd, cc.CloudServer().IdleResource)
+	}
+
+	if cc.CloudServer().CertExpiry.Enable {
+		go cert.ExpiryNoticeTiming(apiClientSet, sd, svr.cmsiCli, cc.CloudServer().BkHcmUrl,
+			cc.CloudServer().CertExpiry)
+	}
+
+	if cc.CloudServer().TargetHealth.Enable {
+		go loadbalancer.TargetHealthCollectTiming(apiClientSet, sd, svr.cmsiCli, cc.CloudServer().BkHcmUrl,
+			cc.CloudServer().TargetHealth)
+	}
+
+	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, svr.cmdbCli, svr.cmsiCli,
+		cc.CloudServer().BkHcmUrl)
 
 	go appcvm.TimingHandleDeliverApplication(svr.client, 2*time.Second)
 
 	go task.TimingHandleTaskMgmtState(apiClientSet, sd, time.Second)
 
+	approvalCfg := cc.CloudServer().Approval
+	if approvalCfg.IsNative() && approvalCfg.RemindIntervalMin > 0 {
+		interval := time.Duration(approvalCfg.RemindIntervalMin) * time.Minute
+		go application.TimingRemindApprovalTicket(apiClientSet, sd, svr.cmsiCli, cc.CloudServer().BkHcmUrl,
+			interval)
+	}
+
 	return svr, nil
 }
 
@@ -221,9 +245,7 @@ func getCloudClientSvr(sd serviced.ServiceDiscover) (*client.ClientSet, *Service
 
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.NewFromConfig(cryptoConfig)
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -309,6 +331,8 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	subnet.InitSubnetService(c)
 	image.InitImageService(c)
 	routetable.InitRouteTableService(c)
+	reachability.InitService(c)
+	idleresource.InitService(c)
 	cvm.InitCvmService(c)
 	resourcegroup.InitResourceGroupService(c)
 	zone.InitZoneService(c)
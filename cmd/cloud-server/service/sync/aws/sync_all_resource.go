@@ -119,6 +119,7 @@ var syncOrder = []enumor.CloudResourceType{
 	enumor.RouteTableCloudResType,
 	enumor.SecurityGroupUsageBizRelResType,
 	enumor.CvmCCInfoResType,
+	enumor.LoadBalancerCloudResType,
 }
 var syncFuncMap = map[enumor.CloudResourceType]ResSyncFunc{
 	enumor.DiskCloudResType:                SyncDisk,
@@ -130,4 +131,5 @@ var syncFuncMap = map[enumor.CloudResourceType]ResSyncFunc{
 	enumor.RouteTableCloudResType:          SyncRouteTable,
 	enumor.SecurityGroupUsageBizRelResType: SyncSGUsageBizRel,
 	enumor.CvmCCInfoResType:                SyncCvmCCHostInfo,
+	enumor.LoadBalancerCloudResType:        SyncLoadBalancer,
 }
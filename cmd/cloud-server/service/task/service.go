@@ -45,6 +45,14 @@ func InitService(c *capability.Capability) {
 		svc.CancelBizTaskManagement)
 	h.Add("ListBizTaskManagementState", http.MethodPost, "/bizs/{bk_biz_id}/task_managements/state/list",
 		svc.ListBizTaskManagementState)
+	h.Add("PauseBizTaskManagement", http.MethodPost, "/bizs/{bk_biz_id}/task_managements/{id}/pause",
+		svc.PauseBizTaskManagement)
+	h.Add("ResumeBizTaskManagement", http.MethodPost, "/bizs/{bk_biz_id}/task_managements/{id}/resume",
+		svc.ResumeBizTaskManagement)
+	h.Add("PromoteBizTaskManagement", http.MethodPost, "/bizs/{bk_biz_id}/task_managements/{id}/promote",
+		svc.PromoteBizTaskManagement)
+	h.Add("AbortBizTaskManagement", http.MethodPost, "/bizs/{bk_biz_id}/task_managements/{id}/abort",
+		svc.AbortBizTaskManagement)
 
 	h.Add("ListBizTaskDetail", http.MethodPost, "/bizs/{bk_biz_id}/task_details/list", svc.ListBizTaskDetail)
 	h.Add("ListBizTaskDetailByCond", http.MethodPost, "/bizs/{bk_biz_id}/task_details/list_by_cond",
@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package task
+
+import (
+	lblogic "hcm/cmd/cloud-server/logics/load-balancer"
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// PauseBizTaskManagement pause biz traffic shift task management.
+func (svc *service) PauseBizTaskManagement(cts *rest.Contexts) (interface{}, error) {
+	return svc.controlTrafficShift(cts, handler.BizOperateAuth, enumor.TrafficShiftControlPause)
+}
+
+// ResumeBizTaskManagement resume biz traffic shift task management.
+func (svc *service) ResumeBizTaskManagement(cts *rest.Contexts) (interface{}, error) {
+	return svc.controlTrafficShift(cts, handler.BizOperateAuth, enumor.TrafficShiftControlResume)
+}
+
+// PromoteBizTaskManagement promote biz traffic shift task management.
+func (svc *service) PromoteBizTaskManagement(cts *rest.Contexts) (interface{}, error) {
+	return svc.controlTrafficShift(cts, handler.BizOperateAuth, enumor.TrafficShiftControlPromote)
+}
+
+// AbortBizTaskManagement abort biz traffic shift task management.
+func (svc *service) AbortBizTaskManagement(cts *rest.Contexts) (interface{}, error) {
+	return svc.controlTrafficShift(cts, handler.BizOperateAuth, enumor.TrafficShiftControlAbort)
+}
+
+func (svc *service) controlTrafficShift(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	control enumor.TrafficShiftControl) (interface{}, error) {
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	list, err := svc.client.DataService().Global.TaskManagement.List(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list task management failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(list.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "task management: %s not found", id)
+	}
+	management := list.Details[0]
+
+	// validate biz and authorize
+	basicInfos := map[string]types.CloudResourceBasicInfo{
+		management.ID: {ID: management.ID, BkBizID: management.BkBizID},
+	}
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.TaskManagement,
+		Action: meta.Update, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	err = lblogic.ControlTrafficShift(cts.Kit, svc.client.DataService(), svc.client.TaskServer(), &management,
+		control)
+	if err != nil {
+		logs.Errorf("control traffic shift failed, err: %v, id: %s, control: %s, rid: %s", err, id, control,
+			cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
@@ -91,10 +91,16 @@ func (ds *dataService) prepare(opt *options.Option) error {
 	ds.sd = sd
 
 	// init hcm control tool
-	if err := ctl.LoadCtl(ctl.WithBasics(sd)...); err != nil {
+	cmds := append(ctl.WithBasics(sd), svc.ReEncryptSecretCmd(), svc.VerifyAuditChainCmd())
+	if err := ctl.LoadCtl(cmds...); err != nil {
 		return fmt.Errorf("load control tool failed, err: %v", err)
 	}
 
+	// export audits to the external sink if enabled
+	if err := svc.StartAuditExport(sd); err != nil {
+		return fmt.Errorf("start audit export failed, err: %v", err)
+	}
+
 	return nil
 }
 
@@ -61,6 +61,8 @@ func (svc *service) CreateMainAccount(cts *rest.Contexts) (interface{}, error) {
 		result, err = createAccount[dataproto.ZenlayerMainAccountExtensionCreateReq](vendor, svc, cts)
 	case enumor.Kaopu:
 		result, err = createAccount[dataproto.KaopuMainAccountExtensionCreateReq](vendor, svc, cts)
+	case enumor.TCloud:
+		result, err = createAccount[dataproto.TCloudMainAccountExtensionCreateReq](vendor, svc, cts)
 	default:
 		return nil, fmt.Errorf("unsupport %s vendor for now", vendor)
 	}
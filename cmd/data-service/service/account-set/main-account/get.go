@@ -134,6 +134,9 @@ func (svc *service) GetMainAccount(cts *rest.Contexts) (interface{}, error) {
 			baseAccount, dbAccount.Extension, svc)
 	case enumor.Kaopu:
 		account, err = convertToMainAccountResult[protocore.KaopuMainAccountExtension](baseAccount, dbAccount.Extension, svc)
+	case enumor.TCloud:
+		account, err = convertToMainAccountResult[protocore.TCloudMainAccountExtension](
+			baseAccount, dbAccount.Extension, svc)
 	}
 
 	if err != nil {
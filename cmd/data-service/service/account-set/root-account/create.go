@@ -61,6 +61,8 @@ func (svc *service) CreateRootAccount(cts *rest.Contexts) (interface{}, error) {
 		result, err = createAccount[dataproto.ZenlayerRootAccountExtensionCreateReq](vendor, svc, cts)
 	case enumor.Kaopu:
 		result, err = createAccount[dataproto.KaopuRootAccountExtensionCreateReq](vendor, svc, cts)
+	case enumor.TCloud:
+		result, err = createAccount[dataproto.TCloudRootAccountExtensionCreateReq](vendor, svc, cts)
 	default:
 		return nil, fmt.Errorf("unsupport %s vendor for now", vendor)
 	}
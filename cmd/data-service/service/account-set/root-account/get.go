@@ -127,6 +127,9 @@ func (svc *service) GetRootAccount(cts *rest.Contexts) (interface{}, error) {
 	case enumor.Kaopu:
 		account, err = convertToRootAccountResult[protocore.KaopuRootAccountExtension](
 			baseAccount, dbAccount.Extension, svc)
+	case enumor.TCloud:
+		account, err = convertToRootAccountResult[protocore.TCloudRootAccountExtension](
+			baseAccount, dbAccount.Extension, svc)
 	}
 
 	if err != nil {
@@ -56,6 +56,8 @@ func (svc *service) UpdateRootAccount(cts *rest.Contexts) (interface{}, error) {
 		return updateRootAccount[dataproto.ZenlayerRootAccountExtensionUpdateReq](accountID, svc, cts)
 	case enumor.Kaopu:
 		return updateRootAccount[dataproto.KaopuRootAccountExtensionUpdateReq](accountID, svc, cts)
+	case enumor.TCloud:
+		return updateRootAccount[dataproto.TCloudRootAccountExtensionUpdateReq](accountID, svc, cts)
 	}
 	return nil, nil
 }
@@ -83,6 +83,8 @@ func (svc *applicationSvc) CreateApplication(cts *rest.Contexts) (interface{}, e
 		Memo:           req.Memo,
 		Creator:        cts.Kit.User,
 		Reviser:        cts.Kit.User,
+
+		AutoApprovalPolicyID: req.AutoApprovalPolicyID,
 	}
 
 	applicationID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
@@ -148,6 +150,8 @@ func (svc *applicationSvc) convertToApplicationResp(
 		Content:        string(application.Content),
 		DeliveryDetail: string(application.DeliveryDetail),
 		Memo:           application.Memo,
+
+		AutoApprovalPolicyID: application.AutoApprovalPolicyID,
 		Revision: core.Revision{
 			Creator:   application.Creator,
 			Reviser:   application.Reviser,
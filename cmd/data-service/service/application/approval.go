@@ -0,0 +1,322 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coreapproval "hcm/pkg/api/core/approval"
+	proto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableapplication "hcm/pkg/dal/table/application"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitApprovalService 内置审批引擎的审批链、审批单及自动审批策略接口
+func InitApprovalService(cap *capability.Capability) {
+	svc := &approvalSvc{
+		dao: cap.Dao,
+	}
+	h := rest.NewHandler()
+
+	h.Add("CreateApprovalChain", "POST", "/approval_chains/create", svc.CreateApprovalChain)
+	h.Add("UpdateApprovalChain", "PATCH", "/approval_chains", svc.UpdateApprovalChain)
+	h.Add("ListApprovalChain", "POST", "/approval_chains/list", svc.ListApprovalChain)
+	h.Add("BatchDeleteApprovalChain", "DELETE", "/approval_chains/batch", svc.BatchDeleteApprovalChain)
+
+	h.Add("CreateApprovalTicket", "POST", "/approval_tickets/create", svc.CreateApprovalTicket)
+	h.Add("UpdateApprovalTicket", "PATCH", "/approval_tickets", svc.UpdateApprovalTicket)
+	h.Add("ListApprovalTicket", "POST", "/approval_tickets/list", svc.ListApprovalTicket)
+
+	h.Add("CreateAutoApprovalPolicy", "POST", "/auto_approval_policies/create", svc.CreateAutoApprovalPolicy)
+	h.Add("UpdateAutoApprovalPolicy", "PATCH", "/auto_approval_policies", svc.UpdateAutoApprovalPolicy)
+	h.Add("ListAutoApprovalPolicy", "POST", "/auto_approval_policies/list", svc.ListAutoApprovalPolicy)
+	h.Add("BatchDeleteAutoApprovalPolicy", "DELETE", "/auto_approval_policies/batch",
+		svc.BatchDeleteAutoApprovalPolicy)
+
+	h.Load(cap.WebService)
+}
+
+type approvalSvc struct {
+	dao dao.Set
+}
+
+// CreateApprovalChain ...
+func (svc *approvalSvc) CreateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalChainTable{
+		ApplicationType: req.ApplicationType,
+		BkBizID:         req.BkBizID,
+		Nodes:           req.Nodes,
+		Memo:            req.Memo,
+		Creator:         cts.Kit.User,
+		Reviser:         cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ApprovalChain().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	chainID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create approval chain but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: chainID}, nil
+}
+
+// UpdateApprovalChain ...
+func (svc *approvalSvc) UpdateApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalChainUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalChainTable{
+		Nodes:   req.Nodes,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+	if err := svc.dao.ApprovalChain().Update(cts.Kit, tools.EqualExpression("id", req.ID), model); err != nil {
+		logs.Errorf("update approval chain failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListApprovalChain ...
+func (svc *approvalSvc) ListApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.ApprovalChain().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &proto.ApprovalChainListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreapproval.Chain, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coreapproval.Chain{
+			ID:              one.ID,
+			ApplicationType: one.ApplicationType,
+			BkBizID:         one.BkBizID,
+			Nodes:           one.Nodes,
+			Memo:            one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &proto.ApprovalChainListResult{Details: details}, nil
+}
+
+// BatchDeleteApprovalChain ...
+func (svc *approvalSvc) BatchDeleteApprovalChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ApprovalChain().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete approval chain failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CreateApprovalTicket ...
+func (svc *approvalSvc) CreateApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTicketCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalTicketTable{
+		ApplicationID:   req.ApplicationID,
+		ApplicationType: req.ApplicationType,
+		Title:           req.Title,
+		Applicant:       req.Applicant,
+		ChainID:         req.ChainID,
+		Nodes:           req.Nodes,
+		CurrentNode:     0,
+		Approvers:       req.Approvers,
+		Status:          enumor.ApprovalTicketPending,
+		Records:         make(tableapplication.ApprovalRecords, 0),
+		Version:         0,
+		RemindedAt:      req.RemindedAt,
+		Creator:         cts.Kit.User,
+		Reviser:         cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.ApprovalTicket().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create approval ticket failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ticketID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create approval ticket but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: ticketID}, nil
+}
+
+// UpdateApprovalTicket ...
+func (svc *approvalSvc) UpdateApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ApprovalTicketUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.ApprovalTicketTable{
+		CurrentNode: converter.PtrToVal(req.CurrentNode),
+		Approvers:   req.Approvers,
+		Status:      req.Status,
+		Records:     req.Records,
+		RemindedAt:  req.RemindedAt,
+		Reviser:     cts.Kit.User,
+	}
+
+	rules := []filter.RuleFactory{tools.RuleEqual("id", req.ID)}
+	if req.Version != nil {
+		rules = append(rules, tools.RuleEqual("version", *req.Version))
+		model.Version = *req.Version + 1
+	}
+	expr := &filter.Expression{Op: filter.And, Rules: rules}
+
+	if err := svc.dao.ApprovalTicket().Update(cts.Kit, expr, model); err != nil {
+		logs.Errorf("update approval ticket failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListApprovalTicket ...
+func (svc *approvalSvc) ListApprovalTicket(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.ApprovalTicket().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list approval ticket failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &proto.ApprovalTicketListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreapproval.Ticket, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coreapproval.Ticket{
+			ID:              one.ID,
+			ApplicationID:   one.ApplicationID,
+			ApplicationType: one.ApplicationType,
+			Title:           one.Title,
+			Applicant:       one.Applicant,
+			ChainID:         one.ChainID,
+			Nodes:           one.Nodes,
+			CurrentNode:     one.CurrentNode,
+			Approvers:       one.Approvers,
+			Status:          one.Status,
+			Records:         one.Records,
+			Version:         one.Version,
+			RemindedAt:      one.RemindedAt,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &proto.ApprovalTicketListResult{Details: details}, nil
+}
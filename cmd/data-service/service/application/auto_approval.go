@@ -0,0 +1,189 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coreapproval "hcm/pkg/api/core/approval"
+	proto "hcm/pkg/api/data-service"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableapplication "hcm/pkg/dal/table/application"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateAutoApprovalPolicy ...
+func (svc *approvalSvc) CreateAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.AutoApprovalPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	conditions, err := tabletype.NewJsonField(req.Conditions)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.AutoApprovalPolicyTable{
+		Name:            req.Name,
+		ApplicationType: req.ApplicationType,
+		Priority:        converter.ValToPtr(req.Priority),
+		Enabled:         converter.ValToPtr(req.Enabled),
+		Conditions:      conditions,
+		Memo:            req.Memo,
+		Creator:         cts.Kit.User,
+		Reviser:         cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AutoApprovalPolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create auto approval policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	policyID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create auto approval policy but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: policyID}, nil
+}
+
+// UpdateAutoApprovalPolicy ...
+func (svc *approvalSvc) UpdateAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.AutoApprovalPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableapplication.AutoApprovalPolicyTable{
+		Name:     req.Name,
+		Priority: req.Priority,
+		Enabled:  req.Enabled,
+		Memo:     req.Memo,
+		Reviser:  cts.Kit.User,
+	}
+	if req.Conditions != nil {
+		conditions, err := tabletype.NewJsonField(req.Conditions)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.Conditions = conditions
+	}
+
+	if err := svc.dao.AutoApprovalPolicy().Update(cts.Kit, tools.EqualExpression("id", req.ID), model); err != nil {
+		logs.Errorf("update auto approval policy failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListAutoApprovalPolicy ...
+func (svc *approvalSvc) ListAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.AutoApprovalPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list auto approval policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &proto.AutoApprovalPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreapproval.AutoApprovalPolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		policy := coreapproval.AutoApprovalPolicy{
+			ID:              one.ID,
+			Name:            one.Name,
+			ApplicationType: one.ApplicationType,
+			Priority:        converter.PtrToVal(one.Priority),
+			Enabled:         converter.PtrToVal(one.Enabled),
+			Memo:            one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+		if !one.Conditions.IsEmpty() {
+			if err = json.UnmarshalFromString(string(one.Conditions), &policy.Conditions); err != nil {
+				logs.Errorf("unmarshal auto approval policy conditions failed, err: %v, id: %s, rid: %s", err,
+					one.ID, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+		details = append(details, policy)
+	}
+
+	return &proto.AutoApprovalPolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteAutoApprovalPolicy ...
+func (svc *approvalSvc) BatchDeleteAutoApprovalPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.AutoApprovalPolicy().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete auto approval policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
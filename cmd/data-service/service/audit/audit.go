@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"hcm/cmd/data-service/service/audit/chain"
 	"hcm/cmd/data-service/service/audit/cloud"
 	"hcm/cmd/data-service/service/capability"
 	"hcm/pkg/api/core"
@@ -31,6 +32,7 @@ import (
 	proto "hcm/pkg/api/data-service/audit"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao"
+	daoaudit "hcm/pkg/dal/dao/audit"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/logs"
@@ -58,6 +60,7 @@ func InitAuditService(cap *capability.Capability) {
 		svc.cloudAudit.CloudResourceRecycleAudit)
 	h.Add("ListAudit", http.MethodPost, "/audits/list", svc.ListAudit)
 	h.Add("GetAudit", http.MethodGet, "/audits/{id}", svc.GetAudit)
+	h.Add("VerifyAuditChain", http.MethodPost, "/audits/chain/verify", svc.VerifyAuditChain)
 
 	h.Load(cap.WebService)
 }
@@ -110,6 +113,9 @@ func (svc *svc) ListAudit(cts *rest.Contexts) (interface{}, error) {
 			Source:     one.Source,
 			Rid:        one.Rid,
 			AppCode:    one.AppCode,
+			ChainSeq:   one.ChainSeq,
+			PrevHash:   one.PrevHash,
+			Hash:       one.Hash,
 			CreatedAt:  one.CreatedAt.String(),
 		})
 	}
@@ -153,8 +159,26 @@ func (svc *svc) GetAudit(cts *rest.Contexts) (interface{}, error) {
 		Rid:        result.Details[0].Rid,
 		AppCode:    result.Details[0].AppCode,
 		Detail:     result.Details[0].Detail,
+		ChainSeq:   result.Details[0].ChainSeq,
+		PrevHash:   result.Details[0].PrevHash,
+		Hash:       result.Details[0].Hash,
 		CreatedAt:  result.Details[0].CreatedAt.String(),
 	}
 
 	return audit, nil
 }
+
+// VerifyAuditChain verify the audit hash chain of current tenant.
+func (svc *svc) VerifyAuditChain(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ChainVerifyReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tenantID := daoaudit.ChainTenantID(cts.Kit.TenantID)
+	return chain.Verify(cts.Kit, svc.dao, tenantID, req.StartSeq, req.EndSeq)
+}
@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	coreaudit "hcm/pkg/api/core/audit"
+	"hcm/pkg/cc"
+	"hcm/pkg/dal/dao"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+)
+
+// Exporter export the audits on hash chains to the external sink as json lines. The exported position of each
+// tenant is saved as the export seq of the chain head after the audits are written to sink, so audits are exported
+// at least once, consumers can deduplicate them by tenant id and chain seq.
+type Exporter struct {
+	dao       dao.Set
+	sink      Sink
+	interval  time.Duration
+	batchSize uint
+}
+
+// NewExporter create audit exporter.
+func NewExporter(dao dao.Set, conf cc.AuditExport) (*Exporter, error) {
+	sink, err := NewSink(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		dao:       dao,
+		sink:      sink,
+		interval:  time.Duration(conf.IntervalSec) * time.Second,
+		batchSize: conf.BatchSize,
+	}, nil
+}
+
+// exportRecord is the exported json line of audit.
+type exportRecord struct {
+	TenantID        string `json:"tenant_id"`
+	coreaudit.Audit `json:",inline"`
+}
+
+// Run export audits periodically, only the master node exports audits.
+func (e *Exporter) Run(state serviced.State) {
+	logs.Infof("audit export enable, interval: %v, batch size: %d", e.interval, e.batchSize)
+
+	for {
+		time.Sleep(e.interval)
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := e.Export(kt); err != nil {
+			logs.Errorf("export audits failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+// Export export the audits of all tenants which are not exported yet.
+func (e *Exporter) Export(kt *kit.Kit) error {
+	heads, err := e.dao.Audit().ListChain(kt)
+	if err != nil {
+		return err
+	}
+
+	for _, head := range heads {
+		if err = e.exportTenant(kt, head); err != nil {
+			logs.Errorf("export audits of tenant failed, err: %v, tenant: %s, export seq: %d, rid: %s", err,
+				head.TenantID, head.ExportSeq, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) exportTenant(kt *kit.Kit, head tableaudit.AuditChainTable) error {
+	exportSeq := head.ExportSeq
+	for exportSeq < head.LastSeq {
+		records, err := e.dao.Audit().ListChainRecords(kt, head.TenantID, exportSeq+1, 0, e.batchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			return nil
+		}
+
+		lines := make([][]byte, 0, len(records))
+		for _, one := range records {
+			line, err := json.Marshal(convExportRecord(head.TenantID, one))
+			if err != nil {
+				return fmt.Errorf("marshal audit %d failed, err: %v", one.ID, err)
+			}
+			lines = append(lines, line)
+		}
+
+		if err = e.sink.Write(lines); err != nil {
+			return err
+		}
+
+		exportSeq = records[len(records)-1].ChainSeq
+		if err = e.dao.Audit().UpdateChainExportSeq(kt, head.TenantID, exportSeq); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convExportRecord(tenantID string, one tableaudit.AuditTable) *exportRecord {
+	return &exportRecord{
+		TenantID: tenantID,
+		Audit: coreaudit.Audit{
+			ID:         one.ID,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			ResName:    one.ResName,
+			ResType:    one.ResType,
+			Action:     one.Action,
+			BkBizID:    one.BkBizID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			Operator:   one.Operator,
+			Source:     one.Source,
+			Rid:        one.Rid,
+			AppCode:    one.AppCode,
+			Detail:     one.Detail,
+			ChainSeq:   one.ChainSeq,
+			PrevHash:   one.PrevHash,
+			Hash:       one.Hash,
+			CreatedAt:  one.CreatedAt.String(),
+		},
+	}
+}
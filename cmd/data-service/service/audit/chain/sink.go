@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"log/syslog"
+	"os"
+
+	"hcm/pkg/cc"
+)
+
+// Sink is the external sink that audits are exported to, each line is a json encoded audit.
+type Sink interface {
+	Write(lines [][]byte) error
+	Close() error
+}
+
+// NewSink create the sink of audit export.
+func NewSink(conf cc.AuditExport) (Sink, error) {
+	switch conf.Sink {
+	case cc.AuditExportFileSink:
+		return &fileSink{path: conf.File.Path}, nil
+	case cc.AuditExportSyslogSink:
+		writer, err := syslog.Dial(conf.Syslog.Network, conf.Syslog.Address, syslog.LOG_INFO|syslog.LOG_LOCAL0,
+			conf.Syslog.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("dial syslog failed, err: %v", err)
+		}
+		return &syslogSink{writer: writer}, nil
+	default:
+		return nil, fmt.Errorf("unsupported audit export sink: %s", conf.Sink)
+	}
+}
+
+// fileSink append audits to local file, the file is reopened on each write so that it works with logrotate.
+type fileSink struct {
+	path string
+}
+
+// Write audits to file.
+func (f *fileSink) Write(lines [][]byte) error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return fmt.Errorf("open audit export file %s failed, err: %v", f.path, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if _, err = file.Write(buf.Bytes()); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write audit export file %s failed, err: %v", f.path, err)
+	}
+
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("sync audit export file %s failed, err: %v", f.path, err)
+	}
+
+	return file.Close()
+}
+
+// Close file sink.
+func (f *fileSink) Close() error {
+	return nil
+}
+
+// syslogSink send each audit as a syslog message.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// Write audits to syslog.
+func (s *syslogSink) Write(lines [][]byte) error {
+	for _, line := range lines {
+		if err := s.writer.Info(string(line)); err != nil {
+			return fmt.Errorf("write audit to syslog failed, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Close syslog sink.
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,233 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package chain verify and export the tamper-evident audit hash chains.
+package chain
+
+import (
+	"fmt"
+
+	coreaudit "hcm/pkg/api/core/audit"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// recordPageSize is the page size of listing audits on the hash chain.
+const recordPageSize = 500
+
+// recordLister list at most limit audits on the hash chain in order of chain seq and id, starting from the
+// position of (startSeq, startID).
+type recordLister func(startSeq, startID uint64, limit uint) ([]tableaudit.AuditTable, error)
+
+// Verify verify the audit hash chain of tenant from startSeq to endSeq, it detects the missing, duplicated and
+// modified audits. startSeq 0 means from the first audit, endSeq 0 means to the latest audit of the chain head.
+func Verify(kt *kit.Kit, dao dao.Set, tenantID string, startSeq, endSeq uint64) (*coreaudit.ChainVerifyResult,
+	error) {
+
+	head, err := dao.Audit().GetChain(kt, tenantID)
+	if err != nil {
+		logs.Errorf("get audit chain head failed, err: %v, tenant: %s, rid: %s", err, tenantID, kt.Rid)
+		return nil, err
+	}
+
+	lister := func(startSeq, startID uint64, limit uint) ([]tableaudit.AuditTable, error) {
+		return dao.Audit().ListChainRecords(kt, tenantID, startSeq, startID, limit)
+	}
+	anchor, err := getAnchor(startSeq, lister)
+	if err != nil {
+		logs.Errorf("get audit chain anchor failed, err: %v, tenant: %s, seq: %d, rid: %s", err, tenantID,
+			startSeq, kt.Rid)
+		return nil, err
+	}
+
+	result, err := verify(tenantID, head, startSeq, endSeq, anchor, lister)
+	if err != nil {
+		logs.Errorf("verify audit chain failed, err: %v, tenant: %s, rid: %s", err, tenantID, kt.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getAnchor get the audit previous to startSeq whose hash the audit of startSeq links to, returns nil if verifying
+// from the first audit or the previous audit is missing.
+func getAnchor(startSeq uint64, list recordLister) (*tableaudit.AuditTable, error) {
+	if startSeq <= 1 {
+		return nil, nil
+	}
+
+	records, err := list(startSeq-1, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 || records[0].ChainSeq != startSeq-1 {
+		return nil, nil
+	}
+	return &records[0], nil
+}
+
+// verify verify the audits from startSeq to endSeq, anchor is the previous audit of startSeq which is loaded
+// separately, audits of startSeq are linked to it.
+func verify(tenantID string, head *tableaudit.AuditChainTable, startSeq, endSeq uint64,
+	anchor *tableaudit.AuditTable, list recordLister) (*coreaudit.ChainVerifyResult, error) {
+
+	var lastSeq uint64
+	var lastHash string
+	if head != nil {
+		lastSeq, lastHash = head.LastSeq, head.LastHash
+	}
+
+	if startSeq == 0 {
+		startSeq = 1
+	}
+	// records beyond the chain head are also checked when verifying to the latest audit
+	toHead := endSeq == 0 || endSeq >= lastSeq
+	if endSeq == 0 {
+		endSeq = lastSeq
+	}
+
+	v := &verifier{
+		tenantID: tenantID,
+		lastSeq:  lastSeq,
+		expect:   startSeq,
+		// the first audit of the chain links to an empty hash, otherwise the previous audit is used as anchor
+		linked: startSeq == 1 || anchor != nil,
+		result: &coreaudit.ChainVerifyResult{
+			TenantID: tenantID,
+			StartSeq: startSeq,
+			EndSeq:   endSeq,
+			LastSeq:  lastSeq,
+			Issues:   make([]coreaudit.ChainIssue, 0),
+		},
+	}
+
+	if anchor != nil {
+		v.prevHash = anchor.Hash
+	}
+
+	cursorSeq, cursorID := startSeq, uint64(0)
+	for {
+		records, err := list(cursorSeq, cursorID, recordPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range records {
+			one := &records[i]
+			if !toHead && one.ChainSeq > endSeq {
+				return v.finish(endSeq, toHead, lastHash), nil
+			}
+
+			if err = v.check(one); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(records) < recordPageSize {
+			break
+		}
+		last := records[len(records)-1]
+		cursorSeq, cursorID = last.ChainSeq, last.ID+1
+	}
+
+	return v.finish(endSeq, toHead, lastHash), nil
+}
+
+type verifier struct {
+	tenantID string
+	lastSeq  uint64
+	// expect is the chain seq of the next audit, prevHash is the hash of the previous audit
+	expect   uint64
+	prevHash string
+	// linked defines whether prevHash is known to check the link of the next audit
+	linked bool
+	result *coreaudit.ChainVerifyResult
+}
+
+func (v *verifier) check(one *tableaudit.AuditTable) error {
+	v.result.Checked++
+
+	if one.ChainSeq < v.expect {
+		v.addIssue(enumor.AuditChainDuplicate, one.ChainSeq, 0, one.ID,
+			fmt.Sprintf("chain seq %d is duplicated", one.ChainSeq))
+		return nil
+	}
+
+	if one.ChainSeq > v.expect {
+		v.addIssue(enumor.AuditChainGap, v.expect, one.ChainSeq-1, 0,
+			fmt.Sprintf("audits of chain seq [%d, %d] are missing", v.expect, one.ChainSeq-1))
+		v.linked = false
+	}
+
+	hash, err := one.ChainHash(v.tenantID)
+	if err != nil {
+		return fmt.Errorf("calculate hash of audit %d failed, err: %v", one.ID, err)
+	}
+
+	if hash != one.Hash {
+		v.addIssue(enumor.AuditChainModified, one.ChainSeq, 0, one.ID, "audit content does not match its hash")
+	}
+
+	if v.linked && one.PrevHash != v.prevHash {
+		v.addIssue(enumor.AuditChainBrokenLink, one.ChainSeq, 0, one.ID,
+			"prev hash does not match the hash of previous audit")
+	}
+
+	if one.ChainSeq > v.lastSeq {
+		v.addIssue(enumor.AuditChainHeadMismatch, one.ChainSeq, 0, one.ID,
+			fmt.Sprintf("chain seq is beyond the latest seq %d of chain head", v.lastSeq))
+	}
+
+	v.expect, v.prevHash, v.linked = one.ChainSeq+1, one.Hash, true
+	return nil
+}
+
+func (v *verifier) finish(endSeq uint64, toHead bool, lastHash string) *coreaudit.ChainVerifyResult {
+	if v.expect <= endSeq {
+		v.addIssue(enumor.AuditChainGap, v.expect, endSeq, 0,
+			fmt.Sprintf("audits of chain seq [%d, %d] are missing", v.expect, endSeq))
+	}
+
+	if toHead && v.lastSeq > 0 && v.expect == v.lastSeq+1 && v.prevHash != lastHash {
+		v.addIssue(enumor.AuditChainHeadMismatch, v.lastSeq, 0, 0,
+			"hash of the latest audit does not match the chain head")
+	}
+
+	v.result.Valid = v.result.IssueCount == 0
+	return v.result
+}
+
+func (v *verifier) addIssue(typ enumor.AuditChainIssueType, seq, endSeq, auditID uint64, msg string) {
+	v.result.IssueCount++
+	if len(v.result.Issues) >= coreaudit.ChainVerifyMaxIssues {
+		return
+	}
+
+	v.result.Issues = append(v.result.Issues, coreaudit.ChainIssue{
+		Type:    typ,
+		Seq:     seq,
+		EndSeq:  endSeq,
+		AuditID: auditID,
+		Message: msg,
+	})
+}
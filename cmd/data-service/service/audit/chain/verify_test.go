@@ -0,0 +1,258 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	coreaudit "hcm/pkg/api/core/audit"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/dal/table/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTenantID = "tenant-1"
+
+type testDetail struct {
+	Name  string   `json:"name"`
+	Port  int64    `json:"port"`
+	Ratio float64  `json:"ratio"`
+	Tags  []string `json:"tags"`
+}
+
+// buildTestChain build audits on hash chain like they are read from db, details are decoded as generic json.
+func buildTestChain(t *testing.T, count int) ([]tableaudit.AuditTable, *tableaudit.AuditChainTable) {
+	records := make([]tableaudit.AuditTable, 0, count)
+	prevHash := ""
+	for i := 1; i <= count; i++ {
+		one := tableaudit.AuditTable{
+			ID:       uint64(1000 + i),
+			ResID:    fmt.Sprintf("cvm-%d", i),
+			ResName:  "<web>&server",
+			ResType:  enumor.CvmAuditResType,
+			Action:   enumor.Update,
+			BkBizID:  100,
+			Vendor:   enumor.TCloud,
+			Operator: "admin",
+			Source:   enumor.ApiCall,
+			Rid:      "rid",
+			Detail: &tableaudit.BasicDetail{
+				Data:    testDetail{Name: "web", Port: int64(8000 + i), Ratio: 0.3, Tags: []string{"b", "a"}},
+				Changed: map[string]interface{}{"port": i, "name": "web"},
+			},
+			ChainSeq:  uint64(i),
+			PrevHash:  prevHash,
+			CreatedAt: types.Time(time.Unix(int64(1760000000+i), 0).UTC().Format(constant.TimeStdFormat)),
+		}
+		hash, err := one.ChainHash(testTenantID)
+		assert.NoError(t, err)
+		one.Hash = hash
+		prevHash = hash
+
+		raw, err := one.Detail.Value()
+		assert.NoError(t, err)
+		decoded := new(tableaudit.BasicDetail)
+		assert.NoError(t, decoded.Scan(raw))
+		one.Detail = decoded
+
+		records = append(records, one)
+	}
+
+	return records, &tableaudit.AuditChainTable{TenantID: testTenantID, LastSeq: uint64(count), LastHash: prevHash}
+}
+
+func testLister(records []tableaudit.AuditTable) recordLister {
+	return func(startSeq, startID uint64, limit uint) ([]tableaudit.AuditTable, error) {
+		result := make([]tableaudit.AuditTable, 0)
+		for _, one := range records {
+			if one.ChainSeq < startSeq || (one.ChainSeq == startSeq && one.ID < startID) {
+				continue
+			}
+			if uint(len(result)) >= limit {
+				break
+			}
+			result = append(result, one)
+		}
+		return result, nil
+	}
+}
+
+func issueTypes(issues []coreaudit.ChainIssue) []enumor.AuditChainIssueType {
+	result := make([]enumor.AuditChainIssueType, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, issue.Type)
+	}
+	return result
+}
+
+func TestChainHash(t *testing.T) {
+	records, _ := buildTestChain(t, 2)
+
+	// 从db读取的通用json详情与写入时的结构化详情计算出相同的哈希
+	hash, err := records[1].ChainHash(testTenantID)
+	assert.NoError(t, err)
+	assert.Equal(t, records[1].Hash, hash)
+
+	// 租户、序号、前序哈希及审计内容都会影响哈希
+	otherTenant, err := records[1].ChainHash("tenant-2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, otherTenant)
+
+	modified := records[1]
+	modified.Operator = "guest"
+	modifiedHash, err := modified.ChainHash(testTenantID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, modifiedHash)
+
+	// 创建时间按时间点参与哈希，与时区无关
+	modified = records[1]
+	createdAt, err := time.Parse(constant.TimeStdFormat, string(modified.CreatedAt))
+	assert.NoError(t, err)
+	modified.CreatedAt = types.Time(createdAt.In(time.FixedZone("CST", 8*3600)).Format(constant.TimeStdFormat))
+	modifiedHash, err = modified.ChainHash(testTenantID)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, modifiedHash)
+
+	modified.CreatedAt = types.Time(createdAt.Add(time.Second).Format(constant.TimeStdFormat))
+	modifiedHash, err = modified.ChainHash(testTenantID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, modifiedHash)
+
+	modified = records[1]
+	modified.PrevHash = ""
+	modifiedHash, err = modified.ChainHash(testTenantID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, modifiedHash)
+}
+
+func TestVerifyValidChain(t *testing.T) {
+	records, head := buildTestChain(t, 1200)
+
+	result, err := verify(testTenantID, head, 0, 0, nil, testLister(records))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, uint64(1200), result.Checked)
+	assert.Equal(t, uint64(1), result.StartSeq)
+	assert.Equal(t, uint64(1200), result.EndSeq)
+
+	// 校验部分区间时单独加载前一条记录作为锚点
+	anchor, err := getAnchor(600, testLister(records))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(599), anchor.ChainSeq)
+	result, err = verify(testTenantID, head, 600, 800, anchor, testLister(records))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, uint64(201), result.Checked)
+
+	// 锚点与区间首条记录的链接被破坏
+	brokenAnchor := *anchor
+	brokenAnchor.Hash = "broken"
+	result, err = verify(testTenantID, head, 600, 800, &brokenAnchor, testLister(records))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainBrokenLink}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(600), result.Issues[0].Seq)
+
+	// 锚点缺失时不校验区间首条记录的链接
+	anchor, err = getAnchor(600, testLister(append(append([]tableaudit.AuditTable{}, records[:598]...),
+		records[599:]...)))
+	assert.NoError(t, err)
+	assert.Nil(t, anchor)
+
+	// 没有审计记录的租户
+	result, err = verify(testTenantID, nil, 0, 0, nil, testLister(nil))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, uint64(0), result.Checked)
+}
+
+func TestVerifyTamperedChain(t *testing.T) {
+	records, head := buildTestChain(t, 10)
+
+	// 修改审计内容
+	modified := append([]tableaudit.AuditTable{}, records...)
+	modified[3].Operator = "guest"
+	result, err := verify(testTenantID, head, 0, 0, nil, testLister(modified))
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainModified}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(4), result.Issues[0].Seq)
+	assert.Equal(t, records[3].ID, result.Issues[0].AuditID)
+
+	// 修改审计内容并重新计算其哈希，下一条记录的链接被破坏
+	rehashed := append([]tableaudit.AuditTable{}, records...)
+	rehashed[3].Operator = "guest"
+	rehashed[3].Hash, err = rehashed[3].ChainHash(testTenantID)
+	assert.NoError(t, err)
+	result, err = verify(testTenantID, head, 0, 0, nil, testLister(rehashed))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainBrokenLink}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(5), result.Issues[0].Seq)
+
+	// 删除中间的审计记录
+	deleted := append(append([]tableaudit.AuditTable{}, records[:4]...), records[6:]...)
+	result, err = verify(testTenantID, head, 0, 0, nil, testLister(deleted))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainGap}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(5), result.Issues[0].Seq)
+	assert.Equal(t, uint64(6), result.Issues[0].EndSeq)
+
+	// 删除最新的审计记录
+	result, err = verify(testTenantID, head, 0, 0, nil, testLister(records[:8]))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainGap}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(9), result.Issues[0].Seq)
+	assert.Equal(t, uint64(10), result.Issues[0].EndSeq)
+
+	// 删除最新的审计记录并回退链头的序号
+	truncatedHead := &tableaudit.AuditChainTable{TenantID: testTenantID, LastSeq: 8, LastHash: head.LastHash}
+	result, err = verify(testTenantID, truncatedHead, 0, 0, nil, testLister(records[:8]))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainHeadMismatch}, issueTypes(result.Issues))
+
+	// 重复的序号
+	duplicated := append([]tableaudit.AuditTable{}, records...)
+	dup := records[2]
+	dup.ID = 9999
+	duplicated = append(append(duplicated[:3:3], dup), records[3:]...)
+	result, err = verify(testTenantID, head, 0, 0, nil, testLister(duplicated))
+	assert.NoError(t, err)
+	assert.Equal(t, []enumor.AuditChainIssueType{enumor.AuditChainDuplicate}, issueTypes(result.Issues))
+	assert.Equal(t, uint64(9999), result.Issues[0].AuditID)
+}
+
+func TestExportRecord(t *testing.T) {
+	records, _ := buildTestChain(t, 1)
+
+	line, err := json.Marshal(convExportRecord(testTenantID, records[0]))
+	assert.NoError(t, err)
+
+	decoded := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(line, &decoded))
+	assert.Equal(t, testTenantID, decoded["tenant_id"])
+	assert.Equal(t, float64(1), decoded["chain_seq"])
+	assert.Equal(t, records[0].Hash, decoded["hash"])
+	assert.Equal(t, "cvm-1", decoded["res_id"])
+}
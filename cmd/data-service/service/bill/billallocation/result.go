@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billallocation
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReplaceBillAllocationResult replace the allocation results of given main account bill day and version,
+// old results of the same version are removed in the same transaction.
+func (svc *service) ReplaceBillAllocationResult(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.AllocationResultReplaceReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	results := make([]tablebill.AccountBillAllocationResult, 0, len(req.Results))
+	for _, one := range req.Results {
+		results = append(results, tablebill.AccountBillAllocationResult{
+			RuleID:        one.RuleID,
+			Vendor:        req.Vendor,
+			RootAccountID: req.RootAccountID,
+			MainAccountID: req.MainAccountID,
+			BillYear:      req.BillYear,
+			BillMonth:     req.BillMonth,
+			BillDay:       req.BillDay,
+			VersionID:     req.VersionID,
+			SourceBkBizID: one.SourceBkBizID,
+			BkBizID:       one.BkBizID,
+			Currency:      one.Currency,
+			Cost:          &types.Decimal{Decimal: one.Cost},
+			ItemCount:     one.ItemCount,
+			Creator:       cts.Kit.User,
+			Reviser:       cts.Kit.User,
+		})
+	}
+
+	delFilter := tools.ExpressionAnd(
+		tools.RuleEqual("vendor", req.Vendor),
+		tools.RuleEqual("root_account_id", req.RootAccountID),
+		tools.RuleEqual("main_account_id", req.MainAccountID),
+		tools.RuleEqual("bill_year", req.BillYear),
+		tools.RuleEqual("bill_month", req.BillMonth),
+		tools.RuleEqual("bill_day", req.BillDay),
+		tools.RuleEqual("version_id", req.VersionID),
+	)
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.AccountBillAllocationResult().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			logs.Errorf("delete bill allocation result failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(results) == 0 {
+			return nil, nil
+		}
+
+		if _, err := svc.dao.AccountBillAllocationResult().CreateWithTx(cts.Kit, txn, results); err != nil {
+			logs.Errorf("create bill allocation result failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillAllocationResult list bill allocation result.
+func (svc *service) ListBillAllocationResult(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillAllocationResult().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dsbill.AllocationResultListResult{Details: slice.Map(data.Details, convAllocationResult),
+		Count: data.Count}, nil
+}
+
+func convAllocationResult(r tablebill.AccountBillAllocationResult) bill.AllocationResult {
+	result := bill.AllocationResult{
+		ID:            r.ID,
+		RuleID:        r.RuleID,
+		Vendor:        r.Vendor,
+		RootAccountID: r.RootAccountID,
+		MainAccountID: r.MainAccountID,
+		BillYear:      r.BillYear,
+		BillMonth:     r.BillMonth,
+		BillDay:       r.BillDay,
+		VersionID:     r.VersionID,
+		SourceBkBizID: r.SourceBkBizID,
+		BkBizID:       r.BkBizID,
+		Currency:      r.Currency,
+		ItemCount:     r.ItemCount,
+		Revision: &core.Revision{
+			Creator:   r.Creator,
+			Reviser:   r.Reviser,
+			CreatedAt: r.CreatedAt.String(),
+			UpdatedAt: r.UpdatedAt.String(),
+		},
+	}
+	if r.Cost != nil {
+		result.Cost = cvt.ValToPtr(r.Cost.Decimal)
+	}
+
+	return result
+}
@@ -0,0 +1,280 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billallocation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateBillAllocationRule create bill allocation rules.
+func (svc *service) BatchCreateBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.BatchCreateAllocationRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := make([]tablebill.AccountBillAllocationRule, 0, len(req.Rules))
+	for _, one := range req.Rules {
+		matcher, err := types.NewJsonField(one.Matcher)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		config, err := types.NewJsonField(one.Config)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		rules = append(rules, tablebill.AccountBillAllocationRule{
+			Name:     one.Name,
+			Vendor:   one.Vendor,
+			Priority: cvt.ValToPtr(one.Priority),
+			Enabled:  cvt.ValToPtr(one.Enabled),
+			Matcher:  matcher,
+			Method:   one.Method,
+			Config:   config,
+			Memo:     one.Memo,
+			Creator:  cts.Kit.User,
+			Reviser:  cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		ids, err := svc.dao.AccountBillAllocationRule().CreateWithTx(cts.Kit, txn, rules)
+		if err != nil {
+			logs.Errorf("create bill allocation rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, fmt.Errorf("create bill allocation rule failed, err: %v", err)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idList, ok := ids.([]string)
+	if !ok {
+		return nil, fmt.Errorf("create bill allocation rule but return ids type %T is not []string", ids)
+	}
+
+	return &core.BatchCreateResult{IDs: idList}, nil
+}
+
+// UpdateBillAllocationRule update bill allocation rule.
+func (svc *service) UpdateBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(dsbill.AllocationRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rule := &tablebill.AccountBillAllocationRule{
+		ID:       req.ID,
+		Name:     req.Name,
+		Priority: req.Priority,
+		Enabled:  req.Enabled,
+		Method:   req.Method,
+		Memo:     req.Memo,
+		Reviser:  cts.Kit.User,
+	}
+
+	if req.Matcher != nil {
+		matcher, err := types.NewJsonField(req.Matcher)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		rule.Matcher = matcher
+	}
+
+	if req.Config != nil {
+		origin, err := svc.getRule(cts, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err = req.Config.Validate(req.Method, origin.Vendor); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		config, err := types.NewJsonField(req.Config)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		rule.Config = config
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.AccountBillAllocationRule().UpdateByIDWithTx(cts.Kit, txn, rule.ID, rule); err != nil {
+			logs.Errorf("update bill allocation rule failed, err: %v, id: %s, rid: %s", err, rule.ID, cts.Kit.Rid)
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) getRule(cts *rest.Contexts, id string) (*tablebill.AccountBillAllocationRule, error) {
+	opt := &daotypes.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list bill allocation rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "bill allocation rule: %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// ListBillAllocationRule list bill allocation rule.
+func (svc *service) ListBillAllocationRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]bill.AllocationRule, 0, len(data.Details))
+	for _, one := range data.Details {
+		rule, err := convAllocationRule(one)
+		if err != nil {
+			logs.Errorf("convert bill allocation rule failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, rule)
+	}
+
+	return &dsbill.AllocationRuleListResult{Details: details, Count: data.Count}, nil
+}
+
+func convAllocationRule(r tablebill.AccountBillAllocationRule) (bill.AllocationRule, error) {
+	rule := bill.AllocationRule{
+		ID:       r.ID,
+		Name:     r.Name,
+		Vendor:   r.Vendor,
+		Priority: cvt.PtrToVal(r.Priority),
+		Enabled:  cvt.PtrToVal(r.Enabled),
+		Method:   r.Method,
+		Memo:     cvt.PtrToVal(r.Memo),
+		Revision: &core.Revision{
+			Creator:   r.Creator,
+			Reviser:   r.Reviser,
+			CreatedAt: r.CreatedAt.String(),
+			UpdatedAt: r.UpdatedAt.String(),
+		},
+	}
+
+	if len(r.Matcher) != 0 {
+		if err := json.Unmarshal([]byte(r.Matcher), &rule.Matcher); err != nil {
+			return rule, fmt.Errorf("unmarshal matcher failed, err: %v", err)
+		}
+	}
+	if len(r.Config) != 0 {
+		if err := json.Unmarshal([]byte(r.Config), &rule.Config); err != nil {
+			return rule, fmt.Errorf("unmarshal config failed, err: %v", err)
+		}
+	}
+
+	return rule, nil
+}
+
+// BatchDeleteBillAllocationRule delete bill allocation rules.
+func (svc *service) BatchDeleteBillAllocationRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	listResp, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list bill allocation rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list bill allocation rule failed, err: %v", err)
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", delIDs)
+		if err := svc.dao.AccountBillAllocationRule().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			logs.Errorf("delete bill allocation rule failed, err: %v, ids: %v, rid: %s", err, delIDs, cts.Kit.Rid)
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
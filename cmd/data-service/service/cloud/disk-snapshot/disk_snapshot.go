@@ -0,0 +1,303 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot 云盘快照及快照策略的DB接口
+package disksnapshot
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabledisksnapshot "hcm/pkg/dal/table/cloud/disk-snapshot"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the disk snapshot service
+func InitService(cap *capability.Capability) {
+	svc := &diskSnapshotSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateDiskSnapshot", http.MethodPost, "/disk_snapshots/batch/create", svc.BatchCreateDiskSnapshot)
+	h.Add("BatchUpdateDiskSnapshot", http.MethodPatch, "/disk_snapshots/batch/update", svc.BatchUpdateDiskSnapshot)
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.BatchDeleteDiskSnapshot)
+
+	h.Add("CreateDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/create",
+		svc.CreateDiskSnapshotPolicy)
+	h.Add("UpdateDiskSnapshotPolicy", http.MethodPatch, "/disk_snapshot_policies", svc.UpdateDiskSnapshotPolicy)
+	h.Add("ListDiskSnapshotPolicy", http.MethodPost, "/disk_snapshot_policies/list", svc.ListDiskSnapshotPolicy)
+	h.Add("BatchDeleteDiskSnapshotPolicy", http.MethodDelete, "/disk_snapshot_policies/batch",
+		svc.BatchDeleteDiskSnapshotPolicy)
+
+	h.Load(cap.WebService)
+}
+
+type diskSnapshotSvc struct {
+	dao dao.Set
+}
+
+// BatchCreateDiskSnapshot batch create disk snapshot.
+func (svc *diskSnapshotSvc) BatchCreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tabledisksnapshot.DiskSnapshotTable, 0, len(req.Snapshots))
+	for _, one := range req.Snapshots {
+		extension, err := marshalExtension(one.Extension)
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, &tabledisksnapshot.DiskSnapshotTable{
+			Vendor:           one.Vendor,
+			AccountID:        one.AccountID,
+			CloudID:          one.CloudID,
+			BkBizID:          one.BkBizID,
+			Name:             one.Name,
+			Region:           one.Region,
+			Zone:             one.Zone,
+			DiskID:           one.DiskID,
+			CloudDiskID:      one.CloudDiskID,
+			DiskSize:         one.DiskSize,
+			Status:           one.Status,
+			Encrypted:        converter.ValToPtr(one.Encrypted),
+			PolicyID:         one.PolicyID,
+			Memo:             one.Memo,
+			CloudCreatedTime: one.CloudCreatedTime,
+			Extension:        extension,
+			Creator:          cts.Kit.User,
+			Reviser:          cts.Kit.User,
+		})
+	}
+
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskSnapshot().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create disk snapshot but return id type is not []string, id type: %T", result)
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// BatchUpdateDiskSnapshot batch update disk snapshot.
+func (svc *diskSnapshotSvc) BatchUpdateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, one := range req.Snapshots {
+			var extension tabletypes.JsonField
+			if one.Extension != nil {
+				var err error
+				if extension, err = marshalExtension(one.Extension); err != nil {
+					return nil, err
+				}
+			}
+
+			model := &tabledisksnapshot.DiskSnapshotTable{
+				BkBizID:   one.BkBizID,
+				Name:      one.Name,
+				DiskSize:  one.DiskSize,
+				Status:    one.Status,
+				Encrypted: one.Encrypted,
+				Memo:      one.Memo,
+				Extension: extension,
+				Reviser:   cts.Kit.User,
+			}
+			if one.DiskID != nil {
+				model.DiskID = *one.DiskID
+			}
+
+			if err := svc.dao.DiskSnapshot().UpdateByIDWithTx(cts.Kit, txn, one.ID, model); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshot list disk snapshot.
+func (svc *diskSnapshotSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &protocloud.DiskSnapshotListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coredisksnapshot.DiskSnapshot, 0, len(result.Details))
+	for _, one := range result.Details {
+		snapshot, err := convDiskSnapshot(one)
+		if err != nil {
+			logs.Errorf("convert disk snapshot %s failed, err: %v, rid: %s", one.ID, err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *snapshot)
+	}
+
+	return &protocloud.DiskSnapshotListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshot.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.DiskSnapshot().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", delIDs))
+	})
+	if err != nil {
+		logs.Errorf("delete disk snapshot failed, ids: %v, err: %v, rid: %s", delIDs, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func marshalExtension(ext *coredisksnapshot.Extension) (tabletypes.JsonField, error) {
+	if ext == nil {
+		ext = new(coredisksnapshot.Extension)
+	}
+
+	extension, err := json.MarshalToString(ext)
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return tabletypes.JsonField(extension), nil
+}
+
+func convDiskSnapshot(one tabledisksnapshot.DiskSnapshotTable) (*coredisksnapshot.DiskSnapshot, error) {
+	extension := new(coredisksnapshot.Extension)
+	if len(one.Extension) != 0 {
+		if err := json.UnmarshalFromString(string(one.Extension), extension); err != nil {
+			return nil, fmt.Errorf("unmarshal extension failed, err: %v", err)
+		}
+	}
+
+	return &coredisksnapshot.DiskSnapshot{
+		ID:               one.ID,
+		Vendor:           one.Vendor,
+		AccountID:        one.AccountID,
+		CloudID:          one.CloudID,
+		BkBizID:          one.BkBizID,
+		Name:             one.Name,
+		Region:           one.Region,
+		Zone:             one.Zone,
+		DiskID:           one.DiskID,
+		CloudDiskID:      one.CloudDiskID,
+		DiskSize:         one.DiskSize,
+		Status:           one.Status,
+		Encrypted:        one.Encrypted,
+		PolicyID:         one.PolicyID,
+		Memo:             one.Memo,
+		CloudCreatedTime: one.CloudCreatedTime,
+		Extension:        extension,
+		Revision: &core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}, nil
+}
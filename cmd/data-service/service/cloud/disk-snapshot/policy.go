@@ -0,0 +1,188 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabledisksnapshot "hcm/pkg/dal/table/cloud/disk-snapshot"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateDiskSnapshotPolicy create disk snapshot policy.
+func (svc *diskSnapshotSvc) CreateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotPolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tabledisksnapshot.DiskSnapshotPolicyTable{
+		Name:           req.Name,
+		Vendor:         req.Vendor,
+		AccountID:      req.AccountID,
+		BkBizID:        req.BkBizID,
+		DiskIDs:        req.DiskIDs,
+		CronExpr:       req.CronExpr,
+		TimeZone:       req.TimeZone,
+		RetentionCount: converter.ValToPtr(req.RetentionCount),
+		RetentionDays:  converter.ValToPtr(req.RetentionDays),
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+		Creator:        cts.Kit.User,
+		Reviser:        cts.Kit.User,
+	}
+	result, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskSnapshotPolicy().BatchCreateWithTx(cts.Kit, txn,
+			[]*tabledisksnapshot.DiskSnapshotPolicyTable{model})
+	})
+	if err != nil {
+		logs.Errorf("create disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := result.([]string)
+	if !ok || len(ids) != 1 {
+		return nil, fmt.Errorf("create disk snapshot policy but return ids is invalid, result: %v", result)
+	}
+
+	return &core.CreateResult{ID: ids[0]}, nil
+}
+
+// UpdateDiskSnapshotPolicy update disk snapshot policy.
+func (svc *diskSnapshotSvc) UpdateDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.DiskSnapshotPolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tabledisksnapshot.DiskSnapshotPolicyTable{
+		Name:           req.Name,
+		DiskIDs:        req.DiskIDs,
+		CronExpr:       req.CronExpr,
+		RetentionCount: req.RetentionCount,
+		RetentionDays:  req.RetentionDays,
+		Enabled:        req.Enabled,
+		ScheduleID:     req.ScheduleID,
+		Memo:           req.Memo,
+		Reviser:        cts.Kit.User,
+	}
+	if req.TimeZone != nil {
+		model.TimeZone = *req.TimeZone
+	}
+
+	if err := svc.dao.DiskSnapshotPolicy().Update(cts.Kit, tools.EqualExpression("id", req.ID), model); err != nil {
+		logs.Errorf("update disk snapshot policy failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListDiskSnapshotPolicy list disk snapshot policy.
+func (svc *diskSnapshotSvc) ListDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.DiskSnapshotPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &protocloud.DiskSnapshotPolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coredisksnapshot.Policy, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, coredisksnapshot.Policy{
+			ID:             one.ID,
+			Name:           one.Name,
+			Vendor:         one.Vendor,
+			AccountID:      one.AccountID,
+			BkBizID:        one.BkBizID,
+			DiskIDs:        one.DiskIDs,
+			CronExpr:       one.CronExpr,
+			TimeZone:       one.TimeZone,
+			RetentionCount: converter.PtrToVal(one.RetentionCount),
+			RetentionDays:  converter.PtrToVal(one.RetentionDays),
+			Enabled:        converter.PtrToVal(one.Enabled),
+			ScheduleID:     one.ScheduleID,
+			Memo:           one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.DiskSnapshotPolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteDiskSnapshotPolicy batch delete disk snapshot policy.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshotPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.DiskSnapshotPolicy().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete disk snapshot policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
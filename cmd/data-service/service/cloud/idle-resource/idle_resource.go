@@ -0,0 +1,219 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package idleresource 闲置资源的DB接口
+package idleresource
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coreidle "hcm/pkg/api/core/cloud/idle-resource"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tableidle "hcm/pkg/dal/table/cloud/idle-resource"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the idle resource service
+func InitService(cap *capability.Capability) {
+	svc := &idleResourceSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateIdleResource", http.MethodPost, "/idle_resources/batch/create", svc.BatchCreateIdleResource)
+	h.Add("BatchUpdateIdleResource", http.MethodPatch, "/idle_resources/batch/update", svc.BatchUpdateIdleResource)
+	h.Add("ListIdleResource", http.MethodPost, "/idle_resources/list", svc.ListIdleResource)
+	h.Add("BatchDeleteIdleResource", http.MethodDelete, "/idle_resources/batch", svc.BatchDeleteIdleResource)
+
+	h.Load(cap.WebService)
+}
+
+type idleResourceSvc struct {
+	dao dao.Set
+}
+
+// BatchCreateIdleResource batch create idle resource.
+func (svc *idleResourceSvc) BatchCreateIdleResource(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.IdleResourceBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tableidle.IdleResourceTable, 0, len(req.Items))
+	for _, item := range req.Items {
+		models = append(models, tableidle.IdleResourceTable{
+			Vendor:     item.Vendor,
+			ResType:    item.ResType,
+			ResID:      item.ResID,
+			CloudResID: item.CloudResID,
+			ResName:    item.ResName,
+			AccountID:  item.AccountID,
+			BkBizID:    item.BkBizID,
+			Region:     item.Region,
+			Reason:     item.Reason,
+			Cost:       &tabletypes.Decimal{Decimal: item.Cost},
+			Currency:   item.Currency,
+			Creator:    cts.Kit.User,
+			Reviser:    cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.IdleResource().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create idle resource failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	createdIDs, ok := ids.([]string)
+	if !ok {
+		return nil, errf.Newf(errf.Unknown, "batch create idle resource but return ids type is %T", ids)
+	}
+
+	return &core.BatchCreateResult{IDs: createdIDs}, nil
+}
+
+// BatchUpdateIdleResource batch update idle resource.
+func (svc *idleResourceSvc) BatchUpdateIdleResource(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.IdleResourceBatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, item := range req.Items {
+			model := &tableidle.IdleResourceTable{
+				ResName:  item.ResName,
+				BkBizID:  item.BkBizID,
+				Reason:   item.Reason,
+				Currency: item.Currency,
+				Reviser:  cts.Kit.User,
+			}
+			if item.Cost != nil {
+				model.Cost = &tabletypes.Decimal{Decimal: *item.Cost}
+			}
+
+			if err := svc.dao.IdleResource().UpdateByIDWithTx(cts.Kit, txn, item.ID, model); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update idle resource failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListIdleResource list idle resource.
+func (svc *idleResourceSvc) ListIdleResource(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.IdleResource().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list idle resource failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &protocloud.IdleResourceListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coreidle.IdleResource, 0, len(result.Details))
+	for _, one := range result.Details {
+		idle := coreidle.IdleResource{
+			ID:         one.ID,
+			Vendor:     one.Vendor,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			CloudResID: one.CloudResID,
+			ResName:    one.ResName,
+			AccountID:  one.AccountID,
+			BkBizID:    one.BkBizID,
+			Region:     one.Region,
+			Reason:     one.Reason,
+			Currency:   one.Currency,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+		if one.Cost != nil {
+			idle.Cost = one.Cost.Decimal
+		}
+		details = append(details, idle)
+	}
+
+	return &protocloud.IdleResourceListResult{Details: details}, nil
+}
+
+// BatchDeleteIdleResource batch delete idle resource.
+func (svc *idleResourceSvc) BatchDeleteIdleResource(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.IdleResource().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete idle resource failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
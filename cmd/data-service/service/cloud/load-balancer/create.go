@@ -52,6 +52,8 @@ func (svc *lbSvc) BatchCreateLoadBalancer(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchCreateLoadBalancer[corelb.TCloudClbExtension](cts, svc, vendor)
+	case enumor.Aws:
+		return batchCreateLoadBalancer[corelb.AwsLoadBalancerExtension](cts, svc, vendor)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
@@ -51,6 +51,8 @@ func (svc *lbSvc) BatchCreateTargetGroup(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchCreateTargetGroup[corelb.TCloudTargetGroupExtension](cts, svc, vendor)
+	case enumor.Aws:
+		return batchCreateTargetGroup[corelb.AwsTargetGroupExtension](cts, svc, vendor)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
@@ -133,6 +135,7 @@ func convTargetGroupCreateReqToTable[T corelb.TargetGroupExtension](kt *kit.Kit,
 	}
 
 	targetGroup := &tablelb.LoadBalancerTargetGroupTable{
+		CloudID:         tg.CloudID,
 		Name:            tg.Name,
 		Vendor:          vendor,
 		AccountID:       tg.AccountID,
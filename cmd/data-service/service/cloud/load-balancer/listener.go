@@ -51,6 +51,8 @@ func (svc *lbSvc) BatchCreateListener(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchCreateListener[corelb.TCloudListenerExtension](cts, svc)
+	case enumor.Aws:
+		return batchCreateListener[corelb.AwsListenerExtension](cts, svc)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
@@ -306,6 +308,8 @@ func (svc *lbSvc) BatchUpdateListener(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchUpdateListener[corelb.TCloudListenerExtension](cts)
+	case enumor.Aws:
+		return batchUpdateListener[corelb.AwsListenerExtension](cts)
 	default:
 		return nil, errf.New(errf.InvalidParameter, "unsupported vendor: "+string(vendor))
 	}
@@ -77,6 +77,13 @@ func (svc *lbSvc) GetListener(cts *rest.Contexts) (any, error) {
 			return nil, err
 		}
 		return newLblInfo, nil
+	case enumor.Aws:
+		newLblInfo, err := convTableToListener[corelb.AwsListenerExtension](&lblInfo)
+		if err != nil {
+			logs.Errorf("fail to conv listener with extension, lblID: %s, err: %v, rid: %s", id, err, cts.Kit.Rid)
+			return nil, err
+		}
+		return newLblInfo, nil
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
 	}
@@ -119,6 +126,22 @@ func (svc *lbSvc) ListListener(cts *rest.Contexts) (interface{}, error) {
 
 // ListListenerExt list listener with extension.
 func (svc *lbSvc) ListListenerExt(cts *rest.Contexts) (any, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	switch vendor {
+	case enumor.TCloud:
+		return listListenerExt[corelb.TCloudListenerExtension](cts, svc)
+	case enumor.Aws:
+		return listListenerExt[corelb.AwsListenerExtension](cts, svc)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "unsupported vendor: %s", vendor)
+	}
+}
+
+func listListenerExt[T corelb.ListenerExtension](cts *rest.Contexts, svc *lbSvc) (any, error) {
 	req := new(core.ListReq)
 	if err := cts.DecodeInto(req); err != nil {
 		return nil, err
@@ -143,16 +166,17 @@ func (svc *lbSvc) ListListenerExt(cts *rest.Contexts) (any, error) {
 		return &protocloud.ListenerListResult{Count: result.Count}, nil
 	}
 
-	details := make([]corelb.Listener[corelb.TCloudListenerExtension], 0, len(result.Details))
+	details := make([]corelb.Listener[T], 0, len(result.Details))
 	for _, one := range result.Details {
-		tmpOne, err := convTableToListener[corelb.TCloudListenerExtension](&one)
+		tmpOne, err := convTableToListener[T](&one)
 		if err != nil {
 			logs.Errorf("fail to conv listener with extension, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
 		}
 		details = append(details, *tmpOne)
 	}
 
-	return &protocloud.TCloudListenerListResult{Details: details}, nil
+	return &core.ListResultT[corelb.Listener[T]]{Details: details}, nil
 }
 
 func convTableToBaseListener(one *tablelb.LoadBalancerListenerTable) *corelb.BaseListener {
@@ -53,7 +53,8 @@ func InitService(cap *capability.Capability) {
 	// 监听器
 	h.Add("GetListener", http.MethodGet, "/vendors/{vendor}/listeners/{id}", svc.GetListener)
 	h.Add("ListListener", http.MethodPost, "/load_balancers/listeners/list", svc.ListListener)
-	h.Add("ListListenerExt", http.MethodPost, "/vendors/tcloud/load_balancers/listeners/list", svc.ListListenerExt)
+	h.Add("ListListenerExt", http.MethodPost, "/vendors/{vendor}/load_balancers/listeners/list",
+		svc.ListListenerExt)
 	h.Add("BatchCreateListener", http.MethodPost, "/vendors/{vendor}/listeners/batch/create", svc.BatchCreateListener)
 	h.Add("BatchCreateListenerWithRule", http.MethodPost, "/vendors/{vendor}/listeners/rules/batch/create",
 		svc.BatchCreateListenerWithRule)
@@ -103,6 +104,21 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchUpdateListenerRuleRelStatusByTGID", http.MethodPatch,
 		"/target_group_listener_rels/target_groups/{tg_id}/update", svc.BatchUpdateListenerRuleRelStatusByTGID)
 
+	// 配置快照
+	h.Add("CreateLoadBalancerSnapshot", http.MethodPost, "/load_balancers/snapshots/create",
+		svc.CreateLoadBalancerSnapshot)
+	h.Add("UpdateLoadBalancerSnapshot", http.MethodPatch, "/load_balancers/snapshots/{id}",
+		svc.UpdateLoadBalancerSnapshot)
+	h.Add("ListLoadBalancerSnapshot", http.MethodPost, "/load_balancers/snapshots/list", svc.ListLoadBalancerSnapshot)
+
+	// RS健康状态变化事件
+	h.Add("BatchCreateTargetHealthEvent", http.MethodPost, "/load_balancers/target_health_events/batch/create",
+		svc.BatchCreateTargetHealthEvent)
+	h.Add("ListTargetHealthEvent", http.MethodPost, "/load_balancers/target_health_events/list",
+		svc.ListTargetHealthEvent)
+	h.Add("BatchDeleteTargetHealthEvent", http.MethodDelete, "/load_balancers/target_health_events/batch",
+		svc.BatchDeleteTargetHealthEvent)
+
 	// 资源与Flow相关的接口
 	resFlowRel(h)
 
@@ -187,6 +187,8 @@ func (svc *lbSvc) ListLoadBalancerExt(cts *rest.Contexts) (interface{}, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return convLbListResult[corelb.TCloudClbExtension](data.Details)
+	case enumor.Aws:
+		return convLbListResult[corelb.AwsLoadBalancerExtension](data.Details)
 	default:
 		return nil, errf.Newf(errf.InvalidParameter, "unsupported vendor: %s", vendor)
 	}
@@ -221,7 +223,9 @@ func (svc *lbSvc) GetLoadBalancer(cts *rest.Contexts) (any, error) {
 	lbTable := result.Details[0]
 	switch lbTable.Vendor {
 	case enumor.TCloud:
-		return convLoadBalancerWithExt(&lbTable)
+		return convLoadBalancerWithExt[corelb.TCloudClbExtension](&lbTable)
+	case enumor.Aws:
+		return convLoadBalancerWithExt[corelb.AwsLoadBalancerExtension](&lbTable)
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
 	}
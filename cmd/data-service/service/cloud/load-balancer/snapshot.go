@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	typesdao "hcm/pkg/dal/dao/types"
+	tablelb "hcm/pkg/dal/table/cloud/load-balancer"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// lbSnapshotRetention 每个负载均衡保留的快照数量，超出时删除最早的快照
+const lbSnapshotRetention = 100
+
+// CreateLoadBalancerSnapshot 创建负载均衡配置快照，版本号在同一负载均衡下递增
+func (svc *lbSvc) CreateLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	req := new(dataproto.LbSnapshotCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	spec, err := types.NewJsonField(req.Spec)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	latest, err := svc.dao.LoadBalancerSnapshot().List(cts.Kit, &typesdao.ListOption{
+		Fields: []string{"version"},
+		Filter: tools.EqualExpression("lb_id", req.LbID),
+		Page:   &core.BasePage{Start: 0, Limit: 1, Sort: "version", Order: core.Descending},
+	})
+	if err != nil {
+		logs.Errorf("list latest load balancer snapshot failed, err: %v, lb: %s, rid: %s", err, req.LbID, cts.Kit.Rid)
+		return nil, err
+	}
+	version := uint64(1)
+	if len(latest.Details) > 0 {
+		version = latest.Details[0].Version + 1
+	}
+
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (any, error) {
+		model := &tablelb.LoadBalancerSnapshotTable{
+			Vendor:    req.Vendor,
+			LbID:      req.LbID,
+			AccountID: req.AccountID,
+			BkBizID:   req.BkBizID,
+			Version:   version,
+			Source:    req.Source,
+			FlowID:    req.FlowID,
+			Spec:      spec,
+			Creator:   cts.Kit.User,
+			Reviser:   cts.Kit.User,
+		}
+		id, err := svc.dao.LoadBalancerSnapshot().CreateWithTx(cts.Kit, txn, model)
+		if err != nil {
+			logs.Errorf("create load balancer snapshot failed, err: %v, lb: %s, rid: %s", err, req.LbID, cts.Kit.Rid)
+			return nil, fmt.Errorf("create load balancer snapshot failed, err: %v", err)
+		}
+
+		if version <= lbSnapshotRetention {
+			return id, nil
+		}
+		expr, err := tools.And(tools.RuleEqual("lb_id", req.LbID),
+			tools.RuleLessThanEqual("version", version-lbSnapshotRetention))
+		if err != nil {
+			return nil, err
+		}
+		if err = svc.dao.LoadBalancerSnapshot().DeleteWithTx(cts.Kit, txn, expr); err != nil {
+			logs.Errorf("delete expired load balancer snapshot failed, err: %v, lb: %s, rid: %s", err, req.LbID,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		return id, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idStr, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create load balancer snapshot but return id type is not string, id type: %T", id)
+	}
+	return &core.CreateResult{ID: idStr}, nil
+}
+
+// UpdateLoadBalancerSnapshot 更新快照关联的异步任务
+func (svc *lbSvc) UpdateLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dataproto.LbSnapshotUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (any, error) {
+		model := &tablelb.LoadBalancerSnapshotTable{
+			FlowID:  req.FlowID,
+			Reviser: cts.Kit.User,
+		}
+		return nil, svc.dao.LoadBalancerSnapshot().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update load balancer snapshot failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListLoadBalancerSnapshot 查询负载均衡配置快照
+func (svc *lbSvc) ListLoadBalancerSnapshot(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &typesdao.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.LoadBalancerSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list load balancer snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list load balancer snapshot failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dataproto.LbSnapshotListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corelb.LoadBalancerSnapshot, 0, len(result.Details))
+	for _, one := range result.Details {
+		snapshot := corelb.LoadBalancerSnapshot{
+			ID:        one.ID,
+			Vendor:    one.Vendor,
+			LbID:      one.LbID,
+			AccountID: one.AccountID,
+			BkBizID:   one.BkBizID,
+			Version:   one.Version,
+			Source:    one.Source,
+			FlowID:    one.FlowID,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+		if len(one.Spec) != 0 {
+			snapshot.Spec = new(corelb.TCloudLoadBalancerSpec)
+			if err = json.Unmarshal([]byte(one.Spec), snapshot.Spec); err != nil {
+				logs.Errorf("unmarshal load balancer snapshot spec failed, err: %v, id: %s, rid: %s", err, one.ID,
+					cts.Kit.Rid)
+				return nil, err
+			}
+		}
+		details = append(details, snapshot)
+	}
+
+	return &dataproto.LbSnapshotListResult{Details: details}, nil
+}
@@ -105,7 +105,7 @@ func (svc *lbSvc) GetTargetGroup(cts *rest.Contexts) (any, error) {
 
 	tgInfo := result.Details[0]
 	switch tgInfo.Vendor {
-	case enumor.TCloud:
+	case enumor.TCloud, enumor.Aws:
 		return convTableToBaseTargetGroup(cts.Kit, &tgInfo)
 	default:
 		return nil, fmt.Errorf("unsupport vendor: %s", vendor)
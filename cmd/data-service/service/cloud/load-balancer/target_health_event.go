@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataservice "hcm/pkg/api/data-service"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	typesdao "hcm/pkg/dal/dao/types"
+	tablelb "hcm/pkg/dal/table/cloud/load-balancer"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateTargetHealthEvent 批量创建RS健康状态变化事件
+func (svc *lbSvc) BatchCreateTargetHealthEvent(cts *rest.Contexts) (any, error) {
+	req := new(dataproto.TargetHealthEventBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (any, error) {
+		models := make([]*tablelb.TargetHealthEventTable, 0, len(req.Events))
+		for _, one := range req.Events {
+			models = append(models, &tablelb.TargetHealthEventTable{
+				Vendor:       one.Vendor,
+				AccountID:    one.AccountID,
+				BkBizID:      one.BkBizID,
+				LbID:         one.LbID,
+				CloudLbID:    one.CloudLbID,
+				CloudLblID:   one.CloudLblID,
+				CloudRuleID:  one.CloudRuleID,
+				CloudInstID:  one.CloudInstID,
+				IP:           one.IP,
+				Port:         one.Port,
+				Status:       one.Status,
+				PrevStatus:   one.PrevStatus,
+				StatusDetail: one.StatusDetail,
+				Creator:      cts.Kit.User,
+			})
+		}
+		ids, err := svc.dao.TargetHealthEvent().BatchCreateWithTx(cts.Kit, txn, models)
+		if err != nil {
+			logs.Errorf("batch create target health event failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, fmt.Errorf("batch create target health event failed, err: %v", err)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idList, ok := ids.([]string)
+	if !ok {
+		return nil, fmt.Errorf("batch create target health event but return ids type is not []string, id type: %T",
+			ids)
+	}
+	return &core.BatchCreateResult{IDs: idList}, nil
+}
+
+// ListTargetHealthEvent 查询RS健康状态变化事件
+func (svc *lbSvc) ListTargetHealthEvent(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &typesdao.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.TargetHealthEvent().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list target health event failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list target health event failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &dataproto.TargetHealthEventListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corelb.TargetHealthEvent, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corelb.TargetHealthEvent{
+			ID:           one.ID,
+			Vendor:       one.Vendor,
+			AccountID:    one.AccountID,
+			BkBizID:      one.BkBizID,
+			LbID:         one.LbID,
+			CloudLbID:    one.CloudLbID,
+			CloudLblID:   one.CloudLblID,
+			CloudRuleID:  one.CloudRuleID,
+			CloudInstID:  one.CloudInstID,
+			IP:           one.IP,
+			Port:         one.Port,
+			Status:       one.Status,
+			PrevStatus:   one.PrevStatus,
+			StatusDetail: one.StatusDetail,
+			Creator:      one.Creator,
+			CreatedAt:    one.CreatedAt.String(),
+		})
+	}
+
+	return &dataproto.TargetHealthEventListResult{Details: details}, nil
+}
+
+// BatchDeleteTargetHealthEvent 删除RS健康状态变化事件，用于清理超出保留时间的事件
+func (svc *lbSvc) BatchDeleteTargetHealthEvent(cts *rest.Contexts) (any, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (any, error) {
+		return nil, svc.dao.TargetHealthEvent().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete target health event failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -53,6 +53,8 @@ func (svc *lbSvc) BatchUpdateLoadBalancer(cts *rest.Contexts) (any, error) {
 	switch vendor {
 	case enumor.TCloud:
 		return batchUpdateLoadBalancer[corelb.TCloudClbExtension](cts, svc)
+	case enumor.Aws:
+		return batchUpdateLoadBalancer[corelb.AwsLoadBalancerExtension](cts, svc)
 
 	default:
 		return nil, fmt.Errorf("unsupport  vendor %s", vendor)
@@ -0,0 +1,218 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sgcompliance 安全组规则自定义合规检查项的DB接口
+package sgcompliance
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablesgcompliance "hcm/pkg/dal/table/cloud/sg-compliance"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the security group rule compliance check service
+func InitService(cap *capability.Capability) {
+	svc := &sgComplianceSvc{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateSGComplianceCheck", http.MethodPost, "/security_groups/compliance_checks/create",
+		svc.CreateSGComplianceCheck)
+	h.Add("UpdateSGComplianceCheck", http.MethodPatch, "/security_groups/compliance_checks",
+		svc.UpdateSGComplianceCheck)
+	h.Add("ListSGComplianceCheck", http.MethodPost, "/security_groups/compliance_checks/list",
+		svc.ListSGComplianceCheck)
+	h.Add("BatchDeleteSGComplianceCheck", http.MethodDelete, "/security_groups/compliance_checks/batch",
+		svc.BatchDeleteSGComplianceCheck)
+
+	h.Load(cap.WebService)
+}
+
+type sgComplianceSvc struct {
+	dao dao.Set
+}
+
+// CreateSGComplianceCheck create security group rule compliance check.
+func (svc *sgComplianceSvc) CreateSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGComplianceCheckCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	conditions, err := tabletypes.NewJsonField(req.Conditions)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablesgcompliance.SGComplianceCheckTable{
+		Name:       req.Name,
+		BkBizID:    req.BkBizID,
+		Severity:   req.Severity,
+		Enabled:    req.Enabled,
+		Conditions: conditions,
+		Memo:       req.Memo,
+		Creator:    cts.Kit.User,
+		Reviser:    cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.SGComplianceCheck().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create sg compliance check failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	checkID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create sg compliance check but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: checkID}, nil
+}
+
+// UpdateSGComplianceCheck update security group rule compliance check.
+func (svc *sgComplianceSvc) UpdateSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.SGComplianceCheckUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablesgcompliance.SGComplianceCheckTable{
+		Name:     req.Name,
+		Severity: req.Severity,
+		Enabled:  req.Enabled,
+		Memo:     req.Memo,
+		Reviser:  cts.Kit.User,
+	}
+	if req.Conditions != nil {
+		conditions, err := tabletypes.NewJsonField(req.Conditions)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.Conditions = conditions
+	}
+
+	if err := svc.dao.SGComplianceCheck().Update(cts.Kit, tools.EqualExpression("id", req.ID), model); err != nil {
+		logs.Errorf("update sg compliance check failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListSGComplianceCheck list security group rule compliance check.
+func (svc *sgComplianceSvc) ListSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.SGComplianceCheck().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list sg compliance check failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &protocloud.SGComplianceCheckListResult{Count: result.Count}, nil
+	}
+
+	details := make([]coresgcompliance.Check, 0, len(result.Details))
+	for _, one := range result.Details {
+		check := coresgcompliance.Check{
+			ID:       one.ID,
+			Name:     one.Name,
+			BkBizID:  one.BkBizID,
+			Severity: one.Severity,
+			Enabled:  converter.PtrToVal(one.Enabled),
+			Memo:     one.Memo,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		}
+		if !one.Conditions.IsEmpty() {
+			if err = json.UnmarshalFromString(string(one.Conditions), &check.Conditions); err != nil {
+				logs.Errorf("unmarshal sg compliance check conditions failed, err: %v, id: %s, rid: %s", err,
+					one.ID, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+		details = append(details, check)
+	}
+
+	return &protocloud.SGComplianceCheckListResult{Details: details}, nil
+}
+
+// BatchDeleteSGComplianceCheck batch delete security group rule compliance check.
+func (svc *sgComplianceSvc) BatchDeleteSGComplianceCheck(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.SGComplianceCheck().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete sg compliance check failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -66,11 +66,13 @@ func (s service) GenerateTemporalUrl(cts *rest.Contexts) (any, error) {
 		return nil, err
 	}
 
-	return &cos.GenerateTemporalUrlResult{
-		AK:    cred.TmpSecretID,
-		Token: cred.SessionToken,
-		URL:   url,
-	}, nil
+	result := &cos.GenerateTemporalUrlResult{URL: url}
+	// 部分存储的签名信息已包含在URL中，不需要返回临时密钥
+	if cred != nil {
+		result.AK = cred.AK
+		result.Token = cred.Token
+	}
+	return result, nil
 }
 
 // UploadFile uploads a file to COS.
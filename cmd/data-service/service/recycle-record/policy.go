@@ -0,0 +1,234 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/recycle-record"
+	dataservice "hcm/pkg/api/data-service"
+	protodata "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	prototable "hcm/pkg/dal/table/recycle-record"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateRecyclePolicy create recycle policy.
+func (svc *recycleRecordSvc) CreateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protodata.PolicyCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	purgeWindows, err := tabletype.NewJsonField(req.PurgeWindows)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &prototable.RecyclePolicyTable{
+		BkBizID:           req.BkBizID,
+		ResType:           req.ResType,
+		RetentionHours:    converter.ValToPtr(req.RetentionHours),
+		NotifyBeforeHours: converter.ValToPtr(req.NotifyBeforeHours),
+		TimeZone:          req.TimeZone,
+		PurgeWindows:      purgeWindows,
+		Memo:              req.Memo,
+		Creator:           cts.Kit.User,
+		Reviser:           cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.RecyclePolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	policyID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create recycle policy but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: policyID}, nil
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (svc *recycleRecordSvc) UpdateRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(protodata.PolicyUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &prototable.RecyclePolicyTable{
+		RetentionHours:    req.RetentionHours,
+		NotifyBeforeHours: req.NotifyBeforeHours,
+		Memo:              req.Memo,
+		Reviser:           cts.Kit.User,
+	}
+	if req.TimeZone != nil {
+		model.TimeZone = *req.TimeZone
+	}
+	if req.PurgeWindows != nil {
+		purgeWindows, err := tabletype.NewJsonField(req.PurgeWindows)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.PurgeWindows = purgeWindows
+	}
+
+	if err := svc.dao.RecyclePolicy().Update(cts.Kit, tools.EqualExpression("id", req.ID), model); err != nil {
+		logs.Errorf("update recycle policy failed, err: %v, id: %s, rid: %s", err, req.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListRecyclePolicy list recycle policy.
+func (svc *recycleRecordSvc) ListRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{Fields: req.Fields, Filter: req.Filter, Page: req.Page}
+	result, err := svc.dao.RecyclePolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &protodata.PolicyListResult{Count: result.Count}, nil
+	}
+
+	details := make([]protocore.Policy, 0, len(result.Details))
+	for _, one := range result.Details {
+		policy, err := convRecyclePolicy(one)
+		if err != nil {
+			logs.Errorf("convert recycle policy failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *policy)
+	}
+
+	return &protodata.PolicyListResult{Details: details}, nil
+}
+
+// BatchDeleteRecyclePolicy batch delete recycle policy.
+func (svc *recycleRecordSvc) BatchDeleteRecyclePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.RecyclePolicy().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func convRecyclePolicy(one prototable.RecyclePolicyTable) (*protocore.Policy, error) {
+	policy := &protocore.Policy{
+		ID:                one.ID,
+		BkBizID:           one.BkBizID,
+		ResType:           one.ResType,
+		RetentionHours:    converter.PtrToVal(one.RetentionHours),
+		NotifyBeforeHours: converter.PtrToVal(one.NotifyBeforeHours),
+		TimeZone:          one.TimeZone,
+		Memo:              one.Memo,
+		Revision: &core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+	if !one.PurgeWindows.IsEmpty() {
+		if err := json.UnmarshalFromString(string(one.PurgeWindows), &policy.PurgeWindows); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// listRecyclePolicy 获取业务下资源适用的回收站保留策略，包括业务下的策略和对所有业务生效的策略
+func (svc *recycleRecordSvc) listRecyclePolicy(kt *kit.Kit, resType enumor.CloudResourceType, bizIDs []int64) (
+	[]protocore.Policy, error) {
+
+	opt := &types.ListOption{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", resType),
+			tools.RuleIn("bk_biz_id", append(bizIDs, constant.UnassignedBiz)),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.RecyclePolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, res type: %s, rid: %s", err, resType, kt.Rid)
+		return nil, err
+	}
+
+	policies := make([]protocore.Policy, 0, len(result.Details))
+	for _, one := range result.Details {
+		policy, err := convRecyclePolicy(one)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *policy)
+	}
+
+	return policies, nil
+}
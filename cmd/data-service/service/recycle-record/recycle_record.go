@@ -27,6 +27,7 @@ import (
 	"hcm/pkg/api/core"
 	protocore "hcm/pkg/api/core/recycle-record"
 	protodata "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao"
@@ -39,7 +40,9 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/times"
 
 	"github.com/jmoiron/sqlx"
@@ -60,6 +63,11 @@ func InitRecycleRecordService(cap *capability.Capability) {
 	h.Add("BatchUpdateRecycleStatus", "PATCH", "/recycle_records/recycle_status/batch",
 		svc.BatchUpdateRecycleStatus)
 
+	h.Add("CreateRecyclePolicy", "POST", "/recycle_policies/create", svc.CreateRecyclePolicy)
+	h.Add("UpdateRecyclePolicy", "PATCH", "/recycle_policies", svc.UpdateRecyclePolicy)
+	h.Add("ListRecyclePolicy", "POST", "/recycle_policies/list", svc.ListRecyclePolicy)
+	h.Add("BatchDeleteRecyclePolicy", "DELETE", "/recycle_policies/batch", svc.BatchDeleteRecyclePolicy)
+
 	h.Load(cap.WebService)
 }
 
@@ -91,6 +99,15 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 		return nil, errf.Newf(errf.InvalidParameter, "recycle resource count is invalid")
 	}
 
+	bizIDs := make([]int64, 0, len(resourceInfo))
+	for _, info := range resourceInfo {
+		bizIDs = append(bizIDs, info.BkBizID)
+	}
+	policies, err := svc.listRecyclePolicy(cts.Kit, req.ResType, slice.Unique(bizIDs))
+	if err != nil {
+		return nil, err
+	}
+
 	taskID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		recycleRecords := make([]prototable.RecycleRecordTable, 0, len(resourceInfo))
 		for idx, info := range resourceInfo {
@@ -104,11 +121,8 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 				return nil, errf.NewFromErr(errf.InvalidParameter, err)
 			}
 
-			recycleReserveTime := req.DefaultRecycleTime
-			// TODO: 将默认时间修改放到cloud-server中去做
-			if accountInfo.Details[0].RecycleReserveTime > -1 {
-				recycleReserveTime = uint(accountInfo.Details[0].RecycleReserveTime)
-			}
+			recycleReserveTime := getRecycleReserveTime(policies, info.BkBizID, req.ResType,
+				accountInfo.Details[0].RecycleReserveTime, req.DefaultRecycleTime)
 			recycleRecords = append(recycleRecords, prototable.RecycleRecordTable{
 				RecycleType: req.RecycleType,
 				Vendor:      info.Vendor,
@@ -124,6 +138,7 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 				Creator:     cts.Kit.User,
 				Reviser:     cts.Kit.User,
 				RecycledAt:  times.ConvStdTimeNow().Add(time.Hour * time.Duration(recycleReserveTime)),
+				Notified:    converter.ValToPtr(false),
 			})
 		}
 		// 标记资源回收状态
@@ -145,6 +160,28 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 	return taskID, nil
 }
 
+// getRecycleReserveTime 获取资源在回收站中的保留时长，优先级依次为：业务下的回收站保留策略、账号设置的保留时长、
+// 对所有业务生效的回收站保留策略、默认保留时长
+func getRecycleReserveTime(policies []protocore.Policy, bizID int64, resType enumor.CloudResourceType,
+	accountReserveTime int, defaultTime uint) uint {
+
+	policy := protocore.MatchPolicy(policies, bizID, resType)
+	if policy != nil && policy.BkBizID != constant.UnassignedBiz {
+		return policy.RetentionHours
+	}
+
+	// TODO: 将默认时间修改放到cloud-server中去做
+	if accountReserveTime > -1 {
+		return uint(accountReserveTime)
+	}
+
+	if policy != nil {
+		return policy.RetentionHours
+	}
+
+	return defaultTime
+}
+
 func (svc *recycleRecordSvc) checkAndGetAccount(kt *kit.Kit, info protodao.RecycleResourceInfo) (
 	*types.ListAccountDetails, error) {
 
@@ -265,6 +302,7 @@ func (svc *recycleRecordSvc) ListRecycleRecord(cts *rest.Contexts) (interface{},
 				Region:      recycleRecord.Region,
 				Status:      enumor.RecycleRecordStatus(recycleRecord.Status),
 				RecycledAt:  times.ConvStdTimeFormat(recycleRecord.RecycledAt),
+				Notified:    converter.PtrToVal(recycleRecord.Notified),
 				Revision: core.Revision{
 					Creator:   recycleRecord.Creator,
 					Reviser:   recycleRecord.Reviser,
@@ -321,6 +359,7 @@ func (svc *recycleRecordSvc) BatchUpdateRecycleRecord(cts *rest.Contexts) (inter
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		for _, updateReq := range req.Data {
 			record.Status = string(updateReq.Status)
+			record.Notified = updateReq.Notified
 
 			if updateReq.Detail != nil {
 				updatedDetail, err := json.UpdateMerge(updateReq.Detail, string(detailMap[updateReq.ID]))
@@ -0,0 +1,356 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package secret provides re-encryption of stored account secrets and application contents after encryption key
+// rotation.
+package secret
+
+import (
+	rawjson "encoding/json"
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/cryptography"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaccountset "hcm/pkg/dal/table/account-set"
+	tableapplication "hcm/pkg/dal/table/application"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+)
+
+// secretFields 账号扩展字段中加密存储的字段
+var secretFields = []string{
+	"cloud_secret_key",
+	"cloud_service_secret_key",
+	"cloud_client_secret_key",
+	"cloud_init_password",
+}
+
+// applicationSecretFields 申请单内容中加密存储的字段，新增账号申请的密钥在扩展字段中，创建虚拟机申请的密码在顶层字段中
+var applicationSecretFields = map[enumor.ApplicationType][]string{
+	enumor.CreateCvm: {"password", "confirmed_password"},
+}
+
+// ReEncryptor re-encrypt account, root account and main account secrets and the secrets in application contents with
+// the current key of keyring.
+type ReEncryptor struct {
+	dao     dao.Set
+	rotator cryptography.Rotator
+}
+
+// NewReEncryptor new re-encryptor, cipher must support key rotation.
+func NewReEncryptor(dao dao.Set, cipher cryptography.Crypto) (*ReEncryptor, error) {
+	rotator, ok := cipher.(cryptography.Rotator)
+	if !ok {
+		return nil, errors.New("crypto keyring is not configured, re-encrypt is not supported")
+	}
+
+	return &ReEncryptor{dao: dao, rotator: rotator}, nil
+}
+
+// ReEncryptResult result of re-encrypt.
+type ReEncryptResult struct {
+	DryRun bool                    `json:"dry_run"`
+	Tables []*TableReEncryptResult `json:"tables"`
+}
+
+// TableReEncryptResult re-encrypt result of one table.
+type TableReEncryptResult struct {
+	Table string `json:"table"`
+	Total uint   `json:"total"`
+	// ReEncrypted 需要(dry_run时)或已经重新加密的记录数
+	ReEncrypted uint     `json:"re_encrypted"`
+	FailedIDs   []string `json:"failed_ids,omitempty"`
+}
+
+// extensionRecord id and extension of a record with secrets, extension is the content for application.
+type extensionRecord struct {
+	ID        string
+	Extension tabletype.JsonField
+	// Type application type, empty for accounts.
+	Type enumor.ApplicationType
+}
+
+// extensionTable operations to re-encrypt secrets in extension of a table.
+type extensionTable struct {
+	name      string
+	list      func(kt *kit.Kit, page *core.BasePage) ([]extensionRecord, error)
+	reEncrypt func(record extensionRecord) (tabletype.JsonField, bool, error)
+	update    func(kt *kit.Kit, id string, extension tabletype.JsonField) error
+}
+
+// ReEncrypt re-encrypt all secrets that are not encrypted by the current key, only count them if dryRun.
+func (r *ReEncryptor) ReEncrypt(kt *kit.Kit, dryRun bool) (interface{}, error) {
+	if len(kt.User) == 0 {
+		kt.User = constant.BackendOperationUserKey
+	}
+
+	result := &ReEncryptResult{DryRun: dryRun}
+	for _, table := range r.tables() {
+		tableResult, err := r.reEncryptTable(kt, table, dryRun)
+		if err != nil {
+			logs.Errorf("re-encrypt %s secrets failed, err: %v, rid: %s", table.name, err, kt.Rid)
+			return nil, err
+		}
+		result.Tables = append(result.Tables, tableResult)
+	}
+
+	return result, nil
+}
+
+func (r *ReEncryptor) reEncryptTable(kt *kit.Kit, table extensionTable, dryRun bool) (*TableReEncryptResult,
+	error) {
+
+	result := &TableReEncryptResult{Table: table.name}
+	page := &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id"}
+	for {
+		records, err := table.list(kt, page)
+		if err != nil {
+			return nil, fmt.Errorf("list %s failed, err: %v", table.name, err)
+		}
+
+		for _, record := range records {
+			result.Total++
+
+			extension, changed, err := table.reEncrypt(record)
+			if err != nil {
+				logs.Errorf("re-encrypt %s(%s) secret failed, err: %v, rid: %s", table.name, record.ID, err, kt.Rid)
+				result.FailedIDs = append(result.FailedIDs, record.ID)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			if !dryRun {
+				if err = table.update(kt, record.ID, extension); err != nil {
+					logs.Errorf("update %s(%s) secret failed, err: %v, rid: %s", table.name, record.ID, err, kt.Rid)
+					result.FailedIDs = append(result.FailedIDs, record.ID)
+					continue
+				}
+			}
+			result.ReEncrypted++
+		}
+
+		if uint(len(records)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	logs.Infof("re-encrypt %s secrets done, dry run: %v, total: %d, re-encrypted: %d, failed: %d, rid: %s",
+		table.name, dryRun, result.Total, result.ReEncrypted, len(result.FailedIDs), kt.Rid)
+
+	return result, nil
+}
+
+// reEncryptExtension re-encrypt secret fields in extension, other fields are kept as they are.
+func (r *ReEncryptor) reEncryptExtension(record extensionRecord) (tabletype.JsonField, bool, error) {
+	return r.reEncryptJson(record.Extension, secretFields)
+}
+
+// reEncryptApplication re-encrypt secrets in application content, the secret key of add account application is in
+// extension, and gcp cvm public key is not encrypted.
+func (r *ReEncryptor) reEncryptApplication(record extensionRecord) (tabletype.JsonField, bool, error) {
+	if len(record.Extension) == 0 {
+		return record.Extension, false, nil
+	}
+
+	if record.Type == enumor.AddAccount {
+		content := make(map[string]rawjson.RawMessage)
+		if err := json.UnmarshalFromString(string(record.Extension), &content); err != nil {
+			return "", false, fmt.Errorf("unmarshal application content failed, err: %v", err)
+		}
+
+		extension, changed, err := r.reEncryptJson(tabletype.JsonField(content["extension"]), secretFields)
+		if err != nil || !changed {
+			return record.Extension, false, err
+		}
+		content["extension"] = rawjson.RawMessage(extension)
+
+		updated, err := json.MarshalToString(content)
+		if err != nil {
+			return "", false, fmt.Errorf("marshal application content failed, err: %v", err)
+		}
+		return tabletype.JsonField(updated), true, nil
+	}
+
+	content := struct {
+		Vendor enumor.Vendor `json:"vendor"`
+	}{}
+	if err := json.UnmarshalFromString(string(record.Extension), &content); err != nil {
+		return "", false, fmt.Errorf("unmarshal application content failed, err: %v", err)
+	}
+	if content.Vendor == enumor.Gcp {
+		return record.Extension, false, nil
+	}
+
+	return r.reEncryptJson(record.Extension, applicationSecretFields[record.Type])
+}
+
+// reEncryptJson re-encrypt secret fields in json object, other fields are kept as they are.
+func (r *ReEncryptor) reEncryptJson(object tabletype.JsonField, names []string) (tabletype.JsonField, bool, error) {
+	if len(object) == 0 || len(names) == 0 {
+		return object, false, nil
+	}
+
+	fields := make(map[string]rawjson.RawMessage)
+	if err := json.UnmarshalFromString(string(object), &fields); err != nil {
+		return "", false, fmt.Errorf("unmarshal extension failed, err: %v", err)
+	}
+
+	changed := false
+	for _, name := range names {
+		raw, exists := fields[name]
+		if !exists {
+			continue
+		}
+
+		var encrypted string
+		// 非字符串类型（如null）的字段不是加密数据，直接跳过
+		if err := json.Unmarshal(raw, &encrypted); err != nil || len(encrypted) == 0 {
+			continue
+		}
+
+		reEncrypted, fieldChanged, err := r.rotator.ReEncryptFromBase64(encrypted)
+		if err != nil {
+			return "", false, fmt.Errorf("re-encrypt field %s failed, err: %v", name, err)
+		}
+		if !fieldChanged {
+			continue
+		}
+
+		value, err := json.Marshal(reEncrypted)
+		if err != nil {
+			return "", false, err
+		}
+		fields[name] = value
+		changed = true
+	}
+
+	if !changed {
+		return object, false, nil
+	}
+
+	updated, err := json.MarshalToString(fields)
+	if err != nil {
+		return "", false, fmt.Errorf("marshal extension failed, err: %v", err)
+	}
+	return tabletype.JsonField(updated), true, nil
+}
+
+func (r *ReEncryptor) tables() []extensionTable {
+	fields := []string{"id", "extension"}
+	return []extensionTable{
+		{
+			name: "account",
+			list: func(kt *kit.Kit, page *core.BasePage) ([]extensionRecord, error) {
+				opt := &types.ListOption{Fields: fields, Filter: tools.AllExpression(), Page: page}
+				result, err := r.dao.Account().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]extensionRecord, 0, len(result.Details))
+				for _, one := range result.Details {
+					records = append(records, extensionRecord{ID: one.ID, Extension: one.Extension})
+				}
+				return records, nil
+			},
+			reEncrypt: r.reEncryptExtension,
+			update: func(kt *kit.Kit, id string, extension tabletype.JsonField) error {
+				model := &tablecloud.AccountTable{Extension: extension, Reviser: kt.User}
+				return r.dao.Account().Update(kt, tools.EqualExpression("id", id), model)
+			},
+		},
+		{
+			name: "root_account",
+			list: func(kt *kit.Kit, page *core.BasePage) ([]extensionRecord, error) {
+				opt := &types.ListOption{Fields: fields, Filter: tools.AllExpression(), Page: page}
+				result, err := r.dao.RootAccount().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]extensionRecord, 0, len(result.Details))
+				for _, one := range result.Details {
+					records = append(records, extensionRecord{ID: one.ID, Extension: one.Extension})
+				}
+				return records, nil
+			},
+			reEncrypt: r.reEncryptExtension,
+			update: func(kt *kit.Kit, id string, extension tabletype.JsonField) error {
+				model := &tableaccountset.RootAccountTable{Extension: extension, Reviser: kt.User}
+				return r.dao.RootAccount().Update(kt, tools.EqualExpression("id", id), model)
+			},
+		},
+		{
+			name: "main_account",
+			list: func(kt *kit.Kit, page *core.BasePage) ([]extensionRecord, error) {
+				opt := &types.ListOption{Fields: fields, Filter: tools.AllExpression(), Page: page}
+				result, err := r.dao.MainAccount().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]extensionRecord, 0, len(result.Details))
+				for _, one := range result.Details {
+					records = append(records, extensionRecord{ID: one.ID, Extension: one.Extension})
+				}
+				return records, nil
+			},
+			reEncrypt: r.reEncryptExtension,
+			update: func(kt *kit.Kit, id string, extension tabletype.JsonField) error {
+				model := &tableaccountset.MainAccountTable{Extension: extension, Reviser: kt.User}
+				return r.dao.MainAccount().Update(kt, tools.EqualExpression("id", id), model)
+			},
+		},
+		{
+			name: "application",
+			list: func(kt *kit.Kit, page *core.BasePage) ([]extensionRecord, error) {
+				opt := &types.ListOption{
+					Fields: []string{"id", "type", "content"},
+					Filter: tools.ContainersExpression("type",
+						[]enumor.ApplicationType{enumor.AddAccount, enumor.CreateCvm}),
+					Page: page,
+				}
+				result, err := r.dao.Application().List(kt, opt)
+				if err != nil {
+					return nil, err
+				}
+				records := make([]extensionRecord, 0, len(result.Details))
+				for _, one := range result.Details {
+					records = append(records, extensionRecord{ID: one.ID, Extension: one.Content,
+						Type: enumor.ApplicationType(one.Type)})
+				}
+				return records, nil
+			},
+			reEncrypt: r.reEncryptApplication,
+			update: func(kt *kit.Kit, id string, content tabletype.JsonField) error {
+				model := &tableapplication.ApplicationTable{Content: content, Reviser: kt.User}
+				return r.dao.Application().Update(kt, tools.EqualExpression("id", id), model)
+			},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package secret
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/cryptography"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReEncryptApplication(t *testing.T) {
+	oldRing, err := cryptography.NewKeyring(1, map[uint][]byte{1: []byte("1111111111111111")}, nil)
+	assert.NoError(t, err)
+	newRing, err := cryptography.NewKeyring(2, map[uint][]byte{1: []byte("1111111111111111"),
+		2: []byte("2222222222222222")}, nil)
+	assert.NoError(t, err)
+	r := &ReEncryptor{rotator: newRing}
+
+	decrypt := func(content tabletype.JsonField, path ...string) string {
+		fields := make(map[string]interface{})
+		assert.NoError(t, json.UnmarshalFromString(string(content), &fields))
+		for _, one := range path[:len(path)-1] {
+			fields = fields[one].(map[string]interface{})
+		}
+		plaintext, err := newRing.DecryptFromBase64(fields[path[len(path)-1]].(string))
+		assert.NoError(t, err)
+		return plaintext
+	}
+
+	// 新增账号申请的密钥在扩展字段中
+	content := `{"vendor":"tcloud","name":"a","extension":{"cloud_secret_id":"id","cloud_secret_key":"` +
+		oldRing.EncryptToBase64("secret") + `"}}`
+	updated, changed, err := r.reEncryptApplication(extensionRecord{Extension: tabletype.JsonField(content),
+		Type: enumor.AddAccount})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, string(updated), `"v2$`)
+	assert.Contains(t, string(updated), `"cloud_secret_id":"id"`)
+	assert.Equal(t, "secret", decrypt(updated, "extension", "cloud_secret_key"))
+
+	// 已经使用当前密钥加密的内容不再更新
+	_, changed, err = r.reEncryptApplication(extensionRecord{Extension: updated, Type: enumor.AddAccount})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// 创建虚拟机申请的密码在顶层字段中
+	password := oldRing.EncryptToBase64("password")
+	content = `{"vendor":"aws","password":"` + password + `","confirmed_password":"` + password + `"}`
+	updated, changed, err = r.reEncryptApplication(extensionRecord{Extension: tabletype.JsonField(content),
+		Type: enumor.CreateCvm})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "password", decrypt(updated, "password"))
+	assert.Equal(t, "password", decrypt(updated, "confirmed_password"))
+
+	// gcp 主机公钥未加密，不需要重新加密
+	content = `{"vendor":"gcp","password":"ssh-rsa AAAA"}`
+	updated, changed, err = r.reEncryptApplication(extensionRecord{Extension: tabletype.JsonField(content),
+		Type: enumor.CreateCvm})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, content, string(updated))
+}
@@ -32,8 +32,10 @@ import (
 	rootaccount "hcm/cmd/data-service/service/account-set/root-account"
 	"hcm/cmd/data-service/service/application"
 	"hcm/cmd/data-service/service/audit"
+	auditchain "hcm/cmd/data-service/service/audit/chain"
 	"hcm/cmd/data-service/service/auth"
 	"hcm/cmd/data-service/service/bill/billadjustmentitem"
+	"hcm/cmd/data-service/service/bill/billallocation"
 	"hcm/cmd/data-service/service/bill/billdailytask"
 	"hcm/cmd/data-service/service/bill/billexchangerate"
 	"hcm/cmd/data-service/service/bill/billitem"
@@ -56,8 +58,10 @@ import (
 	"hcm/cmd/data-service/service/cloud/cvm"
 	"hcm/cmd/data-service/service/cloud/disk"
 	diskcvmrel "hcm/cmd/data-service/service/cloud/disk-cvm-rel"
+	disksnapshot "hcm/cmd/data-service/service/cloud/disk-snapshot"
 	"hcm/cmd/data-service/service/cloud/eip"
 	eipcvmrel "hcm/cmd/data-service/service/cloud/eip-cvm-rel"
+	idleresource "hcm/cmd/data-service/service/cloud/idle-resource"
 	"hcm/cmd/data-service/service/cloud/image"
 	loadbalancer "hcm/cmd/data-service/service/cloud/load-balancer"
 	networkinterface "hcm/cmd/data-service/service/cloud/network-interface"
@@ -69,12 +73,14 @@ import (
 	securitygroup "hcm/cmd/data-service/service/cloud/security-group"
 	sgcomrel "hcm/cmd/data-service/service/cloud/security-group-common-rel"
 	sgcvmrel "hcm/cmd/data-service/service/cloud/security-group-cvm-rel"
+	sgcompliance "hcm/cmd/data-service/service/cloud/sg-compliance"
 	subaccount "hcm/cmd/data-service/service/cloud/sub-account"
 	sync "hcm/cmd/data-service/service/cloud/sync"
 	"hcm/cmd/data-service/service/cloud/zone"
 	"hcm/cmd/data-service/service/cos"
 	globalconfig "hcm/cmd/data-service/service/global-config"
 	recyclerecord "hcm/cmd/data-service/service/recycle-record"
+	"hcm/cmd/data-service/service/secret"
 	"hcm/cmd/data-service/service/task"
 	"hcm/cmd/data-service/service/tenant"
 	"hcm/cmd/data-service/service/user"
@@ -84,9 +90,11 @@ import (
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/objectstore"
 	"hcm/pkg/handler"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/ctl/cmd"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
 	"hcm/pkg/thirdparty/api-gateway/cmdb"
@@ -138,11 +146,46 @@ func NewService() (*Service, error) {
 	return svr, nil
 }
 
+// ReEncryptSecretCmd returns the control tool command to re-encrypt stored secrets with the current key.
+func (s *Service) ReEncryptSecretCmd() cmd.Cmd {
+	return cmd.WithReEncryptSecret(func(kt *kit.Kit, dryRun bool) (interface{}, error) {
+		reEncryptor, err := secret.NewReEncryptor(s.dao, s.cipher)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		return reEncryptor.ReEncrypt(kt, dryRun)
+	})
+}
+
+// VerifyAuditChainCmd returns the control tool command to verify the audit hash chain of tenant.
+func (s *Service) VerifyAuditChainCmd() cmd.Cmd {
+	return cmd.WithVerifyAuditChain(func(kt *kit.Kit, tenantID string, startSeq, endSeq uint64) (interface{}, error) {
+		if endSeq != 0 && endSeq < startSeq {
+			return nil, errf.New(errf.InvalidParameter, "end_seq should >= start_seq")
+		}
+		return auditchain.Verify(kt, s.dao, tenantID, startSeq, endSeq)
+	})
+}
+
+// StartAuditExport start exporting audits to the external sink if enabled, only the master node exports audits.
+func (s *Service) StartAuditExport(state serviced.State) error {
+	conf := cc.DataService().AuditExport
+	if !conf.Enable {
+		return nil
+	}
+
+	exporter, err := auditchain.NewExporter(s.dao, conf)
+	if err != nil {
+		return err
+	}
+
+	go exporter.Run(state)
+	return nil
+}
+
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
-	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
-	cfg := cryptoConfig.AesGcm
-	return cryptography.NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	return cryptography.NewFromConfig(cryptoConfig)
 }
 
 // ListenAndServeRest listen and serve the restful server
@@ -219,11 +262,13 @@ func (s *Service) apiSet() *restful.Container {
 	accountbizrel.InitService(capability)
 	securitygroup.InitSecurityGroupService(capability)
 	securitygroup.InitGcpFirewallRuleService(capability)
+	sgcompliance.InitService(capability)
 	cloud.InitVpcService(capability)
 	cloud.InitSubnetService(capability)
 	cloud.InitCloudService(capability)
 	auth.InitAuthService(capability)
 	disk.InitService(capability)
+	disksnapshot.InitService(capability)
 	region.InitRegionService(capability)
 	resourcegroup.InitAzureResourceGroupService(capability)
 	audit.InitAuditService(capability)
@@ -235,8 +280,10 @@ func (s *Service) apiSet() *restful.Container {
 	routetable.InitRouteTableService(capability)
 	application.InitApplicationService(capability)
 	application.InitApprovalProcessService(capability)
+	application.InitApprovalService(capability)
 	diskcvmrel.InitService(capability)
 	eipcvmrel.InitService(capability)
+	idleresource.InitService(capability)
 	networkinterface.InitNetInterfaceService(capability)
 	networkcvmrel.InitService(capability)
 	recyclerecord.InitRecycleRecordService(capability)
@@ -270,6 +317,7 @@ func (s *Service) apiSet() *restful.Container {
 	sgcomrel.InitService(capability)
 
 	billexchangerate.InitService(capability)
+	billallocation.InitService(capability)
 	billsyncrecord.InitService(capability)
 	globalconfig.InitService(capability)
 
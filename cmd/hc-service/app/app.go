@@ -24,11 +24,13 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"hcm/cmd/hc-service/options"
 	"hcm/cmd/hc-service/service"
 	adptmetric "hcm/pkg/adaptor/metric"
 	mocktcloud "hcm/pkg/adaptor/mock/tcloud"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/cc"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
@@ -78,6 +80,14 @@ func (ds *hcService) prepare(opt *options.Option) error {
 	metrics.InitMetrics(net.JoinHostPort(network.BindIP, strconv.Itoa(int(network.Port))))
 	adptmetric.InitCloudApiMetrics(metrics.Register())
 
+	// init cloud api rate limiter
+	limitCfg := cc.HCService().CloudApiLimit
+	ratelimit.Init(ratelimit.Option{
+		Rule:            limitCfg,
+		MinQPS:          limitCfg.MinQPS,
+		RecoverInterval: time.Duration(limitCfg.RecoverIntervalSec) * time.Second,
+	})
+
 	// register hc service.
 	svcOpt := serviced.NewServiceOption(cc.HCServiceName, cc.HCService().Network, opt.Sys)
 	disOpt := serviced.DiscoveryOption{
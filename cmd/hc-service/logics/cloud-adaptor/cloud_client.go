@@ -136,6 +136,22 @@ func (cli *CloudAdaptorClient) GcpRoot(kt *kit.Kit, accountID string) (*gcp.Gcp,
 	return cli.adaptor.Gcp(cred)
 }
 
+// TCloudRoot return tcloud client of root account.
+func (cli *CloudAdaptorClient) TCloudRoot(kt *kit.Kit, accountID string) (tcloud.TCloud, error) {
+	secret, err := cli.secretCli.TCloudRootSecret(kt, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cli.adaptor.TCloud(secret)
+	if err != nil {
+		return nil, err
+	}
+	client.SetRateLimitRetryWithRandomInterval(kt.RequestSource == enumor.AsynchronousTasks)
+
+	return client, nil
+}
+
 // HuaWeiRoot return huawei client.
 func (cli *CloudAdaptorClient) HuaWeiRoot(kt *kit.Kit, accountID string) (*huawei.HuaWei, error) {
 	secret, err := cli.secretCli.HuaWeiRootSecret(kt, accountID)
@@ -53,6 +53,7 @@ func (cli *SecretClient) TCloudSecret(kt *kit.Kit, accountID string) (*types.Bas
 	secret := &types.BaseSecret{
 		CloudSecretID:  account.Extension.CloudSecretID,
 		CloudSecretKey: account.Extension.CloudSecretKey,
+		CloudAccountID: account.Extension.CloudMainAccountID,
 	}
 
 	if err := secret.Validate(); err != nil {
@@ -100,6 +101,7 @@ func (cli *SecretClient) HuaWeiSecret(kt *kit.Kit, accountID string) (*types.Bas
 	secret := &types.BaseSecret{
 		CloudSecretID:  account.Extension.CloudSecretID,
 		CloudSecretKey: account.Extension.CloudSecretKey,
+		CloudAccountID: account.Extension.CloudSubAccountID,
 	}
 
 	if err := secret.Validate(); err != nil {
@@ -242,6 +244,31 @@ func (cli *SecretClient) HuaWeiRootSecret(kt *kit.Kit, accountID string) (*types
 	secret := &types.BaseSecret{
 		CloudSecretID:  account.Extension.CloudSecretID,
 		CloudSecretKey: account.Extension.CloudSecretKey,
+		CloudAccountID: account.Extension.CloudSubAccountID,
+	}
+
+	if err := secret.Validate(); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// TCloudRootSecret get tcloud root account secret and validate secret.
+func (cli *SecretClient) TCloudRootSecret(kt *kit.Kit, accountID string) (*types.BaseSecret, error) {
+	account, err := cli.data.TCloud.RootAccount.Get(kt, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("get tcloud root account failed, err: %v", err)
+	}
+
+	if account.Extension == nil {
+		return nil, errors.New("tcloud root account extension is nil")
+	}
+
+	secret := &types.BaseSecret{
+		CloudSecretID:  account.Extension.CloudSecretID,
+		CloudSecretKey: account.Extension.CloudSecretKey,
+		CloudAccountID: account.Extension.CloudMainAccountID,
 	}
 
 	if err := secret.Validate(); err != nil {
@@ -77,7 +77,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.AwsCvm, corecvm.Cvm[cvm.AwsCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -348,6 +348,10 @@ func parseIPInfo(one typescvm.AwsCvm) (privateIPv4Addresses, publicIPv4Addresses
 
 // deleteCvm ...
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -70,7 +70,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AwsImage, coreimage.Image[coreimage.AwsExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -167,6 +167,10 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 
 // deleteImage deletes images from the database after validating that they do not exist in the cloud.
 func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
@@ -0,0 +1,482 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/adaptor/aws"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+// SyncLBOption ...
+type SyncLBOption struct {
+}
+
+// Validate ...
+func (o *SyncLBOption) Validate() error {
+	return validator.Validate.Struct(o)
+}
+
+// LoadBalancerWithListener 同步指定负载均衡及下属监听器、目标组
+// 1. 同步该负载均衡自身属性
+// 2. 同步该负载均衡下的监听器
+// 3. 同步该负载均衡关联的目标组
+func (cli *client) LoadBalancerWithListener(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult,
+	error) {
+
+	if _, err := cli.LoadBalancer(kt, params, opt); err != nil {
+		logs.Errorf("fail to sync aws load balancer with rel, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	lbList, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		logs.Errorf("fail to get lb from db after lb layer sync, before listener sync, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	for _, lb := range lbList {
+		if err = cli.listener(kt, params.AccountID, params.Region, lb); err != nil {
+			logs.Errorf("fail to sync listener of lb(%s), err: %v, rid: %s", lb.CloudID, err, kt.Rid)
+			return nil, err
+		}
+		if err = cli.targetGroup(kt, params.AccountID, params.Region, lb); err != nil {
+			logs.Errorf("fail to sync target group of lb(%s), err: %v, rid: %s", lb.CloudID, err, kt.Rid)
+			return nil, err
+		}
+	}
+
+	return new(SyncResult), nil
+}
+
+// LoadBalancer 同步指定负载均衡自身属性，不同步关联资源
+func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLBOption) (*SyncResult, error) {
+	if err := validator.ValidateTool(params, opt); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbFromCloud, err := cli.listLBFromCloud(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	lbFromDB, err := cli.listLBFromDB(kt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lbFromCloud) == 0 && len(lbFromDB) == 0 {
+		return new(SyncResult), nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.AwsLoadBalancer, corelb.AwsLoadBalancer](
+		kt, lbFromCloud, lbFromDB, isLBChange)
+
+	if len(delCloudIDs) != 0 {
+		if err = cli.deleteLoadBalancer(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(addSlice) != 0 {
+		if err = cli.createLoadBalancer(kt, params.AccountID, params.Region, addSlice); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(updateMap) != 0 {
+		if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
+			return nil, err
+		}
+	}
+
+	return new(SyncResult), nil
+}
+
+// RemoveLoadBalancerDeleteFromCloud 删除存在本地但是在云上被删除的数据
+func (cli *client) RemoveLoadBalancerDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+		),
+		Page: &core.BasePage{Start: 0, Limit: constant.BatchOperationMaxLimit},
+	}
+
+	for {
+		lbFromDB, err := cli.dbCli.Global.LoadBalancer.ListLoadBalancer(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] request dataservice to list lb failed, err: %v, req: %v, rid: %s", enumor.Aws,
+				err, req, kt.Rid)
+			return err
+		}
+
+		cloudIDs := slice.Map(lbFromDB.Details, func(lb corelb.BaseLoadBalancer) string { return lb.CloudID })
+		if len(cloudIDs) == 0 {
+			break
+		}
+
+		params := &SyncBaseParams{AccountID: accountID, Region: region, CloudIDs: cloudIDs}
+		delCloudIDs, err := cli.listRemovedLBID(kt, params)
+		if err != nil {
+			return err
+		}
+
+		if len(delCloudIDs) != 0 {
+			if err = cli.deleteLoadBalancer(kt, accountID, region, delCloudIDs); err != nil {
+				return err
+			}
+		}
+
+		if len(lbFromDB.Details) < constant.BatchOperationMaxLimit {
+			break
+		}
+
+		req.Page.Start += constant.BatchOperationMaxLimit
+	}
+
+	return nil
+}
+
+// listRemovedLBID check lb exists, return its id if one can not be found
+func (cli *client) listRemovedLBID(kt *kit.Kit, params *SyncBaseParams) ([]string, error) {
+	if err := params.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbMap := cvt.StringSliceToMap(params.CloudIDs)
+	found, err := cli.listLBFromCloud(kt, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range found {
+		delete(lbMap, lb.GetCloudID())
+	}
+
+	return cvt.MapKeyToSlice(lbMap), nil
+}
+
+// createLoadBalancer call data service to create lb
+func (cli *client) createLoadBalancer(kt *kit.Kit, accountID string, region string,
+	addSlice []typeslb.AwsLoadBalancer) error {
+
+	cloudVpcIDs := slice.Map(addSlice, func(lb typeslb.AwsLoadBalancer) string { return cvt.PtrToVal(lb.VpcId) })
+	vpcMap, err := cli.getVpcMap(kt, accountID, region, cloudVpcIDs)
+	if err != nil {
+		logs.Errorf("fail to get vpc of load balancer during syncing, err: %v, account: %s, vpcIDs: %v, rid: %s",
+			err, accountID, cloudVpcIDs, kt.Rid)
+		return err
+	}
+
+	var lbCreateReq protocloud.AwsLBCreateReq
+	for _, one := range addSlice {
+		lbCreateReq.Lbs = append(lbCreateReq.Lbs, convLBCloudToDBCreate(one, accountID, region, vpcMap))
+	}
+
+	if _, err = cli.dbCli.Aws.LoadBalancer.BatchCreateAwsLoadBalancer(kt, &lbCreateReq); err != nil {
+		logs.Errorf("[%s] call data service to create aws load balancer failed, err: %v, rid: %s",
+			enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to create lb success, accountID: %s, count: %d, rid: %s",
+		enumor.Aws, accountID, len(addSlice), kt.Rid)
+
+	return nil
+}
+
+// updateLoadBalancer call data service to update lb
+func (cli *client) updateLoadBalancer(kt *kit.Kit, accountID string, region string,
+	updateMap map[string]typeslb.AwsLoadBalancer) error {
+
+	cloudVpcIDs := make([]string, 0, len(updateMap))
+	for _, lb := range updateMap {
+		cloudVpcIDs = append(cloudVpcIDs, cvt.PtrToVal(lb.VpcId))
+	}
+	vpcMap, err := cli.getVpcMap(kt, accountID, region, cloudVpcIDs)
+	if err != nil {
+		logs.Errorf("fail to get vpc of load balancer during syncing, err: %v, account: %s, vpcIDs: %v, rid: %s",
+			err, accountID, cloudVpcIDs, kt.Rid)
+		return err
+	}
+
+	var updateReq protocloud.AwsLbBatchUpdateReq
+	for id, lb := range updateMap {
+		updateReq.Lbs = append(updateReq.Lbs, convLBCloudToDBUpdate(id, lb, vpcMap))
+	}
+
+	if err = cli.dbCli.Aws.LoadBalancer.BatchUpdate(kt, &updateReq); err != nil {
+		logs.Errorf("[%s] call data service to update aws load balancer failed, err: %v, rid: %s",
+			enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to update lb success, accountID: %s, count: %d, rid: %s",
+		enumor.Aws, accountID, len(updateMap), kt.Rid)
+
+	return nil
+}
+
+// deleteLoadBalancer call data service to delete lb
+func (cli *client) deleteLoadBalancer(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	checkParams := &SyncBaseParams{
+		AccountID: accountID,
+		Region:    region,
+		CloudIDs:  delCloudIDs,
+	}
+	delLBFromCloud, err := cli.listLBFromCloud(kt, checkParams)
+	if err != nil {
+		return err
+	}
+
+	if len(delLBFromCloud) > 0 {
+		logs.Errorf("[%s] validate lb not exist failed, before delete, opt: %v, failed_count: %d, rid: %s",
+			enumor.Aws, checkParams, len(delLBFromCloud), kt.Rid)
+		return fmt.Errorf("validate lb not exist failed, before delete")
+	}
+
+	deleteReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", delCloudIDs),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("vendor", enumor.Aws),
+		),
+	}
+	if err = cli.dbCli.Global.LoadBalancer.BatchDelete(kt, deleteReq); err != nil {
+		logs.Errorf("[%s] call data service to batch delete lb failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync load balancer to delete lb success, accountID: %s, count: %d, rid: %s",
+		enumor.Aws, accountID, len(delCloudIDs), kt.Rid)
+
+	return nil
+}
+
+// listLBFromCloud list load balancer from cloud vendor, load balancers that can not be found will be ignored.
+func (cli *client) listLBFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]typeslb.AwsLoadBalancer, error) {
+	result := make([]typeslb.AwsLoadBalancer, 0, len(params.CloudIDs))
+	for _, cloudIDs := range slice.Split(params.CloudIDs, typeslb.AwsElbDescribeMax) {
+		opt := &typeslb.AwsListOption{Region: params.Region, CloudIDs: cloudIDs}
+		batch, _, err := cli.cloudCli.ListLoadBalancer(kt, opt)
+		if err == nil {
+			result = append(result, batch...)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), aws.ErrElbNotFound) {
+			logs.Errorf("[%s] list lb from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
+				err, params.AccountID, opt, kt.Rid)
+			return nil, err
+		}
+
+		// 批量查询时只要有一个不存在，aws就会报错，此时逐个查询以过滤掉已删除的负载均衡
+		for _, cloudID := range cloudIDs {
+			opt := &typeslb.AwsListOption{Region: params.Region, CloudIDs: []string{cloudID}}
+			one, _, err := cli.cloudCli.ListLoadBalancer(kt, opt)
+			if err != nil {
+				if strings.Contains(err.Error(), aws.ErrElbNotFound) {
+					continue
+				}
+				logs.Errorf("[%s] list lb from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
+					err, params.AccountID, opt, kt.Rid)
+				return nil, err
+			}
+			result = append(result, one...)
+		}
+	}
+
+	return result, nil
+}
+
+// listLBFromDB list load balancer from database
+func (cli *client) listLBFromDB(kt *kit.Kit, params *SyncBaseParams) ([]corelb.AwsLoadBalancer, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("account_id", params.AccountID),
+			tools.RuleEqual("region", params.Region),
+			tools.RuleIn("cloud_id", params.CloudIDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := cli.dbCli.Aws.LoadBalancer.ListLoadBalancer(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] list lb from db failed, err: %v, account: %s, req: %v, rid: %s", enumor.Aws, err,
+			params.AccountID, req, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+func convLBCloudToDBCreate(cloud typeslb.AwsLoadBalancer, accountID string, region string,
+	vpcMap map[string]*common.VpcDB) protocloud.LbBatchCreate[corelb.AwsLoadBalancerExtension] {
+
+	cloudVpcID := cvt.PtrToVal(cloud.VpcId)
+	privateIPv4, publicIPv4, ipv6 := cloud.GetAddresses()
+	lb := protocloud.LbBatchCreate[corelb.AwsLoadBalancerExtension]{
+		CloudID:              cloud.GetCloudID(),
+		Name:                 cloud.GetName(),
+		Vendor:               enumor.Aws,
+		AccountID:            accountID,
+		BkBizID:              constant.UnassignedBiz,
+		LoadBalancerType:     string(cloud.GetScheme().ToTCloudType()),
+		IPVersion:            cloud.GetIPVersion(),
+		Region:               region,
+		Zones:                cloud.GetZones(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloudVpcID]).VpcID,
+		CloudVpcID:           cloudVpcID,
+		PrivateIPv4Addresses: privateIPv4,
+		PublicIPv4Addresses:  publicIPv4,
+		PublicIPv6Addresses:  ipv6,
+		Domain:               cvt.PtrToVal(cloud.DNSName),
+		Status:               cloud.GetStatus(),
+		Tags:                 cloud.GetTagMap(),
+		// 备注字段云上没有
+		Memo:      nil,
+		SyncTime:  times.ConvStdTimeFormat(time.Now()),
+		Extension: convLBExtension(cloud),
+	}
+	if cloud.CreatedTime != nil {
+		lb.CloudCreatedTime = times.ConvStdTimeFormat(*cloud.CreatedTime)
+	}
+
+	return lb
+}
+
+func convLBCloudToDBUpdate(id string, cloud typeslb.AwsLoadBalancer,
+	vpcMap map[string]*common.VpcDB) *protocloud.LoadBalancerExtUpdateReq[corelb.AwsLoadBalancerExtension] {
+
+	cloudVpcID := cvt.PtrToVal(cloud.VpcId)
+	privateIPv4, publicIPv4, ipv6 := cloud.GetAddresses()
+	lb := &protocloud.LoadBalancerExtUpdateReq[corelb.AwsLoadBalancerExtension]{
+		ID:                   id,
+		Name:                 cloud.GetName(),
+		IPVersion:            cloud.GetIPVersion(),
+		VpcID:                cvt.PtrToVal(vpcMap[cloudVpcID]).VpcID,
+		CloudVpcID:           cloudVpcID,
+		PrivateIPv4Addresses: privateIPv4,
+		PublicIPv4Addresses:  publicIPv4,
+		PublicIPv6Addresses:  ipv6,
+		Domain:               cvt.PtrToVal(cloud.DNSName),
+		Status:               cloud.GetStatus(),
+		Tags:                 cloud.GetTagMap(),
+		SyncTime:             times.ConvStdTimeFormat(time.Now()),
+		Extension:            convLBExtension(cloud),
+	}
+	if cloud.CreatedTime != nil {
+		lb.CloudCreatedTime = times.ConvStdTimeFormat(*cloud.CreatedTime)
+	}
+
+	return lb
+}
+
+func convLBExtension(cloud typeslb.AwsLoadBalancer) *corelb.AwsLoadBalancerExtension {
+	ext := &corelb.AwsLoadBalancerExtension{
+		Type:                  cloud.Type,
+		Scheme:                cloud.Scheme,
+		DNSName:               cloud.DNSName,
+		CanonicalHostedZoneID: cloud.CanonicalHostedZoneId,
+		IpAddressType:         cloud.IpAddressType,
+		CloudSubnetIDs:        cloud.GetSubnetIDs(),
+		CloudSecurityGroupIDs: cvt.PtrToSlice(cloud.SecurityGroups),
+	}
+	if cloud.State != nil {
+		ext.StateReason = cloud.State.Reason
+	}
+
+	return ext
+}
+
+func isLBChange(cloud typeslb.AwsLoadBalancer, db corelb.AwsLoadBalancer) bool {
+	if db.Name != cloud.GetName() {
+		return true
+	}
+
+	if db.IPVersion != cloud.GetIPVersion() {
+		return true
+	}
+
+	if db.Status != cloud.GetStatus() {
+		return true
+	}
+
+	if db.Domain != cvt.PtrToVal(cloud.DNSName) {
+		return true
+	}
+
+	if db.CloudVpcID != cvt.PtrToVal(cloud.VpcId) {
+		return true
+	}
+
+	if !assert.IsStringMapEqual(db.Tags, cloud.GetTagMap()) {
+		return true
+	}
+
+	privateIPv4, publicIPv4, ipv6 := cloud.GetAddresses()
+	if !assert.IsStringSliceEqual(db.PrivateIPv4Addresses, privateIPv4) ||
+		!assert.IsStringSliceEqual(db.PublicIPv4Addresses, publicIPv4) ||
+		!assert.IsStringSliceEqual(db.PublicIPv6Addresses, ipv6) {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Zones, cloud.GetZones()) {
+		return true
+	}
+
+	if db.Extension == nil {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Extension.CloudSecurityGroupIDs, cvt.PtrToSlice(cloud.SecurityGroups)) {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Extension.CloudSubnetIDs, cloud.GetSubnetIDs()) {
+		return true
+	}
+
+	if cloud.State != nil && !assert.IsPtrStringEqual(db.Extension.StateReason, cloud.State.Reason) {
+		return true
+	}
+
+	return false
+}
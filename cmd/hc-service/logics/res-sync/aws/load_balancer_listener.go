@@ -0,0 +1,208 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+)
+
+// listener 同步负载均衡下的监听器
+func (cli *client) listener(kt *kit.Kit, accountID string, region string, lb corelb.AwsLoadBalancer) error {
+	listOpt := &typeslb.AwsListListenersOption{Region: region, LoadBalancerID: lb.CloudID}
+	lblFromCloud, err := cli.cloudCli.ListListener(kt, listOpt)
+	if err != nil {
+		logs.Errorf("[%s] list listener from cloud failed, err: %v, opt: %v, rid: %s", enumor.Aws, err, listOpt,
+			kt.Rid)
+		return err
+	}
+
+	lblFromDB, err := cli.listListenerFromDB(kt, lb.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(lblFromCloud) == 0 && len(lblFromDB) == 0 {
+		return nil
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.AwsListener, corelb.AwsListener](
+		kt, lblFromCloud, lblFromDB, isListenerChange)
+
+	if len(delCloudIDs) > 0 {
+		if err = cli.deleteListener(kt, region, delCloudIDs); err != nil {
+			return err
+		}
+	}
+
+	if len(addSlice) > 0 {
+		if err = cli.createListener(kt, accountID, region, lb, addSlice); err != nil {
+			return err
+		}
+	}
+
+	if len(updateMap) > 0 {
+		if err = cli.updateListener(kt, updateMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cli *client) listListenerFromDB(kt *kit.Kit, lbID string) ([]corelb.AwsListener, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lb_id", lbID),
+			tools.RuleEqual("vendor", enumor.Aws),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := cli.dbCli.Aws.LoadBalancer.ListListener(kt, listReq)
+	if err != nil {
+		logs.Errorf("[%s] list listener of lb(%s) from db failed, err: %v, rid: %s", enumor.Aws, lbID, err, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+func (cli *client) createListener(kt *kit.Kit, accountID string, region string, lb corelb.AwsLoadBalancer,
+	addSlice []typeslb.AwsListener) error {
+
+	listeners := make([]protocloud.ListenersCreateReq[corelb.AwsListenerExtension], 0, len(addSlice))
+	for _, one := range addSlice {
+		listeners = append(listeners, protocloud.ListenersCreateReq[corelb.AwsListenerExtension]{
+			CloudID:   one.GetCloudID(),
+			Name:      genListenerName(one),
+			Vendor:    enumor.Aws,
+			AccountID: accountID,
+			BkBizID:   lb.BkBizID,
+			LbID:      lb.ID,
+			CloudLbID: lb.CloudID,
+			Protocol:  one.GetProtocol(),
+			Port:      cvt.PtrToVal(one.Port),
+			Region:    region,
+			Extension: convListenerExtension(one),
+		})
+	}
+
+	req := &protocloud.AwsListenerBatchCreateReq{Listeners: listeners}
+	if _, err := cli.dbCli.Aws.LoadBalancer.BatchCreateAwsListener(kt, req); err != nil {
+		logs.Errorf("[%s] call data service to create aws listener failed, err: %v, rid: %s", enumor.Aws, err,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync listener to create listener success, lb: %s, count: %d, rid: %s", enumor.Aws,
+		lb.CloudID, len(addSlice), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) updateListener(kt *kit.Kit, updateMap map[string]typeslb.AwsListener) error {
+	listeners := make([]*protocloud.ListenerUpdateReq[corelb.AwsListenerExtension], 0, len(updateMap))
+	for id, one := range updateMap {
+		listeners = append(listeners, &protocloud.ListenerUpdateReq[corelb.AwsListenerExtension]{
+			ID:        id,
+			Name:      genListenerName(one),
+			Extension: convListenerExtension(one),
+		})
+	}
+
+	req := &protocloud.AwsListenerUpdateReq{Listeners: listeners}
+	if err := cli.dbCli.Aws.LoadBalancer.BatchUpdateAwsListener(kt, req); err != nil {
+		logs.Errorf("[%s] call data service to update aws listener failed, err: %v, rid: %s", enumor.Aws, err,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (cli *client) deleteListener(kt *kit.Kit, region string, cloudIDs []string) error {
+	delReq := &protocloud.LoadBalancerBatchDeleteReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("cloud_id", cloudIDs),
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("region", region),
+		),
+	}
+	if err := cli.dbCli.Global.LoadBalancer.DeleteListener(kt, delReq); err != nil {
+		logs.Errorf("[%s] fail to delete listeners(ids: %v) while sync, err: %v, rid: %s", enumor.Aws, cloudIDs,
+			err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// genListenerName aws 监听器没有名称，使用 协议:端口 作为名称
+func genListenerName(lbl typeslb.AwsListener) string {
+	return fmt.Sprintf("%s:%d", cvt.PtrToVal(lbl.Protocol), cvt.PtrToVal(lbl.Port))
+}
+
+func convListenerExtension(lbl typeslb.AwsListener) *corelb.AwsListenerExtension {
+	return &corelb.AwsListenerExtension{
+		SslPolicy:                  lbl.SslPolicy,
+		CertCloudIDs:               lbl.GetCertificateIDs(),
+		DefaultCloudTargetGroupIDs: lbl.GetDefaultTargetGroupIDs(),
+		AlpnPolicy:                 cvt.PtrToSlice(lbl.AlpnPolicy),
+	}
+}
+
+func isListenerChange(cloud typeslb.AwsListener, db corelb.AwsListener) bool {
+	if db.Name != genListenerName(cloud) {
+		return true
+	}
+
+	if db.Extension == nil {
+		return true
+	}
+
+	if !assert.IsPtrStringEqual(db.Extension.SslPolicy, cloud.SslPolicy) {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Extension.CertCloudIDs, cloud.GetCertificateIDs()) {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Extension.DefaultCloudTargetGroupIDs, cloud.GetDefaultTargetGroupIDs()) {
+		return true
+	}
+
+	if !assert.IsStringSliceEqual(db.Extension.AlpnPolicy, cvt.PtrToSlice(cloud.AlpnPolicy)) {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,378 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"strconv"
+	"strings"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/adaptor/aws"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/assert"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+)
+
+// targetGroup 同步负载均衡关联的目标组，aws目标组可以被多个负载均衡共享，
+// 因此这里只做新增和更新，云上已删除的目标组由 RemoveTargetGroupDeleteFromCloud 清理
+func (cli *client) targetGroup(kt *kit.Kit, accountID string, region string, lb corelb.AwsLoadBalancer) error {
+	tgFromCloud, err := cli.listTargetGroupFromCloudByLB(kt, region, lb.CloudID)
+	if err != nil {
+		return err
+	}
+
+	if len(tgFromCloud) == 0 {
+		return nil
+	}
+
+	cloudIDs := slice.Map(tgFromCloud, typeslb.AwsTargetGroup.GetCloudID)
+	tgFromDB, err := cli.listTargetGroupFromDB(kt, accountID, region, cloudIDs)
+	if err != nil {
+		return err
+	}
+
+	addSlice, updateMap, _ := common.Diff[typeslb.AwsTargetGroup, corelb.BaseTargetGroup](kt, tgFromCloud, tgFromDB,
+		isTargetGroupChange)
+
+	if len(addSlice) > 0 {
+		if err = cli.createTargetGroup(kt, accountID, region, lb, addSlice); err != nil {
+			return err
+		}
+	}
+
+	for id, one := range updateMap {
+		if err = cli.updateTargetGroup(kt, id, one); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveTargetGroupDeleteFromCloud 删除存在本地但是在云上被删除的目标组
+func (cli *client) RemoveTargetGroupDeleteFromCloud(kt *kit.Kit, accountID string, region string) error {
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", enumor.Aws),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("region", region),
+			tools.RuleEqual("target_group_type", enumor.CloudTargetGroupType),
+		),
+		Page: &core.BasePage{Start: 0, Limit: constant.BatchOperationMaxLimit},
+	}
+
+	for {
+		tgFromDB, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] request dataservice to list target group failed, err: %v, req: %v, rid: %s",
+				enumor.Aws, err, req, kt.Rid)
+			return err
+		}
+
+		cloudIDs := slice.Map(tgFromDB.Details, corelb.BaseTargetGroup.GetCloudID)
+		if len(cloudIDs) == 0 {
+			break
+		}
+
+		tgFromCloud, err := cli.listTargetGroupFromCloud(kt, region, cloudIDs)
+		if err != nil {
+			return err
+		}
+		existMap := cvt.StringSliceToMap(cloudIDs)
+		for _, tg := range tgFromCloud {
+			delete(existMap, tg.GetCloudID())
+		}
+
+		if delCloudIDs := cvt.MapKeyToSlice(existMap); len(delCloudIDs) > 0 {
+			delReq := &core.ListReq{
+				Filter: tools.ExpressionAnd(
+					tools.RuleEqual("vendor", enumor.Aws),
+					tools.RuleEqual("account_id", accountID),
+					tools.RuleIn("cloud_id", delCloudIDs),
+				),
+			}
+			if err = cli.dbCli.Global.LoadBalancer.DeleteTargetGroup(kt, delReq); err != nil {
+				logs.Errorf("[%s] delete target group failed, err: %v, cloud ids: %v, rid: %s", enumor.Aws, err,
+					delCloudIDs, kt.Rid)
+				return err
+			}
+			logs.Infof("[%s] sync target group to delete target group success, accountID: %s, count: %d, rid: %s",
+				enumor.Aws, accountID, len(delCloudIDs), kt.Rid)
+		}
+
+		if len(tgFromDB.Details) < constant.BatchOperationMaxLimit {
+			break
+		}
+
+		req.Page.Start += constant.BatchOperationMaxLimit
+	}
+
+	return nil
+}
+
+func (cli *client) listTargetGroupFromCloudByLB(kt *kit.Kit, region string, lbCloudID string) (
+	[]typeslb.AwsTargetGroup, error) {
+
+	opt := &typeslb.AwsListTargetGroupOption{
+		Region:         region,
+		LoadBalancerID: lbCloudID,
+		PageSize:       cvt.ValToPtr(int64(typeslb.AwsElbPageSizeMax)),
+	}
+	result := make([]typeslb.AwsTargetGroup, 0)
+	for {
+		groups, marker, err := cli.cloudCli.ListTargetGroup(kt, opt)
+		if err != nil {
+			logs.Errorf("[%s] list target group from cloud failed, err: %v, opt: %v, rid: %s", enumor.Aws, err,
+				opt, kt.Rid)
+			return nil, err
+		}
+		result = append(result, groups...)
+
+		if len(cvt.PtrToVal(marker)) == 0 {
+			break
+		}
+		opt.Marker = marker
+	}
+
+	return result, nil
+}
+
+// listTargetGroupFromCloud list target group by cloud ids, target groups that can not be found will be ignored.
+func (cli *client) listTargetGroupFromCloud(kt *kit.Kit, region string, cloudIDs []string) (
+	[]typeslb.AwsTargetGroup, error) {
+
+	result := make([]typeslb.AwsTargetGroup, 0, len(cloudIDs))
+	for _, batch := range slice.Split(cloudIDs, typeslb.AwsElbDescribeMax) {
+		opt := &typeslb.AwsListTargetGroupOption{Region: region, CloudIDs: batch}
+		groups, _, err := cli.cloudCli.ListTargetGroup(kt, opt)
+		if err == nil {
+			result = append(result, groups...)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), aws.ErrTargetGroupNotFound) {
+			logs.Errorf("[%s] list target group from cloud failed, err: %v, opt: %v, rid: %s", enumor.Aws, err,
+				opt, kt.Rid)
+			return nil, err
+		}
+
+		// 批量查询时只要有一个不存在，aws就会报错，此时逐个查询以过滤掉已删除的目标组
+		for _, cloudID := range batch {
+			opt := &typeslb.AwsListTargetGroupOption{Region: region, CloudIDs: []string{cloudID}}
+			one, _, err := cli.cloudCli.ListTargetGroup(kt, opt)
+			if err != nil {
+				if strings.Contains(err.Error(), aws.ErrTargetGroupNotFound) {
+					continue
+				}
+				logs.Errorf("[%s] list target group from cloud failed, err: %v, opt: %v, rid: %s", enumor.Aws,
+					err, opt, kt.Rid)
+				return nil, err
+			}
+			result = append(result, one...)
+		}
+	}
+
+	return result, nil
+}
+
+func (cli *client) listTargetGroupFromDB(kt *kit.Kit, accountID string, region string, cloudIDs []string) (
+	[]corelb.BaseTargetGroup, error) {
+
+	result := make([]corelb.BaseTargetGroup, 0, len(cloudIDs))
+	for _, batch := range slice.Split(cloudIDs, int(core.DefaultMaxPageLimit)) {
+		req := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", enumor.Aws),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleEqual("region", region),
+				tools.RuleIn("cloud_id", batch),
+			),
+			Page: core.NewDefaultBasePage(),
+		}
+		resp, err := cli.dbCli.Global.LoadBalancer.ListTargetGroup(kt, req)
+		if err != nil {
+			logs.Errorf("[%s] list target group from db failed, err: %v, req: %v, rid: %s", enumor.Aws, err, req,
+				kt.Rid)
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+	}
+
+	return result, nil
+}
+
+func (cli *client) createTargetGroup(kt *kit.Kit, accountID string, region string, lb corelb.AwsLoadBalancer,
+	addSlice []typeslb.AwsTargetGroup) error {
+
+	cloudVpcIDs := slice.Map(addSlice, func(tg typeslb.AwsTargetGroup) string { return cvt.PtrToVal(tg.VpcId) })
+	vpcMap, err := cli.getVpcMap(kt, accountID, region, cloudVpcIDs)
+	if err != nil {
+		logs.Errorf("fail to get vpc of target group during syncing, err: %v, vpcIDs: %v, rid: %s", err,
+			cloudVpcIDs, kt.Rid)
+		return err
+	}
+
+	createReq := &protocloud.AwsTargetGroupCreateReq{}
+	for _, one := range addSlice {
+		// lambda 类型的目标组没有vpc、协议与端口，暂不纳管
+		if one.VpcId == nil || one.Protocol == nil {
+			continue
+		}
+		healthCheck, err := json.Marshal(convHealthCheck(one))
+		if err != nil {
+			logs.Errorf("marshal health check failed, err: %v, tg: %s, rid: %s", err, one.GetCloudID(), kt.Rid)
+			return err
+		}
+		cloudVpcID := cvt.PtrToVal(one.VpcId)
+		createReq.TargetGroups = append(createReq.TargetGroups,
+			protocloud.TargetGroupBatchCreate[corelb.AwsTargetGroupExtension]{
+				CloudID:         one.GetCloudID(),
+				Name:            cvt.PtrToVal(one.TargetGroupName),
+				Vendor:          enumor.Aws,
+				AccountID:       accountID,
+				BkBizID:         lb.BkBizID,
+				Region:          region,
+				Protocol:        enumor.ProtocolType(cvt.PtrToVal(one.Protocol)),
+				Port:            cvt.PtrToVal(one.Port),
+				VpcID:           cvt.PtrToVal(vpcMap[cloudVpcID]).VpcID,
+				CloudVpcID:      cloudVpcID,
+				TargetGroupType: enumor.CloudTargetGroupType,
+				HealthCheck:     types.JsonField(healthCheck),
+				Extension:       convTargetGroupExtension(one),
+			})
+	}
+
+	if len(createReq.TargetGroups) == 0 {
+		return nil
+	}
+
+	if _, err = cli.dbCli.Aws.LoadBalancer.BatchCreateAwsTargetGroup(kt, createReq); err != nil {
+		logs.Errorf("[%s] call data service to create aws target group failed, err: %v, rid: %s", enumor.Aws,
+			err, kt.Rid)
+		return err
+	}
+
+	logs.Infof("[%s] sync target group to create target group success, accountID: %s, count: %d, rid: %s",
+		enumor.Aws, accountID, len(createReq.TargetGroups), kt.Rid)
+
+	return nil
+}
+
+func (cli *client) updateTargetGroup(kt *kit.Kit, id string, cloud typeslb.AwsTargetGroup) error {
+	req := &protocloud.TargetGroupUpdateReq{
+		IDs:         []string{id},
+		Name:        cvt.PtrToVal(cloud.TargetGroupName),
+		Protocol:    enumor.ProtocolType(cvt.PtrToVal(cloud.Protocol)),
+		Port:        cvt.PtrToVal(cloud.Port),
+		CloudVpcID:  cvt.PtrToVal(cloud.VpcId),
+		HealthCheck: convHealthCheck(cloud),
+	}
+	if err := cli.dbCli.Aws.LoadBalancer.BatchUpdateAwsTargetGroup(kt, req); err != nil {
+		logs.Errorf("[%s] call data service to update aws target group failed, err: %v, id: %s, rid: %s",
+			enumor.Aws, err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// convHealthCheck 将aws健康检查配置转换为通用的健康检查结构
+func convHealthCheck(tg typeslb.AwsTargetGroup) *corelb.TCloudHealthCheckInfo {
+	health := &corelb.TCloudHealthCheckInfo{
+		HealthSwitch:  cvt.ValToPtr(int64(0)),
+		TimeOut:       tg.HealthCheckTimeoutSeconds,
+		IntervalTime:  tg.HealthCheckIntervalSeconds,
+		HealthNum:     tg.HealthyThresholdCount,
+		UnHealthNum:   tg.UnhealthyThresholdCount,
+		CheckType:     tg.HealthCheckProtocol,
+		HttpCheckPath: tg.HealthCheckPath,
+	}
+	if cvt.PtrToVal(tg.HealthCheckEnabled) {
+		health.HealthSwitch = cvt.ValToPtr(int64(1))
+	}
+	// traffic-port 表示使用后端端口
+	if port, err := strconv.ParseInt(cvt.PtrToVal(tg.HealthCheckPort), 10, 64); err == nil {
+		health.CheckPort = cvt.ValToPtr(port)
+	}
+
+	return health
+}
+
+func convTargetGroupExtension(tg typeslb.AwsTargetGroup) *corelb.AwsTargetGroupExtension {
+	ext := &corelb.AwsTargetGroupExtension{
+		TargetType:           tg.TargetType,
+		ProtocolVersion:      tg.ProtocolVersion,
+		IpAddressType:        tg.IpAddressType,
+		CloudLoadBalancerIDs: cvt.PtrToSlice(tg.LoadBalancerArns),
+	}
+	if tg.Matcher != nil {
+		ext.HealthCheckMatcher = tg.Matcher.HttpCode
+	}
+
+	return ext
+}
+
+func isTargetGroupChange(cloud typeslb.AwsTargetGroup, db corelb.BaseTargetGroup) bool {
+	if db.Name != cvt.PtrToVal(cloud.TargetGroupName) {
+		return true
+	}
+
+	if db.Protocol != enumor.ProtocolType(cvt.PtrToVal(cloud.Protocol)) {
+		return true
+	}
+
+	if db.Port != cvt.PtrToVal(cloud.Port) {
+		return true
+	}
+
+	if db.CloudVpcID != cvt.PtrToVal(cloud.VpcId) {
+		return true
+	}
+
+	if db.HealthCheck == nil {
+		return true
+	}
+
+	health := convHealthCheck(cloud)
+	if !assert.IsPtrInt64Equal(db.HealthCheck.HealthSwitch, health.HealthSwitch) ||
+		!assert.IsPtrInt64Equal(db.HealthCheck.TimeOut, health.TimeOut) ||
+		!assert.IsPtrInt64Equal(db.HealthCheck.IntervalTime, health.IntervalTime) ||
+		!assert.IsPtrInt64Equal(db.HealthCheck.HealthNum, health.HealthNum) ||
+		!assert.IsPtrInt64Equal(db.HealthCheck.UnHealthNum, health.UnHealthNum) ||
+		!assert.IsPtrInt64Equal(db.HealthCheck.CheckPort, health.CheckPort) ||
+		!assert.IsPtrStringEqual(db.HealthCheck.CheckType, health.CheckType) ||
+		!assert.IsPtrStringEqual(db.HealthCheck.HttpCheckPath, health.HttpCheckPath) {
+		return true
+	}
+
+	return false
+}
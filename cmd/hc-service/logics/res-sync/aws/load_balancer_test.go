@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"testing"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typeslb "hcm/pkg/adaptor/types/load-balancer"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestAwsLB() typeslb.AwsLoadBalancer {
+	return typeslb.AwsLoadBalancer{
+		LoadBalancer: &elbv2.LoadBalancer{
+			LoadBalancerArn:  cvt.ValToPtr("arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/net/nlb/1"),
+			LoadBalancerName: cvt.ValToPtr("nlb"),
+			Type:             cvt.ValToPtr(elbv2.LoadBalancerTypeEnumNetwork),
+			Scheme:           cvt.ValToPtr(elbv2.LoadBalancerSchemeEnumInternal),
+			IpAddressType:    cvt.ValToPtr(elbv2.IpAddressTypeDualstack),
+			DNSName:          cvt.ValToPtr("nlb-1.elb.us-east-1.amazonaws.com"),
+			VpcId:            cvt.ValToPtr("vpc-1"),
+			State:            &elbv2.LoadBalancerState{Code: cvt.ValToPtr(elbv2.LoadBalancerStateEnumActive)},
+			SecurityGroups:   []*string{cvt.ValToPtr("sg-1")},
+			AvailabilityZones: []*elbv2.AvailabilityZone{
+				{
+					ZoneName: cvt.ValToPtr("us-east-1a"),
+					SubnetId: cvt.ValToPtr("subnet-a"),
+					LoadBalancerAddresses: []*elbv2.LoadBalancerAddress{{
+						PrivateIPv4Address: cvt.ValToPtr("10.0.0.10"),
+						IPv6Address:        cvt.ValToPtr("2001:db8::10"),
+					}},
+				},
+				{ZoneName: cvt.ValToPtr("us-east-1b"), SubnetId: cvt.ValToPtr("subnet-b")},
+			},
+		},
+		Tags: []*elbv2.Tag{{Key: cvt.ValToPtr("env"), Value: cvt.ValToPtr("prod")}},
+	}
+}
+
+// convTestDBLB convert the create request to load balancer as it's saved in db.
+func convTestDBLB(cloud typeslb.AwsLoadBalancer) corelb.AwsLoadBalancer {
+	vpcMap := map[string]*common.VpcDB{"vpc-1": {VpcCloudID: "vpc-1", VpcID: "00000001"}}
+	create := convLBCloudToDBCreate(cloud, "acc", "us-east-1", vpcMap)
+	return corelb.AwsLoadBalancer{
+		BaseLoadBalancer: corelb.BaseLoadBalancer{
+			CloudID:              create.CloudID,
+			Name:                 create.Name,
+			IPVersion:            create.IPVersion,
+			Zones:                create.Zones,
+			VpcID:                create.VpcID,
+			CloudVpcID:           create.CloudVpcID,
+			PrivateIPv4Addresses: create.PrivateIPv4Addresses,
+			PublicIPv4Addresses:  create.PublicIPv4Addresses,
+			PublicIPv6Addresses:  create.PublicIPv6Addresses,
+			Domain:               create.Domain,
+			Status:               create.Status,
+			Tags:                 create.Tags,
+		},
+		Extension: create.Extension,
+	}
+}
+
+func TestConvLBCloudToDBCreate(t *testing.T) {
+	vpcMap := map[string]*common.VpcDB{"vpc-1": {VpcCloudID: "vpc-1", VpcID: "00000001"}}
+	create := convLBCloudToDBCreate(buildTestAwsLB(), "acc", "us-east-1", vpcMap)
+
+	assert.Equal(t, "arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/net/nlb/1", create.CloudID)
+	assert.Equal(t, enumor.Aws, create.Vendor)
+	assert.Equal(t, string(typeslb.InternalLoadBalancerType), create.LoadBalancerType)
+	assert.Equal(t, enumor.Ipv6DualStack, create.IPVersion)
+	assert.Equal(t, "00000001", create.VpcID)
+	assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, create.Zones)
+	assert.Equal(t, []string{"10.0.0.10"}, create.PrivateIPv4Addresses)
+	assert.Empty(t, create.PublicIPv4Addresses)
+	assert.Equal(t, []string{"2001:db8::10"}, create.PublicIPv6Addresses)
+	assert.Equal(t, elbv2.LoadBalancerStateEnumActive, create.Status)
+	assert.Equal(t, "prod", create.Tags["env"])
+	assert.Equal(t, []string{"subnet-a", "subnet-b"}, create.Extension.CloudSubnetIDs)
+	assert.Equal(t, []string{"sg-1"}, create.Extension.CloudSecurityGroupIDs)
+}
+
+func TestIsLBChange(t *testing.T) {
+	cloud := buildTestAwsLB()
+	db := convTestDBLB(cloud)
+	assert.False(t, isLBChange(cloud, db))
+
+	changed := buildTestAwsLB()
+	changed.State.Code = cvt.ValToPtr(elbv2.LoadBalancerStateEnumProvisioning)
+	assert.True(t, isLBChange(changed, db))
+
+	changed = buildTestAwsLB()
+	changed.AvailabilityZones = changed.AvailabilityZones[:1]
+	assert.True(t, isLBChange(changed, db))
+
+	changed = buildTestAwsLB()
+	changed.SecurityGroups = []*string{cvt.ValToPtr("sg-2")}
+	assert.True(t, isLBChange(changed, db))
+
+	changed = buildTestAwsLB()
+	changed.Tags = nil
+	assert.True(t, isLBChange(changed, db))
+}
@@ -72,7 +72,7 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.AwsRegion, cloudcore.AwsRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, opt, delCloudIDs); err != nil {
@@ -176,6 +176,10 @@ func (cli *client) updateRegion(kt *kit.Kit, opt *SyncRegionOption,
 }
 
 func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
@@ -127,7 +127,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AwsRoute,
-		routetable.AwsRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.AwsRoute](kt, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.Region, opt.CloudRouteTableID, routeTable.ID,
@@ -250,6 +250,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, region, routeTableID stri
 func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID string,
 	delCloudIDs []string, routeFromDB []routetable.AwsRoute) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -73,7 +73,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AwsRouteTable,
-		routetable.AwsRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
+		routetable.AwsRouteTable](kt, routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 
@@ -253,6 +253,10 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 
 // deleteRouteTable delete route table
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("routeTable delCloudIDs is <= 0, not delete")
 	}
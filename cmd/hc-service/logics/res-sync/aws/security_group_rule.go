@@ -125,7 +125,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygrouprule.AwsSGRule,
-		corecloud.AwsSecurityGroupRule](sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
+		corecloud.AwsSecurityGroupRule](kt, sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
 
 	if len(delCloudIDs) > 0 {
 		err := cli.deleteSGRule(kt, opt, delCloudIDs)
@@ -304,6 +304,10 @@ func (cli *client) genUpdateRulesList(updateMap map[string]securitygrouprule.Aws
 
 // deleteSGRule delete security group rules in the database
 func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sgRule delCloudIDs is <= 0, not delete")
 	}
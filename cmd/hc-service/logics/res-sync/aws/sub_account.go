@@ -72,7 +72,7 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[account.AwsAccount,
-		coresubaccount.SubAccount[coresubaccount.AwsExtension]](fromCloud, fromDB, isSubAccountChange)
+		coresubaccount.SubAccount[coresubaccount.AwsExtension]](kt, fromCloud, fromDB, isSubAccountChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubAccount(kt, opt, delCloudIDs); err != nil {
@@ -226,6 +226,10 @@ func (cli *client) createSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, addS
 
 func (cli *client) deleteSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("delCloudIDs is required")
 	}
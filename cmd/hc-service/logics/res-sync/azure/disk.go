@@ -78,7 +78,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[typesdisk.AzureDisk, *coredisk.Disk[coredisk.AzureExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -193,6 +193,10 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, resGroupName string
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, resGroupName string,
 	delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("delCloudIDs is <= 0, not delete")
 	}
@@ -71,7 +71,7 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.AzureEip,
-		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](kt, eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -168,6 +168,10 @@ func (cli *client) RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, resGr
 }
 
 func (cli *client) deleteEip(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("eip delCloudIDs is <= 0, not delete")
 	}
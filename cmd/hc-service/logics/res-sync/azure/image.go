@@ -73,7 +73,7 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AzureImage, coreimage.Image[coreimage.AzureExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteImage(kt, opt, delCloudIDs); err != nil {
@@ -177,6 +177,10 @@ func (cli *client) createImage(kt *kit.Kit, opt *SyncImageOption,
 }
 
 func (cli *client) deleteImage(kt *kit.Kit, opt *SyncImageOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
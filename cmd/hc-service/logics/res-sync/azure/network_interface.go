@@ -73,7 +73,7 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 	}
 
 	addNetworkInterface, updateMap, delCloudIDs := common.Diff[typesni.AzureNI,
-		coreni.NetworkInterface[coreni.AzureNIExtension]](niFromCloud, niFromDB, isNIChange)
+		coreni.NetworkInterface[coreni.AzureNIExtension]](kt, niFromCloud, niFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -171,6 +171,10 @@ func (cli *client) RemoveNetworkInterfaceDeleteFromCloud(kt *kit.Kit, accountID
 // deleteNetworkInterface deletes network interfaces from the database
 func (cli *client) deleteNetworkInterface(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete network interface, network interfaces is required")
 	}
@@ -70,7 +70,7 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesrg.AzureResourceGroup, corerg.AzureRG](
-		resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
+		kt, resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteResourceGroup(kt, opt, delCloudIDs); err != nil {
@@ -161,6 +161,10 @@ func (cli *client) updateResourceGroup(kt *kit.Kit, opt *SyncRGOption,
 }
 
 func (cli *client) deleteResourceGroup(kt *kit.Kit, opt *SyncRGOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("resourcegroup delCloudIDs is <= 0, not delete")
 	}
@@ -123,7 +123,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRoute,
-		routetable.AzureRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.AzureRoute](kt, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.ResourceGroupName, opt.CloudRouteTableID, routeTable.ID,
@@ -228,6 +228,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, resGroupName, routeTableI
 func (cli *client) deleteRoute(kt *kit.Kit, accountID, resGroupName, cloudRTID, rtID string,
 	delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -71,7 +71,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRouteTable,
-		routetable.AzureRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
+		routetable.AzureRouteTable](kt, routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 
@@ -317,6 +317,10 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 
 // deleteRouteTable 删除路由表
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("routeTable delCloudIDs is <= 0, not delete")
 	}
@@ -71,7 +71,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 
 	addSlice, updateMap, delCloudIDs := common.Diff[
 		securitygroup.AzureSecurityGroup, cloudcore.SecurityGroup[cloudcore.AzureSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -206,6 +206,10 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string, resGroupName string,
 
 // deleteSG deletes security groups in the database
 func (cli *client) deleteSG(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sg delCloudIDs is <= 0, not delete")
 	}
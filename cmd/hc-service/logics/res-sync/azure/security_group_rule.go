@@ -136,7 +136,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygrouprule.AzureSGRule,
-		corecloud.AzureSecurityGroupRule](sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
+		corecloud.AzureSecurityGroupRule](kt, sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
 
 	if len(delCloudIDs) > 0 {
 		err := cli.deleteSGRule(kt, opt, delCloudIDs)
@@ -357,6 +357,10 @@ func (cli *client) genUpdateRulesList(updateMap map[string]securitygrouprule.Azu
 
 // deleteSGRule delete security group rule
 func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sgRule delCloudIDs is <= 0, not delete")
 	}
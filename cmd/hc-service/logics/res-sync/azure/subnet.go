@@ -72,7 +72,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AzureSubnet,
-		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](subnetFromCloud, subnetFromDB, isSubnetChange)
+		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](kt, subnetFromCloud, subnetFromDB, isSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.ResourceGroupName, opt.CloudVpcID,
@@ -172,6 +172,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, resGroupN
 // deleteSubnet delete subnet from db, before delete, check if subnet exist in cloud
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID string, delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -71,7 +71,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.AzureVpc, cloudcore.Vpc[cloudcore.AzureVpcExtension]](
-		vpcFromCloud, vpcFromDB, isVpcChange)
+		kt, vpcFromCloud, vpcFromDB, isVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -168,6 +168,10 @@ func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, resGr
 // deleteVpc deletes vpcs from the database.
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/adaptor/types/cert"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	typesdisk "hcm/pkg/adaptor/types/disk"
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	firewallrule "hcm/pkg/adaptor/types/firewall-rule"
 	typesimage "hcm/pkg/adaptor/types/image"
@@ -43,6 +44,7 @@ import (
 	corecert "hcm/pkg/api/core/cloud/cert"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	coredisk "hcm/pkg/api/core/cloud/disk"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
 	coreimage "hcm/pkg/api/core/cloud/image"
 	corelb "hcm/pkg/api/core/cloud/load-balancer"
 	corecloudni "hcm/pkg/api/core/cloud/network-interface"
@@ -53,6 +55,7 @@ import (
 	corezone "hcm/pkg/api/core/cloud/zone"
 	corerecyclerecord "hcm/pkg/api/core/recycle-record"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/kit"
 	"hcm/pkg/thirdparty/api-gateway/cmdb"
 )
 
@@ -103,6 +106,8 @@ type CloudResType interface {
 		typesdisk.GcpDisk |
 		typesdisk.AzureDisk |
 
+		typesdisksnapshot.DiskSnapshot |
+
 		securitygroup.TCloudSG |
 		securitygroup.HuaWeiSG |
 		securitygroup.AwsSG |
@@ -157,7 +162,10 @@ type CloudResType interface {
 		typeslb.TCloudClb |
 		typeslb.TCloudListener |
 		typeslb.TCloudUrlRule |
-		typeslb.Backend
+		typeslb.Backend |
+		typeslb.AwsLoadBalancer |
+		typeslb.AwsListener |
+		typeslb.AwsTargetGroup
 }
 
 // TestCloudRes 测试云资源类型
@@ -213,6 +221,8 @@ type DBResType interface {
 		*coredisk.Disk[coredisk.GcpExtension] |
 		*coredisk.Disk[coredisk.AzureExtension] |
 
+		coredisksnapshot.DiskSnapshot |
+
 		cloudcore.SecurityGroup[cloudcore.TCloudSecurityGroupExtension] |
 		cloudcore.SecurityGroup[cloudcore.HuaWeiSecurityGroupExtension] |
 		cloudcore.SecurityGroup[cloudcore.AwsSecurityGroupExtension] |
@@ -264,11 +274,15 @@ type DBResType interface {
 		corelb.TCloudLoadBalancer |
 		corelb.TCloudLbUrlRule |
 		corelb.TCloudListener |
-		corelb.BaseTarget
+		corelb.BaseTarget |
+		corelb.AwsLoadBalancer |
+		corelb.AwsListener |
+		corelb.BaseTargetGroup
 }
 
 // Diff 对比云和db资源，划分出新增数据，更新数据，删除数据。
-func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, dataFromDB []DBType,
+// 计划模式下差异只记录到同步计划中，返回空结果，调用方不会修改db数据。
+func Diff[CloudType CloudResType, DBType DBResType](kt *kit.Kit, dataFromCloud []CloudType, dataFromDB []DBType,
 	isChange func(CloudType, DBType) bool) ([]CloudType, map[string]CloudType, []string) {
 
 	dbMap := make(map[string]DBType, len(dataFromDB))
@@ -278,6 +292,10 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 
 	newAddData := make([]CloudType, 0)
 	updateMap := make(map[string]CloudType, 0)
+	delDBMap := make(map[string]DBType, len(dbMap))
+	for cloudID, one := range dbMap {
+		delDBMap[cloudID] = one
+	}
 	for _, oneFromCloud := range dataFromCloud {
 		oneFromDB, exist := dbMap[oneFromCloud.GetCloudID()]
 		if !exist {
@@ -285,16 +303,21 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 			continue
 		}
 
-		delete(dbMap, oneFromCloud.GetCloudID())
+		delete(delDBMap, oneFromCloud.GetCloudID())
 		if isChange(oneFromCloud, oneFromDB) {
 			updateMap[oneFromDB.GetID()] = oneFromCloud
 		}
 	}
 
 	delCloudIDs := make([]string, 0)
-	for cloudID := range dbMap {
+	for cloudID := range delDBMap {
 		delCloudIDs = append(delCloudIDs, cloudID)
 	}
 
+	if plan := PlanFromKit(kt); plan != nil {
+		planDiff(plan, newAddData, updateMap, delCloudIDs, dbMap)
+		return make([]CloudType, 0), make(map[string]CloudType), make([]string, 0)
+	}
+
 	return newAddData, updateMap, delCloudIDs
 }
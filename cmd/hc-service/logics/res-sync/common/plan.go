@@ -0,0 +1,265 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
+)
+
+type planCtxKey struct{}
+
+// SyncPlan 同步计划，计划模式下同步只计算云上与db的差异并记录到计划中，不修改db数据
+type SyncPlan struct {
+	lock     sync.Mutex
+	resource string
+	// Resources 资源类型 -> 资源差异
+	Resources map[string]*ResourcePlan `json:"resources"`
+}
+
+// ResourcePlan 单个资源类型的同步差异
+type ResourcePlan = hcsync.ResourcePlan
+
+// UpdatedResource 更新的资源
+type UpdatedResource = hcsync.UpdatedResource
+
+// StartPlan 开启计划模式，之后使用该kit的同步只记录差异，所有对 data-service 的写请求都会被拒绝
+func StartPlan(kt *kit.Kit) *SyncPlan {
+	plan := &SyncPlan{Resources: make(map[string]*ResourcePlan)}
+	kt.Ctx = rest.WithReadOnly(context.WithValue(kt.Ctx, planCtxKey{}, plan))
+	return plan
+}
+
+// PlanFromKit 获取kit中的同步计划，不是计划模式时返回nil
+func PlanFromKit(kt *kit.Kit) *SyncPlan {
+	if kt == nil || kt.Ctx == nil {
+		return nil
+	}
+
+	plan, _ := kt.Ctx.Value(planCtxKey{}).(*SyncPlan)
+	return plan
+}
+
+// SetResource 设置当前同步的资源类型，之后记录的差异都归属于该资源类型
+func (p *SyncPlan) SetResource(resource string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.resource = resource
+}
+
+// PlanRemove 计划模式下记录需要删除的资源，返回true表示处于计划模式，调用方不应执行删除
+func PlanRemove[T any](kt *kit.Kit, ids []T) bool {
+	plan := PlanFromKit(kt)
+	if plan == nil {
+		return false
+	}
+
+	plan.record("", func(res *ResourcePlan) {
+		for _, id := range ids {
+			res.Removed = append(res.Removed, fmt.Sprint(id))
+		}
+	})
+	return true
+}
+
+// PlanAdd 计划模式下记录需要新增的资源，返回true表示处于计划模式，调用方不应执行新增
+func PlanAdd[T any](kt *kit.Kit, ids []T) bool {
+	plan := PlanFromKit(kt)
+	if plan == nil {
+		return false
+	}
+
+	plan.record("", func(res *ResourcePlan) {
+		for _, id := range ids {
+			res.Added = append(res.Added, fmt.Sprint(id))
+		}
+	})
+	return true
+}
+
+// PlanUpdate 计划模式下记录需要更新的资源，返回true表示处于计划模式，调用方不应执行更新。
+// updated 只在计划模式下调用，避免非计划模式下对比变更字段的开销
+func PlanUpdate(kt *kit.Kit, updated func() []UpdatedResource) bool {
+	plan := PlanFromKit(kt)
+	if plan == nil {
+		return false
+	}
+
+	one := updated()
+	plan.record("", func(res *ResourcePlan) {
+		res.Updated = append(res.Updated, one...)
+	})
+	return true
+}
+
+// PlanRelation 计划模式下记录关联关系的差异，关联关系按 relation 单独归类，不归属于当前资源类型，
+// 返回true表示处于计划模式，调用方不应修改关联关系
+func PlanRelation(kt *kit.Kit, relation string, added []string, removed []string) bool {
+	plan := PlanFromKit(kt)
+	if plan == nil {
+		return false
+	}
+
+	plan.recordTo(relation, func(res *ResourcePlan) {
+		res.Added = append(res.Added, added...)
+		res.Removed = append(res.Removed, removed...)
+	})
+	return true
+}
+
+// NewUpdatedResource 对比更新数据与db资源生成更新记录，更新数据中为空的字段不会被更新，不参与对比
+func NewUpdatedResource(id, cloudID string, update, fromDB any) UpdatedResource {
+	return UpdatedResource{
+		ID:            id,
+		CloudID:       cloudID,
+		ChangedFields: changedFields(update, fromDB, true),
+	}
+}
+
+// planDiff 记录Diff结果到计划中
+func planDiff[CloudType CloudResType, DBType DBResType](plan *SyncPlan, add []CloudType,
+	updateMap map[string]CloudType, delCloudIDs []string, dbMap map[string]DBType) {
+
+	updated := make([]UpdatedResource, 0, len(updateMap))
+	for id, oneFromCloud := range updateMap {
+		updated = append(updated, UpdatedResource{
+			ID:            id,
+			CloudID:       oneFromCloud.GetCloudID(),
+			ChangedFields: changedFields(oneFromCloud, dbMap[oneFromCloud.GetCloudID()], false),
+		})
+	}
+	sort.Slice(updated, func(i, j int) bool { return updated[i].CloudID < updated[j].CloudID })
+
+	plan.record(typeName[CloudType](), func(res *ResourcePlan) {
+		for _, one := range add {
+			res.Added = append(res.Added, one.GetCloudID())
+		}
+		res.Updated = append(res.Updated, updated...)
+		res.Removed = append(res.Removed, delCloudIDs...)
+	})
+}
+
+// record 记录差异到当前资源类型下，未设置当前资源类型时使用 defaultResource
+func (p *SyncPlan) record(defaultResource string, fn func(res *ResourcePlan)) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	resource := p.resource
+	if len(resource) == 0 {
+		resource = defaultResource
+	}
+	p.recordLocked(resource, fn)
+}
+
+// recordTo 记录差异到指定的资源类型下
+func (p *SyncPlan) recordTo(resource string, fn func(res *ResourcePlan)) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.recordLocked(resource, fn)
+}
+
+func (p *SyncPlan) recordLocked(resource string, fn func(res *ResourcePlan)) {
+	res, exists := p.Resources[resource]
+	if !exists {
+		res = &ResourcePlan{Added: make([]string, 0), Updated: make([]UpdatedResource, 0), Removed: make([]string, 0)}
+		p.Resources[resource] = res
+	}
+	fn(res)
+}
+
+func typeName[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// changedFields 将云上与db资源按json展开，返回两者都有但值不同的字段，ignoreNull 为true时云上为空的字段不参与对比。
+// 云上资源与db资源结构不同，只有同名字段才能比较，结果仅作为参考。
+func changedFields(fromCloud, fromDB any, ignoreNull bool) []string {
+	cloudFields, err := flattenJson(fromCloud)
+	if err != nil {
+		return nil
+	}
+	dbFields, err := flattenJson(fromDB)
+	if err != nil {
+		return nil
+	}
+
+	changed := make([]string, 0)
+	for field, cloudValue := range cloudFields {
+		if ignoreNull && cloudValue == nil {
+			continue
+		}
+		dbValue, exists := dbFields[field]
+		if !exists {
+			continue
+		}
+		if !reflect.DeepEqual(cloudValue, dbValue) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func flattenJson(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err = json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]any)
+	flatten("", data, result)
+	return result, nil
+}
+
+func flatten(prefix string, value any, result map[string]any) {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		if len(prefix) != 0 {
+			result[prefix] = value
+		}
+		return
+	}
+
+	for key, one := range fields {
+		if len(prefix) != 0 {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		flatten(key, one, result)
+	}
+}
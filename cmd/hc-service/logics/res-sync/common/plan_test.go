@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	corezone "hcm/pkg/api/core/cloud/zone"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffInPlanMode(t *testing.T) {
+	kt := &kit.Kit{Ctx: context.Background()}
+	plan := StartPlan(kt)
+	plan.SetResource("zone")
+
+	fromCloud := []TestCloudRes{{CloudID: "add"}, {CloudID: "update"}, {CloudID: "same"}}
+	fromDB := []corezone.BaseZone{{ID: "1", CloudID: "update"}, {ID: "2", CloudID: "same"}, {ID: "3", CloudID: "del"}}
+	isChange := func(cloud TestCloudRes, db corezone.BaseZone) bool { return cloud.CloudID == "update" }
+
+	add, updateMap, delCloudIDs := Diff[TestCloudRes, corezone.BaseZone](kt, fromCloud, fromDB, isChange)
+	assert.Empty(t, add)
+	assert.Empty(t, updateMap)
+	assert.Empty(t, delCloudIDs)
+
+	assert.True(t, PlanRemove(kt, []int64{4}))
+
+	res := plan.Resources["zone"]
+	assert.NotNil(t, res)
+	assert.Equal(t, []string{"add"}, res.Added)
+	assert.Len(t, res.Updated, 1)
+	assert.Equal(t, "1", res.Updated[0].ID)
+	assert.Equal(t, "update", res.Updated[0].CloudID)
+	assert.Equal(t, []string{"del", "4"}, res.Removed)
+}
+
+func TestDiffWithoutPlan(t *testing.T) {
+	kt := &kit.Kit{Ctx: context.Background()}
+	assert.False(t, PlanRemove(kt, []string{"del"}))
+
+	fromCloud := []TestCloudRes{{CloudID: "add"}}
+	fromDB := []corezone.BaseZone{{ID: "1", CloudID: "del"}}
+	add, _, delCloudIDs := Diff[TestCloudRes, corezone.BaseZone](kt, fromCloud, fromDB,
+		func(TestCloudRes, corezone.BaseZone) bool { return false })
+	assert.Len(t, add, 1)
+	assert.Equal(t, []string{"del"}, delCloudIDs)
+}
+
+func TestPlanWrites(t *testing.T) {
+	kt := &kit.Kit{Ctx: context.Background()}
+	assert.False(t, PlanAdd(kt, []string{"add"}))
+	assert.False(t, PlanUpdate(kt, func() []UpdatedResource {
+		t.Fatal("updated should not be called without plan")
+		return nil
+	}))
+	assert.False(t, PlanRelation(kt, "cvm_disk_rel", []string{"cvm/disk"}, nil))
+
+	plan := StartPlan(kt)
+	plan.SetResource("security_group")
+
+	name := "new"
+	type update struct {
+		Name *string `json:"name"`
+		Memo *string `json:"memo"`
+	}
+	type db struct {
+		Name string `json:"name"`
+		Memo string `json:"memo"`
+	}
+	assert.True(t, PlanAdd(kt, []string{"add"}))
+	assert.True(t, PlanUpdate(kt, func() []UpdatedResource {
+		return []UpdatedResource{NewUpdatedResource("1", "update", update{Name: &name}, db{Name: "old", Memo: "m"})}
+	}))
+	assert.True(t, PlanRelation(kt, "cvm_disk_rel", []string{"cvm/disk"}, []string{"cvm/old-disk"}))
+
+	res := plan.Resources["security_group"]
+	assert.Equal(t, []string{"add"}, res.Added)
+	assert.Equal(t, []UpdatedResource{{ID: "1", CloudID: "update", ChangedFields: []string{"name"}}}, res.Updated)
+
+	rel := plan.Resources["cvm_disk_rel"]
+	assert.Equal(t, []string{"cvm/disk"}, rel.Added)
+	assert.Equal(t, []string{"cvm/old-disk"}, rel.Removed)
+}
@@ -39,6 +39,11 @@ func CancelRouteTableSubnetRel(kt *kit.Kit, dataCli *dataclient.Client, vendor e
 		return nil
 	}
 
+	// 计划模式下路由表的删除会记录到计划中，子网解绑路由表是删除路由表的附带操作，不需要修改db
+	if PlanFromKit(kt) != nil {
+		return nil
+	}
+
 	expr := &filter.Expression{
 		Op: filter.And,
 		Rules: []filter.RuleFactory{
@@ -42,7 +42,7 @@ func (mgr *CvmRelManger) syncCvmDiskRel(kt *kit.Kit, cvmMap map[string]string, o
 		return err
 	}
 
-	cvmIDs, cvmRelMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(enumor.DiskCloudResType, cvmMap, diskMap)
+	cvmIDs, cvmRelMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(kt, enumor.DiskCloudResType, cvmMap, diskMap)
 	if err != nil {
 		logs.Errorf("get cvm id with ass res id map failed, err: %v, rid: %s", err, kt.Rid)
 		return err
@@ -54,6 +54,10 @@ func (mgr *CvmRelManger) syncCvmDiskRel(kt *kit.Kit, cvmMap map[string]string, o
 		return err
 	}
 
+	if planRel(kt, opt.ResType, cvmRelMapFromCloud, cvmRelMapFromDB) {
+		return nil
+	}
+
 	addRels, delIDs := diffCvmWithAssResRel(cvmRelMapFromCloud, cvmRelMapFromDB)
 
 	if len(addRels) > 0 {
@@ -43,7 +43,8 @@ func (mgr *CvmRelManger) syncCvmNetworkInterfaceRel(kt *kit.Kit, cvmMap map[stri
 		return err
 	}
 
-	cvmIDs, cvmRelMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(enumor.NetworkInterfaceCloudResType, cvmMap, niMap)
+	cvmIDs, cvmRelMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(kt, enumor.NetworkInterfaceCloudResType, cvmMap,
+		niMap)
 	if err != nil {
 		logs.Errorf("get cvm id with ass res id map failed, err: %v, rid: %s", err, kt.Rid)
 		return err
@@ -55,6 +56,10 @@ func (mgr *CvmRelManger) syncCvmNetworkInterfaceRel(kt *kit.Kit, cvmMap map[stri
 		return err
 	}
 
+	if planRel(kt, opt.ResType, cvmRelMapFromCloud, cvmRelMapFromDB) {
+		return nil
+	}
+
 	addRels, delIDs := diffCvmWithAssResRel(cvmRelMapFromCloud, cvmRelMapFromDB)
 
 	if len(addRels) > 0 {
@@ -22,6 +22,7 @@ package cvmrelmgr
 import (
 	"sort"
 
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud"
 	dataproto "hcm/pkg/api/data-service"
@@ -49,7 +50,7 @@ func (mgr *CvmRelManger) syncCvmSGRel(kt *kit.Kit, cvmMap map[string]string, opt
 		return err
 	}
 
-	cvmIDs, cvmIDToSgIDMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(enumor.SecurityGroupCloudResType, cvmMap,
+	cvmIDs, cvmIDToSgIDMapFromCloud, err := mgr.getCvmIDWithAssResIDMap(kt, enumor.SecurityGroupCloudResType, cvmMap,
 		securityGroupMap)
 	if err != nil {
 		logs.Errorf("get cvm id with ass res id map failed, err: %v, rid: %s", err, kt.Rid)
@@ -73,6 +74,8 @@ func (mgr *CvmRelManger) syncCvmSGRel(kt *kit.Kit, cvmMap map[string]string, opt
 func (mgr *CvmRelManger) compareCvmSGRel(kt *kit.Kit, cvmIDToSgIDMapFromCloud map[string][]string,
 	cvmIDToSGRelsMapFromDB map[string][]cloud.SGCommonRelWithBaseSecurityGroup, vendor enumor.Vendor) error {
 
+	planned := common.PlanFromKit(kt) != nil
+	planAdded, planRemoved := make([]string, 0), make([]string, 0)
 	for cvmID, sgIDs := range cvmIDToSgIDMapFromCloud {
 		localSGRels := cvmIDToSGRelsMapFromDB[cvmID]
 		// 按优先级从小到大排序
@@ -94,6 +97,16 @@ func (mgr *CvmRelManger) compareCvmSGRel(kt *kit.Kit, cvmIDToSgIDMapFromCloud ma
 			// 加入可以保留的安全组id列表中
 			stayLocalIDs = append(stayLocalIDs, sgID)
 		}
+		// 计划模式下与upsert保持一致，优先级变化的关联关系会被删除后重新创建
+		if planned {
+			for _, rel := range localSGRels[len(stayLocalIDs):] {
+				planRemoved = append(planRemoved, relKey(cvmID, rel.ID))
+			}
+			for _, one := range sgIDs[idx:] {
+				planAdded = append(planAdded, relKey(cvmID, one))
+			}
+			continue
+		}
 		err := mgr.upsertSgRelForCvm(kt, cvmID, idx, stayLocalIDs, sgIDs[idx:], vendor)
 		if err != nil {
 			logs.Errorf("fail to upsert cvm(%s) security group rel, err: %v, rid: %s", cvmID, err, kt.Rid)
@@ -101,6 +114,10 @@ func (mgr *CvmRelManger) compareCvmSGRel(kt *kit.Kit, cvmIDToSgIDMapFromCloud ma
 		}
 	}
 
+	if planned {
+		common.PlanRelation(kt, relationName(enumor.SecurityGroupCloudResType), planAdded, planRemoved)
+	}
+
 	return nil
 }
 
@@ -19,6 +19,16 @@
 
 package cvmrelmgr
 
+import (
+	"fmt"
+	"sort"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+)
+
 func diffCvmWithAssResRel(cloud map[string][]string, db map[string]map[string]cvmRelInfo) ([]cvmRelInfo, []uint64) {
 
 	addRels := make([]cvmRelInfo, 0)
@@ -63,3 +73,45 @@ func diffCvmWithAssResRel(cloud map[string][]string, db map[string]map[string]cv
 
 	return addRels, delIDs
 }
+
+// planRel 计划模式下记录主机与关联资源关系的差异，返回true表示处于计划模式，调用方不应修改关联关系
+func planRel(kt *kit.Kit, resType enumor.CloudResourceType, cloud map[string][]string,
+	db map[string]map[string]cvmRelInfo) bool {
+
+	if common.PlanFromKit(kt) == nil {
+		return false
+	}
+
+	added := make([]string, 0)
+	for cvmID, assResIDs := range cloud {
+		for _, assResID := range assResIDs {
+			if _, exist := db[cvmID][assResID]; !exist {
+				added = append(added, relKey(cvmID, assResID))
+			}
+		}
+	}
+
+	removed := make([]string, 0)
+	for cvmID, relMap := range db {
+		assResIDMap := converter.StringSliceToMap(cloud[cvmID])
+		for assResID := range relMap {
+			if _, exist := assResIDMap[assResID]; !exist {
+				removed = append(removed, relKey(cvmID, assResID))
+			}
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return common.PlanRelation(kt, relationName(resType), added, removed)
+}
+
+// relKey 关联关系在同步计划中的标识，格式为 主机ID/关联资源ID，计划模式下未入库的主机和关联资源使用云ID
+func relKey(cvmID, assResID string) string {
+	return cvmID + "/" + assResID
+}
+
+// relationName 关联关系在同步计划中的资源类型
+func relationName(resType enumor.CloudResourceType) string {
+	return fmt.Sprintf("cvm_%s_rel", resType)
+}
@@ -264,15 +264,20 @@ type cvmRelInfo struct {
 	CvmID    string
 }
 
-func (mgr *CvmRelManger) getCvmIDWithAssResIDMap(resType enumor.CloudResourceType,
+// getCvmIDWithAssResIDMap 获取主机ID和关联资源ID的映射，计划模式下新增的主机和关联资源没有入库，使用云ID代替
+func (mgr *CvmRelManger) getCvmIDWithAssResIDMap(kt *kit.Kit, resType enumor.CloudResourceType,
 	cvmMap, assResMap map[string]string) ([]string, map[string][]string, error) {
 
+	planned := common.PlanFromKit(kt) != nil
 	result := make(map[string][]string)
 	cvmIDs := make([]string, 0, len(mgr.cvmAssResMap))
 	for cvmCloudID, valueMap := range mgr.cvmAssResMap {
 		cvmID, exist := cvmMap[cvmCloudID]
 		if !exist {
-			return nil, nil, fmt.Errorf("cvm: %s not found", cvmCloudID)
+			if !planned {
+				return nil, nil, fmt.Errorf("cvm: %s not found", cvmCloudID)
+			}
+			cvmID = cvmCloudID
 		}
 
 		cvmIDs = append(cvmIDs, cvmID)
@@ -286,7 +291,10 @@ func (mgr *CvmRelManger) getCvmIDWithAssResIDMap(resType enumor.CloudResourceTyp
 		for _, one := range assResCloudIDs {
 			id, exist := assResMap[one]
 			if !exist {
-				return nil, nil, fmt.Errorf("%s: %s not found", resType, one)
+				if !planned {
+					return nil, nil, fmt.Errorf("%s: %s not found", resType, one)
+				}
+				id = one
 			}
 
 			result[cvmID] = append(result[cvmID], id)
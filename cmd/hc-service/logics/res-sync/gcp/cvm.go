@@ -77,7 +77,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.GcpCvm, corecvm.Cvm[cvm.GcpCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, opt.Zone, delCloudIDs); err != nil {
@@ -455,6 +455,10 @@ func buildCvmUpdateReq(id string, one typescvm.GcpCvm, vpcCloudID, vpcID, imageI
 
 // deleteCvm deletes cvm by delCloudIDs
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, zone string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -70,7 +70,7 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[firewallrule.GcpFirewall, cloudcore.GcpFirewallRule](
-		firewallFromCloud, firewallFromDB, isFirewallChange)
+		kt, firewallFromCloud, firewallFromDB, isFirewallChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteFirewall(kt, params.AccountID, delCloudIDs); err != nil {
@@ -240,6 +240,10 @@ func (cli *client) updateFirewall(kt *kit.Kit, accountID string,
 }
 
 func (cli *client) deleteFirewall(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("firewall delCloudIDs is <= 0, not delete")
 	}
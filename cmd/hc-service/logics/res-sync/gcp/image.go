@@ -70,7 +70,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.GcpImage, coreimage.Image[coreimage.GcpExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteImage(kt, params.AccountID, opt.ProjectID, delCloudIDs); err != nil {
@@ -166,6 +166,10 @@ func (cli *client) createImage(kt *kit.Kit, accountID, projectID, region string,
 }
 
 func (cli *client) deleteImage(kt *kit.Kit, accountID string, projectID string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
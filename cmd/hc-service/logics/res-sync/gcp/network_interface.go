@@ -118,7 +118,7 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesni.GcpNI, coreni.
-		NetworkInterface[coreni.GcpNIExtension]](networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
+		NetworkInterface[coreni.GcpNIExtension]](kt, networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, delCloudIDs, opt); err != nil {
@@ -144,6 +144,10 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 // deleteNetworkInterface 删除网络接口
 func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt *syncNIOption) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if err := opt.Validate(); err != nil {
 		return errf.NewFromErr(errf.InvalidParameter, err)
 	}
@@ -74,7 +74,7 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.GcpRegion, cloudcore.GcpRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, params.AccountID, delCloudIDs); err != nil {
@@ -229,6 +229,10 @@ func (cli *client) updateRegion(kt *kit.Kit, accountID string,
 }
 
 func (cli *client) deleteRegion(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
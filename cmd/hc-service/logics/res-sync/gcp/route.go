@@ -70,7 +70,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.GcpRoute, cloudcoreroutetable.GcpRoute](
-		routeFromCloud, routeFromDB, isRouteChange)
+		kt, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRoute(kt, params.AccountID, delCloudIDs, routeFromDB); err != nil {
@@ -157,6 +157,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID string,
 func (cli *client) deleteRoute(kt *kit.Kit, accountID string, delCloudIDs []string,
 	routeFromDB []cloudcoreroutetable.GcpRoute) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
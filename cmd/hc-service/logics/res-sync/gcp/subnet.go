@@ -72,7 +72,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.GcpSubnet, cloudcore.Subnet[cloudcore.GcpSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isGcpSubnetChange)
+		kt, subnetFromCloud, subnetFromDB, isGcpSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, opt.Region, delCloudIDs); err != nil {
@@ -166,6 +166,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, region st
 
 // deleteSubnet delete subnet from db
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
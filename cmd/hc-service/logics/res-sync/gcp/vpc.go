@@ -71,7 +71,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.GcpVpc, cloudcore.Vpc[cloudcore.GcpVpcExtension]](
-		vpcFromCloud, vpcFromDB, isGcpVpcChange)
+		kt, vpcFromCloud, vpcFromDB, isGcpVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, delCloudIDs); err != nil {
@@ -164,6 +164,10 @@ func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string) error
 
 // deleteVpc delete vpc from db, and validate if the vpc exist in cloud
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -72,7 +72,7 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeszone.GcpZone, corezone.BaseZone](
-		zoneFromCloud, zoneFromDB, isZoneChange)
+		kt, zoneFromCloud, zoneFromDB, isZoneChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteZone(kt, opt, delCloudIDs); err != nil {
@@ -166,6 +166,10 @@ func (cli *client) updateZone(kt *kit.Kit, opt *SyncZoneOption,
 }
 
 func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("zone delCloudIDs is <= 0, not delete")
 	}
@@ -81,7 +81,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.HuaWeiCvmWrapper, corecvm.Cvm[cvm.HuaWeiCvmExtension]](
-		cvmFromCloud, cvmFromDB, cli.isCvmChange)
+		kt, cvmFromCloud, cvmFromDB, cli.isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -417,6 +417,10 @@ func (cli *client) getSubnets(kt *kit.Kit, accountID, region, serverID string,
 }
 
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
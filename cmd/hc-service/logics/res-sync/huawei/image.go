@@ -71,7 +71,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.HuaWeiImage, coreimage.Image[coreimage.HuaWeiExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs, opt.Platform); err != nil {
@@ -170,6 +170,10 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, delCloudIDs []string,
 	platform model.ListImagesRequestPlatform) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
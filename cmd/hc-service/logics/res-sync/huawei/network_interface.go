@@ -114,7 +114,7 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesni.HuaWeiNI, coreni.
-		NetworkInterface[coreni.HuaWeiNIExtension]](networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
+		NetworkInterface[coreni.HuaWeiNIExtension]](kt, networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, delCloudIDs, opt); err != nil {
@@ -140,6 +140,10 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 // deleteNetworkInterface 删除网络接口
 func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt *syncNIOption) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if err := opt.Validate(); err != nil {
 		return errf.NewFromErr(errf.InvalidParameter, err)
 	}
@@ -122,7 +122,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.HuaWeiRoute,
-		routetable.HuaWeiRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.HuaWeiRoute](kt, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.Region, opt.CloudRouteTableID, routeTable.ID, delCloudIDs,
@@ -225,6 +225,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, region, routeTableID stri
 func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID string,
 	delCloudIDs []string, routeFromDB []routetable.HuaWeiRoute) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -71,7 +71,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.HuaWeiSubnet,
-		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
+		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](kt, subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, opt.CloudVpcID, delCloudIDs); err != nil {
@@ -167,6 +167,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, region, c
 
 // deleteSubnet delete subnet from db
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region, cloudVpcID string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud/cvm"
 	"hcm/pkg/api/data-service/cloud"
@@ -79,6 +80,10 @@ func (cli *client) RemoveHostByCCInfo(kt *kit.Kit, params *DelHostParams) error
 }
 
 func (cli *client) deleteHostByHostID(kt *kit.Kit, hostIDs []int64) error {
+	if common.PlanRemove(kt, hostIDs) {
+		return nil
+	}
+
 	if len(hostIDs) == 0 {
 		return nil
 	}
@@ -385,7 +390,7 @@ func (cli *client) doHostDiff(kt *kit.Kit, ccHosts []cmdb.HostWithCloudID, dbHos
 	}
 
 	if len(updateHosts) != 0 {
-		if err := cli.updateHost(kt, updateHosts); err != nil {
+		if err := cli.updateHost(kt, updateHosts, dbHosts); err != nil {
 			logs.Errorf("update host id failed, err: %v, data: %+v, rid: %s", err, updateHosts, kt.Rid)
 			return err
 		}
@@ -527,6 +532,15 @@ func (cli *client) createHost(kt *kit.Kit, hosts []cloud.CvmBatchCreate[cvm.Othe
 		return nil
 	}
 
+	if common.PlanFromKit(kt) != nil {
+		cloudIDs := make([]string, 0, len(hosts))
+		for _, host := range hosts {
+			cloudIDs = append(cloudIDs, host.CloudID)
+		}
+		common.PlanAdd(kt, cloudIDs)
+		return nil
+	}
+
 	for _, batch := range slice.Split(hosts, constant.BatchOperationMaxLimit) {
 		createReq := &cloud.CvmBatchCreateReq[cvm.OtherCvmExtension]{Cvms: batch}
 		if _, err := cli.dbCli.Other.Cvm.BatchCreateCvm(kt, createReq); err != nil {
@@ -538,11 +552,29 @@ func (cli *client) createHost(kt *kit.Kit, hosts []cloud.CvmBatchCreate[cvm.Othe
 	return nil
 }
 
-func (cli *client) updateHost(kt *kit.Kit, updateData []cloud.CvmCommonInfoBatchUpdateData) error {
+func (cli *client) updateHost(kt *kit.Kit, updateData []cloud.CvmCommonInfoBatchUpdateData,
+	dbHosts []cvm.BaseCvm) error {
+
 	if len(updateData) == 0 {
 		return nil
 	}
 
+	planned := common.PlanUpdate(kt, func() []common.UpdatedResource {
+		dbHostMap := make(map[string]cvm.BaseCvm, len(dbHosts))
+		for _, host := range dbHosts {
+			dbHostMap[host.ID] = host
+		}
+		updated := make([]common.UpdatedResource, 0, len(updateData))
+		for _, one := range updateData {
+			dbHost := dbHostMap[one.ID]
+			updated = append(updated, common.NewUpdatedResource(one.ID, dbHost.CloudID, one, dbHost))
+		}
+		return updated
+	})
+	if planned {
+		return nil
+	}
+
 	for _, batch := range slice.Split(updateData, constant.BatchOperationMaxLimit) {
 		update := &cloud.CvmCommonInfoBatchUpdateReq{Cvms: batch}
 		if err := cli.dbCli.Global.Cvm.BatchUpdateCvmCommonInfo(kt, update); err != nil {
@@ -88,7 +88,7 @@ func (cli *client) ArgsTplAddress(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 
 	// 对比云端和数据库数据，获取需要新增、更新、删除的数据
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplAddress,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeAddress)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, fromCloud, fromDB, isChangeAddress)
 
 	logs.Infof("[%s] hcservice sync argument template diff address success, addNum: %d, updateNum: %d, delNum: %d, "+
 		"rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -120,6 +120,10 @@ func (cli *client) ArgsTplAddress(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 // deleteAddress 删除地址模板
 // 先验证云端确实不存在这些资源，然后从数据库中删除
 func (cli *client) deleteAddress(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -553,7 +557,7 @@ func (cli *client) ArgsTplAddressGroup(kt *kit.Kit, params *SyncBaseParams, opt
 
 	// 对比云端和数据库数据，获取需要新增、更新、删除的数据
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplAddressGroup,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeAddressGroup)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, fromCloud, fromDB, isChangeAddressGroup)
 
 	logs.Infof("[%s] hcservice sync argument template diff address group success, addNum: %d, updateNum: %d, "+
 		"delNum: %d, rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -580,6 +584,10 @@ func (cli *client) ArgsTplAddressGroup(kt *kit.Kit, params *SyncBaseParams, opt
 }
 
 func (cli *client) deleteAddressGroup(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -811,7 +819,7 @@ func (cli *client) ArgsTplService(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplService,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeService)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, fromCloud, fromDB, isChangeService)
 
 	logs.Infof("[%s] hcservice sync argument template diff service success, addNum: %d, updateNum: %d, delNum: %d, "+
 		"rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -840,6 +848,10 @@ func (cli *client) ArgsTplService(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 // deleteService 删除服务模板
 // 删除前会先验证云端是否还存在这些资源，确保不会误删
 func (cli *client) deleteService(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -1097,7 +1109,7 @@ func (cli *client) ArgsTplServiceGroup(kt *kit.Kit, params *SyncBaseParams, opt
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplServiceGroup,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeServiceGroup)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, fromCloud, fromDB, isChangeServiceGroup)
 
 	logs.Infof("[%s] hcservice sync argument template diff service group success, addNum: %d, updateNum: %d, "+
 		"delNum: %d, rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -1126,6 +1138,10 @@ func (cli *client) ArgsTplServiceGroup(kt *kit.Kit, params *SyncBaseParams, opt
 // deleteServiceGroup 删除服务组模板
 // 删除前会先验证云端是否还存在这些资源，确保不会误删
 func (cli *client) deleteServiceGroup(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
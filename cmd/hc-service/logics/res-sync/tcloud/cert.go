@@ -80,7 +80,7 @@ func (cli *client) Cert(kt *kit.Kit, params *SyncBaseParams, opt *SyncCertOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typecert.TCloudCert, *corecert.Cert[corecert.TCloudCertExtension]](
-		certFromCloud, certFromDB, isCertChange)
+		kt, certFromCloud, certFromDB, isCertChange)
 
 	if err = cli.deleteCert(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
 		return nil, err
@@ -98,6 +98,10 @@ func (cli *client) Cert(kt *kit.Kit, params *SyncBaseParams, opt *SyncCertOption
 }
 
 func (cli *client) deleteCert(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return nil
 	}
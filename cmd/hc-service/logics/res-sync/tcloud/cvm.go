@@ -72,7 +72,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.TCloudCvm, corecvm.Cvm[cvm.TCloudCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -319,6 +319,10 @@ func buildCvmCreateReq(one typescvm.TCloudCvm, extension *corecvm.TCloudCvmExten
 
 // deleteCvm deletes CVMs from the database.
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -68,7 +68,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.TCloudImage, coreimage.Image[coreimage.TCloudExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -164,6 +164,10 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 }
 
 func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
@@ -240,7 +240,7 @@ func (cli *client) listener(kt *kit.Kit, params *SyncBaseParams, opt *SyncListen
 		cloudListeners[i].Region = params.Region
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.TCloudListener, corelb.TCloudListener](
-		cloudListeners, dbListeners, isListenerChange)
+		kt, cloudListeners, dbListeners, isListenerChange)
 
 	// 删除云上已经删除的监听器实例
 	if len(delCloudIDs) != 0 {
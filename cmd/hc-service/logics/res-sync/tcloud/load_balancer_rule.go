@@ -108,7 +108,7 @@ func (cli *client) LoadBalancerLayer4Rule(kt *kit.Kit, params *SyncBaseParams, o
 
 	// 新增实例应该在同步监听器的时候附带创建，云上已删除的规则应该在监听器同步时被删除
 	_, updateMap, _ := common.Diff[typeslb.TCloudListener, corelb.TCloudLbUrlRule](
-		l4Listeners, dbRules, isLayer4RuleChange)
+		kt, l4Listeners, dbRules, isLayer4RuleChange)
 
 	// 更新变更监听器，更新对应四层/七层 规则
 	if err = cli.updateLayer4Rule(kt, params, opt, updateMap); err != nil {
@@ -142,7 +142,7 @@ func (cli *client) ListenerLayer7Rule(kt *kit.Kit, params *SyncBaseParams, opt *
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.TCloudUrlRule, corelb.TCloudLbUrlRule](
-		cloudRules, dbRules, isLayer7RuleChange)
+		kt, cloudRules, dbRules, isLayer7RuleChange)
 
 	if len(delCloudIDs) != 0 {
 		if err = cli.deleteLayer7Rule(kt, params.Region, delCloudIDs); err != nil {
@@ -75,7 +75,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 
 	addSlice, updateMap, delCloudIDs := common.Diff[
 		securitygroup.TCloudSG, cloudcore.SecurityGroup[cloudcore.TCloudSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSG(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -219,6 +219,10 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, region string,
 
 // deleteSG delete security group in database
 func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sg delCloudIDs is <= 0, not delete")
 	}
@@ -20,6 +20,9 @@
 package tcloud
 
 import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	securitygrouprule "hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
@@ -157,7 +160,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		}
 	}
 	if len(updateRules) != 0 {
-		if err = cli.updateSGRule(kt, sg.ID, updateRules); err != nil {
+		if err = cli.updateSGRule(kt, sg.ID, updateRules, rulesFromDB); err != nil {
 			return nil, err
 		}
 	}
@@ -275,7 +278,7 @@ func (cli *client) listSGRuleFromCloud(kt *kit.Kit, region, cloudSGID string) (s
 
 // updateSGRule update security group rule
 func (cli *client) updateSGRule(kt *kit.Kit, sgID string, updateRules map[string]*corecloud.
-	TCloudSecurityGroupRule) error {
+	TCloudSecurityGroupRule, rulesFromDB []corecloud.TCloudSecurityGroupRule) error {
 
 	// convert update rules map to rule slice
 	ruleSlice := make([]protocloud.TCloudSGRuleBatchUpdate, 0, len(updateRules))
@@ -308,6 +311,23 @@ func (cli *client) updateSGRule(kt *kit.Kit, sgID string, updateRules map[string
 			AccountID:                  rule.AccountID,
 		})
 	}
+
+	planned := common.PlanUpdate(kt, func() []common.UpdatedResource {
+		dbRuleMap := make(map[string]corecloud.TCloudSecurityGroupRule, len(rulesFromDB))
+		for _, one := range rulesFromDB {
+			dbRuleMap[one.ID] = one
+		}
+		updated := make([]common.UpdatedResource, 0, len(ruleSlice))
+		for _, one := range ruleSlice {
+			key := planSGRuleKey(one.CloudSecurityGroupID, one.Type, one.CloudPolicyIndex)
+			updated = append(updated, common.NewUpdatedResource(one.ID, key, one, dbRuleMap[one.ID]))
+		}
+		return updated
+	})
+	if planned {
+		return nil
+	}
+
 	// split rules into batches to avoid reaching batch operation limit
 	ruleBatches := slice.Split(ruleSlice, constant.BatchOperationMaxLimit)
 	for batchIdx, updateRuleBatch := range ruleBatches {
@@ -325,6 +345,10 @@ func (cli *client) updateSGRule(kt *kit.Kit, sgID string, updateRules map[string
 // deleteSGRule delete security group rule
 func (cli *client) deleteSGRule(kt *kit.Kit, sgID string, delIDs []string) error {
 
+	if common.PlanRemove(kt, delIDs) {
+		return nil
+	}
+
 	// split rules into batches to avoid reaching batch operation limit
 	delIdBatches := slice.Split(delIDs, constant.BatchOperationMaxLimit)
 	for batchIdx, delIdBatch := range delIdBatches {
@@ -347,6 +371,15 @@ func (cli *client) deleteSGRule(kt *kit.Kit, sgID string, delIDs []string) error
 func (cli *client) createSGRule(kt *kit.Kit, sgID string, allRules []corecloud.
 	TCloudSecurityGroupRule) ([]string, error) {
 
+	if common.PlanFromKit(kt) != nil {
+		planKeys := make([]string, 0, len(allRules))
+		for _, rule := range allRules {
+			planKeys = append(planKeys, planSGRuleKey(rule.CloudSecurityGroupID, rule.Type, rule.CloudPolicyIndex))
+		}
+		common.PlanAdd(kt, planKeys)
+		return make([]string, 0), nil
+	}
+
 	// split all rules into batches to avoid reaching batch operation limit
 	splitRuleBatches := slice.Split(allRules, constant.BatchOperationMaxLimit)
 	resultIds := make([]string, 0, len(allRules))
@@ -392,6 +425,11 @@ func (cli *client) createSGRule(kt *kit.Kit, sgID string, allRules []corecloud.
 	return resultIds, nil
 }
 
+// planSGRuleKey 安全组规则没有云ID，计划中以 云安全组ID/规则类型/规则索引 标识规则
+func planSGRuleKey(cloudSGID string, ruleType enumor.SecurityGroupRuleType, policyIndex int64) string {
+	return fmt.Sprintf("%s/%s/%d", cloudSGID, ruleType, policyIndex)
+}
+
 func convTCloudRule(policy *vpc.SecurityGroupPolicy, sg *corecloud.BaseSecurityGroup, version string,
 	ruleType enumor.SecurityGroupRuleType,
 	argsTplMap map[string]coreargstpl.BaseArgsTpl) *corecloud.TCloudSecurityGroupRule {
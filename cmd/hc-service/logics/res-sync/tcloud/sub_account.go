@@ -75,7 +75,7 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[account.TCloudAccount,
-		coresubaccount.SubAccount[coresubaccount.TCloudExtension]](fromCloud, fromDB, isSubAccountChange)
+		coresubaccount.SubAccount[coresubaccount.TCloudExtension]](kt, fromCloud, fromDB, isSubAccountChange)
 
 	account, err := cli.dbCli.TCloud.Account.Get(kt.Ctx, kt.Header(), opt.AccountID)
 	if err != nil {
@@ -239,6 +239,10 @@ func (cli *client) createSubAccount(kt *kit.Kit,
 func (cli *client) deleteSubAccount(
 	kt *kit.Kit, opt *SyncSubAccountOption, mainAccountID string, delCloudIDs []string) error {
 
+	if common.PlanRemove(kt, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("delCloudIDs is required")
 	}
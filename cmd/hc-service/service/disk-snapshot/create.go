@@ -0,0 +1,142 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateDiskSnapshot 为云盘创建快照，创建成功后同步到db
+func (svc *service) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	disk, err := svc.getDisk(cts.Kit, vendor, req.DiskID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &typesdisksnapshot.CreateOption{
+		Region:      disk.Region,
+		Zone:        disk.Zone,
+		CloudDiskID: disk.CloudID,
+		DiskName:    disk.Name,
+		Name:        req.Name,
+		Memo:        req.Memo,
+	}
+	if vendor == enumor.Azure {
+		if opt.ResourceGroupName, err = svc.getAzureDiskResGroup(cts.Kit, disk.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := svc.snapshotClient(cts.Kit, vendor, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudID, err := client.CreateDiskSnapshot(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("create %s disk snapshot failed, err: %v, disk: %s, rid: %s", vendor, err, disk.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	syncOpt := &syncOption{
+		Vendor:            vendor,
+		AccountID:         disk.AccountID,
+		Region:            disk.Region,
+		ResourceGroupName: opt.ResourceGroupName,
+		CloudIDs:          []string{cloudID},
+		PolicyID:          req.PolicyID,
+	}
+	if err = svc.syncDiskSnapshot(cts.Kit, syncOpt); err != nil {
+		logs.Errorf("sync disk snapshot after create failed, err: %v, cloud_id: %s, rid: %s", err, cloudID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, err := svc.listSnapshotIDByCloudID(cts.Kit, vendor, disk.AccountID, []string{cloudID})
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot: %s not synced", cloudID)
+	}
+
+	return &core.CreateResult{ID: ids[0]}, nil
+}
+
+func (svc *service) getDisk(kt *kit.Kit, vendor enumor.Vendor, diskID string) (*coredisk.BaseDisk, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", diskID),
+			tools.RuleEqual("vendor", vendor),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.ListDisk(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk failed, err: %v, id: %s, rid: %s", err, diskID, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk: %s not found", diskID)
+	}
+
+	return result.Details[0], nil
+}
+
+func (svc *service) getAzureDiskResGroup(kt *kit.Kit, diskID string) (string, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", diskID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Azure.ListDisk(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list azure disk failed, err: %v, id: %s, rid: %s", err, diskID, kt.Rid)
+		return "", err
+	}
+
+	if len(result.Details) == 0 || result.Details[0].Extension == nil {
+		return "", errf.Newf(errf.RecordNotFound, "azure disk: %s not found", diskID)
+	}
+
+	return result.Details[0].Extension.ResourceGroupName, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataservice "hcm/pkg/api/data-service"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// BatchDeleteDiskSnapshot 删除云上快照，并删除db记录
+func (svc *service) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleIn("id", req.IDs),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dataCli.Global.ListDiskSnapshot(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) != len(req.IDs) {
+		return nil, errf.Newf(errf.RecordNotFound, "some disk snapshots not found, ids: %v", req.IDs)
+	}
+
+	// 云上删除按账号、地域、资源组分组进行
+	type groupKey struct {
+		AccountID         string
+		Region            string
+		ResourceGroupName string
+	}
+	groups := make(map[groupKey][]coredisksnapshot.DiskSnapshot)
+	for _, one := range result.Details {
+		key := groupKey{AccountID: one.AccountID, Region: one.Region}
+		if one.Extension != nil {
+			key.ResourceGroupName = one.Extension.ResourceGroupName
+		}
+		groups[key] = append(groups[key], one)
+	}
+
+	for key, snapshots := range groups {
+		client, err := svc.snapshotClient(cts.Kit, vendor, key.AccountID)
+		if err != nil {
+			return nil, err
+		}
+
+		opt := &typesdisksnapshot.DeleteOption{
+			Region:            key.Region,
+			ResourceGroupName: key.ResourceGroupName,
+			CloudIDs:          make([]string, 0, len(snapshots)),
+		}
+		ids := make([]string, 0, len(snapshots))
+		for _, one := range snapshots {
+			opt.CloudIDs = append(opt.CloudIDs, one.CloudID)
+			ids = append(ids, one.ID)
+		}
+
+		if err = client.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+			logs.Errorf("delete %s disk snapshot failed, err: %v, ids: %v, rid: %s", vendor, err, ids, cts.Kit.Rid)
+			return nil, err
+		}
+
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+		if err = svc.dataCli.Global.BatchDeleteDiskSnapshot(cts.Kit, delReq); err != nil {
+			logs.Errorf("delete disk snapshot from db failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot 云盘快照
+package disksnapshot
+
+import (
+	"fmt"
+	"net/http"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the disk snapshot service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		ad:      cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateDiskSnapshot", http.MethodPost, "/vendors/{vendor}/disk_snapshots/create", svc.CreateDiskSnapshot)
+	h.Add("SyncDiskSnapshot", http.MethodPost, "/vendors/{vendor}/disk_snapshots/sync", svc.SyncDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/vendors/{vendor}/disk_snapshots/batch",
+		svc.BatchDeleteDiskSnapshot)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	ad      *cloudclient.CloudAdaptorClient
+	dataCli *dataservice.Client
+}
+
+// snapshotClient 各云厂商 adaptor 实现的云盘快照接口
+type snapshotClient interface {
+	CreateDiskSnapshot(kt *kit.Kit, opt *typesdisksnapshot.CreateOption) (string, error)
+	ListDiskSnapshot(kt *kit.Kit, opt *typesdisksnapshot.ListOption) ([]typesdisksnapshot.DiskSnapshot, error)
+	DeleteDiskSnapshot(kt *kit.Kit, opt *typesdisksnapshot.DeleteOption) error
+}
+
+func (svc *service) snapshotClient(kt *kit.Kit, vendor enumor.Vendor, accountID string) (snapshotClient, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.ad.TCloud(kt, accountID)
+	case enumor.Aws:
+		return svc.ad.Aws(kt, accountID)
+	case enumor.HuaWei:
+		return svc.ad.HuaWei(kt, accountID)
+	case enumor.Gcp:
+		return svc.ad.Gcp(kt, accountID)
+	case enumor.Azure:
+		return svc.ad.Azure(kt, accountID)
+	default:
+		return nil, fmt.Errorf("%s does not support disk snapshot", vendor)
+	}
+}
@@ -0,0 +1,369 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncDiskSnapshot 同步云盘快照
+func (svc *service) SyncDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.SyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &syncOption{
+		Vendor:            vendor,
+		AccountID:         req.AccountID,
+		Region:            req.Region,
+		ResourceGroupName: req.ResourceGroupName,
+		CloudIDs:          req.CloudIDs,
+	}
+	if err := svc.syncDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("sync %s disk snapshot failed, err: %v, req: %+v, rid: %s", vendor, err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type syncOption struct {
+	Vendor            enumor.Vendor
+	AccountID         string
+	Region            string
+	ResourceGroupName string
+	// CloudIDs 为空时同步整个地域(azure 为资源组)下的快照
+	CloudIDs []string
+	// PolicyID 新增快照关联的快照策略
+	PolicyID string
+}
+
+func (svc *service) syncDiskSnapshot(kt *kit.Kit, opt *syncOption) error {
+	client, err := svc.snapshotClient(kt, opt.Vendor, opt.AccountID)
+	if err != nil {
+		return err
+	}
+
+	listOpt := &typesdisksnapshot.ListOption{
+		Region:            opt.Region,
+		ResourceGroupName: opt.ResourceGroupName,
+		CloudIDs:          opt.CloudIDs,
+	}
+	fromCloud, err := client.ListDiskSnapshot(kt, listOpt)
+	if err != nil {
+		logs.Errorf("list disk snapshot from cloud failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	fromDB, err := svc.listSnapshotFromDB(kt, opt)
+	if err != nil {
+		return err
+	}
+
+	addSlice, updateMap, delCloudIDs := common.Diff[typesdisksnapshot.DiskSnapshot, coredisksnapshot.DiskSnapshot](
+		kt, fromCloud, fromDB, isSnapshotChange)
+
+	if len(delCloudIDs) > 0 {
+		if err = svc.deleteSnapshotByCloudID(kt, opt.Vendor, opt.AccountID, delCloudIDs); err != nil {
+			return err
+		}
+	}
+
+	if len(addSlice) == 0 && len(updateMap) == 0 {
+		return nil
+	}
+
+	diskIDMap, err := svc.getDiskIDMap(kt, opt.Vendor, opt.AccountID, append(addSlice,
+		converter.MapValueToSlice(updateMap)...))
+	if err != nil {
+		return err
+	}
+
+	if len(addSlice) > 0 {
+		if err = svc.createSnapshot(kt, opt, addSlice, diskIDMap); err != nil {
+			return err
+		}
+	}
+
+	if len(updateMap) > 0 {
+		if err = svc.updateSnapshot(kt, updateMap, diskIDMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSnapshotChange(cloud typesdisksnapshot.DiskSnapshot, db coredisksnapshot.DiskSnapshot) bool {
+	if cloud.Name != db.Name || cloud.Status != db.Status || cloud.DiskSize != db.DiskSize {
+		return true
+	}
+
+	if cloud.Encrypted != converter.PtrToVal(db.Encrypted) {
+		return true
+	}
+
+	if converter.PtrToVal(cloud.Memo) != converter.PtrToVal(db.Memo) {
+		return true
+	}
+
+	return converter.PtrToVal(cloud.Extension) != converter.PtrToVal(convExtension(db.Extension))
+}
+
+func convExtension(ext *coredisksnapshot.Extension) *typesdisksnapshot.Extension {
+	if ext == nil {
+		return nil
+	}
+
+	return &typesdisksnapshot.Extension{ResourceGroupName: ext.ResourceGroupName, SelfLink: ext.SelfLink}
+}
+
+func convCoreExtension(ext *typesdisksnapshot.Extension) *coredisksnapshot.Extension {
+	if ext == nil {
+		return nil
+	}
+
+	return &coredisksnapshot.Extension{ResourceGroupName: ext.ResourceGroupName, SelfLink: ext.SelfLink}
+}
+
+// diskInfo 快照源云盘在db中的信息
+type diskInfo struct {
+	ID      string
+	BkBizID int64
+}
+
+func (svc *service) getDiskIDMap(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	snapshots []typesdisksnapshot.DiskSnapshot) (map[string]diskInfo, error) {
+
+	cloudDiskIDs := make([]string, 0, len(snapshots))
+	for _, one := range snapshots {
+		if len(one.CloudDiskID) != 0 {
+			cloudDiskIDs = append(cloudDiskIDs, one.CloudDiskID)
+		}
+	}
+
+	result := make(map[string]diskInfo)
+	for _, batch := range slice.Split(slice.Unique(cloudDiskIDs), int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", vendor),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleIn("cloud_id", batch),
+			),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"id", "cloud_id", "bk_biz_id"},
+		}
+		disks, err := svc.dataCli.Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range disks.Details {
+			result[one.CloudID] = diskInfo{ID: one.ID, BkBizID: one.BkBizID}
+		}
+	}
+
+	return result, nil
+}
+
+func (svc *service) createSnapshot(kt *kit.Kit, opt *syncOption, addSlice []typesdisksnapshot.DiskSnapshot,
+	diskIDMap map[string]diskInfo) error {
+
+	for _, batch := range slice.Split(addSlice, constant.BatchOperationMaxLimit) {
+		createReq := &protocloud.DiskSnapshotBatchCreateReq{
+			Snapshots: make([]protocloud.DiskSnapshotCreate, 0, len(batch)),
+		}
+		for _, one := range batch {
+			disk, exist := diskIDMap[one.CloudDiskID]
+			bizID := int64(constant.UnassignedBiz)
+			if exist {
+				bizID = disk.BkBizID
+			}
+			createReq.Snapshots = append(createReq.Snapshots, protocloud.DiskSnapshotCreate{
+				Vendor:           opt.Vendor,
+				AccountID:        opt.AccountID,
+				CloudID:          one.CloudID,
+				BkBizID:          bizID,
+				Name:             one.Name,
+				Region:           one.Region,
+				Zone:             one.Zone,
+				DiskID:           disk.ID,
+				CloudDiskID:      one.CloudDiskID,
+				DiskSize:         one.DiskSize,
+				Status:           one.Status,
+				Encrypted:        one.Encrypted,
+				PolicyID:         opt.PolicyID,
+				Memo:             one.Memo,
+				CloudCreatedTime: one.CloudCreatedTime,
+				Extension:        convCoreExtension(one.Extension),
+			})
+		}
+
+		if _, err := svc.dataCli.Global.BatchCreateDiskSnapshot(kt, createReq); err != nil {
+			logs.Errorf("batch create disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) updateSnapshot(kt *kit.Kit, updateMap map[string]typesdisksnapshot.DiskSnapshot,
+	diskIDMap map[string]diskInfo) error {
+
+	updates := make([]protocloud.DiskSnapshotUpdate, 0, len(updateMap))
+	for id, one := range updateMap {
+		update := protocloud.DiskSnapshotUpdate{
+			ID:        id,
+			Name:      one.Name,
+			DiskSize:  one.DiskSize,
+			Status:    one.Status,
+			Encrypted: converter.ValToPtr(one.Encrypted),
+			Memo:      one.Memo,
+			Extension: convCoreExtension(one.Extension),
+		}
+		if disk, exist := diskIDMap[one.CloudDiskID]; exist {
+			update.DiskID = converter.ValToPtr(disk.ID)
+		}
+		updates = append(updates, update)
+	}
+
+	for _, batch := range slice.Split(updates, constant.BatchOperationMaxLimit) {
+		updateReq := &protocloud.DiskSnapshotBatchUpdateReq{Snapshots: batch}
+		if err := svc.dataCli.Global.BatchUpdateDiskSnapshot(kt, updateReq); err != nil {
+			logs.Errorf("batch update disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) deleteSnapshotByCloudID(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	cloudIDs []string) error {
+
+	for _, batch := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		delReq := &dataservice.BatchDeleteReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("vendor", vendor),
+				tools.RuleEqual("account_id", accountID),
+				tools.RuleIn("cloud_id", batch),
+			),
+		}
+		if err := svc.dataCli.Global.BatchDeleteDiskSnapshot(kt, delReq); err != nil {
+			logs.Errorf("batch delete disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) listSnapshotFromDB(kt *kit.Kit, opt *syncOption) ([]coredisksnapshot.DiskSnapshot, error) {
+	rules := []filter.RuleFactory{
+		tools.RuleEqual("vendor", opt.Vendor),
+		tools.RuleEqual("account_id", opt.AccountID),
+		tools.RuleEqual("region", opt.Region),
+	}
+	if len(opt.CloudIDs) != 0 {
+		rules = append(rules, tools.RuleIn("cloud_id", opt.CloudIDs))
+	}
+
+	listReq := &core.ListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: rules},
+		Page:   core.NewDefaultBasePage(),
+	}
+	snapshots := make([]coredisksnapshot.DiskSnapshot, 0)
+	for {
+		result, err := svc.dataCli.Global.ListDiskSnapshot(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot from db failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			// azure 快照按资源组同步，过滤掉其他资源组的快照
+			if len(opt.ResourceGroupName) != 0 && (one.Extension == nil ||
+				one.Extension.ResourceGroupName != opt.ResourceGroupName) {
+				continue
+			}
+			snapshots = append(snapshots, one)
+		}
+
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			break
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return snapshots, nil
+}
+
+func (svc *service) listSnapshotIDByCloudID(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	cloudIDs []string) ([]string, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleIn("cloud_id", cloudIDs),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	result, err := svc.dataCli.Global.ListDiskSnapshot(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(result.Details))
+	for _, one := range result.Details {
+		ids = append(ids, one.ID)
+	}
+
+	return ids, nil
+}
@@ -24,6 +24,7 @@ import (
 	"hcm/cmd/hc-service/service/disk/datasvc"
 	"hcm/pkg/adaptor/types/disk"
 	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -53,6 +54,15 @@ func (svc *service) CreateAwsDisk(cts *rest.Contexts) (interface{}, error) {
 		DiskSize:  diskSize,
 		DiskCount: converter.ValToPtr(uint64(req.DiskCount)),
 	}
+
+	if len(req.SnapshotID) != 0 {
+		snapshot, err := svc.getRestoreSnapshot(cts.Kit, enumor.Aws, req.AccountID, req.SnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		opt.SnapshotID = converter.ValToPtr(snapshot.CloudID)
+	}
+
 	result, err := client.CreateDisk(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("create aws cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
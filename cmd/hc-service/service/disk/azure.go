@@ -24,6 +24,7 @@ import (
 	"hcm/cmd/hc-service/service/disk/datasvc"
 	"hcm/pkg/adaptor/types/disk"
 	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -55,6 +56,15 @@ func (svc *service) CreateAzureDisk(cts *rest.Contexts) (interface{}, error) {
 		DiskSize:          diskSize,
 		DiskCount:         converter.ValToPtr(uint64(req.DiskCount)),
 	}
+
+	if len(req.SnapshotID) != 0 {
+		snapshot, err := svc.getRestoreSnapshot(cts.Kit, enumor.Azure, req.AccountID, req.SnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		opt.SourceSnapshotID = snapshot.CloudID
+	}
+
 	cloudIDs, err := client.CreateDisk(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("create azure cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
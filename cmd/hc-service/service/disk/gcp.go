@@ -24,6 +24,7 @@ import (
 	"hcm/cmd/hc-service/service/disk/datasvc"
 	"hcm/pkg/adaptor/types/disk"
 	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
@@ -54,6 +55,18 @@ func (svc *service) CreateGcpDisk(cts *rest.Contexts) (interface{}, error) {
 		DiskSize:  diskSize,
 		DiskCount: converter.ValToPtr(uint64(req.DiskCount)),
 	}
+
+	if len(req.SnapshotID) != 0 {
+		snapshot, err := svc.getRestoreSnapshot(cts.Kit, enumor.Gcp, req.AccountID, req.SnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot.Extension == nil || len(snapshot.Extension.SelfLink) == 0 {
+			return nil, errf.Newf(errf.InvalidParameter, "disk snapshot: %s self link is empty", req.SnapshotID)
+		}
+		opt.SourceSnapshot = snapshot.Extension.SelfLink
+	}
+
 	result, err := client.CreateDisk(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("create gcp cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
@@ -24,9 +24,11 @@ import (
 	"hcm/cmd/hc-service/service/disk/datasvc"
 	"hcm/pkg/adaptor/types/disk"
 	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 )
 
 // InquiryPriceHuaWeiDisk ...
@@ -106,6 +108,14 @@ func (svc *service) CreateHuaWeiDisk(cts *rest.Contexts) (interface{}, error) {
 		}
 	}
 
+	if len(req.SnapshotID) != 0 {
+		snapshot, err := svc.getRestoreSnapshot(cts.Kit, enumor.HuaWei, req.AccountID, req.SnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		opt.SnapshotID = converter.ValToPtr(snapshot.CloudID)
+	}
+
 	result, err := client.CreateDisk(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("create huawei disk failed, err: %v, rid: %s", err, cts.Kit.Rid)
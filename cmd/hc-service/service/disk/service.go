@@ -24,7 +24,14 @@ import (
 
 	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
 	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
 
@@ -76,3 +83,29 @@ type service struct {
 	DataCli *dataservice.Client
 	Adaptor *cloudclient.CloudAdaptorClient
 }
+
+// getRestoreSnapshot 查询用于创建云盘的快照，并校验快照与云盘属于同一账号
+func (svc *service) getRestoreSnapshot(kt *kit.Kit, vendor enumor.Vendor, accountID, snapshotID string) (
+	*coredisksnapshot.DiskSnapshot, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", snapshotID),
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("account_id", accountID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.DataCli.Global.ListDiskSnapshot(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, id: %s, rid: %s", err, snapshotID, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk snapshot: %s not found in account: %s", snapshotID,
+			accountID)
+	}
+
+	return &result.Details[0], nil
+}
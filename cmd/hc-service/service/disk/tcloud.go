@@ -24,9 +24,11 @@ import (
 	"hcm/cmd/hc-service/service/disk/datasvc"
 	"hcm/pkg/adaptor/types/disk"
 	proto "hcm/pkg/api/hc-service/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 )
 
 // InquiryPriceTCloudDisk inquiry price tcloud disk.
@@ -104,6 +106,14 @@ func (svc *service) CreateTCloudDisk(cts *rest.Contexts) (interface{}, error) {
 		}
 	}
 
+	if len(req.SnapshotID) != 0 {
+		snapshot, err := svc.getRestoreSnapshot(cts.Kit, enumor.TCloud, req.AccountID, req.SnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		opt.SnapshotID = converter.ValToPtr(snapshot.CloudID)
+	}
+
 	result, err := client.CreateDisk(cts.Kit, opt)
 	if err != nil {
 		logs.Errorf("create tcloud cvm failed, err: %v, rid: %s", err, cts.Kit.Rid)
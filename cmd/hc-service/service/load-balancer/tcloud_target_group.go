@@ -611,6 +611,7 @@ func (svc *clbSvc) ListTCloudTargetsHealth(cts *rest.Contexts) (any, error) {
 			}
 			for _, ruleItem := range lblItem.Rules {
 				var healthNum, unHealthNum int64
+				targets := make([]*protolb.TCloudTargetHealthRsResult, 0, len(ruleItem.Targets))
 				for _, targetItem := range ruleItem.Targets {
 					// 当前健康状态，true：健康，false：不健康（包括尚未开始探测、探测中、状态异常等几种状态）。
 					if cvt.PtrToVal(targetItem.HealthStatus) {
@@ -618,6 +619,13 @@ func (svc *clbSvc) ListTCloudTargetsHealth(cts *rest.Contexts) (any, error) {
 					} else {
 						unHealthNum++
 					}
+					targets = append(targets, &protolb.TCloudTargetHealthRsResult{
+						CloudInstID:        cvt.PtrToVal(targetItem.TargetId),
+						IP:                 cvt.PtrToVal(targetItem.IP),
+						Port:               cvt.PtrToVal(targetItem.Port),
+						HealthStatus:       cvt.PtrToVal(targetItem.HealthStatus),
+						HealthStatusDetail: cvt.PtrToVal(targetItem.HealthStatusDetail),
+					})
 				}
 
 				if !tmpListener.Protocol.IsLayer7Protocol() {
@@ -625,6 +633,7 @@ func (svc *clbSvc) ListTCloudTargetsHealth(cts *rest.Contexts) (any, error) {
 						HealthNum:   cvt.ValToPtr(healthNum),
 						UnHealthNum: cvt.ValToPtr(unHealthNum),
 					}
+					tmpListener.Targets = targets
 					break
 				} else {
 					tmpListener.Rules = append(tmpListener.Rules, &protolb.TCloudTargetHealthRuleResult{
@@ -633,6 +642,7 @@ func (svc *clbSvc) ListTCloudTargetsHealth(cts *rest.Contexts) (any, error) {
 							HealthNum:   cvt.ValToPtr(healthNum),
 							UnHealthNum: cvt.ValToPtr(unHealthNum),
 						},
+						Targets: targets,
 					})
 				}
 			}
@@ -39,6 +39,7 @@ import (
 	"hcm/cmd/hc-service/service/cos"
 	"hcm/cmd/hc-service/service/cvm"
 	"hcm/cmd/hc-service/service/disk"
+	disksnapshot "hcm/cmd/hc-service/service/disk-snapshot"
 	"hcm/cmd/hc-service/service/eip"
 	"hcm/cmd/hc-service/service/firewall"
 	"hcm/cmd/hc-service/service/image"
@@ -178,6 +179,7 @@ func (s *Service) apiSet() *restful.Container {
 	vpc.InitVpcService(c)
 	subnet.InitSubnetService(c)
 	disk.InitDiskService(c)
+	disksnapshot.InitService(c)
 	cvm.InitCvmService(c)
 	routetable.InitRouteTableService(c)
 	eip.InitEipService(c)
@@ -23,6 +23,7 @@ import (
 	"hcm/cmd/hc-service/logics/cloud-adaptor"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/rest"
@@ -40,19 +41,19 @@ func InitService(cap *capability.Capability) {
 	h := rest.NewHandler()
 	h.Path("/vendors/azure")
 
-	h.Add("SyncVpc", "POST", "/vpcs/sync", v.SyncVpc)
-	h.Add("SyncSubnet", "POST", "/subnets/sync", v.SyncSubnet)
-	h.Add("SyncEip", "POST", "/eips/sync", v.SyncEip)
-	h.Add("SyncDisk", "POST", "/disks/sync", v.SyncDisk)
-	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", v.SyncCvmWithRelRes)
-	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync", v.SyncSecurityGroup)
+	h.Add("SyncVpc", "POST", "/vpcs/sync", handler.WithPlan(v.SyncVpc))
+	h.Add("SyncSubnet", "POST", "/subnets/sync", handler.WithPlan(v.SyncSubnet))
+	h.Add("SyncEip", "POST", "/eips/sync", handler.WithPlan(v.SyncEip))
+	h.Add("SyncDisk", "POST", "/disks/sync", handler.WithPlan(v.SyncDisk))
+	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", handler.WithPlan(v.SyncCvmWithRelRes))
+	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync", handler.WithPlan(v.SyncSecurityGroup))
 	h.Add("SyncSecurityGroupUsageBiz", "POST", "/security_groups/usage_biz_rels/sync", v.SyncSecurityGroupUsageBiz)
-	h.Add("SyncNetworkInterface", "POST", "/network_interfaces/sync", v.SyncNetworkInterface)
-	h.Add("SyncRoute", "POST", "/route_tables/sync", v.SyncRouteTable)
-	h.Add("SyncResourceGroup", "POST", "/resource_groups/sync", v.SyncResourceGroup)
-	h.Add("SyncRegion", "POST", "/regions/sync", v.SyncRegion)
-	h.Add("SyncImage", "POST", "/images/sync", v.SyncImage)
-	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", v.SyncSubAccount)
+	h.Add("SyncNetworkInterface", "POST", "/network_interfaces/sync", handler.WithPlan(v.SyncNetworkInterface))
+	h.Add("SyncRoute", "POST", "/route_tables/sync", handler.WithPlan(v.SyncRouteTable))
+	h.Add("SyncResourceGroup", "POST", "/resource_groups/sync", handler.WithPlan(v.SyncResourceGroup))
+	h.Add("SyncRegion", "POST", "/regions/sync", handler.WithPlan(v.SyncRegion))
+	h.Add("SyncImage", "POST", "/images/sync", handler.WithPlan(v.SyncImage))
+	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", handler.WithPlan(v.SyncSubAccount))
 	h.Add("SyncCvmCCInfo", "POST", "/cvms/cc_info/sync", v.SyncCvmCCInfo)
 	h.Add("SyncCvmCCInfoByCond", "POST", "/cvms/cc_info/by_condition/sync", v.SyncCvmCCInfoByCond)
 
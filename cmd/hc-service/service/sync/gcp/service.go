@@ -23,6 +23,7 @@ import (
 	"hcm/cmd/hc-service/logics/cloud-adaptor"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/rest"
@@ -40,17 +41,17 @@ func InitService(cap *capability.Capability) {
 	h := rest.NewHandler()
 	h.Path("/vendors/gcp")
 
-	h.Add("SyncVpc", "POST", "/vpcs/sync", v.SyncVpc)
-	h.Add("SyncSubnet", "POST", "/subnets/sync", v.SyncSubnet)
-	h.Add("SyncDisk", "POST", "/disks/sync", v.SyncDisk)
-	h.Add("SyncFirewallRule", "POST", "/firewalls/rules/sync", v.SyncFirewallRule)
-	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", v.SyncCvmWithRelRes)
-	h.Add("SyncEip", "POST", "/eips/sync", v.SyncEip)
-	h.Add("SyncRoute", "POST", "/routes/sync", v.SyncRoute)
-	h.Add("SyncZone", "POST", "/zones/sync", v.SyncZone)
-	h.Add("SyncRegion", "POST", "/regions/sync", v.SyncRegion)
-	h.Add("SyncImage", "POST", "/images/sync", v.SyncImage)
-	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", v.SyncSubAccount)
+	h.Add("SyncVpc", "POST", "/vpcs/sync", handler.WithPlan(v.SyncVpc))
+	h.Add("SyncSubnet", "POST", "/subnets/sync", handler.WithPlan(v.SyncSubnet))
+	h.Add("SyncDisk", "POST", "/disks/sync", handler.WithPlan(v.SyncDisk))
+	h.Add("SyncFirewallRule", "POST", "/firewalls/rules/sync", handler.WithPlan(v.SyncFirewallRule))
+	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", handler.WithPlan(v.SyncCvmWithRelRes))
+	h.Add("SyncEip", "POST", "/eips/sync", handler.WithPlan(v.SyncEip))
+	h.Add("SyncRoute", "POST", "/routes/sync", handler.WithPlan(v.SyncRoute))
+	h.Add("SyncZone", "POST", "/zones/sync", handler.WithPlan(v.SyncZone))
+	h.Add("SyncRegion", "POST", "/regions/sync", handler.WithPlan(v.SyncRegion))
+	h.Add("SyncImage", "POST", "/images/sync", handler.WithPlan(v.SyncImage))
+	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", handler.WithPlan(v.SyncSubAccount))
 	h.Add("SyncCvmCCInfo", "POST", "/cvms/cc_info/sync", v.SyncCvmCCInfo)
 	h.Add("SyncCvmCCInfoByCond", "POST", "/cvms/cc_info/by_condition/sync", v.SyncCvmCCInfoByCond)
 
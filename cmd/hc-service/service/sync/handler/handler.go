@@ -59,6 +59,10 @@ func ResourceSync(cts *rest.Contexts, handler Handler) error {
 		return err
 	}
 
+	if plan := common.PlanFromKit(kt); plan != nil {
+		plan.SetResource(string(handler.Name()))
+	}
+
 	if err := handler.RemoveDeleteFromCloud(kt); err != nil {
 		logs.Errorf("%s sync handler to removeDeleteFromCloud failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
 		return err
@@ -128,6 +132,11 @@ func ResourceSyncV2[T common.CloudResType](cts *rest.Contexts, handler HandlerV2
 			handler.Describe(), err, kt.Rid)
 		return err
 	}
+
+	if plan := common.PlanFromKit(kt); plan != nil {
+		plan.SetResource(string(handler.Resource()))
+	}
+
 	// 2. 获取云上实例列表
 	logs.Infof("[ResourceSyncV2] %s sync Start with %d workers, rid: %s",
 		handler.Describe(), handler.SyncConcurrent(), kt.Rid)
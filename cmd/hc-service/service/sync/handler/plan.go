@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/rest"
+)
+
+// WithPlan 为同步接口增加计划模式支持，计划模式下返回各资源类型的同步差异
+func WithPlan(fn func(cts *rest.Contexts) (interface{}, error)) func(cts *rest.Contexts) (interface{}, error) {
+	return func(cts *rest.Contexts) (interface{}, error) {
+		if cts.Request.QueryParameter(sync.PlanQueryKey) != "true" {
+			return fn(cts)
+		}
+
+		plan := common.StartPlan(cts.Kit)
+		if _, err := fn(cts); err != nil {
+			return nil, err
+		}
+		return &sync.SyncPlanResult{Resources: plan.Resources}, nil
+	}
+}
@@ -24,6 +24,7 @@ import (
 	cloudadaptor "hcm/cmd/hc-service/logics/cloud-adaptor"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/rest"
@@ -41,9 +42,9 @@ func InitService(cap *capability.Capability) {
 	h := rest.NewHandler()
 	h.Path("/vendors/other")
 
-	h.Add("SyncHostWithRelRes", "POST", "/hosts/with/relation_resources/sync", v.SyncHostWithRelRes)
+	h.Add("SyncHostWithRelRes", "POST", "/hosts/with/relation_resources/sync", handler.WithPlan(v.SyncHostWithRelRes))
 	h.Add("SyncHostWithRelResByCond", "POST", "/hosts/with/relation_resources/by_condition/sync",
-		v.SyncHostWithRelResByCond)
+		handler.WithPlan(v.SyncHostWithRelResByCond))
 	h.Add("DeleteHost", "DELETE", "/hosts/by_condition/delete", v.DeleteHostByCond)
 
 	h.Load(cap.WebService)
@@ -0,0 +1,217 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tcloud daily puller
+package tcloud
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/cmd/task-server/logics/action/bill/dailypull/registry"
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/adaptor/types/core"
+	dsbill "hcm/pkg/api/data-service/bill"
+	hcbillservice "hcm/pkg/api/hc-service/bill"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+const (
+	tcloudMaxBill = uint64(100)
+)
+
+func init() {
+	registry.PullerRegistry[enumor.TCloud] = &TCloudPuller{}
+}
+
+// TCloudPuller tcloud puller
+type TCloudPuller struct{}
+
+// Pull pull tcloud data
+func (tp *TCloudPuller) Pull(kt run.ExecuteKit, opt *registry.PullDailyBillOption) (*registry.PullerResult, error) {
+	rootAccount, err := actcli.GetDataService().Global.RootAccount.GetBasicInfo(kt.Kit(), opt.RootAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("get root account %s failed, err %s", opt.RootAccountID, err.Error())
+	}
+	currency := rootAccount.DefaultCurrency()
+
+	offset := uint64(0)
+	count := int64(0)
+	cost := decimal.NewFromInt(0)
+	var pageContext *string
+	for {
+		itemLen, tmpResult, nextContext, err := tp.doPull(kt, opt, currency, offset, pageContext)
+		if err != nil {
+			return nil, err
+		}
+		cost = cost.Add(tmpResult.Cost)
+		count += int64(itemLen)
+		logs.Infof("get raw bill item %d / total %d of puller %+v", itemLen, tmpResult.Count, opt)
+		if uint64(itemLen) < tcloudMaxBill {
+			break
+		}
+		offset = offset + tcloudMaxBill
+		pageContext = nextContext
+	}
+	return &registry.PullerResult{
+		Count:    count,
+		Currency: currency,
+		Cost:     cost,
+	}, nil
+}
+
+func getRawBillCost(rawBills []dsbill.RawBillItem) decimal.Decimal {
+	cost := decimal.NewFromInt(0)
+	for _, bill := range rawBills {
+		cost = cost.Add(bill.BillCost)
+	}
+	return cost
+}
+
+// parseDecimal 腾讯云账单金额、用量均为字符串，为空时按0处理
+func parseDecimal(val *string) (decimal.Decimal, error) {
+	if val == nil || *val == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(*val)
+}
+
+// ConvertToRawBill 一条腾讯云账单明细对应一条原始账单，费用为各组件优惠后总价之和
+func ConvertToRawBill(currency enumor.CurrencyCode, detailList []*billing.BillDetail) ([]dsbill.RawBillItem, error) {
+	var retList []dsbill.RawBillItem
+	for _, detail := range detailList {
+		if detail == nil {
+			continue
+		}
+		realCost := decimal.NewFromFloat(0)
+		for _, component := range detail.ComponentSet {
+			if component == nil {
+				continue
+			}
+			componentCost, err := parseDecimal(component.RealCost)
+			if err != nil {
+				return nil, fmt.Errorf("parse tcloud bill component real cost %s failed, err %s",
+					cvt.PtrToVal(component.RealCost), err.Error())
+			}
+			realCost = realCost.Add(componentCost)
+		}
+		extensionBytes, err := json.Marshal(detail)
+		if err != nil {
+			return nil, fmt.Errorf("marshal tcloud bill item %v failed", detail)
+		}
+		newBillItem := dsbill.RawBillItem{
+			Region:        cvt.PtrToVal(detail.RegionId),
+			HcProductCode: cvt.PtrToVal(detail.BusinessCode),
+			HcProductName: cvt.PtrToVal(detail.BusinessCodeName),
+			BillCurrency:  currency,
+			BillCost:      realCost,
+			Extension:     types.JsonField(string(extensionBytes)),
+		}
+		// 仅单组件的明细用量有意义，多组件的用量单位各不相同
+		if len(detail.ComponentSet) == 1 && detail.ComponentSet[0] != nil {
+			amount, err := parseDecimal(detail.ComponentSet[0].UsedAmount)
+			if err != nil {
+				return nil, fmt.Errorf("parse tcloud bill used amount %s failed, err %s",
+					cvt.PtrToVal(detail.ComponentSet[0].UsedAmount), err.Error())
+			}
+			newBillItem.ResAmount = amount
+			newBillItem.ResAmountUnit = cvt.PtrToVal(detail.ComponentSet[0].UsedAmountUnit)
+		}
+		retList = append(retList, newBillItem)
+	}
+	return retList, nil
+}
+
+func (tp *TCloudPuller) createRawBill(
+	kt run.ExecuteKit, opt *registry.PullDailyBillOption,
+	filename string, billItems []dsbill.RawBillItem) error {
+
+	storeReq := &dsbill.RawBillCreateReq{
+		RawBillPathParam: dsbill.RawBillPathParam{
+			Vendor:        enumor.TCloud,
+			RootAccountID: opt.RootAccountID,
+			MainAccountID: opt.MainAccountID,
+			BillYear:      fmt.Sprintf("%d", opt.BillYear),
+			BillMonth:     fmt.Sprintf("%02d", opt.BillMonth),
+			BillDate:      fmt.Sprintf("%02d", opt.BillDay),
+			Version:       fmt.Sprintf("%d", opt.VersionID),
+			FileName:      filename,
+		},
+	}
+	storeReq.Items = billItems
+	databillCli := actcli.GetDataService().Global.Bill
+	_, err := databillCli.CreateRawBill(kt.Kit(), storeReq)
+	if err != nil {
+		return fmt.Errorf("create raw bill to dataservice failed, err %s", err.Error())
+	}
+	return nil
+}
+
+func (tp *TCloudPuller) doPull(kt run.ExecuteKit, opt *registry.PullDailyBillOption, currency enumor.CurrencyCode,
+	offset uint64, pageContext *string) (int, *registry.PullerResult, *string, error) {
+
+	billDate := fmt.Sprintf("%d-%02d-%02d", opt.BillYear, opt.BillMonth, opt.BillDay)
+	req := &hcbillservice.TCloudRootBillListReq{
+		RootAccountID:      opt.RootAccountID,
+		MainAccountCloudID: opt.MainAccountCloudID,
+		Month:              fmt.Sprintf("%d-%02d", opt.BillYear, opt.BillMonth),
+		BeginDate:          billDate + " 00:00:00",
+		EndDate:            billDate + " 23:59:59",
+		Page: &core.TCloudPage{
+			Offset: offset,
+			Limit:  tcloudMaxBill,
+		},
+		Context: pageContext,
+	}
+	resp, err := actcli.GetHCService().TCloud.Bill.ListRootBill(kt.Kit(), req)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("list tcloud root account bill failed, err %s", err.Error())
+	}
+
+	itemLen := len(resp.Details)
+	if itemLen == 0 {
+		return 0, &registry.PullerResult{
+			Count:    int64(0),
+			Currency: currency,
+			Cost:     decimal.NewFromFloat(0),
+		}, resp.Context, nil
+	}
+
+	filename := fmt.Sprintf("%d-%d.csv", offset, itemLen)
+	billItems, err := ConvertToRawBill(currency, resp.Details)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	cost := getRawBillCost(billItems)
+	if err := tp.createRawBill(kt, opt, filename, billItems); err != nil {
+		return 0, nil, nil, err
+	}
+	return itemLen, &registry.PullerResult{
+		Count:    int64(resp.Count),
+		Currency: currency,
+		Cost:     cost,
+	}, resp.Context, nil
+}
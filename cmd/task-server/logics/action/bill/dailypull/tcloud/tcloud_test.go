@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+func TestConvertToRawBill(t *testing.T) {
+	details := []*billing.BillDetail{
+		{
+			BusinessCode:     cvt.ValToPtr("p_cvm"),
+			BusinessCodeName: cvt.ValToPtr("云服务器CVM"),
+			RegionId:         cvt.ValToPtr("1"),
+			ComponentSet: []*billing.BillDetailComponent{
+				{RealCost: cvt.ValToPtr("1.25"), UsedAmount: cvt.ValToPtr("2"), UsedAmountUnit: cvt.ValToPtr("核")},
+				{RealCost: cvt.ValToPtr("0.75")},
+			},
+		},
+		{
+			BusinessCode: cvt.ValToPtr("p_cos"),
+			RegionId:     cvt.ValToPtr("4"),
+			ComponentSet: []*billing.BillDetailComponent{
+				{RealCost: cvt.ValToPtr("3.5"), UsedAmount: cvt.ValToPtr("10"), UsedAmountUnit: cvt.ValToPtr("GB")},
+			},
+		},
+	}
+
+	items, err := ConvertToRawBill(enumor.CurrencyCNY, details)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	assert.Equal(t, "p_cvm", items[0].HcProductCode)
+	assert.True(t, decimal.NewFromFloat(2).Equal(items[0].BillCost))
+	// 多组件不记录用量
+	assert.True(t, items[0].ResAmount.IsZero())
+	assert.Equal(t, "", items[0].ResAmountUnit)
+
+	assert.True(t, decimal.NewFromFloat(3.5).Equal(items[1].BillCost))
+	assert.True(t, decimal.NewFromInt(10).Equal(items[1].ResAmount))
+	assert.Equal(t, "GB", items[1].ResAmountUnit)
+	assert.Equal(t, enumor.CurrencyCNY, items[1].BillCurrency)
+
+	_, err = ConvertToRawBill(enumor.CurrencyCNY, []*billing.BillDetail{{
+		ComponentSet: []*billing.BillDetailComponent{{RealCost: cvt.ValToPtr("invalid")}},
+	}})
+	assert.Error(t, err)
+}
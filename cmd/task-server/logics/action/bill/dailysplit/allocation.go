@@ -0,0 +1,405 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"fmt"
+	"sort"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corebill "hcm/pkg/api/core/bill"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// allocationCostPrecision 分摊后金额保留的小数位数，和账单明细表 cost 字段精度保持一致
+const allocationCostPrecision = 10
+
+// allocationShare 分摊到单个业务的比例
+type allocationShare struct {
+	BkBizID int64
+	Ratio   decimal.Decimal
+}
+
+type allocationResultKey struct {
+	RuleID        string
+	SourceBkBizID int64
+	BkBizID       int64
+	Currency      enumor.CurrencyCode
+}
+
+// billItemExt 分摊规则关心的账单明细扩展字段
+type billItemExt struct {
+	ResCloudID string
+	Tags       map[string]string
+}
+
+// allocator 按分摊规则将匹配的账单明细拆分到多个业务，并汇总分摊结果
+type allocator struct {
+	vendor enumor.Vendor
+	rules  []corebill.AllocationRule
+	// usageBizRels 资源云ID与资源使用业务的关联关系
+	usageBizRels map[string][]corecloud.ResUsageBizRel
+	results      map[allocationResultKey]*bill.AllocationResultCreate
+}
+
+// newAllocator load enabled allocation rules of vendor, rules are sorted by priority.
+func newAllocator(kt *kit.Kit, vendor enumor.Vendor) (*allocator, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("enabled", true),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	rules := make([]corebill.AllocationRule, 0)
+	for {
+		result, err := actcli.GetDataService().Global.Bill.ListBillAllocationRule(kt, listReq)
+		if err != nil {
+			logs.Errorf("list bill allocation rule failed, err: %v, vendor: %s, rid: %s", err, vendor, kt.Rid)
+			return nil, err
+		}
+		rules = append(rules, result.Details...)
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return &allocator{
+		vendor:       vendor,
+		rules:        sortAllocationRules(rules),
+		usageBizRels: make(map[string][]corecloud.ResUsageBizRel),
+		results:      make(map[allocationResultKey]*bill.AllocationResultCreate),
+	}, nil
+}
+
+func sortAllocationRules(rules []corebill.AllocationRule) []corebill.AllocationRule {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Allocate split bill items according to allocation rules, items not matched by any rule are returned as is.
+func (a *allocator) Allocate(kt *kit.Kit, items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	if len(a.rules) == 0 {
+		return items, nil
+	}
+
+	exts := make([]*billItemExt, len(items))
+	for i := range items {
+		ext, err := parseBillItemExt(a.vendor, items[i].Extension)
+		if err != nil {
+			return nil, err
+		}
+		exts[i] = ext
+	}
+	if err := a.loadUsageBizRels(kt, exts); err != nil {
+		return nil, err
+	}
+
+	allocated := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(items))
+	for i, item := range items {
+		rule, shares := a.matchShares(item, exts[i])
+		if rule == nil {
+			allocated = append(allocated, item)
+			continue
+		}
+
+		splitItems := splitBillItemByShares(item, shares)
+		for _, one := range splitItems {
+			a.addResult(rule.ID, item.BkBizID, one)
+		}
+		allocated = append(allocated, splitItems...)
+	}
+
+	return allocated, nil
+}
+
+// matchShares returns the first rule that matches the item and can produce shares for it.
+func (a *allocator) matchShares(item bill.BillItemCreateReq[rawjson.RawMessage], ext *billItemExt) (
+	*corebill.AllocationRule, []allocationShare) {
+
+	for i := range a.rules {
+		rule := &a.rules[i]
+		if !matchAllocationRule(&rule.Matcher, item) {
+			continue
+		}
+
+		var shares []allocationShare
+		switch rule.Method {
+		case enumor.BillAllocationFixedRatio:
+			shares = fixedRatioShares(&rule.Config)
+		case enumor.BillAllocationResUsage:
+			shares = resUsageShares(&rule.Config, a.usageBizRels[ext.ResCloudID])
+		case enumor.BillAllocationTag:
+			shares = tagShares(&rule.Config, ext.Tags)
+		}
+		if len(shares) != 0 {
+			return rule, shares
+		}
+	}
+
+	return nil, nil
+}
+
+func (a *allocator) addResult(ruleID string, sourceBizID int64, item bill.BillItemCreateReq[rawjson.RawMessage]) {
+	key := allocationResultKey{
+		RuleID:        ruleID,
+		SourceBkBizID: sourceBizID,
+		BkBizID:       item.BkBizID,
+		Currency:      item.Currency,
+	}
+	result, exists := a.results[key]
+	if !exists {
+		result = &bill.AllocationResultCreate{
+			RuleID:        ruleID,
+			SourceBkBizID: sourceBizID,
+			BkBizID:       item.BkBizID,
+			Currency:      item.Currency,
+			Cost:          decimal.Zero,
+		}
+		a.results[key] = result
+	}
+	result.Cost = result.Cost.Add(item.Cost)
+	result.ItemCount++
+}
+
+// loadUsageBizRels load resource usage biz relations of bill items that not loaded yet.
+func (a *allocator) loadUsageBizRels(kt *kit.Kit, exts []*billItemExt) error {
+	hasResUsageRule := false
+	for _, rule := range a.rules {
+		if rule.Method == enumor.BillAllocationResUsage {
+			hasResUsageRule = true
+			break
+		}
+	}
+	if !hasResUsageRule {
+		return nil
+	}
+
+	cloudIDs := make([]string, 0)
+	for _, ext := range exts {
+		if len(ext.ResCloudID) == 0 {
+			continue
+		}
+		if _, exists := a.usageBizRels[ext.ResCloudID]; exists {
+			continue
+		}
+		// 先占位，没有使用业务关联关系的资源也不再重复查询
+		a.usageBizRels[ext.ResCloudID] = nil
+		cloudIDs = append(cloudIDs, ext.ResCloudID)
+	}
+
+	for _, batch := range slice.Split(cloudIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("res_cloud_id", batch),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			result, err := actcli.GetDataService().Global.ResUsageBizRel.ListResUsageBizRel(kt, listReq)
+			if err != nil {
+				logs.Errorf("list res usage biz rel failed, err: %v, rid: %s", err, kt.Rid)
+				return err
+			}
+			for _, rel := range result.Details {
+				a.usageBizRels[rel.ResCloudID] = append(a.usageBizRels[rel.ResCloudID], rel)
+			}
+
+			if uint(len(result.Details)) < listReq.Page.Limit {
+				break
+			}
+			listReq.Page.Start += uint32(listReq.Page.Limit)
+		}
+	}
+
+	return nil
+}
+
+// Save replace allocation results of the bill day, results are versioned by the bill summary version.
+func (a *allocator) Save(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int) error {
+	results := make([]bill.AllocationResultCreate, 0, len(a.results))
+	for _, one := range a.results {
+		results = append(results, *one)
+	}
+
+	req := &bill.AllocationResultReplaceReq{
+		Vendor:        opt.Vendor,
+		RootAccountID: opt.RootAccountID,
+		MainAccountID: opt.MainAccountID,
+		BillYear:      opt.BillYear,
+		BillMonth:     opt.BillMonth,
+		BillDay:       billDay,
+		VersionID:     opt.VersionID,
+		Results:       results,
+	}
+	if err := actcli.GetDataService().Global.Bill.ReplaceBillAllocationResult(kt, req); err != nil {
+		logs.Errorf("replace bill allocation result failed, err: %v, opt: %+v, day: %d, rid: %s",
+			err, opt, billDay, kt.Rid)
+		return fmt.Errorf("replace bill allocation result failed, err: %v", err)
+	}
+
+	return nil
+}
+
+func matchAllocationRule(m *corebill.AllocationMatcher, item bill.BillItemCreateReq[rawjson.RawMessage]) bool {
+	if len(m.RootAccountIDs) != 0 && !slice.IsItemInSlice(m.RootAccountIDs, item.RootAccountID) {
+		return false
+	}
+	if len(m.MainAccountIDs) != 0 && !slice.IsItemInSlice(m.MainAccountIDs, item.MainAccountID) {
+		return false
+	}
+	if len(m.HcProductCodes) != 0 && !slice.IsItemInSlice(m.HcProductCodes, item.HcProductCode) {
+		return false
+	}
+	if len(m.HcProductNames) != 0 && !slice.IsItemInSlice(m.HcProductNames, item.HcProductName) {
+		return false
+	}
+
+	return true
+}
+
+func fixedRatioShares(config *corebill.AllocationConfig) []allocationShare {
+	shares := make([]allocationShare, 0, len(config.Shares))
+	for _, one := range config.Shares {
+		shares = append(shares, allocationShare{BkBizID: one.BkBizID, Ratio: one.Ratio})
+	}
+	return shares
+}
+
+// resUsageShares split equally among the usage bizs of the resource.
+func resUsageShares(config *corebill.AllocationConfig, rels []corecloud.ResUsageBizRel) []allocationShare {
+	bizIDs := make([]int64, 0, len(rels))
+	seen := make(map[int64]struct{}, len(rels))
+	for _, rel := range rels {
+		if len(config.ResType) != 0 && rel.ResType != config.ResType {
+			continue
+		}
+		if _, exists := seen[rel.UsageBizID]; exists {
+			continue
+		}
+		seen[rel.UsageBizID] = struct{}{}
+		bizIDs = append(bizIDs, rel.UsageBizID)
+	}
+	if len(bizIDs) == 0 {
+		return nil
+	}
+
+	sort.Slice(bizIDs, func(i, j int) bool { return bizIDs[i] < bizIDs[j] })
+	ratio := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(len(bizIDs))))
+	shares := make([]allocationShare, 0, len(bizIDs))
+	for _, bizID := range bizIDs {
+		shares = append(shares, allocationShare{BkBizID: bizID, Ratio: ratio})
+	}
+	return shares
+}
+
+func tagShares(config *corebill.AllocationConfig, tags map[string]string) []allocationShare {
+	value, exists := tags[config.TagKey]
+	if !exists {
+		return nil
+	}
+	bizID, exists := config.TagValueBizIDs[value]
+	if !exists {
+		return nil
+	}
+
+	return []allocationShare{{BkBizID: bizID, Ratio: decimal.NewFromInt(1)}}
+}
+
+// splitBillItemByShares split bill item cost and resource amount by shares, the last share takes the remainder
+// so that the sum of split items is exactly the same as the origin item.
+func splitBillItemByShares(item bill.BillItemCreateReq[rawjson.RawMessage],
+	shares []allocationShare) []bill.BillItemCreateReq[rawjson.RawMessage] {
+
+	items := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(shares))
+	restCost, restAmount := item.Cost, item.ResAmount
+	for i, share := range shares {
+		one := item
+		one.BkBizID = share.BkBizID
+		if i == len(shares)-1 {
+			one.Cost, one.ResAmount = restCost, restAmount
+		} else {
+			one.Cost = item.Cost.Mul(share.Ratio).Round(allocationCostPrecision)
+			one.ResAmount = item.ResAmount.Mul(share.Ratio).Round(allocationCostPrecision)
+			restCost = restCost.Sub(one.Cost)
+			restAmount = restAmount.Sub(one.ResAmount)
+		}
+		items = append(items, one)
+	}
+
+	return items
+}
+
+// parseBillItemExt parse the resource cloud id and tags from bill item extension.
+func parseBillItemExt(vendor enumor.Vendor, extension *rawjson.RawMessage) (*billItemExt, error) {
+	ext := new(billItemExt)
+	if extension == nil || len(*extension) == 0 {
+		return ext, nil
+	}
+
+	fields := make(map[string]rawjson.RawMessage)
+	if err := rawjson.Unmarshal(*extension, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal bill item extension failed, err: %v", err)
+	}
+
+	if key, exists := corebill.BillItemResIDKeys[vendor]; exists {
+		if raw, ok := fields[key]; ok {
+			// 资源ID字段不是字符串时视为没有资源ID
+			_ = rawjson.Unmarshal(raw, &ext.ResCloudID)
+		}
+	}
+
+	// 目前只有腾讯云账单明细中带有标签信息
+	if raw, ok := fields["Tags"]; ok && vendor == enumor.TCloud {
+		tags := make([]struct {
+			TagKey   *string `json:"TagKey"`
+			TagValue *string `json:"TagValue"`
+		}, 0)
+		if err := rawjson.Unmarshal(raw, &tags); err != nil {
+			return nil, fmt.Errorf("unmarshal bill item tags failed, err: %v", err)
+		}
+		ext.Tags = make(map[string]string, len(tags))
+		for _, tag := range tags {
+			if tag.TagKey == nil || tag.TagValue == nil {
+				continue
+			}
+			ext.Tags[*tag.TagKey] = *tag.TagValue
+		}
+	}
+
+	return ext, nil
+}
@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"testing"
+
+	corebill "hcm/pkg/api/core/bill"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBillItem(cost string, ext string) bill.BillItemCreateReq[rawjson.RawMessage] {
+	raw := rawjson.RawMessage(ext)
+	return bill.BillItemCreateReq[rawjson.RawMessage]{
+		RootAccountID: "root-1",
+		MainAccountID: "main-1",
+		Vendor:        enumor.TCloud,
+		BkBizID:       100,
+		Currency:      enumor.CurrencyUSD,
+		Cost:          decimal.RequireFromString(cost),
+		ResAmount:     decimal.RequireFromString("3"),
+		HcProductCode: "p_bwp",
+		HcProductName: "bandwidth package",
+		Extension:     &raw,
+	}
+}
+
+func TestSplitBillItemByShares(t *testing.T) {
+	item := newTestBillItem("10", `{}`)
+	third := decimal.NewFromInt(1).Div(decimal.NewFromInt(3))
+	shares := []allocationShare{{BkBizID: 1, Ratio: third}, {BkBizID: 2, Ratio: third}, {BkBizID: 3, Ratio: third}}
+
+	items := splitBillItemByShares(item, shares)
+	assert.Len(t, items, 3)
+
+	totalCost, totalAmount := decimal.Zero, decimal.Zero
+	for i, one := range items {
+		assert.Equal(t, shares[i].BkBizID, one.BkBizID)
+		totalCost = totalCost.Add(one.Cost)
+		totalAmount = totalAmount.Add(one.ResAmount)
+	}
+	assert.True(t, totalCost.Equal(item.Cost))
+	assert.True(t, totalAmount.Equal(item.ResAmount))
+	assert.True(t, items[0].Cost.Equal(decimal.RequireFromString("3.3333333333")))
+	assert.True(t, items[2].Cost.Equal(decimal.RequireFromString("3.3333333334")))
+}
+
+func TestMatchAllocationRule(t *testing.T) {
+	item := newTestBillItem("1", `{}`)
+
+	assert.True(t, matchAllocationRule(&corebill.AllocationMatcher{HcProductCodes: []string{"p_bwp"}}, item))
+	assert.True(t, matchAllocationRule(&corebill.AllocationMatcher{
+		RootAccountIDs: []string{"root-1"}, HcProductNames: []string{"bandwidth package"}}, item))
+	assert.False(t, matchAllocationRule(&corebill.AllocationMatcher{
+		RootAccountIDs: []string{"root-1"}, MainAccountIDs: []string{"main-2"}}, item))
+}
+
+func TestParseBillItemExt(t *testing.T) {
+	raw := rawjson.RawMessage(`{"ResourceId":"bwp-1","Tags":[{"TagKey":"team","TagValue":"a"},{"TagKey":"x"}]}`)
+	ext, err := parseBillItemExt(enumor.TCloud, &raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "bwp-1", ext.ResCloudID)
+	assert.Equal(t, map[string]string{"team": "a"}, ext.Tags)
+
+	raw = rawjson.RawMessage(`{"line_item_resource_id":"vol-1"}`)
+	ext, err = parseBillItemExt(enumor.Aws, &raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol-1", ext.ResCloudID)
+	assert.Empty(t, ext.Tags)
+
+	ext, err = parseBillItemExt(enumor.Gcp, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, ext.ResCloudID)
+}
+
+func TestAllocatorMatchShares(t *testing.T) {
+	a := &allocator{
+		vendor: enumor.TCloud,
+		rules: sortAllocationRules([]corebill.AllocationRule{
+			{
+				ID: "fixed", Priority: 2, Matcher: corebill.AllocationMatcher{HcProductCodes: []string{"p_bwp"}},
+				Method: enumor.BillAllocationFixedRatio,
+				Config: corebill.AllocationConfig{Shares: []corebill.AllocationShare{
+					{BkBizID: 1, Ratio: decimal.RequireFromString("0.6")},
+					{BkBizID: 2, Ratio: decimal.RequireFromString("0.4")},
+				}},
+			},
+			{
+				ID: "tag", Priority: 0, Matcher: corebill.AllocationMatcher{HcProductCodes: []string{"p_bwp"}},
+				Method: enumor.BillAllocationTag,
+				Config: corebill.AllocationConfig{TagKey: "team", TagValueBizIDs: map[string]int64{"a": 10}},
+			},
+			{
+				ID: "usage", Priority: 1, Matcher: corebill.AllocationMatcher{HcProductCodes: []string{"p_bwp"}},
+				Method: enumor.BillAllocationResUsage,
+			},
+		}),
+		usageBizRels: map[string][]corecloud.ResUsageBizRel{
+			"bwp-1": {{ResCloudID: "bwp-1", UsageBizID: 21}, {ResCloudID: "bwp-1", UsageBizID: 20}},
+		},
+		results: make(map[allocationResultKey]*bill.AllocationResultCreate),
+	}
+
+	// 带有匹配标签的明细按标签分摊
+	rule, shares := a.matchShares(newTestBillItem("1", ``), &billItemExt{Tags: map[string]string{"team": "a"}})
+	assert.Equal(t, "tag", rule.ID)
+	assert.Equal(t, []allocationShare{{BkBizID: 10, Ratio: decimal.NewFromInt(1)}}, shares)
+
+	// 标签不匹配时按资源使用业务平均分摊
+	rule, shares = a.matchShares(newTestBillItem("1", ``), &billItemExt{ResCloudID: "bwp-1"})
+	assert.Equal(t, "usage", rule.ID)
+	assert.Len(t, shares, 2)
+	assert.Equal(t, int64(20), shares[0].BkBizID)
+
+	// 都不满足时使用固定比例
+	rule, shares = a.matchShares(newTestBillItem("1", ``), &billItemExt{ResCloudID: "bwp-2"})
+	assert.Equal(t, "fixed", rule.ID)
+	assert.Len(t, shares, 2)
+
+	item := newTestBillItem("1", ``)
+	item.HcProductCode = "p_cvm"
+	rule, _ = a.matchShares(item, &billItemExt{})
+	assert.Nil(t, rule)
+}
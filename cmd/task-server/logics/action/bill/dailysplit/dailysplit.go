@@ -214,6 +214,11 @@ func splitBillItem(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int)
 		return fmt.Errorf("failed to get splitter for %v, err %s", opt, err.Error())
 	}
 
+	allocator, err := newAllocator(kt, opt.Vendor)
+	if err != nil {
+		return fmt.Errorf("failed to load bill allocation rules for %v, err %s", opt, err.Error())
+	}
+
 	for _, filename := range resp.Filenames {
 		var billItemList []bill.BillItemCreateReq[rawjson.RawMessage]
 		// 后续可在该过程中，增加处理过程
@@ -242,6 +247,12 @@ func splitBillItem(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int)
 			billItemList = append(billItemList, reqList...)
 		}
 
+		// 按分摊规则将共享费用拆分到多个业务
+		billItemList, err = allocator.Allocate(kt, billItemList)
+		if err != nil {
+			return fmt.Errorf("allocate bill item for %s failed, err %s", filename, err.Error())
+		}
+
 		for _, itemsBatch := range slice.Split(billItemList, constant.BatchOperationMaxLimit) {
 			createReq := &bill.BatchBillItemCreateReq[rawjson.RawMessage]{
 				ItemCommonOpt: &bill.ItemCommonOpt{
@@ -258,6 +269,10 @@ func splitBillItem(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int)
 		}
 		logs.Infof("split %s successfully", filename)
 	}
+
+	if err = allocator.Save(kt, opt, billDay); err != nil {
+		return fmt.Errorf("save bill allocation result for %v day %d failed, err %s", opt, billDay, err.Error())
+	}
 	return nil
 }
 
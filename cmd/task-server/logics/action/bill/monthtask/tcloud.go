@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package monthtask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/account-set"
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/shopspring/decimal"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
+)
+
+func newTCloudRunner(taskType enumor.MonthTaskType) (MonthTaskRunner, error) {
+	switch taskType {
+	case enumor.TCloudCommonExpenseMonthTask:
+		return &TCloudCommonExpenseMonthTask{}, nil
+	default:
+		return nil, errors.New("not support task type of tcloud: " + string(taskType))
+	}
+}
+
+type tcloudMonthTaskBaseRunner struct {
+	excludeAccountCloudIds []string
+}
+
+func (a *tcloudMonthTaskBaseRunner) initExtension(opt *MonthTaskActionOption) {
+	if opt.Extension == nil {
+		return
+	}
+
+	if opt.Extension[constant.TCloudCommonExpenseExcludeCloudIDKey] != "" {
+		excludeCloudIDStr := opt.Extension[constant.TCloudCommonExpenseExcludeCloudIDKey]
+		excluded := strings.Split(excludeCloudIDStr, ",")
+		a.excludeAccountCloudIds = excluded
+	}
+}
+
+// listMainAccount rootAsMainAccount 作为二级账号存在的根账号，将分摊后的账单抵冲该账号支出
+func (a tcloudMonthTaskBaseRunner) listMainAccount(kt *kit.Kit, rootAccountID, rootAccountCloudID string) (
+	mainAccountMap map[string]*protocore.BaseMainAccount, rootAsMainAccount *protocore.BaseMainAccount, err error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("parent_account_id", rootAccountID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	mainAccountsResp, err := actcli.GetDataService().Global.MainAccount.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("failt to list main account for %s month task, err: %v, rid: %s",
+			enumor.TCloud, err, kt.Rid)
+		return nil, nil, err
+	}
+	mainAccountMap = make(map[string]*protocore.BaseMainAccount, len(mainAccountsResp.Details))
+	for _, account := range mainAccountsResp.Details {
+		mainAccountMap[account.ID] = account
+		// 查找作为主账号录入的根账号
+		if account.CloudID == rootAccountCloudID {
+			rootAsMainAccount = account
+		}
+	}
+	if rootAsMainAccount == nil {
+		return nil, nil, errors.New("can not found root as main account " + rootAccountCloudID)
+	}
+
+	return mainAccountMap, rootAsMainAccount, nil
+}
+
+func convTCloudBillItemExtension(productName string, opt *MonthTaskActionOption, mainAccountCloudID string,
+	cost decimal.Decimal) ([]byte, error) {
+
+	detail := &billing.BillDetail{
+		BusinessCode:     cvt.ValToPtr(productName),
+		BusinessCodeName: cvt.ValToPtr(productName),
+		ProductCode:      cvt.ValToPtr(productName),
+		ProductCodeName:  cvt.ValToPtr(productName),
+		OwnerUin:         cvt.ValToPtr(mainAccountCloudID),
+		PayerUin:         cvt.ValToPtr(mainAccountCloudID),
+		BillMonth:        cvt.ValToPtr(fmt.Sprintf("%d-%02d", opt.BillYear, opt.BillMonth)),
+		ComponentSet: []*billing.BillDetailComponent{{
+			RealCost: cvt.ValToPtr(cost.String()),
+		}},
+	}
+	ext := billcore.TCloudBillItemExtension{BillDetail: detail}
+	return json.Marshal(ext)
+}
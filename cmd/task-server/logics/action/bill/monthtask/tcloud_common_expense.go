@@ -0,0 +1,216 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package monthtask
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/cmd/task-server/logics/action/bill/dailypull/tcloud"
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	typecore "hcm/pkg/adaptor/types/core"
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/account-set"
+	"hcm/pkg/api/data-service/bill"
+	hcbill "hcm/pkg/api/hc-service/bill"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	"github.com/shopspring/decimal"
+)
+
+// TCloudCommonExpenseMonthTask ...
+// 1. 拉取集团管理账号（根账号）自身的账单，直接用根账号cloud_id 作为PayerUin 拉取
+// 2. 按二级账号当月支出比例分摊到各个二级账号下，并冲平根账号支出
+type TCloudCommonExpenseMonthTask struct {
+	tcloudMonthTaskBaseRunner
+}
+
+// Pull root account bill item
+func (a TCloudCommonExpenseMonthTask) Pull(kt *kit.Kit, opt *MonthTaskActionOption, index uint64) (
+	itemList []bill.RawBillItem, isFinished bool, err error) {
+
+	// 查询根账号信息
+	rootAccount, err := actcli.GetDataService().Global.RootAccount.GetBasicInfo(kt, opt.RootAccountID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 获取指定月份最后一天
+	lastDay, err := times.GetLastDayOfMonth(opt.BillYear, opt.BillMonth)
+	if err != nil {
+		logs.Errorf("fail get last day of month for tcloud month task, year: %d, month: %d, err: %v, rid: %s",
+			opt.BillYear, opt.BillMonth, err, kt.Rid)
+		return nil, false, err
+	}
+
+	rootBillReq := &hcbill.TCloudRootBillListReq{
+		RootAccountID:      opt.RootAccountID,
+		MainAccountCloudID: rootAccount.CloudID,
+		Month:              fmt.Sprintf("%d-%02d", opt.BillYear, opt.BillMonth),
+		BeginDate:          fmt.Sprintf("%d-%02d-%02d 00:00:00", opt.BillYear, opt.BillMonth, 1),
+		EndDate:            fmt.Sprintf("%d-%02d-%02d 23:59:59", opt.BillYear, opt.BillMonth, lastDay),
+		Page: &typecore.TCloudPage{
+			Offset: index,
+			Limit:  a.GetBatchSize(kt),
+		},
+	}
+	billResp, err := actcli.GetHCService().TCloud.Bill.ListRootBill(kt, rootBillReq)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(billResp.Details) == 0 {
+		return nil, true, nil
+	}
+	itemList, err = tcloud.ConvertToRawBill(rootAccount.DefaultCurrency(), billResp.Details)
+	if err != nil {
+		logs.Errorf("fail to convert tcloud root account bill, err: %v, rid: %s", err, kt.Rid)
+		return nil, false, err
+	}
+	done := uint64(len(billResp.Details)) < a.GetBatchSize(kt)
+	return itemList, done, nil
+}
+
+// Split tcloud root account expense to main account
+func (a TCloudCommonExpenseMonthTask) Split(kt *kit.Kit, opt *MonthTaskActionOption,
+	rawItemList []*bill.RawBillItem) ([]bill.BillItemCreateReq[json.RawMessage], error) {
+
+	if len(rawItemList) == 0 {
+		return nil, nil
+	}
+	a.initExtension(opt)
+
+	// 查询根账号信息
+	rootAccount, err := actcli.GetDataService().Global.RootAccount.GetBasicInfo(kt, opt.RootAccountID)
+	if err != nil {
+		logs.Errorf("failt to get root account info, err: %v, accountID: %s, rid: %s", err, opt.RootAccountID, kt.Rid)
+		return nil, err
+	}
+
+	// rootAsMainAccount 作为二级账号存在的根账号，将分摊后的账单抵冲该账号支出
+	mainAccountMap, rootAsMainAccount, err := a.listMainAccount(kt, rootAccount.ID, rootAccount.CloudID)
+	if err != nil {
+		logs.Errorf("fail to list main account for tcloud month task split step, err: %v, opt: %#v, rid: %s",
+			err, opt, kt.Rid)
+		return nil, err
+	}
+
+	// 聚合本批次 账单总额，并分摊给每个主账号
+	batchSum := decimal.Zero
+	for _, item := range rawItemList {
+		batchSum = batchSum.Add(item.BillCost)
+	}
+
+	summaryList, err := a.listSummaryMainForCommonExpense(kt, opt, mainAccountMap, rootAsMainAccount.CloudID)
+	if err != nil {
+		logs.Errorf("fail to get summary main list for tcloud month task split step, err: %v, opt: %#v, rid: %s",
+			err, opt, kt.Rid)
+		return nil, err
+	}
+	if len(summaryList) == 0 {
+		logs.Warnf("no main account for tcloud month task common expense, opt: %#v, rid: %s", opt, kt.Rid)
+		return nil, nil
+	}
+
+	// 计算总额，再按比例分摊给各个二级账号
+	summaryTotal := decimal.Zero
+	for _, summaryMain := range summaryList {
+		summaryTotal = summaryTotal.Add(summaryMain.CurrentMonthCost)
+	}
+	if summaryTotal.IsZero() {
+		logs.Warnf("total cost of main accounts is zero, skip tcloud common expense, opt: %#v, rid: %s", opt, kt.Rid)
+		return nil, nil
+	}
+
+	billItems := make([]bill.BillItemCreateReq[json.RawMessage], 0, len(summaryList)*2)
+	for _, summary := range summaryList {
+		mainAccount := mainAccountMap[summary.MainAccountID]
+		cost := batchSum.Mul(summary.CurrentMonthCost).Div(summaryTotal)
+		extJson, err := convTCloudBillItemExtension(constant.BillCommonExpenseName, opt, mainAccount.CloudID, cost)
+		if err != nil {
+			logs.Errorf("fail to marshal tcloud common expense extension to json, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		billItems = append(billItems, convSummaryToCommonExpense(summary, cost, extJson))
+
+		// 此处冲平根账号支出
+		reverseCost := cost.Neg()
+		reverseExtJson, err := convTCloudBillItemExtension(constant.BillCommonExpenseReverseName, opt,
+			mainAccount.CloudID, reverseCost)
+		if err != nil {
+			logs.Errorf("fail to marshal tcloud common expense reverse extension to json, err: %v, rid: %s",
+				err, kt.Rid)
+			return nil, err
+		}
+		billItems = append(billItems, convSummaryToCommonReverse(rootAsMainAccount, summary, reverseCost,
+			reverseExtJson))
+	}
+	return billItems, nil
+}
+
+// 不包含根账号自身以及用户设定的排除账号的 二级账号汇总信息
+func (a TCloudCommonExpenseMonthTask) listSummaryMainForCommonExpense(kt *kit.Kit, opt *MonthTaskActionOption,
+	mainAccountMap map[string]*protocore.BaseMainAccount, rootCloudID string) ([]*bill.BillSummaryMain, error) {
+
+	mainAccountIDs := make([]string, 0, len(mainAccountMap))
+
+	// 排除根账号自身以及用户设定的账号
+	exCloudIdMap := cvt.StringSliceToMap(a.excludeAccountCloudIds)
+	exCloudIdMap[rootCloudID] = struct{}{}
+	for _, account := range mainAccountMap {
+		if _, exist := exCloudIdMap[account.CloudID]; exist {
+			continue
+		}
+		mainAccountIDs = append(mainAccountIDs, account.ID)
+	}
+	if len(mainAccountIDs) == 0 {
+		return nil, nil
+	}
+	summaryListReq := &bill.BillSummaryMainListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("main_account_id", mainAccountIDs),
+			tools.RuleEqual("bill_year", opt.BillYear),
+			tools.RuleEqual("bill_month", opt.BillMonth),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	summaryMainResp, err := actcli.GetDataService().Global.Bill.ListBillSummaryMain(kt, summaryListReq)
+	if err != nil {
+		logs.Errorf("failt to list main account bill summary for %s month task, err: %v, rid: %s",
+			enumor.TCloud, err, kt.Rid)
+		return nil, err
+	}
+	return summaryMainResp.Details, nil
+}
+
+// GetHcProductCodes hc product code ranges
+func (a *TCloudCommonExpenseMonthTask) GetHcProductCodes() []string {
+	return []string{constant.BillCommonExpenseName, constant.BillCommonExpenseReverseName}
+}
+
+// GetBatchSize tcloud bill api returns at most 100 items per page
+func (a TCloudCommonExpenseMonthTask) GetBatchSize(kt *kit.Kit) uint64 {
+	return 100
+}
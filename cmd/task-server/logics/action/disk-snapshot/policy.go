@@ -0,0 +1,290 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actiondisksnapshot 云盘快照相关异步任务
+package actiondisksnapshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+var _ action.Action = new(PolicyAction)
+var _ action.ParameterAction = new(PolicyAction)
+
+// PolicyAction 执行快照策略：为策略下的云盘创建快照，并按保留规则清理该策略创建的过期快照
+type PolicyAction struct{}
+
+// PolicyOption ...
+type PolicyOption struct {
+	PolicyID string `json:"policy_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *PolicyOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return request params.
+func (act PolicyAction) ParameterNew() (params interface{}) {
+	return new(PolicyOption)
+}
+
+// Name return action name
+func (act PolicyAction) Name() enumor.ActionName {
+	return enumor.ActionDiskSnapshotPolicy
+}
+
+// Run ...
+func (act PolicyAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*PolicyOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy, err := getPolicy(kt.Kit(), opt.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 策略已删除或被禁用时不再执行
+	if policy == nil || !policy.Enabled {
+		logs.Infof("disk snapshot policy: %s is deleted or disabled, skip, rid: %s", opt.PolicyID, kt.Kit().Rid)
+		return nil, nil
+	}
+
+	cli, err := getSnapshotClient(policy.Vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	createErrs := make([]error, 0)
+	for _, diskID := range policy.DiskIDs {
+		req := &hcproto.CreateReq{
+			DiskID:   diskID,
+			Name:     snapshotName(policy.Name, now),
+			PolicyID: policy.ID,
+		}
+		if _, err = cli.Create(kt.Kit(), req); err != nil {
+			logs.Errorf("create disk snapshot by policy failed, err: %v, policy: %s, disk: %s, rid: %s", err,
+				policy.ID, diskID, kt.Kit().Rid)
+			createErrs = append(createErrs, fmt.Errorf("disk %s: %v", diskID, err))
+		}
+	}
+
+	if err = prune(kt.Kit(), cli, policy, now); err != nil {
+		return nil, err
+	}
+
+	if len(createErrs) != 0 {
+		return nil, errors.Join(createErrs...)
+	}
+
+	return nil, nil
+}
+
+// snapshotClient hc-service 各云厂商快照客户端的公共方法
+type snapshotClient interface {
+	Create(kt *kit.Kit, req *hcproto.CreateReq) (*core.CreateResult, error)
+	BatchDelete(kt *kit.Kit, req *hcproto.BatchDeleteReq) error
+}
+
+func getSnapshotClient(vendor enumor.Vendor) (snapshotClient, error) {
+	cli := actcli.GetHCService()
+	switch vendor {
+	case enumor.TCloud:
+		return cli.TCloud.DiskSnapshot, nil
+	case enumor.Aws:
+		return cli.Aws.DiskSnapshot, nil
+	case enumor.HuaWei:
+		return cli.HuaWei.DiskSnapshot, nil
+	case enumor.Gcp:
+		return cli.Gcp.DiskSnapshot, nil
+	case enumor.Azure:
+		return cli.Azure.DiskSnapshot, nil
+	default:
+		return nil, fmt.Errorf("vendor: %s not support disk snapshot", vendor)
+	}
+}
+
+func getPolicy(kt *kit.Kit, id string) (*coredisksnapshot.Policy, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := actcli.GetDataService().Global.ListDiskSnapshotPolicy(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	return &result.Details[0], nil
+}
+
+// snapshotName 快照名称为策略名称加执行时间，云上快照名称最长60个字符
+func snapshotName(policyName string, now time.Time) string {
+	suffix := "-" + now.Format("20060102150405")
+	maxLen := 60 - len(suffix)
+	name := []rune(policyName)
+	for len(string(name)) > maxLen {
+		name = name[:len(name)-1]
+	}
+
+	return string(name) + suffix
+}
+
+func prune(kt *kit.Kit, cli snapshotClient, policy *coredisksnapshot.Policy, now time.Time) error {
+	snapshots, err := listPolicySnapshot(kt, policy.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, one := range snapshots {
+		if _, ok := createdTime(one); !ok {
+			logs.Warnf("disk snapshot created time is invalid, skip prune, policy: %s, snapshot: %s, cloud created "+
+				"time: %s, created at: %s, rid: %s", policy.ID, one.ID, one.CloudCreatedTime, one.CreatedAt, kt.Rid)
+		}
+	}
+
+	ids := SelectExpired(snapshots, policy.RetentionCount, policy.RetentionDays, now)
+	for _, batch := range slice.Split(ids, constant.BatchOperationMaxLimit) {
+		if err = cli.BatchDelete(kt, &hcproto.BatchDeleteReq{IDs: batch}); err != nil {
+			logs.Errorf("delete expired disk snapshot failed, err: %v, policy: %s, ids: %v, rid: %s", err,
+				policy.ID, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func listPolicySnapshot(kt *kit.Kit, policyID string) ([]coredisksnapshot.DiskSnapshot, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("policy_id", policyID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	snapshots := make([]coredisksnapshot.DiskSnapshot, 0)
+	for {
+		result, err := actcli.GetDataService().Global.ListDiskSnapshot(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot by policy failed, err: %v, policy: %s, rid: %s", err, policyID, kt.Rid)
+			return nil, err
+		}
+
+		snapshots = append(snapshots, result.Details...)
+		if uint(len(result.Details)) < core.DefaultMaxPageLimit {
+			break
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return snapshots, nil
+}
+
+// SelectExpired 按云盘分组，返回超出保留个数或超出保留天数的快照ID，保留规则为0时表示不按该规则清理，
+// 无法确定创建时间的快照不参与清理
+func SelectExpired(snapshots []coredisksnapshot.DiskSnapshot, retentionCount, retentionDays uint32,
+	now time.Time) []string {
+
+	diskSnapshots := make(map[string][]coredisksnapshot.DiskSnapshot)
+	createdTimes := make(map[string]time.Time, len(snapshots))
+	for _, one := range snapshots {
+		created, ok := createdTime(one)
+		if !ok {
+			continue
+		}
+		createdTimes[one.ID] = created
+		diskSnapshots[one.CloudDiskID] = append(diskSnapshots[one.CloudDiskID], one)
+	}
+
+	expired := make([]string, 0)
+	for _, list := range diskSnapshots {
+		// 按创建时间倒序，最新的快照在前
+		sort.SliceStable(list, func(i, j int) bool {
+			return createdTimes[list[i].ID].After(createdTimes[list[j].ID])
+		})
+
+		for idx, one := range list {
+			if retentionCount > 0 && idx >= int(retentionCount) {
+				expired = append(expired, one.ID)
+				continue
+			}
+
+			if retentionDays > 0 && now.Sub(createdTimes[one.ID]) > time.Duration(retentionDays)*24*time.Hour {
+				expired = append(expired, one.ID)
+			}
+		}
+	}
+
+	return expired
+}
+
+// createdTime 快照的创建时间，优先使用云上创建时间，都无法解析时返回false
+func createdTime(snapshot coredisksnapshot.DiskSnapshot) (time.Time, bool) {
+	if t, err := time.Parse(constant.TimeStdFormat, snapshot.CloudCreatedTime); err == nil {
+		return t, true
+	}
+
+	if snapshot.Revision != nil {
+		if t, err := time.Parse(constant.TimeStdFormat, snapshot.CreatedAt); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// FlowDiskSnapshotPolicyTpl 快照策略任务流模版
+var FlowDiskSnapshotPolicyTpl = action.FlowTemplate{
+	Name:      enumor.FlowDiskSnapshotPolicy,
+	ShareData: tableasync.NewShareData(nil),
+	Tasks: []action.TaskTemplate{
+		{
+			ActionID:   "1",
+			ActionName: enumor.ActionDiskSnapshotPolicy,
+		},
+	},
+}
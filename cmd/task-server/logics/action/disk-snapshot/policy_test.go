@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/tools/times"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectExpired(t *testing.T) {
+	now := time.Now()
+	snapshot := func(id, diskID string, daysAgo int) coredisksnapshot.DiskSnapshot {
+		return coredisksnapshot.DiskSnapshot{
+			ID:               id,
+			CloudDiskID:      diskID,
+			CloudCreatedTime: times.ConvStdTimeFormat(now.Add(-time.Duration(daysAgo) * 24 * time.Hour)),
+		}
+	}
+	snapshots := []coredisksnapshot.DiskSnapshot{
+		snapshot("a1", "disk-a", 1),
+		snapshot("a3", "disk-a", 3),
+		snapshot("a2", "disk-a", 2),
+		snapshot("a10", "disk-a", 10),
+		snapshot("b1", "disk-b", 1),
+		snapshot("b8", "disk-b", 8),
+		// 无法确定创建时间的快照不参与清理
+		{ID: "c0", CloudDiskID: "disk-a"},
+	}
+
+	cases := []struct {
+		name  string
+		count uint32
+		days  uint32
+		want  []string
+	}{
+		{name: "keep all", want: []string{}},
+		{name: "by count", count: 2, want: []string{"a10", "a3"}},
+		{name: "by days", days: 7, want: []string{"a10", "b8"}},
+		{name: "by count and days", count: 3, days: 7, want: []string{"a10", "b8"}},
+		{name: "keep latest one", count: 1, days: 7, want: []string{"a10", "a2", "a3", "b8"}},
+	}
+
+	for _, c := range cases {
+		got := SelectExpired(snapshots, c.count, c.days, now)
+		sort.Strings(got)
+		assert.Equal(t, c.want, got, c.name)
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	now := time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC)
+	assert.Equal(t, "daily-20261018010203", snapshotName("daily", now))
+
+	long := snapshotName("快照策略快照策略快照策略快照策略快照策略快照策略", now)
+	assert.LessOrEqual(t, len(long), 60)
+	assert.Contains(t, long, "-20261018010203")
+}
@@ -28,6 +28,7 @@ import (
 	actionrootsummary "hcm/cmd/task-server/logics/action/bill/rootsummary"
 	actcli "hcm/cmd/task-server/logics/action/cli"
 	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	actiondisksnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
 	actioneip "hcm/cmd/task-server/logics/action/eip"
 	actionfirewall "hcm/cmd/task-server/logics/action/firewall"
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
@@ -62,6 +63,9 @@ func register() {
 	action.RegisterAction(actionsg.CreateHuaweiSGRuleAction{})
 	action.RegisterAction(actioneip.DeleteEIPAction{})
 
+	action.RegisterAction(actiondisksnapshot.PolicyAction{})
+	action.RegisterTpl(actiondisksnapshot.FlowDiskSnapshotPolicyTpl)
+
 	action.RegisterAction(actionlb.AddTargetToGroupAction{})
 	action.RegisterAction(actionflow.LoadBalancerOperateWatchAction{})
 	action.RegisterTpl(actionflow.FlowLoadBalancerOperateWatchTpl)
@@ -71,6 +75,9 @@ func register() {
 
 	action.RegisterAction(actionlb.ListenerRuleAddTargetAction{})
 	action.RegisterAction(actionlb.ListenerRuleUpdateHealthCheckAction{})
+	action.RegisterAction(actionlb.ListenerReplaceCertAction{})
+	action.RegisterAction(actionlb.ApplyTCloudSpecAction{})
+	action.RegisterAction(actionlb.TrafficShiftAction{})
 	action.RegisterAction(actionlb.DeleteLoadBalancerAction{})
 
 	action.RegisterAction(actionbilldailypull.PullDailyBillAction{})
@@ -0,0 +1,644 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	tclb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+)
+
+// --------------------------[应用负载均衡声明式配置]-----------------------------
+
+var _ action.Action = new(ApplyTCloudSpecAction)
+var _ action.ParameterAction = new(ApplyTCloudSpecAction)
+
+// ApplyTCloudSpecAction 执行声明式配置计划中同一阶段的变更
+type ApplyTCloudSpecAction struct{}
+
+// ApplyTCloudSpecOption ...
+type ApplyTCloudSpecOption struct {
+	Vendor  enumor.Vendor             `json:"vendor" validate:"required"`
+	LbID    string                    `json:"lb_id" validate:"required"`
+	Changes []corelb.TCloudSpecChange `json:"changes" validate:"required,min=1"`
+}
+
+// Validate validate option.
+func (opt ApplyTCloudSpecOption) Validate() error {
+	if opt.Vendor != enumor.TCloud {
+		return fmt.Errorf("vendor: %s not support apply load balancer spec", opt.Vendor)
+	}
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return request params.
+func (act ApplyTCloudSpecAction) ParameterNew() (params any) {
+	return new(ApplyTCloudSpecOption)
+}
+
+// Name return action name
+func (act ApplyTCloudSpecAction) Name() enumor.ActionName {
+	return enumor.ActionLoadBalancerApplySpec
+}
+
+// Run 按变更类型分组执行，每一步执行前都会重新检查资源当前状态，重试时已完成的变更会被跳过
+func (act ApplyTCloudSpecAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*ApplyTCloudSpecOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	groups := make(map[string][]corelb.TCloudSpecChange)
+	order := make([]string, 0)
+	for _, change := range opt.Changes {
+		key := string(change.Action) + "/" + string(change.ResType)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], change)
+	}
+
+	for _, key := range order {
+		if err := act.apply(kt.Kit(), opt.LbID, groups[key]); err != nil {
+			logs.Errorf("apply load balancer spec changes failed, err: %v, lb: %s, changes: %s, rid: %s", err,
+				opt.LbID, key, kt.Kit().Rid)
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (act ApplyTCloudSpecAction) apply(kt *kit.Kit, lbID string, changes []corelb.TCloudSpecChange) error {
+	switch changes[0].ResType {
+	case corelb.SpecResListener:
+		switch changes[0].Action {
+		case corelb.SpecChangeCreate:
+			return act.createListeners(kt, lbID, changes)
+		case corelb.SpecChangeUpdate:
+			return act.updateListeners(kt, changes)
+		case corelb.SpecChangeDelete:
+			return act.deleteListeners(kt, changes)
+		}
+	case corelb.SpecResUrlRule:
+		switch changes[0].Action {
+		case corelb.SpecChangeCreate:
+			return act.createRules(kt, lbID, changes)
+		case corelb.SpecChangeUpdate:
+			return act.updateRules(kt, changes)
+		case corelb.SpecChangeDelete:
+			return act.deleteRules(kt, changes)
+		}
+	case corelb.SpecResTarget:
+		switch changes[0].Action {
+		case corelb.SpecChangeCreate:
+			return act.registerTargets(kt, lbID, changes)
+		case corelb.SpecChangeUpdate:
+			return act.modifyTargetWeight(kt, lbID, changes)
+		case corelb.SpecChangeDelete:
+			return act.removeTargets(kt, lbID, changes)
+		}
+	}
+	return fmt.Errorf("unsupported spec change: %s %s", changes[0].Action, changes[0].ResType)
+}
+
+func (act ApplyTCloudSpecAction) createListeners(kt *kit.Kit, lbID string,
+	changes []corelb.TCloudSpecChange) error {
+
+	lb, err := getTCloudLoadBalancer(kt, lbID)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		lbl, err := findSpecListener(kt, lbID, change.Protocol, change.Port)
+		if err != nil {
+			return err
+		}
+		if lbl != nil {
+			logs.Infof("listener(%s:%d) of lb(%s) already exists, skip create, rid: %s", change.Protocol,
+				change.Port, lbID, kt.Rid)
+			continue
+		}
+
+		spec := change.Listener
+		req := &hclb.TCloudListenerCreateReq{
+			Name:          spec.Name,
+			BkBizID:       lb.BkBizID,
+			LbID:          lbID,
+			Protocol:      spec.Protocol,
+			Port:          spec.Port,
+			Scheduler:     spec.Scheduler,
+			SessionExpire: cvt.PtrToVal(spec.SessionExpire),
+			SniSwitch:     cvt.PtrToVal(spec.SniSwitch),
+			Certificate:   spec.Certificate,
+			HealthCheck:   spec.HealthCheck,
+		}
+		if len(spec.SessionType) > 0 {
+			req.SessionType = cvt.ValToPtr(spec.SessionType)
+		}
+		if spec.EndPort > 0 {
+			req.EndPort = cvt.ValToPtr(spec.EndPort)
+		}
+		if _, err = actcli.GetHCService().TCloud.Clb.CreateListener(kt, req); err != nil {
+			logs.Errorf("create listener(%s) failed, err: %v, lb: %s, rid: %s", spec.Key(), err, lbID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) updateListeners(kt *kit.Kit, changes []corelb.TCloudSpecChange) error {
+	for _, change := range changes {
+		resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListListener(kt, &core.ListReq{
+			Filter: tools.EqualExpression("id", change.ListenerID),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list listener failed, err: %v, id: %s, rid: %s", err, change.ListenerID, kt.Rid)
+			return err
+		}
+		if len(resp.Details) == 0 {
+			return errf.Newf(errf.RecordNotFound, "listener(%s) not found", change.ListenerID)
+		}
+		lbl, spec := resp.Details[0], change.Listener
+
+		if slice.IsItemInSlice(change.Fields, "name") || slice.IsItemInSlice(change.Fields, "sni_switch") ||
+			slice.IsItemInSlice(change.Fields, "certificate") {
+
+			req := &hclb.ListenerWithRuleUpdateReq{
+				Name:      spec.Name,
+				BkBizID:   lbl.BkBizID,
+				SniSwitch: lbl.SniSwitch,
+				Extension: lbl.Extension,
+			}
+			if spec.SniSwitch != nil {
+				req.SniSwitch = *spec.SniSwitch
+			}
+			if spec.Certificate != nil {
+				req.Extension = &corelb.TCloudListenerExtension{Certificate: spec.Certificate}
+			}
+			if _, err = actcli.GetHCService().TCloud.Clb.UpdateListener(kt, lbl.ID, req); err != nil {
+				logs.Errorf("update listener(%s) failed, err: %v, rid: %s", lbl.ID, err, kt.Rid)
+				return err
+			}
+		}
+
+		if slice.IsItemInSlice(change.Fields, "health_check") {
+			req := &hclb.HealthCheckUpdateReq{HealthCheck: spec.HealthCheck}
+			if err = actcli.GetHCService().TCloud.Clb.UpdateListenerHealthCheck(kt, lbl.ID, req); err != nil {
+				logs.Errorf("update listener(%s) health check failed, err: %v, rid: %s", lbl.ID, err, kt.Rid)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) deleteListeners(kt *kit.Kit, changes []corelb.TCloudSpecChange) error {
+	ids := slice.Map(changes, func(c corelb.TCloudSpecChange) string { return c.ListenerID })
+	resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListListener(kt, &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	})
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+	if len(resp.Details) == 0 {
+		return nil
+	}
+
+	existIDs := slice.Map(resp.Details, corelb.TCloudListener.GetID)
+	if err = actcli.GetHCService().TCloud.Clb.DeleteListener(kt, &core.BatchDeleteReq{IDs: existIDs}); err != nil {
+		logs.Errorf("delete listener failed, err: %v, ids: %v, rid: %s", err, existIDs, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) createRules(kt *kit.Kit, lbID string, changes []corelb.TCloudSpecChange) error {
+	lblRules := make(map[string][]hclb.TCloudRuleCreate)
+	lblOrder := make([]string, 0)
+	for _, change := range changes {
+		lblID, err := resolveSpecListenerID(kt, lbID, change)
+		if err != nil {
+			return err
+		}
+		rule, err := findSpecRule(kt, lblID, change.Domain, change.Url)
+		if err != nil {
+			return err
+		}
+		if rule != nil {
+			continue
+		}
+
+		spec := change.Rule
+		create := hclb.TCloudRuleCreate{
+			Url:               spec.Url,
+			Domains:           []string{spec.Domain},
+			SessionExpireTime: spec.SessionExpire,
+			HealthCheck:       spec.HealthCheck,
+			Certificates:      spec.Certificate,
+		}
+		if len(spec.Scheduler) > 0 {
+			create.Scheduler = cvt.ValToPtr(spec.Scheduler)
+		}
+		if _, ok := lblRules[lblID]; !ok {
+			lblOrder = append(lblOrder, lblID)
+		}
+		lblRules[lblID] = append(lblRules[lblID], create)
+	}
+
+	for _, lblID := range lblOrder {
+		req := &hclb.TCloudRuleBatchCreateReq{Rules: lblRules[lblID]}
+		if _, err := actcli.GetHCService().TCloud.Clb.BatchCreateUrlRule(kt, lblID, req); err != nil {
+			logs.Errorf("create url rule failed, err: %v, lbl: %s, rid: %s", err, lblID, kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) updateRules(kt *kit.Kit, changes []corelb.TCloudSpecChange) error {
+	for _, change := range changes {
+		spec := change.Rule
+		req := new(hclb.TCloudRuleUpdateReq)
+		if slice.IsItemInSlice(change.Fields, "scheduler") {
+			req.Scheduler = cvt.ValToPtr(spec.Scheduler)
+		}
+		if slice.IsItemInSlice(change.Fields, "session_expire") {
+			req.SessionExpireTime = spec.SessionExpire
+		}
+		if slice.IsItemInSlice(change.Fields, "health_check") {
+			req.HealthCheck = spec.HealthCheck
+		}
+		if req.Scheduler != nil || req.SessionExpireTime != nil || req.HealthCheck != nil {
+			err := actcli.GetHCService().TCloud.Clb.UpdateUrlRule(kt, change.ListenerID, change.RuleID, req)
+			if err != nil {
+				logs.Errorf("update url rule(%s) failed, err: %v, rid: %s", change.RuleID, err, kt.Rid)
+				return err
+			}
+		}
+
+		if slice.IsItemInSlice(change.Fields, "certificate") {
+			req := &hclb.DomainAttrUpdateReq{Domain: spec.Domain, Certificate: spec.Certificate}
+			if err := actcli.GetHCService().TCloud.Clb.UpdateDomainAttr(kt, change.ListenerID, req); err != nil {
+				logs.Errorf("update listener(%s) domain(%s) cert failed, err: %v, rid: %s", change.ListenerID,
+					spec.Domain, err, kt.Rid)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) deleteRules(kt *kit.Kit, changes []corelb.TCloudSpecChange) error {
+	lblRuleIDs := make(map[string][]string)
+	for _, change := range changes {
+		lblRuleIDs[change.ListenerID] = append(lblRuleIDs[change.ListenerID], change.RuleID)
+	}
+
+	for lblID, ruleIDs := range lblRuleIDs {
+		resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListUrlRule(kt, &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("lbl_id", lblID), tools.RuleIn("id", ruleIDs)),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"id"},
+		})
+		if err != nil {
+			logs.Errorf("list url rule failed, err: %v, ids: %v, rid: %s", err, ruleIDs, kt.Rid)
+			return err
+		}
+		if len(resp.Details) == 0 {
+			continue
+		}
+
+		req := &hclb.TCloudRuleDeleteByIDReq{
+			RuleIDs: slice.Map(resp.Details, func(r corelb.TCloudLbUrlRule) string { return r.ID }),
+		}
+		if err = actcli.GetHCService().TCloud.Clb.BatchDeleteUrlRule(kt, lblID, req); err != nil {
+			logs.Errorf("delete url rule failed, err: %v, lbl: %s, ids: %v, rid: %s", err, lblID, req.RuleIDs,
+				kt.Rid)
+			return err
+		}
+	}
+	return nil
+}
+
+// registerTargets 将RS注册到监听器/规则上，只操作云上，DB中的目标组及RS由最后的同步任务补齐
+func (act ApplyTCloudSpecAction) registerTargets(kt *kit.Kit, lbID string,
+	changes []corelb.TCloudSpecChange) error {
+
+	reqMap := make(map[string]*hclb.BatchRegisterTCloudTargetReq)
+	order := make([]string, 0)
+	for _, change := range changes {
+		lblID, err := resolveSpecListenerID(kt, lbID, change)
+		if err != nil {
+			return err
+		}
+		lbl, err := actcli.GetDataService().TCloud.LoadBalancer.GetListener(kt, lblID)
+		if err != nil {
+			logs.Errorf("get listener failed, err: %v, id: %s, rid: %s", err, lblID, kt.Rid)
+			return err
+		}
+
+		req := &hclb.BatchRegisterTCloudTargetReq{CloudListenerID: lbl.CloudID, RuleType: enumor.Layer4RuleType}
+		if lbl.Protocol.IsLayer7Protocol() {
+			rule, err := findSpecRule(kt, lblID, change.Domain, change.Url)
+			if err != nil {
+				return err
+			}
+			if rule == nil {
+				return errf.Newf(errf.RecordNotFound, "rule(%s%s) of listener(%s) not found", change.Domain,
+					change.Url, lblID)
+			}
+			req.CloudRuleID, req.RuleType = rule.CloudID, enumor.Layer7RuleType
+		}
+
+		key := req.CloudListenerID + "/" + req.CloudRuleID
+		if _, ok := reqMap[key]; !ok {
+			reqMap[key] = req
+			order = append(order, key)
+		}
+		target := change.Target
+		reqMap[key].Targets = append(reqMap[key].Targets, &hclb.RegisterTarget{
+			CloudInstID: target.CloudInstID,
+			TargetType:  target.InstType,
+			EniIp:       target.IP,
+			Port:        target.Port,
+			Weight:      target.Weight,
+		})
+	}
+
+	// 重试时部分RS可能已在上次执行中注册成功，过滤掉云上已注册的RS，避免重复注册失败
+	registered, err := listRegisteredTargetKeys(kt, lbID, slice.Map(order, func(key string) string {
+		return reqMap[key].CloudListenerID
+	}))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range order {
+		req := reqMap[key]
+		req.Targets = slice.Filter(req.Targets, func(target *hclb.RegisterTarget) bool {
+			_, exists := registered[key+"/"+registerTargetKey(target)]
+			return !exists
+		})
+		for _, part := range slice.Split(req.Targets, constant.BatchAddRSCloudMaxLimit) {
+			partReq := *req
+			partReq.Targets = part
+			err := actcli.GetHCService().TCloud.Clb.BatchRegisterTargetToListenerRule(kt, lbID, &partReq)
+			if err != nil {
+				logs.Errorf("register target to listener rule failed, err: %v, lb: %s, key: %s, rid: %s", err,
+					lbID, key, kt.Rid)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listRegisteredTargetKeys 查询云上监听器/规则下已注册的RS，key 为 云监听器ID/云规则ID/RS唯一标识
+func listRegisteredTargetKeys(kt *kit.Kit, lbID string, cloudLblIDs []string) (map[string]struct{}, error) {
+	lb, err := getTCloudLoadBalancer(kt, lbID)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[string]struct{})
+	for _, ids := range slice.Split(slice.Unique(cloudLblIDs), constant.TCLBDescribeMax) {
+		req := &hclb.QueryTCloudListenerTargets{
+			AccountID:           lb.AccountID,
+			Region:              lb.Region,
+			LoadBalancerCloudId: lb.CloudID,
+			ListenerCloudIDs:    ids,
+		}
+		lblTargets, err := actcli.GetHCService().TCloud.Clb.QueryListenerTargetsByCloudIDs(kt, req)
+		if err != nil {
+			logs.Errorf("query listener targets from cloud failed, err: %v, lb: %s, listeners: %v, rid: %s", err,
+				lbID, ids, kt.Rid)
+			return nil, err
+		}
+
+		for _, lbl := range cvt.PtrToVal(lblTargets) {
+			if lbl.ListenerBackend == nil {
+				continue
+			}
+			lblCloudID := cvt.PtrToVal(lbl.ListenerId)
+			// 四层监听器的RS直接挂在监听器上，云规则ID为空
+			for _, target := range lbl.Targets {
+				registered[lblCloudID+"//"+cloudTargetKey(target)] = struct{}{}
+			}
+			for _, rule := range lbl.Rules {
+				if rule == nil {
+					continue
+				}
+				for _, target := range rule.Targets {
+					registered[lblCloudID+"/"+cvt.PtrToVal(rule.LocationId)+"/"+cloudTargetKey(target)] = struct{}{}
+				}
+			}
+		}
+	}
+	return registered, nil
+}
+
+// cloudTargetKey 云上RS的唯一标识，与 corelb.TCloudTargetSpec.Key 保持一致
+func cloudTargetKey(target *tclb.Backend) string {
+	if target == nil {
+		return ""
+	}
+
+	spec := corelb.TCloudTargetSpec{
+		InstType:    enumor.InstType(cvt.PtrToVal(target.Type)),
+		CloudInstID: cvt.PtrToVal(target.InstanceId),
+		Port:        cvt.PtrToVal(target.Port),
+	}
+	if len(target.PrivateIpAddresses) > 0 {
+		spec.IP = cvt.PtrToVal(target.PrivateIpAddresses[0])
+	}
+	return spec.Key()
+}
+
+// registerTargetKey 待注册RS的唯一标识，与 corelb.TCloudTargetSpec.Key 保持一致
+func registerTargetKey(target *hclb.RegisterTarget) string {
+	spec := corelb.TCloudTargetSpec{
+		InstType:    target.TargetType,
+		CloudInstID: target.CloudInstID,
+		IP:          target.EniIp,
+		Port:        target.Port,
+	}
+	return spec.Key()
+}
+
+func (act ApplyTCloudSpecAction) modifyTargetWeight(kt *kit.Kit, lbID string,
+	changes []corelb.TCloudSpecChange) error {
+
+	tgTargets := make(map[string][]*dataproto.TargetBaseReq)
+	for _, change := range changes {
+		tgTargets[change.TargetGroupID] = append(tgTargets[change.TargetGroupID], &dataproto.TargetBaseReq{
+			ID:          change.TargetID,
+			IP:          change.Target.IP,
+			InstType:    change.Target.InstType,
+			CloudInstID: change.Target.CloudInstID,
+			Port:        change.Target.Port,
+			Weight:      change.OldWeight,
+			NewWeight:   change.Target.Weight,
+		})
+	}
+
+	for tgID, targets := range tgTargets {
+		for _, part := range slice.Split(targets, constant.BatchModifyTargetWeightCloudMaxLimit) {
+			req := &hclb.TCloudBatchOperateTargetReq{TargetGroupID: tgID, LbID: lbID, RsList: part}
+			if err := actcli.GetHCService().TCloud.Clb.BatchModifyTargetWeight(kt, tgID, req); err != nil {
+				logs.Errorf("modify target weight failed, err: %v, tg: %s, rid: %s", err, tgID, kt.Rid)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (act ApplyTCloudSpecAction) removeTargets(kt *kit.Kit, lbID string, changes []corelb.TCloudSpecChange) error {
+	tgTargets := make(map[string][]string)
+	for _, change := range changes {
+		tgTargets[change.TargetGroupID] = append(tgTargets[change.TargetGroupID], change.TargetID)
+	}
+
+	for tgID, targetIDs := range tgTargets {
+		// 只移除仍然存在的RS，保证重试时可重入
+		resp, err := actcli.GetDataService().Global.LoadBalancer.ListTarget(kt, &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("target_group_id", tgID), tools.RuleIn("id", targetIDs)),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list target failed, err: %v, tg: %s, rid: %s", err, tgID, kt.Rid)
+			return err
+		}
+		rsList := slice.Map(resp.Details, func(t corelb.BaseTarget) *dataproto.TargetBaseReq {
+			return &dataproto.TargetBaseReq{ID: t.ID, IP: t.IP, InstType: t.InstType, CloudInstID: t.CloudInstID,
+				Port: t.Port, Weight: t.Weight}
+		})
+		for _, part := range slice.Split(rsList, constant.BatchRemoveRSCloudMaxLimit) {
+			req := &hclb.TCloudBatchOperateTargetReq{TargetGroupID: tgID, LbID: lbID, RsList: part}
+			if _, err = actcli.GetHCService().TCloud.Clb.BatchRemoveTarget(kt, tgID, req); err != nil {
+				logs.Errorf("remove target failed, err: %v, tg: %s, rid: %s", err, tgID, kt.Rid)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Rollback 各变更执行前都会检查当前状态，支持重入，无需回滚
+func (act ApplyTCloudSpecAction) Rollback(kt run.ExecuteKit, params any) error {
+	logs.Infof(" ----------- ApplyTCloudSpecAction Rollback -----------, params: %+v, rid: %s",
+		params, kt.Kit().Rid)
+	return nil
+}
+
+func getTCloudLoadBalancer(kt *kit.Kit, lbID string) (*corelb.BaseLoadBalancer, error) {
+	resp, err := actcli.GetDataService().Global.LoadBalancer.ListLoadBalancer(kt, &core.ListReq{
+		Filter: tools.EqualExpression("id", lbID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list load balancer failed, err: %v, id: %s, rid: %s", err, lbID, kt.Rid)
+		return nil, err
+	}
+	if len(resp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "load balancer(%s) not found", lbID)
+	}
+	return &resp.Details[0], nil
+}
+
+// resolveSpecListenerID 已存在的监听器在计划阶段已确定ID，新建的监听器按 协议:端口 查询
+func resolveSpecListenerID(kt *kit.Kit, lbID string, change corelb.TCloudSpecChange) (string, error) {
+	if len(change.ListenerID) > 0 {
+		return change.ListenerID, nil
+	}
+	lbl, err := findSpecListener(kt, lbID, change.Protocol, change.Port)
+	if err != nil {
+		return "", err
+	}
+	if lbl == nil {
+		return "", errf.Newf(errf.RecordNotFound, "listener(%s:%d) of lb(%s) not found", change.Protocol,
+			change.Port, lbID)
+	}
+	return lbl.ID, nil
+}
+
+func findSpecListener(kt *kit.Kit, lbID string, protocol enumor.ProtocolType, port int64) (
+	*corelb.TCloudListener, error) {
+
+	resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListListener(kt, &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lb_id", lbID),
+			tools.RuleEqual("protocol", protocol),
+			tools.RuleEqual("port", port),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list listener failed, err: %v, lb: %s, protocol: %s, port: %d, rid: %s", err, lbID, protocol,
+			port, kt.Rid)
+		return nil, err
+	}
+	if len(resp.Details) == 0 {
+		return nil, nil
+	}
+	return &resp.Details[0], nil
+}
+
+func findSpecRule(kt *kit.Kit, lblID, domain, url string) (*corelb.TCloudLbUrlRule, error) {
+	resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListUrlRule(kt, &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lbl_id", lblID),
+			tools.RuleEqual("domain", domain),
+			tools.RuleEqual("url", url),
+			tools.RuleEqual("rule_type", enumor.Layer7RuleType),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list url rule failed, err: %v, lbl: %s, domain: %s, url: %s, rid: %s", err, lblID, domain,
+			url, kt.Rid)
+		return nil, err
+	}
+	if len(resp.Details) == 0 {
+		return nil, nil
+	}
+	return &resp.Details[0], nil
+}
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"testing"
+
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+	tclb "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/clb/v20180317"
+)
+
+func TestRegisterTargetKeyMatchesCloudTarget(t *testing.T) {
+	cvm := &tclb.Backend{
+		Type:               cvt.ValToPtr(string(enumor.CvmInstType)),
+		InstanceId:         cvt.ValToPtr("ins-1"),
+		Port:               cvt.ValToPtr(int64(8080)),
+		PrivateIpAddresses: []*string{cvt.ValToPtr("10.0.0.1")},
+	}
+	assert.Equal(t, cloudTargetKey(cvm), registerTargetKey(&hclb.RegisterTarget{
+		CloudInstID: "ins-1", TargetType: enumor.CvmInstType, Port: 8080}))
+	assert.NotEqual(t, cloudTargetKey(cvm), registerTargetKey(&hclb.RegisterTarget{
+		CloudInstID: "ins-1", TargetType: enumor.CvmInstType, Port: 8081}))
+
+	eni := &tclb.Backend{
+		Type:               cvt.ValToPtr(string(enumor.EniInstType)),
+		Port:               cvt.ValToPtr(int64(80)),
+		PrivateIpAddresses: []*string{cvt.ValToPtr("10.0.0.2")},
+	}
+	assert.Equal(t, cloudTargetKey(eni), registerTargetKey(&hclb.RegisterTarget{
+		EniIp: "10.0.0.2", TargetType: enumor.EniInstType, Port: 80}))
+	assert.NotEqual(t, cloudTargetKey(eni), registerTargetKey(&hclb.RegisterTarget{
+		EniIp: "10.0.0.3", TargetType: enumor.EniInstType, Port: 80}))
+}
@@ -0,0 +1,291 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"errors"
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+var _ action.Action = new(ListenerReplaceCertAction)
+var _ action.ParameterAction = new(ListenerReplaceCertAction)
+var _ action.RollbackAction = new(ListenerReplaceCertAction)
+
+// ListenerReplaceCertAction 将同一个负载均衡下监听器、域名使用的旧证书替换为新证书
+type ListenerReplaceCertAction struct{}
+
+// ListenerReplaceCertOption ...
+type ListenerReplaceCertOption struct {
+	Vendor         enumor.Vendor `json:"vendor" validate:"required"`
+	LbID           string        `json:"lb_id" validate:"required"`
+	OldCertCloudID string        `json:"old_cert_cloud_id" validate:"required"`
+	NewCertCloudID string        `json:"new_cert_cloud_id" validate:"required"`
+	// Listeners 未开启SNI、在监听器上使用旧证书的监听器
+	Listeners []ReplaceCertListener `json:"listeners" validate:"omitempty,dive"`
+	// Domains 开启SNI、在域名上使用旧证书的域名
+	Domains []ReplaceCertDomain `json:"domains" validate:"omitempty,dive"`
+}
+
+// ReplaceCertListener 需要替换证书的监听器，Certificate 为替换前的证书信息，用于回滚
+type ReplaceCertListener struct {
+	ListenerID  string                        `json:"listener_id" validate:"required"`
+	Certificate *corelb.TCloudCertificateInfo `json:"certificate" validate:"required"`
+}
+
+// ReplaceCertDomain 需要替换证书的域名，Certificate 为替换前的证书信息，用于回滚
+type ReplaceCertDomain struct {
+	ListenerID  string                        `json:"listener_id" validate:"required"`
+	Domain      string                        `json:"domain" validate:"required"`
+	Certificate *corelb.TCloudCertificateInfo `json:"certificate" validate:"required"`
+}
+
+// Validate validate option.
+func (opt ListenerReplaceCertOption) Validate() error {
+	if opt.Vendor != enumor.TCloud {
+		return fmt.Errorf("vendor: %s not support replace cert", opt.Vendor)
+	}
+
+	if opt.OldCertCloudID == opt.NewCertCloudID {
+		return errors.New("new cert should be different from old cert")
+	}
+
+	if len(opt.Listeners) == 0 && len(opt.Domains) == 0 {
+		return errors.New("listeners or domains is required")
+	}
+
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return request params.
+func (act ListenerReplaceCertAction) ParameterNew() (params any) {
+	return new(ListenerReplaceCertOption)
+}
+
+// Name return action name
+func (act ListenerReplaceCertAction) Name() enumor.ActionName {
+	return enumor.ActionListenerReplaceCert
+}
+
+// Run 依次替换监听器、域名上的证书，任一替换失败时恢复本任务中已替换的证书，保证同一个负载均衡下证书替换的原子性
+func (act ListenerReplaceCertAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*ListenerReplaceCertOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		logs.Errorf("ListenerReplaceCertAction option validate failed, err: %v, params: %+v, rid: %s",
+			err, opt, kt.Kit().Rid)
+		return nil, err
+	}
+
+	replacedLbl := make([]ReplaceCertListener, 0, len(opt.Listeners))
+	for _, lbl := range opt.Listeners {
+		replaced, err := act.replaceListenerCert(kt.Kit(), lbl.ListenerID, opt.OldCertCloudID, opt.NewCertCloudID)
+		if err != nil {
+			act.restore(kt.Kit(), opt, replacedLbl, nil)
+			return nil, err
+		}
+		if replaced {
+			replacedLbl = append(replacedLbl, lbl)
+		}
+	}
+
+	replacedDomains := make([]ReplaceCertDomain, 0, len(opt.Domains))
+	for _, domain := range opt.Domains {
+		replaced, err := act.replaceDomainCert(kt.Kit(), domain, opt.OldCertCloudID, opt.NewCertCloudID)
+		if err != nil {
+			act.restore(kt.Kit(), opt, replacedLbl, replacedDomains)
+			return nil, err
+		}
+		if replaced {
+			replacedDomains = append(replacedDomains, domain)
+		}
+	}
+
+	logs.Infof("replace cert of load balancer(%s) success, old cert: %s, new cert: %s, listener count: %d, "+
+		"domain count: %d, rid: %s", opt.LbID, opt.OldCertCloudID, opt.NewCertCloudID, len(replacedLbl),
+		len(replacedDomains), kt.Kit().Rid)
+	return nil, nil
+}
+
+// replaceListenerCert 将监听器上的旧证书替换为新证书，监听器已不再使用旧证书时跳过，使用监听器当前的名称和SNI配置避免覆盖
+func (act ListenerReplaceCertAction) replaceListenerCert(kt *kit.Kit, lblID, oldCloudID, newCloudID string) (
+	bool, error) {
+
+	lbl, err := actcli.GetDataService().TCloud.LoadBalancer.GetListener(kt, lblID)
+	if err != nil {
+		logs.Errorf("get listener(%s) for replace cert failed, err: %v, rid: %s", lblID, err, kt.Rid)
+		return false, err
+	}
+
+	if lbl.Extension == nil || !lbl.Extension.Certificate.UsesCert(oldCloudID) {
+		return false, nil
+	}
+
+	req := &hclb.ListenerWithRuleUpdateReq{
+		Name:      lbl.Name,
+		SniSwitch: lbl.SniSwitch,
+		Extension: &corelb.TCloudListenerExtension{
+			Certificate: lbl.Extension.Certificate.ReplaceCert(oldCloudID, newCloudID),
+		},
+	}
+	if _, err = actcli.GetHCService().TCloud.Clb.UpdateListener(kt, lblID, req); err != nil {
+		logs.Errorf("replace listener(%s) cert failed, old cert: %s, new cert: %s, err: %v, rid: %s", lblID,
+			oldCloudID, newCloudID, err, kt.Rid)
+		return false, err
+	}
+	return true, nil
+}
+
+// replaceDomainCert 将域名上的旧证书替换为新证书，域名已不再使用旧证书时跳过
+func (act ListenerReplaceCertAction) replaceDomainCert(kt *kit.Kit, domain ReplaceCertDomain, oldCloudID,
+	newCloudID string) (bool, error) {
+
+	cert, err := act.getDomainCert(kt, domain.ListenerID, domain.Domain)
+	if err != nil {
+		return false, err
+	}
+
+	if !cert.UsesCert(oldCloudID) {
+		return false, nil
+	}
+
+	req := &hclb.DomainAttrUpdateReq{
+		Domain:      domain.Domain,
+		Certificate: cert.ReplaceCert(oldCloudID, newCloudID),
+	}
+	if err = actcli.GetHCService().TCloud.Clb.UpdateDomainAttr(kt, domain.ListenerID, req); err != nil {
+		logs.Errorf("replace listener(%s) domain(%s) cert failed, old cert: %s, new cert: %s, err: %v, rid: %s",
+			domain.ListenerID, domain.Domain, oldCloudID, newCloudID, err, kt.Rid)
+		return false, err
+	}
+	return true, nil
+}
+
+// getDomainCert 获取域名当前使用的证书，同一域名下的规则共用域名的证书配置
+func (act ListenerReplaceCertAction) getDomainCert(kt *kit.Kit, lblID, domain string) (
+	*corelb.TCloudCertificateInfo, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("lbl_id", lblID),
+			tools.RuleEqual("domain", domain),
+			tools.RuleEqual("rule_type", enumor.Layer7RuleType),
+		),
+		Page: &core.BasePage{Start: 0, Limit: 1},
+	}
+	resp, err := actcli.GetDataService().TCloud.LoadBalancer.ListUrlRule(kt, listReq)
+	if err != nil {
+		logs.Errorf("list url rule of listener(%s) domain(%s) failed, err: %v, rid: %s", lblID, domain, err,
+			kt.Rid)
+		return nil, err
+	}
+
+	if len(resp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "domain %s of listener %s not found", domain, lblID)
+	}
+	return resp.Details[0].Certificate, nil
+}
+
+// restore 将已替换为新证书的监听器、域名恢复为替换前的证书，恢复失败时只记录日志，由人工介入处理
+func (act ListenerReplaceCertAction) restore(kt *kit.Kit, opt *ListenerReplaceCertOption,
+	listeners []ReplaceCertListener, domains []ReplaceCertDomain) {
+
+	for _, lbl := range listeners {
+		if err := act.restoreListenerCert(kt, lbl, opt.NewCertCloudID); err != nil {
+			logs.Errorf("restore listener(%s) cert failed, err: %v, cert: %+v, rid: %s", lbl.ListenerID, err,
+				lbl.Certificate, kt.Rid)
+		}
+	}
+
+	for _, domain := range domains {
+		if err := act.restoreDomainCert(kt, domain, opt.NewCertCloudID); err != nil {
+			logs.Errorf("restore listener(%s) domain(%s) cert failed, err: %v, cert: %+v, rid: %s",
+				domain.ListenerID, domain.Domain, err, domain.Certificate, kt.Rid)
+		}
+	}
+}
+
+// restoreListenerCert 监听器当前使用新证书时恢复为替换前的证书
+func (act ListenerReplaceCertAction) restoreListenerCert(kt *kit.Kit, lbl ReplaceCertListener,
+	newCloudID string) error {
+
+	current, err := actcli.GetDataService().TCloud.LoadBalancer.GetListener(kt, lbl.ListenerID)
+	if err != nil {
+		return err
+	}
+
+	if current.Extension == nil || !current.Extension.Certificate.UsesCert(newCloudID) {
+		return nil
+	}
+
+	req := &hclb.ListenerWithRuleUpdateReq{
+		Name:      current.Name,
+		SniSwitch: current.SniSwitch,
+		Extension: &corelb.TCloudListenerExtension{Certificate: lbl.Certificate},
+	}
+	_, err = actcli.GetHCService().TCloud.Clb.UpdateListener(kt, lbl.ListenerID, req)
+	return err
+}
+
+// restoreDomainCert 域名当前使用新证书时恢复为替换前的证书
+func (act ListenerReplaceCertAction) restoreDomainCert(kt *kit.Kit, domain ReplaceCertDomain,
+	newCloudID string) error {
+
+	current, err := act.getDomainCert(kt, domain.ListenerID, domain.Domain)
+	if err != nil {
+		return err
+	}
+
+	if !current.UsesCert(newCloudID) {
+		return nil
+	}
+
+	req := &hclb.DomainAttrUpdateReq{Domain: domain.Domain, Certificate: domain.Certificate}
+	return actcli.GetHCService().TCloud.Clb.UpdateDomainAttr(kt, domain.ListenerID, req)
+}
+
+// Rollback 重试或强制回滚前，将本任务中已替换为新证书的监听器、域名恢复为替换前的证书
+func (act ListenerReplaceCertAction) Rollback(kt run.ExecuteKit, params any) error {
+	logs.Infof(" ----------- ListenerReplaceCertAction Rollback -----------, params: %+v, rid: %s",
+		params, kt.Kit().Rid)
+
+	opt, ok := params.(*ListenerReplaceCertOption)
+	if !ok {
+		return errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	act.restore(kt.Kit(), opt, opt.Listeners, opt.Domains)
+	return nil
+}
@@ -0,0 +1,406 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionlb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	coretask "hcm/pkg/api/core/task"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	datatask "hcm/pkg/api/data-service/task"
+	hclb "hcm/pkg/api/hc-service/load-balancer"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// trafficShiftPollInterval 观察期间检查控制指令和RS健康状态的间隔
+const trafficShiftPollInterval = 10 * time.Second
+
+// --------------------------[目标组渐进式流量切换]-----------------------------
+
+var _ action.Action = new(TrafficShiftAction)
+var _ action.ParameterAction = new(TrafficShiftAction)
+
+// TrafficShiftAction 目标组渐进式流量切换，每个任务负责一步权重调整或两步之间的一段观察
+type TrafficShiftAction struct{}
+
+// TrafficShiftOption ...
+type TrafficShiftOption struct {
+	TaskManagementID string `json:"task_management_id" validate:"required"`
+	// Step 当前任务所属的步骤，观察任务为即将执行的步骤
+	Step int `json:"step" validate:"min=1"`
+	// ManagementDetailID 步骤对应的任务详情，为空时表示两步之间的观察任务
+	ManagementDetailID string `json:"management_detail_id,omitempty"`
+	// WaitSec 观察任务的观察时长
+	WaitSec int64 `json:"wait_sec,omitempty"`
+}
+
+// Validate validate option.
+func (opt TrafficShiftOption) Validate() error {
+	if len(opt.ManagementDetailID) == 0 && opt.WaitSec <= 0 {
+		return fmt.Errorf("management_detail_id or wait_sec is required")
+	}
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return request params.
+func (act TrafficShiftAction) ParameterNew() (params any) {
+	return new(TrafficShiftOption)
+}
+
+// Name return action name
+func (act TrafficShiftAction) Name() enumor.ActionName {
+	return enumor.ActionTargetGroupTrafficShift
+}
+
+// Run 执行前先处理控制指令并检查新RS的健康状态，切换已暂停、中止、完成或回滚时直接跳过，重试时已完成的步骤也会跳过
+func (act TrafficShiftAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*TrafficShiftOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if len(opt.ManagementDetailID) != 0 {
+		details, err := listTaskDetail(kt.Kit(), []string{opt.ManagementDetailID})
+		if err != nil {
+			return nil, err
+		}
+		if details[0].State == enumor.TaskDetailCancel || details[0].State == enumor.TaskDetailSuccess {
+			return fmt.Sprintf("task detail %s is %s", details[0].ID, details[0].State), nil
+		}
+	}
+
+	reason, err := act.checkpoint(kt.Kit(), opt.TaskManagementID)
+	if err != nil || len(reason) > 0 {
+		return reason, err
+	}
+
+	if len(opt.ManagementDetailID) == 0 {
+		return act.observe(kt.Kit(), opt)
+	}
+
+	mgmt, err := getTaskManagement(kt.Kit(), opt.TaskManagementID)
+	if err != nil {
+		return nil, err
+	}
+	return nil, act.applyStep(kt.Kit(), mgmt, opt)
+}
+
+// observe 两步之间的观察，期间定期处理控制指令和检查RS健康状态
+func (act TrafficShiftAction) observe(kt *kit.Kit, opt *TrafficShiftOption) (any, error) {
+	end := time.Now().Add(time.Duration(opt.WaitSec) * time.Second)
+	for {
+		wait := time.Until(end)
+		if wait <= 0 {
+			return nil, nil
+		}
+		select {
+		case <-kt.Ctx.Done():
+			return nil, kt.Ctx.Err()
+		case <-time.After(min(wait, trafficShiftPollInterval)):
+		}
+
+		reason, err := act.checkpoint(kt, opt.TaskManagementID)
+		if err != nil || len(reason) > 0 {
+			return reason, err
+		}
+	}
+}
+
+// checkpoint 处理控制指令并检查RS健康状态，返回不为空时表示流量切换已暂停或结束，后续任务不再执行
+func (act TrafficShiftAction) checkpoint(kt *kit.Kit, mgmtID string) (string, error) {
+	mgmt, err := getTaskManagement(kt, mgmtID)
+	if err != nil {
+		return "", err
+	}
+	if mgmt.State != enumor.TaskManagementRunning {
+		return fmt.Sprintf("task management %s is %s", mgmt.ID, mgmt.State), nil
+	}
+	plan, progress := mgmt.Extension.TrafficShift, mgmt.Extension.TrafficShiftProgress
+	if progress.State != enumor.TrafficShiftRunning {
+		return fmt.Sprintf("traffic shift is %s", progress.State), nil
+	}
+
+	switch mgmt.Extension.TrafficShiftControl {
+	case enumor.TrafficShiftControlPause:
+		progress.State = enumor.TrafficShiftPaused
+		if err = updateTrafficShiftProgress(kt, mgmt, progress); err != nil {
+			return "", err
+		}
+		return "traffic shift paused", nil
+
+	case enumor.TrafficShiftControlPromote:
+		if err = act.modifyWeight(kt, plan, plan.Steps); err != nil {
+			return "", err
+		}
+		if err = finishRemainSteps(kt, mgmt.ID, enumor.TaskDetailSuccess, nil); err != nil {
+			return "", err
+		}
+		progress.Step, progress.State = plan.Steps, enumor.TrafficShiftSuccess
+		if err = updateTrafficShiftProgress(kt, mgmt, progress); err != nil {
+			return "", err
+		}
+		return "traffic shift promoted", nil
+
+	case enumor.TrafficShiftControlAbort:
+		if err = act.modifyWeight(kt, plan, 0); err != nil {
+			return "", err
+		}
+		if err = finishRemainSteps(kt, mgmt.ID, enumor.TaskDetailCancel, nil); err != nil {
+			return "", err
+		}
+		progress.State = enumor.TrafficShiftAborted
+		if err = updateTrafficShiftProgress(kt, mgmt, progress); err != nil {
+			return "", err
+		}
+		return "traffic shift aborted", nil
+	}
+
+	unhealthy, err := act.unhealthyPercent(kt, plan)
+	if err != nil {
+		return "", err
+	}
+	if unhealthy <= plan.MaxUnhealthyPercent {
+		return "", nil
+	}
+
+	// 不健康的新RS超过阈值，回滚到切换前的权重
+	reason := fmt.Sprintf("unhealthy new targets %d%% exceed threshold %d%%, rolled back at step %d",
+		unhealthy, plan.MaxUnhealthyPercent, progress.Step)
+	logs.Warnf("traffic shift rollback, task management: %s, reason: %s, rid: %s", mgmt.ID, reason, kt.Rid)
+	if err = act.modifyWeight(kt, plan, 0); err != nil {
+		return "", err
+	}
+	if err = finishRemainSteps(kt, mgmt.ID, enumor.TaskDetailFailed, errors.New(reason)); err != nil {
+		return "", err
+	}
+	progress.State, progress.Reason = enumor.TrafficShiftRolledBack, reason
+	if err = updateTrafficShiftProgress(kt, mgmt, progress); err != nil {
+		return "", err
+	}
+	updateReq := &datatask.UpdateManagementReq{
+		Items: []datatask.UpdateTaskManagementField{{ID: mgmt.ID, State: enumor.TaskManagementFailed}},
+	}
+	if err = actcli.GetDataService().Global.TaskManagement.Update(kt, updateReq); err != nil {
+		logs.Errorf("update task management state failed, err: %v, id: %s, rid: %s", err, mgmt.ID, kt.Rid)
+		return "", err
+	}
+	return reason, nil
+}
+
+// applyStep 将RS权重调整到第opt.Step步的权重
+func (act TrafficShiftAction) applyStep(kt *kit.Kit, mgmt *coretask.Management, opt *TrafficShiftOption) error {
+	detailIDs := []string{opt.ManagementDetailID}
+	if err := batchUpdateTaskDetailState(kt, detailIDs, enumor.TaskDetailRunning); err != nil {
+		logs.Errorf("fail to update task detail state, err: %v, opt: %+v rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	plan := mgmt.Extension.TrafficShift
+	err := act.modifyWeight(kt, plan, opt.Step)
+	state := enumor.TaskDetailSuccess
+	if err != nil {
+		state = enumor.TaskDetailFailed
+	}
+	if updateErr := batchUpdateTaskDetailResultState(kt, detailIDs, state, nil, err); updateErr != nil {
+		logs.Errorf("fail to update task detail state, err: %v, opt: %+v rid: %s", updateErr, opt, kt.Rid)
+		return updateErr
+	}
+	if err != nil {
+		return err
+	}
+
+	progress := &corelb.TrafficShiftProgress{Step: opt.Step, State: enumor.TrafficShiftRunning}
+	if opt.Step >= plan.Steps {
+		progress.State = enumor.TrafficShiftSuccess
+	}
+	return updateTrafficShiftProgress(kt, mgmt, progress)
+}
+
+// modifyWeight 将计划中所有RS的权重调整为第step步完成后的权重，step为0时即回滚到切换前的权重
+func (act TrafficShiftAction) modifyWeight(kt *kit.Kit, plan *corelb.TrafficShiftPlan, step int) error {
+	if plan.Vendor != enumor.TCloud {
+		return fmt.Errorf("vendor: %s not support traffic shift", plan.Vendor)
+	}
+
+	req := &hclb.TCloudBatchOperateTargetReq{
+		TargetGroupID: plan.TargetGroupID,
+		LbID:          plan.LbID,
+		RsList:        make([]*dataproto.TargetBaseReq, 0, len(plan.Targets)),
+	}
+	for _, target := range plan.Targets {
+		weight := target.WeightAt(step, plan.Steps)
+		req.RsList = append(req.RsList, &dataproto.TargetBaseReq{
+			ID:          target.ID,
+			IP:          target.IP,
+			InstType:    target.InstType,
+			CloudInstID: target.CloudInstID,
+			Port:        target.Port,
+			Weight:      cvt.ValToPtr(weight),
+			NewWeight:   cvt.ValToPtr(weight),
+		})
+	}
+	if err := actcli.GetHCService().TCloud.Clb.BatchModifyTargetWeight(kt, plan.TargetGroupID, req); err != nil {
+		logs.Errorf("traffic shift modify target weight failed, err: %v, tgID: %s, step: %d, rid: %s", err,
+			plan.TargetGroupID, step, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// unhealthyPercent 新RS中不健康RS的占比，云上未返回健康状态的新RS(如注册失败或已被解绑)按不健康计算
+func (act TrafficShiftAction) unhealthyPercent(kt *kit.Kit, plan *corelb.TrafficShiftPlan) (int64, error) {
+	req := &hclb.TCloudTargetHealthReq{
+		AccountID:  plan.AccountID,
+		Region:     plan.Region,
+		CloudLbIDs: []string{plan.CloudLbID},
+	}
+	resp, err := actcli.GetHCService().TCloud.Clb.ListTargetHealth(kt, req)
+	if err != nil {
+		logs.Errorf("list target health failed, err: %v, lb: %s, rid: %s", err, plan.CloudLbID, kt.Rid)
+		return 0, err
+	}
+
+	healthMap := make(map[string]bool)
+	for _, lb := range resp.Details {
+		for _, lbl := range lb.Listeners {
+			if lbl.CloudLblID != plan.CloudLblID {
+				continue
+			}
+			targets := lbl.Targets
+			if plan.RuleType == enumor.Layer7RuleType {
+				targets = nil
+				for _, rule := range lbl.Rules {
+					if rule.CloudRuleID == plan.CloudRuleID {
+						targets = rule.Targets
+					}
+				}
+			}
+			for _, target := range targets {
+				healthMap[fmt.Sprintf("%s:%d", target.CloudInstID, target.Port)] = target.HealthStatus
+				healthMap[fmt.Sprintf("%s:%d", target.IP, target.Port)] = target.HealthStatus
+			}
+		}
+	}
+
+	var total, unhealthy int64
+	for _, target := range plan.Targets {
+		if target.Role != enumor.TrafficShiftTargetNew {
+			continue
+		}
+		healthy, exist := healthMap[fmt.Sprintf("%s:%d", target.CloudInstID, target.Port)]
+		if !exist {
+			healthy, exist = healthMap[fmt.Sprintf("%s:%d", target.IP, target.Port)]
+		}
+		total++
+		if !exist || !healthy {
+			unhealthy++
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return unhealthy * 100 / total, nil
+}
+
+// Rollback 流量切换失败时的回滚Action，权重回滚由中止或健康检查不通过时主动处理，此处不需要回滚处理
+func (act TrafficShiftAction) Rollback(kt run.ExecuteKit, params any) error {
+	logs.Infof(" ----------- TrafficShiftAction Rollback -----------, params: %s, rid: %s", params, kt.Kit().Rid)
+	return nil
+}
+
+func getTaskManagement(kt *kit.Kit, id string) (*coretask.Management, error) {
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := actcli.GetDataService().Global.TaskManagement.List(kt, req)
+	if err != nil {
+		logs.Errorf("list task management failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+	if len(resp.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "task management %s not found", id)
+	}
+	mgmt := resp.Details[0]
+	if mgmt.Extension == nil || mgmt.Extension.TrafficShift == nil {
+		return nil, fmt.Errorf("task management %s is not a traffic shift task", id)
+	}
+	if mgmt.Extension.TrafficShiftProgress == nil {
+		mgmt.Extension.TrafficShiftProgress = &corelb.TrafficShiftProgress{State: enumor.TrafficShiftRunning}
+	}
+	return &mgmt, nil
+}
+
+// updateTrafficShiftProgress 只更新进度，不覆盖扩展字段中的计划和控制指令
+func updateTrafficShiftProgress(kt *kit.Kit, mgmt *coretask.Management,
+	progress *corelb.TrafficShiftProgress) error {
+
+	updateReq := &datatask.UpdateManagementReq{
+		Items: []datatask.UpdateTaskManagementField{{
+			ID: mgmt.ID,
+			Extension: &coretask.ManagementExt{
+				RegionIDs:            mgmt.Extension.RegionIDs,
+				TrafficShiftProgress: progress,
+			},
+		}},
+	}
+	if err := actcli.GetDataService().Global.TaskManagement.Update(kt, updateReq); err != nil {
+		logs.Errorf("update traffic shift progress failed, err: %v, id: %s, progress: %+v, rid: %s", err, mgmt.ID,
+			progress, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+// finishRemainSteps 将尚未执行的步骤置为终态
+func finishRemainSteps(kt *kit.Kit, mgmtID string, state enumor.TaskDetailState, reason error) error {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("task_management_id", mgmtID),
+			tools.RuleIn("state", []enumor.TaskDetailState{enumor.TaskDetailInit, enumor.TaskDetailRunning}),
+		),
+		Fields: []string{"id"},
+		Page:   core.NewDefaultBasePage(),
+	}
+	resp, err := actcli.GetDataService().Global.TaskDetail.List(kt, req)
+	if err != nil {
+		logs.Errorf("list task detail failed, err: %v, task management: %s, rid: %s", err, mgmtID, kt.Rid)
+		return err
+	}
+	if len(resp.Details) == 0 {
+		return nil
+	}
+	ids := slice.Map(resp.Details, func(detail coretask.Detail) string { return detail.ID })
+	return batchUpdateTaskDetailResultState(kt, ids, state, nil, reason)
+}
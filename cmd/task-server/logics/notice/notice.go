@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package notice 异步任务流通知
+package notice
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/consumer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/kit"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+const (
+	deadlineExceededTitle   = "【HCM】 任务流超过截止时间已取消：%s(%s)"
+	deadlineExceededContent = `<p>任务流超过截止时间仍未结束，已取消并回滚执行中的任务，请关注任务执行结果。</p>
+<p>任务流ID：%s</p>
+<p>任务流名称：%s</p>
+<p>备注：%s</p>
+<p>截止时间：%s</p>
+<p>取消前状态：%s</p>
+<p>创建者：%s</p>
+<p>创建时间：%s</p>`
+)
+
+// NewCmsiFlowNotifier 通过CMSI发送邮件的任务流通知，接收人为任务流创建者及指定接收人
+func NewCmsiFlowNotifier(cli cmsi.Client, receivers []string) consumer.FlowNotifier {
+	return &cmsiFlowNotifier{
+		cli:       cli,
+		receivers: receivers,
+	}
+}
+
+type cmsiFlowNotifier struct {
+	cli       cmsi.Client
+	receivers []string
+}
+
+// NotifyFlowDeadlineExceeded 任务流超过截止时间被取消后发送邮件通知
+func (n *cmsiFlowNotifier) NotifyFlowDeadlineExceeded(kt *kit.Kit, flow model.Flow) error {
+	receivers := make([]string, 0, len(n.receivers)+1)
+	// 后台创建的任务流没有实际的创建者，只通知指定接收人
+	if len(flow.Creator) != 0 && flow.Creator != constant.BackendOperationUserKey {
+		receivers = append(receivers, flow.Creator)
+	}
+	receivers = slice.Unique(append(receivers, n.receivers...))
+	if len(receivers) == 0 {
+		return nil
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(receivers, ","),
+		Title:            fmt.Sprintf(deadlineExceededTitle, flow.Name, flow.ID),
+		Content: fmt.Sprintf(deadlineExceededContent, flow.ID, flow.Name, flow.Memo,
+			converter.PtrToVal(flow.Deadline), flow.State, flow.Creator, flow.CreatedAt),
+	}
+	return n.cli.SendMail(kt, mail)
+}
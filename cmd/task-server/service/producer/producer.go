@@ -43,6 +43,10 @@ func Init(cap *capability.Capability) {
 	h.Add("CreateCustomFlow", "POST", "/custom_flows/create", svc.CreateCustomFlow)
 	h.Add("CloneFlow", "POST", "/flows/{flow_id}/clone", svc.CloneFlow)
 
+	h.Add("CreateFlowSchedule", "POST", "/flow_schedules/create", svc.CreateFlowSchedule)
+	h.Add("UpdateFlowSchedule", "PATCH", "/flow_schedules/{id}", svc.UpdateFlowSchedule)
+	h.Add("DeleteFlowSchedule", "DELETE", "/flow_schedules/{id}", svc.DeleteFlowSchedule)
+
 	h.Load(cap.WebService)
 }
 
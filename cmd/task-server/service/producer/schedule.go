@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateFlowSchedule create flow schedule.
+func (p service) CreateFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	// 请求体使用的是 taskserver.CreateFlowScheduleReq，解析使用 producer.CreateFlowScheduleOption，同 CreateTemplateFlow
+	opt := new(producer.CreateFlowScheduleOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	id, err := p.pro.CreateFlowSchedule(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("create flow schedule failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// UpdateFlowSchedule update flow schedule.
+func (p service) UpdateFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	opt := new(producer.UpdateFlowScheduleOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+	opt.ID = id
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := p.pro.UpdateFlowSchedule(cts.Kit, opt); err != nil {
+		logs.Errorf("update flow schedule failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteFlowSchedule delete flow schedule.
+func (p service) DeleteFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := p.pro.DeleteFlowSchedule(cts.Kit, id); err != nil {
+		logs.Errorf("delete flow schedule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
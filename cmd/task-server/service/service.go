@@ -29,6 +29,7 @@ import (
 	"time"
 
 	logicsaction "hcm/cmd/task-server/logics/action"
+	"hcm/cmd/task-server/logics/notice"
 	"hcm/cmd/task-server/service/capability"
 	"hcm/cmd/task-server/service/controller"
 	"hcm/cmd/task-server/service/producer"
@@ -51,6 +52,7 @@ import (
 	restcli "hcm/pkg/rest/client"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
@@ -112,14 +114,22 @@ func NewService(sd serviced.ServiceDiscover, shutdownWaitTimeSec int) (*Service,
 }
 
 func createAndStartAsync(sd serviced.ServiceDiscover, globalCfgCli *global.GlobalConfigsClient, dao dao.Set, shutdownWaitTimeSec int) (async.Async, error) {
+	cfg := cc.TaskServer().Async
+
 	// 创建async框架使用的backend
-	bd, err := backend.Factory(enumor.BackendMysql, dao)
+	bd, err := backend.Factory(cfg.Backend, dao)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.Backend == enumor.BackendMemory {
+		logs.Warnf("async use memory backend, flows will be lost after task-server restart")
+	}
 
-	leader := leader.NewLeader(sd)
-	cfg := cc.TaskServer().Async
+	// 创建async框架使用的选主器
+	ld, err := leader.Factory(cfg.Leader, sd, dao)
+	if err != nil {
+		return nil, err
+	}
 	opt := &async.Option{
 		Register: metrics.Register(),
 		ConsumerOption: &consumer.Option{
@@ -150,9 +160,25 @@ func createAndStartAsync(sd serviced.ServiceDiscover, globalCfgCli *global.Globa
 				ShutdownWaitTimeSec: uint(shutdownWaitTimeSec),
 				WorkerNumber:        cfg.WatchDog.WorkerNumber,
 			},
+			ScheduleTrigger: &consumer.ScheduleTriggerOption{
+				WatchIntervalSec:    cfg.ScheduleTrigger.WatchIntervalSec,
+				FetcherConcurrency:  cfg.ScheduleTrigger.FetcherConcurrency,
+				MisfireThresholdSec: cfg.ScheduleTrigger.MisfireThresholdSec,
+			},
 		},
 	}
-	async, err := async.NewAsync(bd, leader, opt)
+
+	if cfg.Notice.Enabled {
+		cmsiCfg := cc.TaskServer().Cmsi
+		cmsiCli, err := cmsi.NewClient(&cmsiCfg, metrics.Register())
+		if err != nil {
+			logs.Errorf("failed to create cmsi client, err: %v", err)
+			return nil, err
+		}
+		opt.ConsumerOption.Notifier = notice.NewCmsiFlowNotifier(cmsiCli, cfg.Notice.Receivers)
+	}
+
+	async, err := async.NewAsync(bd, ld, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +74,7 @@ func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
 		ShareData: one.ShareData,
 		Memo:      one.Memo,
 		Worker:    one.Worker,
+		Deadline:  one.Deadline,
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
@@ -0,0 +1,311 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// GetFlowGraph get flow dag with task timings, critical path and estimated remaining time.
+func (svc *service) GetFlowGraph(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	flowResult, err := svc.dao.AsyncFlow().List(cts.Kit, &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+	if len(flowResult.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
+	}
+
+	tasks, err := svc.listFlowTasks(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := buildFlowGraph(flowResult.Details[0], tasks, svc.avgExecTime, time.Now())
+	if err != nil {
+		logs.Errorf("build flow graph failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, errf.NewFromErr(errf.Aborted, err)
+	}
+
+	return graph, nil
+}
+
+func (svc *service) listFlowTasks(kt *kit.Kit, flowID string) ([]tableasync.AsyncFlowTaskTable, error) {
+	tasks := make([]tableasync.AsyncFlowTaskTable, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		result, err := svc.dao.AsyncFlowTask().List(kt, &types.ListOption{
+			Filter: tools.EqualExpression("flow_id", flowID),
+			Page:   page,
+		})
+		if err != nil {
+			logs.Errorf("list flow task failed, err: %v, flow: %s, rid: %s", err, flowID, kt.Rid)
+			return nil, err
+		}
+
+		tasks = append(tasks, result.Details...)
+		if uint(len(result.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return tasks, nil
+}
+
+// avgExecTime 获取当前节点统计的任务类型平均执行时间
+func (svc *service) avgExecTime(actionName enumor.ActionName) (float64, bool) {
+	if svc.async == nil {
+		return 0, true
+	}
+
+	return svc.async.GetConsumer().GetTaskTypeAvgExecTime(actionName)
+}
+
+type graphTask struct {
+	task     tableasync.AsyncFlowTaskTable
+	node     *ts.FlowGraphNode
+	parents  []int
+	children []int
+
+	start, end       time.Time
+	started, ended   bool
+	remaining        float64
+	pathSec, restSec float64
+	prev             int
+}
+
+// buildFlowGraph 根据任务依赖构建DAG。db中没有记录任务开始时间，任务开始时间按照依赖任务中最晚的结束时间推算，
+// 无依赖的任务以任务流创建时间作为开始时间；未结束任务的耗时优先使用同一任务流中同类型任务的平均耗时，
+// 其次使用执行器统计的该类型任务平均耗时。
+func buildFlowGraph(flow tableasync.AsyncFlowTable, tasks []tableasync.AsyncFlowTaskTable,
+	avgExecTime func(enumor.ActionName) (float64, bool), now time.Time) (*ts.FlowGraphResult, error) {
+
+	nodes, order, err := sortFlowTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	flowCreatedAt, _ := time.Parse(time.RFC3339, string(flow.CreatedAt))
+	sumMap, cntMap := make(map[enumor.ActionName]float64), make(map[enumor.ActionName]int)
+	for _, idx := range order {
+		one := nodes[idx]
+		fillTaskTiming(one, nodes, flowCreatedAt, now)
+		if one.ended && one.task.State == enumor.TaskSuccess {
+			sumMap[one.task.ActionName] += one.node.DurationSec
+			cntMap[one.task.ActionName]++
+		}
+	}
+
+	for _, idx := range order {
+		one := nodes[idx]
+		if one.ended {
+			continue
+		}
+
+		estimated := 0.0
+		if cnt := cntMap[one.task.ActionName]; cnt > 0 {
+			estimated = sumMap[one.task.ActionName] / float64(cnt)
+		} else if avgExecTime != nil {
+			if avg, neverExec := avgExecTime(one.task.ActionName); !neverExec {
+				estimated = avg
+			}
+		}
+
+		one.node.DurationSec = max(estimated, one.node.ElapsedSec)
+		one.node.Estimated = true
+		one.remaining = one.node.DurationSec - one.node.ElapsedSec
+	}
+
+	result := &ts.FlowGraphResult{
+		Flow:         convCoreFlow(flow),
+		Nodes:        make([]ts.FlowGraphNode, 0, len(nodes)),
+		Edges:        make([]ts.FlowGraphEdge, 0),
+		CriticalPath: make([]string, 0),
+	}
+
+	// 按拓扑序计算到达每个节点的最长路径，即关键路径
+	last, restSec := -1, 0.0
+	for _, idx := range order {
+		one := nodes[idx]
+		one.prev = -1
+		for _, p := range one.parents {
+			if one.prev == -1 || nodes[p].pathSec > nodes[one.prev].pathSec {
+				one.prev = p
+			}
+			one.restSec = max(one.restSec, nodes[p].restSec)
+		}
+		one.pathSec = one.node.DurationSec
+		if one.prev != -1 {
+			one.pathSec += nodes[one.prev].pathSec
+		}
+		one.restSec += one.remaining
+
+		if last == -1 || one.pathSec > nodes[last].pathSec {
+			last = idx
+		}
+		restSec = max(restSec, one.restSec)
+	}
+
+	for cur := last; cur != -1; cur = nodes[cur].prev {
+		nodes[cur].node.OnCriticalPath = true
+		result.CriticalPath = append([]string{nodes[cur].task.ID}, result.CriticalPath...)
+	}
+	if last != -1 {
+		result.CriticalPathSec = nodes[last].pathSec
+	}
+
+	switch flow.State {
+	case enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel:
+	default:
+		result.EstimatedRemainingSec = &restSec
+	}
+
+	for _, one := range nodes {
+		result.Nodes = append(result.Nodes, *one.node)
+		for _, child := range one.children {
+			result.Edges = append(result.Edges, ts.FlowGraphEdge{From: one.task.ID, To: nodes[child].task.ID})
+		}
+	}
+
+	return result, nil
+}
+
+// sortFlowTasks 根据任务依赖关系构建节点，并返回拓扑排序后的节点下标
+func sortFlowTasks(tasks []tableasync.AsyncFlowTaskTable) ([]*graphTask, []int, error) {
+	if len(tasks) == 0 {
+		return nil, nil, errors.New("flow has no tasks")
+	}
+
+	nodes := make([]*graphTask, 0, len(tasks))
+	actionMap := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		if _, exists := actionMap[task.ActionID]; exists {
+			return nil, nil, fmt.Errorf("task actionID is repeat, actionID: %s", task.ActionID)
+		}
+		actionMap[task.ActionID] = i
+		nodes = append(nodes, &graphTask{
+			task: task,
+			node: &ts.FlowGraphNode{
+				TaskID:     task.ID,
+				ActionID:   task.ActionID,
+				ActionName: task.ActionName,
+				State:      task.State,
+				Reason:     task.Reason,
+			},
+		})
+	}
+
+	inDegree := make([]int, len(nodes))
+	for i, one := range nodes {
+		for _, dependOn := range one.task.DependOn {
+			parent, exists := actionMap[dependOn]
+			if !exists {
+				return nil, nil, fmt.Errorf("does not find task[%s] depend: %s", one.task.ID, dependOn)
+			}
+			one.parents = append(one.parents, parent)
+			nodes[parent].children = append(nodes[parent].children, i)
+			inDegree[i]++
+		}
+	}
+
+	order := make([]int, 0, len(nodes))
+	for i := range nodes {
+		if inDegree[i] == 0 {
+			order = append(order, i)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, child := range nodes[order[i]].children {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				order = append(order, child)
+			}
+		}
+	}
+	if len(order) != len(nodes) {
+		return nil, nil, errors.New("flow tasks has cycle")
+	}
+
+	return nodes, order, nil
+}
+
+// fillTaskTiming 推算任务的开始、结束时间及已执行耗时，调用时依赖任务的时间需已推算完成
+func fillTaskTiming(one *graphTask, nodes []*graphTask, flowCreatedAt time.Time, now time.Time) {
+	switch one.task.State {
+	case enumor.TaskInit, enumor.TaskPending:
+		return
+	}
+
+	one.start, one.started = flowCreatedAt, !flowCreatedAt.IsZero()
+	for _, p := range one.parents {
+		if !nodes[p].ended {
+			continue
+		}
+		if !one.started || nodes[p].end.After(one.start) {
+			one.start, one.started = nodes[p].end, true
+		}
+	}
+
+	switch one.task.State {
+	case enumor.TaskSuccess, enumor.TaskFailed, enumor.TaskCancel:
+		end, err := time.Parse(time.RFC3339, string(one.task.UpdatedAt))
+		if err == nil {
+			one.end, one.ended = end, true
+		}
+	}
+
+	if !one.started {
+		return
+	}
+	one.node.StartedAt = one.start.Format(time.RFC3339)
+
+	end := now
+	if one.ended {
+		end = one.end
+		one.node.EndedAt = one.end.Format(time.RFC3339)
+	}
+	one.node.ElapsedSec = max(end.Sub(one.start).Seconds(), 0)
+	if one.ended {
+		one.node.DurationSec = one.node.ElapsedSec
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFlowGraph(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(sec int) types.Time {
+		return types.Time(base.Add(time.Duration(sec) * time.Second).Format(time.RFC3339))
+	}
+
+	flow := tableasync.AsyncFlowTable{ID: "flow", State: enumor.FlowRunning, CreatedAt: at(0)}
+	// 1 -> 2 -> 4, 1 -> 3 -> 4
+	tasks := []tableasync.AsyncFlowTaskTable{
+		{ID: "t1", ActionID: "1", ActionName: "a", State: enumor.TaskSuccess, UpdatedAt: at(10)},
+		{ID: "t2", ActionID: "2", ActionName: "b", State: enumor.TaskSuccess, DependOn: []string{"1"},
+			UpdatedAt: at(20)},
+		{ID: "t3", ActionID: "3", ActionName: "c", State: enumor.TaskRunning, DependOn: []string{"1"}},
+		{ID: "t4", ActionID: "4", ActionName: "b", State: enumor.TaskPending, DependOn: []string{"2", "3"}},
+	}
+	avg := func(name enumor.ActionName) (float64, bool) {
+		if name == "c" {
+			return 100, false
+		}
+		return 0, true
+	}
+
+	graph, err := buildFlowGraph(flow, tasks, avg, base.Add(40*time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, graph.Nodes, 4)
+	assert.Len(t, graph.Edges, 4)
+
+	// t3 已运行30秒，预估100秒，t4 使用同一任务流中 b 类型任务的耗时10秒
+	assert.Equal(t, 30.0, graph.Nodes[2].ElapsedSec)
+	assert.Equal(t, 100.0, graph.Nodes[2].DurationSec)
+	assert.Equal(t, 10.0, graph.Nodes[3].DurationSec)
+	assert.True(t, graph.Nodes[3].Estimated)
+
+	assert.Equal(t, []string{"t1", "t3", "t4"}, graph.CriticalPath)
+	assert.Equal(t, 120.0, graph.CriticalPathSec)
+	assert.NotNil(t, graph.EstimatedRemainingSec)
+	assert.Equal(t, 80.0, *graph.EstimatedRemainingSec)
+}
+
+func TestBuildFlowGraphWithCycle(t *testing.T) {
+	tasks := []tableasync.AsyncFlowTaskTable{
+		{ID: "t1", ActionID: "1", DependOn: []string{"2"}},
+		{ID: "t2", ActionID: "2", DependOn: []string{"1"}},
+	}
+	_, err := buildFlowGraph(tableasync.AsyncFlowTable{}, tasks, nil, time.Now())
+	assert.Error(t, err)
+}
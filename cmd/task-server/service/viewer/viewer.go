@@ -22,6 +22,7 @@ package viewer
 
 import (
 	"hcm/cmd/task-server/service/capability"
+	"hcm/pkg/async"
 	"hcm/pkg/client"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/rest"
@@ -30,21 +31,25 @@ import (
 // Init initial the async service
 func Init(cap *capability.Capability) {
 	svc := &service{
-		cs:  cap.ApiClient,
-		dao: cap.Dao,
+		cs:    cap.ApiClient,
+		dao:   cap.Dao,
+		async: cap.Async,
 	}
 
 	h := rest.NewHandler()
 
 	h.Add("ListFlow", "POST", "/flows/list", svc.ListFlow)
 	h.Add("GetFlow", "GET", "/flows/{id}", svc.GetFlow)
+	h.Add("GetFlowGraph", "GET", "/flows/{id}/graph", svc.GetFlowGraph)
 	h.Add("ListTask", "POST", "/tasks/list", svc.ListTask)
 	h.Add("GetTask", "GET", "/tasks/{id}", svc.GetTask)
+	h.Add("ListFlowSchedule", "POST", "/flow_schedules/list", svc.ListFlowSchedule)
 
 	h.Load(cap.WebService)
 }
 
 type service struct {
-	cs  *client.ClientSet
-	dao dao.Set
+	cs    *client.ClientSet
+	dao   dao.Set
+	async async.Async
 }
@@ -45,6 +45,7 @@ import (
 	"hcm/pkg/cc"
 	apiclient "hcm/pkg/client"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/objectstore"
 	"hcm/pkg/handler"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/kit"
@@ -79,6 +80,8 @@ type Service struct {
 	// noticeCli notification center client
 	noticeCli pkgnotice.Client
 	cmdbCli   pkgcmdb.Client
+	// localObject 本地文件存储，未配置时为nil
+	localObject *objectstore.LocalDisk
 }
 
 // NewService create a service instance.
@@ -141,17 +144,32 @@ func NewService(dis serviced.Discover) (*Service, error) {
 		return nil, err
 	}
 
+	localObject, err := newLocalObjectStore()
+	if err != nil {
+		logs.Errorf("failed to create local object store, err: %v", err)
+		return nil, err
+	}
+
 	return &Service{
-		client:     apiClientSet,
-		esbClient:  esbClient,
-		proxy:      p,
-		authorizer: authorizer,
-		itsmCli:    itsmCli,
-		noticeCli:  noticeCli,
-		cmdbCli:    cmdbCli,
+		client:      apiClientSet,
+		esbClient:   esbClient,
+		proxy:       p,
+		authorizer:  authorizer,
+		itsmCli:     itsmCli,
+		noticeCli:   noticeCli,
+		cmdbCli:     cmdbCli,
+		localObject: localObject,
 	}, nil
 }
 
+func newLocalObjectStore() (*objectstore.LocalDisk, error) {
+	localCfg := cc.WebServer().LocalObjectStore
+	if len(localCfg.RootDir) == 0 {
+		return nil, nil
+	}
+	return objectstore.NewLocalDisk(localCfg)
+}
+
 func newNotificationClient() (pkgnotice.Client, error) {
 	noticeCfg := cc.WebServer().Notice
 	if !noticeCfg.Enable {
@@ -204,6 +222,9 @@ func (s *Service) ListenAndServeRest() error {
 	container.Add(s.apiSet())
 	container.Add(s.proxyApiSet("/api/v1/cloud"))
 	container.Add(s.proxyApiSet("/api/v1/account"))
+	if s.localObject != nil {
+		container.Add(s.localObjectSet())
+	}
 	container.Add(s.indexSet())
 
 	root.Handle("/", container)
@@ -321,6 +342,21 @@ func (s *Service) staticFileHandleFunc(req *restful.Request, resp *restful.Respo
 	http.ServeFile(resp.ResponseWriter, req.Request, actual)
 }
 
+// localObjectSet 本地文件存储没有独立的访问入口，由 web-server 提供预签名URL的上传、下载服务，
+// URL中的签名即为访问凭证，不经过用户认证
+func (s *Service) localObjectSet() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path("/local_objects")
+	ws.Route(ws.GET("/{action}").To(s.localObjectHandleFunc))
+	ws.Route(ws.PUT("/{action}").To(s.localObjectHandleFunc))
+
+	return ws
+}
+
+func (s *Service) localObjectHandleFunc(req *restful.Request, resp *restful.Response) {
+	s.localObject.ServeHTTP(resp.ResponseWriter, req.Request)
+}
+
 func (s *Service) indexSet() *restful.WebService {
 	ws := new(restful.WebService)
 	// 所有前缀未匹配到的URL都将返回index.html
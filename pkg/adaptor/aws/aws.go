@@ -32,7 +32,7 @@ func NewAws(s *types.BaseSecret, cloudAccountID string, site enumor.AccountSiteT
 		return nil, err
 	}
 
-	return &Aws{clientSet: newClientSet(s), cloudAccountID: cloudAccountID, site: site}, nil
+	return &Aws{clientSet: newClientSet(s, cloudAccountID), cloudAccountID: cloudAccountID, site: site}, nil
 }
 
 // Aws is aws operator.
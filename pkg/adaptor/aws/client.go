@@ -20,45 +20,85 @@
 package aws
 
 import (
+	"net/http"
+
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/athena"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	curservice "github.com/aws/aws-sdk-go/service/costandusagereportservice"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 const (
-	ErrDataNotFound       = "InvalidInstanceID.Malformed: Invalid id"
-	ErrDryRunSuccess      = "DryRunOperation: Request would have succeeded, but DryRun flag is set"
-	ErrSGNotFound         = "InvalidGroup.NotFound"
-	ErrRouteTableNotFound = "InvalidRouteTableID.NotFound"
-	ErrImageNotFound      = "InvalidAMIID.NotFound"
-	ErrVpcNotFound        = "InvalidVpcID.NotFound"
-	ErrSubnetNotFound     = "InvalidSubnetID.NotFound"
-	ErrDiskNotFound       = "InvalidVolume.NotFound"
-	ErrCvmNotFound        = "InvalidInstanceID.NotFound"
+	ErrDataNotFound        = "InvalidInstanceID.Malformed: Invalid id"
+	ErrDryRunSuccess       = "DryRunOperation: Request would have succeeded, but DryRun flag is set"
+	ErrSGNotFound          = "InvalidGroup.NotFound"
+	ErrRouteTableNotFound  = "InvalidRouteTableID.NotFound"
+	ErrImageNotFound       = "InvalidAMIID.NotFound"
+	ErrVpcNotFound         = "InvalidVpcID.NotFound"
+	ErrSubnetNotFound      = "InvalidSubnetID.NotFound"
+	ErrDiskNotFound        = "InvalidVolume.NotFound"
+	ErrCvmNotFound         = "InvalidInstanceID.NotFound"
+	ErrElbNotFound         = "LoadBalancerNotFound"
+	ErrListenerNotFound    = "ListenerNotFound"
+	ErrTargetGroupNotFound = "TargetGroupNotFound"
 )
 
 type clientSet struct {
 	credentials *credentials.Credentials
+	// httpClient with rate limiter and metrics of cloud api
+	httpClient *http.Client
 }
 
-func newClientSet(secret *types.BaseSecret) *clientSet {
-	return &clientSet{credentials.NewStaticCredentials(secret.CloudSecretID, secret.CloudSecretKey, "")}
+func newClientSet(secret *types.BaseSecret, cloudAccountID string) *clientSet {
+	account := cloudAccountID
+	if len(account) == 0 {
+		account = secret.RateLimitAccount()
+	}
+
+	return &clientSet{
+		credentials: credentials.NewStaticCredentials(secret.CloudSecretID, secret.CloudSecretKey, ""),
+		httpClient: &http.Client{
+			Transport: ratelimit.NewRoundTripper(enumor.Aws, account, metric.PathApiResolver, nil),
+		},
+	}
+}
+
+// newSession new aws session, the operation name and region is set into the context of http request,
+// aws api name can not be resolved from http request since most of aws api is requested with POST form.
+func (c *clientSet) newSession(cfg *aws.Config) (*session.Session, error) {
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.Handlers.Build.PushBack(func(r *request.Request) {
+		api := r.ClientInfo.ServiceName
+		if r.Operation != nil {
+			api += "." + r.Operation.Name
+		}
+		r.SetContext(metric.WithApiInfo(r.Context(), api, aws.StringValue(r.Config.Region)))
+	})
+	return sess, nil
 }
 
 func (c *clientSet) ec2Client(region string) (*ec2.EC2, error) {
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -70,7 +110,7 @@ func (c *clientSet) ec2Client(region string) (*ec2.EC2, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +123,7 @@ func (c *clientSet) stsClient(region *string) (*sts.STS, error) {
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -92,7 +132,7 @@ func (c *clientSet) stsClient(region *string) (*sts.STS, error) {
 		Region:      region,
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +144,7 @@ func (c *clientSet) athenaClient(region string) (*athena.Athena, error) {
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -116,7 +156,7 @@ func (c *clientSet) athenaClient(region string) (*athena.Athena, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +168,7 @@ func (c *clientSet) organizations() (*organizations.Organizations, error) {
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -136,7 +176,7 @@ func (c *clientSet) organizations() (*organizations.Organizations, error) {
 		SleepDelay:  nil,
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +188,7 @@ func (c *clientSet) s3Client(region string) (*s3.S3, error) {
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -160,7 +200,7 @@ func (c *clientSet) s3Client(region string) (*s3.S3, error) {
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +212,7 @@ func (c *clientSet) costAndUsageReportClient(region string) (*curservice.Costand
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -184,7 +224,7 @@ func (c *clientSet) costAndUsageReportClient(region string) (*curservice.Costand
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -196,7 +236,7 @@ func (c *clientSet) cloudFormationClient(region string) (*cloudformation.CloudFo
 	cfg := &aws.Config{
 		Credentials: c.credentials,
 		DisableSSL:  nil,
-		HTTPClient:  nil,
+		HTTPClient:  c.httpClient,
 		LogLevel:    nil,
 		Logger:      nil,
 		MaxRetries:  nil,
@@ -208,10 +248,34 @@ func (c *clientSet) cloudFormationClient(region string) (*cloudformation.CloudFo
 		cfg.Region = aws.String(region)
 	}
 
-	sess, err := session.NewSession(cfg)
+	sess, err := c.newSession(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	return cloudformation.New(sess, aws.NewConfig().WithRegion(region)), nil
 }
+
+func (c *clientSet) elbv2Client(region string) (*elbv2.ELBV2, error) {
+	cfg := &aws.Config{
+		Credentials: c.credentials,
+		DisableSSL:  nil,
+		HTTPClient:  c.httpClient,
+		LogLevel:    nil,
+		Logger:      nil,
+		MaxRetries:  nil,
+		Retryer:     nil,
+		SleepDelay:  nil,
+	}
+
+	if len(region) != 0 {
+		cfg.Region = aws.String(region)
+	}
+
+	sess, err := c.newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return elbv2.New(sess), nil
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CreateDiskSnapshot 创建云盘快照，快照名称通过 Name 标签设置
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateSnapshot.html
+func (a *Aws) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "aws disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	input := &ec2.CreateSnapshotInput{
+		VolumeId:    aws.String(opt.CloudDiskID),
+		Description: opt.Memo,
+		TagSpecifications: []*ec2.TagSpecification{{
+			ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+			Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(opt.Name)}},
+		}},
+	}
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, input)
+	if err != nil {
+		logs.Errorf("create aws disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	return converter.PtrToVal(resp.SnapshotId), nil
+}
+
+// ListDiskSnapshot 查询当前账号拥有的云盘快照
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeSnapshots.html
+func (a *Aws) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "aws disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.DescribeSnapshotsInput{OwnerIds: aws.StringSlice([]string{"self"})}
+	if len(opt.CloudIDs) != 0 {
+		input.SnapshotIds = aws.StringSlice(opt.CloudIDs)
+	} else {
+		input.MaxResults = aws.Int64(int64(disksnapshot.MaxBatchSize) * 10)
+	}
+	if len(opt.CloudDiskIDs) != 0 {
+		input.Filters = []*ec2.Filter{{Name: aws.String("volume-id"), Values: aws.StringSlice(opt.CloudDiskIDs)}}
+	}
+
+	snapshots := make([]disksnapshot.DiskSnapshot, 0)
+	err = client.DescribeSnapshotsPagesWithContext(kt.Ctx, input,
+		func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+			for _, one := range page.Snapshots {
+				snapshots = append(snapshots, convAwsSnapshot(opt.Region, one))
+			}
+			return true
+		})
+	if err != nil {
+		logs.Errorf("list aws disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func convAwsSnapshot(region string, one *ec2.Snapshot) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:     converter.PtrToVal(one.SnapshotId),
+		Region:      region,
+		CloudDiskID: converter.PtrToVal(one.VolumeId),
+		DiskSize:    uint64(converter.PtrToVal(one.VolumeSize)),
+		Status:      converter.PtrToVal(one.State),
+		Encrypted:   converter.PtrToVal(one.Encrypted),
+		Memo:        one.Description,
+	}
+	if one.StartTime != nil {
+		snapshot.CloudCreatedTime = times.ConvStdTimeFormat(*one.StartTime)
+	}
+	for _, tag := range one.Tags {
+		if converter.PtrToVal(tag.Key) == "Name" {
+			snapshot.Name = converter.PtrToVal(tag.Value)
+		}
+	}
+
+	return snapshot
+}
+
+// DeleteDiskSnapshot 删除云盘快照，aws 不支持批量删除，逐个删除
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteSnapshot.html
+func (a *Aws) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		input := &ec2.DeleteSnapshotInput{SnapshotId: aws.String(cloudID)}
+		if _, err = client.DeleteSnapshotWithContext(kt.Ctx, input); err != nil {
+			logs.Errorf("delete aws disk snapshot failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return fmt.Errorf("delete snapshot %s failed, err: %v", cloudID, err)
+		}
+	}
+
+	return nil
+}
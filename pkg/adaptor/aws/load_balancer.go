@@ -0,0 +1,208 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// ListLoadBalancer 查询elb列表，会同时查询负载均衡的标签
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeLoadBalancers.html
+func (a *Aws) ListLoadBalancer(kt *kit.Kit, opt *typelb.AwsListOption) ([]typelb.AwsLoadBalancer, *string, error) {
+	if opt == nil {
+		return nil, nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := new(elbv2.DescribeLoadBalancersInput)
+	if len(opt.CloudIDs) != 0 {
+		req.LoadBalancerArns = cvt.SliceToPtr(opt.CloudIDs)
+	}
+	if len(opt.Names) != 0 {
+		req.Names = cvt.SliceToPtr(opt.Names)
+	}
+	req.Marker = opt.Marker
+	req.PageSize = opt.PageSize
+
+	resp, err := client.DescribeLoadBalancersWithContext(kt.Ctx, req)
+	if err != nil {
+		if !strings.Contains(err.Error(), ErrElbNotFound) {
+			logs.Errorf("list aws load balancer failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		}
+		return nil, nil, err
+	}
+
+	arns := make([]string, 0, len(resp.LoadBalancers))
+	for _, one := range resp.LoadBalancers {
+		arns = append(arns, cvt.PtrToVal(one.LoadBalancerArn))
+	}
+	tagMap, err := a.listElbTags(kt, client, arns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lbs := make([]typelb.AwsLoadBalancer, 0, len(resp.LoadBalancers))
+	for _, one := range resp.LoadBalancers {
+		lbs = append(lbs, typelb.AwsLoadBalancer{
+			LoadBalancer: one,
+			Tags:         tagMap[cvt.PtrToVal(one.LoadBalancerArn)],
+		})
+	}
+
+	return lbs, resp.NextMarker, nil
+}
+
+// listElbTags 批量查询elb资源标签，返回 arn -> tags
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeTags.html
+func (a *Aws) listElbTags(kt *kit.Kit, client *elbv2.ELBV2, arns []string) (map[string][]*elbv2.Tag, error) {
+	result := make(map[string][]*elbv2.Tag, len(arns))
+	for _, batch := range slice.Split(arns, typelb.AwsElbDescribeTagsMax) {
+		req := &elbv2.DescribeTagsInput{ResourceArns: cvt.SliceToPtr(batch)}
+		resp, err := client.DescribeTagsWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("describe aws elb tags failed, err: %v, arns: %v, rid: %s", err, batch, kt.Rid)
+			return nil, err
+		}
+		for _, desc := range resp.TagDescriptions {
+			if desc == nil {
+				continue
+			}
+			result[cvt.PtrToVal(desc.ResourceArn)] = desc.Tags
+		}
+	}
+	return result, nil
+}
+
+// CountLoadBalancer 返回指定地域下负载均衡数量
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeLoadBalancers.html
+func (a *Aws) CountLoadBalancer(kt *kit.Kit, region string) (int32, error) {
+	client, err := a.clientSet.elbv2Client(region)
+	if err != nil {
+		return 0, err
+	}
+
+	req := &elbv2.DescribeLoadBalancersInput{PageSize: aws.Int64(typelb.AwsElbPageSizeMax)}
+	total := 0
+	for {
+		resp, err := client.DescribeLoadBalancersWithContext(kt.Ctx, req)
+		if err != nil {
+			logs.Errorf("count aws load balancer failed, err: %v, region: %s, rid: %s", err, region, kt.Rid)
+			return 0, err
+		}
+		total += len(resp.LoadBalancers)
+		if resp.NextMarker == nil {
+			break
+		}
+		req.Marker = resp.NextMarker
+	}
+
+	return int32(total), nil
+}
+
+// CreateLoadBalancer 创建负载均衡，返回负载均衡arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateLoadBalancer.html
+func (a *Aws) CreateLoadBalancer(kt *kit.Kit, opt *typelb.AwsCreateLoadBalancerOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.CreateLoadBalancerInput{
+		Name:          aws.String(opt.Name),
+		Type:          aws.String(string(opt.Type)),
+		IpAddressType: opt.IpAddressType,
+		Subnets:       cvt.SliceToPtr(opt.CloudSubnetIDs),
+	}
+	if len(opt.Scheme) != 0 {
+		req.Scheme = aws.String(string(opt.Scheme))
+	}
+	if len(opt.SecurityGroups) != 0 {
+		req.SecurityGroups = cvt.SliceToPtr(opt.SecurityGroups)
+	}
+	for _, tag := range opt.Tags {
+		req.Tags = append(req.Tags, &elbv2.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+
+	resp, err := client.CreateLoadBalancerWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws load balancer failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return "", err
+	}
+
+	if len(resp.LoadBalancers) == 0 || resp.LoadBalancers[0] == nil {
+		return "", errf.New(errf.Unknown, "create aws load balancer succeeded but return empty load balancer")
+	}
+
+	return cvt.PtrToVal(resp.LoadBalancers[0].LoadBalancerArn), nil
+}
+
+// DeleteLoadBalancer 删除负载均衡，aws不支持批量删除，逐个删除
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteLoadBalancer.html
+func (a *Aws) DeleteLoadBalancer(kt *kit.Kit, opt *typelb.AwsDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(cloudID)}
+		if _, err = client.DeleteLoadBalancerWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws load balancer failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
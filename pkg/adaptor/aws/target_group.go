@@ -0,0 +1,249 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	typelb "hcm/pkg/adaptor/types/load-balancer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// ListTargetGroup 查询目标组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeTargetGroups.html
+func (a *Aws) ListTargetGroup(kt *kit.Kit, opt *typelb.AwsListTargetGroupOption) ([]typelb.AwsTargetGroup, *string,
+	error) {
+
+	if opt == nil {
+		return nil, nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.DescribeTargetGroupsInput{
+		Marker:   opt.Marker,
+		PageSize: opt.PageSize,
+	}
+	if len(opt.LoadBalancerID) != 0 {
+		req.LoadBalancerArn = aws.String(opt.LoadBalancerID)
+	}
+	if len(opt.CloudIDs) != 0 {
+		req.TargetGroupArns = cvt.SliceToPtr(opt.CloudIDs)
+	}
+
+	resp, err := client.DescribeTargetGroupsWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("list aws target group failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, nil, err
+	}
+
+	groups := make([]typelb.AwsTargetGroup, 0, len(resp.TargetGroups))
+	for _, one := range resp.TargetGroups {
+		groups = append(groups, typelb.AwsTargetGroup{TargetGroup: one})
+	}
+
+	return groups, resp.NextMarker, nil
+}
+
+// CreateTargetGroup 创建目标组，返回目标组arn
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_CreateTargetGroup.html
+func (a *Aws) CreateTargetGroup(kt *kit.Kit, opt *typelb.AwsCreateTargetGroupOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.CreateTargetGroupInput{
+		Name:                       aws.String(opt.Name),
+		Protocol:                   aws.String(string(opt.Protocol)),
+		Port:                       aws.Int64(opt.Port),
+		VpcId:                      aws.String(opt.CloudVpcID),
+		TargetType:                 opt.TargetType,
+		HealthCheckEnabled:         opt.HealthCheckEnabled,
+		HealthCheckProtocol:        opt.HealthCheckProtocol,
+		HealthCheckPort:            opt.HealthCheckPort,
+		HealthCheckPath:            opt.HealthCheckPath,
+		HealthCheckIntervalSeconds: opt.HealthCheckInterval,
+		HealthCheckTimeoutSeconds:  opt.HealthCheckTimeout,
+		HealthyThresholdCount:      opt.HealthyThresholdCount,
+		UnhealthyThresholdCount:    opt.UnhealthyThresholdCount,
+	}
+
+	resp, err := client.CreateTargetGroupWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("create aws target group failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return "", err
+	}
+
+	if len(resp.TargetGroups) == 0 || resp.TargetGroups[0] == nil {
+		return "", errf.New(errf.Unknown, "create aws target group succeeded but return empty target group")
+	}
+
+	return cvt.PtrToVal(resp.TargetGroups[0].TargetGroupArn), nil
+}
+
+// DeleteTargetGroup 删除目标组
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeleteTargetGroup.html
+func (a *Aws) DeleteTargetGroup(kt *kit.Kit, opt *typelb.AwsDeleteTargetGroupOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		req := &elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(cloudID)}
+		if _, err = client.DeleteTargetGroupWithContext(kt.Ctx, req); err != nil {
+			logs.Errorf("delete aws target group failed, err: %v, arn: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterTargets 向目标组注册后端
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_RegisterTargets.html
+func (a *Aws) RegisterTargets(kt *kit.Kit, opt *typelb.AwsTargetOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "register option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.RegisterTargetsInput{
+		TargetGroupArn: aws.String(opt.TargetGroupID),
+		Targets:        convTargetDescriptions(opt.Targets),
+	}
+	if _, err = client.RegisterTargetsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("register aws targets failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeregisterTargets 从目标组解绑后端
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DeregisterTargets.html
+func (a *Aws) DeregisterTargets(kt *kit.Kit, opt *typelb.AwsTargetOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "deregister option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.DeregisterTargetsInput{
+		TargetGroupArn: aws.String(opt.TargetGroupID),
+		Targets:        convTargetDescriptions(opt.Targets),
+	}
+	if _, err = client.DeregisterTargetsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("deregister aws targets failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func convTargetDescriptions(targets []typelb.AwsTarget) []*elbv2.TargetDescription {
+	result := make([]*elbv2.TargetDescription, 0, len(targets))
+	for _, one := range targets {
+		result = append(result, &elbv2.TargetDescription{
+			Id:               aws.String(one.CloudID),
+			Port:             one.Port,
+			AvailabilityZone: one.Zone,
+		})
+	}
+	return result
+}
+
+// ListTargetHealth 查询目标组下后端及其健康状态
+// reference: https://docs.aws.amazon.com/elasticloadbalancing/latest/APIReference/API_DescribeTargetHealth.html
+func (a *Aws) ListTargetHealth(kt *kit.Kit, opt *typelb.AwsListTargetHealthOption) ([]typelb.AwsTargetHealth,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.elbv2Client(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new aws elbv2 client failed, region: %s, err: %v", opt.Region, err)
+	}
+
+	req := &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(opt.TargetGroupID)}
+	resp, err := client.DescribeTargetHealthWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("list aws target health failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+
+	result := make([]typelb.AwsTargetHealth, 0, len(resp.TargetHealthDescriptions))
+	for _, one := range resp.TargetHealthDescriptions {
+		result = append(result, typelb.AwsTargetHealth{TargetHealthDescription: one})
+	}
+
+	return result, nil
+}
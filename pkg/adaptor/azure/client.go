@@ -21,11 +21,17 @@ package azure
 
 import (
 	"fmt"
+	"net/http"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v2"
@@ -37,10 +43,25 @@ import (
 
 type clientSet struct {
 	credential *types.AzureCredential
+	// httpClient with rate limiter and metrics of cloud api
+	httpClient *http.Client
 }
 
 func newClientSet(credential *types.AzureCredential) *clientSet {
-	return &clientSet{credential}
+	return &clientSet{
+		credential: credential,
+		httpClient: &http.Client{
+			Transport: ratelimit.NewRoundTripper(enumor.Azure, credential.CloudSubscriptionID,
+				metric.AzureApiResolver, nil),
+		},
+	}
+}
+
+// armClientOptions azure resource manager client options with rate limiter and metrics transport.
+func (c *clientSet) armClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{Transport: c.httpClient},
+	}
 }
 
 // graphServiceClient ...
@@ -65,7 +86,7 @@ func (c *clientSet) subscriptionClient() (*armsubscription.SubscriptionsClient,
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armsubscription.NewSubscriptionsClient(credential, nil)
+	client, err := armsubscription.NewSubscriptionsClient(credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure subscription client failed, err: %v", err)
 	}
@@ -83,7 +104,7 @@ func (c *clientSet) vpcClient() (*armnetwork.VirtualNetworksClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewVirtualNetworksClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewVirtualNetworksClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -100,7 +121,7 @@ func (c *clientSet) usageClient() (*armnetwork.UsagesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewUsagesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewUsagesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure usage client failed, err: %v", err)
 	}
@@ -117,7 +138,7 @@ func (c *clientSet) subnetClient() (*armnetwork.SubnetsClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewSubnetsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewSubnetsClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -130,7 +151,16 @@ func (c *clientSet) diskClient() (*armcompute.DisksClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
-	return armcompute.NewDisksClient(c.credential.CloudSubscriptionID, credential, nil)
+	return armcompute.NewDisksClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
+}
+
+// snapshotClient ...
+func (c *clientSet) snapshotClient() (*armcompute.SnapshotsClient, error) {
+	credential, err := c.newClientSecretCredential()
+	if err != nil {
+		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
+	}
+	return armcompute.NewSnapshotsClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 }
 
 // imageClient ...
@@ -140,7 +170,7 @@ func (c *clientSet) imageClient() (*armcompute.VirtualMachineImagesClient, error
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	return armcompute.NewVirtualMachineImagesClient(c.credential.CloudSubscriptionID, credential, nil)
+	return armcompute.NewVirtualMachineImagesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 }
 
 // newClientSecretCredential ...
@@ -158,7 +188,7 @@ func (c *clientSet) securityGroupClient() (*armnetwork.SecurityGroupsClient, err
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewSecurityGroupsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewSecurityGroupsClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure security group client failed, err: %v", err)
 	}
@@ -173,7 +203,7 @@ func (c *clientSet) virtualMachineClient() (*armcompute.VirtualMachinesClient, e
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewVirtualMachinesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewVirtualMachinesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure virtual machines client failed, err: %v", err)
 	}
@@ -188,7 +218,7 @@ func (c *clientSet) virtualMachineSizeClient() (*armcompute.VirtualMachineSizesC
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewVirtualMachineSizesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewVirtualMachineSizesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure virtual machine sizes client failed, err: %v", err)
 	}
@@ -203,7 +233,7 @@ func (c *clientSet) clientFactory() (*armcompute.ClientFactory, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armcompute.NewClientFactory(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armcompute.NewClientFactory(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure client factory failed, err: %v", err)
 	}
@@ -218,7 +248,7 @@ func (c *clientSet) resourceGroupsClient() (*armresources.ResourceGroupsClient,
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armresources.NewResourceGroupsClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armresources.NewResourceGroupsClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init resourceGroups client failed, err: %v", err)
 	}
@@ -233,7 +263,7 @@ func (c *clientSet) regionClient() (*armsubscriptions.Client, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armsubscriptions.NewClient(credential, nil)
+	client, err := armsubscriptions.NewClient(credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init region client failed, err: %v", err)
 	}
@@ -251,7 +281,7 @@ func (c *clientSet) routeTableClient() (*armnetwork.RouteTablesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewRouteTablesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewRouteTablesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -268,7 +298,7 @@ func (c *clientSet) routeClient() (*armnetwork.RoutesClient, error) {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewRoutesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewRoutesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure vpc client failed, err: %v", err)
 	}
@@ -281,7 +311,7 @@ func (c *clientSet) publicIPAddressesClient() (*armnetwork.PublicIPAddressesClie
 	if err != nil {
 		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
 	}
-	client, err := armnetwork.NewPublicIPAddressesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewPublicIPAddressesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init azure public ip addresses client failed, err: %v", err)
 	}
@@ -295,7 +325,7 @@ func (c *clientSet) networkInterfaceClient() (*armnetwork.InterfacesClient, erro
 		return nil, fmt.Errorf("init network interface credential failed, err: %v", err)
 	}
 
-	client, err := armnetwork.NewInterfacesClient(c.credential.CloudSubscriptionID, credential, nil)
+	client, err := armnetwork.NewInterfacesClient(c.credential.CloudSubscriptionID, credential, c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init network interface client failed, err: %v", err)
 	}
@@ -311,7 +341,7 @@ func (c *clientSet) networkInterfaceIPConfigClient() (*armnetwork.InterfaceIPCon
 	}
 
 	client, err := armnetwork.NewInterfaceIPConfigurationsClient(c.credential.CloudSubscriptionID, credential,
-		nil)
+		c.armClientOptions())
 	if err != nil {
 		return nil, fmt.Errorf("init network interface ipconfig client failed, err: %v", err)
 	}
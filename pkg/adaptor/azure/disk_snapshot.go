@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// CreateDiskSnapshot 创建云盘快照，CloudDiskID 为云盘资源ID
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/create-or-update?tabs=Go
+func (az *Azure) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "azure disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.ResourceGroupName) == 0 {
+		return "", errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrResourceGroupRequired)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return "", err
+	}
+
+	req := armcompute.Snapshot{
+		Location: to.Ptr(opt.Region),
+		Properties: &armcompute.SnapshotProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     to.Ptr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: to.Ptr(opt.CloudDiskID),
+			},
+		},
+	}
+	pollerResp, err := client.BeginCreateOrUpdate(kt.Ctx, opt.ResourceGroupName, opt.Name, req, nil)
+	if err != nil {
+		logs.Errorf("create azure disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	resp, err := pollerResp.PollUntilDone(kt.Ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return SPtrToLowerStr(resp.ID), nil
+}
+
+// ListDiskSnapshot 查询资源组下的云盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/list-by-resource-group?tabs=Go
+func (az *Azure) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "azure disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.ResourceGroupName) == 0 {
+		return nil, errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrResourceGroupRequired)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := converter.StringSliceToMap(opt.CloudIDs)
+	diskIDMap := converter.StringSliceToMap(opt.CloudDiskIDs)
+
+	snapshots := make([]disksnapshot.DiskSnapshot, 0)
+	pager := client.NewListByResourceGroupPager(opt.ResourceGroupName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(kt.Ctx)
+		if err != nil {
+			logs.Errorf("list azure disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, fmt.Errorf("failed to advance page: %v", err)
+		}
+
+		for _, one := range page.Value {
+			snapshot := convAzureSnapshot(opt.ResourceGroupName, one)
+			if snapshot.Region != opt.Region {
+				continue
+			}
+			if _, exist := idMap[snapshot.CloudID]; len(idMap) != 0 && !exist {
+				continue
+			}
+			if _, exist := diskIDMap[snapshot.CloudDiskID]; len(diskIDMap) != 0 && !exist {
+				continue
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots, nil
+}
+
+func convAzureSnapshot(resGroupName string, one *armcompute.Snapshot) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:   SPtrToLowerStr(one.ID),
+		Name:      SPtrToLowerStr(one.Name),
+		Region:    SPtrToLowerNoSpaceStr(one.Location),
+		Extension: &disksnapshot.Extension{ResourceGroupName: resGroupName},
+	}
+	if prop := one.Properties; prop != nil {
+		snapshot.DiskSize = uint64(converter.PtrToVal(prop.DiskSizeGB))
+		snapshot.Status = converter.PtrToVal(prop.ProvisioningState)
+		if prop.CreationData != nil {
+			snapshot.CloudDiskID = SPtrToLowerStr(prop.CreationData.SourceResourceID)
+		}
+		if prop.TimeCreated != nil {
+			snapshot.CloudCreatedTime = times.ConvStdTimeFormat(*prop.TimeCreated)
+		}
+		snapshot.Encrypted = prop.EncryptionSettingsCollection != nil &&
+			converter.PtrToVal(prop.EncryptionSettingsCollection.Enabled)
+	}
+
+	return snapshot
+}
+
+// DeleteDiskSnapshot 删除云盘快照，按资源ID中的快照名称逐个删除
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/delete?tabs=Go
+func (az *Azure) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.ResourceGroupName) == 0 {
+		return errf.NewFromErr(errf.InvalidParameter, disksnapshot.ErrResourceGroupRequired)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		name := cloudID[strings.LastIndex(cloudID, "/")+1:]
+		pollerResp, err := client.BeginDelete(kt.Ctx, opt.ResourceGroupName, name, nil)
+		if err != nil {
+			logs.Errorf("delete azure disk snapshot failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return errorf(err)
+		}
+
+		if _, err = pollerResp.PollUntilDone(kt.Ctx, nil); err != nil {
+			return fmt.Errorf("delete snapshot %s failed, err: %v", cloudID, err)
+		}
+	}
+
+	return nil
+}
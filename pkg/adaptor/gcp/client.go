@@ -21,8 +21,12 @@ package gcp
 
 import (
 	"fmt"
+	"net/http"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 
 	asset "cloud.google.com/go/asset/apiv1"
@@ -33,19 +37,46 @@ import (
 	"google.golang.org/api/compute/v1"
 	iam "google.golang.org/api/iam/v1"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
 )
 
 type clientSet struct {
 	credential *types.GcpCredential
+	// transport with rate limiter and metrics of cloud api, without authorization
+	transport http.RoundTripper
 }
 
 func newClientSet(credential *types.GcpCredential) *clientSet {
-	return &clientSet{credential}
+	return &clientSet{
+		credential: credential,
+		transport:  ratelimit.NewRoundTripper(enumor.Gcp, credential.CloudProjectID, metric.GcpApiResolver, nil),
+	}
+}
+
+// httpClientOption returns option of http client with authorization and rate limiter, credentials option is
+// ignored when http client is specified, so the authorization transport is wrapped here.
+func (c *clientSet) httpClientOption(kt *kit.Kit) (option.ClientOption, error) {
+	transport, err := htransport.NewTransport(kt.Ctx, c.transport, option.WithCredentialsJSON(c.credential.Json),
+		option.WithScopes(compute.CloudPlatformScope))
+	if err != nil {
+		return nil, fmt.Errorf("new gcp http transport failed, err: %v", err)
+	}
+
+	return option.WithHTTPClient(&http.Client{Transport: transport}), nil
+}
+
+// grpcClientOptions returns options of grpc client with rate limiter.
+func (c *clientSet) grpcClientOptions() []option.ClientOption {
+	interceptor := ratelimit.UnaryClientInterceptor(enumor.Gcp, c.credential.CloudProjectID)
+	return []option.ClientOption{
+		option.WithCredentialsJSON(c.credential.Json),
+		option.WithGRPCDialOption(grpc.WithUnaryInterceptor(interceptor)),
+	}
 }
 
 func (c *clientSet) assetClient(kt *kit.Kit) (*asset.Client, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
-	client, err := asset.NewClient(kt.Ctx, opt)
+	client, err := asset.NewClient(kt.Ctx, c.grpcClientOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +85,7 @@ func (c *clientSet) assetClient(kt *kit.Kit) (*asset.Client, error) {
 }
 
 func (c *clientSet) iamClient(kt *kit.Kit) (*credentials.IamCredentialsClient, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
-	client, err := credentials.NewIamCredentialsClient(kt.Ctx, opt)
+	client, err := credentials.NewIamCredentialsClient(kt.Ctx, c.grpcClientOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +94,11 @@ func (c *clientSet) iamClient(kt *kit.Kit) (*credentials.IamCredentialsClient, e
 }
 
 func (c *clientSet) computeClient(kt *kit.Kit) (*compute.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpClientOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := compute.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -74,7 +108,11 @@ func (c *clientSet) computeClient(kt *kit.Kit) (*compute.Service, error) {
 }
 
 func (c *clientSet) bigQueryClient(kt *kit.Kit) (*bigquery.Client, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpClientOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := bigquery.NewClient(kt.Ctx, c.credential.CloudProjectID, opt)
 	if err != nil {
 		return nil, fmt.Errorf("gcp.bigquery.NewClient, projectID: %s, err: %+v",
@@ -86,7 +124,11 @@ func (c *clientSet) bigQueryClient(kt *kit.Kit) (*bigquery.Client, error) {
 }
 
 func (c *clientSet) resClient(kt *kit.Kit) (*res.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpClientOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := res.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -96,7 +138,11 @@ func (c *clientSet) resClient(kt *kit.Kit) (*res.Service, error) {
 }
 
 func (c *clientSet) iamServiceClient(kt *kit.Kit) (*iam.Service, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpClientOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := iam.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -106,7 +152,11 @@ func (c *clientSet) iamServiceClient(kt *kit.Kit) (*iam.Service, error) {
 }
 
 func (c *clientSet) billingClient(kt *kit.Kit) (*cloudbilling.APIService, error) {
-	opt := option.WithCredentialsJSON(c.credential.Json)
+	opt, err := c.httpClientOption(kt)
+	if err != nil {
+		return nil, err
+	}
+
 	service, err := cloudbilling.NewService(kt.Ctx, opt)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,195 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// CreateDiskSnapshot 创建云盘快照，返回快照ID
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/createSnapshot
+func (g *Gcp) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "gcp disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Zone) == 0 || len(opt.DiskName) == 0 {
+		return "", errf.New(errf.InvalidParameter, "zone and disk_name are required")
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	cloudProjectID := g.CloudProjectID()
+	req := &compute.Snapshot{Name: opt.Name}
+	if opt.Memo != nil {
+		req.Description = *opt.Memo
+	}
+
+	_, err = client.Disks.CreateSnapshot(cloudProjectID, opt.Zone, opt.DiskName, req).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("create gcp disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.DiskName, kt.Rid)
+		return "", err
+	}
+
+	snapshot, err := client.Snapshots.Get(cloudProjectID, opt.Name).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("get gcp disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return "", err
+	}
+
+	return strconv.FormatUint(snapshot.Id, 10), nil
+}
+
+// ListDiskSnapshot 查询云盘快照. gcp 快照为全局资源，这里按照源云盘所在地域过滤，保证同步时不会跨地域重复
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/list
+func (g *Gcp) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "gcp disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	list, err := g.listSnapshot(kt, opt.CloudIDs, opt.CloudDiskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]disksnapshot.DiskSnapshot, 0, len(list))
+	for _, one := range list {
+		snapshot := convGcpSnapshot(one)
+		if snapshot.Region != opt.Region {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+func (g *Gcp) listSnapshot(kt *kit.Kit, cloudIDs, cloudDiskIDs []string) ([]*compute.Snapshot, error) {
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	request := client.Snapshots.List(g.CloudProjectID()).Context(kt.Ctx)
+	switch {
+	case len(cloudIDs) != 0:
+		request.Filter(generateResourceIDsFilter(cloudIDs))
+	case len(cloudDiskIDs) != 0:
+		request.Filter(generateResourceFilter("sourceDiskId", cloudDiskIDs))
+	}
+
+	snapshots := make([]*compute.Snapshot, 0)
+	err = request.Pages(kt.Ctx, func(page *compute.SnapshotList) error {
+		snapshots = append(snapshots, page.Items...)
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func convGcpSnapshot(one *compute.Snapshot) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:          strconv.FormatUint(one.Id, 10),
+		Name:             one.Name,
+		CloudDiskID:      one.SourceDiskId,
+		DiskSize:         uint64(one.DiskSizeGb),
+		Status:           one.Status,
+		Encrypted:        one.SnapshotEncryptionKey != nil,
+		CloudCreatedTime: one.CreationTimestamp,
+		Extension:        &disksnapshot.Extension{SelfLink: one.SelfLink},
+	}
+	if len(one.Description) != 0 {
+		snapshot.Memo = &one.Description
+	}
+
+	// source disk format: https://www.googleapis.com/compute/v1/projects/{project}/zones/{zone}/disks/{disk}
+	parts := strings.Split(one.SourceDisk, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "zones" {
+			snapshot.Zone = parts[i+1]
+			if idx := strings.LastIndex(snapshot.Zone, "-"); idx > 0 {
+				snapshot.Region = snapshot.Zone[:idx]
+			}
+			break
+		}
+	}
+
+	return snapshot
+}
+
+// DeleteDiskSnapshot 删除云盘快照，gcp 按名称删除，需要先查询快照名称
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/delete
+func (g *Gcp) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	list, err := g.listSnapshot(kt, opt.CloudIDs, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		return errors.New("snapshots not found")
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	for _, one := range list {
+		if _, err = client.Snapshots.Delete(g.CloudProjectID(), one.Name).Context(kt.Ctx).Do(); err != nil {
+			logs.Errorf("delete gcp disk snapshot failed, err: %v, name: %s, rid: %s", err, one.Name, kt.Rid)
+			return fmt.Errorf("delete snapshot %s failed, err: %v", one.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -21,12 +21,19 @@ package huawei
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
+	"hcm/pkg/criteria/enumor"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/global"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/config"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/httphandler"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
 	bssintl "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2"
 	bssintlv2region "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/region"
@@ -64,10 +71,12 @@ type NewGlobalCredentialsFunc func() *global.Credentials
 type clientSet struct {
 	credentials       NewCredentialsFunc
 	globalCredentials NewGlobalCredentialsFunc
+	rateLimitAccount  string
 }
 
 func newClientSet(secret *types.BaseSecret) *clientSet {
 	return &clientSet{
+		rateLimitAccount: secret.RateLimitAccount(),
 		credentials: func() *basic.Credentials {
 			return basic.NewCredentialsBuilder().
 				WithAk(secret.CloudSecretID).
@@ -83,6 +92,37 @@ func newClientSet(secret *types.BaseSecret) *clientSet {
 	}
 }
 
+// httpConfig returns http config with rate limiter and metrics of cloud api, huawei sdk does not support
+// custom http transport, so they are implemented by http handler, request failed without response is not recorded.
+func (c *clientSet) httpConfig() *config.HttpConfig {
+	handler := httphandler.NewHttpHandler().
+		AddRequestHandler(func(req http.Request) {
+			api, _ := metric.HuaWeiApiResolver(&req)
+			// request handler can not return error, sdk will return error when sending request with canceled ctx.
+			_ = ratelimit.Before(req.Context(), ratelimit.Key{Vendor: enumor.HuaWei, Account: c.rateLimitAccount,
+				Api: api})
+		}).
+		AddMonitorHandler(func(m *httphandler.MonitorMetric) {
+			req := &http.Request{Method: m.Method, Host: m.Host, URL: &url.URL{Host: m.Host, Path: m.Path}}
+			api, region := metric.HuaWeiApiResolver(req)
+			metric.Record(&metric.Request{
+				Vendor:   enumor.HuaWei,
+				Account:  c.rateLimitAccount,
+				Api:      api,
+				Region:   region,
+				Endpoint: m.Host,
+				Code:     strconv.Itoa(m.StatusCode),
+				Failed:   m.StatusCode >= http.StatusBadRequest,
+				CostSec:  m.Latency.Seconds(),
+			})
+
+			key := ratelimit.Key{Vendor: enumor.HuaWei, Account: c.rateLimitAccount, Api: api}
+			ratelimit.After(key, m.StatusCode == http.StatusTooManyRequests)
+		})
+
+	return config.DefaultHttpConfig().WithHttpHandler(handler)
+}
+
 func (c *clientSet) iamGlobalClient(region *region.Region) (client *iam.IamClient, err error) {
 	defer func() {
 		if p := recover(); p != nil {
@@ -94,7 +134,7 @@ func (c *clientSet) iamGlobalClient(region *region.Region) (client *iam.IamClien
 		iam.IamClientBuilder().
 			WithRegion(region).
 			WithCredential(c.globalCredentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -111,7 +151,7 @@ func (c *clientSet) iamClient(region *region.Region) (client *iam.IamClient, err
 		iam.IamClientBuilder().
 			WithRegion(region).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -127,7 +167,7 @@ func (c *clientSet) iamRegionClient(region string) (client *iam.IamClient, err e
 		iam.IamClientBuilder().
 			WithRegion(iamregion.ValueOf(region)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -143,7 +183,7 @@ func (c *clientSet) evsClient(region string) (client *evs.EvsClient, err error)
 		evs.EvsClientBuilder().
 			WithRegion(evsregion.ValueOf(region)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -160,7 +200,7 @@ func (c *clientSet) vpcClient(regionID string) (cli *vpc.VpcClient, err error) {
 		vpc.VpcClientBuilder().
 			WithRegion(vpcregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -177,7 +217,7 @@ func (c *clientSet) vpcClientV2(regionID string) (cli *vpcv2.VpcClient, err erro
 		vpcv2.VpcClientBuilder().
 			WithRegion(vpcregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -194,7 +234,7 @@ func (c *clientSet) imsClientV2(region *region.Region) (cli *ims.ImsClient, err
 		ims.ImsClientBuilder().
 			WithRegion(region).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -211,7 +251,7 @@ func (c *clientSet) ecsClient(regionID string) (cli *ecs.EcsClient, err error) {
 		ecs.EcsClientBuilder().
 			WithRegion(ecsregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -228,7 +268,7 @@ func (c *clientSet) dcsClient(regionID string) (cli *dcs.DcsClient, err error) {
 		dcs.DcsClientBuilder().
 			WithRegion(dcsregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
@@ -245,7 +285,7 @@ func (c *clientSet) eipClient(regionID string) (cli *eip.EipClient, err error) {
 		eip.EipClientBuilder().
 			WithRegion(eipregion.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -262,7 +302,7 @@ func (c *clientSet) eipV3Client(regionID string) (cli *eipv3.EipClient, err erro
 		eipv3.EipClientBuilder().
 			WithRegion(eipv3region.ValueOf(regionID)).
 			WithCredential(c.credentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return cli, nil
@@ -311,7 +351,7 @@ func (c *clientSet) newRmsClient() (cli *rms.RmsClient, err error) {
 		rms.RmsClientBuilder().
 			WithRegion(rmsregion.ValueOf("cn-north-4")).
 			WithCredential(c.globalCredentials()).
-			WithHttpConfig(config.DefaultHttpConfig()).
+			WithHttpConfig(c.httpConfig()).
 			Build())
 
 	return client, nil
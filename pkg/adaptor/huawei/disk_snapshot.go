@@ -0,0 +1,191 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	evs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// CreateDiskSnapshot 创建云盘快照
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2022.html
+func (h *HuaWei) CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.CreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "huawei disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return "", fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	req := &model.CreateSnapshotRequest{
+		Body: &model.CreateSnapshotRequestBody{
+			Snapshot: &model.CreateSnapshotOption{
+				VolumeId:    opt.CloudDiskID,
+				Name:        converter.ValToPtr(opt.Name),
+				Description: opt.Memo,
+			},
+		},
+	}
+	resp, err := client.CreateSnapshot(req)
+	if err != nil {
+		logs.Errorf("create huawei disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	if resp.Snapshot == nil {
+		return "", fmt.Errorf("create snapshot return empty snapshot, disk: %s", opt.CloudDiskID)
+	}
+
+	return converter.PtrToVal(resp.Snapshot.Id), nil
+}
+
+// ListDiskSnapshot 查询云盘快照，华为云仅支持按单个快照ID或云盘ID过滤，传入多个时逐个查询
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2024.html
+func (h *HuaWei) ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.ListOption) ([]disksnapshot.DiskSnapshot,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return nil, fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	reqs := make([]*model.ListSnapshotsRequest, 0)
+	switch {
+	case len(opt.CloudIDs) != 0:
+		for _, id := range opt.CloudIDs {
+			reqs = append(reqs, &model.ListSnapshotsRequest{Id: converter.ValToPtr(id)})
+		}
+	case len(opt.CloudDiskIDs) != 0:
+		for _, id := range opt.CloudDiskIDs {
+			reqs = append(reqs, &model.ListSnapshotsRequest{VolumeId: converter.ValToPtr(id)})
+		}
+	default:
+		reqs = append(reqs, new(model.ListSnapshotsRequest))
+	}
+
+	snapshots := make([]disksnapshot.DiskSnapshot, 0)
+	for _, req := range reqs {
+		list, err := h.listSnapshots(kt, client, req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range list {
+			snapshots = append(snapshots, convHuaWeiSnapshot(opt.Region, one))
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (h *HuaWei) listSnapshots(kt *kit.Kit, client *evs.EvsClient, req *model.ListSnapshotsRequest) (
+	[]model.SnapshotList, error) {
+
+	limit := int32(disksnapshot.MaxBatchSize)
+	req.Limit = converter.ValToPtr(limit)
+
+	result := make([]model.SnapshotList, 0)
+	for offset := int32(0); ; offset += limit {
+		req.Offset = converter.ValToPtr(offset)
+		resp, err := client.ListSnapshots(req)
+		if err != nil {
+			logs.Errorf("list huawei disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		if resp.Snapshots == nil {
+			break
+		}
+		result = append(result, *resp.Snapshots...)
+
+		if len(*resp.Snapshots) < int(limit) {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func convHuaWeiSnapshot(region string, one model.SnapshotList) disksnapshot.DiskSnapshot {
+	snapshot := disksnapshot.DiskSnapshot{
+		CloudID:          one.Id,
+		Name:             converter.PtrToVal(one.Name),
+		Region:           region,
+		CloudDiskID:      one.VolumeId,
+		DiskSize:         uint64(one.Size),
+		Status:           one.Status,
+		Memo:             one.Description,
+		CloudCreatedTime: one.CreatedAt,
+	}
+	if createdTime, err := times.ParseToStdTime("2006-01-02T15:04:05.000000", one.CreatedAt); err == nil {
+		snapshot.CloudCreatedTime = createdTime
+	}
+
+	return snapshot
+}
+
+// DeleteDiskSnapshot 删除云盘快照，华为云不支持批量删除，逐个删除
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2023.html
+func (h *HuaWei) DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.DeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.DeleteSnapshot(&model.DeleteSnapshotRequest{SnapshotId: cloudID}); err != nil {
+			logs.Errorf("delete huawei disk snapshot failed, err: %v, id: %s, rid: %s", err, cloudID, kt.Rid)
+			return fmt.Errorf("delete snapshot %s failed, err: %v", cloudID, err)
+		}
+	}
+
+	return nil
+}
@@ -22,7 +22,6 @@ package metric
 
 import (
 	"net/http"
-	"strings"
 	"time"
 
 	"hcm/pkg/criteria/enumor"
@@ -48,7 +47,7 @@ func InitCloudApiMetrics(reg prometheus.Registerer) {
 		Help:        "the lag seconds to request the cloud API",
 		ConstLabels: labels,
 		Buckets:     []float64{0.05, 0.075, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5, 0.7, 1, 2, 3, 4, 5, 10, 20, 30},
-	}, []string{"vendor", "http_code", "api_name", "region", "endpoint"})
+	}, []string{"vendor", "account", "http_code", "api_name", "region", "endpoint"})
 	reg.MustRegister(m.lagSec)
 
 	m.errCounter = prometheus.NewCounterVec(
@@ -58,9 +57,29 @@ func InitCloudApiMetrics(reg prometheus.Registerer) {
 			Name:        "total_err_count",
 			Help:        "the total error count to request the restful API",
 			ConstLabels: labels,
-		}, []string{"vendor", "http_code", "api_name", "region", "endpoint"})
+		}, []string{"vendor", "account", "http_code", "api_name", "region", "endpoint"})
 	reg.MustRegister(m.errCounter)
 
+	m.throttleCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace:   metrics.Namespace,
+			Subsystem:   metrics.CloudApiSubSys,
+			Name:        "total_throttle_count",
+			Help:        "the total count of cloud API requests throttled by the cloud",
+			ConstLabels: labels,
+		}, []string{"vendor", "account", "api_name"})
+	reg.MustRegister(m.throttleCounter)
+
+	m.waitSec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   metrics.Namespace,
+		Subsystem:   metrics.CloudApiSubSys,
+		Name:        "limiter_wait_seconds",
+		Help:        "the seconds waited in local rate limiter before requesting the cloud API",
+		ConstLabels: labels,
+		Buckets:     []float64{0.01, 0.05, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"vendor", "account", "api_name"})
+	reg.MustRegister(m.waitSec)
+
 	cloudApiMetric = m
 }
 
@@ -70,16 +89,84 @@ type metric struct {
 
 	// errCounter record the total error count request cloud API.
 	errCounter *prometheus.CounterVec
+
+	// throttleCounter record the total count of request throttled by cloud.
+	throttleCounter *prometheus.CounterVec
+
+	// waitSec record the time waited in local rate limiter.
+	waitSec *prometheus.HistogramVec
+}
+
+// Request describe one cloud API request to be recorded.
+type Request struct {
+	Vendor   enumor.Vendor
+	Account  string
+	Api      string
+	Region   string
+	Endpoint string
+	// Code http status of response, "nil" if no response received.
+	Code    string
+	Failed  bool
+	CostSec float64
+}
+
+// Record record the lag and error of a cloud API request, do nothing if metrics is not initialized.
+func Record(r *Request) {
+	if cloudApiMetric == nil || r == nil {
+		return
+	}
+
+	labels := prometheus.Labels{
+		"vendor":    string(r.Vendor),
+		"account":   r.Account,
+		"endpoint":  r.Endpoint,
+		"region":    r.Region,
+		"api_name":  r.Api,
+		"http_code": r.Code,
+	}
+	if r.Failed {
+		cloudApiMetric.errCounter.With(labels).Inc()
+	}
+	cloudApiMetric.lagSec.With(labels).Observe(r.CostSec)
+}
+
+// IncThrottle increase the throttled count of cloud API.
+func IncThrottle(vendor enumor.Vendor, account, api string) {
+	if cloudApiMetric == nil {
+		return
+	}
+	cloudApiMetric.throttleCounter.With(prometheus.Labels{
+		"vendor":   string(vendor),
+		"account":  account,
+		"api_name": api,
+	}).Inc()
+}
+
+// ObserveWait record the seconds waited in local rate limiter.
+func ObserveWait(vendor enumor.Vendor, account, api string, waitSec float64) {
+	if cloudApiMetric == nil {
+		return
+	}
+	cloudApiMetric.waitSec.With(prometheus.Labels{
+		"vendor":   string(vendor),
+		"account":  account,
+		"api_name": api,
+	}).Observe(waitSec)
 }
 
-// GetTCloudRecordRoundTripper get record round tripper for tcloud
-func GetTCloudRecordRoundTripper(next http.RoundTripper) promhttp.RoundTripperFunc {
+// GetRecordRoundTripper get record round tripper of given vendor and account,
+// resolve is used to get api name and region from request, use PathApiResolver if nil.
+func GetRecordRoundTripper(vendor enumor.Vendor, account string, resolve ApiResolver,
+	next http.RoundTripper) promhttp.RoundTripperFunc {
+
 	if next == nil {
 		next = http.DefaultTransport
 	}
+	if resolve == nil {
+		resolve = PathApiResolver
+	}
 	return func(req *http.Request) (*http.Response, error) {
-		action := strings.Join(req.Header["X-TC-Action"], ",")
-		region := strings.Join(req.Header["X-TC-Region"], ",")
+		api, region := resolve(req)
 		start := time.Now()
 		code := "nil"
 		ret, err := next.RoundTrip(req)
@@ -87,24 +174,16 @@ func GetTCloudRecordRoundTripper(next http.RoundTripper) promhttp.RoundTripperFu
 			code = ret.Status
 		}
 
-		if err != nil || (ret != nil && ret.StatusCode != http.StatusOK) {
-			cloudApiMetric.errCounter.With(prometheus.Labels{
-				"vendor":    string(enumor.TCloud),
-				"endpoint":  req.Host,
-				"region":    region,
-				"api_name":  action,
-				"http_code": code,
-			}).Inc()
-		}
-		cost := time.Since(start).Seconds()
-		cloudApiMetric.lagSec.With(
-			prometheus.Labels{
-				"vendor":    string(enumor.TCloud),
-				"endpoint":  req.Host,
-				"region":    region,
-				"api_name":  action,
-				"http_code": code,
-			}).Observe(cost)
+		Record(&Request{
+			Vendor:   vendor,
+			Account:  account,
+			Api:      api,
+			Region:   region,
+			Endpoint: req.Host,
+			Code:     code,
+			Failed:   err != nil || (ret != nil && ret.StatusCode >= http.StatusBadRequest),
+			CostSec:  time.Since(start).Seconds(),
+		})
 		return ret, err
 	}
 }
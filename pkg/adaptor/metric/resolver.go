@@ -0,0 +1,150 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package metric
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ApiResolver resolve the cloud api name and region from http request.
+type ApiResolver func(req *http.Request) (api string, region string)
+
+type apiInfoKey struct{}
+
+type apiInfo struct {
+	api    string
+	region string
+}
+
+// WithApiInfo set api name and region into context, for sdk which can not get api name from http request,
+// api info in context has higher priority than the resolver of vendor.
+func WithApiInfo(ctx context.Context, api, region string) context.Context {
+	return context.WithValue(ctx, apiInfoKey{}, apiInfo{api: api, region: region})
+}
+
+func apiInfoFromContext(ctx context.Context) (apiInfo, bool) {
+	info, ok := ctx.Value(apiInfoKey{}).(apiInfo)
+	return info, ok
+}
+
+// TCloudApiResolver resolve tcloud api name and region from request header.
+func TCloudApiResolver(req *http.Request) (string, string) {
+	if info, ok := apiInfoFromContext(req.Context()); ok {
+		return info.api, info.region
+	}
+	return strings.Join(req.Header["X-TC-Action"], ","), strings.Join(req.Header["X-TC-Region"], ",")
+}
+
+// idSegmentRegexp matches path segments look like resource id, such as uuid or project id.
+var idSegmentRegexp = regexp.MustCompile(`^[0-9a-fA-F-]{16,}$`)
+
+// PathApiResolver resolve api name as "method path" from restful request, segments look like id in path
+// are replaced with "{id}" to avoid high cardinality of metric labels.
+func PathApiResolver(req *http.Request) (string, string) {
+	if info, ok := apiInfoFromContext(req.Context()); ok {
+		return info.api, info.region
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, seg := range segments {
+		if idSegmentRegexp.MatchString(seg) {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " /" + strings.Join(segments, "/"), ""
+}
+
+// HuaWeiApiResolver resolve huawei api name from request path, region from endpoint host,
+// endpoint host of huawei is like `vpc.ap-southeast-1.myhuaweicloud.com`.
+func HuaWeiApiResolver(req *http.Request) (string, string) {
+	api, region := PathApiResolver(req)
+	if len(region) != 0 {
+		return api, region
+	}
+
+	labels := strings.Split(req.URL.Hostname(), ".")
+	if len(labels) > 3 {
+		region = labels[1]
+	}
+	return api, region
+}
+
+// AzureApiResolver resolve azure resource manager api name from request path,
+// arm path is composed of `collection/name` pairs, such as
+// `/subscriptions/{id}/resourceGroups/{name}/providers/Microsoft.Network/networkSecurityGroups/{name}`,
+// names in path are replaced with "{}", and the provider namespace is kept.
+func AzureApiResolver(req *http.Request) (string, string) {
+	if info, ok := apiInfoFromContext(req.Context()); ok {
+		return info.api, info.region
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	result := make([]string, 0, len(segments))
+	for i := 0; i < len(segments); i++ {
+		result = append(result, segments[i])
+		if i+1 >= len(segments) {
+			break
+		}
+
+		i++
+		if strings.EqualFold(segments[i-1], "providers") {
+			result = append(result, segments[i])
+			continue
+		}
+		result = append(result, "{}")
+	}
+
+	return req.Method + " /" + strings.Join(result, "/"), ""
+}
+
+// GcpApiResolver resolve gcp api name from request path, gcp path is like
+// `/compute/v1/projects/{project}/zones/{zone}/instances/{name}`, the part after `projects` is composed of
+// `collection/name` pairs, names are replaced with "{}", region or zone in path is returned as region.
+func GcpApiResolver(req *http.Request) (string, string) {
+	if info, ok := apiInfoFromContext(req.Context()); ok {
+		return info.api, info.region
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	result := make([]string, 0, len(segments))
+	region := ""
+	pair := false
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+		if seg == "projects" {
+			pair = true
+		}
+		result = append(result, seg)
+		if !pair || i+1 >= len(segments) {
+			continue
+		}
+
+		i++
+		if seg == "regions" || seg == "zones" {
+			region = segments[i]
+		}
+		result = append(result, "{}")
+	}
+
+	return req.Method + " /" + strings.Join(result, "/"), region
+}
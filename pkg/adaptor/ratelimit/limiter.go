@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ratelimit 云API限流，按 vendor/账号/API 维度的令牌桶限流，在云上返回限流错误时自适应降低速率，
+// 一段时间内未再被限流时逐步恢复到配置的速率。
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+
+	"golang.org/x/time/rate"
+)
+
+// Key 限流维度
+type Key struct {
+	Vendor  enumor.Vendor
+	Account string
+	Api     string
+}
+
+// RuleGetter 获取指定 vendor、API 的限流速率配置
+type RuleGetter interface {
+	GetLimit(vendor enumor.Vendor, api string) (qps float64, burst int)
+}
+
+// Option 限流配置
+type Option struct {
+	Rule RuleGetter
+	// MinQPS 被云上限流后降速的下限
+	MinQPS float64
+	// RecoverInterval 未再被限流时，每隔 RecoverInterval 恢复一次速率
+	RecoverInterval time.Duration
+}
+
+const (
+	// decreaseInterval 同一时刻并发的多个请求同时被限流时，只降速一次
+	decreaseInterval = time.Second
+	// recoverSteps 恢复到配置速率所需的次数
+	recoverSteps = 10
+)
+
+// Limiter 云API限流器
+type Limiter struct {
+	opt     Option
+	lock    sync.Mutex
+	buckets map[Key]*bucket
+}
+
+type bucket struct {
+	lock    sync.Mutex
+	limiter *rate.Limiter
+	// base 配置的速率
+	base rate.Limit
+	// lastAdjust 上次调整速率的时间
+	lastAdjust time.Time
+}
+
+// New limiter.
+func New(opt Option) *Limiter {
+	return &Limiter{
+		opt:     opt,
+		buckets: make(map[Key]*bucket),
+	}
+}
+
+func (l *Limiter) getBucket(key Key) *bucket {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	b, exists := l.buckets[key]
+	if exists {
+		return b
+	}
+
+	qps, burst := l.opt.Rule.GetLimit(key.Vendor, key.Api)
+	limit := rate.Limit(qps)
+	if qps <= 0 {
+		limit = rate.Inf
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	b = &bucket{limiter: rate.NewLimiter(limit, burst), base: limit}
+	l.buckets[key] = b
+	return b
+}
+
+// Wait 等待令牌，返回等待的时长，ctx 取消时返回错误
+func (l *Limiter) Wait(ctx context.Context, key Key) (time.Duration, error) {
+	start := time.Now()
+	if err := l.getBucket(key).limiter.Wait(ctx); err != nil {
+		return time.Since(start), err
+	}
+	return time.Since(start), nil
+}
+
+// Throttled 请求被云上限流，速率减半，最低降到 MinQPS
+func (l *Limiter) Throttled(key Key) {
+	b := l.getBucket(key)
+	if b.base == rate.Inf {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(b.lastAdjust) < decreaseInterval {
+		return
+	}
+
+	floor := rate.Limit(l.opt.MinQPS)
+	if floor > b.base {
+		floor = b.base
+	}
+	limit := b.limiter.Limit() / 2
+	if limit < floor {
+		limit = floor
+	}
+	b.limiter.SetLimitAt(now, limit)
+	b.lastAdjust = now
+}
+
+// Succeeded 请求未被限流，距上次调整超过 RecoverInterval 时，按配置速率的 1/recoverSteps 恢复
+func (l *Limiter) Succeeded(key Key) {
+	b := l.getBucket(key)
+	if b.base == rate.Inf {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	current := b.limiter.Limit()
+	if current >= b.base {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(b.lastAdjust) < l.opt.RecoverInterval {
+		return
+	}
+
+	limit := current + b.base/recoverSteps
+	if limit > b.base {
+		limit = b.base
+	}
+	b.limiter.SetLimitAt(now, limit)
+	b.lastAdjust = now
+}
+
+// Limit 返回当前速率
+func (l *Limiter) Limit(key Key) rate.Limit {
+	return l.getBucket(key).limiter.Limit()
+}
+
+var defaultLimiter *Limiter
+
+// Init 初始化全局限流器，未初始化时不限流
+func Init(opt Option) {
+	defaultLimiter = New(opt)
+}
+
+// Default 返回全局限流器，未初始化时返回nil
+func Default() *Limiter {
+	return defaultLimiter
+}
@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+type fixedRule struct {
+	qps   float64
+	burst int
+}
+
+// GetLimit ...
+func (r fixedRule) GetLimit(enumor.Vendor, string) (float64, int) {
+	return r.qps, r.burst
+}
+
+func TestLimiterAdaptive(t *testing.T) {
+	l := New(Option{Rule: fixedRule{qps: 10, burst: 10}, MinQPS: 2, RecoverInterval: 0})
+	key := Key{Vendor: enumor.Aws, Account: "123", Api: "ec2.DescribeInstances"}
+	assert.Equal(t, rate.Limit(10), l.Limit(key))
+
+	l.Throttled(key)
+	assert.Equal(t, rate.Limit(5), l.Limit(key))
+
+	// throttled again within decrease interval should be ignored
+	l.Throttled(key)
+	assert.Equal(t, rate.Limit(5), l.Limit(key))
+
+	l.getBucket(key).lastAdjust = time.Now().Add(-decreaseInterval)
+	l.Throttled(key)
+	l.getBucket(key).lastAdjust = time.Now().Add(-decreaseInterval)
+	l.Throttled(key)
+	assert.Equal(t, rate.Limit(2), l.Limit(key))
+
+	l.Succeeded(key)
+	assert.Equal(t, rate.Limit(3), l.Limit(key))
+	for i := 0; i < 20; i++ {
+		l.Succeeded(key)
+	}
+	assert.Equal(t, rate.Limit(10), l.Limit(key))
+
+	// other api of same account is not affected
+	other := Key{Vendor: enumor.Aws, Account: "123", Api: "ec2.DescribeVpcs"}
+	assert.Equal(t, rate.Limit(10), l.Limit(other))
+}
+
+func TestIsThrottled(t *testing.T) {
+	newResp := func(code int, body string) *http.Response {
+		return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	tcloudBody := `{"Response":{"Error":{"Code":"RequestLimitExceeded","Message":"limit"},"RequestId":"x"}}`
+	resp := newResp(http.StatusOK, tcloudBody)
+	assert.True(t, IsThrottled(enumor.TCloud, resp))
+	// body should be kept for sdk
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, tcloudBody, string(body))
+
+	assert.False(t, IsThrottled(enumor.Aws, newResp(http.StatusOK, "RequestLimitExceeded")))
+	assert.True(t, IsThrottled(enumor.Aws, newResp(http.StatusBadRequest,
+		"<Response><Errors><Error><Code>RequestLimitExceeded</Code></Error></Errors></Response>")))
+	assert.True(t, IsThrottled(enumor.Azure, newResp(http.StatusTooManyRequests, "")))
+	assert.False(t, IsThrottled(enumor.HuaWei, newResp(http.StatusNotFound, `{"error_code":"VPC.0202"}`)))
+
+	long := strings.Repeat("a", peekSize*3)
+	resp = newResp(http.StatusForbidden, long)
+	assert.False(t, IsThrottled(enumor.Gcp, resp))
+	body, err = io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, long, string(body))
+}
@@ -0,0 +1,199 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/criteria/enumor"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// throttleCodes 各云厂商限流错误码，在响应体中出现即认为被限流，http 429 对所有厂商都视为限流
+var throttleCodes = map[enumor.Vendor][]string{
+	enumor.TCloud: {"RequestLimitExceeded"},
+	enumor.Aws: {"Throttling", "RequestLimitExceeded", "TooManyRequestsException", "RequestThrottled",
+		"SlowDown"},
+	enumor.HuaWei: {"APIGW.0308"},
+	enumor.Gcp:    {"rateLimitExceeded", "RateLimitExceeded"},
+}
+
+// peekSize 检查限流错误码时读取的响应体长度，错误码均在响应体开头
+const peekSize = 1024
+
+// IsThrottled 判断响应是否被云上限流，tcloud 限流时 http 状态码为200，需要检查响应体
+func IsThrottled(vendor enumor.Vendor, resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	codes := throttleCodes[vendor]
+	if len(codes) == 0 || resp.Body == nil {
+		return false
+	}
+	if resp.StatusCode < http.StatusBadRequest && vendor != enumor.TCloud {
+		return false
+	}
+
+	head := make([]byte, peekSize)
+	n, err := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	body := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(head), &errReader{reader: body, err: err}),
+		Closer: body,
+	}
+
+	for _, code := range codes {
+		if bytes.Contains(head, []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// errReader 读取响应体前缀时遇到的非EOF错误需要在后续读取时返回
+type errReader struct {
+	reader io.Reader
+	err    error
+}
+
+// Read ...
+func (r *errReader) Read(p []byte) (int, error) {
+	if r.err != nil && r.err != io.EOF && r.err != io.ErrUnexpectedEOF {
+		return 0, r.err
+	}
+	return r.reader.Read(p)
+}
+
+// NewRoundTripper 返回带限流和指标采集的 round tripper，resolve 为nil时使用 metric.PathApiResolver
+func NewRoundTripper(vendor enumor.Vendor, account string, resolve metric.ApiResolver,
+	next http.RoundTripper) http.RoundTripper {
+
+	if resolve == nil {
+		resolve = metric.PathApiResolver
+	}
+
+	return &roundTripper{
+		vendor:  vendor,
+		account: account,
+		resolve: resolve,
+		next:    metric.GetRecordRoundTripper(vendor, account, resolve, next),
+	}
+}
+
+type roundTripper struct {
+	vendor  enumor.Vendor
+	account string
+	resolve metric.ApiResolver
+	next    http.RoundTripper
+}
+
+// RoundTrip ...
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	api, _ := rt.resolve(req)
+	key := Key{Vendor: rt.vendor, Account: rt.account, Api: api}
+
+	if err := Before(req.Context(), key); err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	After(key, IsThrottled(rt.vendor, resp))
+	return resp, nil
+}
+
+// Before 请求云API前等待令牌，全局限流器未初始化时直接返回
+func Before(ctx context.Context, key Key) error {
+	limiter := Default()
+	if limiter == nil {
+		return nil
+	}
+
+	wait, err := limiter.Wait(ctx, key)
+	metric.ObserveWait(key.Vendor, key.Account, key.Api, wait.Seconds())
+	return err
+}
+
+// After 根据请求是否被云上限流调整速率
+func After(key Key, throttled bool) {
+	if throttled {
+		metric.IncThrottle(key.Vendor, key.Account, key.Api)
+	}
+
+	limiter := Default()
+	if limiter == nil {
+		return
+	}
+
+	if throttled {
+		limiter.Throttled(key)
+		return
+	}
+	limiter.Succeeded(key)
+}
+
+// UnaryClientInterceptor 返回带限流和指标采集的 grpc 拦截器，用于 gcp 等使用 grpc 协议的sdk
+func UnaryClientInterceptor(vendor enumor.Vendor, account string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+
+		api := method[strings.LastIndex(method, "/")+1:]
+		key := Key{Vendor: vendor, Account: account, Api: api}
+		if err := Before(ctx, key); err != nil {
+			return err
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		code := status.Code(err)
+		metric.Record(&metric.Request{
+			Vendor:   vendor,
+			Account:  account,
+			Api:      api,
+			Endpoint: cc.Target(),
+			Code:     code.String(),
+			Failed:   err != nil,
+			CostSec:  time.Since(start).Seconds(),
+		})
+
+		After(key, code == codes.ResourceExhausted)
+		return err
+	}
+}
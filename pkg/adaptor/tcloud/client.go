@@ -25,9 +25,11 @@ import (
 	"time"
 
 	"hcm/pkg/adaptor/metric"
+	"hcm/pkg/adaptor/ratelimit"
 	"hcm/pkg/adaptor/types"
 	typescos "hcm/pkg/adaptor/types/cos"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/tools/rand"
 
 	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
@@ -66,12 +68,15 @@ type ClientSet interface {
 type clientSet struct {
 	credential *common.Credential
 	profile    *profile.ClientProfile
+	// transport with rate limiter and metrics of cloud api
+	transport http.RoundTripper
 }
 
 func newClientSet(s *types.BaseSecret, profile *profile.ClientProfile) ClientSet {
 	return &clientSet{
 		credential: common.NewCredential(s.CloudSecretID, s.CloudSecretKey),
 		profile:    profile,
+		transport:  ratelimit.NewRoundTripper(enumor.TCloud, s.RateLimitAccount(), metric.TCloudApiResolver, nil),
 	}
 }
 
@@ -96,7 +101,7 @@ func (c *clientSet) CamServiceClient(region string) (*cam.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 	return client, nil
 }
 
@@ -106,7 +111,7 @@ func (c *clientSet) CvmClient(region string) (*cvm.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 	return client, nil
 }
 
@@ -116,7 +121,7 @@ func (c *clientSet) CbsClient(region string) (*cbs.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 	return client, nil
 }
 
@@ -126,7 +131,7 @@ func (c *clientSet) VpcClient(region string) (*vpc.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 
 	return client, nil
 }
@@ -137,7 +142,7 @@ func (c *clientSet) BillClient() (*billing.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 
 	return client, nil
 }
@@ -148,7 +153,7 @@ func (c *clientSet) ClbClient(region string) (*clb.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 
 	return client, nil
 }
@@ -159,7 +164,7 @@ func (c *clientSet) CertClient() (*ssl.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 
 	return client, nil
 }
@@ -170,7 +175,7 @@ func (c *clientSet) TagClient() (*tag.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client.WithHttpTransport(metric.GetTCloudRecordRoundTripper(nil))
+	client.WithHttpTransport(c.transport)
 
 	return client, nil
 }
@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot 云盘快照的通用参数定义，各云厂商共用同一套结构.
+package disksnapshot
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/criteria/validator"
+)
+
+// DiskSnapshot 云盘快照
+type DiskSnapshot struct {
+	CloudID string `json:"cloud_id"`
+	Name    string `json:"name"`
+	Region  string `json:"region"`
+	Zone    string `json:"zone"`
+	// CloudDiskID 快照源云盘的云上ID
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 快照源云盘大小，单位 GB
+	DiskSize uint64 `json:"disk_size"`
+	Status   string `json:"status"`
+	// Encrypted 是否加密
+	Encrypted bool    `json:"encrypted"`
+	Memo      *string `json:"memo"`
+	// CloudCreatedTime 云上创建时间
+	CloudCreatedTime string     `json:"cloud_created_time"`
+	Extension        *Extension `json:"extension"`
+}
+
+// GetCloudID ...
+func (s DiskSnapshot) GetCloudID() string {
+	return s.CloudID
+}
+
+// Extension 云厂商差异字段
+type Extension struct {
+	// ResourceGroupName azure 资源组
+	ResourceGroupName string `json:"resource_group_name,omitempty"`
+	// SelfLink gcp 快照 selfLink，基于快照创建云盘时使用
+	SelfLink string `json:"self_link,omitempty"`
+}
+
+// CreateOption 创建云盘快照参数
+type CreateOption struct {
+	Region string `json:"region" validate:"required"`
+	// Zone 云盘所在可用区，gcp 必填
+	Zone string `json:"zone" validate:"omitempty"`
+	// ResourceGroupName azure 必填
+	ResourceGroupName string `json:"resource_group_name" validate:"omitempty"`
+	CloudDiskID       string `json:"cloud_disk_id" validate:"required"`
+	// DiskName 云盘名称，gcp 必填
+	DiskName string  `json:"disk_name" validate:"omitempty"`
+	Name     string  `json:"name" validate:"required,max=60"`
+	Memo     *string `json:"memo" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *CreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ListOption 查询云盘快照参数，接口内部会完成分页，返回满足条件的全部快照
+type ListOption struct {
+	Region string `json:"region" validate:"required"`
+	// ResourceGroupName azure 必填
+	ResourceGroupName string   `json:"resource_group_name" validate:"omitempty"`
+	CloudIDs          []string `json:"cloud_ids" validate:"omitempty"`
+	CloudDiskIDs      []string `json:"cloud_disk_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *ListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) > MaxBatchSize {
+		return fmt.Errorf("cloud_ids should <= %d", MaxBatchSize)
+	}
+
+	if len(opt.CloudDiskIDs) > MaxBatchSize {
+		return fmt.Errorf("cloud_disk_ids should <= %d", MaxBatchSize)
+	}
+
+	return nil
+}
+
+// DeleteOption 删除云盘快照参数
+type DeleteOption struct {
+	Region string `json:"region" validate:"required"`
+	// ResourceGroupName azure 必填
+	ResourceGroupName string   `json:"resource_group_name" validate:"omitempty"`
+	CloudIDs          []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate ...
+func (opt *DeleteOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) > MaxBatchSize {
+		return fmt.Errorf("cloud_ids should <= %d", MaxBatchSize)
+	}
+
+	return nil
+}
+
+// MaxBatchSize 单次批量操作的快照数量上限
+const MaxBatchSize = 100
+
+// ErrResourceGroupRequired azure 未传资源组
+var ErrResourceGroupRequired = errors.New("resource_group_name is required")
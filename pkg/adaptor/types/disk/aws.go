@@ -36,6 +36,8 @@ type AwsDiskCreateOption struct {
 	DiskType  *string `json:"disk_type"`
 	DiskSize  int64   `json:"disk_size" validate:"required"`
 	DiskCount *uint64 `json:"disk_count" validate:"required"`
+	// SnapshotID 快照ID，传入时基于该快照创建云盘
+	SnapshotID *string `json:"snapshot_id"`
 }
 
 // Validate ...
@@ -53,6 +55,7 @@ func (opt *AwsDiskCreateOption) ToCreateVolumeInput() (*ec2.CreateVolumeInput, e
 		AvailabilityZone: aws.String(opt.Zone),
 		Size:             aws.Int64(opt.DiskSize),
 		VolumeType:       opt.DiskType,
+		SnapshotId:       opt.SnapshotID,
 	}, nil
 }
 
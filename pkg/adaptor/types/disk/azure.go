@@ -43,6 +43,8 @@ type AzureDiskCreateOption struct {
 	DiskType          string  `json:"disk_type" validate:"required"`
 	DiskSize          int32   `json:"disk_size" validate:"required"`
 	DiskCount         *uint64 `json:"disk_count" validate:"required"`
+	// SourceSnapshotID 快照ID，传入时基于该快照创建云盘
+	SourceSnapshotID string `json:"source_snapshot_id"`
 }
 
 // Validate ...
@@ -64,6 +66,12 @@ func (opt *AzureDiskCreateOption) ToCreateDiskRequest() (*armcompute.Disk, error
 			CreateOption: converter.ValToPtr(armcompute.DiskCreateOptionEmpty),
 		},
 	}
+	if len(opt.SourceSnapshotID) != 0 {
+		prop.CreationData = &armcompute.CreationData{
+			CreateOption:     converter.ValToPtr(armcompute.DiskCreateOptionCopy),
+			SourceResourceID: to.Ptr(opt.SourceSnapshotID),
+		}
+	}
 
 	return &armcompute.Disk{
 		Zones:      to.SliceOfPtrs[string](opt.Zone),
@@ -36,6 +36,8 @@ type GcpDiskCreateOption struct {
 	DiskType  string  `json:"disk_type" validate:"required"`
 	DiskSize  int64   `json:"disk_size" validate:"required"`
 	DiskCount *uint64 `json:"disk_count" validate:"required"`
+	// SourceSnapshot 快照的 selfLink，传入时基于该快照创建云盘
+	SourceSnapshot string `json:"source_snapshot"`
 }
 
 // Validate ...
@@ -54,7 +56,8 @@ func (opt *GcpDiskCreateOption) ToCreateDiskRequest(cloudProjectID string) (*com
 		Name:   opt.DiskName,
 		Type: fmt.Sprintf("projects/%s/zones/%s/diskTypes/%s", cloudProjectID, opt.Zone,
 			opt.DiskType),
-		SizeGb: opt.DiskSize,
+		SizeGb:         opt.DiskSize,
+		SourceSnapshot: opt.SourceSnapshot,
 	}, nil
 }
 
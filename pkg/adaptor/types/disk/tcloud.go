@@ -45,6 +45,8 @@ type TCloudDiskCreateOption struct {
 	DiskCount         *uint64 `json:"disk_count"`
 	DiskChargeType    string  `json:"disk_charge_type" validate:"required"`
 	DiskChargePrepaid *TCloudDiskChargePrepaid
+	// SnapshotID 快照ID，传入时基于该快照创建云盘
+	SnapshotID *string `json:"snapshot_id"`
 }
 
 // Validate ...
@@ -65,6 +67,7 @@ func (opt *TCloudDiskCreateOption) ToCreateDisksRequest() (*cbs.CreateDisksReque
 	req.DiskCount = opt.DiskCount
 	req.DiskSize = opt.DiskSize
 	req.DiskChargeType = common.StringPtr(opt.DiskChargeType)
+	req.SnapshotId = opt.SnapshotID
 	// 预付费模式需要设定 ChargePrepaid
 	if *req.DiskChargeType == TCloudDiskChargeTypeEnum.PREPAID {
 		req.DiskChargePrepaid = &cbs.DiskChargePrepaid{
@@ -0,0 +1,528 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	apicore "hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+// AwsLoadBalancerType aws elb 负载均衡类型
+type AwsLoadBalancerType string
+
+// Validate AwsLoadBalancerType.
+func (t AwsLoadBalancerType) Validate() error {
+	switch t {
+	case AwsApplicationLoadBalancer:
+	case AwsNetworkLoadBalancer:
+	case AwsGatewayLoadBalancer:
+	default:
+		return fmt.Errorf("unsupported aws load balancer type: %s", t)
+	}
+
+	return nil
+}
+
+const (
+	// AwsApplicationLoadBalancer 应用型负载均衡(ALB)
+	AwsApplicationLoadBalancer AwsLoadBalancerType = elbv2.LoadBalancerTypeEnumApplication
+	// AwsNetworkLoadBalancer 网络型负载均衡(NLB)
+	AwsNetworkLoadBalancer AwsLoadBalancerType = elbv2.LoadBalancerTypeEnumNetwork
+	// AwsGatewayLoadBalancer 网关型负载均衡(GWLB)
+	AwsGatewayLoadBalancer AwsLoadBalancerType = elbv2.LoadBalancerTypeEnumGateway
+)
+
+// AwsLoadBalancerScheme aws elb 网络属性
+type AwsLoadBalancerScheme string
+
+const (
+	// AwsInternetFacingScheme 公网
+	AwsInternetFacingScheme AwsLoadBalancerScheme = elbv2.LoadBalancerSchemeEnumInternetFacing
+	// AwsInternalScheme 内网
+	AwsInternalScheme AwsLoadBalancerScheme = elbv2.LoadBalancerSchemeEnumInternal
+)
+
+// ToTCloudType 转换为与腾讯云一致的网络类型表示，用于统一列表展示
+func (s AwsLoadBalancerScheme) ToTCloudType() TCloudLoadBalancerType {
+	if s == AwsInternalScheme {
+		return InternalLoadBalancerType
+	}
+	return OpenLoadBalancerType
+}
+
+const (
+	// AwsElbDescribeMax 指定arn查询负载均衡时单次最大数量
+	AwsElbDescribeMax = 20
+	// AwsElbPageSizeMax 分页查询最大数量
+	AwsElbPageSizeMax = 400
+	// AwsElbDescribeTagsMax 单次查询标签的资源最大数量
+	AwsElbDescribeTagsMax = 20
+)
+
+// -------------------------- List Load Balancer --------------------------
+
+// AwsListOption defines options to list aws elb instances.
+type AwsListOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"omitempty,max=20"`
+	Names    []string `json:"names" validate:"omitempty,max=20"`
+	// Marker 上一次查询返回的NextMarker
+	Marker   *string `json:"marker" validate:"omitempty"`
+	PageSize *int64  `json:"page_size" validate:"omitempty,min=1,max=400"`
+}
+
+// Validate aws elb list option.
+func (opt AwsListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) != 0 && len(opt.Names) != 0 {
+		return errf.New(errf.InvalidParameter, "only one of cloud_ids and names can be set")
+	}
+
+	return nil
+}
+
+// AwsLoadBalancer for aws elb Instance
+type AwsLoadBalancer struct {
+	*elbv2.LoadBalancer
+	Tags []*elbv2.Tag `json:"tags"`
+}
+
+// GetCloudID get cloud id, aws use arn as unique id.
+func (lb AwsLoadBalancer) GetCloudID() string {
+	return cvt.PtrToVal(lb.LoadBalancerArn)
+}
+
+// GetScheme ...
+func (lb AwsLoadBalancer) GetScheme() AwsLoadBalancerScheme {
+	return AwsLoadBalancerScheme(cvt.PtrToVal(lb.Scheme))
+}
+
+// GetIPVersion 返回ip版本信息
+func (lb AwsLoadBalancer) GetIPVersion() enumor.IPAddressType {
+	switch cvt.PtrToVal(lb.IpAddressType) {
+	case elbv2.IpAddressTypeIpv4:
+		return enumor.Ipv4
+	case elbv2.IpAddressTypeDualstack:
+		return enumor.Ipv6DualStack
+	}
+	// fall back to unknown
+	return enumor.IPAddressType(cvt.PtrToVal(lb.IpAddressType))
+}
+
+// GetStatus 返回负载均衡状态
+func (lb AwsLoadBalancer) GetStatus() string {
+	if lb.State == nil {
+		return ""
+	}
+	return cvt.PtrToVal(lb.State.Code)
+}
+
+// GetZones 返回可用区列表
+func (lb AwsLoadBalancer) GetZones() []string {
+	zones := make([]string, 0, len(lb.AvailabilityZones))
+	for _, zone := range lb.AvailabilityZones {
+		if zone == nil {
+			continue
+		}
+		zones = append(zones, cvt.PtrToVal(zone.ZoneName))
+	}
+	return zones
+}
+
+// GetSubnetIDs 返回子网云id列表
+func (lb AwsLoadBalancer) GetSubnetIDs() []string {
+	subnets := make([]string, 0, len(lb.AvailabilityZones))
+	for _, zone := range lb.AvailabilityZones {
+		if zone == nil || zone.SubnetId == nil {
+			continue
+		}
+		subnets = append(subnets, cvt.PtrToVal(zone.SubnetId))
+	}
+	return subnets
+}
+
+// GetAddresses 返回负载均衡绑定的ip地址，NLB会返回静态地址，ALB只能通过DNS解析
+func (lb AwsLoadBalancer) GetAddresses() (privateIPv4, publicIPv4, ipv6 []string) {
+	for _, zone := range lb.AvailabilityZones {
+		if zone == nil {
+			continue
+		}
+		for _, addr := range zone.LoadBalancerAddresses {
+			if addr == nil {
+				continue
+			}
+			if ip := cvt.PtrToVal(addr.PrivateIPv4Address); len(ip) != 0 {
+				privateIPv4 = append(privateIPv4, ip)
+			}
+			if ip := cvt.PtrToVal(addr.IpAddress); len(ip) != 0 {
+				publicIPv4 = append(publicIPv4, ip)
+			}
+			if ip := cvt.PtrToVal(addr.IPv6Address); len(ip) != 0 {
+				ipv6 = append(ipv6, ip)
+			}
+		}
+	}
+	return privateIPv4, publicIPv4, ipv6
+}
+
+// GetTagMap ...
+func (lb AwsLoadBalancer) GetTagMap() apicore.TagMap {
+	if len(lb.Tags) == 0 {
+		return nil
+	}
+	tagMap := make(apicore.TagMap, len(lb.Tags))
+	for _, tag := range lb.Tags {
+		tagMap.Set(cvt.PtrToVal(tag.Key), cvt.PtrToVal(tag.Value))
+	}
+	return tagMap
+}
+
+// GetName 返回负载均衡名称
+func (lb AwsLoadBalancer) GetName() string {
+	return cvt.PtrToVal(lb.LoadBalancerName)
+}
+
+// -------------------------- Create Load Balancer --------------------------
+
+// AwsCreateLoadBalancerOption defines options to create aws elb instance.
+type AwsCreateLoadBalancerOption struct {
+	Region         string                `json:"region" validate:"required"`
+	Name           string                `json:"name" validate:"required,max=32"`
+	Type           AwsLoadBalancerType   `json:"type" validate:"required"`
+	Scheme         AwsLoadBalancerScheme `json:"scheme" validate:"omitempty"`
+	IpAddressType  *string               `json:"ip_address_type" validate:"omitempty"`
+	CloudSubnetIDs []string              `json:"cloud_subnet_ids" validate:"required,min=1"`
+	SecurityGroups []string              `json:"security_groups" validate:"omitempty"`
+	Tags           []apicore.TagPair     `json:"tags" validate:"omitempty"`
+}
+
+// Validate aws elb create option.
+func (opt AwsCreateLoadBalancerOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.Type.Validate()
+}
+
+// AwsDeleteOption 删除负载均衡
+type AwsDeleteOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate ...
+func (opt AwsDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Listener --------------------------
+
+// AwsListListenersOption defines options to list aws listeners.
+type AwsListListenersOption struct {
+	Region         string   `json:"region" validate:"required"`
+	LoadBalancerID string   `json:"load_balancer_id" validate:"required_without=CloudIDs"`
+	CloudIDs       []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate aws listeners list option.
+func (opt AwsListListenersOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsListener for aws elb listener.
+type AwsListener struct {
+	*elbv2.Listener
+}
+
+// GetCloudID get cloud id
+func (l AwsListener) GetCloudID() string {
+	return cvt.PtrToVal(l.ListenerArn)
+}
+
+// GetProtocol ...
+func (l AwsListener) GetProtocol() enumor.ProtocolType {
+	return enumor.ProtocolType(cvt.PtrToVal(l.Protocol))
+}
+
+// GetDefaultTargetGroupIDs 返回默认转发动作指向的目标组
+func (l AwsListener) GetDefaultTargetGroupIDs() []string {
+	return getForwardTargetGroups(l.DefaultActions)
+}
+
+// GetCertificateIDs 返回监听器证书arn列表
+func (l AwsListener) GetCertificateIDs() []string {
+	certs := make([]string, 0, len(l.Certificates))
+	for _, cert := range l.Certificates {
+		if cert == nil {
+			continue
+		}
+		certs = append(certs, cvt.PtrToVal(cert.CertificateArn))
+	}
+	return certs
+}
+
+func (l AwsListener) String() string {
+	return fmt.Sprintf("{arn:%s,protocol:%s,port:%d}",
+		cvt.PtrToVal(l.ListenerArn), cvt.PtrToVal(l.Protocol), cvt.PtrToVal(l.Port))
+}
+
+func getForwardTargetGroups(actions []*elbv2.Action) []string {
+	result := make([]string, 0)
+	for _, action := range actions {
+		if action == nil || cvt.PtrToVal(action.Type) != elbv2.ActionTypeEnumForward {
+			continue
+		}
+		if action.TargetGroupArn != nil {
+			result = append(result, cvt.PtrToVal(action.TargetGroupArn))
+			continue
+		}
+		if action.ForwardConfig == nil {
+			continue
+		}
+		for _, tg := range action.ForwardConfig.TargetGroups {
+			if tg == nil {
+				continue
+			}
+			result = append(result, cvt.PtrToVal(tg.TargetGroupArn))
+		}
+	}
+	return result
+}
+
+// AwsCreateListenerOption defines options to create aws listener.
+type AwsCreateListenerOption struct {
+	Region         string              `json:"region" validate:"required"`
+	LoadBalancerID string              `json:"load_balancer_id" validate:"required"`
+	Protocol       enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port           int64               `json:"port" validate:"required,min=1,max=65535"`
+	// TargetGroupID 默认转发的目标组
+	TargetGroupID string   `json:"target_group_id" validate:"required"`
+	SslPolicy     *string  `json:"ssl_policy" validate:"omitempty"`
+	CertCloudIDs  []string `json:"cert_cloud_ids" validate:"omitempty"`
+}
+
+// Validate aws listener create option.
+func (opt AwsCreateListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsDeleteListenerOption defines options to delete aws listener.
+type AwsDeleteListenerOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate aws listener delete option.
+func (opt AwsDeleteListenerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Rule --------------------------
+
+// AwsListRuleOption defines options to list aws listener rules.
+type AwsListRuleOption struct {
+	Region     string `json:"region" validate:"required"`
+	ListenerID string `json:"listener_id" validate:"required"`
+}
+
+// Validate aws rule list option.
+func (opt AwsListRuleOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsListenerRule for aws elb listener rule.
+type AwsListenerRule struct {
+	*elbv2.Rule
+}
+
+// GetCloudID get cloud id
+func (r AwsListenerRule) GetCloudID() string {
+	return cvt.PtrToVal(r.RuleArn)
+}
+
+// GetTargetGroupIDs 返回规则转发的目标组
+func (r AwsListenerRule) GetTargetGroupIDs() []string {
+	return getForwardTargetGroups(r.Actions)
+}
+
+// GetConditionValues 返回指定字段的匹配条件，如 host-header、path-pattern
+func (r AwsListenerRule) GetConditionValues(field string) []string {
+	values := make([]string, 0)
+	for _, cond := range r.Conditions {
+		if cond == nil || cvt.PtrToVal(cond.Field) != field {
+			continue
+		}
+		switch field {
+		case AwsRuleFieldHostHeader:
+			if cond.HostHeaderConfig != nil {
+				values = append(values, cvt.PtrToSlice(cond.HostHeaderConfig.Values)...)
+				continue
+			}
+		case AwsRuleFieldPathPattern:
+			if cond.PathPatternConfig != nil {
+				values = append(values, cvt.PtrToSlice(cond.PathPatternConfig.Values)...)
+				continue
+			}
+		}
+		values = append(values, cvt.PtrToSlice(cond.Values)...)
+	}
+	return values
+}
+
+const (
+	// AwsRuleFieldHostHeader 按域名匹配
+	AwsRuleFieldHostHeader = "host-header"
+	// AwsRuleFieldPathPattern 按路径匹配
+	AwsRuleFieldPathPattern = "path-pattern"
+)
+
+// -------------------------- Target Group --------------------------
+
+// AwsListTargetGroupOption defines options to list aws target groups.
+type AwsListTargetGroupOption struct {
+	Region         string   `json:"region" validate:"required"`
+	LoadBalancerID string   `json:"load_balancer_id" validate:"omitempty"`
+	CloudIDs       []string `json:"cloud_ids" validate:"omitempty,max=20"`
+	// Marker 上一次查询返回的NextMarker
+	Marker   *string `json:"marker" validate:"omitempty"`
+	PageSize *int64  `json:"page_size" validate:"omitempty,min=1,max=400"`
+}
+
+// Validate aws target group list option.
+func (opt AwsListTargetGroupOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.LoadBalancerID) != 0 && len(opt.CloudIDs) != 0 {
+		return errf.New(errf.InvalidParameter, "only one of load_balancer_id and cloud_ids can be set")
+	}
+
+	return nil
+}
+
+// AwsTargetGroup for aws elb target group.
+type AwsTargetGroup struct {
+	*elbv2.TargetGroup
+}
+
+// GetCloudID get cloud id
+func (tg AwsTargetGroup) GetCloudID() string {
+	return cvt.PtrToVal(tg.TargetGroupArn)
+}
+
+// AwsCreateTargetGroupOption defines options to create aws target group.
+type AwsCreateTargetGroupOption struct {
+	Region     string              `json:"region" validate:"required"`
+	Name       string              `json:"name" validate:"required,max=32"`
+	Protocol   enumor.ProtocolType `json:"protocol" validate:"required"`
+	Port       int64               `json:"port" validate:"required,min=1,max=65535"`
+	CloudVpcID string              `json:"cloud_vpc_id" validate:"required"`
+	// TargetType instance | ip | lambda | alb, 默认 instance
+	TargetType *string `json:"target_type" validate:"omitempty"`
+
+	HealthCheckEnabled      *bool   `json:"health_check_enabled"`
+	HealthCheckProtocol     *string `json:"health_check_protocol"`
+	HealthCheckPort         *string `json:"health_check_port"`
+	HealthCheckPath         *string `json:"health_check_path"`
+	HealthCheckInterval     *int64  `json:"health_check_interval" validate:"omitempty,min=5,max=300"`
+	HealthCheckTimeout      *int64  `json:"health_check_timeout" validate:"omitempty,min=2,max=120"`
+	HealthyThresholdCount   *int64  `json:"healthy_threshold_count" validate:"omitempty,min=2,max=10"`
+	UnhealthyThresholdCount *int64  `json:"unhealthy_threshold_count" validate:"omitempty,min=2,max=10"`
+}
+
+// Validate aws target group create option.
+func (opt AwsCreateTargetGroupOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsDeleteTargetGroupOption defines options to delete aws target group.
+type AwsDeleteTargetGroupOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate aws target group delete option.
+func (opt AwsDeleteTargetGroupOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// -------------------------- Target --------------------------
+
+// AwsTarget 目标组下的后端
+type AwsTarget struct {
+	// CloudID 实例id、ip地址或者lambda arn，取决于目标组类型
+	CloudID string `json:"cloud_id" validate:"required"`
+	Port    *int64 `json:"port" validate:"omitempty,min=1,max=65535"`
+	// Zone ip类型后端位于vpc外时需要指定为all
+	Zone *string `json:"zone" validate:"omitempty"`
+}
+
+// AwsTargetOption defines options to register/deregister targets.
+type AwsTargetOption struct {
+	Region        string      `json:"region" validate:"required"`
+	TargetGroupID string      `json:"target_group_id" validate:"required"`
+	Targets       []AwsTarget `json:"targets" validate:"required,min=1,dive"`
+}
+
+// Validate aws target option.
+func (opt AwsTargetOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsListTargetHealthOption defines options to list aws target health.
+type AwsListTargetHealthOption struct {
+	Region        string `json:"region" validate:"required"`
+	TargetGroupID string `json:"target_group_id" validate:"required"`
+}
+
+// Validate aws target health option.
+func (opt AwsListTargetHealthOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsTargetHealth for aws target health.
+type AwsTargetHealth struct {
+	*elbv2.TargetHealthDescription
+}
+
+// GetCloudID 返回后端id
+func (t AwsTargetHealth) GetCloudID() string {
+	if t.Target == nil {
+		return ""
+	}
+	return cvt.PtrToVal(t.Target.Id)
+}
+
+// IsHealthy 后端是否健康
+func (t AwsTargetHealth) IsHealthy() bool {
+	return t.TargetHealth != nil && cvt.PtrToVal(t.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy
+}
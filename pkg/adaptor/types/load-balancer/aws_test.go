@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAwsLoadBalancerConv(t *testing.T) {
+	lb := AwsLoadBalancer{
+		LoadBalancer: &elbv2.LoadBalancer{
+			LoadBalancerArn:  cvt.ValToPtr("arn:aws:elasticloadbalancing:lb/net/test/1"),
+			LoadBalancerName: cvt.ValToPtr("test"),
+			Scheme:           cvt.ValToPtr(elbv2.LoadBalancerSchemeEnumInternetFacing),
+			IpAddressType:    cvt.ValToPtr(elbv2.IpAddressTypeDualstack),
+			State:            &elbv2.LoadBalancerState{Code: cvt.ValToPtr(elbv2.LoadBalancerStateEnumActive)},
+			AvailabilityZones: []*elbv2.AvailabilityZone{
+				{
+					ZoneName: cvt.ValToPtr("us-east-1a"),
+					SubnetId: cvt.ValToPtr("subnet-a"),
+					LoadBalancerAddresses: []*elbv2.LoadBalancerAddress{{
+						IpAddress:          cvt.ValToPtr("1.1.1.1"),
+						PrivateIPv4Address: cvt.ValToPtr("10.0.0.1"),
+						IPv6Address:        cvt.ValToPtr("2001:db8::1"),
+					}},
+				},
+				nil,
+				{ZoneName: cvt.ValToPtr("us-east-1b")},
+			},
+		},
+		Tags: []*elbv2.Tag{{Key: cvt.ValToPtr("env"), Value: cvt.ValToPtr("prod")}},
+	}
+
+	assert.Equal(t, "arn:aws:elasticloadbalancing:lb/net/test/1", lb.GetCloudID())
+	assert.Equal(t, "test", lb.GetName())
+	assert.Equal(t, AwsLoadBalancerScheme(elbv2.LoadBalancerSchemeEnumInternetFacing), lb.GetScheme())
+	assert.Equal(t, enumor.Ipv6DualStack, lb.GetIPVersion())
+	assert.Equal(t, elbv2.LoadBalancerStateEnumActive, lb.GetStatus())
+	assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, lb.GetZones())
+	assert.Equal(t, []string{"subnet-a"}, lb.GetSubnetIDs())
+
+	privateIPv4, publicIPv4, ipv6 := lb.GetAddresses()
+	assert.Equal(t, []string{"10.0.0.1"}, privateIPv4)
+	assert.Equal(t, []string{"1.1.1.1"}, publicIPv4)
+	assert.Equal(t, []string{"2001:db8::1"}, ipv6)
+	assert.Equal(t, "prod", lb.GetTagMap()["env"])
+
+	empty := AwsLoadBalancer{LoadBalancer: &elbv2.LoadBalancer{}}
+	assert.Equal(t, "", empty.GetStatus())
+	assert.Nil(t, empty.GetTagMap())
+}
+
+func TestAwsForwardTargetGroups(t *testing.T) {
+	listener := AwsListener{Listener: &elbv2.Listener{
+		DefaultActions: []*elbv2.Action{
+			{Type: cvt.ValToPtr(elbv2.ActionTypeEnumForward), TargetGroupArn: cvt.ValToPtr("tg-1")},
+			{Type: cvt.ValToPtr(elbv2.ActionTypeEnumRedirect), TargetGroupArn: cvt.ValToPtr("tg-ignored")},
+			{
+				Type: cvt.ValToPtr(elbv2.ActionTypeEnumForward),
+				ForwardConfig: &elbv2.ForwardActionConfig{TargetGroups: []*elbv2.TargetGroupTuple{
+					{TargetGroupArn: cvt.ValToPtr("tg-2")}, nil, {TargetGroupArn: cvt.ValToPtr("tg-3")},
+				}},
+			},
+		},
+		Certificates: []*elbv2.Certificate{{CertificateArn: cvt.ValToPtr("cert-1")}, nil},
+	}}
+
+	assert.Equal(t, []string{"tg-1", "tg-2", "tg-3"}, listener.GetDefaultTargetGroupIDs())
+	assert.Equal(t, []string{"cert-1"}, listener.GetCertificateIDs())
+}
+
+func TestAwsRuleConditionValues(t *testing.T) {
+	rule := AwsListenerRule{Rule: &elbv2.Rule{
+		Conditions: []*elbv2.RuleCondition{
+			{
+				Field:            cvt.ValToPtr(AwsRuleFieldHostHeader),
+				HostHeaderConfig: &elbv2.HostHeaderConditionConfig{Values: cvt.SliceToPtr([]string{"a.com"})},
+			},
+			{Field: cvt.ValToPtr(AwsRuleFieldHostHeader), Values: cvt.SliceToPtr([]string{"b.com"})},
+			{
+				Field:             cvt.ValToPtr(AwsRuleFieldPathPattern),
+				PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: cvt.SliceToPtr([]string{"/api/*"})},
+			},
+		},
+	}}
+
+	assert.Equal(t, []string{"a.com", "b.com"}, rule.GetConditionValues(AwsRuleFieldHostHeader))
+	assert.Equal(t, []string{"/api/*"}, rule.GetConditionValues(AwsRuleFieldPathPattern))
+	assert.Empty(t, rule.GetConditionValues("http-header"))
+}
+
+func TestAwsTargetHealth(t *testing.T) {
+	healthy := AwsTargetHealth{TargetHealthDescription: &elbv2.TargetHealthDescription{
+		Target:       &elbv2.TargetDescription{Id: cvt.ValToPtr("i-1")},
+		TargetHealth: &elbv2.TargetHealth{State: cvt.ValToPtr(elbv2.TargetHealthStateEnumHealthy)},
+	}}
+	assert.Equal(t, "i-1", healthy.GetCloudID())
+	assert.True(t, healthy.IsHealthy())
+
+	unknown := AwsTargetHealth{TargetHealthDescription: &elbv2.TargetHealthDescription{}}
+	assert.Equal(t, "", unknown.GetCloudID())
+	assert.False(t, unknown.IsHealthy())
+}
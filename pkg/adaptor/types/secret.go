@@ -20,6 +20,9 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
 )
@@ -47,6 +50,18 @@ func (b BaseSecret) Validate() error {
 	return nil
 }
 
+// RateLimitAccount returns the account dimension of cloud api rate limiter and metrics. if cloud account id is not
+// set, use the digest of secret id instead, so that different accounts do not share one rate limiter, and the secret
+// id is not exposed in metrics.
+func (b BaseSecret) RateLimitAccount() string {
+	if len(b.CloudAccountID) != 0 {
+		return b.CloudAccountID
+	}
+
+	sum := sha256.Sum256([]byte(b.CloudSecretID))
+	return "secret-" + hex.EncodeToString(sum[:8])
+}
+
 // GcpCredential define gcp credential information.
 type GcpCredential struct {
 	CloudProjectID string `json:"cloud_project_id" validate:"required"`
@@ -0,0 +1,38 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitAccount(t *testing.T) {
+	assert.Equal(t, "100001", BaseSecret{CloudSecretID: "AKID1", CloudAccountID: "100001"}.RateLimitAccount())
+
+	// 未设置云账号ID时，不同密钥使用不同的限流维度，且不暴露密钥ID
+	account1 := BaseSecret{CloudSecretID: "AKID1"}.RateLimitAccount()
+	account2 := BaseSecret{CloudSecretID: "AKID2"}.RateLimitAccount()
+	assert.NotEqual(t, account1, account2)
+	assert.Equal(t, account1, BaseSecret{CloudSecretID: "AKID1"}.RateLimitAccount())
+	assert.True(t, strings.HasPrefix(account1, "secret-"))
+	assert.NotContains(t, account1, "AKID1")
+}
@@ -150,6 +150,22 @@ func (req *HuaWeiRootAccountExtensionUpdateReq) Validate() error {
 	return nil
 }
 
+// TCloudRootAccountExtensionUpdateReq ...
+type TCloudRootAccountExtensionUpdateReq struct {
+	CloudSubAccountID string `json:"cloud_sub_account_id" validate:"required"`
+	CloudSecretID     string `json:"cloud_secret_id" validate:"omitempty"`
+	CloudSecretKey    string `json:"cloud_secret_key" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *TCloudRootAccountExtensionUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ZenlayerRootAccountExtensionUpdateReq ...
 type ZenlayerRootAccountExtensionUpdateReq struct {
 }
@@ -202,6 +218,19 @@ func (req *AwsAccountInfoBySecretReq) Validate() error {
 	return validator.Validate.Struct(req)
 }
 
+// TCloudAccountInfoBySecretReq ...
+type TCloudAccountInfoBySecretReq struct {
+	*cloud.TCloudSecret `json:",inline" validate:"required"`
+}
+
+// Validate ...
+func (req *TCloudAccountInfoBySecretReq) Validate() error {
+	if err := req.TCloudSecret.Validate(); err != nil {
+		return err
+	}
+	return validator.Validate.Struct(req)
+}
+
 // HuaWeiAccountInfoBySecretReq ...
 type HuaWeiAccountInfoBySecretReq struct {
 	*cloud.HuaWeiSecret `json:",inline" validate:"required"`
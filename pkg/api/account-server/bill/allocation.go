@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	corebill "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// AllocationRuleCreateReq create bill allocation rule request
+type AllocationRuleCreateReq struct {
+	Name     string                      `json:"name" validate:"required,max=255"`
+	Vendor   enumor.Vendor               `json:"vendor" validate:"required"`
+	Priority uint                        `json:"priority"`
+	Enabled  bool                        `json:"enabled"`
+	Matcher  corebill.AllocationMatcher  `json:"matcher" validate:"required"`
+	Method   enumor.BillAllocationMethod `json:"method" validate:"required"`
+	Config   corebill.AllocationConfig   `json:"config" validate:"required"`
+	Memo     *string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *AllocationRuleCreateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if err := r.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Matcher.Validate(); err != nil {
+		return err
+	}
+
+	return r.Config.Validate(r.Method, r.Vendor)
+}
+
+// AllocationRuleUpdateReq update bill allocation rule request
+type AllocationRuleUpdateReq struct {
+	Name     string                      `json:"name" validate:"omitempty,max=255"`
+	Priority *uint                       `json:"priority"`
+	Enabled  *bool                       `json:"enabled"`
+	Matcher  *corebill.AllocationMatcher `json:"matcher"`
+	Method   enumor.BillAllocationMethod `json:"method"`
+	Config   *corebill.AllocationConfig  `json:"config"`
+	Memo     *string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *AllocationRuleUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.Matcher != nil {
+		if err := r.Matcher.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if (len(r.Method) == 0) != (r.Config == nil) {
+		return errors.New("method and config should be updated together")
+	}
+
+	return nil
+}
@@ -59,3 +59,19 @@ func (r *AzureResCondSyncReq) Validate() error {
 	}
 	return validator.Validate.Struct(r)
 }
+
+// ResSyncPlanReq 资源同步计划请求，只计算同步会产生的差异，不修改db数据
+type ResSyncPlanReq struct {
+	Regions  []string `json:"regions,required" validate:"min=1,max=5"`
+	CloudIDs []string `json:"cloud_ids,omitempty" validate:"max=20"`
+}
+
+// Validate ...
+func (r *ResSyncPlanReq) Validate() error {
+	if len(r.CloudIDs) > 0 {
+		if len(r.Regions) > 1 {
+			return fmt.Errorf("regions must be one when cloud_ids is specified, got: %v", r.Regions)
+		}
+	}
+	return validator.Validate.Struct(r)
+}
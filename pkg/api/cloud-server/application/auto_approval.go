@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// AutoApprovalPolicyCreateReq ...
+type AutoApprovalPolicyCreateReq struct {
+	Name            string                 `json:"name" validate:"required,max=64"`
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	// Priority 数值越小优先级越高，多个策略同时命中时使用优先级最高的策略
+	Priority   uint32                             `json:"priority" validate:"omitempty"`
+	Enabled    bool                               `json:"enabled" validate:"omitempty"`
+	Conditions coreapproval.AutoApprovalCondition `json:"conditions" validate:"required"`
+	Memo       *string                            `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *AutoApprovalPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	switch req.ApplicationType {
+	case enumor.CreateCvm, enumor.CreateDisk, enumor.CreateSecurityGroupRule:
+	default:
+		return errors.New("auto approval only support application type create_cvm, create_disk and " +
+			"create_security_group_rule")
+	}
+
+	return req.Conditions.Validate()
+}
+
+// AutoApprovalPolicyUpdateReq ...
+type AutoApprovalPolicyUpdateReq struct {
+	Name       string                              `json:"name" validate:"omitempty,max=64"`
+	Priority   *uint32                             `json:"priority" validate:"omitempty"`
+	Enabled    *bool                               `json:"enabled" validate:"omitempty"`
+	Conditions *coreapproval.AutoApprovalCondition `json:"conditions" validate:"omitempty"`
+	Memo       *string                             `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *AutoApprovalPolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Name) == 0 && req.Priority == nil && req.Enabled == nil && req.Conditions == nil && req.Memo == nil {
+		return errors.New("at least one field should be updated")
+	}
+
+	if req.Conditions != nil {
+		return req.Conditions.Validate()
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ package application
 
 import (
 	"hcm/pkg/api/core"
+	coreapproval "hcm/pkg/api/core/approval"
 	"hcm/pkg/criteria/enumor"
 )
 
@@ -38,4 +39,8 @@ type ApplicationGetResp struct {
 	core.Revision  `json:",inline"`
 
 	TicketUrl string `json:"ticket_url"`
+	// Approval 内置审批单信息，仅来源为 native 的申请单返回
+	Approval *coreapproval.Ticket `json:"approval,omitempty"`
+	// AutoApprovalPolicyID 命中的自动审批策略ID，仅来源为 auto_approval 的申请单返回
+	AutoApprovalPolicyID string `json:"auto_approval_policy_id,omitempty"`
 }
@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApprovalChainCreateReq ...
+type ApprovalChainCreateReq struct {
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	// BkBizID 为 -1 时表示该申请单类型的默认审批链
+	BkBizID int64               `json:"bk_biz_id" validate:"required"`
+	Nodes   []coreapproval.Node `json:"nodes" validate:"required"`
+	Memo    *string             `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.ApplicationType.Validate(); err != nil {
+		return err
+	}
+
+	if req.BkBizID <= 0 && req.BkBizID != constant.UnassignedBiz {
+		return errors.New("bk_biz_id should > 0 or be -1")
+	}
+
+	return coreapproval.ValidateNodes(req.Nodes)
+}
+
+// ApprovalChainUpdateReq ...
+type ApprovalChainUpdateReq struct {
+	Nodes []coreapproval.Node `json:"nodes" validate:"omitempty"`
+	Memo  *string             `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Nodes == nil && req.Memo == nil {
+		return errors.New("one of nodes and memo is required")
+	}
+
+	if req.Nodes != nil {
+		return coreapproval.ValidateNodes(req.Nodes)
+	}
+
+	return nil
+}
+
+// ApprovalOperateReq 同意/驳回内置审批单
+type ApprovalOperateReq struct {
+	Opinion string `json:"opinion" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalOperateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalTransferReq 将当前节点的审批转给其他人
+type ApprovalTransferReq struct {
+	Target  string `json:"target" validate:"required,max=64"`
+	Opinion string `json:"opinion" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalTransferReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/validator"
+)
+
+// TCloudSGRuleCreateReq 申请创建腾讯云安全组规则
+type TCloudSGRuleCreateReq struct {
+	BkBizID         int64  `json:"bk_biz_id" validate:"required,min=1"`
+	SecurityGroupID string `json:"security_group_id" validate:"required"`
+
+	cloudserver.SecurityGroupRuleCreateReq[cloudserver.TCloudSecurityGroupRule] `json:",inline"`
+}
+
+// Validate ...
+func (req *TCloudSGRuleCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.SecurityGroupRuleCreateReq.Validate()
+}
@@ -53,3 +53,40 @@ func (req *AssignCertToBizReq) Validate() error {
 
 	return nil
 }
+
+// ReplaceCertReq define replace cert req.
+type ReplaceCertReq struct {
+	OldCertID string `json:"old_cert_id" validate:"required"`
+	NewCertID string `json:"new_cert_id" validate:"required"`
+}
+
+// Validate replace cert request.
+func (req *ReplaceCertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.OldCertID == req.NewCertID {
+		return errors.New("new_cert_id should be different from old_cert_id")
+	}
+
+	return nil
+}
+
+// ReplaceCertResult define replace cert result.
+type ReplaceCertResult struct {
+	// FlowID 替换证书的异步任务ID，没有需要替换的监听器、域名时为空
+	FlowID        string `json:"flow_id"`
+	ListenerCount int    `json:"listener_count"`
+	DomainCount   int    `json:"domain_count"`
+	// Tasks 各负载均衡对应的子任务，子任务相互独立，可按子任务状态确认各负载均衡的替换结果
+	Tasks []ReplaceCertTask `json:"tasks"`
+}
+
+// ReplaceCertTask 单个负载均衡的证书替换子任务
+type ReplaceCertTask struct {
+	LbID          string `json:"lb_id"`
+	ActionID      string `json:"action_id"`
+	ListenerCount int    `json:"listener_count"`
+	DomainCount   int    `json:"domain_count"`
+}
@@ -48,6 +48,8 @@ type AwsDiskCreateReq struct {
 	DiskSize  int32   `json:"disk_size" validate:"required"`
 	DiskCount int32   `json:"disk_count" validate:"required"`
 	Memo      *string `json:"memo" validate:"omitempty"`
+	// SnapshotID 基于该快照创建云盘
+	SnapshotID string `json:"snapshot_id" validate:"omitempty"`
 }
 
 // Validate ...
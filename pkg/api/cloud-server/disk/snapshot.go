@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package csdisk
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/validator"
+)
+
+// DiskSnapshotCreateReq 为云盘创建快照
+type DiskSnapshotCreateReq struct {
+	DiskID string  `json:"disk_id" validate:"required"`
+	Name   string  `json:"name" validate:"required,max=60"`
+	Memo   *string `json:"memo" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *DiskSnapshotCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotBatchDeleteReq 批量删除云盘快照
+type DiskSnapshotBatchDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate ...
+func (req *DiskSnapshotBatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotPolicyCreateReq 创建快照策略，策略下的云盘必须属于同一账号
+type DiskSnapshotPolicyCreateReq struct {
+	Name    string   `json:"name" validate:"required,max=64"`
+	DiskIDs []string `json:"disk_ids" validate:"required,min=1,max=100"`
+	// CronExpr 5段式cron表达式
+	CronExpr string `json:"cron_expr" validate:"required,max=64"`
+	// TimeZone cron表达式所在时区，为空时使用UTC
+	TimeZone string `json:"time_zone" validate:"omitempty,max=64"`
+	// RetentionCount 每块云盘保留的快照个数，0表示不按个数清理
+	RetentionCount uint32 `json:"retention_count"`
+	// RetentionDays 快照保留天数，0表示不按天数清理
+	RetentionDays uint32  `json:"retention_days"`
+	Enabled       *bool   `json:"enabled" validate:"omitempty"`
+	Memo          *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *DiskSnapshotPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.RetentionCount == 0 && req.RetentionDays == 0 {
+		return errors.New("one of retention_count and retention_days is required")
+	}
+
+	return nil
+}
+
+// DiskSnapshotPolicyUpdateReq 更新快照策略，只更新非空字段
+type DiskSnapshotPolicyUpdateReq struct {
+	Name           string   `json:"name" validate:"omitempty,max=64"`
+	DiskIDs        []string `json:"disk_ids" validate:"omitempty,max=100"`
+	CronExpr       string   `json:"cron_expr" validate:"omitempty,max=64"`
+	TimeZone       *string  `json:"time_zone" validate:"omitempty,max=64"`
+	RetentionCount *uint32  `json:"retention_count"`
+	RetentionDays  *uint32  `json:"retention_days"`
+	Enabled        *bool    `json:"enabled"`
+	Memo           *string  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *DiskSnapshotPolicyUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
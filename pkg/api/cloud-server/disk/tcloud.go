@@ -40,6 +40,8 @@ type TCloudDiskCreateReq struct {
 	DiskChargeType    string                           `json:"disk_charge_type" validate:"required"`
 	DiskChargePrepaid *hcproto.TCloudDiskChargePrepaid `json:"disk_charge_prepaid" validate:"omitempty"`
 	Memo              *string                          `json:"memo" validate:"omitempty"`
+	// SnapshotID 基于该快照创建云盘
+	SnapshotID string `json:"snapshot_id" validate:"omitempty"`
 }
 
 // Validate ...
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
+)
+
+// IdleResourceExportReq idle resource export request.
+type IdleResourceExportReq struct {
+	// Filter 导出的闲置资源的过滤条件，为空时导出全部闲置资源
+	Filter *filter.Expression `json:"filter" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *IdleResourceExportReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// IdleResourceRecycleReq idle resource batch recycle request.
+type IdleResourceRecycleReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate ...
+func (req *IdleResourceRecycleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// IdleResourceRecycleResult idle resource batch recycle result.
+type IdleResourceRecycleResult struct {
+	// TaskIDs 回收任务ID，每种资源类型对应一个回收任务
+	TaskIDs   []string                    `json:"task_ids"`
+	Succeeded []string                    `json:"succeeded"`
+	Failed    []IdleResourceRecycleFailed `json:"failed"`
+}
+
+// IdleResourceRecycleFailed idle resource recycle failed info.
+type IdleResourceRecycleFailed struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,40 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cslb
+
+import (
+	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/validator"
+)
+
+// LbSnapshotRollbackReq 回滚负载均衡到历史快照请求
+type LbSnapshotRollbackReq struct {
+	// Refresh 对比前是否先从云上同步该负载均衡，保证与云上最新状态对比
+	Refresh bool `json:"refresh"`
+}
+
+// Validate ...
+func (req *LbSnapshotRollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// LbSnapshotListResult 负载均衡配置快照列表，不包含快照配置内容
+type LbSnapshotListResult = core.ListResultT[corelb.LoadBalancerSnapshot]
@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cslb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/validator"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecFormat 声明式配置的格式
+type SpecFormat string
+
+const (
+	// SpecFormatJSON json格式
+	SpecFormatJSON SpecFormat = "json"
+	// SpecFormatYAML yaml格式
+	SpecFormatYAML SpecFormat = "yaml"
+)
+
+// TCloudSpecReq 负载均衡声明式配置计划/应用请求
+type TCloudSpecReq struct {
+	Format  SpecFormat `json:"format" validate:"required"`
+	Content string     `json:"content" validate:"required"`
+	// Refresh 计划前是否先从云上同步该负载均衡，保证与云上最新状态对比
+	Refresh bool `json:"refresh"`
+}
+
+// Validate ...
+func (req *TCloudSpecReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	switch req.Format {
+	case SpecFormatJSON, SpecFormatYAML:
+	default:
+		return fmt.Errorf("unsupported spec format: %s", req.Format)
+	}
+	return nil
+}
+
+// Parse 解析配置内容，yaml格式先转换为json再解析，使yaml与json共用同一套字段名
+func (req *TCloudSpecReq) Parse() (*corelb.TCloudLoadBalancerSpec, error) {
+	content := []byte(req.Content)
+	if req.Format == SpecFormatYAML {
+		var raw any
+		if err := yaml.Unmarshal(content, &raw); err != nil {
+			return nil, fmt.Errorf("parse yaml spec failed, err: %v", err)
+		}
+		var err error
+		if content, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("convert yaml spec to json failed, err: %v", err)
+		}
+	}
+
+	spec := new(corelb.TCloudLoadBalancerSpec)
+	if err := json.Unmarshal(content, spec); err != nil {
+		return nil, fmt.Errorf("parse json spec failed, err: %v", err)
+	}
+	return spec, nil
+}
+
+// TCloudSpecPlanResult 声明式配置与当前状态的差异，Changes 按执行顺序排列
+type TCloudSpecPlanResult struct {
+	LbID     string                    `json:"lb_id"`
+	Changes  []corelb.TCloudSpecChange `json:"changes"`
+	Warnings []string                  `json:"warnings"`
+}
+
+// TCloudSpecApplyResult 声明式配置应用结果，没有差异时不创建任务，FlowID 为空
+type TCloudSpecApplyResult struct {
+	TCloudSpecPlanResult `json:",inline"`
+	FlowID               string `json:"flow_id"`
+}
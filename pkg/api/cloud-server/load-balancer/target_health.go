@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cslb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+)
+
+// targetHealthStatMaxDays RS健康状态统计的最大时间跨度
+const targetHealthStatMaxDays = 31
+
+// TargetHealthStatReq 业务下RS健康状态统计请求，时间格式为 2006-01-02T15:04:05Z07:00
+type TargetHealthStatReq struct {
+	StartTime string   `json:"start_time" validate:"required"`
+	EndTime   string   `json:"end_time" validate:"required"`
+	LbIDs     []string `json:"lb_ids" validate:"omitempty,max=100"`
+	// MinTransitions 统计时间段内健康状态变化次数达到该值的RS视为抖动，仅用于查询抖动的RS，默认为 3
+	MinTransitions int `json:"min_transitions" validate:"omitempty,min=1"`
+}
+
+// Validate ...
+func (req *TargetHealthStatReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	start, end, err := req.TimeRange()
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		return errors.New("end_time should be after start_time")
+	}
+	if end.Sub(start) > targetHealthStatMaxDays*24*time.Hour {
+		return fmt.Errorf("time range cannot exceed %d days", targetHealthStatMaxDays)
+	}
+	return nil
+}
+
+// TimeRange 解析统计的起止时间
+func (req *TargetHealthStatReq) TimeRange() (time.Time, time.Time, error) {
+	start, err := time.Parse(constant.TimeStdFormat, req.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start_time: %v", err)
+	}
+	end, err := time.Parse(constant.TimeStdFormat, req.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end_time: %v", err)
+	}
+	return start, end, nil
+}
+
+// TargetHealthStatResult 业务下RS健康状态统计结果
+type TargetHealthStatResult struct {
+	Details []corelb.TargetHealthStat `json:"details"`
+}
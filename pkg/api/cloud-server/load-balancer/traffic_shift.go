@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cslb
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+)
+
+// TrafficShiftCreateReq 目标组渐进式流量切换请求，新旧RS需要已绑定在同一个目标组下
+type TrafficShiftCreateReq struct {
+	AccountID    string   `json:"account_id" validate:"required"`
+	OldTargetIDs []string `json:"old_target_ids" validate:"required,min=1,dive,required"`
+	NewTargetIDs []string `json:"new_target_ids" validate:"required,min=1,dive,required"`
+	// NewWeight 切换完成后新RS的权重，旧RS的权重最终降为0
+	NewWeight *int64 `json:"new_weight" validate:"required,min=1,max=100"`
+	// Steps 切换步数，为1时即蓝绿发布的一次性切换
+	Steps           int   `json:"steps" validate:"required,min=1,max=10"`
+	StepIntervalSec int64 `json:"step_interval_sec" validate:"min=0,max=1800"`
+	// MaxUnhealthyPercent 新RS中不健康RS占比超过该值时自动回滚
+	MaxUnhealthyPercent *int64 `json:"max_unhealthy_percent" validate:"required,min=0,max=100"`
+}
+
+// Validate ...
+func (req *TrafficShiftCreateReq) Validate() error {
+	if len(req.OldTargetIDs)+len(req.NewTargetIDs) > constant.BatchModifyTargetWeightCloudMaxLimit {
+		return fmt.Errorf("the number of old and new targets cannot exceed %d",
+			constant.BatchModifyTargetWeightCloudMaxLimit)
+	}
+	oldIDs := make(map[string]struct{}, len(req.OldTargetIDs))
+	for _, id := range req.OldTargetIDs {
+		oldIDs[id] = struct{}{}
+	}
+	for _, id := range req.NewTargetIDs {
+		if _, exist := oldIDs[id]; exist {
+			return fmt.Errorf("target %s cannot be both old and new target", id)
+		}
+	}
+	return validator.Validate.Struct(req)
+}
@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"fmt"
+
+	corereach "hcm/pkg/api/core/cloud/reachability"
+	"hcm/pkg/criteria/validator"
+)
+
+// ReachabilityAnalyzeReq network reachability analyze request.
+type ReachabilityAnalyzeReq struct {
+	Source      corereach.Endpoint `json:"source" validate:"required"`
+	Destination corereach.Endpoint `json:"destination" validate:"required"`
+	// Protocol 协议，支持 tcp、udp、icmp，目的端为负载均衡监听器时可不指定
+	Protocol string `json:"protocol" validate:"omitempty"`
+	// Port 端口，目的端为负载均衡监听器时可不指定
+	Port uint32 `json:"port" validate:"omitempty,max=65535"`
+}
+
+// Validate ...
+func (req *ReachabilityAnalyzeReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Source.Validate(); err != nil {
+		return fmt.Errorf("source: %v", err)
+	}
+
+	if err := req.Destination.Validate(); err != nil {
+		return fmt.Errorf("destination: %v", err)
+	}
+
+	return nil
+}
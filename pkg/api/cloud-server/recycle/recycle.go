@@ -21,12 +21,35 @@
 package recycle
 
 import (
+	"fmt"
+
 	rr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
 )
 
 // ------------------------ Recycle ------------------------
 
+// ResRecycleReq defines recycle resource request, used by resources without recycle options.
+type ResRecycleReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecycleReq
+func (req ResRecycleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ResRecoverReq defines recover resource from recycle bin request.
+type ResRecoverReq struct {
+	RecordIDs []string `json:"record_ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecoverReq
+func (req ResRecoverReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
 // RecycleResult defines recycle resource result.
 type RecycleResult struct {
 	TaskID string `json:"task_id"`
@@ -48,3 +71,53 @@ type CvmDetail struct {
 	AccountID           string
 	rr.CvmRecycleDetail `json:",inline"`
 }
+
+// -------------------------- Policy --------------------------
+
+// PolicyCreateReq defines create recycle policy request.
+type PolicyCreateReq struct {
+	// BkBizID 策略生效的业务，-1 表示对所有业务生效，业务下接口以路径中的业务为准
+	BkBizID           int64                    `json:"bk_biz_id"`
+	ResType           enumor.CloudResourceType `json:"res_type" validate:"required"`
+	RetentionHours    uint                     `json:"retention_hours" validate:"required"`
+	NotifyBeforeHours uint                     `json:"notify_before_hours"`
+	TimeZone          string                   `json:"time_zone" validate:"omitempty,max=64"`
+	PurgeWindows      []rr.PurgeWindow         `json:"purge_windows" validate:"omitempty,max=20"`
+	Memo              *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate PolicyCreateReq.
+func (req *PolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exists := enumor.RecyclableResTypes[req.ResType]; !exists {
+		return fmt.Errorf("res_type: %s does not support recycle", req.ResType)
+	}
+
+	return rr.ValidatePurgeWindows(req.TimeZone, req.PurgeWindows)
+}
+
+// PolicyUpdateReq defines update recycle policy request, only not empty field will be updated.
+type PolicyUpdateReq struct {
+	RetentionHours    *uint            `json:"retention_hours" validate:"omitempty,min=1"`
+	NotifyBeforeHours *uint            `json:"notify_before_hours"`
+	TimeZone          *string          `json:"time_zone" validate:"omitempty,max=64"`
+	PurgeWindows      []rr.PurgeWindow `json:"purge_windows" validate:"omitempty,max=20"`
+	Memo              *string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate PolicyUpdateReq.
+func (req *PolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	timeZone := ""
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+
+	return rr.ValidatePurgeWindows(timeZone, req.PurgeWindows)
+}
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SGComplianceCheckCreateReq security group rule compliance check create request.
+type SGComplianceCheckCreateReq struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// BkBizID 检查项生效的业务，-1 表示对所有业务生效
+	BkBizID    int64                           `json:"bk_biz_id" validate:"required"`
+	Severity   enumor.SGComplianceSeverity     `json:"severity" validate:"required"`
+	Enabled    *bool                           `json:"enabled" validate:"required"`
+	Conditions coresgcompliance.CheckCondition `json:"conditions" validate:"required"`
+	Memo       *string                         `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *SGComplianceCheckCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Severity.Validate(); err != nil {
+		return err
+	}
+
+	return req.Conditions.Validate()
+}
+
+// SGComplianceCheckUpdateReq security group rule compliance check update request.
+type SGComplianceCheckUpdateReq struct {
+	Name       string                           `json:"name" validate:"omitempty,max=64"`
+	Severity   enumor.SGComplianceSeverity      `json:"severity" validate:"omitempty"`
+	Enabled    *bool                            `json:"enabled" validate:"omitempty"`
+	Conditions *coresgcompliance.CheckCondition `json:"conditions" validate:"omitempty"`
+	Memo       *string                          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *SGComplianceCheckUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Severity) != 0 {
+		if err := req.Severity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if req.Conditions != nil {
+		return req.Conditions.Validate()
+	}
+
+	return nil
+}
+
+// SGComplianceBuiltinCheck security group rule compliance builtin check info.
+type SGComplianceBuiltinCheck struct {
+	Name     enumor.SGComplianceBuiltinCheck `json:"name"`
+	Severity enumor.SGComplianceSeverity     `json:"severity"`
+	Enabled  bool                            `json:"enabled"`
+}
+
+// SGComplianceBuiltinCheckListResult security group rule compliance builtin check list result.
+type SGComplianceBuiltinCheckListResult struct {
+	// Enforce 是否拦截违规的规则
+	Enforce         bool                        `json:"enforce"`
+	EnforceSeverity enumor.SGComplianceSeverity `json:"enforce_severity"`
+	Details         []SGComplianceBuiltinCheck  `json:"details"`
+}
+
+// SGComplianceFindingListReq list security group rule compliance findings request.
+type SGComplianceFindingListReq struct {
+	// SecurityGroupIDs 需要检查的安全组，与 CloudVpcIDs 均为空时检查业务下全部安全组和gcp防火墙规则
+	SecurityGroupIDs []string `json:"security_group_ids" validate:"omitempty,max=100"`
+	// CloudVpcIDs 需要检查的gcp防火墙规则所属VPC的云ID
+	CloudVpcIDs []string                      `json:"cloud_vpc_ids" validate:"omitempty,max=100"`
+	Severities  []enumor.SGComplianceSeverity `json:"severities" validate:"omitempty,max=3"`
+}
+
+// Validate ...
+func (req *SGComplianceFindingListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, severity := range req.Severities {
+		if err := severity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range req.SecurityGroupIDs {
+		if len(id) == 0 {
+			return errors.New("security group id can not be empty")
+		}
+	}
+
+	return nil
+}
+
+// SGComplianceFindingListResult list security group rule compliance findings result.
+type SGComplianceFindingListResult struct {
+	Count   uint64                     `json:"count"`
+	Details []coresgcompliance.Finding `json:"details"`
+}
@@ -0,0 +1,119 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package approval 内置审批引擎的审批链及审批单定义
+package approval
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// MaxNodeCount 审批链最多支持的审批节点数量
+const MaxNodeCount = 10
+
+// Node 审批节点，节点内任一审批人同意即流转到下一节点，所有节点同意后审批通过
+type Node struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Approvers 固定审批人
+	Approvers []string `json:"approvers" validate:"omitempty,max=20"`
+	// Variables 审批人变量，创建审批单时由申请单Handler解析，如 account_manager 表示账号负责人
+	Variables []string `json:"variables" validate:"omitempty,max=5"`
+}
+
+// Validate ...
+func (n Node) Validate() error {
+	if err := validator.Validate.Struct(n); err != nil {
+		return err
+	}
+
+	if len(n.Approvers) == 0 && len(n.Variables) == 0 {
+		return errors.New("one of approvers and variables is required")
+	}
+
+	return nil
+}
+
+// ValidateNodes validate approval chain nodes.
+func ValidateNodes(nodes []Node) error {
+	if len(nodes) == 0 {
+		return errors.New("nodes is required")
+	}
+
+	if len(nodes) > MaxNodeCount {
+		return errors.New("nodes should <= 10")
+	}
+
+	for _, node := range nodes {
+		if err := node.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Record 审批操作记录
+type Record struct {
+	NodeIndex uint32                `json:"node_index"`
+	NodeName  string                `json:"node_name"`
+	Action    enumor.ApprovalAction `json:"action"`
+	Operator  string                `json:"operator"`
+	// Target 转审的目标审批人
+	Target  string `json:"target,omitempty"`
+	Opinion string `json:"opinion,omitempty"`
+	// OperatedAt 操作时间，constant.TimeStdFormat 格式
+	OperatedAt string `json:"operated_at"`
+}
+
+// Chain 审批链，按申请单类型及业务配置，业务ID为-1表示该申请单类型的默认审批链
+type Chain struct {
+	ID              string                 `json:"id"`
+	ApplicationType enumor.ApplicationType `json:"application_type"`
+	BkBizID         int64                  `json:"bk_biz_id"`
+	Nodes           []Node                 `json:"nodes"`
+	Memo            *string                `json:"memo"`
+	*core.Revision  `json:",inline"`
+}
+
+// Ticket 内置审批单，与来源为 native 的申请单一一对应
+type Ticket struct {
+	ID              string                 `json:"id"`
+	ApplicationID   string                 `json:"application_id"`
+	ApplicationType enumor.ApplicationType `json:"application_type"`
+	Title           string                 `json:"title"`
+	Applicant       string                 `json:"applicant"`
+	ChainID         string                 `json:"chain_id"`
+	// Nodes 创建审批单时审批链的快照，审批人变量已解析为具体审批人
+	Nodes []Node `json:"nodes"`
+	// CurrentNode 当前审批节点下标
+	CurrentNode uint32 `json:"current_node"`
+	// Approvers 当前节点的审批人，转审后会替换为目标审批人
+	Approvers []string                    `json:"approvers"`
+	Status    enumor.ApprovalTicketStatus `json:"status"`
+	Records   []Record                    `json:"records"`
+	// Version 乐观锁版本号，每次审批操作后加一
+	Version uint32 `json:"version"`
+	// RemindedAt 最近一次通知审批人的时间，constant.TimeStdFormat 格式
+	RemindedAt     string `json:"reminded_at"`
+	*core.Revision `json:",inline"`
+}
@@ -0,0 +1,240 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/slice"
+)
+
+// AutoApprovalPolicy 自动审批策略，申请单满足策略的所有条件时跳过人工审批直接交付
+type AutoApprovalPolicy struct {
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	ApplicationType enumor.ApplicationType `json:"application_type"`
+	// Priority 优先级，数值越小越优先匹配
+	Priority       uint32                `json:"priority"`
+	Enabled        bool                  `json:"enabled"`
+	Conditions     AutoApprovalCondition `json:"conditions"`
+	Memo           *string               `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// AutoApprovalCondition 自动审批条件，未设置的条件不参与匹配，设置的条件需全部满足
+type AutoApprovalCondition struct {
+	BkBizIDs []int64         `json:"bk_biz_ids,omitempty" validate:"omitempty,max=100"`
+	Vendors  []enumor.Vendor `json:"vendors,omitempty" validate:"omitempty,max=10"`
+	Regions  []string        `json:"regions,omitempty" validate:"omitempty,max=100"`
+	// InstanceTypes 主机机型或云盘类型
+	InstanceTypes []string `json:"instance_types,omitempty" validate:"omitempty,max=100"`
+	// MaxCount 单次申请的资源数量上限
+	MaxCount *int64 `json:"max_count,omitempty" validate:"omitempty,min=1"`
+	// MaxCost 询价得到的费用上限，无法询价的申请单不满足该条件
+	MaxCost *float64 `json:"max_cost,omitempty" validate:"omitempty,min=0"`
+	// CIDRs 安全组规则的源/目标地址需包含在其中一个网段内，支持单个IP地址
+	CIDRs []string `json:"cidrs,omitempty" validate:"omitempty,max=50"`
+	// PortRanges 安全组规则的端口需包含在其中一个端口范围内
+	PortRanges []PortRange `json:"port_ranges,omitempty" validate:"omitempty,max=50"`
+}
+
+// PortRange 端口范围，From 与 To 相等时表示单个端口
+type PortRange struct {
+	From uint32 `json:"from" validate:"max=65535"`
+	To   uint32 `json:"to" validate:"max=65535"`
+}
+
+// Contains 端口范围是否包含另一个端口范围
+func (p PortRange) Contains(other PortRange) bool {
+	return p.From <= other.From && other.To <= p.To
+}
+
+// Validate ...
+func (c AutoApprovalCondition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	for _, vendor := range c.Vendors {
+		if err := vendor.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, cidr := range c.CIDRs {
+		if _, err := parseIPNet(cidr); err != nil {
+			return err
+		}
+	}
+
+	for _, one := range c.PortRanges {
+		if one.From > one.To {
+			return fmt.Errorf("invalid port range: %d-%d", one.From, one.To)
+		}
+	}
+
+	if c.IsEmpty() {
+		return errors.New("at least one condition is required")
+	}
+
+	return nil
+}
+
+// IsEmpty 是否未设置任何条件，未设置条件的策略会放行所有申请，不允许创建
+func (c AutoApprovalCondition) IsEmpty() bool {
+	return len(c.BkBizIDs) == 0 && len(c.Vendors) == 0 && len(c.Regions) == 0 && len(c.InstanceTypes) == 0 &&
+		c.MaxCount == nil && c.MaxCost == nil && len(c.CIDRs) == 0 && len(c.PortRanges) == 0
+}
+
+// AutoApprovalFacts 申请单中用于匹配自动审批策略的信息
+type AutoApprovalFacts struct {
+	BkBizIDs      []int64
+	Vendor        enumor.Vendor
+	Regions       []string
+	InstanceTypes []string
+	Count         int64
+	// Cost 询价得到的费用，为空表示不支持询价或未询价
+	Cost       *float64
+	CIDRs      []string
+	PortRanges []PortRange
+}
+
+// Match 判断申请单信息是否满足所有条件，不满足时返回原因
+func (c AutoApprovalCondition) Match(facts *AutoApprovalFacts) (bool, string) {
+	if facts == nil {
+		return false, "no facts"
+	}
+
+	if len(c.BkBizIDs) > 0 && (len(facts.BkBizIDs) == 0 || !allIn(facts.BkBizIDs, c.BkBizIDs)) {
+		return false, "business not matched"
+	}
+
+	if len(c.Vendors) > 0 && !slice.IsItemInSlice(c.Vendors, facts.Vendor) {
+		return false, "vendor not matched"
+	}
+
+	if len(c.Regions) > 0 && (len(facts.Regions) == 0 || !allIn(facts.Regions, c.Regions)) {
+		return false, "region not matched"
+	}
+
+	if len(c.InstanceTypes) > 0 && (len(facts.InstanceTypes) == 0 || !allIn(facts.InstanceTypes, c.InstanceTypes)) {
+		return false, "instance type not matched"
+	}
+
+	if c.MaxCount != nil && facts.Count > *c.MaxCount {
+		return false, "count exceeded"
+	}
+
+	if c.MaxCost != nil && (facts.Cost == nil || *facts.Cost > *c.MaxCost) {
+		return false, "cost exceeded or unknown"
+	}
+
+	if len(c.CIDRs) > 0 && (len(facts.CIDRs) == 0 || !cidrsContained(facts.CIDRs, c.CIDRs)) {
+		return false, "cidr not matched"
+	}
+
+	if len(c.PortRanges) > 0 && (len(facts.PortRanges) == 0 || !portRangesContained(facts.PortRanges,
+		c.PortRanges)) {
+		return false, "port not matched"
+	}
+
+	return true, ""
+}
+
+func allIn[T comparable](values, allowed []T) bool {
+	for _, value := range values {
+		if !slice.IsItemInSlice(allowed, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// cidrsContained 判断每个网段(或IP地址)是否都包含在允许的某个网段内
+func cidrsContained(cidrs, allowed []string) bool {
+	allowedNets := make([]*net.IPNet, 0, len(allowed))
+	for _, one := range allowed {
+		if ipNet, err := parseIPNet(one); err == nil {
+			allowedNets = append(allowedNets, ipNet)
+		}
+	}
+
+	for _, cidr := range cidrs {
+		ipNet, err := parseIPNet(cidr)
+		if err != nil {
+			return false
+		}
+
+		ones, bits := ipNet.Mask.Size()
+		contained := false
+		for _, allowedNet := range allowedNets {
+			allowedOnes, allowedBits := allowedNet.Mask.Size()
+			if allowedBits == bits && allowedOnes <= ones && allowedNet.Contains(ipNet.IP) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseIPNet 解析网段，兼容单个IP地址
+func parseIPNet(cidr string) (*net.IPNet, error) {
+	if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+		return ipNet, nil
+	}
+
+	ip := net.ParseIP(cidr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid cidr: %s", cidr)
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)}, nil
+}
+
+// portRangesContained 判断每个端口范围是否都包含在允许的某个端口范围内
+func portRangesContained(ranges, allowed []PortRange) bool {
+	for _, one := range ranges {
+		contained := false
+		for _, allowedRange := range allowed {
+			if allowedRange.Contains(one) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package approval
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoApprovalCondition_Match(t *testing.T) {
+	cond := AutoApprovalCondition{
+		BkBizIDs:      []int64{100},
+		Vendors:       []enumor.Vendor{enumor.TCloud},
+		Regions:       []string{"ap-guangzhou"},
+		InstanceTypes: []string{"S5.MEDIUM4"},
+		MaxCount:      converter.ValToPtr(int64(2)),
+		MaxCost:       converter.ValToPtr(100.0),
+	}
+	assert.NoError(t, cond.Validate())
+
+	facts := &AutoApprovalFacts{
+		BkBizIDs:      []int64{100},
+		Vendor:        enumor.TCloud,
+		Regions:       []string{"ap-guangzhou"},
+		InstanceTypes: []string{"S5.MEDIUM4"},
+		Count:         2,
+		Cost:          converter.ValToPtr(99.5),
+	}
+	matched, _ := cond.Match(facts)
+	assert.True(t, matched)
+
+	// 超过数量上限
+	facts.Count = 3
+	matched, _ = cond.Match(facts)
+	assert.False(t, matched)
+	facts.Count = 1
+
+	// 无法询价时不满足费用条件
+	facts.Cost = nil
+	matched, _ = cond.Match(facts)
+	assert.False(t, matched)
+	facts.Cost = converter.ValToPtr(10.0)
+
+	// 业务不匹配
+	facts.BkBizIDs = []int64{200}
+	matched, _ = cond.Match(facts)
+	assert.False(t, matched)
+}
+
+func TestAutoApprovalCondition_MatchRule(t *testing.T) {
+	cond := AutoApprovalCondition{
+		CIDRs:      []string{"10.0.0.0/8", "192.168.1.1"},
+		PortRanges: []PortRange{{From: 80, To: 80}, {From: 8000, To: 9000}},
+	}
+	assert.NoError(t, cond.Validate())
+
+	facts := &AutoApprovalFacts{
+		CIDRs:      []string{"10.1.0.0/16", "192.168.1.1/32"},
+		PortRanges: []PortRange{{From: 80, To: 80}, {From: 8080, To: 8090}},
+	}
+	matched, _ := cond.Match(facts)
+	assert.True(t, matched)
+
+	// 网段超出允许范围
+	facts.CIDRs = []string{"0.0.0.0/0"}
+	matched, _ = cond.Match(facts)
+	assert.False(t, matched)
+
+	// 端口超出允许范围
+	facts.CIDRs = []string{"10.0.0.1"}
+	facts.PortRanges = []PortRange{{From: 8000, To: 9001}}
+	matched, _ = cond.Match(facts)
+	assert.False(t, matched)
+
+	// 空条件不合法
+	assert.Error(t, AutoApprovalCondition{}.Validate())
+}
@@ -35,6 +35,7 @@ type AsyncFlow struct {
 	ShareData     *tableasync.ShareData `json:"share_data"`
 	Memo          string                `json:"memo"`
 	Worker        *string               `json:"worker"`
+	Deadline      *string               `json:"deadline"`
 	core.Revision `json:",inline"`
 }
 
@@ -53,3 +54,20 @@ type AsyncFlowTask struct {
 	Reason        *tableasync.Reason `json:"reason"`
 	core.Revision `json:",inline"`
 }
+
+// AsyncFlowSchedule ...
+type AsyncFlowSchedule struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	FlowName      enumor.FlowName      `json:"flow_name"`
+	CronExpr      string               `json:"cron_expr"`
+	TimeZone      string               `json:"time_zone"`
+	Tasks         types.JsonField      `json:"tasks"`
+	Enabled       *bool                `json:"enabled"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy"`
+	NextFireTime  string               `json:"next_fire_time"`
+	LastFireTime  string               `json:"last_fire_time"`
+	LastFlowID    string               `json:"last_flow_id"`
+	Memo          *string              `json:"memo"`
+	core.Revision `json:",inline"`
+}
@@ -47,6 +47,9 @@ type Audit struct {
 	Rid                  string                   `json:"rid"`
 	AppCode              string                   `json:"app_code"`
 	Detail               any                      `json:"detail,omitempty"` // Detail list接口该字段默认不返回
+	ChainSeq             uint64                   `json:"chain_seq"`
+	PrevHash             string                   `json:"prev_hash"`
+	Hash                 string                   `json:"hash"`
 	CreatedAt            string                   `json:"created_at"`
 }
 
@@ -70,6 +73,9 @@ type RawAudit struct {
 	Rid                  string                   `json:"rid"`
 	AppCode              string                   `json:"app_code"`
 	Detail               *audit.BasicDetailRaw    `json:"detail,omitempty"` // Detail list接口该字段默认不返回
+	ChainSeq             uint64                   `json:"chain_seq"`
+	PrevHash             string                   `json:"prev_hash"`
+	Hash                 string                   `json:"hash"`
 	CreatedAt            string                   `json:"created_at"`
 }
 
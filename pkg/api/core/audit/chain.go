@@ -0,0 +1,54 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import "hcm/pkg/criteria/enumor"
+
+// ChainVerifyResult is the result of verifying a tenant's audit hash chain.
+type ChainVerifyResult struct {
+	TenantID string `json:"tenant_id"`
+	// StartSeq、EndSeq 本次校验的哈希链序号范围
+	StartSeq uint64 `json:"start_seq"`
+	EndSeq   uint64 `json:"end_seq"`
+	// LastSeq 链头表记录的哈希链最新序号
+	LastSeq uint64 `json:"last_seq"`
+	// Checked 本次校验的审计记录数
+	Checked uint64 `json:"checked"`
+	// Valid 哈希链是否完整且未被篡改
+	Valid bool `json:"valid"`
+	// IssueCount 发现的问题总数，Issues 最多返回 ChainVerifyMaxIssues 条
+	IssueCount uint64       `json:"issue_count"`
+	Issues     []ChainIssue `json:"issues"`
+}
+
+// ChainVerifyMaxIssues is the max number of issues returned in ChainVerifyResult.
+const ChainVerifyMaxIssues = 100
+
+// ChainIssue is an issue found when verifying the audit hash chain.
+type ChainIssue struct {
+	Type enumor.AuditChainIssueType `json:"type"`
+	// Seq 出现问题的哈希链序号，gap 类型时为缺失的起始序号
+	Seq uint64 `json:"seq"`
+	// EndSeq gap 类型时为缺失的结束序号
+	EndSeq uint64 `json:"end_seq,omitempty"`
+	// AuditID 出现问题的审计记录ID
+	AuditID uint64 `json:"audit_id,omitempty"`
+	Message string `json:"message"`
+}
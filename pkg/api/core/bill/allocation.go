@@ -0,0 +1,165 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+// AllocationRule 账单分摊规则
+type AllocationRule struct {
+	ID       string                      `json:"id"`
+	Name     string                      `json:"name"`
+	Vendor   enumor.Vendor               `json:"vendor"`
+	Priority uint                        `json:"priority"`
+	Enabled  bool                        `json:"enabled"`
+	Matcher  AllocationMatcher           `json:"matcher"`
+	Method   enumor.BillAllocationMethod `json:"method"`
+	Config   AllocationConfig            `json:"config"`
+	Memo     string                      `json:"memo"`
+
+	*core.Revision `json:",inline"`
+}
+
+// AllocationMatcher 账单明细匹配条件，各条件之间为且关系，条件内为或关系，至少需要指定一个条件
+type AllocationMatcher struct {
+	RootAccountIDs []string `json:"root_account_ids,omitempty"`
+	MainAccountIDs []string `json:"main_account_ids,omitempty"`
+	HcProductCodes []string `json:"hc_product_codes,omitempty"`
+	HcProductNames []string `json:"hc_product_names,omitempty"`
+}
+
+// Validate AllocationMatcher
+func (m *AllocationMatcher) Validate() error {
+	if len(m.RootAccountIDs) == 0 && len(m.MainAccountIDs) == 0 && len(m.HcProductCodes) == 0 &&
+		len(m.HcProductNames) == 0 {
+		return errors.New("at least one of root_account_ids, main_account_ids, hc_product_codes, " +
+			"hc_product_names is required")
+	}
+
+	return nil
+}
+
+// AllocationShare 按固定比例分摊时单个业务的分摊比例
+type AllocationShare struct {
+	BkBizID int64           `json:"bk_biz_id"`
+	Ratio   decimal.Decimal `json:"ratio"`
+}
+
+// AllocationConfig 分摊方式配置
+type AllocationConfig struct {
+	// Shares 按固定比例分摊时各业务的分摊比例，比例之和必须为1，仅 fixed_ratio 方式使用
+	Shares []AllocationShare `json:"shares,omitempty"`
+	// ResType 按资源使用业务分摊时限定的资源类型，为空时不限制，仅 res_usage 方式使用
+	ResType enumor.CloudResourceType `json:"res_type,omitempty"`
+	// TagKey 按标签分摊时使用的标签键，仅 tag 方式使用
+	TagKey string `json:"tag_key,omitempty"`
+	// TagValueBizIDs 按标签分摊时标签值与业务的映射关系，仅 tag 方式使用
+	TagValueBizIDs map[string]int64 `json:"tag_value_biz_ids,omitempty"`
+}
+
+// AllocationResUsageVendors 支持按资源使用业务分摊的云厂商，其账单明细中带有可以和资源关联的资源ID
+var AllocationResUsageVendors = map[enumor.Vendor]struct{}{
+	enumor.TCloud: {},
+	enumor.HuaWei: {},
+	enumor.Aws:    {},
+}
+
+// Validate AllocationConfig against the allocation method and vendor.
+func (c *AllocationConfig) Validate(method enumor.BillAllocationMethod, vendor enumor.Vendor) error {
+	switch method {
+	case enumor.BillAllocationFixedRatio:
+		if len(c.Shares) == 0 {
+			return errors.New("shares is required for fixed_ratio allocation")
+		}
+
+		total := decimal.Zero
+		bizIDs := make(map[int64]struct{}, len(c.Shares))
+		for _, share := range c.Shares {
+			if share.BkBizID <= 0 {
+				return fmt.Errorf("invalid share bk_biz_id: %d", share.BkBizID)
+			}
+			if _, exists := bizIDs[share.BkBizID]; exists {
+				return fmt.Errorf("share bk_biz_id %d is duplicated", share.BkBizID)
+			}
+			bizIDs[share.BkBizID] = struct{}{}
+
+			if !share.Ratio.IsPositive() {
+				return fmt.Errorf("share ratio of bk_biz_id %d should be positive", share.BkBizID)
+			}
+			total = total.Add(share.Ratio)
+		}
+		if !total.Equal(decimal.NewFromInt(1)) {
+			return fmt.Errorf("sum of share ratios should be 1, but got %s", total.String())
+		}
+
+	case enumor.BillAllocationResUsage:
+		if _, exists := AllocationResUsageVendors[vendor]; !exists {
+			return fmt.Errorf("res_usage allocation does not support vendor: %s", vendor)
+		}
+
+	case enumor.BillAllocationTag:
+		if vendor != enumor.TCloud {
+			return fmt.Errorf("tag allocation does not support vendor: %s", vendor)
+		}
+		if len(c.TagKey) == 0 {
+			return errors.New("tag_key is required for tag allocation")
+		}
+		if len(c.TagValueBizIDs) == 0 {
+			return errors.New("tag_value_biz_ids is required for tag allocation")
+		}
+		for value, bizID := range c.TagValueBizIDs {
+			if bizID <= 0 {
+				return fmt.Errorf("invalid bk_biz_id %d of tag value %s", bizID, value)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported bill allocation method: %s", method)
+	}
+
+	return nil
+}
+
+// AllocationResult 账单分摊结果
+type AllocationResult struct {
+	ID            string              `json:"id"`
+	RuleID        string              `json:"rule_id"`
+	Vendor        enumor.Vendor       `json:"vendor"`
+	RootAccountID string              `json:"root_account_id"`
+	MainAccountID string              `json:"main_account_id"`
+	BillYear      int                 `json:"bill_year"`
+	BillMonth     int                 `json:"bill_month"`
+	BillDay       int                 `json:"bill_day"`
+	VersionID     int                 `json:"version_id"`
+	SourceBkBizID int64               `json:"source_bk_biz_id"`
+	BkBizID       int64               `json:"bk_biz_id"`
+	Currency      enumor.CurrencyCode `json:"currency"`
+	Cost          *decimal.Decimal    `json:"cost"`
+	ItemCount     uint                `json:"item_count"`
+
+	*core.Revision `json:",inline"`
+}
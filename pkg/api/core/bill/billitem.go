@@ -28,8 +28,16 @@ import (
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/model"
 	"github.com/shopspring/decimal"
+	billing "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/billing/v20180709"
 )
 
+// BillItemResIDKeys 各云厂商账单明细扩展字段中资源ID的字段名，gcp、azure账单明细中没有可以和资源关联的资源ID
+var BillItemResIDKeys = map[enumor.Vendor]string{
+	enumor.TCloud: "ResourceId",
+	enumor.HuaWei: "resource_id",
+	enumor.Aws:    "line_item_resource_id",
+}
+
 // BaseBillItem 存储分账后的明细
 type BaseBillItem struct {
 	ID             string              `json:"id,omitempty"`
@@ -98,6 +106,7 @@ type BillItemExtension interface {
 
 // TCloudBillItemExtension ...
 type TCloudBillItemExtension struct {
+	*billing.BillDetail `json:",inline"`
 }
 
 // AwsBillItemExtension ...
@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// DiskSnapshot define disk snapshot.
+type DiskSnapshot struct {
+	ID               string        `json:"id"`
+	Vendor           enumor.Vendor `json:"vendor"`
+	AccountID        string        `json:"account_id"`
+	CloudID          string        `json:"cloud_id"`
+	BkBizID          int64         `json:"bk_biz_id"`
+	Name             string        `json:"name"`
+	Region           string        `json:"region"`
+	Zone             string        `json:"zone"`
+	DiskID           string        `json:"disk_id"`
+	CloudDiskID      string        `json:"cloud_disk_id"`
+	DiskSize         uint64        `json:"disk_size"`
+	Status           string        `json:"status"`
+	Encrypted        *bool         `json:"encrypted"`
+	PolicyID         string        `json:"policy_id"`
+	Memo             *string       `json:"memo"`
+	CloudCreatedTime string        `json:"cloud_created_time"`
+	Extension        *Extension    `json:"extension"`
+	*core.Revision   `json:",inline"`
+}
+
+// GetID ...
+func (s DiskSnapshot) GetID() string {
+	return s.ID
+}
+
+// GetCloudID ...
+func (s DiskSnapshot) GetCloudID() string {
+	return s.CloudID
+}
+
+// Extension define disk snapshot vendor extension.
+type Extension struct {
+	// ResourceGroupName azure 资源组
+	ResourceGroupName string `json:"resource_group_name,omitempty"`
+	// SelfLink gcp 快照 selfLink
+	SelfLink string `json:"self_link,omitempty"`
+}
+
+// Policy define disk snapshot policy.
+type Policy struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Vendor         enumor.Vendor `json:"vendor"`
+	AccountID      string        `json:"account_id"`
+	BkBizID        int64         `json:"bk_biz_id"`
+	DiskIDs        []string      `json:"disk_ids"`
+	CronExpr       string        `json:"cron_expr"`
+	TimeZone       string        `json:"time_zone"`
+	RetentionCount uint32        `json:"retention_count"`
+	RetentionDays  uint32        `json:"retention_days"`
+	Enabled        bool          `json:"enabled"`
+	ScheduleID     string        `json:"schedule_id"`
+	Memo           *string       `json:"memo"`
+	*core.Revision `json:",inline"`
+}
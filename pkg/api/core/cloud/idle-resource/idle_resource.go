@@ -0,0 +1,46 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package idleresource ...
+package idleresource
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+// IdleResource 闲置资源，由闲置资源分析任务根据资源关联关系和账单费用识别
+type IdleResource struct {
+	ID         string                    `json:"id"`
+	Vendor     enumor.Vendor             `json:"vendor"`
+	ResType    enumor.CloudResourceType  `json:"res_type"`
+	ResID      string                    `json:"res_id"`
+	CloudResID string                    `json:"cloud_res_id"`
+	ResName    string                    `json:"res_name"`
+	AccountID  string                    `json:"account_id"`
+	BkBizID    int64                     `json:"bk_biz_id"`
+	Region     string                    `json:"region"`
+	Reason     enumor.IdleResourceReason `json:"reason"`
+	// Cost 统计周期内该资源的账单费用，没有账单数据时为0
+	Cost           decimal.Decimal     `json:"cost"`
+	Currency       enumor.CurrencyCode `json:"currency"`
+	*core.Revision `json:",inline"`
+}
@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+// AwsLoadBalancer ...
+type AwsLoadBalancer = LoadBalancer[AwsLoadBalancerExtension]
+
+// AwsLoadBalancerExtension aws elb extension.
+type AwsLoadBalancerExtension struct {
+	// Type 负载均衡类型，application：应用型，network：网络型，gateway：网关型
+	Type *string `json:"type,omitempty"`
+	// Scheme 网络属性，internet-facing：公网，internal：内网
+	Scheme *string `json:"scheme,omitempty"`
+	// DNSName 负载均衡的公共DNS名称
+	DNSName *string `json:"dns_name,omitempty"`
+	// CanonicalHostedZoneID 负载均衡关联的Route 53托管区域ID
+	CanonicalHostedZoneID *string `json:"canonical_hosted_zone_id,omitempty"`
+	// IpAddressType ipv4 | dualstack
+	IpAddressType *string `json:"ip_address_type,omitempty"`
+	// CloudSubnetIDs 负载均衡所在的子网，aws负载均衡每个可用区对应一个子网
+	CloudSubnetIDs []string `json:"cloud_subnet_ids,omitempty"`
+	// CloudSecurityGroupIDs 负载均衡关联的安全组，仅ALB、NLB支持
+	CloudSecurityGroupIDs []string `json:"cloud_security_group_ids,omitempty"`
+	// StateReason 负载均衡处于非active状态的原因
+	StateReason *string `json:"state_reason,omitempty"`
+}
+
+// AwsListener ...
+type AwsListener = Listener[AwsListenerExtension]
+
+// AwsListenerExtension aws elb 监听器拓展
+type AwsListenerExtension struct {
+	// SslPolicy HTTPS/TLS监听器的安全策略
+	SslPolicy *string `json:"ssl_policy,omitempty"`
+	// CertCloudIDs 证书arn列表
+	CertCloudIDs []string `json:"cert_cloud_ids,omitempty"`
+	// DefaultCloudTargetGroupIDs 默认转发动作指向的目标组arn
+	DefaultCloudTargetGroupIDs []string `json:"default_cloud_target_group_ids,omitempty"`
+	// AlpnPolicy TLS监听器的ALPN策略
+	AlpnPolicy []string `json:"alpn_policy,omitempty"`
+}
+
+// AwsTargetGroupExtension aws elb 目标组拓展
+type AwsTargetGroupExtension struct {
+	// TargetType 后端类型 instance | ip | lambda | alb
+	TargetType *string `json:"target_type,omitempty"`
+	// ProtocolVersion HTTP1 | HTTP2 | GRPC
+	ProtocolVersion *string `json:"protocol_version,omitempty"`
+	// IpAddressType ipv4 | ipv6
+	IpAddressType *string `json:"ip_address_type,omitempty"`
+	// CloudLoadBalancerIDs 目标组关联的负载均衡arn列表
+	CloudLoadBalancerIDs []string `json:"cloud_load_balancer_ids,omitempty"`
+	// HealthCheckMatcher 健康检查成功返回码，如 200-299
+	HealthCheckMatcher *string `json:"health_check_matcher,omitempty"`
+}
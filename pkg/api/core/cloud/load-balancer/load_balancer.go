@@ -123,7 +123,7 @@ func (lb LoadBalancer[T]) GetCloudID() string {
 
 // Extension extension.
 type Extension interface {
-	TCloudClbExtension
+	TCloudClbExtension | AwsLoadBalancerExtension
 }
 
 // BaseListener define base listener.
@@ -168,7 +168,7 @@ func (lbl Listener[T]) GetCloudID() string {
 
 // ListenerExtension 监听器拓展
 type ListenerExtension interface {
-	TCloudListenerExtension
+	TCloudListenerExtension | AwsListenerExtension
 }
 
 // TCloudLbUrlRule define base tcloud lb url rule.
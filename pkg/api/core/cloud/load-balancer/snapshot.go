@@ -0,0 +1,39 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// LoadBalancerSnapshot 负载均衡配置快照，Spec 为快照时刻负载均衡的完整声明式配置
+type LoadBalancerSnapshot struct {
+	ID             string                  `json:"id"`
+	Vendor         enumor.Vendor           `json:"vendor"`
+	LbID           string                  `json:"lb_id"`
+	AccountID      string                  `json:"account_id"`
+	BkBizID        int64                   `json:"bk_biz_id"`
+	Version        uint64                  `json:"version"`
+	Source         enumor.LbSnapshotSource `json:"source"`
+	FlowID         string                  `json:"flow_id"`
+	Spec           *TCloudLoadBalancerSpec `json:"spec,omitempty"`
+	*core.Revision `json:",inline"`
+}
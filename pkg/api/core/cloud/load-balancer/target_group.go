@@ -47,6 +47,16 @@ type BaseTargetGroup struct {
 	*core.Revision  `json:",inline"`
 }
 
+// GetID ...
+func (tg BaseTargetGroup) GetID() string {
+	return tg.ID
+}
+
+// GetCloudID ...
+func (tg BaseTargetGroup) GetCloudID() string {
+	return tg.CloudID
+}
+
 // TargetGroup define target group.
 type TargetGroup[Ext TargetGroupExtension] struct {
 	BaseTargetGroup `json:",inline"`
@@ -65,7 +75,7 @@ func (tg TargetGroup[T]) GetCloudID() string {
 
 // TargetGroupExtension extension.
 type TargetGroupExtension interface {
-	TCloudTargetGroupExtension
+	TCloudTargetGroupExtension | AwsTargetGroupExtension
 }
 
 // BaseTarget define base target.
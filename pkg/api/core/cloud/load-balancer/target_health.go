@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"hcm/pkg/criteria/enumor"
+)
+
+// TargetHealthEvent RS健康状态变化事件，PrevStatus 为空表示首次采集到该RS
+type TargetHealthEvent struct {
+	ID           string                    `json:"id"`
+	Vendor       enumor.Vendor             `json:"vendor"`
+	AccountID    string                    `json:"account_id"`
+	BkBizID      int64                     `json:"bk_biz_id"`
+	LbID         string                    `json:"lb_id"`
+	CloudLbID    string                    `json:"cloud_lb_id"`
+	CloudLblID   string                    `json:"cloud_lbl_id"`
+	CloudRuleID  string                    `json:"cloud_rule_id"`
+	CloudInstID  string                    `json:"cloud_inst_id"`
+	IP           string                    `json:"ip"`
+	Port         int64                     `json:"port"`
+	Status       enumor.TargetHealthStatus `json:"status"`
+	PrevStatus   enumor.TargetHealthStatus `json:"prev_status"`
+	StatusDetail string                    `json:"status_detail"`
+	Creator      string                    `json:"creator"`
+	CreatedAt    string                    `json:"created_at"`
+}
+
+// TargetHealthStat RS在统计时间段内的健康状态统计
+type TargetHealthStat struct {
+	LbID        string `json:"lb_id"`
+	CloudLbID   string `json:"cloud_lb_id"`
+	CloudLblID  string `json:"cloud_lbl_id"`
+	CloudRuleID string `json:"cloud_rule_id"`
+	CloudInstID string `json:"cloud_inst_id"`
+	IP          string `json:"ip"`
+	Port        int64  `json:"port"`
+	// Status 统计结束时的健康状态
+	Status        enumor.TargetHealthStatus `json:"status"`
+	LastChangedAt string                    `json:"last_changed_at"`
+	// Transitions 统计时间段内健康状态变化的次数
+	Transitions int `json:"transitions"`
+	// UnhealthyTimes 统计时间段内变为不健康的次数
+	UnhealthyTimes int `json:"unhealthy_times"`
+	// DowntimeSec 统计时间段内处于不健康状态的总时长，单位秒
+	DowntimeSec int64 `json:"downtime_sec"`
+}
@@ -211,6 +211,47 @@ type TCloudCertificateInfo struct {
 	CertCloudIDs []string `json:"cert_cloud_ids,omitempty"`
 }
 
+// UsesCert 是否使用了指定的证书，包括服务端证书和CA证书
+func (c *TCloudCertificateInfo) UsesCert(cloudID string) bool {
+	if c == nil || len(cloudID) == 0 {
+		return false
+	}
+
+	if c.CaCloudID != nil && *c.CaCloudID == cloudID {
+		return true
+	}
+
+	for _, one := range c.CertCloudIDs {
+		if one == cloudID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplaceCert 将证书信息中的旧证书替换为新证书，返回替换后的副本，不修改原证书信息
+func (c *TCloudCertificateInfo) ReplaceCert(oldCloudID, newCloudID string) *TCloudCertificateInfo {
+	if c == nil {
+		return nil
+	}
+
+	replaced := &TCloudCertificateInfo{SSLMode: c.SSLMode, CaCloudID: c.CaCloudID}
+	if c.CaCloudID != nil && *c.CaCloudID == oldCloudID {
+		replaced.CaCloudID = &newCloudID
+	}
+
+	if c.CertCloudIDs != nil {
+		replaced.CertCloudIDs = make([]string, 0, len(c.CertCloudIDs))
+		for _, one := range c.CertCloudIDs {
+			if one == oldCloudID {
+				one = newCloudID
+			}
+			replaced.CertCloudIDs = append(replaced.CertCloudIDs, one)
+		}
+	}
+	return replaced
+}
+
 // TCloudListenerExtension 腾讯云监听器拓展
 type TCloudListenerExtension struct {
 	EndPort     *int64                 `json:"end_port,omitempty"`
@@ -0,0 +1,171 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// TCloudLoadBalancerSpec 腾讯云负载均衡声明式配置，描述负载均衡下监听器、规则、健康检查、证书及RS的期望状态
+type TCloudLoadBalancerSpec struct {
+	// CloudID 负载均衡云上ID，非空时需要与待应用的负载均衡一致
+	CloudID string `json:"cloud_id,omitempty"`
+	// Prune 是否删除配置中未声明的监听器，未开启时只新增、修改
+	Prune     bool                 `json:"prune,omitempty"`
+	Listeners []TCloudListenerSpec `json:"listeners"`
+}
+
+// TCloudListenerSpec 监听器声明式配置，四层监听器直接声明RS，七层监听器通过Rules声明规则及RS。
+// 未声明的可选字段不做管理，已声明监听器下的规则、RS以配置为准
+type TCloudListenerSpec struct {
+	Name          string                 `json:"name"`
+	Protocol      enumor.ProtocolType    `json:"protocol"`
+	Port          int64                  `json:"port"`
+	EndPort       int64                  `json:"end_port,omitempty"`
+	Scheduler     string                 `json:"scheduler,omitempty"`
+	SessionType   string                 `json:"session_type,omitempty"`
+	SessionExpire *int64                 `json:"session_expire,omitempty"`
+	SniSwitch     *enumor.SniType        `json:"sni_switch,omitempty"`
+	Certificate   *TCloudCertificateInfo `json:"certificate,omitempty"`
+	HealthCheck   *TCloudHealthCheckInfo `json:"health_check,omitempty"`
+	Targets       []TCloudTargetSpec     `json:"targets,omitempty"`
+	Rules         []TCloudRuleSpec       `json:"rules,omitempty"`
+}
+
+// Key 监听器在负载均衡下的唯一标识
+func (l TCloudListenerSpec) Key() string {
+	return fmt.Sprintf("%s:%d", l.Protocol, l.Port)
+}
+
+// TCloudRuleSpec 七层规则声明式配置，Certificate 仅对开启SNI的监听器生效
+type TCloudRuleSpec struct {
+	Domain        string                 `json:"domain"`
+	Url           string                 `json:"url"`
+	Scheduler     string                 `json:"scheduler,omitempty"`
+	SessionExpire *int64                 `json:"session_expire,omitempty"`
+	HealthCheck   *TCloudHealthCheckInfo `json:"health_check,omitempty"`
+	Certificate   *TCloudCertificateInfo `json:"certificate,omitempty"`
+	Targets       []TCloudTargetSpec     `json:"targets,omitempty"`
+}
+
+// Key 规则在监听器下的唯一标识
+func (r TCloudRuleSpec) Key() string {
+	return r.Domain + r.Url
+}
+
+// TCloudTargetSpec RS声明式配置，CVM类型需要指定 cloud_inst_id，ENI类型需要指定 ip
+type TCloudTargetSpec struct {
+	InstType    enumor.InstType `json:"inst_type"`
+	CloudInstID string          `json:"cloud_inst_id,omitempty"`
+	IP          string          `json:"ip,omitempty"`
+	Port        int64           `json:"port"`
+	Weight      *int64          `json:"weight"`
+}
+
+// Key RS在监听器/规则下的唯一标识
+func (t TCloudTargetSpec) Key() string {
+	if t.InstType == enumor.EniInstType {
+		return fmt.Sprintf("%s/%s:%d", t.InstType, t.IP, t.Port)
+	}
+	return fmt.Sprintf("%s/%s:%d", t.InstType, t.CloudInstID, t.Port)
+}
+
+// SpecChangeAction 声明式配置变更动作
+type SpecChangeAction string
+
+const (
+	// SpecChangeCreate 新增
+	SpecChangeCreate SpecChangeAction = "create"
+	// SpecChangeUpdate 修改
+	SpecChangeUpdate SpecChangeAction = "update"
+	// SpecChangeDelete 删除
+	SpecChangeDelete SpecChangeAction = "delete"
+)
+
+// SpecChangeResType 声明式配置变更的资源类型
+type SpecChangeResType string
+
+const (
+	// SpecResListener 监听器
+	SpecResListener SpecChangeResType = "listener"
+	// SpecResUrlRule 七层规则
+	SpecResUrlRule SpecChangeResType = "url_rule"
+	// SpecResTarget RS
+	SpecResTarget SpecChangeResType = "target"
+)
+
+// TCloudSpecChange 声明式配置与当前状态的单项差异。已存在资源的ID在计划阶段确定，
+// 新建资源的ID在执行阶段按 协议:端口、域名+URL 重新解析
+type TCloudSpecChange struct {
+	Action   SpecChangeAction    `json:"action"`
+	ResType  SpecChangeResType   `json:"res_type"`
+	Protocol enumor.ProtocolType `json:"protocol"`
+	Port     int64               `json:"port"`
+	Domain   string              `json:"domain,omitempty"`
+	Url      string              `json:"url,omitempty"`
+	// Fields 修改时发生变化的字段
+	Fields []string `json:"fields,omitempty"`
+
+	ListenerID    string `json:"listener_id,omitempty"`
+	RuleID        string `json:"rule_id,omitempty"`
+	TargetID      string `json:"target_id,omitempty"`
+	TargetGroupID string `json:"target_group_id,omitempty"`
+
+	Listener *TCloudListenerSpec `json:"listener,omitempty"`
+	Rule     *TCloudRuleSpec     `json:"rule,omitempty"`
+	Target   *TCloudTargetSpec   `json:"target,omitempty"`
+	// OldWeight 修改RS权重时的原权重
+	OldWeight *int64 `json:"old_weight,omitempty"`
+}
+
+// Phase 变更的执行阶段，先删后增：RS解绑、规则删除、监听器删除、监听器修改、监听器新增、规则修改、规则新增、RS权重修改、RS绑定
+func (c TCloudSpecChange) Phase() int {
+	switch c.ResType {
+	case SpecResTarget:
+		switch c.Action {
+		case SpecChangeDelete:
+			return 0
+		case SpecChangeUpdate:
+			return 7
+		default:
+			return 8
+		}
+	case SpecResUrlRule:
+		switch c.Action {
+		case SpecChangeDelete:
+			return 1
+		case SpecChangeUpdate:
+			return 5
+		default:
+			return 6
+		}
+	default:
+		switch c.Action {
+		case SpecChangeDelete:
+			return 2
+		case SpecChangeUpdate:
+			return 3
+		default:
+			return 4
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"hcm/pkg/criteria/enumor"
+)
+
+// TrafficShiftPlan 目标组渐进式流量切换计划，创建后不再变化
+type TrafficShiftPlan struct {
+	Vendor        enumor.Vendor   `json:"vendor"`
+	AccountID     string          `json:"account_id"`
+	Region        string          `json:"region"`
+	LbID          string          `json:"lb_id"`
+	CloudLbID     string          `json:"cloud_lb_id"`
+	TargetGroupID string          `json:"target_group_id"`
+	CloudLblID    string          `json:"cloud_lbl_id"`
+	CloudRuleID   string          `json:"cloud_rule_id"`
+	RuleType      enumor.RuleType `json:"rule_type"`
+	// Steps 切换总步数，为1时即蓝绿发布的一次性切换
+	Steps int `json:"steps"`
+	// StepIntervalSec 相邻两步之间的观察时间
+	StepIntervalSec int64 `json:"step_interval_sec"`
+	// MaxUnhealthyPercent 新RS中不健康RS占比超过该值时自动回滚
+	MaxUnhealthyPercent int64                `json:"max_unhealthy_percent"`
+	Targets             []TrafficShiftTarget `json:"targets"`
+}
+
+// TrafficShiftTarget 参与流量切换的RS
+type TrafficShiftTarget struct {
+	ID          string                        `json:"id"`
+	Role        enumor.TrafficShiftTargetRole `json:"role"`
+	InstType    enumor.InstType               `json:"inst_type"`
+	CloudInstID string                        `json:"cloud_inst_id"`
+	IP          string                        `json:"ip"`
+	Port        int64                         `json:"port"`
+	FromWeight  int64                         `json:"from_weight"`
+	ToWeight    int64                         `json:"to_weight"`
+}
+
+// WeightAt 第step步完成后RS的权重，按步数线性插值，第0步为切换前的权重
+func (t TrafficShiftTarget) WeightAt(step, steps int) int64 {
+	if step <= 0 || steps <= 0 {
+		return t.FromWeight
+	}
+	if step >= steps {
+		return t.ToWeight
+	}
+	return t.FromWeight + (t.ToWeight-t.FromWeight)*int64(step)/int64(steps)
+}
+
+// TrafficShiftProgress 流量切换进度，由异步任务维护
+type TrafficShiftProgress struct {
+	// Step 已完成的步数
+	Step   int                      `json:"step"`
+	State  enumor.TrafficShiftState `json:"state"`
+	Reason string                   `json:"reason,omitempty"`
+}
+
+// TrafficShiftStepParam 流量切换每一步对应的任务详情参数
+type TrafficShiftStepParam struct {
+	Step    int                        `json:"step"`
+	Targets []TrafficShiftTargetWeight `json:"targets"`
+}
+
+// TrafficShiftTargetWeight 某一步完成后RS的权重
+type TrafficShiftTargetWeight struct {
+	ID     string                        `json:"id"`
+	Role   enumor.TrafficShiftTargetRole `json:"role"`
+	IP     string                        `json:"ip"`
+	Port   int64                         `json:"port"`
+	Weight int64                         `json:"weight"`
+}
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package reachability ...
+package reachability
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// Endpoint 网络可达性分析的源端或目的端
+type Endpoint struct {
+	Type enumor.ReachabilityEndpointType `json:"type" validate:"required"`
+	// ID 主机ID或负载均衡监听器ID
+	ID string `json:"id,omitempty"`
+	// IP 端点类型为 ip 时的IP地址
+	IP string `json:"ip,omitempty"`
+	// VpcID IP地址所属的VPC，未指定时仅按照已同步主机的IP进行匹配
+	VpcID string `json:"vpc_id,omitempty"`
+}
+
+// Validate Endpoint.
+func (e Endpoint) Validate() error {
+	if err := e.Type.Validate(); err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case enumor.CvmEndpoint, enumor.ClbListenerEndpoint:
+		if len(e.ID) == 0 {
+			return fmt.Errorf("id is required for %s endpoint", e.Type)
+		}
+		if len(e.IP) != 0 || len(e.VpcID) != 0 {
+			return fmt.Errorf("ip and vpc_id are not allowed for %s endpoint", e.Type)
+		}
+	case enumor.IPEndpoint:
+		if ip := net.ParseIP(e.IP); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("ip %s is not a valid ipv4 address", e.IP)
+		}
+		if len(e.ID) != 0 {
+			return errors.New("id is not allowed for ip endpoint")
+		}
+	}
+
+	return nil
+}
+
+// ResolvedEndpoint 解析后的端点信息
+type ResolvedEndpoint struct {
+	Type enumor.ReachabilityEndpointType `json:"type"`
+	// ResType 端点对应的资源类型，公网地址及未匹配到资源的IP为空
+	ResType       enumor.CloudResourceType `json:"res_type,omitempty"`
+	ResID         string                   `json:"res_id,omitempty"`
+	Vendor        enumor.Vendor            `json:"vendor,omitempty"`
+	VpcID         string                   `json:"vpc_id,omitempty"`
+	CloudVpcID    string                   `json:"cloud_vpc_id,omitempty"`
+	SubnetID      string                   `json:"subnet_id,omitempty"`
+	CloudSubnetID string                   `json:"cloud_subnet_id,omitempty"`
+	PrivateIPs    []string                 `json:"private_ips"`
+	PublicIPs     []string                 `json:"public_ips"`
+	// SecurityGroupIDs 端点绑定的安全组，按照安全组优先级排列
+	SecurityGroupIDs []string `json:"security_group_ids"`
+}
+
+// Hop 网络路径上的一个检查环节及其结论
+type Hop struct {
+	Type    enumor.ReachabilityHopType `json:"type"`
+	Allowed bool                       `json:"allowed"`
+	// ResType 决定该环节结论的资源类型，安全组或路由表
+	ResType enumor.CloudResourceType `json:"res_type,omitempty"`
+	ResID   string                   `json:"res_id,omitempty"`
+	// RuleID 命中的安全组规则ID或路由ID
+	RuleID string `json:"rule_id,omitempty"`
+	// Address 该环节匹配的对端地址
+	Address string `json:"address,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result 网络可达性分析结果
+type Result struct {
+	Allowed     bool             `json:"allowed"`
+	Protocol    string           `json:"protocol"`
+	Port        uint32           `json:"port"`
+	Source      ResolvedEndpoint `json:"source"`
+	Destination ResolvedEndpoint `json:"destination"`
+	// DecidedBy 决定分析结论的环节，拒绝时为第一个拒绝的环节，放通时为最后一个环节
+	DecidedBy *Hop  `json:"decided_by"`
+	Hops      []Hop `json:"hops"`
+}
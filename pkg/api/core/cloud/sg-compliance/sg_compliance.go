@@ -0,0 +1,153 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sgcompliance ...
+package sgcompliance
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// Check 用户自定义的安全组规则合规检查项
+type Check struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// BkBizID 检查项生效的业务，-1 表示对所有业务生效
+	BkBizID        int64                       `json:"bk_biz_id"`
+	Severity       enumor.SGComplianceSeverity `json:"severity"`
+	Enabled        bool                        `json:"enabled"`
+	Conditions     CheckCondition              `json:"conditions"`
+	Memo           *string                     `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// CheckCondition 自定义检查项的命中条件，只检查放通的规则，设置的条件需全部满足才视为违规
+type CheckCondition struct {
+	RuleTypes []enumor.SecurityGroupRuleType `json:"rule_types,omitempty" validate:"omitempty,max=2"`
+	Vendors   []enumor.Vendor                `json:"vendors,omitempty" validate:"omitempty,max=10"`
+	// Protocols 协议，如 tcp、udp、icmp，all 表示全部协议
+	Protocols []string `json:"protocols,omitempty" validate:"omitempty,max=10"`
+	// CIDRs 规则地址与其中任一网段有交集时命中
+	CIDRs []string `json:"cidrs,omitempty" validate:"omitempty,max=50"`
+	// AnyAddress 为 true 时只命中对全部地址(0.0.0.0/0、::/0)开放的规则
+	AnyAddress bool `json:"any_address,omitempty"`
+	// PortRanges 规则端口与其中任一端口范围有交集时命中
+	PortRanges []PortRange `json:"port_ranges,omitempty" validate:"omitempty,max=50"`
+}
+
+// Validate ...
+func (c CheckCondition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	for _, ruleType := range c.RuleTypes {
+		if ruleType != enumor.Ingress && ruleType != enumor.Egress {
+			return fmt.Errorf("unsupported rule type: %s", ruleType)
+		}
+	}
+
+	for _, vendor := range c.Vendors {
+		if err := vendor.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, cidr := range c.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil && net.ParseIP(cidr) == nil {
+			return fmt.Errorf("invalid cidr: %s", cidr)
+		}
+	}
+
+	for _, one := range c.PortRanges {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Protocols) == 0 && len(c.CIDRs) == 0 && !c.AnyAddress && len(c.PortRanges) == 0 {
+		return errors.New("at least one of protocols, cidrs, any_address and port_ranges is required")
+	}
+
+	return nil
+}
+
+// PortRange 端口范围，From 与 To 相等时表示单个端口
+type PortRange struct {
+	From uint32 `json:"from"`
+	To   uint32 `json:"to"`
+}
+
+// Validate ...
+func (p PortRange) Validate() error {
+	if p.From > p.To || p.To > 65535 {
+		return fmt.Errorf("invalid port range: %d-%d", p.From, p.To)
+	}
+
+	return nil
+}
+
+// Contains 端口范围是否包含另一个端口范围
+func (p PortRange) Contains(other PortRange) bool {
+	return p.From <= other.From && other.To <= p.To
+}
+
+// Overlaps 端口范围是否与另一个端口范围有交集
+func (p PortRange) Overlaps(other PortRange) bool {
+	return p.From <= other.To && other.From <= p.To
+}
+
+// Size 端口范围包含的端口数量
+func (p PortRange) Size() uint32 {
+	return p.To - p.From + 1
+}
+
+// String ...
+func (p PortRange) String() string {
+	if p.From == p.To {
+		return fmt.Sprintf("%d", p.From)
+	}
+	return fmt.Sprintf("%d-%d", p.From, p.To)
+}
+
+// Finding 安全组规则合规检查发现的问题
+type Finding struct {
+	// CheckID 内置检查项为检查项名称，自定义检查项为检查项ID
+	CheckID   string                      `json:"check_id"`
+	CheckName string                      `json:"check_name"`
+	Builtin   bool                        `json:"builtin"`
+	Severity  enumor.SGComplianceSeverity `json:"severity"`
+	Vendor    enumor.Vendor               `json:"vendor"`
+	// SecurityGroupID 规则所属安全组ID，gcp 防火墙规则为空
+	SecurityGroupID string `json:"security_group_id,omitempty"`
+	// CloudVpcID gcp 防火墙规则所属VPC的云ID，其他云厂商为空
+	CloudVpcID string                       `json:"cloud_vpc_id,omitempty"`
+	RuleType   enumor.SecurityGroupRuleType `json:"rule_type"`
+	// RuleID 违规的规则ID，待创建的规则为空
+	RuleID string `json:"rule_id"`
+	// RelatedRuleID 重复、覆盖类问题中相关联的规则ID
+	RelatedRuleID string `json:"related_rule_id,omitempty"`
+	Message       string `json:"message"`
+}
@@ -0,0 +1,188 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+)
+
+// Policy 回收站保留策略，按业务、资源类型设置资源在回收站中的保留时长、允许销毁的时间窗口和销毁前通知时间，
+// BkBizID 为 -1 的策略对所有业务生效，业务下存在同类型的策略时以业务下的策略为准
+type Policy struct {
+	ID      string                   `json:"id"`
+	BkBizID int64                    `json:"bk_biz_id"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	// RetentionHours 资源在回收站中的保留时长，单位小时
+	RetentionHours uint `json:"retention_hours"`
+	// NotifyBeforeHours 销毁前多少小时通知回收人，0表示不通知
+	NotifyBeforeHours uint `json:"notify_before_hours"`
+	// TimeZone 销毁时间窗口使用的时区，为空时使用服务所在时区
+	TimeZone string `json:"time_zone"`
+	// PurgeWindows 允许销毁资源的时间窗口，为空表示不限制
+	PurgeWindows   []PurgeWindow `json:"purge_windows"`
+	Memo           *string       `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// InPurgeWindow 指定时间是否处于策略允许销毁资源的时间窗口内
+func (p *Policy) InPurgeWindow(t time.Time) (bool, error) {
+	if len(p.PurgeWindows) == 0 {
+		return true, nil
+	}
+
+	if len(p.TimeZone) != 0 {
+		loc, err := time.LoadLocation(p.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("invalid time_zone: %s, err: %v", p.TimeZone, err)
+		}
+		t = t.In(loc)
+	}
+
+	for _, window := range p.PurgeWindows {
+		if window.Contains(t) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// MatchPolicy 获取业务下资源适用的回收站保留策略，优先使用业务下的策略，其次使用对所有业务生效的策略，不存在时返回nil
+func MatchPolicy(policies []Policy, bizID int64, resType enumor.CloudResourceType) *Policy {
+	var matched *Policy
+	for idx := range policies {
+		policy := &policies[idx]
+		if policy.ResType != resType {
+			continue
+		}
+
+		if policy.BkBizID == bizID {
+			return policy
+		}
+
+		if policy.BkBizID == constant.UnassignedBiz {
+			matched = policy
+		}
+	}
+
+	return matched
+}
+
+// ValidatePurgeWindows 校验销毁时间窗口及窗口使用的时区
+func ValidatePurgeWindows(timeZone string, windows []PurgeWindow) error {
+	if len(timeZone) != 0 {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return fmt.Errorf("invalid time_zone: %s, err: %v", timeZone, err)
+		}
+	}
+
+	for _, window := range windows {
+		if err := window.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeWindow 允许销毁回收站资源的时间窗口，开始时间晚于结束时间时表示窗口跨越零点，开始时间等于结束时间时表示全天
+type PurgeWindow struct {
+	// Weekdays 窗口生效的星期，0表示周日，为空表示每天生效，跨越零点的窗口以开始时间所在的星期为准
+	Weekdays []int `json:"weekdays"`
+	// Start 开始时间，格式为 HH:MM
+	Start string `json:"start"`
+	// End 结束时间，格式为 HH:MM
+	End string `json:"end"`
+}
+
+// Validate PurgeWindow.
+func (w PurgeWindow) Validate() error {
+	for _, weekday := range w.Weekdays {
+		if weekday < 0 || weekday > 6 {
+			return fmt.Errorf("invalid weekday: %d, should be in [0, 6]", weekday)
+		}
+	}
+
+	if _, err := parseClock(w.Start); err != nil {
+		return err
+	}
+
+	if _, err := parseClock(w.End); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Contains 指定时间是否处于时间窗口内，t 需要已经转换为窗口所用的时区
+func (w PurgeWindow) Contains(t time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	weekday := int(t.Weekday())
+	switch {
+	case start == end:
+	case start < end:
+		if minute < start || minute >= end {
+			return false
+		}
+	case minute >= start:
+	case minute < end:
+		// 跨越零点窗口的后半段，属于前一天开始的窗口
+		weekday = (weekday + 6) % 7
+	default:
+		return false
+	}
+
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, one := range w.Weekdays {
+		if one == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseClock 解析 HH:MM 格式的时间，返回距离零点的分钟数
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock: %s, should be HH:MM format", clock)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurgeWindow_Contains(t *testing.T) {
+	// 2026-10-17 为周六
+	saturday := func(clock string) time.Time {
+		tm, _ := time.Parse("2006-01-02 15:04", "2026-10-17 "+clock)
+		return tm
+	}
+
+	window := PurgeWindow{Weekdays: []int{6}, Start: "02:00", End: "06:00"}
+	assert.NoError(t, window.Validate())
+	assert.True(t, window.Contains(saturday("02:00")))
+	assert.True(t, window.Contains(saturday("05:59")))
+	assert.False(t, window.Contains(saturday("06:00")))
+	assert.False(t, window.Contains(saturday("01:59")))
+	assert.False(t, window.Contains(saturday("03:00").AddDate(0, 0, 1)))
+
+	// 跨越零点的窗口以开始时间所在的星期为准
+	overnight := PurgeWindow{Weekdays: []int{5}, Start: "22:00", End: "04:00"}
+	assert.True(t, overnight.Contains(saturday("03:00")))
+	assert.False(t, overnight.Contains(saturday("23:00")))
+	assert.True(t, overnight.Contains(saturday("23:00").AddDate(0, 0, -1)))
+	assert.False(t, overnight.Contains(saturday("12:00")))
+
+	// 开始时间等于结束时间表示全天
+	allDay := PurgeWindow{Start: "00:00", End: "00:00"}
+	assert.True(t, allDay.Contains(saturday("12:34")))
+
+	assert.Error(t, PurgeWindow{Start: "25:00", End: "01:00"}.Validate())
+	assert.Error(t, PurgeWindow{Weekdays: []int{7}, Start: "01:00", End: "02:00"}.Validate())
+}
+
+func TestPolicy_InPurgeWindow(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2026-10-17T19:00:00Z")
+
+	policy := &Policy{}
+	inWindow, err := policy.InPurgeWindow(now)
+	assert.NoError(t, err)
+	assert.True(t, inWindow)
+
+	// UTC 19:00 为北京时间次日 03:00
+	policy = &Policy{TimeZone: "Asia/Shanghai", PurgeWindows: []PurgeWindow{{Weekdays: []int{0}, Start: "02:00",
+		End: "04:00"}}}
+	inWindow, err = policy.InPurgeWindow(now)
+	assert.NoError(t, err)
+	assert.True(t, inWindow)
+
+	policy.TimeZone = "UTC"
+	inWindow, err = policy.InPurgeWindow(now)
+	assert.NoError(t, err)
+	assert.False(t, inWindow)
+
+	policy.TimeZone = "Invalid/Zone"
+	_, err = policy.InPurgeWindow(now)
+	assert.Error(t, err)
+}
+
+func TestMatchPolicy(t *testing.T) {
+	policies := []Policy{
+		{ID: "1", BkBizID: -1, ResType: enumor.CvmCloudResType, RetentionHours: 48},
+		{ID: "2", BkBizID: 100, ResType: enumor.CvmCloudResType, RetentionHours: 24},
+		{ID: "3", BkBizID: 100, ResType: enumor.EipCloudResType, RetentionHours: 12},
+	}
+
+	assert.Equal(t, "2", MatchPolicy(policies, 100, enumor.CvmCloudResType).ID)
+	assert.Equal(t, "1", MatchPolicy(policies, 200, enumor.CvmCloudResType).ID)
+	assert.Equal(t, "3", MatchPolicy(policies, 100, enumor.EipCloudResType).ID)
+	assert.Nil(t, MatchPolicy(policies, 200, enumor.EipCloudResType))
+}
@@ -38,18 +38,20 @@ type RecycleRecordT[T any] struct {
 
 // BaseRecycleRecord defines recycle record basic info.
 type BaseRecycleRecord struct {
-	ID            string                     `json:"id"`
-	TaskID        string                     `json:"task_id"`
-	RecycleType   enumor.RecycleType         `json:"recycle_type"`
-	Vendor        enumor.Vendor              `json:"vendor"`
-	ResType       enumor.CloudResourceType   `json:"res_type"`
-	ResID         string                     `json:"res_id"`
-	CloudResID    string                     `json:"cloud_res_id"`
-	ResName       string                     `json:"res_name"`
-	BkBizID       int64                      `json:"bk_biz_id"`
-	AccountID     string                     `json:"account_id"`
-	Region        string                     `json:"region"`
-	Status        enumor.RecycleRecordStatus `json:"status"`
-	RecycledAt    string                     `json:"recycled_at"`
+	ID          string                     `json:"id"`
+	TaskID      string                     `json:"task_id"`
+	RecycleType enumor.RecycleType         `json:"recycle_type"`
+	Vendor      enumor.Vendor              `json:"vendor"`
+	ResType     enumor.CloudResourceType   `json:"res_type"`
+	ResID       string                     `json:"res_id"`
+	CloudResID  string                     `json:"cloud_res_id"`
+	ResName     string                     `json:"res_name"`
+	BkBizID     int64                      `json:"bk_biz_id"`
+	AccountID   string                     `json:"account_id"`
+	Region      string                     `json:"region"`
+	Status      enumor.RecycleRecordStatus `json:"status"`
+	RecycledAt  string                     `json:"recycled_at"`
+	// Notified 是否已发送销毁前通知
+	Notified      bool `json:"notified"`
 	core.Revision `json:",inline"`
 }
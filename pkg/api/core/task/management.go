@@ -21,6 +21,7 @@ package task
 
 import (
 	"hcm/pkg/api/core"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
 	dataproto "hcm/pkg/api/data-service/cloud"
 	"hcm/pkg/criteria/enumor"
 )
@@ -45,4 +46,9 @@ type ManagementExt struct {
 	RegionIDs     []string                              `json:"region_ids"`
 	LblTargetsReq *dataproto.ListListenerWithTargetsReq `json:"lbl_targets_req"`
 	LblDeleteReq  *dataproto.BatchDeleteListenerReq     `json:"lbl_delete_req"`
+
+	// 目标组渐进式流量切换，计划、控制指令和进度分开存放，避免并发更新时互相覆盖
+	TrafficShift         *corelb.TrafficShiftPlan     `json:"traffic_shift,omitempty"`
+	TrafficShiftControl  enumor.TrafficShiftControl   `json:"traffic_shift_control,omitempty"`
+	TrafficShiftProgress *corelb.TrafficShiftProgress `json:"traffic_shift_progress,omitempty"`
 }
@@ -35,7 +35,8 @@ import (
 type MainAccountExtensionCreateReq interface {
 	AwsMainAccountExtensionCreateReq | GcpMainAccountExtensionCreateReq |
 		AzureMainAccountExtensionCreateReq | HuaWeiMainAccountExtensionCreateReq |
-		ZenlayerMainAccountExtensionCreateReq | KaopuMainAccountExtensionCreateReq
+		ZenlayerMainAccountExtensionCreateReq | KaopuMainAccountExtensionCreateReq |
+		TCloudMainAccountExtensionCreateReq
 }
 
 // AwsMainAccountExtensionCreateReq ...
@@ -86,6 +87,18 @@ func (req *HuaWeiMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptogr
 	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
 }
 
+// TCloudMainAccountExtensionCreateReq ...
+type TCloudMainAccountExtensionCreateReq struct {
+	CloudMainAccountID   string `json:"cloud_main_account_id"`
+	CloudMainAccountName string `json:"cloud_main_account_name"`
+	CloudInitPassword    string `json:"cloud_init_password"`
+}
+
+// EncryptSecretKey ...
+func (req *TCloudMainAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+	req.CloudInitPassword = cipher.EncryptToBase64(req.CloudInitPassword)
+}
+
 // ZenlayerMainAccountExtensionCreateReq ...
 type ZenlayerMainAccountExtensionCreateReq struct {
 	CloudMainAccountID   string `json:"cloud_main_account_id"`
@@ -168,7 +181,8 @@ type MainAccountGetBaseResp struct {
 type MainAccountExtensionGetResp interface {
 	protocore.AwsMainAccountExtension | protocore.GcpMainAccountExtension |
 		protocore.HuaWeiMainAccountExtension | protocore.AzureMainAccountExtension |
-		protocore.ZenlayerMainAccountExtension | protocore.KaopuMainAccountExtension
+		protocore.ZenlayerMainAccountExtension | protocore.KaopuMainAccountExtension |
+		protocore.TCloudMainAccountExtension
 }
 
 // MainAccountGetResult defines get main account result.
@@ -35,7 +35,8 @@ import (
 type RootAccountExtensionCreateReq interface {
 	AwsRootAccountExtensionCreateReq | GcpRootAccountExtensionCreateReq |
 		AzureRootAccountExtensionCreateReq | HuaWeiRootAccountExtensionCreateReq |
-		ZenlayerRootAccountExtensionCreateReq | KaopuRootAccountExtensionCreateReq
+		ZenlayerRootAccountExtensionCreateReq | KaopuRootAccountExtensionCreateReq |
+		TCloudRootAccountExtensionCreateReq
 }
 
 // AwsRootAccountExtensionCreateReq ...
@@ -118,6 +119,24 @@ func (req *HuaWeiRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptogr
 	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
 }
 
+// TCloudRootAccountExtensionCreateReq ...
+type TCloudRootAccountExtensionCreateReq struct {
+	CloudMainAccountID string `json:"cloud_main_account_id" validate:"required"`
+	CloudSubAccountID  string `json:"cloud_sub_account_id" validate:"required"`
+	CloudSecretID      string `json:"cloud_secret_id" validate:"required"`
+	CloudSecretKey     string `json:"cloud_secret_key" validate:"required"`
+}
+
+// Validate ...
+func (req *TCloudRootAccountExtensionCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// EncryptSecretKey ...
+func (req *TCloudRootAccountExtensionCreateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+	req.CloudSecretKey = cipher.EncryptToBase64(req.CloudSecretKey)
+}
+
 // ZenlayerRootAccountExtensionCreateReq ...
 type ZenlayerRootAccountExtensionCreateReq struct {
 	CloudAccountID string `json:"cloud_account_id" validate:"required"`
@@ -171,7 +190,8 @@ func (c *RootAccountCreateReq[T]) Validate() error {
 type RootAccountExtensionUpdateReq interface {
 	AwsRootAccountExtensionUpdateReq | GcpRootAccountExtensionUpdateReq |
 		HuaWeiRootAccountExtensionUpdateReq | AzureRootAccountExtensionUpdateReq |
-		ZenlayerRootAccountExtensionUpdateReq | KaopuRootAccountExtensionUpdateReq
+		ZenlayerRootAccountExtensionUpdateReq | KaopuRootAccountExtensionUpdateReq |
+		TCloudRootAccountExtensionUpdateReq
 }
 
 // AwsRootAccountExtensionUpdateReq ...
@@ -208,6 +228,22 @@ func (req *HuaWeiRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptogr
 	}
 }
 
+// TCloudRootAccountExtensionUpdateReq ...
+type TCloudRootAccountExtensionUpdateReq struct {
+	CloudMainAccountID string  `json:"cloud_main_account_id,omitempty" validate:"omitempty"`
+	CloudSubAccountID  string  `json:"cloud_sub_account_id,omitempty" validate:"omitempty"`
+	CloudSecretID      *string `json:"cloud_secret_id,omitempty" validate:"omitempty"`
+	CloudSecretKey     *string `json:"cloud_secret_key,omitempty" validate:"omitempty"`
+}
+
+// EncryptSecretKey ...
+func (req *TCloudRootAccountExtensionUpdateReq) EncryptSecretKey(cipher cryptography.Crypto) {
+	if req.CloudSecretKey != nil {
+		encryptedCloudSecretKey := cipher.EncryptToBase64(*req.CloudSecretKey)
+		req.CloudSecretKey = &encryptedCloudSecretKey
+	}
+}
+
 // GcpRootAccountExtensionUpdateReq ...
 type GcpRootAccountExtensionUpdateReq struct {
 	Email                   string  `json:"email" validate:"omitempty"`
@@ -299,7 +335,8 @@ type RootAccountGetBaseResp struct {
 type RootAccountExtensionGetResp interface {
 	protocore.AwsRootAccountExtension | protocore.GcpRootAccountExtension |
 		protocore.HuaWeiRootAccountExtension | protocore.AzureRootAccountExtension |
-		protocore.ZenlayerRootAccountExtension | protocore.KaopuRootAccountExtension
+		protocore.ZenlayerRootAccountExtension | protocore.KaopuRootAccountExtension |
+		protocore.TCloudRootAccountExtension
 }
 
 // RootAccountGetResult ...
@@ -314,6 +351,9 @@ type AwsRootAccount = RootAccountGetResult[protocore.AwsRootAccountExtension]
 // HuaweiRootAccount ...
 type HuaweiRootAccount = RootAccountGetResult[protocore.HuaWeiRootAccountExtension]
 
+// TCloudRootAccount ...
+type TCloudRootAccount = RootAccountGetResult[protocore.TCloudRootAccountExtension]
+
 // RootAccountGetResp ...
 type RootAccountGetResp[T RootAccountExtensionGetResp] struct {
 	rest.BaseResp `json:",inline"`
@@ -38,6 +38,8 @@ type ApplicationCreateReq struct {
 	Content        string                   `json:"content" validate:"required"`
 	DeliveryDetail string                   `json:"delivery_detail" validate:"required"`
 	Memo           *string                  `json:"memo" validate:"omitempty"`
+	// AutoApprovalPolicyID 匹配的自动审批策略ID，不为空时会记录自动审批的审计
+	AutoApprovalPolicyID string `json:"auto_approval_policy_id" validate:"omitempty,max=64"`
 }
 
 // Validate ...
@@ -68,7 +70,9 @@ type ApplicationResp struct {
 	Content        string                   `json:"content"`
 	DeliveryDetail string                   `json:"delivery_detail"`
 	Memo           *string                  `json:"memo"`
-	core.Revision  `json:",inline"`
+	// AutoApprovalPolicyID 匹配的自动审批策略ID，为空表示未自动审批
+	AutoApprovalPolicyID string `json:"auto_approval_policy_id"`
+	core.Revision        `json:",inline"`
 }
 
 // ApplicationGetResp ...
@@ -0,0 +1,120 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dataservice
+
+import (
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ApprovalChainCreateReq ...
+type ApprovalChainCreateReq struct {
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	BkBizID         int64                  `json:"bk_biz_id" validate:"required"`
+	Nodes           []coreapproval.Node    `json:"nodes" validate:"required"`
+	Memo            *string                `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.ApplicationType.Validate(); err != nil {
+		return err
+	}
+
+	return coreapproval.ValidateNodes(req.Nodes)
+}
+
+// ApprovalChainUpdateReq ...
+type ApprovalChainUpdateReq struct {
+	ID    string              `json:"id" validate:"required"`
+	Nodes []coreapproval.Node `json:"nodes" validate:"omitempty"`
+	Memo  *string             `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *ApprovalChainUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Nodes != nil {
+		return coreapproval.ValidateNodes(req.Nodes)
+	}
+
+	return nil
+}
+
+// ApprovalChainListResult ...
+type ApprovalChainListResult struct {
+	Count   uint64               `json:"count"`
+	Details []coreapproval.Chain `json:"details"`
+}
+
+// ApprovalTicketCreateReq ...
+type ApprovalTicketCreateReq struct {
+	ApplicationID   string                 `json:"application_id" validate:"required"`
+	ApplicationType enumor.ApplicationType `json:"application_type" validate:"required"`
+	Title           string                 `json:"title" validate:"max=255"`
+	Applicant       string                 `json:"applicant" validate:"required"`
+	ChainID         string                 `json:"chain_id" validate:"required"`
+	Nodes           []coreapproval.Node    `json:"nodes" validate:"required,min=1"`
+	Approvers       []string               `json:"approvers" validate:"required,min=1"`
+	RemindedAt      string                 `json:"reminded_at" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *ApprovalTicketCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ApprovalTicketUpdateReq 更新审批单，Version 不为空时只有审批单版本号与之相等才会更新，并将版本号加一
+type ApprovalTicketUpdateReq struct {
+	ID          string                      `json:"id" validate:"required"`
+	Version     *uint32                     `json:"version" validate:"omitempty"`
+	CurrentNode *uint32                     `json:"current_node" validate:"omitempty"`
+	Approvers   []string                    `json:"approvers" validate:"omitempty"`
+	Status      enumor.ApprovalTicketStatus `json:"status" validate:"omitempty"`
+	Records     []coreapproval.Record       `json:"records" validate:"omitempty"`
+	RemindedAt  string                      `json:"reminded_at" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *ApprovalTicketUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Status) != 0 {
+		return req.Status.Validate()
+	}
+
+	return nil
+}
+
+// ApprovalTicketListResult ...
+type ApprovalTicketListResult struct {
+	Count   uint64                `json:"count"`
+	Details []coreapproval.Ticket `json:"details"`
+}
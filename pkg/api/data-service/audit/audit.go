@@ -247,3 +247,28 @@ type GetAsyncTaskResp struct {
 	Flow  *coreasync.AsyncFlow      `json:"flow"`
 	Tasks []coreasync.AsyncFlowTask `json:"tasks"`
 }
+
+// -------------------------- Verify Audit Chain --------------------------
+
+// ChainVerifyReq defines verify audit hash chain request.
+type ChainVerifyReq struct {
+	// StartSeq 校验的起始序号，为 0 时从哈希链的第一条记录开始校验
+	StartSeq uint64 `json:"start_seq" validate:"omitempty"`
+	// EndSeq 校验的结束序号，为 0 时校验到哈希链的最新记录
+	EndSeq uint64 `json:"end_seq" validate:"omitempty"`
+}
+
+// Validate verify audit hash chain request.
+func (req *ChainVerifyReq) Validate() error {
+	if req.EndSeq != 0 && req.EndSeq < req.StartSeq {
+		return fmt.Errorf("end_seq should >= start_seq")
+	}
+
+	return nil
+}
+
+// ChainVerifyResp defines verify audit hash chain response.
+type ChainVerifyResp struct {
+	rest.BaseResp `json:",inline"`
+	Data          *audit.ChainVerifyResult `json:"data"`
+}
@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// BatchCreateAllocationRuleReq ...
+type BatchCreateAllocationRuleReq struct {
+	Rules []AllocationRuleCreate `json:"rules" validate:"required,min=1,max=100,dive,required"`
+}
+
+// Validate ...
+func (r *BatchCreateAllocationRuleReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	for i := range r.Rules {
+		if err := r.Rules[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllocationRuleCreate ...
+type AllocationRuleCreate struct {
+	Name     string                      `json:"name" validate:"required,max=255"`
+	Vendor   enumor.Vendor               `json:"vendor" validate:"required"`
+	Priority uint                        `json:"priority"`
+	Enabled  bool                        `json:"enabled"`
+	Matcher  bill.AllocationMatcher      `json:"matcher" validate:"required"`
+	Method   enumor.BillAllocationMethod `json:"method" validate:"required"`
+	Config   bill.AllocationConfig       `json:"config" validate:"required"`
+	Memo     *string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *AllocationRuleCreate) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if err := r.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Matcher.Validate(); err != nil {
+		return err
+	}
+
+	return r.Config.Validate(r.Method, r.Vendor)
+}
+
+// AllocationRuleUpdateReq ...
+type AllocationRuleUpdateReq struct {
+	ID       string                      `json:"id" validate:"required"`
+	Name     string                      `json:"name" validate:"omitempty,max=255"`
+	Priority *uint                       `json:"priority"`
+	Enabled  *bool                       `json:"enabled"`
+	Matcher  *bill.AllocationMatcher     `json:"matcher"`
+	Method   enumor.BillAllocationMethod `json:"method"`
+	Config   *bill.AllocationConfig      `json:"config"`
+	Memo     *string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *AllocationRuleUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.Matcher != nil {
+		if err := r.Matcher.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 分摊配置依赖分摊方式进行校验，所以二者需要同时更新
+	if (len(r.Method) == 0) != (r.Config == nil) {
+		return errors.New("method and config should be updated together")
+	}
+
+	return nil
+}
+
+// AllocationRuleListResult ...
+type AllocationRuleListResult = core.ListResultT[bill.AllocationRule]
+
+// AllocationResultReplaceReq replace allocation results of one main account bill day and version.
+type AllocationResultReplaceReq struct {
+	Vendor        enumor.Vendor            `json:"vendor" validate:"required"`
+	RootAccountID string                   `json:"root_account_id" validate:"required"`
+	MainAccountID string                   `json:"main_account_id" validate:"required"`
+	BillYear      int                      `json:"bill_year" validate:"required"`
+	BillMonth     int                      `json:"bill_month" validate:"required,min=1,max=12"`
+	BillDay       int                      `json:"bill_day" validate:"min=0,max=31"`
+	VersionID     int                      `json:"version_id" validate:"required"`
+	Results       []AllocationResultCreate `json:"results" validate:"omitempty,max=1000,dive,required"`
+}
+
+// Validate ...
+func (r *AllocationResultReplaceReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// AllocationResultCreate ...
+type AllocationResultCreate struct {
+	RuleID        string              `json:"rule_id" validate:"required"`
+	SourceBkBizID int64               `json:"source_bk_biz_id"`
+	BkBizID       int64               `json:"bk_biz_id" validate:"required"`
+	Currency      enumor.CurrencyCode `json:"currency" validate:"required"`
+	Cost          decimal.Decimal     `json:"cost"`
+	ItemCount     uint                `json:"item_count"`
+}
+
+// AllocationResultListResult ...
+type AllocationResultListResult = core.ListResultT[bill.AllocationResult]
@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	coredisksnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Disk Snapshot --------------------------
+
+// DiskSnapshotBatchCreateReq disk snapshot batch create request.
+type DiskSnapshotBatchCreateReq struct {
+	Snapshots []DiskSnapshotCreate `json:"snapshots" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (req *DiskSnapshotBatchCreateReq) Validate() error {
+	if len(req.Snapshots) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("snapshots count should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotCreate define disk snapshot create.
+type DiskSnapshotCreate struct {
+	Vendor           enumor.Vendor               `json:"vendor" validate:"required"`
+	AccountID        string                      `json:"account_id" validate:"required"`
+	CloudID          string                      `json:"cloud_id" validate:"required"`
+	BkBizID          int64                       `json:"bk_biz_id"`
+	Name             string                      `json:"name"`
+	Region           string                      `json:"region" validate:"required"`
+	Zone             string                      `json:"zone"`
+	DiskID           string                      `json:"disk_id"`
+	CloudDiskID      string                      `json:"cloud_disk_id"`
+	DiskSize         uint64                      `json:"disk_size"`
+	Status           string                      `json:"status"`
+	Encrypted        bool                        `json:"encrypted"`
+	PolicyID         string                      `json:"policy_id"`
+	Memo             *string                     `json:"memo"`
+	CloudCreatedTime string                      `json:"cloud_created_time"`
+	Extension        *coredisksnapshot.Extension `json:"extension"`
+}
+
+// DiskSnapshotBatchUpdateReq disk snapshot batch update request.
+type DiskSnapshotBatchUpdateReq struct {
+	Snapshots []DiskSnapshotUpdate `json:"snapshots" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (req *DiskSnapshotBatchUpdateReq) Validate() error {
+	if len(req.Snapshots) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("snapshots count should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotUpdate define disk snapshot update, only not empty field will be updated.
+type DiskSnapshotUpdate struct {
+	ID        string                      `json:"id" validate:"required"`
+	BkBizID   int64                       `json:"bk_biz_id"`
+	Name      string                      `json:"name"`
+	DiskID    *string                     `json:"disk_id"`
+	DiskSize  uint64                      `json:"disk_size"`
+	Status    string                      `json:"status"`
+	Encrypted *bool                       `json:"encrypted"`
+	Memo      *string                     `json:"memo"`
+	Extension *coredisksnapshot.Extension `json:"extension"`
+}
+
+// DiskSnapshotListResult define disk snapshot list result.
+type DiskSnapshotListResult struct {
+	Count   uint64                          `json:"count"`
+	Details []coredisksnapshot.DiskSnapshot `json:"details"`
+}
+
+// -------------------------- Disk Snapshot Policy --------------------------
+
+// DiskSnapshotPolicyCreateReq disk snapshot policy create request.
+type DiskSnapshotPolicyCreateReq struct {
+	Name           string        `json:"name" validate:"required,max=64"`
+	Vendor         enumor.Vendor `json:"vendor" validate:"required"`
+	AccountID      string        `json:"account_id" validate:"required"`
+	BkBizID        int64         `json:"bk_biz_id"`
+	DiskIDs        []string      `json:"disk_ids" validate:"required,min=1,max=100"`
+	CronExpr       string        `json:"cron_expr" validate:"required,max=64"`
+	TimeZone       string        `json:"time_zone" validate:"omitempty,max=64"`
+	RetentionCount uint32        `json:"retention_count"`
+	RetentionDays  uint32        `json:"retention_days"`
+	Enabled        *bool         `json:"enabled" validate:"required"`
+	Memo           *string       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *DiskSnapshotPolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.RetentionCount == 0 && req.RetentionDays == 0 {
+		return errors.New("one of retention_count and retention_days is required")
+	}
+
+	return nil
+}
+
+// DiskSnapshotPolicyUpdateReq disk snapshot policy update request, only not empty field will be updated.
+type DiskSnapshotPolicyUpdateReq struct {
+	ID             string   `json:"id" validate:"required"`
+	Name           string   `json:"name" validate:"omitempty,max=64"`
+	DiskIDs        []string `json:"disk_ids" validate:"omitempty,max=100"`
+	CronExpr       string   `json:"cron_expr" validate:"omitempty,max=64"`
+	TimeZone       *string  `json:"time_zone" validate:"omitempty,max=64"`
+	RetentionCount *uint32  `json:"retention_count"`
+	RetentionDays  *uint32  `json:"retention_days"`
+	Enabled        *bool    `json:"enabled"`
+	ScheduleID     string   `json:"schedule_id" validate:"omitempty,max=64"`
+	Memo           *string  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *DiskSnapshotPolicyUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// DiskSnapshotPolicyListResult define disk snapshot policy list result.
+type DiskSnapshotPolicyListResult struct {
+	Count   uint64                    `json:"count"`
+	Details []coredisksnapshot.Policy `json:"details"`
+}
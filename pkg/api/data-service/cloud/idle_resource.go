@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"fmt"
+
+	coreidle "hcm/pkg/api/core/cloud/idle-resource"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// IdleResourceBatchCreateReq idle resource batch create request.
+type IdleResourceBatchCreateReq struct {
+	Items []IdleResourceCreateReq `json:"items" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (req *IdleResourceBatchCreateReq) Validate() error {
+	if len(req.Items) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("items count should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Reason.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IdleResourceCreateReq idle resource create request.
+type IdleResourceCreateReq struct {
+	Vendor     enumor.Vendor             `json:"vendor" validate:"required"`
+	ResType    enumor.CloudResourceType  `json:"res_type" validate:"required"`
+	ResID      string                    `json:"res_id" validate:"required"`
+	CloudResID string                    `json:"cloud_res_id" validate:"required"`
+	ResName    string                    `json:"res_name"`
+	AccountID  string                    `json:"account_id" validate:"required"`
+	BkBizID    int64                     `json:"bk_biz_id"`
+	Region     string                    `json:"region"`
+	Reason     enumor.IdleResourceReason `json:"reason" validate:"required"`
+	Cost       decimal.Decimal           `json:"cost"`
+	Currency   enumor.CurrencyCode       `json:"currency"`
+}
+
+// IdleResourceBatchUpdateReq idle resource batch update request.
+type IdleResourceBatchUpdateReq struct {
+	Items []IdleResourceUpdateReq `json:"items" validate:"required,min=1,dive"`
+}
+
+// Validate ...
+func (req *IdleResourceBatchUpdateReq) Validate() error {
+	if len(req.Items) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("items count should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if len(item.Reason) == 0 {
+			continue
+		}
+		if err := item.Reason.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IdleResourceUpdateReq idle resource update request, only not empty field will be updated.
+type IdleResourceUpdateReq struct {
+	ID       string                    `json:"id" validate:"required"`
+	ResName  string                    `json:"res_name"`
+	BkBizID  int64                     `json:"bk_biz_id"`
+	Reason   enumor.IdleResourceReason `json:"reason"`
+	Cost     *decimal.Decimal          `json:"cost"`
+	Currency enumor.CurrencyCode       `json:"currency"`
+}
+
+// IdleResourceListResult define idle resource list result.
+type IdleResourceListResult struct {
+	Count   uint64                  `json:"count"`
+	Details []coreidle.IdleResource `json:"details"`
+}
@@ -45,6 +45,12 @@ type TCloudCLBCreateReq = LoadBalancerBatchCreateReq[corelb.TCloudClbExtension]
 // TCloudCLBCreate create load balancer
 type TCloudCLBCreate = LbBatchCreate[corelb.TCloudClbExtension]
 
+// AwsLBCreateReq batch create aws load balancer
+type AwsLBCreateReq = LoadBalancerBatchCreateReq[corelb.AwsLoadBalancerExtension]
+
+// AwsLBCreate create aws load balancer
+type AwsLBCreate = LbBatchCreate[corelb.AwsLoadBalancerExtension]
+
 // LbBatchCreate define load balancer batch create.
 type LbBatchCreate[Extension corelb.Extension] struct {
 	CloudID          string               `json:"cloud_id" validate:"required"`
@@ -143,6 +149,9 @@ func (req *LbExtBatchUpdateReq[T]) Validate() error {
 // TCloudClbBatchUpdateReq ...
 type TCloudClbBatchUpdateReq = LbExtBatchUpdateReq[corelb.TCloudClbExtension]
 
+// AwsLbBatchUpdateReq ...
+type AwsLbBatchUpdateReq = LbExtBatchUpdateReq[corelb.AwsLoadBalancerExtension]
+
 // BizBatchUpdateReq 批量更新业务id
 type BizBatchUpdateReq struct {
 	IDs     []string `json:"ids" validate:"required"`
@@ -199,6 +208,9 @@ type ListenerListResult = core.ListResultT[corelb.BaseListener]
 // TCloudListenerListResult ...
 type TCloudListenerListResult = core.ListResultT[corelb.Listener[corelb.TCloudListenerExtension]]
 
+// AwsListenerListResult ...
+type AwsListenerListResult = core.ListResultT[corelb.AwsListener]
+
 // -------------------------- List Count Listener By LbIDs --------------------------
 
 // ListListenerCountByLbIDsReq define list listener count by lbIDs req.
@@ -227,6 +239,9 @@ type ListListenerCountResult struct {
 // TCloudListenerDetailResult ...
 type TCloudListenerDetailResult = corelb.Listener[corelb.TCloudListenerExtension]
 
+// AwsListenerDetailResult ...
+type AwsListenerDetailResult = corelb.AwsListener
+
 // -------------------------- List Target --------------------------
 
 // TargetListResult define target list result.
@@ -718,3 +733,74 @@ type ListListenerQueryReq struct {
 	AccountID         string            `json:"account_id" validate:"required,min=1"`
 	ListenerQueryItem ListenerQueryItem `json:"rule_query_item" validate:"required"`
 }
+
+// -------------------------- Load Balancer Snapshot --------------------------
+
+// LbSnapshotCreateReq load balancer snapshot create req, version is generated by data service.
+type LbSnapshotCreateReq struct {
+	Vendor    enumor.Vendor                  `json:"vendor" validate:"required"`
+	LbID      string                         `json:"lb_id" validate:"required"`
+	AccountID string                         `json:"account_id" validate:"required"`
+	BkBizID   int64                          `json:"bk_biz_id"`
+	Source    enumor.LbSnapshotSource        `json:"source" validate:"required"`
+	FlowID    string                         `json:"flow_id" validate:"omitempty"`
+	Spec      *corelb.TCloudLoadBalancerSpec `json:"spec" validate:"required"`
+}
+
+// Validate validate load balancer snapshot create
+func (req *LbSnapshotCreateReq) Validate() error {
+	if err := req.Source.Validate(); err != nil {
+		return err
+	}
+	return validator.Validate.Struct(req)
+}
+
+// LbSnapshotUpdateReq load balancer snapshot update req.
+type LbSnapshotUpdateReq struct {
+	FlowID string `json:"flow_id" validate:"required"`
+}
+
+// Validate validate load balancer snapshot update
+func (req *LbSnapshotUpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// LbSnapshotListResult define load balancer snapshot list result.
+type LbSnapshotListResult = core.ListResultT[corelb.LoadBalancerSnapshot]
+
+// -------------------------- Target Health Event --------------------------
+
+// TargetHealthEventBatchCreateReq target health event batch create req.
+type TargetHealthEventBatchCreateReq struct {
+	Events []TargetHealthEventCreate `json:"events" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate validate target health event batch create
+func (req *TargetHealthEventBatchCreateReq) Validate() error {
+	for _, one := range req.Events {
+		if err := one.Status.Validate(); err != nil {
+			return err
+		}
+	}
+	return validator.Validate.Struct(req)
+}
+
+// TargetHealthEventCreate target health event create item.
+type TargetHealthEventCreate struct {
+	Vendor       enumor.Vendor             `json:"vendor" validate:"required"`
+	AccountID    string                    `json:"account_id" validate:"required"`
+	BkBizID      int64                     `json:"bk_biz_id"`
+	LbID         string                    `json:"lb_id" validate:"required"`
+	CloudLbID    string                    `json:"cloud_lb_id" validate:"required"`
+	CloudLblID   string                    `json:"cloud_lbl_id" validate:"required"`
+	CloudRuleID  string                    `json:"cloud_rule_id" validate:"omitempty"`
+	CloudInstID  string                    `json:"cloud_inst_id" validate:"omitempty"`
+	IP           string                    `json:"ip" validate:"required"`
+	Port         int64                     `json:"port"`
+	Status       enumor.TargetHealthStatus `json:"status" validate:"required"`
+	PrevStatus   enumor.TargetHealthStatus `json:"prev_status" validate:"omitempty"`
+	StatusDetail string                    `json:"status_detail" validate:"omitempty"`
+}
+
+// TargetHealthEventListResult define target health event list result.
+type TargetHealthEventListResult = core.ListResultT[corelb.TargetHealthEvent]
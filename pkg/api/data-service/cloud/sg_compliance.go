@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	coresgcompliance "hcm/pkg/api/core/cloud/sg-compliance"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SGComplianceCheckCreateReq security group rule compliance check create request.
+type SGComplianceCheckCreateReq struct {
+	Name       string                          `json:"name" validate:"required,max=64"`
+	BkBizID    int64                           `json:"bk_biz_id" validate:"required"`
+	Severity   enumor.SGComplianceSeverity     `json:"severity" validate:"required"`
+	Enabled    *bool                           `json:"enabled" validate:"required"`
+	Conditions coresgcompliance.CheckCondition `json:"conditions" validate:"required"`
+	Memo       *string                         `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *SGComplianceCheckCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Severity.Validate(); err != nil {
+		return err
+	}
+
+	return req.Conditions.Validate()
+}
+
+// SGComplianceCheckUpdateReq security group rule compliance check update request, only not empty field will be updated.
+type SGComplianceCheckUpdateReq struct {
+	ID         string                           `json:"id" validate:"required"`
+	Name       string                           `json:"name" validate:"omitempty,max=64"`
+	Severity   enumor.SGComplianceSeverity      `json:"severity" validate:"omitempty"`
+	Enabled    *bool                            `json:"enabled"`
+	Conditions *coresgcompliance.CheckCondition `json:"conditions"`
+	Memo       *string                          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (req *SGComplianceCheckUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Severity) != 0 {
+		if err := req.Severity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if req.Conditions != nil {
+		return req.Conditions.Validate()
+	}
+
+	return nil
+}
+
+// SGComplianceCheckListResult define security group rule compliance check list result.
+type SGComplianceCheckListResult struct {
+	Count   uint64                   `json:"count"`
+	Details []coresgcompliance.Check `json:"details"`
+}
@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"fmt"
+
+	rr "hcm/pkg/api/core/recycle-record"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Policy --------------------------
+
+// PolicyCreateReq defines create recycle policy request.
+type PolicyCreateReq struct {
+	BkBizID           int64                    `json:"bk_biz_id" validate:"required"`
+	ResType           enumor.CloudResourceType `json:"res_type" validate:"required"`
+	RetentionHours    uint                     `json:"retention_hours" validate:"required"`
+	NotifyBeforeHours uint                     `json:"notify_before_hours"`
+	TimeZone          string                   `json:"time_zone" validate:"omitempty,max=64"`
+	PurgeWindows      []rr.PurgeWindow         `json:"purge_windows" validate:"omitempty,max=20"`
+	Memo              *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate PolicyCreateReq.
+func (req *PolicyCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exists := enumor.RecyclableResTypes[req.ResType]; !exists {
+		return fmt.Errorf("res_type: %s does not support recycle", req.ResType)
+	}
+
+	return rr.ValidatePurgeWindows(req.TimeZone, req.PurgeWindows)
+}
+
+// PolicyUpdateReq defines update recycle policy request, only not empty field will be updated.
+type PolicyUpdateReq struct {
+	ID                string           `json:"id" validate:"required"`
+	RetentionHours    *uint            `json:"retention_hours" validate:"omitempty,min=1"`
+	NotifyBeforeHours *uint            `json:"notify_before_hours"`
+	TimeZone          *string          `json:"time_zone" validate:"omitempty,max=64"`
+	PurgeWindows      []rr.PurgeWindow `json:"purge_windows" validate:"omitempty,max=20"`
+	Memo              *string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate PolicyUpdateReq.
+func (req *PolicyUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	timeZone := ""
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+
+	return rr.ValidatePurgeWindows(timeZone, req.PurgeWindows)
+}
+
+// PolicyListResult defines list recycle policy result.
+type PolicyListResult struct {
+	Count   uint64      `json:"count"`
+	Details []rr.Policy `json:"details"`
+}
@@ -92,6 +92,8 @@ type UpdateReq struct {
 	ID     string                     `json:"id" validate:"required"`
 	Status enumor.RecycleRecordStatus `json:"status" validate:"omitempty"`
 	Detail interface{}                `json:"detail" validate:"omitempty"`
+	// Notified 是否已发送销毁前通知
+	Notified *bool `json:"notified" validate:"omitempty"`
 }
 
 // Validate BatchUpdateReq.
@@ -211,6 +211,37 @@ func (opt TCloudBillListReq) Validate() error {
 	return nil
 }
 
+// TCloudRootBillListReq define tcloud root account bill list req.
+type TCloudRootBillListReq struct {
+	RootAccountID      string `json:"root_account_id" validate:"required"`
+	MainAccountCloudID string `json:"main_account_cloud_id" validate:"required"`
+	// 月份，格式为yyyy-mm，不支持跨月查询
+	Month string `json:"month" validate:"required"`
+	// 起始日期，周期开始时间，格式为Y-m-d H:i:s，需要和Month在同一个月
+	BeginDate string `json:"begin_date" validate:"required"`
+	// 截止日期，周期结束时间，格式为Y-m-d H:i:s，需要和Month在同一个月
+	EndDate string `json:"end_date" validate:"required"`
+	// Limit: 最大值为100
+	Page *core.TCloudPage `json:"page" validate:"omitempty"`
+	// 上一次请求返回的上下文信息，用于加快分页查询速度
+	Context *string `json:"context" validate:"omitempty"`
+}
+
+// Validate tcloud root account bill list req.
+func (opt TCloudRootBillListReq) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.Page != nil {
+		if err := opt.Page.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // HuaWeiBillListReq defines huawei bill list req.
 type HuaWeiBillListReq struct {
 	AccountID string `json:"account_id" validate:"required"`
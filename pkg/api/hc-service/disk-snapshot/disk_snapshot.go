@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"errors"
+
+	typesdisksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq disk snapshot create request.
+type CreateReq struct {
+	DiskID string  `json:"disk_id" validate:"required"`
+	Name   string  `json:"name" validate:"required,max=60"`
+	Memo   *string `json:"memo" validate:"omitempty"`
+	// PolicyID 由快照策略创建时传入，用于按策略清理过期快照
+	PolicyID string `json:"policy_id" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *CreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SyncReq disk snapshot sync request.
+type SyncReq struct {
+	AccountID         string   `json:"account_id" validate:"required"`
+	Region            string   `json:"region" validate:"required"`
+	ResourceGroupName string   `json:"resource_group_name" validate:"omitempty"`
+	CloudIDs          []string `json:"cloud_ids" validate:"omitempty"`
+}
+
+// Validate ...
+func (req *SyncReq) Validate() error {
+	if len(req.CloudIDs) > typesdisksnapshot.MaxBatchSize {
+		return errors.New("cloud_ids count should <= 100")
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeleteReq disk snapshot batch delete request.
+type BatchDeleteReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate ...
+func (req *BatchDeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
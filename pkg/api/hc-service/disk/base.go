@@ -37,6 +37,8 @@ type DiskBaseCreateReq struct {
 	DiskType  string  `json:"disk_type" validate:"required"`
 	DiskCount uint32  `json:"disk_count" validate:"required"`
 	Memo      *string `json:"memo"`
+	// SnapshotID 快照ID，传入时基于该快照创建云盘
+	SnapshotID string `json:"snapshot_id"`
 }
 
 // DiskSyncReq disk sync request
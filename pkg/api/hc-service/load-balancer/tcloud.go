@@ -532,12 +532,27 @@ type TCloudTargetHealthLblResult struct {
 	ListenerName string                          `json:"listener_name"`
 	HealthCheck  *corelb.TCloudHealthCheckInfo   `json:"health_check"`
 	Rules        []*TCloudTargetHealthRuleResult `json:"rules"`
+	// Targets 四层监听器下各RS的健康状态
+	Targets []*TCloudTargetHealthRsResult `json:"targets,omitempty"`
 }
 
 // TCloudTargetHealthRuleResult ...
 type TCloudTargetHealthRuleResult struct {
 	CloudRuleID string                        `json:"cloud_rule_id"`
 	HealthCheck *corelb.TCloudHealthCheckInfo `json:"health_check"`
+	// Targets 七层规则下各RS的健康状态
+	Targets []*TCloudTargetHealthRsResult `json:"targets,omitempty"`
+}
+
+// TCloudTargetHealthRsResult RS的健康状态
+type TCloudTargetHealthRsResult struct {
+	// CloudInstID 云上RS的ID，弹性网卡类型的RS为IP
+	CloudInstID  string `json:"cloud_inst_id"`
+	IP           string `json:"ip"`
+	Port         int64  `json:"port"`
+	HealthStatus bool   `json:"health_status"`
+	// HealthStatusDetail 健康状态详情，如 Alive、Dead、Unknown、Close
+	HealthStatusDetail string `json:"health_status_detail"`
 }
 
 // QueryTCloudListenerTargets ...
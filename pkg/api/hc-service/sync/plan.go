@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package sync
+
+// PlanQueryKey 同步接口开启计划模式的查询参数，plan=true 时只返回同步差异，不修改db数据
+const PlanQueryKey = "plan"
+
+// SyncPlanResult 计划模式下同步接口返回的同步差异
+type SyncPlanResult struct {
+	// Resources 资源类型 -> 资源差异
+	Resources map[string]*ResourcePlan `json:"resources"`
+}
+
+// Merge 合并另一个同步计划的差异，用于汇总多个地域的同步计划
+func (r *SyncPlanResult) Merge(other *SyncPlanResult) {
+	if other == nil {
+		return
+	}
+
+	if r.Resources == nil {
+		r.Resources = make(map[string]*ResourcePlan, len(other.Resources))
+	}
+	for resource, plan := range other.Resources {
+		if plan == nil {
+			continue
+		}
+
+		merged, exists := r.Resources[resource]
+		if !exists {
+			merged = &ResourcePlan{Added: make([]string, 0), Updated: make([]UpdatedResource, 0),
+				Removed: make([]string, 0)}
+			r.Resources[resource] = merged
+		}
+		merged.Added = append(merged.Added, plan.Added...)
+		merged.Updated = append(merged.Updated, plan.Updated...)
+		merged.Removed = append(merged.Removed, plan.Removed...)
+	}
+}
+
+// ResourcePlan 单个资源类型的同步差异
+type ResourcePlan struct {
+	// Added 新增资源的云ID
+	Added []string `json:"added"`
+	// Updated 更新的资源及其变更字段
+	Updated []UpdatedResource `json:"updated"`
+	// Removed 删除资源的云ID
+	Removed []string `json:"removed"`
+}
+
+// UpdatedResource 更新的资源
+type UpdatedResource struct {
+	ID      string `json:"id"`
+	CloudID string `json:"cloud_id"`
+	// ChangedFields 云上与db中值不一致的字段，字段路径以 . 分隔
+	ChangedFields []string `json:"changed_fields"`
+}
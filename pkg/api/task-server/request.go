@@ -36,6 +36,10 @@ type AddTemplateFlowReq struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"required, min=1"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// Deadline 任务流截止时间，标准格式：2006-01-02T15:04:05Z，超过后取消任务流并回滚执行中的任务
+	Deadline string `json:"deadline" validate:"omitempty"`
+	// TimeoutSec 任务流从创建开始允许的最长运行时间，与Deadline同时设置时取较早者
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
 }
 
 // Validate AddTemplateFlowReq
@@ -79,6 +83,10 @@ type AddCustomFlowReq struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// Deadline 任务流截止时间，标准格式：2006-01-02T15:04:05Z，超过后取消任务流并回滚执行中的任务
+	Deadline string `json:"deadline" validate:"omitempty"`
+	// TimeoutSec 任务流从创建开始允许的最长运行时间，与Deadline同时设置时取较早者
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
 }
 
 // Validate AddCustomFlowReq
@@ -116,3 +124,75 @@ type CustomFlowTask struct {
 func (task *CustomFlowTask) Validate() error {
 	return validator.Validate.Struct(task)
 }
+
+// CreateFlowScheduleReq define create flow schedule request.
+type CreateFlowScheduleReq struct {
+	// Name 定时任务名称
+	Name string `json:"name" validate:"required,lte=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// CronExpr 5段式cron表达式
+	CronExpr string `json:"cron_expr" validate:"required,lte=64"`
+	// TimeZone cron表达式所在时区，为空时使用UTC
+	TimeZone string `json:"time_zone" validate:"omitempty,lte=64"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+	// Enabled 是否启用，默认启用
+	Enabled *bool `json:"enabled" validate:"omitempty"`
+	// MisfirePolicy 错过触发时间后的处理策略，默认fire_once
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	// Memo 备注
+	Memo *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreateFlowScheduleReq
+func (req *CreateFlowScheduleReq) Validate() error {
+
+	if err := req.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	if len(req.MisfirePolicy) != 0 {
+		if err := req.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range req.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// UpdateFlowScheduleReq define update flow schedule request.
+type UpdateFlowScheduleReq struct {
+	Name     string  `json:"name" validate:"omitempty,lte=64"`
+	CronExpr string  `json:"cron_expr" validate:"omitempty,lte=64"`
+	TimeZone *string `json:"time_zone" validate:"omitempty,lte=64"`
+	// Tasks 为空时不更新任务参数
+	Tasks         []TemplateFlowTask   `json:"tasks" validate:"omitempty"`
+	Enabled       *bool                `json:"enabled" validate:"omitempty"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	Memo          *string              `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate UpdateFlowScheduleReq
+func (req *UpdateFlowScheduleReq) Validate() error {
+
+	if len(req.MisfirePolicy) != 0 {
+		if err := req.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range req.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
@@ -19,7 +19,11 @@
 
 package taskserver
 
-import "hcm/pkg/api/core/async"
+import (
+	coreasync "hcm/pkg/api/core/async"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
 
 // ListFlowResult ...
 type ListFlowResult struct {
@@ -32,3 +36,49 @@ type ListTaskResult struct {
 	Count   uint64                    `json:"count"`
 	Details []coreasync.AsyncFlowTask `json:"details"`
 }
+
+// ListFlowScheduleResult ...
+type ListFlowScheduleResult struct {
+	Count   uint64                        `json:"count"`
+	Details []coreasync.AsyncFlowSchedule `json:"details"`
+}
+
+// FlowGraphResult 任务流的DAG视图
+type FlowGraphResult struct {
+	Flow  coreasync.AsyncFlow `json:"flow"`
+	Nodes []FlowGraphNode     `json:"nodes"`
+	Edges []FlowGraphEdge     `json:"edges"`
+	// CriticalPath 关键路径上的任务ID，按依赖顺序排列
+	CriticalPath []string `json:"critical_path"`
+	// CriticalPathSec 关键路径总耗时(秒)，未结束的任务使用预估耗时
+	CriticalPathSec float64 `json:"critical_path_sec"`
+	// EstimatedRemainingSec 未结束任务流的预估剩余执行时间(秒)，任务流已结束时为空
+	EstimatedRemainingSec *float64 `json:"estimated_remaining_sec,omitempty"`
+}
+
+// FlowGraphNode DAG中的任务节点
+type FlowGraphNode struct {
+	TaskID     string             `json:"task_id"`
+	ActionID   string             `json:"action_id"`
+	ActionName enumor.ActionName  `json:"action_name"`
+	State      enumor.TaskState   `json:"state"`
+	Reason     *tableasync.Reason `json:"reason,omitempty"`
+	// StartedAt 任务开始时间，由依赖任务的结束时间推算，未开始时为空
+	StartedAt string `json:"started_at,omitempty"`
+	// EndedAt 任务结束时间，未结束时为空
+	EndedAt string `json:"ended_at,omitempty"`
+	// ElapsedSec 任务已执行时间(秒)
+	ElapsedSec float64 `json:"elapsed_sec"`
+	// DurationSec 任务执行耗时(秒)，已结束的任务为实际耗时，否则为预估耗时
+	DurationSec float64 `json:"duration_sec"`
+	// Estimated DurationSec 是否为预估值
+	Estimated bool `json:"estimated"`
+	// OnCriticalPath 是否在关键路径上
+	OnCriticalPath bool `json:"on_critical_path"`
+}
+
+// FlowGraphEdge DAG中的依赖边，From 执行成功后才能执行 To
+type FlowGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
@@ -56,7 +56,7 @@ func NewAsync(bd backend.Backend, ld leader.Leader, opt *Option) (Async, error)
 		return nil, err
 	}
 
-	csm, err := consumer.NewConsumer(bd, ld, opt.Register, opt.ConsumerOption)
+	csm, err := consumer.NewConsumer(bd, ld, pdr, opt.Register, opt.ConsumerOption)
 	if err != nil {
 		logs.Errorf("new consumer failed, err: %v", err)
 		return nil, err
@@ -56,6 +56,20 @@ type Backend interface {
 
 	// RetryTask 重试任务 将flow置为running, task 置为pending
 	RetryTask(kt *kit.Kit, flowID, taskID string) error
+
+	/*
+		Schedule 相关接口
+	*/
+	// CreateSchedule 创建定时任务流
+	CreateSchedule(kt *kit.Kit, schedule *model.Schedule) (string, error)
+	// UpdateSchedule 更新定时任务流
+	UpdateSchedule(kt *kit.Kit, schedule *model.Schedule) error
+	// UpdateScheduleFireTimeByCAS CAS更新定时任务流下次触发时间
+	UpdateScheduleFireTimeByCAS(kt *kit.Kit, info *UpdateScheduleFireInfo) error
+	// ListSchedule 查询定时任务流
+	ListSchedule(kt *kit.Kit, input *ListInput) ([]model.Schedule, error)
+	// DeleteSchedule 删除定时任务流
+	DeleteSchedule(kt *kit.Kit, id string) error
 }
 
 // ListInput 查询输入参数
@@ -76,3 +90,11 @@ type UpdateTaskInfo typesasync.UpdateTaskInfo
 func (info *UpdateTaskInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
+
+// UpdateScheduleFireInfo define update schedule fire time info.
+type UpdateScheduleFireInfo typesasync.UpdateScheduleFireInfo
+
+// Validate UpdateScheduleFireInfo
+func (info *UpdateScheduleFireInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
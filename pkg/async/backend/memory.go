@@ -0,0 +1,669 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+)
+
+// NewMemory create memory backend instance, 数据只保存在进程内存中，服务重启后丢失。
+// 适用于单元测试中运行完整的异步任务流程，以及不依赖MySQL的单节点小规模部署。
+func NewMemory() Backend {
+	return &memory{
+		flows:     make(map[string]*model.Flow),
+		tasks:     make(map[string]*model.Task),
+		schedules: make(map[string]*model.Schedule),
+	}
+}
+
+// memory 内存后端存储，所有读写操作通过读写锁串行化，以此保证CAS更新及批量更新的原子性
+type memory struct {
+	lock sync.RWMutex
+
+	flowSeq     uint64
+	taskSeq     uint64
+	scheduleSeq uint64
+
+	// flows 任务流，不包含任务，任务单独保存在tasks中
+	flows     map[string]*model.Flow
+	tasks     map[string]*model.Task
+	schedules map[string]*model.Schedule
+}
+
+var _ Backend = new(memory)
+
+// TenantLister 可以提供租户ID列表的后端存储，consumer优先使用该接口获取需要处理的租户，
+// 以便在不依赖data-service的情况下运行。
+type TenantLister interface {
+	ListTenantIDs(kt *kit.Kit) ([]string, error)
+}
+
+var _ TenantLister = new(memory)
+
+// matchTenant 判断数据是否对kit所属租户可见，与mysql一致，没有租户信息的kit(如consumer内部使用的kit)不过滤租户
+func matchTenant(kt *kit.Kit, tenantID string) bool {
+	return len(kt.TenantID) == 0 || kt.TenantID == tenantID
+}
+
+// genID 生成与id_generator格式一致的8位36进制ID
+func genID(seq *uint64) string {
+	*seq++
+	return fmt.Sprintf("%08s", strconv.FormatUint(*seq, 36))
+}
+
+func now() string {
+	return time.Now().Format(constant.TimeStdFormat)
+}
+
+// CreateFlow 创建任务流
+func (m *memory) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+	if flow == nil {
+		return "", errors.New("flow is required")
+	}
+
+	if len(flow.Name) == 0 {
+		return "", errors.New("flow name is required")
+	}
+
+	flowState := enumor.FlowPending
+	if flow.State == enumor.FlowInit {
+		flowState = flow.State
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	createdAt := now()
+	md := &model.Flow{
+		ID:        genID(&m.flowSeq),
+		Name:      flow.Name,
+		State:     flowState,
+		Reason:    new(tableasync.Reason),
+		ShareData: cloneShareData(flow.ShareData),
+		Memo:      flow.Memo,
+		Worker:    converter.ValToPtr(""),
+		Deadline:  cloneStrPtr(flow.Deadline),
+		Creator:   kt.User,
+		Reviser:   kt.User,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		TenantID:  kt.TenantID,
+	}
+
+	for _, one := range flow.Tasks {
+		taskState := enumor.TaskPending
+		if one.State == enumor.TaskInit {
+			taskState = one.State
+		}
+
+		task := cloneTask(&one)
+		task.ID = genID(&m.taskSeq)
+		task.FlowID = md.ID
+		task.State = taskState
+		task.Reason = new(tableasync.Reason)
+		task.Result = ""
+		task.Creator = kt.User
+		task.Reviser = kt.User
+		task.CreatedAt = createdAt
+		task.UpdatedAt = createdAt
+		task.TenantID = kt.TenantID
+		m.tasks[task.ID] = task
+	}
+	m.flows[md.ID] = md
+
+	return md.ID, nil
+}
+
+// BatchUpdateFlow 批量更新任务流，只更新非空字段，其中worker允许更新为空
+func (m *memory) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	staged := make(map[string]*model.Flow, len(flows))
+	for _, one := range flows {
+		flow, err := m.stagedFlow(kt, staged, one.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(one.State) != 0 {
+			flow.State = one.State
+		}
+		if one.Reason != nil {
+			flow.Reason = cloneReason(one.Reason)
+		}
+		if one.ShareData != nil {
+			flow.ShareData = cloneShareData(one.ShareData)
+		}
+		if len(one.Memo) != 0 {
+			flow.Memo = one.Memo
+		}
+		if one.Worker != nil {
+			flow.Worker = cloneStrPtr(one.Worker)
+		}
+		if len(one.Reviser) != 0 {
+			flow.Reviser = one.Reviser
+		}
+		flow.UpdatedAt = now()
+	}
+
+	for id, flow := range staged {
+		m.flows[id] = flow
+	}
+
+	return nil
+}
+
+// ListFlow 查询任务流
+func (m *memory) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	records := make([]*model.Flow, 0)
+	for _, one := range m.flows {
+		if matchTenant(kt, one.TenantID) {
+			records = append(records, one)
+		}
+	}
+
+	list, err := listRecords(input, tableasync.AsyncFlowColumns.ColumnTypes(), records, flowColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.Flow, 0, len(list))
+	for _, one := range list {
+		flows = append(flows, *cloneFlow(one))
+	}
+
+	return flows, nil
+}
+
+// BatchUpdateFlowStateByCAS CAS批量更新Flow状态，任意一个任务流状态不匹配时全部不更新
+func (m *memory) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error {
+	for _, one := range infos {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	staged := make(map[string]*model.Flow, len(infos))
+	for _, one := range infos {
+		flow, err := m.stagedFlow(kt, staged, one.ID)
+		if err != nil || flow.State != one.Source {
+			return errf.Newf(errf.RecordNotUpdate, "flow[%s] update state: `%s`->`%s`, worker: %+v failed",
+				one.ID, one.Source, one.Target, one.Worker)
+		}
+
+		flow.State = one.Target
+		if one.Worker != nil {
+			flow.Worker = cloneStrPtr(one.Worker)
+		}
+		if one.Reason != nil {
+			flow.Reason = cloneReason(one.Reason)
+		}
+		flow.UpdatedAt = now()
+	}
+
+	for id, flow := range staged {
+		m.flows[id] = flow
+	}
+
+	return nil
+}
+
+// stagedFlow 获取任务流的待更新副本，同一批次内多次更新同一任务流时返回同一个副本
+func (m *memory) stagedFlow(kt *kit.Kit, staged map[string]*model.Flow, id string) (*model.Flow, error) {
+	if flow, exist := staged[id]; exist {
+		return flow, nil
+	}
+
+	flow, exist := m.flows[id]
+	if !exist || !matchTenant(kt, flow.TenantID) {
+		return nil, errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	staged[id] = cloneFlow(flow)
+	return staged[id], nil
+}
+
+// BatchCreateTask 批量创建任务
+func (m *memory) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, one := range tasks {
+		if len(one.FlowID) == 0 {
+			return nil, errors.New("task flow_id is required")
+		}
+	}
+
+	ids := make([]string, 0, len(tasks))
+	createdAt := now()
+	for _, one := range tasks {
+		task := cloneTask(&one)
+		task.ID = genID(&m.taskSeq)
+		task.State = enumor.TaskPending
+		task.CreatedAt = createdAt
+		task.UpdatedAt = createdAt
+		task.TenantID = kt.TenantID
+		m.tasks[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	return ids, nil
+}
+
+// UpdateTask 更新任务，只更新非空字段
+func (m *memory) UpdateTask(kt *kit.Kit, task *model.Task) error {
+	if task == nil || len(task.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	one, exist := m.tasks[task.ID]
+	if !exist || !matchTenant(kt, one.TenantID) {
+		return errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	updated := cloneTask(one)
+	if task.Retry != nil {
+		updated.Retry = cloneRetry(task.Retry)
+	}
+	if len(task.State) != 0 {
+		updated.State = task.State
+	}
+	if len(task.Result) != 0 {
+		updated.Result = task.Result
+	}
+	if task.Reason != nil {
+		updated.Reason = cloneReason(task.Reason)
+	}
+	updated.Reviser = kt.User
+	updated.UpdatedAt = now()
+	m.tasks[task.ID] = updated
+
+	return nil
+}
+
+// UpdateTaskStateByCAS CAS更新任务状态
+func (m *memory) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+	if info == nil {
+		return errors.New("update info is required")
+	}
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.updateTaskStateByCAS(kt, info)
+}
+
+func (m *memory) updateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+	task, exist := m.tasks[info.ID]
+	if !exist || !matchTenant(kt, task.TenantID) || task.State != info.Source {
+		return errf.Newf(errf.RecordNotUpdate, "task[%s: %s] update state to %s failed", info.ID, info.Source,
+			info.Target)
+	}
+
+	updated := cloneTask(task)
+	updated.State = info.Target
+	if info.Reason != nil {
+		updated.Reason = cloneReason(info.Reason)
+	}
+	updated.UpdatedAt = now()
+	m.tasks[info.ID] = updated
+
+	return nil
+}
+
+// ListTask 查询任务
+func (m *memory) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	records := make([]*model.Task, 0)
+	for _, one := range m.tasks {
+		if matchTenant(kt, one.TenantID) {
+			records = append(records, one)
+		}
+	}
+
+	list, err := listRecords(input, tableasync.AsyncFlowTaskColumns.ColumnTypes(), records, taskColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]model.Task, 0, len(list))
+	for _, one := range list {
+		tasks = append(tasks, *cloneTask(one))
+	}
+
+	return tasks, nil
+}
+
+// RetryTask 重试任务，将flow置为pending, task 置为pending
+func (m *memory) RetryTask(kt *kit.Kit, flowID, taskID string) error {
+	if len(flowID) == 0 || len(taskID) == 0 {
+		return errors.New("empty flow id or task id")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	flow, exist := m.flows[flowID]
+	if !exist || !matchTenant(kt, flow.TenantID) {
+		return fmt.Errorf("flow %s not found", flowID)
+	}
+	if flow.State != enumor.FlowFailed {
+		return fmt.Errorf("flow(%s) state(%s) wrong, only `failed` allowed for retry", flowID, flow.State)
+	}
+
+	task, exist := m.tasks[taskID]
+	if !exist || !matchTenant(kt, task.TenantID) || task.FlowID != flowID {
+		return fmt.Errorf("task(%s) of flow(%s) not found", taskID, flowID)
+	}
+	if task.State != enumor.TaskFailed {
+		return fmt.Errorf("task(%s) state(%s) wrong, only `failed` allowed for retry", taskID, task.State)
+	}
+
+	taskUpdate := &UpdateTaskInfo{
+		ID:     taskID,
+		Source: enumor.TaskFailed,
+		Target: enumor.TaskPending,
+		Reason: &tableasync.Reason{Message: "retry task " + taskID},
+	}
+	if err := m.updateTaskStateByCAS(kt, taskUpdate); err != nil {
+		return err
+	}
+
+	updated := cloneFlow(flow)
+	updated.State = enumor.FlowPending
+	updated.Reason = &tableasync.Reason{Message: "retry task " + taskID}
+	updated.UpdatedAt = now()
+	m.flows[flowID] = updated
+
+	return nil
+}
+
+// CreateSchedule 创建定时任务流
+func (m *memory) CreateSchedule(kt *kit.Kit, schedule *model.Schedule) (string, error) {
+	if schedule == nil {
+		return "", errors.New("schedule is required")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, one := range m.schedules {
+		if matchTenant(kt, one.TenantID) && one.Name == schedule.Name {
+			return "", errf.Newf(errf.RecordDuplicated, "schedule name %s already exists", schedule.Name)
+		}
+	}
+
+	md := cloneSchedule(schedule)
+	md.ID = genID(&m.scheduleSeq)
+	md.LastFireTime = ""
+	md.LastFlowID = ""
+	md.Creator = kt.User
+	md.Reviser = kt.User
+	md.CreatedAt = now()
+	md.UpdatedAt = md.CreatedAt
+	md.TenantID = kt.TenantID
+	m.schedules[md.ID] = md
+
+	return md.ID, nil
+}
+
+// UpdateSchedule 更新定时任务流，只更新非空字段
+func (m *memory) UpdateSchedule(kt *kit.Kit, schedule *model.Schedule) error {
+	if schedule == nil {
+		return errors.New("schedule is required")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	one, exist := m.schedules[schedule.ID]
+	if !exist || !matchTenant(kt, one.TenantID) {
+		return errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	updated := cloneSchedule(one)
+	if len(schedule.Name) != 0 {
+		updated.Name = schedule.Name
+	}
+	if len(schedule.CronExpr) != 0 {
+		updated.CronExpr = schedule.CronExpr
+	}
+	if len(schedule.TimeZone) != 0 {
+		updated.TimeZone = schedule.TimeZone
+	}
+	if len(schedule.Tasks) != 0 {
+		updated.Tasks = schedule.Tasks
+	}
+	if schedule.Enabled != nil {
+		updated.Enabled = converter.ValToPtr(*schedule.Enabled)
+	}
+	if len(schedule.MisfirePolicy) != 0 {
+		updated.MisfirePolicy = schedule.MisfirePolicy
+	}
+	if len(schedule.NextFireTime) != 0 {
+		updated.NextFireTime = schedule.NextFireTime
+	}
+	if schedule.Memo != nil {
+		updated.Memo = cloneStrPtr(schedule.Memo)
+	}
+	updated.Reviser = kt.User
+	updated.UpdatedAt = now()
+	m.schedules[schedule.ID] = updated
+
+	return nil
+}
+
+// UpdateScheduleFireTimeByCAS CAS更新定时任务流下次触发时间
+func (m *memory) UpdateScheduleFireTimeByCAS(kt *kit.Kit, info *UpdateScheduleFireInfo) error {
+	if info == nil {
+		return errors.New("update info is required")
+	}
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	one, exist := m.schedules[info.ID]
+	if !exist || !matchTenant(kt, one.TenantID) || one.NextFireTime != info.Source {
+		return errf.Newf(errf.RecordNotUpdate, "schedule[%s] update next fire time: `%s`->`%s` failed",
+			info.ID, info.Source, info.Target)
+	}
+
+	updated := cloneSchedule(one)
+	updated.NextFireTime = info.Target
+	if info.LastFireTime != nil {
+		updated.LastFireTime = *info.LastFireTime
+	}
+	if info.LastFlowID != nil {
+		updated.LastFlowID = *info.LastFlowID
+	}
+	updated.UpdatedAt = now()
+	m.schedules[info.ID] = updated
+
+	return nil
+}
+
+// ListSchedule 查询定时任务流
+func (m *memory) ListSchedule(kt *kit.Kit, input *ListInput) ([]model.Schedule, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	records := make([]*model.Schedule, 0)
+	for _, one := range m.schedules {
+		if matchTenant(kt, one.TenantID) {
+			records = append(records, one)
+		}
+	}
+
+	list, err := listRecords(input, tableasync.AsyncFlowScheduleColumns.ColumnTypes(), records, scheduleColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]model.Schedule, 0, len(list))
+	for _, one := range list {
+		schedules = append(schedules, *cloneSchedule(one))
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule 删除定时任务流
+func (m *memory) DeleteSchedule(kt *kit.Kit, id string) error {
+	if len(id) == 0 {
+		return errors.New("id is required")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if one, exist := m.schedules[id]; exist && matchTenant(kt, one.TenantID) {
+		delete(m.schedules, id)
+	}
+
+	return nil
+}
+
+// ListTenantIDs 返回存在任务流或定时任务流的租户ID
+func (m *memory) ListTenantIDs(_ *kit.Kit) ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	exists := make(map[string]struct{})
+	for _, one := range m.flows {
+		exists[one.TenantID] = struct{}{}
+	}
+	for _, one := range m.schedules {
+		exists[one.TenantID] = struct{}{}
+	}
+
+	tenantIDs := make([]string, 0, len(exists))
+	for tenantID := range exists {
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	sort.Strings(tenantIDs)
+
+	return tenantIDs, nil
+}
+
+func cloneFlow(src *model.Flow) *model.Flow {
+	dst := *src
+	dst.Reason = cloneReason(src.Reason)
+	dst.ShareData = cloneShareData(src.ShareData)
+	dst.Worker = cloneStrPtr(src.Worker)
+	dst.Deadline = cloneStrPtr(src.Deadline)
+	dst.Tasks = nil
+	return &dst
+}
+
+func cloneTask(src *model.Task) *model.Task {
+	dst := *src
+	dst.Retry = cloneRetry(src.Retry)
+	dst.Reason = cloneReason(src.Reason)
+	dst.DependOn = append(dst.DependOn[:0:0], src.DependOn...)
+	return &dst
+}
+
+func cloneSchedule(src *model.Schedule) *model.Schedule {
+	dst := *src
+	if src.Enabled != nil {
+		dst.Enabled = converter.ValToPtr(*src.Enabled)
+	}
+	dst.Memo = cloneStrPtr(src.Memo)
+	return &dst
+}
+
+func cloneStrPtr(src *string) *string {
+	if src == nil {
+		return nil
+	}
+	return converter.ValToPtr(*src)
+}
+
+func cloneReason(src *tableasync.Reason) *tableasync.Reason {
+	if src == nil {
+		return nil
+	}
+	dst := *src
+	return &dst
+}
+
+func cloneRetry(src *tableasync.Retry) *tableasync.Retry {
+	if src == nil {
+		return nil
+	}
+	dst := *src
+	if src.Policy != nil {
+		policy := *src.Policy
+		dst.Policy = &policy
+	}
+	return &dst
+}
+
+// cloneShareData 共享数据包含锁及内部map，需要序列化后重新生成，避免与调用方共享同一份数据
+func cloneShareData(src *tableasync.ShareData) *tableasync.ShareData {
+	if src == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return tableasync.NewShareData(src.GetInitData())
+	}
+
+	dst := new(tableasync.ShareData)
+	if err = json.Unmarshal(raw, dst); err != nil {
+		return tableasync.NewShareData(src.GetInitData())
+	}
+	return dst
+}
@@ -0,0 +1,384 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/runtime/filter"
+)
+
+// listRecords 按照查询条件在内存中过滤、排序及分页，行为与dao层的查询保持一致：
+// 1. 查询条件使用表字段进行校验，不支持JSON字段的查询。
+// 2. 字段值为NULL时，除相等判断外的所有比较都不匹配。
+// 3. 未指定排序字段时按照id排序。
+func listRecords[T any](input *ListInput, columnTypes map[string]enumor.ColumnType, records []*T,
+	columns func(*T) map[string]interface{}) ([]*T, error) {
+
+	if input == nil {
+		return nil, errors.New("list input is required")
+	}
+
+	if input.Filter == nil {
+		return nil, errors.New("filter is required")
+	}
+
+	if input.Page == nil {
+		return nil, errors.New("page is required")
+	}
+
+	if err := input.Filter.Validate(filter.NewExprOption(filter.RuleFields(columnTypes))); err != nil {
+		return nil, err
+	}
+
+	// 查询数量时dao层不返回详情
+	if input.Page.Count {
+		return make([]*T, 0), nil
+	}
+
+	type row struct {
+		record  *T
+		columns map[string]interface{}
+	}
+	rows := make([]row, 0, len(records))
+	for _, one := range records {
+		cols := columns(one)
+		matched, err := matchExpression(input.Filter, cols)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			rows = append(rows, row{record: one, columns: cols})
+		}
+	}
+
+	sortField := input.Page.Sort
+	if len(sortField) == 0 {
+		sortField = "id"
+	}
+	desc := input.Page.Order == core.Descending
+	sort.SliceStable(rows, func(i, j int) bool {
+		cmp, _ := compareValue(rows[i].columns[sortField], rows[j].columns[sortField])
+		if cmp == 0 {
+			cmp, _ = compareValue(rows[i].columns["id"], rows[j].columns["id"])
+		}
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	start, end := int(input.Page.Start), len(rows)
+	if input.Page.Limit != 0 && start+int(input.Page.Limit) < end {
+		end = start + int(input.Page.Limit)
+	}
+	if start >= len(rows) {
+		return make([]*T, 0), nil
+	}
+
+	result := make([]*T, 0, end-start)
+	for _, one := range rows[start:end] {
+		result = append(result, one.record)
+	}
+
+	return result, nil
+}
+
+// matchExpression 判断记录的字段值是否满足查询条件
+func matchExpression(expr *filter.Expression, columns map[string]interface{}) (bool, error) {
+	if expr == nil || len(expr.Rules) == 0 {
+		return true, nil
+	}
+
+	for _, rule := range expr.Rules {
+		matched, err := matchRule(rule, columns)
+		if err != nil {
+			return false, err
+		}
+
+		if expr.Op == filter.Or && matched {
+			return true, nil
+		}
+		if expr.Op != filter.Or && !matched {
+			return false, nil
+		}
+	}
+
+	return expr.Op != filter.Or, nil
+}
+
+func matchRule(rule filter.RuleFactory, columns map[string]interface{}) (bool, error) {
+	switch r := rule.(type) {
+	case filter.AtomRule:
+		return matchAtomRule(&r, columns)
+	case *filter.AtomRule:
+		return matchAtomRule(r, columns)
+	case *filter.Expression:
+		return matchExpression(r, columns)
+	default:
+		return false, fmt.Errorf("unsupported rule type: %T", rule)
+	}
+}
+
+func matchAtomRule(rule *filter.AtomRule, columns map[string]interface{}) (bool, error) {
+	field, exist := columns[rule.Field]
+	if !exist {
+		return false, fmt.Errorf("field %s is not supported by memory backend", rule.Field)
+	}
+
+	// NULL与任何值比较都不匹配
+	if field == nil {
+		return false, nil
+	}
+
+	_, isTime := field.(time.Time)
+	switch filter.OpType(rule.Op) {
+	case filter.Equal, filter.NotEqual, filter.GreaterThan, filter.GreaterThanEqual, filter.LessThan,
+		filter.LessThanEqual:
+
+		value, err := normalizeValue(rule.Value, isTime)
+		if err != nil {
+			return false, err
+		}
+		cmp, comparable := compareValue(field, value)
+		if !comparable {
+			return false, fmt.Errorf("field %s value %v is not comparable with %v", rule.Field, field, rule.Value)
+		}
+		return matchCompare(filter.OpType(rule.Op), cmp), nil
+
+	case filter.In, filter.NotIn:
+		values := reflect.ValueOf(rule.Value)
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return false, fmt.Errorf("field %s value of %s operator should be an array", rule.Field, rule.Op)
+		}
+
+		in := false
+		for i := 0; i < values.Len(); i++ {
+			value, err := normalizeValue(values.Index(i).Interface(), isTime)
+			if err != nil {
+				return false, err
+			}
+			if cmp, ok := compareValue(field, value); ok && cmp == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (filter.OpType(rule.Op) == filter.In), nil
+
+	case filter.ContainsSensitive, filter.ContainsInsensitive:
+		str, ok := field.(string)
+		sub, valid := rule.Value.(string)
+		if !ok || !valid {
+			return false, fmt.Errorf("field %s does not support %s operator", rule.Field, rule.Op)
+		}
+		if filter.OpType(rule.Op) == filter.ContainsInsensitive {
+			return strings.Contains(strings.ToLower(str), strings.ToLower(sub)), nil
+		}
+		return strings.Contains(str, sub), nil
+
+	default:
+		return false, fmt.Errorf("operator %s is not supported by memory backend", rule.Op)
+	}
+}
+
+func matchCompare(op filter.OpType, cmp int) bool {
+	switch op {
+	case filter.Equal:
+		return cmp == 0
+	case filter.NotEqual:
+		return cmp != 0
+	case filter.GreaterThan:
+		return cmp > 0
+	case filter.GreaterThanEqual:
+		return cmp >= 0
+	case filter.LessThan:
+		return cmp < 0
+	case filter.LessThanEqual:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// normalizeValue 将查询条件中的值转换为string、float64、bool或time.Time，便于与记录的字段值比较
+func normalizeValue(value interface{}, isTime bool) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if !isTime {
+			return v.String(), nil
+		}
+		t, err := time.ParseInLocation(constant.TimeStdFormat, v.String(), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s to location time failed, err: %v", v.String(), err)
+		}
+		return t, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type: %T", value)
+	}
+}
+
+// compareValue 比较两个已经标准化的值，返回值小于0、等于0、大于0分别表示a小于、等于、大于b，类型不一致时返回false
+func compareValue(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(av, bv), true
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case av == bv:
+			return 0, true
+		case !av:
+			return -1, true
+		default:
+			return 1, true
+		}
+	case time.Time:
+		bv, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		return av.Compare(bv), true
+	default:
+		return 0, false
+	}
+}
+
+// parseRecordTime 解析记录的创建、更新时间，用于时间类型的比较
+func parseRecordTime(t string) interface{} {
+	parsed, err := time.Parse(constant.TimeStdFormat, t)
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+func strPtrValue(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func flowColumns(f *model.Flow) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         f.ID,
+		"name":       string(f.Name),
+		"state":      string(f.State),
+		"memo":       f.Memo,
+		"worker":     strPtrValue(f.Worker),
+		"deadline":   strPtrValue(f.Deadline),
+		"tenant_id":  f.TenantID,
+		"creator":    f.Creator,
+		"reviser":    f.Reviser,
+		"created_at": parseRecordTime(f.CreatedAt),
+		"updated_at": parseRecordTime(f.UpdatedAt),
+	}
+}
+
+func taskColumns(t *model.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          t.ID,
+		"flow_id":     t.FlowID,
+		"flow_name":   string(t.FlowName),
+		"action_id":   string(t.ActionID),
+		"action_name": string(t.ActionName),
+		"state":       string(t.State),
+		"tenant_id":   t.TenantID,
+		"creator":     t.Creator,
+		"reviser":     t.Reviser,
+		"created_at":  parseRecordTime(t.CreatedAt),
+		"updated_at":  parseRecordTime(t.UpdatedAt),
+	}
+}
+
+func scheduleColumns(s *model.Schedule) map[string]interface{} {
+	var enabled interface{}
+	if s.Enabled != nil {
+		enabled = *s.Enabled
+	}
+
+	return map[string]interface{}{
+		"id":             s.ID,
+		"name":           s.Name,
+		"flow_name":      string(s.FlowName),
+		"cron_expr":      s.CronExpr,
+		"time_zone":      s.TimeZone,
+		"enabled":        enabled,
+		"misfire_policy": string(s.MisfirePolicy),
+		"next_fire_time": s.NextFireTime,
+		"last_fire_time": s.LastFireTime,
+		"last_flow_id":   s.LastFlowID,
+		"memo":           strPtrValue(s.Memo),
+		"tenant_id":      s.TenantID,
+		"creator":        s.Creator,
+		"reviser":        s.Reviser,
+		"created_at":     parseRecordTime(s.CreatedAt),
+		"updated_at":     parseRecordTime(s.UpdatedAt),
+	}
+}
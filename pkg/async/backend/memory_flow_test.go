@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend_test
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async"
+	actiontest "hcm/pkg/async/action/test"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/consumer"
+	"hcm/pkg/async/consumer/leader"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/cc"
+	"hcm/pkg/client/data-service/global"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+	"hcm/pkg/rest/client"
+	"hcm/pkg/rest/discovery"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryFlowEndToEnd 使用内存backend完整运行 producer -> dispatcher -> scheduler -> executor 流程
+func TestMemoryFlowEndToEnd(t *testing.T) {
+	bd := backend.NewMemory()
+	asy, err := async.NewAsync(bd, leader.NewStaticLeader(), &async.Option{
+		Register:       prometheus.NewRegistry(),
+		ConsumerOption: newTestConsumerOption(),
+	})
+	assert.NoError(t, err)
+
+	// 全局配置获取失败时调度器使用默认优先级
+	globalCfgCli := global.NewGlobalConfigClient(rest.NewClient(
+		&client.Capability{Discover: discovery.DeniedServers(cc.DataServiceName)}, "/api/v1/data"))
+	assert.NoError(t, asy.GetConsumer().Start(globalCfgCli))
+	defer asy.GetConsumer().Close()
+
+	kt := kit.New()
+	kt.User = "tester"
+	kt.TenantID = "default"
+	flowID, err := asy.GetProducer().AddTemplateFlow(kt, &producer.AddTemplateFlowOption{
+		Name: actiontest.NormalTpl.Name,
+		Tasks: []producer.TemplateFlowTask{{
+			ActionID: "1",
+			Params:   `{"name":"factory","age":1}`,
+		}},
+	})
+	assert.NoError(t, err)
+
+	var state enumor.FlowState
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		flows, err := bd.ListFlow(kt, &backend.ListInput{
+			Filter: tools.EqualExpression("id", flowID),
+			Page:   core.NewDefaultBasePage(),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, flows, 1)
+		state = flows[0].State
+		if state == enumor.FlowSuccess || state == enumor.FlowFailed {
+			break
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	assert.Equal(t, enumor.FlowSuccess, state)
+
+	tasks, err := bd.ListTask(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, len(actiontest.NormalTpl.Tasks))
+	for _, task := range tasks {
+		assert.Equal(t, enumor.TaskSuccess, task.State, "task %s", task.ActionID)
+	}
+}
+
+func newTestConsumerOption() *consumer.Option {
+	return &consumer.Option{
+		Scheduler: &consumer.SchedulerOption{
+			WatchIntervalSec:                1,
+			WorkerNumber:                    2,
+			ScheduledFlowFetcherConcurrency: 1,
+			CanceledFlowFetcherConcurrency:  1,
+		},
+		Executor: &consumer.ExecutorOption{
+			WorkerNumber:          2,
+			TaskExecTimeoutSec:    10,
+			InitQueueCapacity:     10,
+			FastTaskWorkerRatio:   0.5,
+			FastTaskThresholdSec:  1,
+			TimeWindowCapacity:    10,
+			TimeWindowDurationMin: 1,
+			FastTaskQueueCapacity: 10,
+			SlowTaskQueueCapacity: 10,
+		},
+		Dispatcher: &consumer.DispatcherOption{
+			WatchIntervalSec:              1,
+			PendingFlowFetcherConcurrency: 1,
+		},
+		WatchDog: &consumer.WatchDogOption{
+			WatchIntervalSec:    1,
+			TaskRunTimeoutSec:   30,
+			ShutdownWaitTimeSec: 1,
+			WorkerNumber:        1,
+		},
+		ScheduleTrigger: &consumer.ScheduleTriggerOption{
+			WatchIntervalSec:    1,
+			FetcherConcurrency:  1,
+			MisfireThresholdSec: 60,
+		},
+	}
+}
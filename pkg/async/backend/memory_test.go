@@ -0,0 +1,180 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"testing"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKit() *kit.Kit {
+	kt := kit.New()
+	kt.User = "tester"
+	kt.TenantID = "default"
+	return kt
+}
+
+func TestMemoryFlowStateCAS(t *testing.T) {
+	kt := newTestKit()
+	bd := NewMemory()
+
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "test",
+		ShareData: tableasync.NewShareData(map[string]string{"k": "v"}),
+		Tasks:     []model.Task{{ActionID: "1", ActionName: "test"}, {ActionID: "2", ActionName: "test"}},
+	})
+	assert.NoError(t, err)
+
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("state", enumor.FlowPending),
+			tools.RuleEqual("worker", ""),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, flows, 1)
+	assert.Equal(t, flowID, flows[0].ID)
+
+	// 第二个更新的源状态不匹配，整个批次都不会更新
+	err = bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("node1")},
+		{ID: flowID, Source: enumor.FlowPending, Target: enumor.FlowRunning},
+	})
+	assert.Equal(t, errf.RecordNotUpdate, errf.Error(err).Code)
+
+	err = bd.BatchUpdateFlowStateByCAS(kt, []UpdateFlowInfo{
+		{ID: flowID, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: converter.ValToPtr("node1")},
+		{ID: flowID, Source: enumor.FlowScheduled, Target: enumor.FlowRunning},
+	})
+	assert.NoError(t, err)
+
+	flows, err = bd.ListFlow(kt, &ListInput{
+		Filter: tools.ContainersExpression("state", []enumor.FlowState{enumor.FlowScheduled, enumor.FlowRunning}),
+		Page:   core.NewDefaultBasePage(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, flows, 1)
+	assert.Equal(t, enumor.FlowRunning, flows[0].State)
+	assert.Equal(t, "node1", *flows[0].Worker)
+	value, _ := flows[0].ShareData.Get("k")
+	assert.Equal(t, "v", value)
+
+	// 其他租户不可见
+	other := newTestKit()
+	other.TenantID = "other"
+	flows, err = bd.ListFlow(other, &ListInput{Filter: tools.AllExpression(), Page: core.NewDefaultBasePage()})
+	assert.NoError(t, err)
+	assert.Len(t, flows, 0)
+}
+
+func TestMemoryRetryTask(t *testing.T) {
+	kt := newTestKit()
+	bd := NewMemory()
+
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:  "test",
+		Tasks: []model.Task{{ActionID: "1", ActionName: "test"}},
+	})
+	assert.NoError(t, err)
+
+	tasks, err := bd.ListTask(kt, &ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	taskID := tasks[0].ID
+
+	// 任务流未失败时不允许重试
+	assert.Error(t, bd.RetryTask(kt, flowID, taskID))
+
+	err = bd.UpdateTaskStateByCAS(kt, &UpdateTaskInfo{ID: taskID, Source: enumor.TaskPending,
+		Target: enumor.TaskFailed})
+	assert.NoError(t, err)
+	err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: flowID, State: enumor.FlowFailed}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, bd.RetryTask(kt, flowID, taskID))
+
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, enumor.FlowPending, flows[0].State)
+
+	err = bd.UpdateTaskStateByCAS(kt, &UpdateTaskInfo{ID: taskID, Source: enumor.TaskFailed,
+		Target: enumor.TaskRunning})
+	assert.Equal(t, errf.RecordNotUpdate, errf.Error(err).Code)
+}
+
+func TestMemoryListPage(t *testing.T) {
+	kt := newTestKit()
+	bd := NewMemory()
+
+	for _, deadline := range []string{"2026-10-18T10:00:00Z", "2026-10-18T09:00:00Z", ""} {
+		flow := &model.Flow{Name: "test"}
+		if len(deadline) != 0 {
+			flow.Deadline = converter.ValToPtr(deadline)
+		}
+		_, err := bd.CreateFlow(kt, flow)
+		assert.NoError(t, err)
+	}
+
+	// 未设置截止时间的任务流不会匹配
+	flows, err := bd.ListFlow(kt, &ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				filter.AtomRule{Field: "deadline", Op: filter.LessThanEqual.Factory(), Value: "2026-10-18T11:00:00Z"},
+			},
+		},
+		Page: &core.BasePage{Start: 0, Limit: 1, Sort: "deadline", Order: core.Ascending},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, flows, 1)
+	assert.Equal(t, "2026-10-18T09:00:00Z", *flows[0].Deadline)
+
+	flows, err = bd.ListFlow(kt, &ListInput{
+		Filter: tools.AllExpression(),
+		Page:   &core.BasePage{Start: 1, Limit: 5, Order: core.Descending},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, flows, 2)
+	assert.Equal(t, "00000002", flows[0].ID)
+
+	_, err = bd.ListFlow(kt, &ListInput{
+		Filter: tools.EqualExpression("not_exist", "x"),
+		Page:   core.NewDefaultBasePage(),
+	})
+	assert.Error(t, err)
+}
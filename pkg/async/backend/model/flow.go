@@ -31,6 +31,8 @@ type Flow struct {
 	Name      enumor.FlowName       `json:"name"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Memo      string                `json:"memo"`
+	// Deadline 任务流截止时间，UTC时间的 constant.TimeStdFormat 格式，为空表示不限制
+	Deadline *string `json:"deadline"`
 
 	ID        string             `json:"id"`
 	State     enumor.FlowState   `json:"state"`
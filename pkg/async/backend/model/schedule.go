@@ -0,0 +1,46 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+)
+
+// Schedule 定时任务流，按照Cron表达式周期性创建模版任务流
+type Schedule struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	FlowName      enumor.FlowName      `json:"flow_name"`
+	CronExpr      string               `json:"cron_expr"`
+	TimeZone      string               `json:"time_zone"`
+	Tasks         types.JsonField      `json:"tasks"`
+	Enabled       *bool                `json:"enabled"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy"`
+	NextFireTime  string               `json:"next_fire_time"`
+	LastFireTime  string               `json:"last_fire_time"`
+	LastFlowID    string               `json:"last_flow_id"`
+	Memo          *string              `json:"memo"`
+	Creator       string               `json:"creator"`
+	Reviser       string               `json:"reviser"`
+	CreatedAt     string               `json:"created_at"`
+	UpdatedAt     string               `json:"updated_at"`
+	TenantID      string               `json:"tenant_id"`
+}
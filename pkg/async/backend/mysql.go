@@ -204,6 +204,7 @@ func (db *mysql) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
 			ShareData: flow.ShareData,
 			Memo:      flow.Memo,
 			Worker:    converter.ValToPtr(""),
+			Deadline:  flow.Deadline,
 			Creator:   kt.User,
 			Reviser:   kt.User,
 		}
@@ -304,6 +305,7 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 			ShareData: one.ShareData,
 			Memo:      one.Memo,
 			Worker:    one.Worker,
+			Deadline:  one.Deadline,
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
 			CreatedAt: one.CreatedAt.String(),
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+)
+
+// CreateSchedule 创建定时任务流
+func (db *mysql) CreateSchedule(kt *kit.Kit, schedule *model.Schedule) (string, error) {
+	if schedule == nil {
+		return "", errors.New("schedule is required")
+	}
+
+	md := &tableasync.AsyncFlowScheduleTable{
+		Name:          schedule.Name,
+		FlowName:      schedule.FlowName,
+		CronExpr:      schedule.CronExpr,
+		TimeZone:      schedule.TimeZone,
+		Tasks:         schedule.Tasks,
+		Enabled:       schedule.Enabled,
+		MisfirePolicy: schedule.MisfirePolicy,
+		NextFireTime:  schedule.NextFireTime,
+		Memo:          schedule.Memo,
+		Creator:       kt.User,
+		Reviser:       kt.User,
+	}
+	return db.dao.AsyncFlowSchedule().Create(kt, md)
+}
+
+// UpdateSchedule 更新定时任务流
+func (db *mysql) UpdateSchedule(kt *kit.Kit, schedule *model.Schedule) error {
+	if schedule == nil {
+		return errors.New("schedule is required")
+	}
+
+	md := &tableasync.AsyncFlowScheduleTable{
+		Name:          schedule.Name,
+		CronExpr:      schedule.CronExpr,
+		TimeZone:      schedule.TimeZone,
+		Tasks:         schedule.Tasks,
+		Enabled:       schedule.Enabled,
+		MisfirePolicy: schedule.MisfirePolicy,
+		NextFireTime:  schedule.NextFireTime,
+		Memo:          schedule.Memo,
+		Reviser:       kt.User,
+	}
+	return db.dao.AsyncFlowSchedule().UpdateByID(kt, schedule.ID, md)
+}
+
+// UpdateScheduleFireTimeByCAS CAS更新定时任务流下次触发时间
+func (db *mysql) UpdateScheduleFireTimeByCAS(kt *kit.Kit, info *UpdateScheduleFireInfo) error {
+	if info == nil {
+		return errors.New("update info is required")
+	}
+
+	return db.dao.AsyncFlowSchedule().UpdateFireTimeByCAS(kt, (*typesasync.UpdateScheduleFireInfo)(info))
+}
+
+// ListSchedule 查询定时任务流
+func (db *mysql) ListSchedule(kt *kit.Kit, input *ListInput) ([]model.Schedule, error) {
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowSchedule().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]model.Schedule, 0, len(list.Details))
+	for _, one := range list.Details {
+		schedules = append(schedules, model.Schedule{
+			ID:            one.ID,
+			Name:          one.Name,
+			FlowName:      one.FlowName,
+			CronExpr:      one.CronExpr,
+			TimeZone:      one.TimeZone,
+			Tasks:         one.Tasks,
+			Enabled:       one.Enabled,
+			MisfirePolicy: one.MisfirePolicy,
+			NextFireTime:  one.NextFireTime,
+			LastFireTime:  one.LastFireTime,
+			LastFlowID:    one.LastFlowID,
+			Memo:          one.Memo,
+			Creator:       one.Creator,
+			Reviser:       one.Reviser,
+			CreatedAt:     one.CreatedAt.String(),
+			UpdatedAt:     one.UpdatedAt.String(),
+			TenantID:      one.TenantID,
+		})
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule 删除定时任务流
+func (db *mysql) DeleteSchedule(kt *kit.Kit, id string) error {
+	if len(id) == 0 {
+		return errors.New("id is required")
+	}
+
+	return db.dao.AsyncFlowSchedule().Delete(kt, tools.EqualExpression("id", id))
+}
@@ -27,6 +27,7 @@ import (
 	"hcm/pkg/async/backend"
 	"hcm/pkg/async/compctrl"
 	"hcm/pkg/async/consumer/leader"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/client/data-service/global"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao/tools"
@@ -47,6 +48,8 @@ Consumer 异步任务消费者。组件分为两类，公共组件、主节点
 	2.处理处于Scheduled状态，但执行节点已经挂掉的任务流
 	3.处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
 
+-scheduleTrigger（定时触发器）: 负责按cron表达式周期性创建定时任务流对应的模版任务流。
+
 公共组件：
 -scheduler（调度器）:
 
@@ -64,12 +67,16 @@ type Consumer interface {
 	Start(globalCfg *global.GlobalConfigsClient) error
 	CancelFlow(kit *kit.Kit, flowId string) error
 	SetFlowTypePriority(flowType enumor.FlowName, priority int)
+	// GetTaskTypeAvgExecTime 获取当前节点统计的任务类型平均执行时间，neverExec为true表示当前节点未执行过该类型任务
+	GetTaskTypeAvgExecTime(actionName enumor.ActionName) (avgExecTime float64, neverExec bool)
 }
 
 var _ Consumer = new(consumer)
 
 // NewConsumer new consumer.
-func NewConsumer(bd backend.Backend, ld leader.Leader, register prometheus.Registerer, opt *Option) (Consumer, error) {
+func NewConsumer(bd backend.Backend, ld leader.Leader, pdr producer.Producer, register prometheus.Registerer,
+	opt *Option) (Consumer, error) {
+
 	if bd == nil {
 		return nil, errors.New("backend is required")
 	}
@@ -78,6 +85,10 @@ func NewConsumer(bd backend.Backend, ld leader.Leader, register prometheus.Regis
 		return nil, errors.New("leader is required")
 	}
 
+	if pdr == nil {
+		return nil, errors.New("producer is required")
+	}
+
 	if register == nil {
 		return nil, errors.New("metrics register is required")
 	}
@@ -86,6 +97,7 @@ func NewConsumer(bd backend.Backend, ld leader.Leader, register prometheus.Regis
 		opt:     opt,
 		backend: bd,
 		leader:  ld,
+		pdr:     pdr,
 		mc:      initMetric(register),
 		closers: make([]compctrl.Closer, 0),
 	}, nil
@@ -96,6 +108,7 @@ type consumer struct {
 
 	backend backend.Backend
 	leader  leader.Leader
+	pdr     producer.Producer
 	mc      *metric
 
 	executor  Executor
@@ -121,7 +134,7 @@ func (csm *consumer) Start(globalCfgCli *global.GlobalConfigsClient) error {
 // initLeaderComponent 初始化主节点私有组件并启动，同时设置关闭函数
 func (csm *consumer) initLeaderComponent(kt *kit.Kit, opt *Option) {
 
-	handler := NewLeaderChangeHandler(csm.backend, csm.leader, opt)
+	handler := NewLeaderChangeHandler(csm.backend, csm.leader, csm.pdr, opt)
 	handler.Start()
 	csm.closers = append(csm.closers, handler)
 
@@ -161,6 +174,9 @@ func (csm *consumer) Close() {
 		csm.closers[i].Close()
 	}
 
+	// 所有组件关闭后再释放主节点身份
+	csm.leader.Close()
+
 	logs.Infof("consumer close success")
 
 }
@@ -203,3 +219,12 @@ func (csm *consumer) CancelFlow(kt *kit.Kit, flowId string) error {
 func (csm *consumer) SetFlowTypePriority(flowType enumor.FlowName, priority int) {
 	csm.scheduler.SetFlowTypePriority(flowType, priority)
 }
+
+// GetTaskTypeAvgExecTime 获取当前节点统计的任务类型平均执行时间
+func (csm *consumer) GetTaskTypeAvgExecTime(actionName enumor.ActionName) (avgExecTime float64, neverExec bool) {
+	if csm.executor == nil {
+		return 0, true
+	}
+
+	return csm.executor.GetTaskTypeAvgExecTime(actionName)
+}
@@ -69,7 +69,7 @@ func (d *Dispatcher) Start() {
 // WatchPendingFlow 监听处于Pending状态的流，并派发到指定节点。
 func (d *Dispatcher) WatchPendingFlow() {
 	// 初始化协程池
-	pool := newTenantWorkerPool(d.pendingFlowFetcherConcurrency,
+	pool := newTenantWorkerPool(d.pendingFlowFetcherConcurrency, d.bd,
 		func(tenantID string) {
 			kt := NewKit()
 			kt.TenantID = tenantID
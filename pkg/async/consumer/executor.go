@@ -58,6 +58,8 @@ type Executor interface {
 	// CancelTasks 关闭指定task_id的任务。
 	CancelTasks(taskIDs []string) error
 	CancelFlow(kt *kit.Kit, flowID string) error
+	// WaitFlowTasks 等待指定flow已下发到执行器的任务全部执行返回。
+	WaitFlowTasks(kt *kit.Kit, flowID string) error
 
 	GetTaskTypeAvgExecTime(taskType enumor.ActionName) (float64, bool)
 	GetFastTaskThresholdSec() float64
@@ -73,6 +75,8 @@ type executor struct {
 	fastTaskWorkerRatio float64
 
 	cancelMap             sync.Map
+	flowTaskMu            sync.Mutex
+	flowTaskMap           map[string]*flowTasks // flow已下发到执行器且未执行返回的任务
 	workerWg              sync.WaitGroup
 	initWg                sync.WaitGroup
 	fastTaskQueue         chan *Task
@@ -104,6 +108,7 @@ func NewExecutor(kt *kit.Kit, bd backend.Backend, opt *ExecutorOption, mc *metri
 		workerWg:              sync.WaitGroup{},
 		initWg:                sync.WaitGroup{},
 		initQueue:             NewTaskInitQueue(opt.InitQueueCapacity, mc),
+		flowTaskMap:           make(map[string]*flowTasks),
 		taskTypeTimeWindowMap: make(map[enumor.ActionName]*TimeWindow),
 		closeCh:               make(chan struct{}, 1),
 		taskExecTimeoutSec:    opt.TaskExecTimeoutSec,
@@ -220,6 +225,7 @@ func (exec *executor) initWorkerTask(flow *Flow, task *Task) {
 	if _, ok := exec.cancelMap.Load(task.ID); ok {
 		logs.Warnf("%s: executor task %s is already running, rid: %s", constant.AsyncTaskWarnSign,
 			task.ID, task.Kit.Rid)
+		exec.doneFlowTask(flow.ID)
 		return
 	}
 
@@ -249,6 +255,8 @@ func (exec *executor) initWorkerTask(flow *Flow, task *Task) {
 
 // 任务执行体
 func (exec *executor) workerDo(task *Task) (err error) {
+	// 任务执行返回后，才允许回滚取消的flow
+	defer exec.doneFlowTask(task.FlowID)
 	// cancelMap清理执行成功/失败的任务
 	defer exec.cancelMap.Delete(task.ID)
 	// 无论任务成功还是失败，都需要交给scheduler分析任务流的状态
@@ -416,6 +424,7 @@ func (exec *executor) Push(flow *Flow, task *Task) {
 	default:
 	}
 
+	exec.addFlowTask(flow.ID)
 	err := exec.initQueue.Push(&InitPayload{
 		flow:      flow,
 		task:      task,
@@ -423,10 +432,64 @@ func (exec *executor) Push(flow *Flow, task *Task) {
 	})
 	if err != nil {
 		logs.Errorf("fail to push InitPayload to task init queue, err: %v", err)
+		exec.doneFlowTask(flow.ID)
 		return
 	}
 }
 
+// flowTasks flow已下发到执行器且未执行返回的任务计数，计数归零时关闭done
+type flowTasks struct {
+	num  int
+	done chan struct{}
+}
+
+// addFlowTask 记录flow下发到执行器的任务
+func (exec *executor) addFlowTask(flowID string) {
+	exec.flowTaskMu.Lock()
+	defer exec.flowTaskMu.Unlock()
+
+	ft, ok := exec.flowTaskMap[flowID]
+	if !ok {
+		ft = &flowTasks{done: make(chan struct{})}
+		exec.flowTaskMap[flowID] = ft
+	}
+	ft.num++
+}
+
+// doneFlowTask 标记flow的任务执行返回，全部返回后通知等待者
+func (exec *executor) doneFlowTask(flowID string) {
+	exec.flowTaskMu.Lock()
+	defer exec.flowTaskMu.Unlock()
+
+	ft, ok := exec.flowTaskMap[flowID]
+	if !ok {
+		return
+	}
+	ft.num--
+	if ft.num > 0 {
+		return
+	}
+	close(ft.done)
+	delete(exec.flowTaskMap, flowID)
+}
+
+// WaitFlowTasks 等待指定flow已下发到执行器的任务全部执行返回，用于取消flow后回滚前确保任务不再运行
+func (exec *executor) WaitFlowTasks(kt *kit.Kit, flowID string) error {
+	exec.flowTaskMu.Lock()
+	ft, ok := exec.flowTaskMap[flowID]
+	exec.flowTaskMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-ft.done:
+		return nil
+	case <-kt.Ctx.Done():
+		return kt.Ctx.Err()
+	}
+}
+
 // InitPayload 任务初始化信息
 type InitPayload struct {
 	flow      *Flow
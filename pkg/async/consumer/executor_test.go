@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitFlowTasks(t *testing.T) {
+	exec := &executor{flowTaskMap: make(map[string]*flowTasks)}
+	kt := kit.New()
+
+	// 没有执行中的任务直接返回
+	assert.NoError(t, exec.WaitFlowTasks(kt, "flow-1"))
+
+	exec.addFlowTask("flow-1")
+	exec.addFlowTask("flow-1")
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- exec.WaitFlowTasks(kt, "flow-1")
+	}()
+
+	exec.doneFlowTask("flow-1")
+	select {
+	case <-waited:
+		t.Fatal("wait returned before all tasks of flow done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	exec.doneFlowTask("flow-1")
+	select {
+	case err := <-waited:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("wait not returned after all tasks of flow done")
+	}
+	assert.Empty(t, exec.flowTaskMap)
+
+	// 等待被取消时返回错误
+	exec.addFlowTask("flow-2")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	kt.Ctx = ctx
+	assert.ErrorIs(t, exec.WaitFlowTasks(kt, "flow-2"), context.Canceled)
+}
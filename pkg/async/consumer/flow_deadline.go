@@ -0,0 +1,186 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+)
+
+// handleDeadlineExceededFlows 取消超过截止时间仍未结束的任务流，并发送通知。
+// 已分配执行节点的任务流，由执行节点取消并回滚执行中的任务；未分配执行节点的任务流，直接取消任务流及其任务。
+func (wd *watchDog) handleDeadlineExceededFlows(kt *kit.Kit) error {
+
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{
+					Field: "state",
+					Op:    filter.In.Factory(),
+					Value: []enumor.FlowState{enumor.FlowInit, enumor.FlowPending, enumor.FlowScheduled,
+						enumor.FlowRunning},
+				},
+				&filter.AtomRule{
+					Field: "deadline",
+					Op:    filter.LessThanEqual.Factory(),
+					Value: time.Now().UTC().Format(constant.TimeStdFormat),
+				},
+			},
+		},
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: listDeadlineExceededFlowLimit,
+		},
+	}
+	flows, err := wd.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list deadline exceeded flows failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	for _, flow := range flows {
+		if err = wd.cancelDeadlineExceededFlow(kt, flow); err != nil {
+			if errf.Error(err).Code == errf.RecordNotUpdate {
+				// 任务流状态已经变化，下一轮重新判断
+				continue
+			}
+
+			logs.Errorf("cancel deadline exceeded flow failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+			continue
+		}
+
+		logs.Warnf("%s: flow %s(%s) exceeded deadline %s, canceled from state %s, rid: %s",
+			constant.AsyncTaskWarnSign, flow.ID, flow.Name, converter.PtrToVal(flow.Deadline), flow.State, kt.Rid)
+
+		if wd.notifier == nil {
+			continue
+		}
+		if err = wd.notifier.NotifyFlowDeadlineExceeded(kt, flow); err != nil {
+			logs.Errorf("notify flow deadline exceeded failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+func (wd *watchDog) cancelDeadlineExceededFlow(kt *kit.Kit, flow model.Flow) error {
+	info := backend.UpdateFlowInfo{
+		ID:     flow.ID,
+		Source: flow.State,
+		Target: enumor.FlowCancel,
+		Reason: &tableasync.Reason{
+			Message:          fmt.Sprintf("%s: %s", ErrFlowDeadlineExceeded, converter.PtrToVal(flow.Deadline)),
+			PreState:         string(flow.State),
+			RollbackOnCancel: true,
+		},
+	}
+
+	assigned := flow.State == enumor.FlowScheduled || flow.State == enumor.FlowRunning
+	if !assigned {
+		info.Worker = converter.ValToPtr("")
+	}
+
+	if err := wd.bd.BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
+		return err
+	}
+
+	// 已分配执行节点的任务流，由执行节点上的 canceledFlowWatcher 取消并回滚任务
+	if assigned {
+		return nil
+	}
+
+	tasks, err := listTaskByFlowID(kt, wd.bd, flow.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if task.State == enumor.TaskSuccess || task.State == enumor.TaskCancel {
+			continue
+		}
+
+		md := &model.Task{
+			ID:    task.ID,
+			State: enumor.TaskCancel,
+			Reason: &tableasync.Reason{
+				Message:  ErrFlowDeadlineExceeded,
+				PreState: string(task.State),
+			},
+		}
+		if err = wd.bd.UpdateTask(kt, md); err != nil {
+			logs.Errorf("update task to cancel state failed, err: %v, task: %s, rid: %s", err, task.ID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollbackCanceledTasks 回滚任务流取消时处于执行中的任务，回滚失败不影响任务流取消
+func rollbackCanceledTasks(kt *kit.Kit, flow model.Flow, tasks []*Task) {
+	for _, task := range tasks {
+		if task.State != enumor.TaskRunning && task.State != enumor.TaskRollback {
+			continue
+		}
+
+		act, exist := action.GetAction(task.ActionName)
+		if !exist {
+			logs.Errorf("action: %s not found, skip rollback, task: %s, rid: %s", task.ActionName, task.ID, kt.Rid)
+			continue
+		}
+
+		rollbackAct, ok := act.(action.RollbackAction)
+		if !ok {
+			logs.Infof("action: %s has no RollbackAction, skip rollback, task: %s, rid: %s", task.ActionName,
+				task.ID, kt.Rid)
+			continue
+		}
+
+		task.ExecuteKit = run.NewExecuteContext(task.Kit, flow.ShareData)
+		params, err := task.prepareParams(act)
+		if err != nil {
+			logs.Errorf("prepare task params for rollback failed, err: %v, task: %s, rid: %s", err, task.ID, kt.Rid)
+			continue
+		}
+
+		if err = rollbackAct.Rollback(task.ExecuteKit, params); err != nil {
+			logs.Errorf("%s: rollback canceled task failed, err: %v, flow: %s, task: %s, rid: %s",
+				constant.AsyncTaskWarnSign, err, flow.ID, task.ID, kt.Rid)
+			continue
+		}
+
+		logs.Infof("rollback canceled task success, flow: %s, task: %s, rid: %s", flow.ID, task.ID, kt.Rid)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package leader
+
+import (
+	"strings"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+)
+
+var _ Leader = new(etcdLeader)
+
+// NewEtcdLeader 创建一个基于etcd服务发现的主节点控制器
+func NewEtcdLeader(sd serviced.ServiceDiscover) Leader {
+	return &etcdLeader{
+		sd: sd,
+	}
+}
+
+// etcdLeader 通过etcd服务发现选主，服务注册路径下最早注册的节点为主节点
+type etcdLeader struct {
+	sd serviced.ServiceDiscover
+}
+
+// CurrNode return current node key.
+func (al *etcdLeader) CurrNode() string {
+	split := strings.Split(al.sd.CurrentNodeKey(), "/")
+
+	if len(split) > 0 {
+		return split[len(split)-1]
+	}
+
+	// this should not be happened
+	return ""
+}
+
+// AliveNodes return current node key.
+func (al *etcdLeader) AliveNodes() ([]string, error) {
+
+	keys, err := al.sd.GetServiceAllNodeKeys(cc.TaskServerName)
+	if err != nil {
+		logs.Errorf("get task server all node keys failed, err: %v", err)
+		return nil, err
+	}
+
+	// 因为只是需要TaskServer全部节点的唯一标识，所以，仅需要TaskServer节点路径下的UUID即可。
+	keyUUIDs := make([]string, 0, len(keys))
+	for _, one := range keys {
+		split := strings.Split(one, "/")
+		keyUUIDs = append(keyUUIDs, split[len(split)-1])
+	}
+
+	return keyUUIDs, nil
+}
+
+// IsLeader 判断是否是主节点
+func (al *etcdLeader) IsLeader() bool {
+	return al.sd.IsMaster()
+}
+
+// Close 主节点身份随服务发现注销释放，无需额外处理
+func (al *etcdLeader) Close() {}
@@ -21,66 +21,40 @@
 package leader
 
 import (
-	"strings"
+	"errors"
+	"fmt"
 
-	"hcm/pkg/cc"
-	"hcm/pkg/logs"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
 	"hcm/pkg/serviced"
 )
 
 // Leader 选主管理
 type Leader interface {
+	// Closer 停止选主，释放当前节点持有的主节点身份
+	compctrl.Closer
 	IsLeader() bool
 	AliveNodes() ([]string, error)
 	CurrNode() string
 }
 
-var _ Leader = new(leader)
-
-// NewLeader 创建一个主节点控制器
-func NewLeader(sd serviced.ServiceDiscover) Leader {
-	return &leader{
-		sd: sd,
+// Factory 根据选主方式返回不同的Leader实现，etcd方式需要sd，mysql方式需要dao
+func Factory(typ enumor.LeaderType, sd serviced.ServiceDiscover, ds dao.Set) (Leader, error) {
+	switch typ {
+	case enumor.LeaderEtcd:
+		if sd == nil {
+			return nil, errors.New("service discover is required by etcd leader")
+		}
+		return NewEtcdLeader(sd), nil
+	case enumor.LeaderMysql:
+		if ds == nil {
+			return nil, errors.New("dao set is required by mysql leader")
+		}
+		return NewMysqlLeader(ds), nil
+	case enumor.LeaderStatic:
+		return NewStaticLeader(), nil
+	default:
+		return nil, fmt.Errorf("unsupported leader type: %s", typ)
 	}
 }
-
-// leader ...
-type leader struct {
-	sd serviced.ServiceDiscover
-}
-
-// CurrNode return current node key.
-func (al *leader) CurrNode() string {
-	split := strings.Split(al.sd.CurrentNodeKey(), "/")
-
-	if len(split) > 0 {
-		return split[len(split)-1]
-	}
-
-	// this should not be happened
-	return ""
-}
-
-// AliveNodes return current node key.
-func (al *leader) AliveNodes() ([]string, error) {
-
-	keys, err := al.sd.GetServiceAllNodeKeys(cc.TaskServerName)
-	if err != nil {
-		logs.Errorf("get task server all node keys failed, err: %v", err)
-		return nil, err
-	}
-
-	// 因为只是需要TaskServer全部节点的唯一标识，所以，仅需要TaskServer节点路径下的UUID即可。
-	keyUUIDs := make([]string, 0, len(keys))
-	for _, one := range keys {
-		split := strings.Split(one, "/")
-		keyUUIDs = append(keyUUIDs, split[len(split)-1])
-	}
-
-	return keyUUIDs, nil
-}
-
-// IsLeader 判断是否是主节点
-func (al *leader) IsLeader() bool {
-	return al.sd.IsMaster()
-}
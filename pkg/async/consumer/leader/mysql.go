@@ -0,0 +1,150 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package leader
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/dal/dao"
+	daoasync "hcm/pkg/dal/dao/async"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/uuid"
+)
+
+const (
+	// mysqlLeaderLockName 选主使用的MySQL用户锁名称
+	mysqlLeaderLockName = "hcm_task_server_leader"
+	// mysqlHeartbeatInterval 节点心跳及检查主节点锁的周期
+	mysqlHeartbeatInterval = 5 * time.Second
+	// mysqlNodeExpireSec 超过该时间没有心跳的节点认为已经不存活
+	mysqlNodeExpireSec = 30
+)
+
+var _ Leader = new(mysqlLeader)
+
+// NewMysqlLeader 创建一个基于MySQL的主节点控制器，创建时会同步进行一次心跳上报和抢锁
+func NewMysqlLeader(ds dao.Set) Leader {
+	ml := &mysqlLeader{
+		node:     uuid.UUID(),
+		ds:       ds,
+		lock:     ds.AsyncAdvisoryLock(mysqlLeaderLockName),
+		closeCh:  make(chan struct{}),
+		loopDone: make(chan struct{}),
+	}
+
+	ml.keepalive()
+	go ml.loop()
+
+	return ml
+}
+
+// mysqlLeader 通过MySQL用户锁(GET_LOCK)选主，持有锁的节点为主节点。节点定期在async_worker表中上报心跳，
+// 最近 mysqlNodeExpireSec 秒内有心跳的节点为存活节点。
+type mysqlLeader struct {
+	node     string
+	ds       dao.Set
+	lock     daoasync.AdvisoryLock
+	isLeader atomic.Bool
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	// loopDone 心跳协程退出后关闭
+	loopDone chan struct{}
+}
+
+func (ml *mysqlLeader) loop() {
+	defer close(ml.loopDone)
+
+	ticker := time.NewTicker(mysqlHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ml.closeCh:
+			return
+		case <-ticker.C:
+			ml.keepalive()
+		}
+	}
+}
+
+// Close 停止心跳协程，释放主节点锁并删除当前节点的心跳记录，使其他节点可以立即接管
+func (ml *mysqlLeader) Close() {
+	ml.closeOnce.Do(func() {
+		close(ml.closeCh)
+		<-ml.loopDone
+
+		kt := core.NewBackendKit()
+		ml.isLeader.Store(false)
+		if err := ml.lock.Unlock(kt.Ctx); err != nil {
+			logs.Errorf("release async leader lock failed, err: %v, node: %s, rid: %s", err, ml.node, kt.Rid)
+		}
+
+		if err := ml.ds.AsyncWorker().Delete(kt, ml.node); err != nil {
+			logs.Errorf("delete async worker failed, err: %v, node: %s, rid: %s", err, ml.node, kt.Rid)
+		}
+
+		logs.Infof("async mysql leader closed, node: %s, rid: %s", ml.node, kt.Rid)
+	})
+}
+
+// keepalive 上报心跳，并尝试获取或确认持有主节点锁
+func (ml *mysqlLeader) keepalive() {
+	kt := core.NewBackendKit()
+
+	if err := ml.ds.AsyncWorker().Heartbeat(kt, ml.node); err != nil {
+		logs.Errorf("report async worker heartbeat failed, err: %v, node: %s, rid: %s", err, ml.node, kt.Rid)
+	}
+
+	held, err := ml.lock.TryLock(kt.Ctx)
+	if err != nil {
+		logs.Errorf("try lock async leader failed, err: %v, node: %s, rid: %s", err, ml.node, kt.Rid)
+		held = false
+	}
+
+	if ml.isLeader.Swap(held) != held {
+		logs.Infof("async leader state changed, node: %s, is leader: %v, rid: %s", ml.node, held, kt.Rid)
+	}
+}
+
+// IsLeader 判断是否是主节点
+func (ml *mysqlLeader) IsLeader() bool {
+	return ml.isLeader.Load()
+}
+
+// AliveNodes 返回最近有心跳的节点
+func (ml *mysqlLeader) AliveNodes() ([]string, error) {
+	kt := core.NewBackendKit()
+	nodes, err := ml.ds.AsyncWorker().ListAlive(kt, mysqlNodeExpireSec)
+	if err != nil {
+		logs.Errorf("list alive async worker failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// CurrNode return current node key.
+func (ml *mysqlLeader) CurrNode() string {
+	return ml.node
+}
@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package leader
+
+import (
+	"hcm/pkg/tools/uuid"
+)
+
+var _ Leader = new(staticLeader)
+
+// NewStaticLeader 创建单节点部署使用的主节点控制器，当前节点固定为主节点且是唯一存活节点
+func NewStaticLeader() Leader {
+	return &staticLeader{
+		node: uuid.UUID(),
+	}
+}
+
+// staticLeader 单节点主节点控制器，不依赖任何外部组件，适用于单节点部署和测试环境
+type staticLeader struct {
+	node string
+}
+
+// IsLeader 当前节点固定为主节点
+func (sl *staticLeader) IsLeader() bool {
+	return true
+}
+
+// AliveNodes 只有当前节点存活
+func (sl *staticLeader) AliveNodes() ([]string, error) {
+	return []string{sl.node}, nil
+}
+
+// CurrNode return current node key.
+func (sl *staticLeader) CurrNode() string {
+	return sl.node
+}
+
+// Close 单节点主节点控制器不持有任何资源，无需关闭
+func (sl *staticLeader) Close() {}
@@ -26,15 +26,19 @@ import (
 	"hcm/pkg/async/backend"
 	"hcm/pkg/async/compctrl"
 	"hcm/pkg/async/consumer/leader"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/logs"
 )
 
 // NewLeaderChangeHandler new leader change handler.
-func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, opt *Option) *LeaderChangeHandler {
+func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, pdr producer.Producer,
+	opt *Option) *LeaderChangeHandler {
+
 	return &LeaderChangeHandler{
 		opt:     opt,
 		ld:      ld,
 		bd:      bd,
+		pdr:     pdr,
 		closeCh: make(chan struct{}),
 		closers: make([]compctrl.Closer, 0),
 		wg:      sync.WaitGroup{},
@@ -45,11 +49,13 @@ func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, opt *Option) *
 type LeaderChangeHandler struct {
 	opt *Option
 
-	ld leader.Leader
-	bd backend.Backend
+	ld  leader.Leader
+	bd  backend.Backend
+	pdr producer.Producer
 
 	dispatcher *Dispatcher
 	watchDog   WatchDog
+	trigger    ScheduleTrigger
 
 	closeCh chan struct{}
 
@@ -65,6 +71,8 @@ func (handler *LeaderChangeHandler) Start() {
 
 // Do 负责主节点组件的开启和关闭，在切主/切从的时候。
 func (handler *LeaderChangeHandler) Do() {
+	defer handler.wg.Done()
+
 	for {
 		time.Sleep(time.Second)
 
@@ -72,34 +80,38 @@ func (handler *LeaderChangeHandler) Do() {
 		select {
 		case <-handler.closeCh:
 			handler.closeLeaderComponent()
-			break
+			return
 		default:
 		}
 
-		// 如果是从节点，且主节点组件处于关闭状态，直接跳过即可
-		if !handler.ld.IsLeader() && len(handler.closers) == 0 {
-			continue
-		}
+		handler.handleLeaderChange()
+	}
+}
 
-		// 如果是主切从（从节点，但主节点组件处于开启状态），需要关闭主节点组件
-		if !handler.ld.IsLeader() && len(handler.closers) != 0 {
-			logs.Infof("the current node changes from the master node to the slave node, " +
-				"and start to stop handleRunningFlow async tasks")
+// handleLeaderChange 根据当前节点是否为主节点，开启或关闭主节点组件
+func (handler *LeaderChangeHandler) handleLeaderChange() {
+	isLeader := handler.ld.IsLeader()
 
-			handler.closeLeaderComponent()
-			continue
-		}
+	// 如果是从节点，且主节点组件处于关闭状态，直接跳过即可
+	if !isLeader && len(handler.closers) == 0 {
+		return
+	}
 
-		// 如果是从切主，需要开启主节点组件
-		if handler.ld.IsLeader() && len(handler.closers) == 0 {
-			logs.Infof("the current node is master, start leader component...")
-			handler.startLeaderComponent()
-			logs.Infof("the current node is master, start leader success")
-			continue
-		}
+	// 如果是主切从（从节点，但主节点组件处于开启状态），需要关闭主节点组件
+	if !isLeader && len(handler.closers) != 0 {
+		logs.Infof("the current node changes from the master node to the slave node, " +
+			"and start to stop handleRunningFlow async tasks")
+
+		handler.closeLeaderComponent()
+		return
 	}
 
-	handler.wg.Done()
+	// 如果是从切主，需要开启主节点组件
+	if isLeader && len(handler.closers) == 0 {
+		logs.Infof("the current node is master, start leader component...")
+		handler.startLeaderComponent()
+		logs.Infof("the current node is master, start leader success")
+	}
 }
 
 func (handler *LeaderChangeHandler) startLeaderComponent() {
@@ -109,10 +121,16 @@ func (handler *LeaderChangeHandler) startLeaderComponent() {
 	handler.dispatcher = dis
 
 	// 初始化watchdog并启动同时设置关闭函数
-	wd := NewWatchDog(handler.bd, handler.ld, handler.opt.WatchDog)
+	wd := NewWatchDog(handler.bd, handler.ld, handler.opt.Notifier, handler.opt.WatchDog)
 	wd.Start()
 	handler.closers = append(handler.closers, wd)
 	handler.watchDog = wd
+
+	// 初始化定时触发器并启动同时设置关闭函数
+	st := NewScheduleTrigger(handler.bd, handler.pdr, handler.opt.ScheduleTrigger)
+	st.Start()
+	handler.closers = append(handler.closers, st)
+	handler.trigger = st
 }
 
 // Close 主从切换处理器
@@ -120,8 +138,8 @@ func (handler *LeaderChangeHandler) Close() {
 
 	logs.Infof("LeaderChangeHandler receive close cmd, start to close")
 
+	// 主节点组件由Do协程退出时关闭，避免与Do协程并发关闭
 	close(handler.closeCh)
-	handler.closeLeaderComponent()
 	handler.wg.Wait()
 
 	logs.Infof("LeaderChangeHandler close success")
@@ -0,0 +1,75 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/consumer/leader"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// switchLeader 可以手动切换主从状态的选主器
+type switchLeader struct {
+	leader.Leader
+	isLeader atomic.Bool
+}
+
+func (sl *switchLeader) IsLeader() bool {
+	return sl.isLeader.Load()
+}
+
+func TestLeaderChangeHandler(t *testing.T) {
+	ld := &switchLeader{Leader: leader.NewStaticLeader()}
+	handler := NewLeaderChangeHandler(backend.NewMemory(), ld, nil, &Option{
+		Dispatcher: &DispatcherOption{WatchIntervalSec: 1, PendingFlowFetcherConcurrency: 1},
+		WatchDog: &WatchDogOption{WatchIntervalSec: 1, TaskRunTimeoutSec: 60, ShutdownWaitTimeSec: 1,
+			WorkerNumber: 1},
+		ScheduleTrigger: &ScheduleTriggerOption{WatchIntervalSec: 1, FetcherConcurrency: 1,
+			MisfireThresholdSec: 60},
+	})
+
+	// 从节点不启动主节点组件
+	handler.handleLeaderChange()
+	assert.Len(t, handler.closers, 0)
+
+	// 从切主，启动主节点组件，重复检查不会重复启动
+	ld.isLeader.Store(true)
+	handler.handleLeaderChange()
+	assert.Len(t, handler.closers, 3)
+	handler.handleLeaderChange()
+	assert.Len(t, handler.closers, 3)
+
+	// 主切从，关闭主节点组件
+	ld.isLeader.Store(false)
+	handler.handleLeaderChange()
+	assert.Len(t, handler.closers, 0)
+
+	// 关闭处理器时关闭正在运行的主节点组件
+	ld.isLeader.Store(true)
+	handler.Start()
+	time.Sleep(1500 * time.Millisecond)
+	handler.Close()
+	assert.Len(t, handler.closers, 0)
+}
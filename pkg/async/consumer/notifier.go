@@ -0,0 +1,31 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/kit"
+)
+
+// FlowNotifier 任务流通知，由使用异步任务框架的服务实现，如通过CMSI发送邮件
+type FlowNotifier interface {
+	// NotifyFlowDeadlineExceeded 任务流超过截止时间被取消后发送通知
+	NotifyFlowDeadlineExceeded(kt *kit.Kit, flow model.Flow) error
+}
@@ -31,6 +31,11 @@ type Option struct {
 	Executor   *ExecutorOption   `json:"executor" validate:"required"`
 	Dispatcher *DispatcherOption `json:"dispatcher" validate:"required"`
 	WatchDog   *WatchDogOption   `json:"watch_dog" validate:"required"`
+
+	ScheduleTrigger *ScheduleTriggerOption `json:"schedule_trigger" validate:"required"`
+
+	// Notifier 任务流通知，为空时不发送通知
+	Notifier FlowNotifier `json:"-" validate:"omitempty"`
 }
 
 // Validate Option
@@ -93,6 +98,19 @@ func (opt WatchDogOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
+// ScheduleTriggerOption 主节点组件，负责按cron表达式触发定时任务流
+type ScheduleTriggerOption struct {
+	WatchIntervalSec   uint `json:"watch_interval_sec" validate:"required"`
+	FetcherConcurrency uint `json:"fetcher_concurrency" validate:"required"`
+	// MisfireThresholdSec 超过计划触发时间多久认为错过触发，misfire策略为skip时跳过本次触发
+	MisfireThresholdSec uint `json:"misfire_threshold_sec" validate:"required"`
+}
+
+// Validate ScheduleTriggerOption
+func (opt ScheduleTriggerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
 // SleepPolicy defines the policy of loop interval with different scenario.
 type SleepPolicy struct {
 	baseInterval time.Duration
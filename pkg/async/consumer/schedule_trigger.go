@@ -0,0 +1,237 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+/*
+ScheduleTrigger （定时触发器）: 主节点组件，定期查询到期的定时任务流，按模版创建任务流。
+
+	1.先通过CAS将下次触发时间推进到下一个周期，保证同一个触发时间只会创建一次任务流
+	2.主节点切换、服务停止期间错过的多次触发，在新的主节点上按照misfire策略合并补偿一次或者跳过
+*/
+type ScheduleTrigger interface {
+	compctrl.Closer
+	// Start 启动定时触发器
+	Start()
+}
+
+// scheduleTrigger 定时任务流触发器
+type scheduleTrigger struct {
+	bd  backend.Backend
+	pdr producer.Producer
+
+	watchIntervalSec time.Duration
+	misfireThreshold time.Duration
+	workerNumber     uint
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewScheduleTrigger 创建一个定时触发器
+func NewScheduleTrigger(bd backend.Backend, pdr producer.Producer, opt *ScheduleTriggerOption) ScheduleTrigger {
+	return &scheduleTrigger{
+		bd:               bd,
+		pdr:              pdr,
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		misfireThreshold: time.Duration(opt.MisfireThresholdSec) * time.Second,
+		workerNumber:     opt.FetcherConcurrency,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动定时触发器
+func (st *scheduleTrigger) Start() {
+	st.wg.Add(1)
+	go st.watch()
+}
+
+func (st *scheduleTrigger) watch() {
+	pool := newTenantWorkerPool(st.workerNumber, st.bd,
+		func(tenantID string) {
+			kt := NewKit()
+			kt.TenantID = tenantID
+			if err := st.triggerDueSchedules(kt, time.Now()); err != nil {
+				logs.Errorf("%s: schedule trigger failed for tenant %s, err: %v, rid: %s",
+					constant.AsyncTaskWarnSign, tenantID, err, kt.Rid)
+			}
+		})
+
+	for {
+		select {
+		case <-st.closeCh:
+			pool.shutdownPoolGracefully()
+			st.wg.Done()
+			logs.Infof("received stop signal, stop schedule trigger success.")
+			return
+		default:
+		}
+
+		if err := pool.executeWithTenant(); err != nil {
+			logs.Errorf("schedule trigger failed to executeWithTenant, err: %v", err)
+		}
+
+		time.Sleep(st.watchIntervalSec)
+	}
+}
+
+// Close 等待当前执行体执行完成后再关闭
+func (st *scheduleTrigger) Close() {
+	close(st.closeCh)
+	st.wg.Wait()
+}
+
+// triggerDueSchedules 查询已到触发时间的定时任务流并触发
+func (st *scheduleTrigger) triggerDueSchedules(kt *kit.Kit, now time.Time) error {
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "enabled", Op: filter.Equal.Factory(), Value: true},
+				&filter.AtomRule{Field: "next_fire_time", Op: filter.LessThanEqual.Factory(),
+					Value: now.UTC().Format(constant.TimeStdFormat)},
+			},
+		},
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: listDueSchedulesLimit,
+		},
+	}
+	schedules, err := st.bd.ListSchedule(kt, input)
+	if err != nil {
+		logs.Errorf("list due flow schedules failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	for _, one := range schedules {
+		// 启用状态的定时任务流创建、更新时都会计算下次触发时间，这里仅做防御
+		if len(one.NextFireTime) == 0 {
+			continue
+		}
+
+		if err = st.fire(kt, one, now); err != nil {
+			logs.Errorf("%s: fire flow schedule failed, err: %v, schedule: %s, rid: %s",
+				constant.AsyncTaskWarnSign, err, one.ID, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+// fire 触发一次定时任务流
+func (st *scheduleTrigger) fire(kt *kit.Kit, schedule model.Schedule, now time.Time) error {
+	planned, err := time.Parse(constant.TimeStdFormat, schedule.NextFireTime)
+	if err != nil {
+		return fmt.Errorf("parse next fire time failed, err: %v", err)
+	}
+
+	// 下次触发时间从当前时间开始计算，错过的多次触发只会补偿一次
+	next, err := producer.NextScheduleFireTime(schedule.CronExpr, schedule.TimeZone, now)
+	if err != nil {
+		return err
+	}
+
+	skip := schedule.MisfirePolicy == enumor.MisfireSkip && now.Sub(planned) > st.misfireThreshold
+
+	info := &backend.UpdateScheduleFireInfo{
+		ID:     schedule.ID,
+		Source: schedule.NextFireTime,
+		Target: next,
+	}
+	fireTime := now.UTC().Format(constant.TimeStdFormat)
+	if !skip {
+		info.LastFireTime = &fireTime
+	}
+	if err = st.bd.UpdateScheduleFireTimeByCAS(kt, info); err != nil {
+		if errf.Error(err).Code == errf.RecordNotUpdate {
+			// 已被其他节点触发或者已被用户修改，跳过即可
+			logs.Infof("flow schedule %s fire time has been changed, skip, rid: %s", schedule.ID, kt.Rid)
+			return nil
+		}
+		return err
+	}
+
+	if skip {
+		logs.Infof("flow schedule %s misfired at %s, skip by misfire policy, next fire time: %s, rid: %s",
+			schedule.ID, schedule.NextFireTime, next, kt.Rid)
+		return nil
+	}
+
+	tasks := make([]producer.TemplateFlowTask, 0)
+	if !schedule.Tasks.IsEmpty() {
+		if err = json.Unmarshal([]byte(schedule.Tasks), &tasks); err != nil {
+			return fmt.Errorf("unmarshal schedule tasks failed, err: %v", err)
+		}
+	}
+
+	opt := &producer.AddTemplateFlowOption{
+		Name:  schedule.FlowName,
+		Memo:  ScheduleFlowMemoPrefix + schedule.ID,
+		Tasks: tasks,
+	}
+	flowID, err := st.pdr.AddTemplateFlow(kt, opt)
+	if err != nil {
+		// 回退触发时间，等待下一轮重试
+		rollback := &backend.UpdateScheduleFireInfo{
+			ID:           schedule.ID,
+			Source:       next,
+			Target:       schedule.NextFireTime,
+			LastFireTime: &schedule.LastFireTime,
+		}
+		if rbErr := st.bd.UpdateScheduleFireTimeByCAS(kt, rollback); rbErr != nil {
+			logs.Errorf("rollback flow schedule fire time failed, err: %v, schedule: %s, rid: %s", rbErr,
+				schedule.ID, kt.Rid)
+		}
+		return fmt.Errorf("add template flow failed, err: %v", err)
+	}
+
+	record := &backend.UpdateScheduleFireInfo{
+		ID:         schedule.ID,
+		Source:     next,
+		Target:     next,
+		LastFlowID: &flowID,
+	}
+	if err = st.bd.UpdateScheduleFireTimeByCAS(kt, record); err != nil {
+		logs.Errorf("record flow schedule last flow id failed, err: %v, schedule: %s, flow: %s, rid: %s", err,
+			schedule.ID, flowID, kt.Rid)
+	}
+
+	logs.Infof("flow schedule %s fired, flow: %s, next fire time: %s, rid: %s", schedule.ID, flowID, next, kt.Rid)
+	return nil
+}
@@ -151,12 +151,14 @@ func (sch *scheduler) Start() {
 	logs.Infof("scheduler start, worker number: %d, default loop interval: %s", sch.workerNumber,
 		sch.sp.baseInterval.String())
 
-	// 定期获取等待执行的任务流
+	// 定期获取等待执行的任务流，watcher退出时同样会调用workerWg.Done
+	sch.workerWg.Add(2)
 	go sch.scheduledFlowWatcher()
 	go sch.canceledFlowWatcher()
 
 	// 启动workerNumber个协程进行后续可执行任务流的解析（第一批可执行节点之后的）
 	for i := 0; i < int(sch.workerNumber); i++ {
+		sch.workerWg.Add(1)
 		go sch.goWorker()
 	}
 }
@@ -164,7 +166,7 @@ func (sch *scheduler) Start() {
 // flowWatcher 定期查询调度到该节点的flow
 func (sch *scheduler) scheduledFlowWatcher() {
 	// 初始化协程池
-	pool := newTenantWorkerPool(sch.scheduledFlowFetcherConcurrency,
+	pool := newTenantWorkerPool(sch.scheduledFlowFetcherConcurrency, sch.backend,
 		func(tenantID string) {
 			kt := NewKit()
 			kt.TenantID = tenantID
@@ -255,7 +257,7 @@ func (sch *scheduler) queryCurrNodeAllFlow(kt *kit.Kit, state enumor.FlowState)
 // canceledFlowWatcher 查询当前节点上被取消的flow并执行task取消操作
 func (sch *scheduler) canceledFlowWatcher() {
 	// 初始化协程池
-	pool := newTenantWorkerPool(sch.canceledFlowFetcherConcurrency,
+	pool := newTenantWorkerPool(sch.canceledFlowFetcherConcurrency, sch.backend,
 		func(tenantID string) {
 			kt := NewKit()
 			kt.TenantID = tenantID
@@ -311,7 +313,7 @@ func (sch *scheduler) handleCanceledFlow(kt *kit.Kit) (working bool, err error)
 		sch.DeleteFlowTaskTree(flow.ID)
 		sch.flowTypeRunningNumMap.Inc(string(flow.Name), -1)
 		sch.flowEntryTimeMap.Delete(flow.ID)
-		err = updateFlowToCancel(kt, sch.backend, flow.ID, cvt.PtrToVal(flow.Worker), enumor.FlowCancel)
+		err = updateFlowToCancel(kt, sch.backend, flow)
 		if err != nil {
 			logs.Errorf("fail to update flow clear worker id, err: %v, flow id: %s rid: %s",
 				err, flow.ID, kt.Rid)
@@ -319,13 +321,34 @@ func (sch *scheduler) handleCanceledFlow(kt *kit.Kit) (working bool, err error)
 			continue
 		}
 
-		err := sch.executor.CancelFlow(kt, flow.ID)
+		// 需要回滚的任务流，先记录取消前各任务的状态，取消后回滚执行中的任务
+		var tasks []*Task
+		rollback := flow.Reason != nil && flow.Reason.RollbackOnCancel
+		if rollback {
+			if tasks, err = listTaskByFlowID(kt, sch.backend, flow.ID); err != nil {
+				logs.Errorf("fail to list task of canceled flow, err: %v, flow id: %s, rid: %s", err, flow.ID,
+					kt.Rid)
+				rollback = false
+			}
+		}
+
+		err = sch.executor.CancelFlow(kt, flow.ID)
 		if err != nil {
 			logs.Errorf("fail to handle flow canceling, err: %v, flow id: %s, rid: %s", err, flow.ID, kt.Rid)
 			// keep canceling other flow
 			continue
 		}
 
+		if rollback {
+			// 取消只会通知任务退出，需等待执行中的任务返回后再回滚，避免回滚与任务执行并发
+			if err = sch.executor.WaitFlowTasks(kt, flow.ID); err != nil {
+				logs.Errorf("%s: wait running tasks of canceled flow failed, skip rollback, err: %v, flow id: %s, "+
+					"rid: %s", constant.AsyncTaskWarnSign, err, flow.ID, kt.Rid)
+				continue
+			}
+			rollbackCanceledTasks(kt, flow, tasks)
+		}
+
 		logs.Infof("cancel flow: %s success, rid: %s", flow.ID, kt.Rid)
 	}
 
@@ -515,16 +538,24 @@ func updateFlowStateAndReason(kt *kit.Kit, bd backend.Backend, flowID string, so
 }
 
 // updateFlowToCancel 状态改为取消，清空 worker字段,
-func updateFlowToCancel(kt *kit.Kit, bd backend.Backend, flowId, oldWorkerID string, source enumor.FlowState) error {
+func updateFlowToCancel(kt *kit.Kit, bd backend.Backend, flow model.Flow) error {
+	flowId := flow.ID
+	source := enumor.FlowCancel
+
+	reason := &tableasync.Reason{
+		Message:  "canceled from " + cvt.PtrToVal(flow.Worker),
+		PreState: string(source),
+	}
+	// 需要回滚的任务流（如超过截止时间被取消），保留原取消原因
+	if flow.Reason != nil && flow.Reason.RollbackOnCancel {
+		reason = flow.Reason
+	}
 
 	info := backend.UpdateFlowInfo{
 		ID:     flowId,
 		Source: source,
 		Target: enumor.FlowCancel,
-		Reason: &tableasync.Reason{
-			Message:  "canceled from " + oldWorkerID,
-			PreState: string(source),
-		},
+		Reason: reason,
 		Worker: cvt.ValToPtr(""),
 	}
 
@@ -700,8 +731,6 @@ func (sch *scheduler) caculateFlowTypeScore(flow model.Flow) float64 {
 
 // 任务流解析协程
 func (sch *scheduler) goWorker() {
-	sch.workerWg.Add(1)
-
 	for {
 		task, ok := sch.workerQueue.Pop()
 		if !ok {
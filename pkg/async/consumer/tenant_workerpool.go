@@ -25,6 +25,7 @@ import (
 
 	actcli "hcm/cmd/task-server/logics/action/cli"
 	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/logs"
 )
@@ -32,15 +33,17 @@ import (
 // tenantWorkerPool 租户协程池，用于并发处理租户ID的任务。每个协程从chan中获取租户ID并执行指定的消费租户ID的工作函数
 type tenantWorkerPool struct {
 	workerNum  uint
+	bd         backend.Backend
 	taskChan   chan string
 	workerFunc func(tenantID string)
 	wg         sync.WaitGroup
 }
 
 // newTenantWorkerPool 创建协程池并立即启动workerNum个工作协程，workerFunc是工作协程的执行函数，要求能够接收租户id
-func newTenantWorkerPool(workerNum uint, workerFunc func(tenantID string)) *tenantWorkerPool {
+func newTenantWorkerPool(workerNum uint, bd backend.Backend, workerFunc func(tenantID string)) *tenantWorkerPool {
 	pool := &tenantWorkerPool{
 		workerNum:  workerNum,
+		bd:         bd,
 		taskChan:   make(chan string, workerNum),
 		workerFunc: workerFunc,
 	}
@@ -74,9 +77,13 @@ func (wp *tenantWorkerPool) executeWithTenant() error {
 	return nil
 }
 
-// listTenantIDs 获取所有租户ID
+// listTenantIDs 获取所有租户ID，backend可以提供租户列表时(如内存backend)直接使用，否则从租户表获取
 func (wp *tenantWorkerPool) listTenantIDs() ([]string, error) {
 	kt := NewKit()
+	if lister, ok := wp.bd.(backend.TenantLister); ok {
+		return lister.ListTenantIDs(kt)
+	}
+
 	tenantIDs := make([]string, 0)
 	page := core.NewDefaultBasePage()
 	for {
@@ -49,12 +49,23 @@ const (
 	ErrTaskNodeShutdown = "task node shutdown"
 	// ErrSomeTaskExecFailed 部分任务执行失败
 	ErrSomeTaskExecFailed = "some tasks failed to be executed"
+	// ErrFlowDeadlineExceeded 任务流超过截止时间
+	ErrFlowDeadlineExceeded = "flow deadline exceeded"
 
 	//  listScheduledFlowLimit 每次调度器查询分配给当前节点的任务流数量
 	listScheduledFlowLimit = 20
 
 	// listExpiredTasksLimit 每次WatchDog查询超时任务的数量
 	listExpiredTasksLimit = 100
+
+	// listDeadlineExceededFlowLimit 每次WatchDog查询超过截止时间任务流的数量
+	listDeadlineExceededFlowLimit = 100
+
+	// listDueSchedulesLimit 每次定时触发器查询到期定时任务流的数量
+	listDueSchedulesLimit = 100
+
+	// ScheduleFlowMemoPrefix 定时任务流创建的任务流备注前缀，后接定时任务流ID
+	ScheduleFlowMemoPrefix = "schedule:"
 )
 
 // Flow 消费所需的异步任务流。
@@ -46,6 +46,7 @@ WatchDog （看门狗）:
 	1.处理超时任务
 	2.处理处于Scheduled状态，但执行节点已经挂掉的任务流
 	3.处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
+	4.取消超过截止时间仍未结束的任务流，并发送通知
 */
 type WatchDog interface {
 	compctrl.Closer
@@ -55,8 +56,9 @@ type WatchDog interface {
 
 // watchDog 任务流、任务纠正策略
 type watchDog struct {
-	bd backend.Backend
-	ld leader.Leader
+	bd       backend.Backend
+	ld       leader.Leader
+	notifier FlowNotifier
 
 	taskTimeoutSec      time.Duration
 	shutdownWaitTimeSec time.Duration
@@ -70,11 +72,12 @@ type watchDog struct {
 }
 
 // NewWatchDog 创建一个watchdog
-func NewWatchDog(bd backend.Backend, ld leader.Leader, opt *WatchDogOption) WatchDog {
+func NewWatchDog(bd backend.Backend, ld leader.Leader, notifier FlowNotifier, opt *WatchDogOption) WatchDog {
 
 	return &watchDog{
 		bd:                  bd,
 		ld:                  ld,
+		notifier:            notifier,
 		taskTimeoutSec:      time.Duration(opt.TaskRunTimeoutSec) * time.Second,
 		shutdownWaitTimeSec: time.Duration(opt.ShutdownWaitTimeSec) * time.Second,
 		watchIntervalSec:    time.Duration(opt.WatchIntervalSec) * time.Second,
@@ -93,12 +96,14 @@ func (wd *watchDog) Start() {
 	go wd.watchWrapper(wd.handleScheduledNotExistWorkerFlow)
 	wd.wg.Add(1)
 	go wd.watchWrapper(wd.handleRunningNotExistWorkerFlow)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleDeadlineExceededFlows)
 }
 
 // 定期处理异常任务流或任务
 func (wd *watchDog) watchWrapper(do func(kt *kit.Kit) error) {
 	// 初始化协程池
-	pool := newTenantWorkerPool(wd.workerNumber,
+	pool := newTenantWorkerPool(wd.workerNumber, wd.bd,
 		func(tenantID string) {
 			kt := NewKit()
 			kt.TenantID = tenantID
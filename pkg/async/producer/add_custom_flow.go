@@ -21,6 +21,7 @@ package producer
 
 import (
 	"fmt"
+	"time"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend/model"
@@ -112,6 +113,7 @@ func buildCustomFlow(opt *AddCustomFlowOption) *model.Flow {
 		Name:      opt.Name,
 		ShareData: opt.ShareData,
 		Memo:      opt.Memo,
+		Deadline:  opt.FlowDeadline.resolve(time.Now()),
 		Tasks:     make([]model.Task, 0, len(opt.Tasks)),
 	}
 	if opt.IsInitState {
@@ -21,6 +21,7 @@ package producer
 
 import (
 	"fmt"
+	"time"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend/model"
@@ -63,6 +64,7 @@ func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow
 		Name:      tpl.Name,
 		ShareData: tpl.ShareData,
 		Memo:      opt.Memo,
+		Deadline:  opt.FlowDeadline.resolve(time.Now()),
 		Tasks:     make([]model.Task, 0, len(tpl.Tasks)),
 	}
 	if opt.IsInitState {
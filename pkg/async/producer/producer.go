@@ -36,6 +36,9 @@ type Producer interface {
 	BatchUpdateCustomFlowState(kt *kit.Kit, opt *UpdateCustomFlowStateOption) error
 	RetryFlowTask(kt *kit.Kit, flowID, taskID string) error
 	CloneFlow(kt *kit.Kit, flowId string, opt *CloneFlowOption) (id string, err error)
+	CreateFlowSchedule(kt *kit.Kit, opt *CreateFlowScheduleOption) (id string, err error)
+	UpdateFlowSchedule(kt *kit.Kit, opt *UpdateFlowScheduleOption) error
+	DeleteFlowSchedule(kt *kit.Kit, id string) error
 }
 
 var _ Producer = new(producer)
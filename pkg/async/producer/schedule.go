@@ -0,0 +1,235 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/cron"
+)
+
+// CreateFlowSchedule create flow schedule, 定时任务流由主节点按cron表达式周期性创建模版任务流
+func (p *producer) CreateFlowSchedule(kt *kit.Kit, opt *CreateFlowScheduleOption) (id string, err error) {
+	if err = opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = validateScheduleTasks(kt, opt.FlowName, opt.Tasks); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	enabled := true
+	if opt.Enabled != nil {
+		enabled = *opt.Enabled
+	}
+
+	nextFireTime := ""
+	if enabled {
+		nextFireTime, err = NextScheduleFireTime(opt.CronExpr, opt.TimeZone, time.Now())
+	} else {
+		_, _, err = parseSchedule(opt.CronExpr, opt.TimeZone)
+	}
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tasks, err := types.NewJsonField(opt.Tasks)
+	if err != nil {
+		return "", err
+	}
+
+	policy := opt.MisfirePolicy
+	if len(policy) == 0 {
+		policy = enumor.MisfireFireOnce
+	}
+
+	schedule := &model.Schedule{
+		Name:          opt.Name,
+		FlowName:      opt.FlowName,
+		CronExpr:      opt.CronExpr,
+		TimeZone:      opt.TimeZone,
+		Tasks:         tasks,
+		Enabled:       &enabled,
+		MisfirePolicy: policy,
+		NextFireTime:  nextFireTime,
+		Memo:          opt.Memo,
+	}
+	id, err = p.backend.CreateSchedule(kt, schedule)
+	if err != nil {
+		logs.Errorf("create flow schedule failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// UpdateFlowSchedule update flow schedule, cron表达式、时区变更或重新启用时会重新计算下次触发时间
+func (p *producer) UpdateFlowSchedule(kt *kit.Kit, opt *UpdateFlowScheduleOption) error {
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	old, err := p.getSchedule(kt, opt.ID)
+	if err != nil {
+		return err
+	}
+
+	schedule := &model.Schedule{
+		ID:            opt.ID,
+		Name:          opt.Name,
+		CronExpr:      opt.CronExpr,
+		Enabled:       opt.Enabled,
+		MisfirePolicy: opt.MisfirePolicy,
+		Memo:          opt.Memo,
+	}
+
+	if opt.Tasks != nil {
+		if err = validateScheduleTasks(kt, old.FlowName, opt.Tasks); err != nil {
+			return errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if schedule.Tasks, err = types.NewJsonField(opt.Tasks); err != nil {
+			return err
+		}
+	}
+
+	cronExpr, timeZone := old.CronExpr, old.TimeZone
+	if len(opt.CronExpr) != 0 {
+		cronExpr = opt.CronExpr
+	}
+	if opt.TimeZone != nil {
+		timeZone = *opt.TimeZone
+		schedule.TimeZone = timeZone
+	}
+	if _, _, err = parseSchedule(cronExpr, timeZone); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	oldEnabled := converter.PtrToVal(old.Enabled)
+	enabled := oldEnabled
+	if opt.Enabled != nil {
+		enabled = *opt.Enabled
+	}
+
+	// 调度规则变化或者从禁用变为启用时，从当前时间重新计算下次触发时间，禁用期间错过的触发不做补偿
+	if enabled && (!oldEnabled || cronExpr != old.CronExpr || timeZone != old.TimeZone) {
+		if schedule.NextFireTime, err = NextScheduleFireTime(cronExpr, timeZone, time.Now()); err != nil {
+			return errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	if err = p.backend.UpdateSchedule(kt, schedule); err != nil {
+		logs.Errorf("update flow schedule failed, err: %v, id: %s, rid: %s", err, opt.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFlowSchedule delete flow schedule, 已经创建的任务流不受影响
+func (p *producer) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+	if _, err := p.getSchedule(kt, id); err != nil {
+		return err
+	}
+
+	if err := p.backend.DeleteSchedule(kt, id); err != nil {
+		logs.Errorf("delete flow schedule failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (p *producer) getSchedule(kt *kit.Kit, id string) (*model.Schedule, error) {
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	schedules, err := p.backend.ListSchedule(kt, input)
+	if err != nil {
+		logs.Errorf("list flow schedule failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(schedules) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow schedule: %s not found", id)
+	}
+
+	return &schedules[0], nil
+}
+
+// validateScheduleTasks 校验定时任务流的模版存在且任务参数满足模版要求
+func validateScheduleTasks(kt *kit.Kit, flowName enumor.FlowName, tasks []TemplateFlowTask) error {
+	tpl, exist := action.GetTpl(flowName)
+	if !exist {
+		return fmt.Errorf("flow tempalte: %s not found", flowName)
+	}
+
+	return validateTplUseParam(kt, tpl, &AddTemplateFlowOption{Name: flowName, Tasks: tasks})
+}
+
+func parseSchedule(cronExpr, timeZone string) (*cron.Schedule, *time.Location, error) {
+	sched, err := cron.Parse(cronExpr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc := time.UTC
+	if len(timeZone) != 0 {
+		if loc, err = time.LoadLocation(timeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone: %s, err: %v", timeZone, err)
+		}
+	}
+
+	return sched, loc, nil
+}
+
+// NextScheduleFireTime 计算 after 之后的下一次触发时间，cron表达式按 timeZone 时区解释，返回UTC时间的
+// constant.TimeStdFormat 格式字符串
+func NextScheduleFireTime(cronExpr, timeZone string, after time.Time) (string, error) {
+	sched, loc, err := parseSchedule(cronExpr, timeZone)
+	if err != nil {
+		return "", err
+	}
+
+	next := sched.Next(after.In(loc))
+	if next.IsZero() {
+		return "", fmt.Errorf("cron expr: %s will never fire", cronExpr)
+	}
+
+	return next.UTC().Format(constant.TimeStdFormat), nil
+}
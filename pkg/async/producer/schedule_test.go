@@ -0,0 +1,46 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextScheduleFireTime(t *testing.T) {
+	after := time.Date(2026, 10, 18, 17, 30, 0, 0, time.UTC)
+
+	// 北京时间每天2点触发，对应UTC前一天18点
+	next, err := NextScheduleFireTime("0 2 * * *", "Asia/Shanghai", after)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-18T18:00:00Z", next)
+
+	next, err = NextScheduleFireTime("0 2 * * *", "", after)
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-19T02:00:00Z", next)
+
+	_, err = NextScheduleFireTime("0 2 * * *", "Mars/Olympus", after)
+	assert.Error(t, err)
+
+	_, err = NextScheduleFireTime("0 0 30 2 *", "", after)
+	assert.Error(t, err)
+}
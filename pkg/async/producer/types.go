@@ -21,9 +21,12 @@ package producer
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
@@ -40,6 +43,8 @@ type AddTemplateFlowOption struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// FlowDeadline 任务流截止时间设置
+	FlowDeadline `json:",inline"`
 }
 
 // Validate AddTemplateFlowOption
@@ -53,6 +58,10 @@ func (opt *AddTemplateFlowOption) Validate() error {
 		return err
 	}
 
+	if err := opt.FlowDeadline.Validate(); err != nil {
+		return err
+	}
+
 	for index := range opt.Tasks {
 		if err := opt.Tasks[index].Validate(); err != nil {
 			return err
@@ -87,6 +96,8 @@ type AddCustomFlowOption struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"required"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// FlowDeadline 任务流截止时间设置
+	FlowDeadline `json:",inline"`
 }
 
 // Validate AddCustomFlowOption
@@ -96,6 +107,10 @@ func (opt *AddCustomFlowOption) Validate() error {
 		return err
 	}
 
+	if err := opt.FlowDeadline.Validate(); err != nil {
+		return err
+	}
+
 	if len(opt.Tasks) == 0 {
 		return errors.New("tasks is required")
 	}
@@ -109,6 +124,50 @@ func (opt *AddCustomFlowOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
+// FlowDeadline 任务流截止时间设置，超过截止时间仍未结束的任务流会被取消，执行中的任务会被回滚，同时发送通知
+type FlowDeadline struct {
+	// Deadline 截止时间，标准格式：2006-01-02T15:04:05Z
+	Deadline string `json:"deadline" validate:"omitempty"`
+	// TimeoutSec 任务流从创建开始允许的最长运行时间，与Deadline同时设置时取较早者
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+}
+
+// Validate FlowDeadline
+func (d FlowDeadline) Validate() error {
+	if len(d.Deadline) == 0 {
+		return nil
+	}
+
+	if _, err := time.Parse(constant.TimeStdFormat, d.Deadline); err != nil {
+		return fmt.Errorf("invalid deadline: %s, should be like: %s", d.Deadline, constant.TimeStdFormat)
+	}
+
+	return nil
+}
+
+// resolve 计算任务流截止时间，返回UTC时间的 constant.TimeStdFormat 格式，未设置时返回nil
+func (d FlowDeadline) resolve(now time.Time) *string {
+	var deadline time.Time
+	if len(d.Deadline) != 0 {
+		// 已经在Validate中校验过格式
+		deadline, _ = time.Parse(constant.TimeStdFormat, d.Deadline)
+	}
+
+	if d.TimeoutSec != 0 {
+		timeout := now.Add(time.Duration(d.TimeoutSec) * time.Second)
+		if deadline.IsZero() || timeout.Before(deadline) {
+			deadline = timeout
+		}
+	}
+
+	if deadline.IsZero() {
+		return nil
+	}
+
+	result := deadline.UTC().Format(constant.TimeStdFormat)
+	return &result
+}
+
 // CustomFlowTask define custom flow task info.
 type CustomFlowTask struct {
 	// ActionID Action唯一序列号
@@ -171,3 +230,84 @@ func (opt *CloneFlowOption) Validate() error {
 
 	return validator.Validate.Struct(opt)
 }
+
+// CreateFlowScheduleOption define create flow schedule option.
+type CreateFlowScheduleOption struct {
+	// Name 定时任务名称
+	Name string `json:"name" validate:"required,lte=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// CronExpr 5段式cron表达式，如 "0 2 * * *"
+	CronExpr string `json:"cron_expr" validate:"required,lte=64"`
+	// TimeZone cron表达式所在时区，如 "Asia/Shanghai"，为空时使用UTC
+	TimeZone string `json:"time_zone" validate:"omitempty,lte=64"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+	// Enabled 是否启用，默认启用
+	Enabled *bool `json:"enabled" validate:"omitempty"`
+	// MisfirePolicy 错过触发时间后的处理策略，默认补偿触发一次
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	// Memo 备注
+	Memo *string `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate CreateFlowScheduleOption
+func (opt *CreateFlowScheduleOption) Validate() error {
+
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if err := opt.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	if len(opt.MisfirePolicy) != 0 {
+		if err := opt.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for index := range opt.Tasks {
+		if err := opt.Tasks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateFlowScheduleOption define update flow schedule option.
+type UpdateFlowScheduleOption struct {
+	ID       string  `json:"id" validate:"required"`
+	Name     string  `json:"name" validate:"omitempty,lte=64"`
+	CronExpr string  `json:"cron_expr" validate:"omitempty,lte=64"`
+	TimeZone *string `json:"time_zone" validate:"omitempty,lte=64"`
+	// Tasks 为nil时不更新任务参数
+	Tasks         []TemplateFlowTask   `json:"tasks" validate:"omitempty"`
+	Enabled       *bool                `json:"enabled" validate:"omitempty"`
+	MisfirePolicy enumor.MisfirePolicy `json:"misfire_policy" validate:"omitempty"`
+	Memo          *string              `json:"memo" validate:"omitempty,lte=255"`
+}
+
+// Validate UpdateFlowScheduleOption
+func (opt *UpdateFlowScheduleOption) Validate() error {
+
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.MisfirePolicy) != 0 {
+		if err := opt.MisfirePolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for index := range opt.Tasks {
+		if err := opt.Tasks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowDeadlineResolve(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, FlowDeadline{}.resolve(now))
+
+	deadline := FlowDeadline{TimeoutSec: 600}.resolve(now)
+	assert.Equal(t, "2026-10-18T10:10:00Z", *deadline)
+
+	// 截止时间与超时时间同时设置时取较早者
+	deadline = FlowDeadline{Deadline: "2026-10-18T10:05:00Z", TimeoutSec: 600}.resolve(now)
+	assert.Equal(t, "2026-10-18T10:05:00Z", *deadline)
+
+	deadline = FlowDeadline{Deadline: "2026-10-18T18:30:00+08:00", TimeoutSec: 3600}.resolve(now)
+	assert.Equal(t, "2026-10-18T10:30:00Z", *deadline)
+
+	assert.Error(t, FlowDeadline{Deadline: "2026-10-18 10:05:00"}.Validate())
+}
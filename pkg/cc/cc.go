@@ -63,6 +63,11 @@ func (r *runtime) Ready() bool {
 
 // TenantEnable return tenant enable.
 func TenantEnable() bool {
+	// 未加载配置(如单元测试中)时按未开启多租户处理
+	if rt == nil {
+		return false
+	}
+
 	rt.lock.Lock()
 	defer rt.lock.Unlock()
 
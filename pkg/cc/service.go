@@ -135,6 +135,11 @@ type CloudServerSetting struct {
 	CloudResource    CloudResource    `yaml:"cloudResource"`
 	Recycle          Recycle          `yaml:"recycle"`
 	BillConfig       BillConfig       `yaml:"billConfig"`
+	Approval         Approval         `yaml:"approval"`
+	SGCompliance     SGCompliance     `yaml:"sgCompliance"`
+	IdleResource     IdleResource     `yaml:"idleResource"`
+	CertExpiry       CertExpiry       `yaml:"certExpiry"`
+	TargetHealth     TargetHealth     `yaml:"targetHealth"`
 	Itsm             ApiGateway       `yaml:"itsm"`
 	CloudSelection   CloudSelection   `yaml:"cloudSelection"`
 	Cmsi             CMSI             `yaml:"cmsi"`
@@ -157,6 +162,11 @@ func (s *CloudServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.ConcurrentConfig.trySetDefault()
+	s.Approval.trySetDefault()
+	s.SGCompliance.trySetDefault()
+	s.IdleResource.trySetDefault()
+	s.CertExpiry.trySetDefault()
+	s.TargetHealth.trySetDefault()
 	if s.TmpFileDir == "" {
 		s.TmpFileDir = "/tmp"
 	}
@@ -202,10 +212,33 @@ func (s CloudServerSetting) Validate() error {
 		return err
 	}
 
-	if err := s.Itsm.validate(); err != nil {
+	if err := s.Approval.validate(); err != nil {
 		return err
 	}
 
+	if err := s.SGCompliance.validate(); err != nil {
+		return err
+	}
+
+	if err := s.IdleResource.validate(); err != nil {
+		return err
+	}
+
+	if err := s.CertExpiry.validate(); err != nil {
+		return err
+	}
+
+	if err := s.TargetHealth.validate(); err != nil {
+		return err
+	}
+
+	// 使用内置审批引擎时无需配置ITSM
+	if !s.Approval.IsNative() {
+		if err := s.Itsm.validate(); err != nil {
+			return err
+		}
+	}
+
 	if err := s.Cmsi.validate(); err != nil {
 		return err
 	}
@@ -232,6 +265,7 @@ type DataServiceSetting struct {
 	Crypto      Crypto       `yaml:"crypto"`
 	Cmdb        ApiGateway   `yaml:"cmdb"`
 	Tenant      TenantConfig `yaml:"tenant"`
+	AuditExport AuditExport  `yaml:"auditExport"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -245,6 +279,7 @@ func (s *DataServiceSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.Database.trySetDefault()
+	s.AuditExport.trySetDefault()
 
 	return
 }
@@ -271,6 +306,10 @@ func (s DataServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.AuditExport.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -288,6 +327,8 @@ type HCServiceSetting struct {
 	Tenant        TenantConfig `yaml:"tenant"`
 	Cmdb          ApiGateway   `yaml:"cmdb"`
 	CCHostPoolBiz int64        `yaml:"ccHostPoolBiz"`
+
+	CloudApiLimit CloudApiLimit `yaml:"cloudApiLimit"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -301,6 +342,7 @@ func (s *HCServiceSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.SyncConfig.trySetDefault()
+	s.CloudApiLimit.trySetDefault()
 
 	return
 }
@@ -327,6 +369,10 @@ func (s HCServiceSetting) Validate() error {
 		return fmt.Errorf("ccHostPoolBiz should not be empty")
 	}
 
+	if err := s.CloudApiLimit.Validate(); err != nil {
+		return fmt.Errorf("cloudApiLimit validate error: %w", err)
+	}
+
 	return nil
 }
 
@@ -398,12 +444,16 @@ type WebServerSetting struct {
 	Log           LogOption     `yaml:"log"`
 	Web           Web           `yaml:"web"`
 	Esb           Esb           `yaml:"esb"`
+	Approval      Approval      `yaml:"approval"`
 	Itsm          ApiGateway    `yaml:"itsm"`
 	ChangeLogPath ChangeLogPath `yaml:"changeLogPath"`
 	Notice        Notice        `yaml:"notice"`
 	TemplatePath  string        `yaml:"templatePath"`
 	Tenant        TenantConfig  `yaml:"tenant"`
 	Cmdb          ApiGateway    `yaml:"cmdb"`
+	// LocalObjectStore 本地文件存储的预签名URL服务配置，需与 data-service 的 objectstore.local 配置一致，
+	// rootDir 为空时不提供该服务
+	LocalObjectStore ObjectStoreLocal `yaml:"localObjectStore"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -417,6 +467,7 @@ func (s *WebServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.ChangeLogPath.trySetDefault()
+	s.Approval.trySetDefault()
 	if len(s.TemplatePath) == 0 {
 		s.TemplatePath = "template"
 	}
@@ -447,14 +498,27 @@ func (s WebServerSetting) Validate() error {
 		return err
 	}
 
-	if err := s.Itsm.validate(); err != nil {
+	if err := s.Approval.validate(); err != nil {
 		return err
 	}
 
+	// 使用内置审批引擎时无需配置ITSM
+	if !s.Approval.IsNative() {
+		if err := s.Itsm.validate(); err != nil {
+			return err
+		}
+	}
+
 	if err := s.Notice.validate(); err != nil {
 		return err
 	}
 
+	if len(s.LocalObjectStore.RootDir) != 0 {
+		if err := s.LocalObjectStore.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -476,6 +540,8 @@ type TaskServerSetting struct {
 	Log      LogOption    `yaml:"log"`
 	Async    Async        `yaml:"async"`
 	Tenant   TenantConfig `yaml:"tenant"`
+	// Cmsi 用于发送任务流通知，开启 async.notice 时需要配置
+	Cmsi CMSI `yaml:"cmsi"`
 
 	UseLabel LabelSwitch `yaml:"useLabel"`
 }
@@ -510,6 +576,12 @@ func (s TaskServerSetting) Validate() error {
 		return err
 	}
 
+	if s.Async.Notice.Enabled {
+		if err := s.Cmsi.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
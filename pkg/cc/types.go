@@ -166,10 +166,18 @@ func (lm *Limiter) trySetDefault() {
 
 // Async defines async relating.
 type Async struct {
+	// Backend 异步任务框架的后端存储类型，支持mysql、memory，默认为mysql
+	Backend enumor.BackendType `yaml:"backend"`
+	// Leader 异步任务框架的选主方式，支持etcd、mysql、static，默认为etcd
+	Leader enumor.LeaderType `yaml:"leader"`
+
 	Scheduler  Parser     `yaml:"scheduler"`
 	Executor   Executor   `yaml:"executor"`
 	Dispatcher Dispatcher `yaml:"dispatcher"`
 	WatchDog   WatchDog   `yaml:"watchDog"`
+
+	ScheduleTrigger ScheduleTrigger `yaml:"scheduleTrigger"`
+	Notice          AsyncNotice     `yaml:"notice"`
 }
 
 // Validate Async
@@ -180,6 +188,12 @@ func (a Async) Validate() error {
 
 // trySetDefault try set the default value of Async
 func (s *Async) trySetDefault() {
+	if len(s.Backend) == 0 {
+		s.Backend = enumor.BackendMysql
+	}
+	if len(s.Leader) == 0 {
+		s.Leader = enumor.LeaderEtcd
+	}
 	if s.Executor.InitQueueCapacity == 0 {
 		s.Executor.InitQueueCapacity = 25
 	}
@@ -201,6 +215,15 @@ func (s *Async) trySetDefault() {
 	if s.Executor.SlowTaskQueueCapacity == 0 {
 		s.Executor.SlowTaskQueueCapacity = 10
 	}
+	if s.ScheduleTrigger.WatchIntervalSec == 0 {
+		s.ScheduleTrigger.WatchIntervalSec = 10
+	}
+	if s.ScheduleTrigger.FetcherConcurrency == 0 {
+		s.ScheduleTrigger.FetcherConcurrency = 5
+	}
+	if s.ScheduleTrigger.MisfireThresholdSec == 0 {
+		s.ScheduleTrigger.MisfireThresholdSec = 60
+	}
 }
 
 // Parser 公共组件，负责获取分配给当前节点的任务流，并解析成任务树后，派发当前要执行的任务给executor执行
@@ -238,6 +261,19 @@ type WatchDog struct {
 	WorkerNumber     uint `yaml:"workerNumber"`
 }
 
+// ScheduleTrigger 主节点组件，负责按cron表达式触发定时任务流
+type ScheduleTrigger struct {
+	WatchIntervalSec    uint `yaml:"watchIntervalSec"`
+	FetcherConcurrency  uint `yaml:"fetcherConcurrency"`
+	MisfireThresholdSec uint `yaml:"misfireThresholdSec"`
+}
+
+// AsyncNotice 任务流通知配置，如任务流超过截止时间被取消后，通过CMSI发送邮件给任务流创建者及指定接收人
+type AsyncNotice struct {
+	Enabled   bool     `yaml:"enabled"`
+	Receivers []string `yaml:"receivers"`
+}
+
 // DataBase defines database related runtime
 type DataBase struct {
 	Resource ResourceDB `yaml:"resource"`
@@ -648,6 +684,8 @@ func (a AesGcm) validate() error {
 // TODO: 这里默认只支持AES Gcm算法，后续需要支持国密等的选择，可能还需要支持根据不同场景配置不同（比如不同场景，加密的密钥等都不一样）
 type Crypto struct {
 	AesGcm AesGcm `yaml:"aesGcm"`
+	// Keyring 多版本密钥环，配置后新数据使用当前版本密钥加密并带上版本标识，aesGcm 仅用于解密不带版本标识的历史数据
+	Keyring AesGcmKeyring `yaml:"keyring"`
 }
 
 func (c Crypto) validate() error {
@@ -655,9 +693,60 @@ func (c Crypto) validate() error {
 		return err
 	}
 
+	if err := c.Keyring.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AesGcmKeyring Aes Gcm 多版本密钥环，用于密钥轮换
+type AesGcmKeyring struct {
+	// CurrentVersion 当前用于加密的密钥版本
+	CurrentVersion uint `yaml:"currentVersion"`
+	// Keys 所有密钥，包括当前密钥和仍需用于解密的历史密钥
+	Keys []AesGcmVersionKey `yaml:"keys"`
+}
+
+// Enabled 是否配置了密钥环
+func (a AesGcmKeyring) Enabled() bool {
+	return len(a.Keys) > 0
+}
+
+func (a AesGcmKeyring) validate() error {
+	if !a.Enabled() {
+		return nil
+	}
+
+	versions := make(map[uint]struct{}, len(a.Keys))
+	for _, key := range a.Keys {
+		if key.Version == 0 {
+			return errors.New("keyring key version should be greater than 0")
+		}
+
+		if _, exists := versions[key.Version]; exists {
+			return fmt.Errorf("keyring key version %d is duplicated", key.Version)
+		}
+		versions[key.Version] = struct{}{}
+
+		if len(key.Key) != 16 && len(key.Key) != 32 {
+			return fmt.Errorf("invalid keyring key of version %d, should be 16 or 32 bytes", key.Version)
+		}
+	}
+
+	if _, exists := versions[a.CurrentVersion]; !exists {
+		return fmt.Errorf("keyring current version %d is not in keys", a.CurrentVersion)
+	}
+
 	return nil
 }
 
+// AesGcmVersionKey 带版本的 Aes Gcm 密钥，nonce 在每次加密时随机生成
+type AesGcmVersionKey struct {
+	Version uint   `yaml:"version"`
+	Key     string `yaml:"key"`
+}
+
 // CloudResource 云资源配置
 type CloudResource struct {
 	Sync CloudResourceSync `yaml:"sync"`
@@ -691,6 +780,8 @@ func (c CloudResourceSync) validate() error {
 // Recycle configuration.
 type Recycle struct {
 	AutoDeleteTime uint `yaml:"autoDeleteTimeHour"`
+	// NotifyBeforeHour 资源销毁前多少小时通知回收人，没有适用的回收站保留策略时使用，为0时不通知
+	NotifyBeforeHour uint `yaml:"notifyBeforeHour"`
 }
 
 func (a Recycle) validate() error {
@@ -701,6 +792,259 @@ func (a Recycle) validate() error {
 	return nil
 }
 
+// Approval 申请单审批配置
+type Approval struct {
+	// Engine 审批引擎，itsm 使用蓝鲸ITSM审批，native 使用内置审批引擎，默认为 itsm
+	Engine enumor.ApplicationSource `yaml:"engine"`
+	// RemindIntervalMin 内置审批引擎的审批单超过该时间未处理时再次通知审批人，单位分钟，为0时不提醒
+	RemindIntervalMin uint `yaml:"remindIntervalMin"`
+}
+
+func (a *Approval) trySetDefault() {
+	if len(a.Engine) == 0 {
+		a.Engine = enumor.ApplicationSourceITSM
+	}
+}
+
+func (a Approval) validate() error {
+	return a.Engine.Validate()
+}
+
+// IsNative 是否使用内置审批引擎
+func (a Approval) IsNative() bool {
+	return a.Engine == enumor.ApplicationSourceNative
+}
+
+// SGCompliance 安全组规则合规检查配置
+type SGCompliance struct {
+	// Enforce 是否在创建、更新安全组规则时拦截违规规则
+	Enforce bool `yaml:"enforce"`
+	// EnforceSeverity 开启拦截时，等级不低于该值的问题会被拦截，默认为 high
+	EnforceSeverity enumor.SGComplianceSeverity `yaml:"enforceSeverity"`
+	// DisabledBuiltinChecks 禁用的内置检查项
+	DisabledBuiltinChecks []enumor.SGComplianceBuiltinCheck `yaml:"disabledBuiltinChecks"`
+	// SensitivePorts 不允许对全部地址开放的高危端口
+	SensitivePorts []uint32 `yaml:"sensitivePorts"`
+	// MaxPortRangeSize 单条规则允许开放的最大端口数量，超过则视为端口范围过宽
+	MaxPortRangeSize uint32 `yaml:"maxPortRangeSize"`
+}
+
+func (s *SGCompliance) trySetDefault() {
+	if len(s.EnforceSeverity) == 0 {
+		s.EnforceSeverity = enumor.SGComplianceHigh
+	}
+
+	if len(s.SensitivePorts) == 0 {
+		s.SensitivePorts = []uint32{22, 3389, 3306, 5432, 1433, 1521, 6379, 27017, 9200, 11211}
+	}
+
+	if s.MaxPortRangeSize == 0 {
+		s.MaxPortRangeSize = 1000
+	}
+}
+
+func (s SGCompliance) validate() error {
+	if err := s.EnforceSeverity.Validate(); err != nil {
+		return fmt.Errorf("sgCompliance.enforceSeverity is invalid, err: %v", err)
+	}
+
+	for _, check := range s.DisabledBuiltinChecks {
+		if err := check.Validate(); err != nil {
+			return fmt.Errorf("sgCompliance.disabledBuiltinChecks is invalid, err: %v", err)
+		}
+	}
+
+	for _, port := range s.SensitivePorts {
+		if port > 65535 {
+			return fmt.Errorf("sgCompliance.sensitivePorts %d is invalid", port)
+		}
+	}
+
+	return nil
+}
+
+// IdleResource 闲置资源分析配置
+type IdleResource struct {
+	// Enable 是否开启闲置资源定期分析
+	Enable bool `yaml:"enable"`
+	// AnalyzeIntervalMin 闲置资源分析间隔，单位分钟，默认为 360
+	AnalyzeIntervalMin uint `yaml:"analyzeIntervalMin"`
+	// CostDays 统计闲置资源最近多少天的账单费用，默认为 30
+	CostDays uint `yaml:"costDays"`
+}
+
+// maxIdleCostDays 统计闲置资源费用的最大天数，账单按月分表，限制查询范围避免跨越过多月份
+const maxIdleCostDays = 90
+
+func (i *IdleResource) trySetDefault() {
+	if i.AnalyzeIntervalMin == 0 {
+		i.AnalyzeIntervalMin = 360
+	}
+
+	if i.CostDays == 0 {
+		i.CostDays = 30
+	}
+}
+
+func (i IdleResource) validate() error {
+	if i.CostDays > maxIdleCostDays {
+		return fmt.Errorf("idleResource.costDays should <= %d", maxIdleCostDays)
+	}
+
+	return nil
+}
+
+// CertExpiry 证书过期通知配置
+type CertExpiry struct {
+	// Enable 是否开启证书过期扫描与通知
+	Enable bool `yaml:"enable"`
+	// ScanIntervalMin 证书过期扫描间隔，单位分钟，默认为 60
+	ScanIntervalMin uint `yaml:"scanIntervalMin"`
+	// NoticeDays 证书剩余有效天数到达这些阈值时发送通知，默认为 30、7、1
+	NoticeDays []uint `yaml:"noticeDays"`
+	// Receivers 除证书创建人外额外接收通知的用户
+	Receivers []string `yaml:"receivers"`
+}
+
+func (c *CertExpiry) trySetDefault() {
+	if c.ScanIntervalMin == 0 {
+		c.ScanIntervalMin = 60
+	}
+
+	if len(c.NoticeDays) == 0 {
+		c.NoticeDays = []uint{30, 7, 1}
+	}
+}
+
+func (c CertExpiry) validate() error {
+	for _, day := range c.NoticeDays {
+		if day == 0 {
+			return errors.New("certExpiry.noticeDays should > 0")
+		}
+	}
+
+	return nil
+}
+
+// TargetHealth RS健康状态采集与告警配置
+type TargetHealth struct {
+	// Enable 是否开启RS健康状态的定时采集
+	Enable bool `yaml:"enable"`
+	// CollectIntervalMin 采集间隔，单位分钟，默认为 5
+	CollectIntervalMin uint `yaml:"collectIntervalMin"`
+	// RetentionDays 健康状态变化事件的保留天数，默认为 30
+	RetentionDays uint `yaml:"retentionDays"`
+	// HealthyRatioThreshold 监听器下健康RS占比低于该百分比时发送告警，取值范围 [0, 100]，为 0 时不告警，默认为 50
+	HealthyRatioThreshold *uint `yaml:"healthyRatioThreshold"`
+	// Receivers 除负载均衡创建人外额外接收告警的用户
+	Receivers []string `yaml:"receivers"`
+}
+
+func (t *TargetHealth) trySetDefault() {
+	if t.CollectIntervalMin == 0 {
+		t.CollectIntervalMin = 5
+	}
+
+	if t.RetentionDays == 0 {
+		t.RetentionDays = 30
+	}
+
+	if t.HealthyRatioThreshold == nil {
+		threshold := uint(50)
+		t.HealthyRatioThreshold = &threshold
+	}
+}
+
+func (t TargetHealth) validate() error {
+	if t.HealthyRatioThreshold != nil && *t.HealthyRatioThreshold > 100 {
+		return errors.New("targetHealth.healthyRatioThreshold should <= 100")
+	}
+
+	return nil
+}
+
+// AuditExport defines the options to export audits to external sink as json lines.
+type AuditExport struct {
+	// Enable 是否开启审计记录的外部导出
+	Enable bool `yaml:"enable"`
+	// Sink 导出目标，支持 file、syslog
+	Sink string `yaml:"sink"`
+	// IntervalSec 导出间隔，单位秒，默认为 10
+	IntervalSec uint `yaml:"intervalSec"`
+	// BatchSize 每个租户单次导出的最大审计记录数，默认为 500
+	BatchSize uint              `yaml:"batchSize"`
+	File      AuditExportFile   `yaml:"file"`
+	Syslog    AuditExportSyslog `yaml:"syslog"`
+}
+
+const (
+	// AuditExportFileSink export audits to local file.
+	AuditExportFileSink = "file"
+	// AuditExportSyslogSink export audits to syslog.
+	AuditExportSyslogSink = "syslog"
+)
+
+// AuditExportFile defines the file sink options of audit export.
+type AuditExportFile struct {
+	// Path 导出文件路径，审计记录以追加方式写入
+	Path string `yaml:"path"`
+}
+
+// AuditExportSyslog defines the syslog sink options of audit export.
+type AuditExportSyslog struct {
+	// Network 连接syslog服务的网络类型，支持 udp、tcp，为空时连接本机syslog服务
+	Network string `yaml:"network"`
+	// Address syslog服务地址，Network 不为空时必填
+	Address string `yaml:"address"`
+	// Tag syslog消息标签，默认为 hcm-audit
+	Tag string `yaml:"tag"`
+}
+
+func (a *AuditExport) trySetDefault() {
+	if a.IntervalSec == 0 {
+		a.IntervalSec = 10
+	}
+
+	if a.BatchSize == 0 {
+		a.BatchSize = 500
+	}
+
+	if len(a.Syslog.Tag) == 0 {
+		a.Syslog.Tag = "hcm-audit"
+	}
+}
+
+func (a AuditExport) validate() error {
+	if !a.Enable {
+		return nil
+	}
+
+	if a.BatchSize > 5000 {
+		return errors.New("auditExport.batchSize should <= 5000")
+	}
+
+	switch a.Sink {
+	case AuditExportFileSink:
+		if len(a.File.Path) == 0 {
+			return errors.New("auditExport.file.path is not set")
+		}
+	case AuditExportSyslogSink:
+		switch a.Syslog.Network {
+		case "":
+		case "udp", "tcp":
+			if len(a.Syslog.Address) == 0 {
+				return errors.New("auditExport.syslog.address is not set")
+			}
+		default:
+			return fmt.Errorf("unsupported auditExport.syslog.network: %s", a.Syslog.Network)
+		}
+	default:
+		return fmt.Errorf("unsupported auditExport.sink: %s", a.Sink)
+	}
+
+	return nil
+}
+
 // BillConfig 账号账单配置
 type BillConfig struct {
 	Enable          bool   `yaml:"enable"`
@@ -842,8 +1186,65 @@ type ThreshHoldRanges struct {
 
 // ObjectStore object store config
 type ObjectStore struct {
+	// Type 对象存储类型，可选值：tcloud、s3、local，为空时不启用对象存储
 	Type              string `yaml:"type"`
 	ObjectStoreTCloud `yaml:",inline"`
+	S3                ObjectStoreS3    `yaml:"s3"`
+	Local             ObjectStoreLocal `yaml:"local"`
+}
+
+// ObjectStoreS3 s3 compatible object store config, such as aws s3, minio, ceph.
+type ObjectStoreS3 struct {
+	// Endpoint s3 兼容服务地址，如 http://127.0.0.1:9000，为空时使用 aws 默认地址
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	Prefix          string `yaml:"prefix"`
+	AccessKeyID     string `yaml:"accessKeyId"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	// UsePathStyle 使用 path style 访问 bucket，minio 等自建服务通常需要开启
+	UsePathStyle bool `yaml:"usePathStyle"`
+	// DisableSSL 是否禁用 https
+	DisableSSL bool `yaml:"disableSSL"`
+	IsDebug    bool `yaml:"isDebug"`
+}
+
+// Validate do validate
+func (s ObjectStoreS3) Validate() error {
+	if len(s.Region) == 0 {
+		return errors.New("s3 region cannot be empty")
+	}
+	if len(s.Bucket) == 0 {
+		return errors.New("s3 bucket cannot be empty")
+	}
+	if len(s.AccessKeyID) == 0 {
+		return errors.New("s3 access_key_id cannot be empty")
+	}
+	if len(s.SecretAccessKey) == 0 {
+		return errors.New("s3 secret_access_key cannot be empty")
+	}
+	return nil
+}
+
+// ObjectStoreLocal local filesystem object store config
+type ObjectStoreLocal struct {
+	// RootDir 文件存储根目录
+	RootDir string `yaml:"rootDir"`
+	// URLPrefix 预签名URL前缀，需指向 web-server 的 /local_objects 接口（或其代理地址）
+	URLPrefix string `yaml:"urlPrefix"`
+	// SignKey 预签名URL的签名密钥
+	SignKey string `yaml:"signKey"`
+}
+
+// Validate do validate
+func (l ObjectStoreLocal) Validate() error {
+	if len(l.RootDir) == 0 {
+		return errors.New("local root_dir cannot be empty")
+	}
+	if len(l.SignKey) == 0 {
+		return errors.New("local sign_key cannot be empty")
+	}
+	return nil
 }
 
 // ObjectStoreTCloud tencent cloud cos config
@@ -1024,6 +1425,7 @@ type BillAllocationOption struct {
 	GcpCredits            []GcpCreditConfig       `yaml:"gcpCredits"`
 	GcpCommonExpense      BillCommonExpense       `yaml:"gcpCommonExpense"`
 	HuaweiCommonExpense   BillCommonExpense       `yaml:"huaweiCommonExpense"`
+	TCloudCommonExpense   BillCommonExpense       `yaml:"tcloudCommonExpense"`
 }
 
 func (opt *BillAllocationOption) validate() error {
@@ -1180,6 +1582,131 @@ func (r *SyncConcurrentRule) Validate() error {
 	return nil
 }
 
+// CloudApiLimit 云API限流配置，按 vendor/账号/API 维度限流
+type CloudApiLimit struct {
+	// Enable 是否开启限流，未开启时只采集指标
+	Enable bool `yaml:"enable"`
+	// DefaultQPS 未匹配任何规则时每个账号每个API的QPS
+	DefaultQPS float64 `yaml:"defaultQps"`
+	// DefaultBurst 未匹配任何规则时每个账号每个API的突发请求数
+	DefaultBurst int `yaml:"defaultBurst"`
+	// MinQPS 被云上限流后自适应降速的下限
+	MinQPS float64 `yaml:"minQps"`
+	// RecoverIntervalSec 未再被限流时，每隔多少秒恢复一次速率
+	RecoverIntervalSec uint `yaml:"recoverIntervalSec"`
+	// Rules 限流规则，规则语法：vendor/api，按顺序匹配
+	Rules []CloudApiLimitRule `yaml:"rules"`
+}
+
+func (c *CloudApiLimit) trySetDefault() {
+	if c.DefaultQPS == 0 {
+		c.DefaultQPS = 10
+	}
+	if c.DefaultBurst == 0 {
+		c.DefaultBurst = int(c.DefaultQPS)
+	}
+	if c.MinQPS == 0 {
+		c.MinQPS = 0.5
+	}
+	if c.RecoverIntervalSec == 0 {
+		c.RecoverIntervalSec = 10
+	}
+	for i := range c.Rules {
+		c.Rules[i].trySetDefault()
+	}
+}
+
+// Validate ...
+func (c CloudApiLimit) Validate() error {
+	if c.DefaultQPS < 0 || c.MinQPS < 0 {
+		return errors.New("cloud api limit qps should not be negative")
+	}
+	if c.MinQPS > c.DefaultQPS {
+		return errors.New("cloud api limit minQps should not be greater than defaultQps")
+	}
+	for _, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLimit 获取限流速率，按顺序匹配规则，一旦匹配立即返回，未开启限流时返回0表示不限流
+func (c CloudApiLimit) GetLimit(vendor enumor.Vendor, api string) (float64, int) {
+	if !c.Enable {
+		return 0, 0
+	}
+	for _, r := range c.Rules {
+		if r.Match(vendor, api) {
+			return r.QPS, r.Burst
+		}
+	}
+	return c.DefaultQPS, c.DefaultBurst
+}
+
+// CloudApiLimitRule 云API限流规则
+type CloudApiLimitRule struct {
+	Rule   string        `yaml:"rule"`
+	vendor enumor.Vendor `yaml:"vendor"`
+	api    string        `yaml:"api"`
+	QPS    float64       `yaml:"qps"`
+	// Burst 未设置时与QPS相同
+	Burst int `yaml:"burst"`
+}
+
+// Match ...
+func (r *CloudApiLimitRule) Match(vendor enumor.Vendor, api string) bool {
+	if r == nil {
+		return false
+	}
+	if r.vendor != ConcurrentWildcard && r.vendor != vendor {
+		return false
+	}
+	if r.api != ConcurrentWildcard && r.api != api {
+		return false
+	}
+	return true
+}
+
+func (r *CloudApiLimitRule) trySetDefault() {
+	if r.Rule == "" {
+		return
+	}
+	// api 名称可能包含'/'，如华为云的 "GET /v3/{id}/vpc/vpcs"，因此只按第一个'/'切分
+	parts := strings.SplitN(r.Rule, "/", 2)
+	r.vendor = enumor.Vendor(parts[0])
+	if len(parts) > 1 {
+		r.api = parts[1]
+	}
+	if r.Burst == 0 {
+		r.Burst = int(r.QPS)
+	}
+	if r.Burst == 0 {
+		r.Burst = 1
+	}
+}
+
+// Validate ...
+func (r *CloudApiLimitRule) Validate() error {
+	if r == nil {
+		return errors.New("cloud api limit rule is nil")
+	}
+	if len(r.Rule) == 0 {
+		return errors.New("empty cloud api limit rule")
+	}
+	if r.vendor == "" {
+		return errors.New("invalid cloud api limit rule: empty vendor")
+	}
+	if r.api == "" {
+		return errors.New("invalid cloud api limit rule: empty api")
+	}
+	if r.QPS <= 0 {
+		return fmt.Errorf("invalid cloud api limit rule %s: qps should be positive", r.Rule)
+	}
+	return nil
+}
+
 // TenantConfig tenant config
 type TenantConfig struct {
 	Enabled bool `yaml:"enabled"`
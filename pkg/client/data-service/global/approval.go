@@ -0,0 +1,106 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// CreateApprovalChain create native approval chain.
+func (cli *restClient) CreateApprovalChain(kt *kit.Kit, req *dataservice.ApprovalChainCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[dataservice.ApprovalChainCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/approval_chains/create")
+}
+
+// UpdateApprovalChain update native approval chain.
+func (cli *restClient) UpdateApprovalChain(kt *kit.Kit, req *dataservice.ApprovalChainUpdateReq) error {
+	return common.RequestNoResp[dataservice.ApprovalChainUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/approval_chains")
+}
+
+// ListApprovalChain list native approval chain.
+func (cli *restClient) ListApprovalChain(kt *kit.Kit, req *core.ListReq) (*dataservice.ApprovalChainListResult,
+	error) {
+
+	return common.Request[core.ListReq, dataservice.ApprovalChainListResult](cli.client, rest.POST, kt, req,
+		"/approval_chains/list")
+}
+
+// BatchDeleteApprovalChain batch delete native approval chain.
+func (cli *restClient) BatchDeleteApprovalChain(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/approval_chains/batch")
+}
+
+// CreateApprovalTicket create native approval ticket.
+func (cli *restClient) CreateApprovalTicket(kt *kit.Kit, req *dataservice.ApprovalTicketCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[dataservice.ApprovalTicketCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/approval_tickets/create")
+}
+
+// UpdateApprovalTicket update native approval ticket, return errf.RecordNotUpdate when version is not matched.
+func (cli *restClient) UpdateApprovalTicket(kt *kit.Kit, req *dataservice.ApprovalTicketUpdateReq) error {
+	return common.RequestNoResp[dataservice.ApprovalTicketUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/approval_tickets")
+}
+
+// ListApprovalTicket list native approval ticket.
+func (cli *restClient) ListApprovalTicket(kt *kit.Kit, req *core.ListReq) (*dataservice.ApprovalTicketListResult,
+	error) {
+
+	return common.Request[core.ListReq, dataservice.ApprovalTicketListResult](cli.client, rest.POST, kt, req,
+		"/approval_tickets/list")
+}
+
+// CreateAutoApprovalPolicy create application auto approval policy.
+func (cli *restClient) CreateAutoApprovalPolicy(kt *kit.Kit, req *dataservice.AutoApprovalPolicyCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[dataservice.AutoApprovalPolicyCreateReq, core.CreateResult](cli.client, rest.POST, kt,
+		req, "/auto_approval_policies/create")
+}
+
+// UpdateAutoApprovalPolicy update application auto approval policy.
+func (cli *restClient) UpdateAutoApprovalPolicy(kt *kit.Kit, req *dataservice.AutoApprovalPolicyUpdateReq) error {
+	return common.RequestNoResp[dataservice.AutoApprovalPolicyUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/auto_approval_policies")
+}
+
+// ListAutoApprovalPolicy list application auto approval policy.
+func (cli *restClient) ListAutoApprovalPolicy(kt *kit.Kit, req *core.ListReq) (
+	*dataservice.AutoApprovalPolicyListResult, error) {
+
+	return common.Request[core.ListReq, dataservice.AutoApprovalPolicyListResult](cli.client, rest.POST, kt, req,
+		"/auto_approval_policies/list")
+}
+
+// BatchDeleteAutoApprovalPolicy batch delete application auto approval policy.
+func (cli *restClient) BatchDeleteAutoApprovalPolicy(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/auto_approval_policies/batch")
+}
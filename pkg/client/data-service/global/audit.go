@@ -218,3 +218,11 @@ func (a *AuditClient) GetAuditRaw(kt *kit.Kit, id uint64) (
 	return common.Request[common.Empty, coreaudit.RawAudit](a.client, rest.GET, kt, nil,
 		"/audits/%d", id)
 }
+
+// VerifyAuditChain verify the audit hash chain of current tenant.
+func (a *AuditClient) VerifyAuditChain(kt *kit.Kit, req *protoaudit.ChainVerifyReq) (
+	*coreaudit.ChainVerifyResult, error) {
+
+	return common.Request[protoaudit.ChainVerifyReq, coreaudit.ChainVerifyResult](a.client, rest.POST, kt, req,
+		"/audits/chain/verify")
+}
@@ -508,3 +508,50 @@ func (b *BillClient) SumBillItemCost(kt *kit.Kit, req *billproto.BillItemSumReq)
 	return common.Request[billproto.BillItemSumReq, billproto.BillItemSumResult](b.client, rest.POST, kt, req,
 		"/bills/items/sum")
 }
+
+// --- bill allocation ---
+
+// BatchCreateBillAllocationRule create bill allocation rules
+func (b *BillClient) BatchCreateBillAllocationRule(kt *kit.Kit, req *billproto.BatchCreateAllocationRuleReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[billproto.BatchCreateAllocationRuleReq, core.BatchCreateResult](
+		b.client, rest.POST, kt, req, "/bills/allocation_rules/batch/create")
+}
+
+// UpdateBillAllocationRule update bill allocation rule
+func (b *BillClient) UpdateBillAllocationRule(kt *kit.Kit, req *billproto.AllocationRuleUpdateReq) error {
+
+	return common.RequestNoResp[billproto.AllocationRuleUpdateReq](
+		b.client, rest.PATCH, kt, req, "/bills/allocation_rules")
+}
+
+// ListBillAllocationRule list bill allocation rule
+func (b *BillClient) ListBillAllocationRule(kt *kit.Kit, req *core.ListReq) (
+	*billproto.AllocationRuleListResult, error) {
+
+	return common.Request[core.ListReq, billproto.AllocationRuleListResult](b.client, rest.POST, kt, req,
+		"/bills/allocation_rules/list")
+}
+
+// BatchDeleteBillAllocationRule batch delete bill allocation rule
+func (b *BillClient) BatchDeleteBillAllocationRule(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+
+	return common.RequestNoResp[dataservice.BatchDeleteReq](b.client, rest.DELETE, kt, req,
+		"/bills/allocation_rules/batch")
+}
+
+// ReplaceBillAllocationResult replace bill allocation results of one bill day and version
+func (b *BillClient) ReplaceBillAllocationResult(kt *kit.Kit, req *billproto.AllocationResultReplaceReq) error {
+
+	return common.RequestNoResp[billproto.AllocationResultReplaceReq](b.client, rest.POST, kt, req,
+		"/bills/allocation_results/replace")
+}
+
+// ListBillAllocationResult list bill allocation result
+func (b *BillClient) ListBillAllocationResult(kt *kit.Kit, req *core.ListReq) (
+	*billproto.AllocationResultListResult, error) {
+
+	return common.Request[core.ListReq, billproto.AllocationResultListResult](b.client, rest.POST, kt, req,
+		"/bills/allocation_results/list")
+}
@@ -0,0 +1,83 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// BatchCreateDiskSnapshot batch create disk snapshot.
+func (cli *restClient) BatchCreateDiskSnapshot(kt *kit.Kit, req *protocloud.DiskSnapshotBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.DiskSnapshotBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		req, "/disk_snapshots/batch/create")
+}
+
+// BatchUpdateDiskSnapshot batch update disk snapshot.
+func (cli *restClient) BatchUpdateDiskSnapshot(kt *kit.Kit, req *protocloud.DiskSnapshotBatchUpdateReq) error {
+	return common.RequestNoResp[protocloud.DiskSnapshotBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/disk_snapshots/batch/update")
+}
+
+// ListDiskSnapshot list disk snapshot.
+func (cli *restClient) ListDiskSnapshot(kt *kit.Kit, req *core.ListReq) (*protocloud.DiskSnapshotListResult, error) {
+	return common.Request[core.ListReq, protocloud.DiskSnapshotListResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshots/list")
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshot.
+func (cli *restClient) BatchDeleteDiskSnapshot(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/disk_snapshots/batch")
+}
+
+// CreateDiskSnapshotPolicy create disk snapshot policy.
+func (cli *restClient) CreateDiskSnapshotPolicy(kt *kit.Kit, req *protocloud.DiskSnapshotPolicyCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[protocloud.DiskSnapshotPolicyCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshot_policies/create")
+}
+
+// UpdateDiskSnapshotPolicy update disk snapshot policy.
+func (cli *restClient) UpdateDiskSnapshotPolicy(kt *kit.Kit, req *protocloud.DiskSnapshotPolicyUpdateReq) error {
+	return common.RequestNoResp[protocloud.DiskSnapshotPolicyUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/disk_snapshot_policies")
+}
+
+// ListDiskSnapshotPolicy list disk snapshot policy.
+func (cli *restClient) ListDiskSnapshotPolicy(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.DiskSnapshotPolicyListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.DiskSnapshotPolicyListResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshot_policies/list")
+}
+
+// BatchDeleteDiskSnapshotPolicy batch delete disk snapshot policy.
+func (cli *restClient) BatchDeleteDiskSnapshotPolicy(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/disk_snapshot_policies/batch")
+}
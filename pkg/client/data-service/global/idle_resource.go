@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// BatchCreateIdleResource batch create idle resource.
+func (cli *restClient) BatchCreateIdleResource(kt *kit.Kit, req *protocloud.IdleResourceBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.IdleResourceBatchCreateReq, core.BatchCreateResult](cli.client, rest.POST, kt,
+		req, "/idle_resources/batch/create")
+}
+
+// BatchUpdateIdleResource batch update idle resource.
+func (cli *restClient) BatchUpdateIdleResource(kt *kit.Kit, req *protocloud.IdleResourceBatchUpdateReq) error {
+	return common.RequestNoResp[protocloud.IdleResourceBatchUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/idle_resources/batch/update")
+}
+
+// ListIdleResource list idle resource.
+func (cli *restClient) ListIdleResource(kt *kit.Kit, req *core.ListReq) (*protocloud.IdleResourceListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.IdleResourceListResult](cli.client, rest.POST, kt, req,
+		"/idle_resources/list")
+}
+
+// BatchDeleteIdleResource batch delete idle resource.
+func (cli *restClient) BatchDeleteIdleResource(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/idle_resources/batch")
+}
@@ -251,3 +251,49 @@ func (cli *LoadBalancerClient) ListTargetInstInfo(kt *kit.Kit, req *core.ListReq
 	return common.Request[core.ListReq, typeslb.ListInstInfoDetails](
 		cli.client, rest.POST, kt, req, "/load_balancers/targets/inst_info/list")
 }
+
+// CreateLoadBalancerSnapshot 创建负载均衡配置快照
+func (cli *LoadBalancerClient) CreateLoadBalancerSnapshot(kt *kit.Kit, req *dataproto.LbSnapshotCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[dataproto.LbSnapshotCreateReq, core.CreateResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/snapshots/create")
+}
+
+// UpdateLoadBalancerSnapshot 更新负载均衡配置快照关联的异步任务
+func (cli *LoadBalancerClient) UpdateLoadBalancerSnapshot(kt *kit.Kit, id string,
+	req *dataproto.LbSnapshotUpdateReq) error {
+
+	return common.RequestNoResp[dataproto.LbSnapshotUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/load_balancers/snapshots/%s", id)
+}
+
+// ListLoadBalancerSnapshot 查询负载均衡配置快照
+func (cli *LoadBalancerClient) ListLoadBalancerSnapshot(kt *kit.Kit, req *core.ListReq) (
+	*dataproto.LbSnapshotListResult, error) {
+
+	return common.Request[core.ListReq, dataproto.LbSnapshotListResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/snapshots/list")
+}
+
+// BatchCreateTargetHealthEvent 批量创建RS健康状态变化事件
+func (cli *LoadBalancerClient) BatchCreateTargetHealthEvent(kt *kit.Kit,
+	req *dataproto.TargetHealthEventBatchCreateReq) (*core.BatchCreateResult, error) {
+
+	return common.Request[dataproto.TargetHealthEventBatchCreateReq, core.BatchCreateResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/target_health_events/batch/create")
+}
+
+// ListTargetHealthEvent 查询RS健康状态变化事件
+func (cli *LoadBalancerClient) ListTargetHealthEvent(kt *kit.Kit, req *core.ListReq) (
+	*dataproto.TargetHealthEventListResult, error) {
+
+	return common.Request[core.ListReq, dataproto.TargetHealthEventListResult](
+		cli.client, rest.POST, kt, req, "/load_balancers/target_health_events/list")
+}
+
+// BatchDeleteTargetHealthEvent 删除RS健康状态变化事件
+func (cli *LoadBalancerClient) BatchDeleteTargetHealthEvent(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/load_balancers/target_health_events/batch")
+}
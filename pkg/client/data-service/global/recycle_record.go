@@ -22,6 +22,7 @@ package global
 import (
 	"hcm/pkg/api/core"
 	rr "hcm/pkg/api/core/recycle-record"
+	dataservice "hcm/pkg/api/data-service"
 	proto "hcm/pkg/api/data-service/recycle-record"
 	"hcm/pkg/client/common"
 	"hcm/pkg/criteria/errf"
@@ -165,3 +166,28 @@ func (r *RecycleRecordClient) BatchUpdateRecycleStatus(kt *kit.Kit, request *pro
 
 	return nil
 }
+
+// CreateRecyclePolicy create recycle policy.
+func (r *RecycleRecordClient) CreateRecyclePolicy(kt *kit.Kit, req *proto.PolicyCreateReq) (*core.CreateResult,
+	error) {
+
+	return common.Request[proto.PolicyCreateReq, core.CreateResult](r.client, rest.POST, kt, req,
+		"/recycle_policies/create")
+}
+
+// UpdateRecyclePolicy update recycle policy.
+func (r *RecycleRecordClient) UpdateRecyclePolicy(kt *kit.Kit, req *proto.PolicyUpdateReq) error {
+	return common.RequestNoResp[proto.PolicyUpdateReq](r.client, rest.PATCH, kt, req, "/recycle_policies")
+}
+
+// ListRecyclePolicy list recycle policy.
+func (r *RecycleRecordClient) ListRecyclePolicy(kt *kit.Kit, req *core.ListReq) (*proto.PolicyListResult, error) {
+	return common.Request[core.ListReq, proto.PolicyListResult](r.client, rest.POST, kt, req,
+		"/recycle_policies/list")
+}
+
+// BatchDeleteRecyclePolicy batch delete recycle policy.
+func (r *RecycleRecordClient) BatchDeleteRecyclePolicy(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](r.client, rest.DELETE, kt, req,
+		"/recycle_policies/batch")
+}
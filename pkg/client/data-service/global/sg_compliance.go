@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// CreateSGComplianceCheck create security group rule compliance check.
+func (cli *restClient) CreateSGComplianceCheck(kt *kit.Kit, req *protocloud.SGComplianceCheckCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[protocloud.SGComplianceCheckCreateReq, core.CreateResult](cli.client, rest.POST, kt, req,
+		"/security_groups/compliance_checks/create")
+}
+
+// UpdateSGComplianceCheck update security group rule compliance check.
+func (cli *restClient) UpdateSGComplianceCheck(kt *kit.Kit, req *protocloud.SGComplianceCheckUpdateReq) error {
+	return common.RequestNoResp[protocloud.SGComplianceCheckUpdateReq](cli.client, rest.PATCH, kt, req,
+		"/security_groups/compliance_checks")
+}
+
+// ListSGComplianceCheck list security group rule compliance check.
+func (cli *restClient) ListSGComplianceCheck(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.SGComplianceCheckListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.SGComplianceCheckListResult](cli.client, rest.POST, kt, req,
+		"/security_groups/compliance_checks/list")
+}
+
+// BatchDeleteSGComplianceCheck batch delete security group rule compliance check.
+func (cli *restClient) BatchDeleteSGComplianceCheck(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](cli.client, rest.DELETE, kt, req,
+		"/security_groups/compliance_checks/batch")
+}
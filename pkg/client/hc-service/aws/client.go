@@ -31,6 +31,7 @@ type Client struct {
 	Subnet        *SubnetClient
 	Eip           *EipClient
 	Disk          *DiskClient
+	DiskSnapshot  *DiskSnapshotClient
 	Zone          *ZoneClient
 	Region        *RegionClient
 	Cvm           *CvmClient
@@ -39,6 +40,7 @@ type Client struct {
 	InstanceType  *InstanceTypeClient
 	Bill          *BillClient
 	MainAccount   *MainAccountClient
+	LoadBalancer  *LoadBalancerClient
 }
 
 // NewClient create a new aws api client.
@@ -50,6 +52,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		Subnet:        NewSubnetClient(client),
 		Eip:           NewEipClient(client),
 		Disk:          NewCloudDiskClient(client),
+		DiskSnapshot:  NewDiskSnapshotClient(client),
 		Zone:          NewZoneClient(client),
 		Region:        NewRegionClient(client),
 		Cvm:           NewCvmClient(client),
@@ -58,5 +61,6 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:  NewInstanceTypeClient(client),
 		Bill:          NewBillClient(client),
 		MainAccount:   NewMainAccountClient(client),
+		LoadBalancer:  NewLoadBalancerClient(client),
 	}
 }
@@ -22,6 +22,8 @@ package hcservice
 import (
 	"fmt"
 
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/hc-service/sync"
 	"hcm/pkg/client/hc-service/aws"
 	"hcm/pkg/client/hc-service/azure"
 	"hcm/pkg/client/hc-service/gcp"
@@ -29,6 +31,8 @@ import (
 	"hcm/pkg/client/hc-service/other"
 	"hcm/pkg/client/hc-service/tcloud"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
 	"hcm/pkg/rest"
 	"hcm/pkg/rest/client"
 )
@@ -41,6 +45,9 @@ type Client struct {
 	Gcp    *gcp.Client
 	Azure  *azure.Client
 	Other  *other.Client
+
+	capability *client.Capability
+	prefixPath string
 }
 
 // NewClient create a new hc-service api client.
@@ -66,5 +73,32 @@ func NewClient(c *client.Capability, version string) *Client {
 		Other: other.NewClient(
 			rest.NewClient(c, fmt.Sprintf("%s/%s", prefixPath, enumor.Other)),
 		),
+		capability: c,
+		prefixPath: prefixPath,
+	}
+}
+
+// SyncPlan 以计划模式调用云厂商的同步接口，只返回同步会产生的差异，不修改db数据。
+// syncPath 为同步接口在云厂商下的路径，如 /vpcs/sync，req 为该同步接口的请求体。
+func (c *Client) SyncPlan(kt *kit.Kit, vendor enumor.Vendor, syncPath string, req any) (*sync.SyncPlanResult,
+	error) {
+
+	resp := new(core.BaseResp[*sync.SyncPlanResult])
+	err := rest.NewClient(c.capability, fmt.Sprintf("%s/%s", c.prefixPath, vendor)).Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef(syncPath).
+		WithParam(sync.PlanQueryKey, "true").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
 	}
+
+	return resp.Data, nil
 }
@@ -122,6 +122,24 @@ func (c *Client) GetFlow(kt *kit.Kit, id string) (*coreasync.AsyncFlow, error) {
 	return resp.Data, err
 }
 
+// GetFlowGraph get flow dag with task timings and critical path.
+func (c *Client) GetFlowGraph(kt *kit.Kit, id string) (*apits.FlowGraphResult, error) {
+	resp := new(core.BaseResp[*apits.FlowGraphResult])
+
+	err := c.client.Get().
+		WithContext(kt.Ctx).
+		SubResourcef("/flows/%s/graph", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
 // ListTask list task.
 func (c *Client) ListTask(kt *kit.Kit, req *core.ListReq) (*apits.ListTaskResult, error) {
 	resp := new(core.BaseResp[*apits.ListTaskResult])
@@ -183,3 +201,26 @@ func (c *Client) RetryTask(kt *kit.Kit, flowID string, taskID string) error {
 	return common.RequestNoResp[common.Empty](c.client, rest.PATCH, kt, nil,
 		"/flows/%s/tasks/%s/retry", flowID, taskID)
 }
+
+// CreateFlowSchedule 创建定时任务流
+func (c *Client) CreateFlowSchedule(kt *kit.Kit, req *apits.CreateFlowScheduleReq) (*core.CreateResult, error) {
+	return common.Request[apits.CreateFlowScheduleReq, core.CreateResult](c.client, rest.POST, kt, req,
+		"/flow_schedules/create")
+}
+
+// UpdateFlowSchedule 更新定时任务流
+func (c *Client) UpdateFlowSchedule(kt *kit.Kit, id string, req *apits.UpdateFlowScheduleReq) error {
+	return common.RequestNoResp[apits.UpdateFlowScheduleReq](c.client, rest.PATCH, kt, req,
+		"/flow_schedules/%s", id)
+}
+
+// DeleteFlowSchedule 删除定时任务流
+func (c *Client) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+	return common.RequestNoResp[common.Empty](c.client, rest.DELETE, kt, nil, "/flow_schedules/%s", id)
+}
+
+// ListFlowSchedule 查询定时任务流
+func (c *Client) ListFlowSchedule(kt *kit.Kit, req *core.ListReq) (*apits.ListFlowScheduleResult, error) {
+	return common.Request[core.ListReq, apits.ListFlowScheduleResult](c.client, rest.POST, kt, req,
+		"/flow_schedules/list")
+}
@@ -61,6 +61,9 @@ const AwsLineItemTypeSavingPlanCoveredUsage = "SavingsPlanCoveredUsage"
 // HuaweiCommonExpenseExcludeCloudIDKey ...
 const HuaweiCommonExpenseExcludeCloudIDKey = "huawei_common_expense_exclude_account_cloud_id"
 
+// TCloudCommonExpenseExcludeCloudIDKey ...
+const TCloudCommonExpenseExcludeCloudIDKey = "tcloud_common_expense_exclude_account_cloud_id"
+
 const (
 	// BillOutsideMonthBillName outside bill month bill
 	BillOutsideMonthBillName = "OutsideMonthBill"
@@ -22,4 +22,6 @@ package constant
 const (
 	// FlowTypePriority 不同类型flow的优先级配置
 	FlowTypePriority = "flow_type_priority"
+	// CertExpiryNoticeWatermark 各租户证书过期通知已完成扫描的时间点，config_key 为租户ID
+	CertExpiryNoticeWatermark = "cert_expiry_notice_watermark"
 )
@@ -119,4 +119,19 @@ type ApplicationSource string
 const (
 	// ApplicationSourceITSM itsm 单据
 	ApplicationSourceITSM ApplicationSource = "itsm"
+	// ApplicationSourceNative 内置审批引擎单据
+	ApplicationSourceNative ApplicationSource = "native"
+	// ApplicationSourceAutoApproval 命中自动审批策略，无需审批直接交付的单据
+	ApplicationSourceAutoApproval ApplicationSource = "auto_approval"
 )
+
+// Validate the ApplicationSource is valid or not
+func (s ApplicationSource) Validate() error {
+	switch s {
+	case ApplicationSourceITSM, ApplicationSourceNative, ApplicationSourceAutoApproval:
+	default:
+		return fmt.Errorf("unsupported application source: %s", s)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// ApprovalTicketStatus 内置审批单状态
+type ApprovalTicketStatus string
+
+const (
+	// ApprovalTicketPending 审批中
+	ApprovalTicketPending ApprovalTicketStatus = "pending"
+	// ApprovalTicketApproved 审批通过
+	ApprovalTicketApproved ApprovalTicketStatus = "approved"
+	// ApprovalTicketRejected 审批驳回
+	ApprovalTicketRejected ApprovalTicketStatus = "rejected"
+	// ApprovalTicketCancelled 审批撤销
+	ApprovalTicketCancelled ApprovalTicketStatus = "cancelled"
+)
+
+// Validate the ApprovalTicketStatus is valid or not
+func (s ApprovalTicketStatus) Validate() error {
+	switch s {
+	case ApprovalTicketPending, ApprovalTicketApproved, ApprovalTicketRejected, ApprovalTicketCancelled:
+	default:
+		return fmt.Errorf("unsupported approval ticket status: %s", s)
+	}
+
+	return nil
+}
+
+// ApprovalAction 审批操作
+type ApprovalAction string
+
+const (
+	// ApprovalApprove 同意
+	ApprovalApprove ApprovalAction = "approve"
+	// ApprovalReject 驳回
+	ApprovalReject ApprovalAction = "reject"
+	// ApprovalTransfer 转审
+	ApprovalTransfer ApprovalAction = "transfer"
+	// ApprovalCancel 申请人撤销
+	ApprovalCancel ApprovalAction = "cancel"
+)
@@ -67,7 +67,7 @@ type BackendType string
 // Validate BackendType.
 func (v BackendType) Validate() error {
 	switch v {
-	case BackendMysql:
+	case BackendMysql, BackendMemory:
 	default:
 		return fmt.Errorf("unsupported backend type: %s", v)
 	}
@@ -78,4 +78,50 @@ func (v BackendType) Validate() error {
 const (
 	// BackendMysql mysql backend
 	BackendMysql BackendType = "mysql"
+	// BackendMemory memory backend, 数据保存在进程内存中，服务重启后丢失
+	BackendMemory BackendType = "memory"
+)
+
+// LeaderType 异步任务框架选主方式
+type LeaderType string
+
+// Validate LeaderType.
+func (v LeaderType) Validate() error {
+	switch v {
+	case LeaderEtcd, LeaderMysql, LeaderStatic:
+	default:
+		return fmt.Errorf("unsupported leader type: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// LeaderEtcd 基于etcd服务发现选主
+	LeaderEtcd LeaderType = "etcd"
+	// LeaderMysql 基于MySQL用户锁(GET_LOCK)选主
+	LeaderMysql LeaderType = "mysql"
+	// LeaderStatic 单节点部署，当前节点固定为主节点
+	LeaderStatic LeaderType = "static"
+)
+
+// MisfirePolicy 定时任务流错过触发时间(如主节点切换、服务停止期间)后的处理策略
+type MisfirePolicy string
+
+// Validate MisfirePolicy.
+func (v MisfirePolicy) Validate() error {
+	switch v {
+	case MisfireFireOnce, MisfireSkip:
+	default:
+		return fmt.Errorf("unsupported misfire policy: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// MisfireFireOnce 错过的多次触发合并为一次，立即补偿执行
+	MisfireFireOnce MisfirePolicy = "fire_once"
+	// MisfireSkip 跳过错过的触发，等待下一次触发时间
+	MisfireSkip MisfirePolicy = "skip"
 )
@@ -59,6 +59,7 @@ var defaultFlowNameMap = map[FlowName]struct{}{
 	FlowBillMainAccountSummary: {},
 	FlowBillRootAccountSummary: {},
 	FlowBillMonthTask:          {},
+	FlowDiskSnapshotPolicy:     {},
 }
 
 // ValidateDefault validate default FlowName.
@@ -72,19 +73,22 @@ func (v FlowName) ValidateDefault() error {
 
 // 负载均衡相关的FlowName
 var loadBalancerFlowNameMap = map[FlowName]struct{}{
-	FlowTargetGroupAddRS:               {},
-	FlowTargetGroupRemoveRS:            {},
-	FlowTargetGroupModifyPort:          {},
-	FlowTargetGroupModifyWeight:        {},
-	FlowLoadBalancerOperateWatch:       {},
-	FlowApplyTargetGroupToListenerRule: {},
-	FlowDeleteLoadBalancer:             {},
-	FlowLoadBalancerDeleteRule:         {},
-	FlowLoadBalancerCreateListener:     {},
-	FlowLoadBalancerCreateUrlRule:      {},
-	FlowBatchTaskListenerUnBindTarget:  {},
+	FlowTargetGroupAddRS:                {},
+	FlowTargetGroupRemoveRS:             {},
+	FlowTargetGroupModifyPort:           {},
+	FlowTargetGroupModifyWeight:         {},
+	FlowTargetGroupTrafficShift:         {},
+	FlowLoadBalancerOperateWatch:        {},
+	FlowApplyTargetGroupToListenerRule:  {},
+	FlowDeleteLoadBalancer:              {},
+	FlowLoadBalancerDeleteRule:          {},
+	FlowLoadBalancerCreateListener:      {},
+	FlowLoadBalancerCreateUrlRule:       {},
+	FlowBatchTaskListenerUnBindTarget:   {},
 	FlowBatchTaskListenerModifyRsWeight: {},
-	FlowBatchTaskDeleteListener:        {},
+	FlowBatchTaskDeleteListener:         {},
+	FlowListenerReplaceCert:             {},
+	FlowLoadBalancerApplySpec:           {},
 }
 
 // ValidateLoadBalancer validate load balancer FlowName.
@@ -135,6 +139,12 @@ const (
 	FlowDeleteEIP FlowName = "delete_eip"
 )
 
+// 云盘快照相关Flow
+const (
+	// FlowDiskSnapshotPolicy 按快照策略创建快照并清理过期快照
+	FlowDiskSnapshotPolicy FlowName = "disk_snapshot_policy"
+)
+
 // Flow 相关Flow
 const (
 	// FlowLoadBalancerOperateWatch 负载均衡操作查询
@@ -162,6 +172,12 @@ const (
 	FlowBatchTaskListenerModifyRsWeight = "batch_task_tcloud_listener_modify_rs_weight"
 	// FlowBatchTaskDeleteListener 异步任务-批量删除监听器
 	FlowBatchTaskDeleteListener = "batch_task_tcloud_delete_listener"
+	// FlowListenerReplaceCert 批量替换监听器、域名上使用的证书
+	FlowListenerReplaceCert FlowName = "listener_replace_cert"
+	// FlowLoadBalancerApplySpec 应用负载均衡声明式配置
+	FlowLoadBalancerApplySpec FlowName = "load_balancer_apply_spec"
+	// FlowTargetGroupTrafficShift 目标组渐进式流量切换
+	FlowTargetGroupTrafficShift FlowName = "target_group_traffic_shift"
 )
 
 // 账单相关Flow
@@ -35,12 +35,14 @@ func (v ActionName) Validate() error {
 	case ActionDeleteSubnet:
 	case ActionDeleteSecurityGroup, ActionCreateHuaweiSGRule:
 	case ActionDeleteEIP:
+	case ActionDiskSnapshotPolicy:
 
 	case VirRoot:
 	case ActionCreateFactoryTest, ActionProduceTest, ActionAssembleTest, ActionSleep:
 	case ActionTargetGroupAddRS, ActionTargetGroupRemoveRS, ActionTargetGroupModifyPort, ActionTargetGroupModifyWeight:
 	case ActionLoadBalancerOperateWatch:
-	case ActionListenerRuleAddTarget, ActionListenerRuleUpdateHealthCheck:
+	case ActionListenerRuleAddTarget, ActionListenerRuleUpdateHealthCheck, ActionListenerReplaceCert:
+	case ActionLoadBalancerApplySpec, ActionTargetGroupTrafficShift:
 	case ActionDeleteLoadBalancer:
 	case ActionPullDailyRawBill, ActionMainAccountSummary, ActionRootAccountSummary,
 		ActionDailyAccountSplit, ActionDailyAccountSummary, ActionMonthTaskAction:
@@ -104,6 +106,12 @@ const (
 	ActionDeleteEIP ActionName = "delete_eip"
 )
 
+// 云盘快照相关Action
+const (
+	// ActionDiskSnapshotPolicy 执行快照策略
+	ActionDiskSnapshotPolicy ActionName = "disk_snapshot_policy"
+)
+
 // Flow相关Action
 const (
 	ActionLoadBalancerOperateWatch ActionName = "load_balancer_operate_watch"
@@ -121,6 +129,12 @@ const (
 	// ActionListenerRuleAddTarget 直接将RS绑定到 监听器/规则 上
 	ActionListenerRuleAddTarget         ActionName = "listener_rule_add_target"
 	ActionListenerRuleUpdateHealthCheck ActionName = "listener_rule_update_health_check"
+	// ActionListenerReplaceCert 将监听器、域名上使用的证书替换为新证书
+	ActionListenerReplaceCert ActionName = "listener_replace_cert"
+	// ActionLoadBalancerApplySpec 执行负载均衡声明式配置计划中的变更
+	ActionLoadBalancerApplySpec ActionName = "load_balancer_apply_spec"
+	// ActionTargetGroupTrafficShift 目标组渐进式流量切换中的一步
+	ActionTargetGroupTrafficShift ActionName = "tg_traffic_shift"
 
 	ActionDeleteLoadBalancer = "delete_load_balancer"
 )
@@ -50,6 +50,9 @@ const (
 	UrlRuleDomainAuditResType     AuditResourceType = "url_rule_domain"
 	MainAccountAuditResType       AuditResourceType = "main_account"
 	RootAccountAuditResType       AuditResourceType = "root_account"
+	DiskSnapshotAuditResType      AuditResourceType = "disk_snapshot"
+	SnapshotPolicyAuditResType    AuditResourceType = "disk_snapshot_policy"
+	ApplicationAuditResType       AuditResourceType = "application"
 )
 
 // AuditResourceTypeEnums resource type map.
@@ -75,6 +78,9 @@ var AuditResourceTypeEnums = map[AuditResourceType]struct{}{
 	UrlRuleDomainAuditResType:     {},
 	MainAccountAuditResType:       {},
 	RootAccountAuditResType:       {},
+	DiskSnapshotAuditResType:      {},
+	SnapshotPolicyAuditResType:    {},
+	ApplicationAuditResType:       {},
 }
 
 // Exist judge enum value exist.
@@ -115,6 +121,8 @@ const (
 	Bind AuditAction = "bind"
 	// Deliver 交付
 	Deliver AuditAction = "deliver"
+	// AutoApprove 自动审批
+	AutoApprove AuditAction = "auto_approve"
 )
 
 // AuditActionEnums op type map.
@@ -133,6 +141,7 @@ var AuditActionEnums = map[AuditAction]struct{}{
 	Disassociate: {},
 	Bind:         {},
 	Deliver:      {},
+	AutoApprove:  {},
 }
 
 // Exist judge enum value exist.
@@ -163,3 +172,19 @@ func (a AuditAssignedResType) Exist() bool {
 	_, exist := AuditAssignedResTypeEnums[a]
 	return exist
 }
+
+// AuditChainIssueType is the issue type found when verifying the audit hash chain.
+type AuditChainIssueType string
+
+const (
+	// AuditChainGap 哈希链序号不连续，审计记录被删除
+	AuditChainGap AuditChainIssueType = "gap"
+	// AuditChainDuplicate 哈希链序号重复
+	AuditChainDuplicate AuditChainIssueType = "duplicate"
+	// AuditChainModified 审计记录内容与其哈希不一致，审计记录被修改
+	AuditChainModified AuditChainIssueType = "modified"
+	// AuditChainBrokenLink 审计记录的prev_hash与前一条记录的哈希不一致
+	AuditChainBrokenLink AuditChainIssueType = "broken_link"
+	// AuditChainHeadMismatch 哈希链的最新记录与链头表记录不一致，审计记录被截断或链头被篡改
+	AuditChainHeadMismatch AuditChainIssueType = "head_mismatch"
+)
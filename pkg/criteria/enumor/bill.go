@@ -231,6 +231,9 @@ const (
 
 	// HuaweiSupportMonthTask 华为support plan
 	HuaweiSupportMonthTask MonthTaskType = "support"
+
+	// TCloudCommonExpenseMonthTask 腾讯云集团管理账号自身支出分摊
+	TCloudCommonExpenseMonthTask MonthTaskType = "common_expense"
 )
 
 // MonthTaskStep 月度任务步骤
@@ -278,3 +281,26 @@ var (
 		RootAccountBillSummaryStateStop:       "停止中",
 	}
 )
+
+// BillAllocationMethod 账单分摊方式
+type BillAllocationMethod string
+
+const (
+	// BillAllocationFixedRatio 按固定比例分摊到多个业务
+	BillAllocationFixedRatio BillAllocationMethod = "fixed_ratio"
+	// BillAllocationResUsage 按资源-使用业务关联关系平均分摊
+	BillAllocationResUsage BillAllocationMethod = "res_usage"
+	// BillAllocationTag 按资源标签值分摊到对应业务
+	BillAllocationTag BillAllocationMethod = "tag"
+)
+
+// Validate the BillAllocationMethod is valid or not
+func (m BillAllocationMethod) Validate() error {
+	switch m {
+	case BillAllocationFixedRatio, BillAllocationResUsage, BillAllocationTag:
+	default:
+		return fmt.Errorf("unsupported bill allocation method: %s", m)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// IdleResourceReason 资源被识别为闲置资源的原因
+type IdleResourceReason string
+
+const (
+	// IdleUnattachedDisk 云硬盘未挂载到任何主机
+	IdleUnattachedDisk IdleResourceReason = "unattached_disk"
+	// IdleUnboundEip 弹性IP未绑定任何主机
+	IdleUnboundEip IdleResourceReason = "unbound_eip"
+	// IdleStoppedCvm 主机已关机但仍在计费
+	IdleStoppedCvm IdleResourceReason = "stopped_cvm"
+	// IdleLbNoListener 负载均衡没有监听器
+	IdleLbNoListener IdleResourceReason = "lb_no_listener"
+	// IdleLbNoTarget 负载均衡的监听器下没有后端服务
+	IdleLbNoTarget IdleResourceReason = "lb_no_target"
+)
+
+// Validate the IdleResourceReason is valid or not
+func (r IdleResourceReason) Validate() error {
+	switch r {
+	case IdleUnattachedDisk, IdleUnboundEip, IdleStoppedCvm, IdleLbNoListener, IdleLbNoTarget:
+	default:
+		return fmt.Errorf("unsupported idle resource reason: %s", r)
+	}
+
+	return nil
+}
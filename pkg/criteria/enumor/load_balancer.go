@@ -168,6 +168,10 @@ const (
 		FlowBatchTaskListenerModifyRsWeight)
 	// DeleteListenerTaskType 任务类型-批量删除监听器
 	DeleteListenerTaskType = TaskType(FlowBatchTaskDeleteListener)
+	// ApplySpecTaskType 任务类型-应用负载均衡声明式配置
+	ApplySpecTaskType = TaskType(FlowLoadBalancerApplySpec)
+	// TrafficShiftTaskType 任务类型-目标组渐进式流量切换
+	TrafficShiftTaskType = TaskType(FlowTargetGroupTrafficShift)
 )
 
 // InstType 实例类型
@@ -242,3 +246,108 @@ const (
 	// DisableListenerHealthCheck 关闭监听器健康检查
 	DisableListenerHealthCheck ListenerHealthCheckStr = "disable"
 )
+
+// LbSnapshotSource 负载均衡配置快照来源
+type LbSnapshotSource string
+
+const (
+	// LbSnapshotBatchBindRs 批量绑定RS前的快照
+	LbSnapshotBatchBindRs LbSnapshotSource = "batch_bind_rs"
+	// LbSnapshotBatchUnbindRs 批量解绑RS前的快照
+	LbSnapshotBatchUnbindRs LbSnapshotSource = "batch_unbind_rs"
+	// LbSnapshotBatchModifyRsWeight 批量调整RS权重前的快照
+	LbSnapshotBatchModifyRsWeight LbSnapshotSource = "batch_modify_rs_weight"
+	// LbSnapshotSpecApply 应用声明式配置前的快照
+	LbSnapshotSpecApply LbSnapshotSource = "spec_apply"
+	// LbSnapshotRollback 回滚到历史快照前的快照
+	LbSnapshotRollback LbSnapshotSource = "rollback"
+	// LbSnapshotTrafficShift 渐进式流量切换前的快照
+	LbSnapshotTrafficShift LbSnapshotSource = "traffic_shift"
+)
+
+// Validate 快照来源是否合法
+func (s LbSnapshotSource) Validate() error {
+	switch s {
+	case LbSnapshotBatchBindRs, LbSnapshotBatchUnbindRs, LbSnapshotBatchModifyRsWeight, LbSnapshotSpecApply,
+		LbSnapshotRollback, LbSnapshotTrafficShift:
+	default:
+		return fmt.Errorf("unsupported load balancer snapshot source: %s", s)
+	}
+	return nil
+}
+
+// TrafficShiftState 渐进式流量切换状态
+type TrafficShiftState string
+
+const (
+	// TrafficShiftRunning 切换中
+	TrafficShiftRunning TrafficShiftState = "running"
+	// TrafficShiftPaused 已暂停，恢复后从下一步继续
+	TrafficShiftPaused TrafficShiftState = "paused"
+	// TrafficShiftSuccess 已完成，流量全部切换到新RS
+	TrafficShiftSuccess TrafficShiftState = "success"
+	// TrafficShiftRolledBack 健康检查不通过，已自动回滚到切换前的权重
+	TrafficShiftRolledBack TrafficShiftState = "rolled_back"
+	// TrafficShiftAborted 已中止，已回滚到切换前的权重
+	TrafficShiftAborted TrafficShiftState = "aborted"
+)
+
+// IsFinished 是否为终态
+func (s TrafficShiftState) IsFinished() bool {
+	return s == TrafficShiftSuccess || s == TrafficShiftRolledBack || s == TrafficShiftAborted
+}
+
+// TrafficShiftControl 渐进式流量切换控制指令
+type TrafficShiftControl string
+
+const (
+	// TrafficShiftControlPause 暂停，当前步骤结束后不再继续
+	TrafficShiftControlPause TrafficShiftControl = "pause"
+	// TrafficShiftControlResume 恢复
+	TrafficShiftControlResume TrafficShiftControl = "resume"
+	// TrafficShiftControlPromote 提前完成，直接切换到最终权重
+	TrafficShiftControlPromote TrafficShiftControl = "promote"
+	// TrafficShiftControlAbort 中止，回滚到切换前的权重
+	TrafficShiftControlAbort TrafficShiftControl = "abort"
+)
+
+// Validate 控制指令是否合法
+func (c TrafficShiftControl) Validate() error {
+	switch c {
+	case TrafficShiftControlPause, TrafficShiftControlResume, TrafficShiftControlPromote, TrafficShiftControlAbort:
+	default:
+		return fmt.Errorf("unsupported traffic shift control: %s", c)
+	}
+	return nil
+}
+
+// TrafficShiftTargetRole 渐进式流量切换中RS的角色
+type TrafficShiftTargetRole string
+
+const (
+	// TrafficShiftTargetOld 流量切出的旧RS，权重逐步降为0
+	TrafficShiftTargetOld TrafficShiftTargetRole = "old"
+	// TrafficShiftTargetNew 流量切入的新RS，权重逐步升到目标权重
+	TrafficShiftTargetNew TrafficShiftTargetRole = "new"
+)
+
+// TargetHealthStatus RS健康状态
+type TargetHealthStatus string
+
+const (
+	// TargetHealthy 健康
+	TargetHealthy TargetHealthStatus = "healthy"
+	// TargetUnhealthy 不健康，包括尚未开始探测、探测中、状态异常等几种状态
+	TargetUnhealthy TargetHealthStatus = "unhealthy"
+)
+
+// Validate TargetHealthStatus.
+func (s TargetHealthStatus) Validate() error {
+	switch s {
+	case TargetHealthy, TargetUnhealthy:
+	default:
+		return fmt.Errorf("unsupported target health status: %s", s)
+	}
+
+	return nil
+}
@@ -79,6 +79,11 @@ type MainAccountCommonFields struct {
 
 // MainAccountNameFieldNameMap is the map of main account fields name, only use for main account management
 var MainAccountNameFieldNameMap = map[Vendor]MainAccountCommonFields{
+	TCloud: {
+		AccountName:  "cloud_main_account_name",
+		AccountID:    "cloud_main_account_id",
+		InitPassword: "cloud_init_password",
+	},
 	Aws: {
 		AccountName:  "cloud_main_account_name",
 		AccountID:    "cloud_main_account_id",
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// ReachabilityEndpointType 网络可达性分析的端点类型
+type ReachabilityEndpointType string
+
+const (
+	// CvmEndpoint 主机，使用主机的内网、公网IP及绑定的安全组
+	CvmEndpoint ReachabilityEndpointType = "cvm"
+	// IPEndpoint IP地址，属于已同步的主机时按照主机处理，属于指定VPC的子网时按照子网内地址处理，否则视为公网地址
+	IPEndpoint ReachabilityEndpointType = "ip"
+	// ClbListenerEndpoint 负载均衡监听器，使用负载均衡的VIP及绑定的安全组
+	ClbListenerEndpoint ReachabilityEndpointType = "clb_listener"
+)
+
+// Validate the ReachabilityEndpointType is valid or not
+func (t ReachabilityEndpointType) Validate() error {
+	switch t {
+	case CvmEndpoint, IPEndpoint, ClbListenerEndpoint:
+	default:
+		return fmt.Errorf("unsupported reachability endpoint type: %s", t)
+	}
+
+	return nil
+}
+
+// ReachabilityHopType 网络路径上的检查环节
+type ReachabilityHopType string
+
+const (
+	// SourceSGHop 源端安全组出站规则
+	SourceSGHop ReachabilityHopType = "source_security_group"
+	// RouteHop 源端子网路由
+	RouteHop ReachabilityHopType = "route"
+	// DestinationSGHop 目的端安全组入站规则
+	DestinationSGHop ReachabilityHopType = "destination_security_group"
+)
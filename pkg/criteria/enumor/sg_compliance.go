@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// SGComplianceSeverity 安全组规则合规检查的风险等级
+type SGComplianceSeverity string
+
+const (
+	// SGComplianceLow 低风险
+	SGComplianceLow SGComplianceSeverity = "low"
+	// SGComplianceMedium 中风险
+	SGComplianceMedium SGComplianceSeverity = "medium"
+	// SGComplianceHigh 高风险
+	SGComplianceHigh SGComplianceSeverity = "high"
+)
+
+// Validate the SGComplianceSeverity is valid or not
+func (s SGComplianceSeverity) Validate() error {
+	switch s {
+	case SGComplianceLow, SGComplianceMedium, SGComplianceHigh:
+	default:
+		return fmt.Errorf("unsupported security group compliance severity: %s", s)
+	}
+
+	return nil
+}
+
+// Level 风险等级的数值，数值越大风险越高，用于比较风险等级
+func (s SGComplianceSeverity) Level() int {
+	switch s {
+	case SGComplianceLow:
+		return 1
+	case SGComplianceMedium:
+		return 2
+	case SGComplianceHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// SGComplianceBuiltinCheck 安全组规则内置合规检查项
+type SGComplianceBuiltinCheck string
+
+const (
+	// SGCheckPublicSensitivePort 高危端口(SSH、RDP、数据库等)对全网开放
+	SGCheckPublicSensitivePort SGComplianceBuiltinCheck = "public_sensitive_port"
+	// SGCheckWidePortRange 放通的端口范围过大
+	SGCheckWidePortRange SGComplianceBuiltinCheck = "wide_port_range"
+	// SGCheckDuplicateRule 同一安全组内存在重复规则
+	SGCheckDuplicateRule SGComplianceBuiltinCheck = "duplicate_rule"
+	// SGCheckShadowedRule 规则被更高优先级的规则完全覆盖，不会生效
+	SGCheckShadowedRule SGComplianceBuiltinCheck = "shadowed_rule"
+	// SGCheckInvalidArgsTpl 规则引用的参数模版已被删除
+	SGCheckInvalidArgsTpl SGComplianceBuiltinCheck = "invalid_argument_template"
+)
+
+// SGComplianceBuiltinChecks 所有内置检查项
+var SGComplianceBuiltinChecks = []SGComplianceBuiltinCheck{SGCheckPublicSensitivePort, SGCheckWidePortRange,
+	SGCheckDuplicateRule, SGCheckShadowedRule, SGCheckInvalidArgsTpl}
+
+// Validate the SGComplianceBuiltinCheck is valid or not
+func (c SGComplianceBuiltinCheck) Validate() error {
+	for _, one := range SGComplianceBuiltinChecks {
+		if c == one {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported security group compliance builtin check: %s", c)
+}
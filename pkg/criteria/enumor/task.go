@@ -150,6 +150,8 @@ const (
 	TaskTargetGroupModifyPort TaskOperation = "target_group_modify_port"
 	// TaskTargetGroupModifyWeight is a task indicating that modify target group weight.
 	TaskTargetGroupModifyWeight TaskOperation = "target_group_modify_weight"
+	// TaskTargetGroupTrafficShift is a task indicating that shift target group traffic progressively.
+	TaskTargetGroupTrafficShift TaskOperation = "target_group_traffic_shift"
 	// TaskListenerAddTarget is a task indicating that add target to listener rule.
 	TaskListenerAddTarget TaskOperation = "listener_add_target"
 )
@@ -0,0 +1,244 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"hcm/pkg/cc"
+
+	"github.com/TencentBlueKing/gopkg/conv"
+)
+
+// versionPrefix 带版本密文的前缀，格式为 v{version}$，Base64 字符集不包含 $，可与历史密文区分
+const (
+	versionPrefix    = 'v'
+	versionDelimiter = '$'
+)
+
+// Rotator 支持密钥轮换的加解密器
+type Rotator interface {
+	Crypto
+	// ReEncryptFromBase64 将Base64格式的密文使用当前密钥重新加密，密文已经是当前密钥加密时 changed 为false
+	ReEncryptFromBase64(encryptedTextB64 string) (reEncrypted string, changed bool, err error)
+}
+
+var _ Rotator = new(Keyring)
+
+// Keyring AES Gcm多版本密钥环，使用当前版本密钥加密，并在密文中记录密钥版本，解密时根据版本选择对应密钥。
+// 不带版本标识的历史密文使用 legacy 密钥解密。
+type Keyring struct {
+	current uint
+	keys    map[uint]cipher.AEAD
+	legacy  *AESGcm
+}
+
+// NewKeyring returns a new keyring, legacy is used to decrypt cipher text without version, can be nil.
+func NewKeyring(current uint, keys map[uint][]byte, legacy *AESGcm) (*Keyring, error) {
+	k := &Keyring{
+		current: current,
+		keys:    make(map[uint]cipher.AEAD, len(keys)),
+		legacy:  legacy,
+	}
+
+	for version, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key of version %d, err: %v", version, err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key of version %d, err: %v", version, err)
+		}
+		k.keys[version] = aead
+	}
+
+	if _, exists := k.keys[current]; !exists {
+		return nil, fmt.Errorf("current key version %d not exists", current)
+	}
+
+	return k, nil
+}
+
+// NewFromConfig 根据配置文件里的加密配置生成对应的加解密器，配置了密钥环时返回 Keyring，否则返回 AESGcm
+func NewFromConfig(cryptoConfig cc.Crypto) (Crypto, error) {
+	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
+	cfg := cryptoConfig.AesGcm
+	legacy, err := NewAESGcm([]byte(cfg.Key), []byte(cfg.Nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	if !cryptoConfig.Keyring.Enabled() {
+		return legacy, nil
+	}
+
+	keys := make(map[uint][]byte, len(cryptoConfig.Keyring.Keys))
+	for _, key := range cryptoConfig.Keyring.Keys {
+		keys[key.Version] = []byte(key.Key)
+	}
+
+	return NewKeyring(cryptoConfig.Keyring.CurrentVersion, keys, legacy)
+}
+
+// CurrentVersion returns the key version used to encrypt.
+func (k *Keyring) CurrentVersion() uint {
+	return k.current
+}
+
+// Encrypt 使用当前密钥加密，密文格式为 v{version}${nonce}{encrypted}
+func (k *Keyring) Encrypt(plaintext []byte) []byte {
+	aead := k.keys[k.current]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		// 随机数生成失败意味着系统环境异常，无法保证加密安全
+		panic(fmt.Sprintf("generate nonce failed, err: %v", err))
+	}
+
+	prefix := formatVersion(k.current)
+	out := make([]byte, 0, len(prefix)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, prefix...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, nil)
+}
+
+// Decrypt 根据密文中的版本选择密钥解密，不带版本标识的密文使用 legacy 密钥解密
+func (k *Keyring) Decrypt(encryptedText []byte) ([]byte, error) {
+	version, payload, ok := parseVersion(encryptedText)
+	if !ok {
+		return k.decryptLegacy(encryptedText)
+	}
+
+	plaintext, err := k.decryptVersion(version, payload)
+	if err != nil && k.legacy != nil {
+		// 历史密文可能恰好以版本前缀开头，解密失败时再尝试使用 legacy 密钥
+		if legacyPlaintext, legacyErr := k.legacy.Decrypt(encryptedText); legacyErr == nil {
+			return legacyPlaintext, nil
+		}
+	}
+	return plaintext, err
+}
+
+// EncryptToString encrypts plaintext to string
+func (k *Keyring) EncryptToString(plaintext []byte) string {
+	return conv.BytesToString(k.Encrypt(plaintext))
+}
+
+// DecryptString decrypts ciphertext string
+func (k *Keyring) DecryptString(encryptedText string) ([]byte, error) {
+	return k.Decrypt([]byte(encryptedText))
+}
+
+// EncryptToBase64 使用当前密钥加密，密文格式为 v{version}${base64(nonce+encrypted)}
+func (k *Keyring) EncryptToBase64(plaintext string) string {
+	encryptedText := k.Encrypt([]byte(plaintext))
+	prefixLen := len(formatVersion(k.current))
+	return string(encryptedText[:prefixLen]) + base64.StdEncoding.EncodeToString(encryptedText[prefixLen:])
+}
+
+// DecryptFromBase64 根据密文中的版本选择密钥解密，不带版本标识的密文使用 legacy 密钥解密
+func (k *Keyring) DecryptFromBase64(encryptedTextB64 string) (string, error) {
+	version, payloadB64, ok := parseVersion([]byte(encryptedTextB64))
+	if !ok {
+		if k.legacy == nil {
+			return "", errors.New("encrypted text has no key version")
+		}
+		return k.legacy.DecryptFromBase64(encryptedTextB64)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(string(payloadB64))
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := k.decryptVersion(version, payload)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// ReEncryptFromBase64 将Base64格式的密文使用当前密钥重新加密
+func (k *Keyring) ReEncryptFromBase64(encryptedTextB64 string) (string, bool, error) {
+	if version, _, ok := parseVersion([]byte(encryptedTextB64)); ok && version == k.current {
+		return encryptedTextB64, false, nil
+	}
+
+	plaintext, err := k.DecryptFromBase64(encryptedTextB64)
+	if err != nil {
+		return "", false, err
+	}
+
+	return k.EncryptToBase64(plaintext), true, nil
+}
+
+func (k *Keyring) decryptVersion(version uint, payload []byte) ([]byte, error) {
+	aead, exists := k.keys[version]
+	if !exists {
+		return nil, fmt.Errorf("key of version %d not exists in keyring", version)
+	}
+
+	if len(payload) < aead.NonceSize() {
+		return nil, errors.New("encrypted text is too short")
+	}
+
+	nonce, encrypted := payload[:aead.NonceSize()], payload[aead.NonceSize():]
+	return aead.Open(nil, nonce, encrypted, nil)
+}
+
+func (k *Keyring) decryptLegacy(encryptedText []byte) ([]byte, error) {
+	if k.legacy == nil {
+		return nil, errors.New("encrypted text has no key version")
+	}
+	return k.legacy.Decrypt(encryptedText)
+}
+
+func formatVersion(version uint) []byte {
+	return []byte(string(versionPrefix) + strconv.FormatUint(uint64(version), 10) + string(versionDelimiter))
+}
+
+// parseVersion 解析密文中的版本，返回版本号及去掉版本前缀后的内容
+func parseVersion(encryptedText []byte) (uint, []byte, bool) {
+	if len(encryptedText) < 3 || encryptedText[0] != versionPrefix {
+		return 0, nil, false
+	}
+
+	idx := bytes.IndexByte(encryptedText, versionDelimiter)
+	if idx < 2 {
+		return 0, nil, false
+	}
+
+	version, err := strconv.ParseUint(string(encryptedText[1:idx]), 10, 32)
+	if err != nil {
+		return 0, nil, false
+	}
+
+	return uint(version), encryptedText[idx+1:], true
+}
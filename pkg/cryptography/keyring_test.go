@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cryptography
+
+import (
+	"strings"
+	"testing"
+
+	"hcm/pkg/cc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testLegacyKey   = "0123456789abcdef"
+	testLegacyNonce = "0123456789ab"
+	testKeyV1       = "1111111111111111"
+	testKeyV2       = "22222222222222222222222222222222"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	legacy, err := NewAESGcm([]byte(testLegacyKey), []byte(testLegacyNonce))
+	assert.NoError(t, err)
+	legacyEncrypted := legacy.EncryptToBase64("legacy-secret")
+
+	ringV1, err := NewKeyring(1, map[uint][]byte{1: []byte(testKeyV1)}, legacy)
+	assert.NoError(t, err)
+	v1Encrypted := ringV1.EncryptToBase64("v1-secret")
+	assert.True(t, strings.HasPrefix(v1Encrypted, "v1$"))
+	// 每次加密使用随机nonce，相同明文密文不同
+	assert.NotEqual(t, v1Encrypted, ringV1.EncryptToBase64("v1-secret"))
+
+	// 轮换到 v2 后，历史密文仍可解密
+	ringV2, err := NewKeyring(2, map[uint][]byte{1: []byte(testKeyV1), 2: []byte(testKeyV2)}, legacy)
+	assert.NoError(t, err)
+
+	plaintext, err := ringV2.DecryptFromBase64(legacyEncrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy-secret", plaintext)
+
+	plaintext, err = ringV2.DecryptFromBase64(v1Encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1-secret", plaintext)
+
+	// 重新加密为当前版本，当前版本的密文不需要再重新加密
+	for _, encrypted := range []string{legacyEncrypted, v1Encrypted} {
+		reEncrypted, changed, err := ringV2.ReEncryptFromBase64(encrypted)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.True(t, strings.HasPrefix(reEncrypted, "v2$"))
+
+		again, changed, err := ringV2.ReEncryptFromBase64(reEncrypted)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, reEncrypted, again)
+	}
+
+	// 密钥被移除后无法解密
+	ringOnlyV2, err := NewKeyring(2, map[uint][]byte{2: []byte(testKeyV2)}, nil)
+	assert.NoError(t, err)
+	_, err = ringOnlyV2.DecryptFromBase64(v1Encrypted)
+	assert.Error(t, err)
+	_, err = ringOnlyV2.DecryptFromBase64(legacyEncrypted)
+	assert.Error(t, err)
+
+	// 字节格式加解密
+	encrypted := ringV2.Encrypt([]byte("bytes-secret"))
+	decrypted, err := ringV2.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "bytes-secret", string(decrypted))
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := cc.Crypto{AesGcm: cc.AesGcm{Key: testLegacyKey, Nonce: testLegacyNonce}}
+	cipher, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	_, isRotator := cipher.(Rotator)
+	assert.False(t, isRotator)
+
+	cfg.Keyring = cc.AesGcmKeyring{
+		CurrentVersion: 2,
+		Keys:           []cc.AesGcmVersionKey{{Version: 1, Key: testKeyV1}, {Version: 2, Key: testKeyV2}},
+	}
+	cipher, err = NewFromConfig(cfg)
+	assert.NoError(t, err)
+	_, isRotator = cipher.(Rotator)
+	assert.True(t, isRotator)
+	assert.True(t, strings.HasPrefix(cipher.EncryptToBase64("secret"), "v2$"))
+
+	_, err = NewKeyring(3, map[uint][]byte{1: []byte(testKeyV1)}, nil)
+	assert.Error(t, err)
+}
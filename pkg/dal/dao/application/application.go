@@ -24,13 +24,17 @@ import (
 	"fmt"
 
 	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/audit"
 	idgenerator "hcm/pkg/dal/dao/id-generator"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/dal/table"
 	"hcm/pkg/dal/table/application"
+	tableaudit "hcm/pkg/dal/table/audit"
 	"hcm/pkg/dal/table/utils"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -52,6 +56,7 @@ var _ Application = new(ApplicationDao)
 type ApplicationDao struct {
 	Orm   orm.Interface
 	IDGen idgenerator.IDGenInterface
+	Audit audit.Interface
 }
 
 // CreateWithTx ...
@@ -76,6 +81,31 @@ func (a *ApplicationDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *applicati
 	if err != nil {
 		return "", fmt.Errorf("insert %s failed, err: %v", model.TableName(), err)
 	}
+
+	// 自动审批的申请单记录审计，便于追溯放行申请单的策略
+	if len(model.AutoApprovalPolicyID) != 0 {
+		var bizID int64 = constant.UnassignedBiz
+		if len(model.BkBizIDs) == 1 {
+			bizID = model.BkBizIDs[0]
+		}
+		audits := []*tableaudit.AuditTable{{
+			ResID:    id,
+			ResName:  model.SN,
+			ResType:  enumor.ApplicationAuditResType,
+			Action:   enumor.AutoApprove,
+			BkBizID:  bizID,
+			Operator: kt.User,
+			Source:   kt.GetRequestSource(),
+			Rid:      kt.Rid,
+			AppCode:  kt.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: model},
+		}}
+		if err = a.Audit.BatchCreateWithTx(kt, tx, audits); err != nil {
+			logs.Errorf("create auto approve application audit failed, err: %v, rid: %s", err, kt.Rid)
+			return "", err
+		}
+	}
+
 	return id, nil
 }
 
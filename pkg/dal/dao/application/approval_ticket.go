@@ -0,0 +1,168 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/application"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ApprovalTicket ...
+type ApprovalTicket interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApprovalTicketTable) (string, error)
+	Update(kt *kit.Kit, expr *filter.Expression, model *application.ApprovalTicketTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalTicketDetails, error)
+}
+
+var _ ApprovalTicket = new(ApprovalTicketDao)
+
+// ApprovalTicketDao approval ticket dao.
+type ApprovalTicketDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// CreateWithTx ...
+func (a *ApprovalTicketDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *application.ApprovalTicketTable) (
+	string, error) {
+
+	if err := model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	id, err := a.IDGen.One(kt, table.ApprovalTicketTable)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, model.TableName(),
+		application.ApprovalTicketColumns.ColumnExpr(), application.ApprovalTicketColumns.ColonNameExpr())
+
+	err = a.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(tx).Insert(kt.Ctx, sql, model)
+	if err != nil {
+		return "", fmt.Errorf("insert %s failed, err: %v", model.TableName(), err)
+	}
+
+	return id, nil
+}
+
+// Update 更新审批单，没有记录被更新时返回 errf.RecordNotUpdate，调用方可据此实现乐观锁
+func (a *ApprovalTicketDao) Update(kt *kit.Kit, expr *filter.Expression,
+	model *application.ApprovalTicketTable) error {
+
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, model.TableName(), setExpr, whereExpr)
+
+	_, err = a.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		effected, err := a.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(txn).Update(
+			kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue))
+		if err != nil {
+			logs.ErrorJson("update approval ticket failed, err: %v, filter: %s, rid: %v", err, expr, kt.Rid)
+			return nil, err
+		}
+
+		if effected == 0 {
+			logs.ErrorJson("update approval ticket, but no record updated, filter: %v, rid: %v", expr, kt.Rid)
+			return nil, errf.New(errf.RecordNotUpdate, "approval ticket not found or has been changed")
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// List ...
+func (a *ApprovalTicketDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListApprovalTicketDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list approval ticket options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(application.ApprovalTicketColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ApprovalTicketTable, whereExpr)
+
+		count, err := a.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count approval ticket failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListApprovalTicketDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, application.ApprovalTicketColumns.FieldsNamedExpr(opt.Fields),
+		table.ApprovalTicketTable, whereExpr, pageExpr)
+
+	details := make([]application.ApprovalTicketTable, 0)
+	err = a.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Do().Select(kt.Ctx, &details, sql, whereValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ListApprovalTicketDetails{Details: details}, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AdvisoryLock 基于MySQL用户锁(GET_LOCK)的分布式锁，锁与数据库会话绑定，持有锁期间独占一个数据库连接，
+// 连接断开(如节点异常退出)后锁自动释放。
+type AdvisoryLock interface {
+	// TryLock 尝试获取锁，不等待。已持有锁时检查锁是否仍然有效，返回当前是否持有锁。
+	TryLock(ctx context.Context) (bool, error)
+	// Unlock 释放锁并归还数据库连接
+	Unlock(ctx context.Context) error
+}
+
+// NewAdvisoryLock new advisory lock.
+func NewAdvisoryLock(db *sqlx.DB, name string) AdvisoryLock {
+	return &advisoryLock{
+		db:   db,
+		name: name,
+	}
+}
+
+type advisoryLock struct {
+	db   *sqlx.DB
+	name string
+
+	lock sync.Mutex
+	// conn 持有锁的数据库连接，未持有锁时为nil
+	conn *sqlx.Conn
+}
+
+// TryLock 尝试获取锁
+func (l *advisoryLock) TryLock(ctx context.Context) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.conn != nil {
+		var held sql.NullInt64
+		err := l.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", l.name).Scan(&held)
+		if err == nil && held.Valid && held.Int64 == 1 {
+			return true, nil
+		}
+
+		// 连接已断开或锁已丢失，归还连接后重新获取
+		l.closeConn()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	conn, err := l.db.Connx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired sql.NullInt64
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, err
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Unlock 释放锁
+func (l *advisoryLock) Unlock(ctx context.Context) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	_, err := l.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.name)
+	l.closeConn()
+	return err
+}
+
+func (l *advisoryLock) closeConn() {
+	_ = l.conn.Close()
+	l.conn = nil
+}
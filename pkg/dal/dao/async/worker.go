@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// AsyncWorker only used async worker heartbeat.
+type AsyncWorker interface {
+	Heartbeat(kt *kit.Kit, worker string) error
+	ListAlive(kt *kit.Kit, expireSec uint) ([]string, error)
+	Delete(kt *kit.Kit, worker string) error
+}
+
+var _ AsyncWorker = new(AsyncWorkerDao)
+
+// AsyncWorkerDao async worker dao.
+type AsyncWorkerDao struct {
+	Orm orm.Interface
+}
+
+// Heartbeat 上报节点心跳，节点不存在时创建。心跳时间使用数据库时间，避免节点间时钟不一致。
+func (dao *AsyncWorkerDao) Heartbeat(kt *kit.Kit, worker string) error {
+	if len(worker) == 0 {
+		return errf.New(errf.InvalidParameter, "worker is required")
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (worker, heartbeat_at) VALUES (:worker, NOW())
+		ON DUPLICATE KEY UPDATE heartbeat_at = NOW()`, table.AsyncWorkerTable)
+	if _, err := dao.Orm.Do().Update(kt.Ctx, sql, map[string]interface{}{"worker": worker}); err != nil {
+		logs.Errorf("upsert async worker heartbeat failed, err: %v, worker: %s, rid: %s", err, worker, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListAlive 查询最近expireSec秒内有心跳的节点
+func (dao *AsyncWorkerDao) ListAlive(kt *kit.Kit, expireSec uint) ([]string, error) {
+	if expireSec == 0 {
+		return nil, errf.New(errf.InvalidParameter, "expire sec is required")
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE heartbeat_at >= DATE_SUB(NOW(), INTERVAL :expire_sec SECOND)
+		ORDER BY worker`, tableasync.AsyncWorkerColumns.FieldsNamedExpr(nil), table.AsyncWorkerTable)
+	details := make([]tableasync.AsyncWorkerTable, 0)
+	err := dao.Orm.Do().Select(kt.Ctx, &details, sql, map[string]interface{}{"expire_sec": expireSec})
+	if err != nil {
+		logs.Errorf("select alive async worker failed, err: %v, sql: %s, rid: %s", err, sql, kt.Rid)
+		return nil, err
+	}
+
+	workers := make([]string, 0, len(details))
+	for _, one := range details {
+		workers = append(workers, one.Worker)
+	}
+
+	return workers, nil
+}
+
+// Delete 删除节点心跳记录，节点正常退出时调用，使其立即从存活节点中移除
+func (dao *AsyncWorkerDao) Delete(kt *kit.Kit, worker string) error {
+	if len(worker) == 0 {
+		return errf.New(errf.InvalidParameter, "worker is required")
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE worker = :worker`, table.AsyncWorkerTable)
+	if _, err := dao.Orm.Do().Delete(kt.Ctx, sql, map[string]interface{}{"worker": worker}); err != nil {
+		logs.Errorf("delete async worker failed, err: %v, worker: %s, rid: %s", err, worker, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -22,8 +22,11 @@ package audit
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
@@ -31,6 +34,7 @@ import (
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/dal/table"
 	"hcm/pkg/dal/table/audit"
+	tabletype "hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
@@ -44,6 +48,10 @@ type Interface interface {
 	BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error
 	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error
 	List(kt *kit.Kit, opt *types.ListOption) (*types.ListAuditDetails, error)
+	GetChain(kt *kit.Kit, tenantID string) (*audit.AuditChainTable, error)
+	ListChain(kt *kit.Kit) ([]audit.AuditChainTable, error)
+	ListChainRecords(kt *kit.Kit, tenantID string, startSeq, startID uint64, limit uint) ([]audit.AuditTable, error)
+	UpdateChainExportSeq(kt *kit.Kit, tenantID string, exportSeq uint64) error
 }
 
 var _ Interface = new(Dao)
@@ -67,33 +75,33 @@ func (d Dao) Create(kt *kit.Kit, one *audit.AuditTable) error {
 
 // BatchCreate batch create audit.
 func (d Dao) BatchCreate(kt *kit.Kit, audits []*audit.AuditTable) error {
+	_, err := d.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, d.BatchCreateWithTx(kt, txn, audits)
+	})
+	return err
+}
+
+// BatchCreateWithTx batch create audit with tx, audits are appended to the tenant's hash chain in the same tx.
+func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
 	for _, one := range audits {
 		if err := one.CreateValidate(); err != nil {
 			return err
 		}
 	}
 
-	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
-		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
-	err := d.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Do().BulkInsert(kt.Ctx, sql, audits)
-	if err != nil {
-		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditTable, err, kt.Rid)
-		return fmt.Errorf("insert %s failed, err: %v", table.AuditTable, err)
+	// created_at is covered by the chain hash, so it's assigned before hashing and written as it is instead of now()
+	createdAt := time.Now().Truncate(time.Second)
+	for _, one := range audits {
+		one.CreatedAt = tabletype.Time(createdAt.Format(constant.TimeStdFormat))
 	}
 
-	return nil
-}
-
-// BatchCreateWithTx batch create audit with tx.
-func (d Dao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
-	for _, one := range audits {
-		if err := one.CreateValidate(); err != nil {
-			return err
-		}
+	if err := d.appendToChain(kt, tx, audits); err != nil {
+		return err
 	}
 
-	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable,
-		audit.AuditColumns.ColumnExpr(), audit.AuditColumns.ColonNameExpr())
+	valueExpr := strings.Replace(audit.AuditColumns.ColonNameExpr(), "now()",
+		fmt.Sprintf("FROM_UNIXTIME(%d)", createdAt.Unix()), 1)
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AuditTable, audit.AuditColumns.ColumnExpr(), valueExpr)
 	err := d.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(tx).BulkInsert(kt.Ctx, sql, audits)
 	if err != nil {
 		logs.Errorf("insert %s failed, err: %v, rid: %s", table.AuditTable, err, kt.Rid)
@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"fmt"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ChainTenantID returns the tenant id of the audit hash chain that the audits of the tenant are appended to, it's
+// the same as the tenant_id column of the inserted audits.
+func ChainTenantID(tenantID string) string {
+	if !cc.TenantEnable() || tenantID == "" {
+		return constant.DefaultTenantID
+	}
+	return tenantID
+}
+
+// appendToChain lock the tenant's chain head, then fill the chain seq and hashes of audits and move the head forward,
+// audits of the same tenant are appended to the chain one tx after another.
+func (d Dao) appendToChain(kt *kit.Kit, tx *sqlx.Tx, audits []*audit.AuditTable) error {
+	if len(audits) == 0 {
+		return nil
+	}
+
+	tenantID := ChainTenantID(kt.TenantID)
+	txn := d.Orm.Txn(tx)
+
+	// ON DUPLICATE KEY UPDATE takes an exclusive lock on the existing head, INSERT IGNORE only takes a shared lock
+	// and upgrading it by the following FOR UPDATE deadlocks between concurrent txs of the same tenant.
+	initSql := fmt.Sprintf(`INSERT INTO %s (tenant_id, last_seq, last_hash, export_seq)
+		VALUES (:tenant_id, 0, '', 0) ON DUPLICATE KEY UPDATE tenant_id = tenant_id`, table.AuditChainTable)
+	if err := txn.Insert(kt.Ctx, initSql, map[string]interface{}{"tenant_id": tenantID}); err != nil {
+		logs.Errorf("init %s failed, err: %v, tenant: %s, rid: %s", table.AuditChainTable, err, tenantID, kt.Rid)
+		return fmt.Errorf("init %s failed, err: %v", table.AuditChainTable, err)
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE tenant_id = :tenant_id FOR UPDATE`,
+		audit.AuditChainColumns.NamedExpr(), table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0)
+	if err := txn.Select(kt.Ctx, &heads, sql, map[string]interface{}{"tenant_id": tenantID}); err != nil {
+		logs.Errorf("lock %s failed, err: %v, tenant: %s, rid: %s", table.AuditChainTable, err, tenantID, kt.Rid)
+		return fmt.Errorf("lock %s failed, err: %v", table.AuditChainTable, err)
+	}
+	if len(heads) != 1 {
+		return fmt.Errorf("audit chain head of tenant %s not found", tenantID)
+	}
+
+	seq, prevHash := heads[0].LastSeq, heads[0].LastHash
+	for _, one := range audits {
+		seq++
+		one.ChainSeq = seq
+		one.PrevHash = prevHash
+		hash, err := one.ChainHash(tenantID)
+		if err != nil {
+			logs.Errorf("calculate audit chain hash failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+		one.Hash = hash
+		prevHash = hash
+	}
+
+	updateSql := fmt.Sprintf(`UPDATE %s SET last_seq = :last_seq, last_hash = :last_hash WHERE tenant_id = :tenant_id`,
+		table.AuditChainTable)
+	args := map[string]interface{}{"tenant_id": tenantID, "last_seq": seq, "last_hash": prevHash}
+	if _, err := txn.Update(kt.Ctx, updateSql, args); err != nil {
+		logs.Errorf("update %s failed, err: %v, tenant: %s, rid: %s", table.AuditChainTable, err, tenantID, kt.Rid)
+		return fmt.Errorf("update %s failed, err: %v", table.AuditChainTable, err)
+	}
+
+	return nil
+}
+
+// GetChain get the audit hash chain head of tenant, returns nil if no audit is appended to the chain yet.
+func (d Dao) GetChain(kt *kit.Kit, tenantID string) (*audit.AuditChainTable, error) {
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE tenant_id = :tenant_id`, audit.AuditChainColumns.NamedExpr(),
+		table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0)
+	if err := d.Orm.Do().Select(kt.Ctx, &heads, sql, map[string]interface{}{"tenant_id": tenantID}); err != nil {
+		logs.Errorf("get %s failed, err: %v, tenant: %s, rid: %s", table.AuditChainTable, err, tenantID, kt.Rid)
+		return nil, err
+	}
+
+	if len(heads) == 0 {
+		return nil, nil
+	}
+	return &heads[0], nil
+}
+
+// ListChain list the audit hash chain heads of all tenants.
+func (d Dao) ListChain(kt *kit.Kit) ([]audit.AuditChainTable, error) {
+	sql := fmt.Sprintf(`SELECT %s FROM %s ORDER BY tenant_id`, audit.AuditChainColumns.NamedExpr(),
+		table.AuditChainTable)
+	heads := make([]audit.AuditChainTable, 0)
+	if err := d.Orm.Do().Select(kt.Ctx, &heads, sql, map[string]interface{}{}); err != nil {
+		logs.Errorf("list %s failed, err: %v, rid: %s", table.AuditChainTable, err, kt.Rid)
+		return nil, err
+	}
+
+	return heads, nil
+}
+
+// ListChainRecords list at most limit audits on the tenant's hash chain in order of chain seq and id, starting from
+// the position of (startSeq, startID).
+func (d Dao) ListChainRecords(kt *kit.Kit, tenantID string, startSeq, startID uint64, limit uint) (
+	[]audit.AuditTable, error) {
+
+	if limit == 0 {
+		return nil, fmt.Errorf("limit is required")
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s WHERE tenant_id = :tenant_id AND chain_seq >= :start_seq
+		AND (chain_seq > :start_seq OR id >= :start_id) ORDER BY chain_seq, id LIMIT %d`,
+		audit.AuditColumns.NamedExpr(), table.AuditTable, limit)
+	args := map[string]interface{}{"tenant_id": tenantID, "start_seq": startSeq, "start_id": startID}
+	details := make([]audit.AuditTable, 0)
+	if err := d.Orm.Do().Select(kt.Ctx, &details, sql, args); err != nil {
+		logs.Errorf("list audit chain records failed, err: %v, tenant: %s, start: (%d, %d), rid: %s", err,
+			tenantID, startSeq, startID, kt.Rid)
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// UpdateChainExportSeq move forward the exported chain seq of tenant.
+func (d Dao) UpdateChainExportSeq(kt *kit.Kit, tenantID string, exportSeq uint64) error {
+	sql := fmt.Sprintf(`UPDATE %s SET export_seq = :export_seq WHERE tenant_id = :tenant_id
+		AND export_seq < :export_seq`, table.AuditChainTable)
+	args := map[string]interface{}{"tenant_id": tenantID, "export_seq": exportSeq}
+	if _, err := d.Orm.Do().Update(kt.Ctx, sql, args); err != nil {
+		logs.Errorf("update %s export seq failed, err: %v, tenant: %s, seq: %d, rid: %s", table.AuditChainTable,
+			err, tenantID, exportSeq, kt.Rid)
+		return err
+	}
+
+	return nil
+}
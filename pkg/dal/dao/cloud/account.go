@@ -74,6 +74,10 @@ func (a AccountDao) DeleteValidate(kt *kit.Kit, accountID string) (map[string]ui
 		table.AzureSecurityGroupRuleTable:  {},
 		table.TCloudSecurityGroupRuleTable: {},
 		table.HuaWeiSecurityGroupRuleTable: {},
+		table.IdleResourceTable:            {},
+		table.LoadBalancerSnapshotTable:    {},
+		table.TargetHealthEventTable:       {},
+		table.DiskSnapshotPolicyTable:      {},
 	}
 
 	expr := `select table_name as name from information_schema.columns where column_name = :column_name;`
@@ -0,0 +1,192 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgen "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typeslb "hcm/pkg/dal/dao/types/load-balancer"
+	"hcm/pkg/dal/table"
+	tablelb "hcm/pkg/dal/table/cloud/load-balancer"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SnapshotInterface only used for load balancer snapshot.
+type SnapshotInterface interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *tablelb.LoadBalancerSnapshotTable) (string, error)
+	UpdateByIDWithTx(kt *kit.Kit, tx *sqlx.Tx, id string, model *tablelb.LoadBalancerSnapshotTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typeslb.ListLoadBalancerSnapshotDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ SnapshotInterface = new(SnapshotDao)
+
+// SnapshotDao load balancer snapshot dao.
+type SnapshotDao struct {
+	Orm   orm.Interface
+	IDGen idgen.IDGenInterface
+}
+
+// CreateWithTx load balancer snapshot.
+func (dao SnapshotDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *tablelb.LoadBalancerSnapshotTable) (
+	string, error) {
+
+	if model == nil {
+		return "", errf.New(errf.InvalidParameter, "model to create cannot be nil")
+	}
+
+	tableName := model.TableName()
+	id, err := dao.IDGen.One(kt, tableName)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	if err = model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, tableName,
+		tablelb.LoadBalancerSnapshotColumns.ColumnExpr(), tablelb.LoadBalancerSnapshotColumns.ColonNameExpr())
+	err = dao.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(tx).Insert(kt.Ctx, sql, model)
+	if err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", tableName, err, kt.Rid)
+		return "", fmt.Errorf("insert %s failed, err: %v", tableName, err)
+	}
+
+	return id, nil
+}
+
+// UpdateByIDWithTx load balancer snapshot.
+func (dao SnapshotDao) UpdateByIDWithTx(kt *kit.Kit, tx *sqlx.Tx, id string,
+	model *tablelb.LoadBalancerSnapshotTable) error {
+
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s where id = :id`, model.TableName(), setExpr)
+
+	toUpdate["id"] = id
+	effected, err := dao.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(tx).Update(kt.Ctx, sql,
+		toUpdate)
+	if err != nil {
+		logs.Errorf("update load balancer snapshot failed, id: %s, err: %v, rid: %v", id, err, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		logs.Errorf("update load balancer snapshot, but record not found, id: %s, rid: %v", id, kt.Rid)
+		return errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+	}
+
+	return nil
+}
+
+// List load balancer snapshot.
+func (dao SnapshotDao) List(kt *kit.Kit, opt *types.ListOption) (*typeslb.ListLoadBalancerSnapshotDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(
+		tablelb.LoadBalancerSnapshotColumns.ColumnTypes())), core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.LoadBalancerSnapshotTable, whereExpr)
+		count, err := dao.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Do().Count(kt.Ctx, sql,
+			whereValue)
+		if err != nil {
+			logs.ErrorJson("count load balancer snapshot failed, err: %v, filter: %s, rid: %s",
+				err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typeslb.ListLoadBalancerSnapshotDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tablelb.LoadBalancerSnapshotColumns.FieldsNamedExpr(opt.Fields),
+		table.LoadBalancerSnapshotTable, whereExpr, pageExpr)
+
+	details := make([]tablelb.LoadBalancerSnapshotTable, 0)
+	err = dao.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Do().Select(kt.Ctx, &details, sql,
+		whereValue)
+	if err != nil {
+		logs.ErrorJson("select load balancer snapshot failed, err: %v, filter: %s, rid: %s",
+			err, opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typeslb.ListLoadBalancerSnapshotDetails{Details: details}, nil
+}
+
+// DeleteWithTx load balancer snapshot.
+func (dao SnapshotDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.LoadBalancerSnapshotTable, whereExpr)
+	_, err = dao.Orm.ModifySQLOpts(orm.NewInjectTenantIDOpt(kt.TenantID)).Txn(tx).Delete(kt.Ctx, sql, whereValue)
+	if err != nil {
+		logs.ErrorJson("delete load balancer snapshot failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
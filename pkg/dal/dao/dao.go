@@ -41,8 +41,10 @@ import (
 	"hcm/pkg/dal/dao/cloud/cvm"
 	"hcm/pkg/dal/dao/cloud/disk"
 	diskcvmrel "hcm/pkg/dal/dao/cloud/disk-cvm-rel"
+	daodisksnapshot "hcm/pkg/dal/dao/cloud/disk-snapshot"
 	"hcm/pkg/dal/dao/cloud/eip"
 	eipcvmrel "hcm/pkg/dal/dao/cloud/eip-cvm-rel"
+	idleresource "hcm/pkg/dal/dao/cloud/idle-resource"
 	cimage "hcm/pkg/dal/dao/cloud/image"
 	loadbalancer "hcm/pkg/dal/dao/cloud/load-balancer"
 	networkinterface "hcm/pkg/dal/dao/cloud/network-interface"
@@ -54,6 +56,7 @@ import (
 	securitygroup "hcm/pkg/dal/dao/cloud/security-group"
 	sgcomrel "hcm/pkg/dal/dao/cloud/security-group-common-rel"
 	sgcvmrel "hcm/pkg/dal/dao/cloud/security-group-cvm-rel"
+	sgcompliance "hcm/pkg/dal/dao/cloud/sg-compliance"
 	daosubaccount "hcm/pkg/dal/dao/cloud/sub-account"
 	daosync "hcm/pkg/dal/dao/cloud/sync"
 	"hcm/pkg/dal/dao/cloud/zone"
@@ -83,6 +86,7 @@ type Set interface {
 	AwsSGRule() securitygroup.AwsSGRule
 	HuaWeiSGRule() securitygroup.HuaWeiSGRule
 	AzureSGRule() securitygroup.AzureSGRule
+	SGComplianceCheck() sgcompliance.Check
 	GcpFirewallRule() cloud.GcpFirewallRule
 	Cloud() cloud.Cloud
 	AccountBizRel() cloud.AccountBizRel
@@ -101,10 +105,17 @@ type Set interface {
 	Route() routetable.Route
 	Application() application.Application
 	ApprovalProcess() application.ApprovalProcess
+	ApprovalChain() application.ApprovalChain
+	ApprovalTicket() application.ApprovalTicket
+	AutoApprovalPolicy() application.AutoApprovalPolicy
 	NetworkInterface() networkinterface.NetworkInterface
 	RecycleRecord() recyclerecord.RecycleRecord
+	RecyclePolicy() recyclerecord.Policy
 	Eip() eip.Eip
 	Disk() disk.Disk
+	DiskSnapshot() daodisksnapshot.DiskSnapshot
+	DiskSnapshotPolicy() daodisksnapshot.Policy
+	IdleResource() idleresource.IdleResource
 	NiCvmRel() nicvmrel.NiCvmRel
 	Image() cimage.Image
 	DiskCvmRel() diskcvmrel.DiskCvmRel
@@ -121,8 +132,13 @@ type Set interface {
 	RootAccountBillConfig() bill.RootAccountBillConfig
 	AccountBillExchangeRate() bill.AccountBillExchangeRate
 	AccountBillSyncRecord() bill.AccountBillSyncRecord
+	AccountBillAllocationRule() bill.AccountBillAllocationRule
+	AccountBillAllocationResult() bill.AccountBillAllocationResult
 	AsyncFlow() daoasync.AsyncFlow
 	AsyncFlowTask() daoasync.AsyncFlowTask
+	AsyncFlowSchedule() daoasync.AsyncFlowSchedule
+	AsyncWorker() daoasync.AsyncWorker
+	AsyncAdvisoryLock(name string) daoasync.AdvisoryLock
 	UserCollection() daouser.Interface
 	CloudSelectionScheme() daoselection.SchemeInterface
 	CloudSelectionBizType() daoselection.BizTypeInterface
@@ -135,6 +151,8 @@ type Set interface {
 	LoadBalancerTargetGroup() loadbalancer.TargetGroupInterface
 	LoadBalancerTargetGroupListenerRuleRel() loadbalancer.TargetGroupListenerRuleRelInterface
 	LoadBalancerTCloudUrlRule() loadbalancer.LbTCloudUrlRuleInterface
+	LoadBalancerSnapshot() loadbalancer.SnapshotInterface
+	TargetHealthEvent() loadbalancer.TargetHealthEventInterface
 	ResourceFlowRel() resflow.ResourceFlowRelInterface
 	ResourceFlowLock() resflow.ResourceFlowLockInterface
 	SGCommonRel() sgcomrel.Interface
@@ -282,6 +300,32 @@ func (s *set) Disk() disk.Disk {
 	}
 }
 
+// DiskSnapshot return disk snapshot dao.
+func (s *set) DiskSnapshot() daodisksnapshot.DiskSnapshot {
+	return &daodisksnapshot.DiskSnapshotDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// DiskSnapshotPolicy return disk snapshot policy dao.
+func (s *set) DiskSnapshotPolicy() daodisksnapshot.Policy {
+	return &daodisksnapshot.PolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// IdleResource return idle resource dao.
+func (s *set) IdleResource() idleresource.IdleResource {
+	return &idleresource.IdleResourceDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Eip return Eip dao.
 func (s *set) Eip() eip.Eip {
 	return &eip.EipDao{
@@ -441,6 +485,14 @@ func (s *set) AzureSGRule() securitygroup.AzureSGRule {
 	}
 }
 
+// SGComplianceCheck return security group rule compliance check dao.
+func (s *set) SGComplianceCheck() sgcompliance.Check {
+	return &sgcompliance.CheckDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Cvm return cvm dao.
 func (s *set) Cvm() cvm.Interface {
 	return &cvm.Dao{
@@ -485,6 +537,7 @@ func (s *set) Application() application.Application {
 	return &application.ApplicationDao{
 		Orm:   s.orm,
 		IDGen: s.idGen,
+		Audit: s.audit,
 	}
 }
 
@@ -496,6 +549,30 @@ func (s *set) ApprovalProcess() application.ApprovalProcess {
 	}
 }
 
+// ApprovalChain return approval chain dao.
+func (s *set) ApprovalChain() application.ApprovalChain {
+	return &application.ApprovalChainDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// ApprovalTicket return approval ticket dao.
+func (s *set) ApprovalTicket() application.ApprovalTicket {
+	return &application.ApprovalTicketDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AutoApprovalPolicy return auto approval policy dao.
+func (s *set) AutoApprovalPolicy() application.AutoApprovalPolicy {
+	return &application.AutoApprovalPolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // NetworkInterface return network interface dao.
 func (s *set) NetworkInterface() networkinterface.NetworkInterface {
 	return &networkinterface.NetworkInterfaceDao{
@@ -510,6 +587,14 @@ func (s *set) RecycleRecord() recyclerecord.RecycleRecord {
 	return recyclerecord.NewRecycleRecordDao(s.orm, s.idGen, s.audit)
 }
 
+// RecyclePolicy return recycle policy dao.
+func (s *set) RecyclePolicy() recyclerecord.Policy {
+	return &recyclerecord.PolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Txn define dao set Txn.
 type Txn struct {
 	orm orm.Interface
@@ -625,6 +710,22 @@ func (s *set) AccountBillSyncRecord() bill.AccountBillSyncRecord {
 	}
 }
 
+// AccountBillAllocationRule return bill.AccountBillAllocationRule dao
+func (s *set) AccountBillAllocationRule() bill.AccountBillAllocationRule {
+	return &bill.AccountBillAllocationRuleDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillAllocationResult return bill.AccountBillAllocationResult dao
+func (s *set) AccountBillAllocationResult() bill.AccountBillAllocationResult {
+	return &bill.AccountBillAllocationResultDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // UserCollection returns user collection dao.
 func (s *set) UserCollection() daouser.Interface {
 	return &daouser.Dao{
@@ -649,6 +750,26 @@ func (s *set) AsyncFlowTask() daoasync.AsyncFlowTask {
 	}
 }
 
+// AsyncFlowSchedule return AsyncFlowSchedule dao.
+func (s *set) AsyncFlowSchedule() daoasync.AsyncFlowSchedule {
+	return &daoasync.AsyncFlowScheduleDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AsyncWorker return AsyncWorker dao.
+func (s *set) AsyncWorker() daoasync.AsyncWorker {
+	return &daoasync.AsyncWorkerDao{
+		Orm: s.orm,
+	}
+}
+
+// AsyncAdvisoryLock return mysql advisory lock with the given name.
+func (s *set) AsyncAdvisoryLock(name string) daoasync.AdvisoryLock {
+	return daoasync.NewAdvisoryLock(s.db, name)
+}
+
 // CloudSelectionScheme returns cloud selection scheme dao.
 func (s *set) CloudSelectionScheme() daoselection.SchemeInterface {
 	return &daoselection.SchemeDao{
@@ -745,6 +866,22 @@ func (s *set) LoadBalancerTCloudUrlRule() loadbalancer.LbTCloudUrlRuleInterface
 	}
 }
 
+// LoadBalancerSnapshot return load balancer snapshot dao.
+func (s *set) LoadBalancerSnapshot() loadbalancer.SnapshotInterface {
+	return &loadbalancer.SnapshotDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// TargetHealthEvent return target health event dao.
+func (s *set) TargetHealthEvent() loadbalancer.TargetHealthEventInterface {
+	return &loadbalancer.TargetHealthEventDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // ResourceFlowRel return resource flow rel dao.
 func (s *set) ResourceFlowRel() resflow.ResourceFlowRelInterface {
 	return &resflow.ResourceFlowRelDao{
@@ -28,3 +28,21 @@ type ListApprovalProcessDetails struct {
 	Count   uint64                              `json:"count,omitempty"`
 	Details []*application.ApprovalProcessTable `json:"details,omitempty"`
 }
+
+// ListApprovalChainDetails list approval chain details.
+type ListApprovalChainDetails struct {
+	Count   uint64                           `json:"count,omitempty"`
+	Details []application.ApprovalChainTable `json:"details,omitempty"`
+}
+
+// ListApprovalTicketDetails list approval ticket details.
+type ListApprovalTicketDetails struct {
+	Count   uint64                            `json:"count,omitempty"`
+	Details []application.ApprovalTicketTable `json:"details,omitempty"`
+}
+
+// ListAutoApprovalPolicyDetails list auto approval policy details.
+type ListAutoApprovalPolicyDetails struct {
+	Count   uint64                                `json:"count,omitempty"`
+	Details []application.AutoApprovalPolicyTable `json:"details,omitempty"`
+}
@@ -0,0 +1,47 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package typesasync
+
+import (
+	"hcm/pkg/criteria/validator"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// ListAsyncFlowSchedules list async flow schedules.
+type ListAsyncFlowSchedules struct {
+	Count   uint64                              `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowScheduleTable `json:"details,omitempty"`
+}
+
+// UpdateScheduleFireInfo define update schedule fire time info.
+type UpdateScheduleFireInfo struct {
+	ID string `json:"id" validate:"required"`
+	// Source 当前的下次触发时间，CAS条件
+	Source string `json:"source"`
+	// Target 新的下次触发时间
+	Target       string  `json:"target"`
+	LastFireTime *string `json:"last_fire_time" validate:"omitempty"`
+	LastFlowID   *string `json:"last_flow_id" validate:"omitempty"`
+}
+
+// Validate UpdateScheduleFireInfo.
+func (info *UpdateScheduleFireInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
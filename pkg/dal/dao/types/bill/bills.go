@@ -126,3 +126,15 @@ type SumCostDetail struct {
 	Cost     decimal.Decimal     `json:"cost"`
 	Currency enumor.CurrencyCode `json:"currency"`
 }
+
+// ListAccountBillAllocationRuleDetails list account bill allocation rule details
+type ListAccountBillAllocationRuleDetails struct {
+	Count   uint64                                `json:"count,omitempty"`
+	Details []tablebill.AccountBillAllocationRule `json:"details,omitempty"`
+}
+
+// ListAccountBillAllocationResultDetails list account bill allocation result details
+type ListAccountBillAllocationResultDetails struct {
+	Count   uint64                                  `json:"count,omitempty"`
+	Details []tablebill.AccountBillAllocationResult `json:"details,omitempty"`
+}
@@ -30,6 +30,12 @@ type RecycleRecordListResult struct {
 	Details []rr.RecycleRecordTable `json:"details"`
 }
 
+// RecyclePolicyListResult list recycle policy result.
+type RecyclePolicyListResult struct {
+	Count   uint64                  `json:"count"`
+	Details []rr.RecyclePolicyTable `json:"details"`
+}
+
 // RecycleResourceInfo define recycle resource info.
 type RecycleResourceInfo struct {
 	Vendor    enumor.Vendor `db:"vendor" json:"vendor"`
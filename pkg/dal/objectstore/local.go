@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// LocalDisk local filesystem object store, object path is relative to root dir.
+type LocalDisk struct {
+	rootDir   string
+	urlPrefix string
+	signKey   []byte
+	// now is used to get current time, can be replaced in test.
+	now func() time.Time
+}
+
+// NewLocalDisk create local filesystem object store
+func NewLocalDisk(config cc.ObjectStoreLocal) (*LocalDisk, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	rootDir, err := filepath.Abs(config.RootDir)
+	if err != nil {
+		return nil, fmt.Errorf("get abs path of root dir %s failed, err %s", config.RootDir, err.Error())
+	}
+	if err = os.MkdirAll(rootDir, 0750); err != nil {
+		return nil, fmt.Errorf("create root dir %s failed, err %s", rootDir, err.Error())
+	}
+
+	return &LocalDisk{
+		rootDir:   rootDir,
+		urlPrefix: strings.TrimRight(config.URLPrefix, "/"),
+		signKey:   []byte(config.SignKey),
+		now:       time.Now,
+	}, nil
+}
+
+// Upload put object to path
+func (l *LocalDisk) Upload(kt *kit.Kit, uploadPath string, r io.Reader) error {
+	fullPath, err := l.fullPath(uploadPath)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
+		return fmt.Errorf("create dir for path %s failed, err %s", uploadPath, err.Error())
+	}
+
+	// 先写入临时文件再重命名，避免读到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), "."+filepath.Base(fullPath)+".*")
+	if err != nil {
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	if err = os.Rename(tmp.Name(), fullPath); err != nil {
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	return nil
+}
+
+// Download get object from path
+func (l *LocalDisk) Download(kt *kit.Kit, downloadPath string, w io.Writer) error {
+	fullPath, err := l.fullPath(downloadPath)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return fmt.Errorf("get from path %s failed, err %s", downloadPath, err.Error())
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		return fmt.Errorf("failed writing response, err %s", err.Error())
+	}
+	return nil
+}
+
+// ListItems list items under path, only files directly under the folder are returned.
+func (l *LocalDisk) ListItems(kt *kit.Kit, folderPath string) ([]string, error) {
+	fullPath, err := l.fullPath(folderPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list item for path %s failed, err %s", folderPath, err.Error())
+	}
+
+	var retList []string
+	for _, entry := range entries {
+		// 忽略目录以及上传过程中的临时文件
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		retList = append(retList, filepath.Join(filepath.Clean(folderPath), entry.Name()))
+	}
+	return retList, nil
+}
+
+// Delete delete object by path
+func (l *LocalDisk) Delete(kt *kit.Kit, path string) error {
+	fullPath, err := l.fullPath(path)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// GetPreSignedURL 获取预签名URL，URL由 urlPrefix、操作类型、对象路径、过期时间和签名组成，不返回临时密钥
+func (l *LocalDisk) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.Duration, path string) (
+	*TemporalCredential, string, error) {
+
+	switch action {
+	case DownloadOperateAction, UploadOperateAction:
+	default:
+		return nil, "", errors.New("invalid action for get presigned url: " + string(action))
+	}
+	if len(l.urlPrefix) == 0 {
+		return nil, "", errors.New("url prefix of local object store is not configured")
+	}
+	if _, err := l.fullPath(path); err != nil {
+		return nil, "", err
+	}
+
+	expires := strconv.FormatInt(l.now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("path", path)
+	query.Set("expires", expires)
+	query.Set("sign", l.sign(action, path, expires))
+
+	return nil, fmt.Sprintf("%s/%s?%s", l.urlPrefix, action, query.Encode()), nil
+}
+
+// ServeHTTP serve the pre-signed url generated by GetPreSignedURL, the last element of url path is the action.
+func (l *LocalDisk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	action := OperateAction(filepath.Base(r.URL.Path))
+	query := r.URL.Query()
+	path := query.Get("path")
+	if err := l.verify(action, path, query.Get("expires"), query.Get("sign")); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	kt := kit.New()
+	kt.Ctx = r.Context()
+	switch {
+	case action == DownloadOperateAction && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filepath.Base(path)))
+		if err := l.Download(kt, path, w); err != nil {
+			logs.Errorf("serve local object download failed, err: %v, path: %s, rid: %s", err, path, kt.Rid)
+			http.Error(w, "download failed", http.StatusNotFound)
+		}
+	case action == UploadOperateAction && r.Method == http.MethodPut:
+		if err := l.Upload(kt, path, r.Body); err != nil {
+			logs.Errorf("serve local object upload failed, err: %v, path: %s, rid: %s", err, path, kt.Rid)
+			http.Error(w, "upload failed", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (l *LocalDisk) verify(action OperateAction, path, expires, sign string) error {
+	if len(path) == 0 || len(expires) == 0 || len(sign) == 0 {
+		return errors.New("path, expires and sign are required")
+	}
+	expireAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires %s", expires)
+	}
+	if l.now().Unix() > expireAt {
+		return errors.New("url is expired")
+	}
+	if !hmac.Equal([]byte(sign), []byte(l.sign(action, path, expires))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func (l *LocalDisk) sign(action OperateAction, path, expires string) string {
+	mac := hmac.New(sha256.New, l.signKey)
+	mac.Write([]byte(string(action) + "\n" + path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fullPath convert object path to file path under root dir, path escaping root dir is rejected.
+func (l *LocalDisk) fullPath(path string) (string, error) {
+	fullPath := filepath.Join(l.rootDir, filepath.Clean("/"+path))
+	if fullPath != l.rootDir && !strings.HasPrefix(fullPath, l.rootDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object path %s", path)
+	}
+	return fullPath, nil
+}
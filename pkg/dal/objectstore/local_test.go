@@ -0,0 +1,117 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalDisk(t *testing.T) *LocalDisk {
+	l, err := NewLocalDisk(cc.ObjectStoreLocal{
+		RootDir:   t.TempDir(),
+		URLPrefix: "http://127.0.0.1/local_objects/",
+		SignKey:   "test-sign-key",
+	})
+	assert.NoError(t, err)
+	return l
+}
+
+func TestLocalDiskObjectOperation(t *testing.T) {
+	l := newTestLocalDisk(t)
+	kt := kit.New()
+
+	assert.NoError(t, l.Upload(kt, "rawbills/a/1.csv", strings.NewReader("1")))
+	assert.NoError(t, l.Upload(kt, "rawbills/a/2.csv", strings.NewReader("2")))
+	assert.NoError(t, l.Upload(kt, "rawbills/a/sub/3.csv", strings.NewReader("3")))
+
+	items, err := l.ListItems(kt, "rawbills/a/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rawbills/a/1.csv", "rawbills/a/2.csv"}, items)
+
+	var buf bytes.Buffer
+	assert.NoError(t, l.Download(kt, "rawbills/a/2.csv", &buf))
+	assert.Equal(t, "2", buf.String())
+
+	assert.NoError(t, l.Delete(kt, "rawbills/a/1.csv"))
+	items, err = l.ListItems(kt, "rawbills/a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rawbills/a/2.csv"}, items)
+
+	items, err = l.ListItems(kt, "not/exists")
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	// 路径不能逃逸出根目录
+	assert.NoError(t, l.Upload(kt, "../../escape.csv", strings.NewReader("x")))
+	items, err = l.ListItems(kt, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"escape.csv"}, items)
+}
+
+func TestLocalDiskPreSignedURL(t *testing.T) {
+	l := newTestLocalDisk(t)
+	kt := kit.New()
+
+	cred, url, err := l.GetPreSignedURL(kt, UploadOperateAction, time.Minute, "export/bill.xlsx")
+	assert.NoError(t, err)
+	assert.Nil(t, cred)
+	assert.True(t, strings.HasPrefix(url, "http://127.0.0.1/local_objects/upload?"))
+
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, url, strings.NewReader("excel")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	_, url, err = l.GetPreSignedURL(kt, DownloadOperateAction, time.Minute, "export/bill.xlsx")
+	assert.NoError(t, err)
+	rec = httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "excel", rec.Body.String())
+
+	// 篡改路径后签名校验失败
+	rec = httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strings.Replace(url, "bill.xlsx", "other.xlsx", 1), nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 下载签名不能用于上传
+	rec = httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, strings.Replace(url, "/download?", "/upload?", 1),
+		strings.NewReader("x")))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// 过期后签名校验失败
+	l.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	rec = httptest.NewRecorder()
+	l.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	_, _, err = l.GetPreSignedURL(kt, "invalid", time.Minute, "export/bill.xlsx")
+	assert.Error(t, err)
+}
@@ -26,19 +26,32 @@ import (
 	"time"
 
 	"hcm/pkg/cc"
-	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
+)
+
+// Type object store type
+type Type string
 
-	sts "github.com/tencentyun/qcloud-cos-sts-sdk/go"
+const (
+	// TCloudType 腾讯云 cos
+	TCloudType Type = "tcloud"
+	// S3Type s3 兼容存储，如 aws s3、minio、ceph
+	S3Type Type = "s3"
+	// LocalType 本地文件系统
+	LocalType Type = "local"
 )
 
 // GetObjectStore get object store from env
 func GetObjectStore(config cc.ObjectStore) (Storage, error) {
-	switch config.Type {
+	switch Type(config.Type) {
 	case "":
 		return nil, nil
-	case string(enumor.TCloud):
+	case TCloudType:
 		return NewTCloudCOS(config.ObjectStoreTCloud)
+	case S3Type:
+		return NewS3(config.S3)
+	case LocalType:
+		return NewLocalDisk(config.Local)
 	default:
 		return nil, fmt.Errorf("invalid object store type %s", config.Type)
 	}
@@ -50,6 +63,13 @@ type Storage interface {
 	Download(kt *kit.Kit, downloadPath string, w io.Writer) error
 	ListItems(kt *kit.Kit, folderPath string) ([]string, error)
 	Delete(kt *kit.Kit, path string) error
+	// GetPreSignedURL 获取预签名URL，对于需要临时密钥才能访问的存储会同时返回临时密钥，否则临时密钥为nil
 	GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.Duration, path string) (
-		tempCred *sts.Credentials, url string, err error)
+		tempCred *TemporalCredential, url string, err error)
+}
+
+// TemporalCredential vendor-neutral temporal credential for accessing pre-signed url.
+type TemporalCredential struct {
+	AK    string
+	Token string
 }
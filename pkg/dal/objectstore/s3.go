@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package objectstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3 s3 compatible object store client, such as aws s3, minio, ceph.
+type S3 struct {
+	prefix   string
+	config   cc.ObjectStoreS3
+	cli      *s3.S3
+	uploader *s3manager.Uploader
+}
+
+// NewS3 create s3 compatible object store client
+func NewS3(config cc.ObjectStoreS3) (*S3, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := &aws.Config{
+		Credentials:      credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
+		Region:           aws.String(config.Region),
+		S3ForcePathStyle: aws.Bool(config.UsePathStyle),
+		DisableSSL:       aws.Bool(config.DisableSSL),
+	}
+	if len(config.Endpoint) != 0 {
+		cfg.Endpoint = aws.String(config.Endpoint)
+	}
+	if config.IsDebug {
+		cfg.LogLevel = aws.LogLevel(aws.LogDebugWithHTTPBody)
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create s3 session failed, err %s", err.Error())
+	}
+	client := s3.New(sess)
+	if _, err = client.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(config.Bucket)}); err != nil {
+		return nil, fmt.Errorf("check bucket failed, err %s", err.Error())
+	}
+
+	return &S3{
+		prefix:   config.Prefix,
+		config:   config,
+		cli:      client,
+		uploader: s3manager.NewUploaderWithClient(client),
+	}, nil
+}
+
+// Upload put object to path
+func (s *S3) Upload(kt *kit.Kit, uploadPath string, r io.Reader) error {
+	uploadPath = s.prependPrefix(uploadPath)
+	// 使用 uploader 以支持非 io.ReadSeeker 的输入
+	_, err := s.uploader.UploadWithContext(kt.Ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(uploadPath),
+		Body:   r,
+	})
+	if err != nil {
+		return fmt.Errorf("put to path %s failed, err %s", uploadPath, err.Error())
+	}
+	return nil
+}
+
+// Download get object from path
+func (s *S3) Download(kt *kit.Kit, downloadPath string, w io.Writer) error {
+	downloadPath = s.prependPrefix(downloadPath)
+	resp, err := s.cli.GetObjectWithContext(kt.Ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(downloadPath),
+	})
+	if err != nil {
+		return fmt.Errorf("get from path %s failed, err %s", downloadPath, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if _, err = io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed writing response, err %s", err.Error())
+	}
+	return nil
+}
+
+// ListItems list items under path
+func (s *S3) ListItems(kt *kit.Kit, folderPath string) ([]string, error) {
+	folderPath = s.prependPrefix(folderPath)
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.Bucket),
+		// filepath join之后，最后的斜杠会被去掉，这里需要加上，不然查不出来
+		Prefix:    aws.String(folderPath + "/"),
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int64(1000),
+	}
+	var retList []string
+	err := s.cli.ListObjectsV2PagesWithContext(kt.Ctx, input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, content := range page.Contents {
+			retList = append(retList, aws.StringValue(content.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list item for path %s failed, err %s", folderPath, err.Error())
+	}
+	return retList, nil
+}
+
+// Delete delete object by path
+func (s *S3) Delete(kt *kit.Kit, path string) error {
+	deletePath := s.prependPrefix(path)
+	_, err := s.cli.DeleteObjectWithContext(kt.Ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(deletePath),
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetPreSignedURL 获取预签名URL，签名信息已包含在URL中，不返回临时密钥
+func (s *S3) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.Duration, path string) (
+	*TemporalCredential, string, error) {
+
+	path = s.prependPrefix(path)
+	var req *request.Request
+	switch action {
+	case DownloadOperateAction:
+		req, _ = s.cli.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(s.config.Bucket),
+			Key:    aws.String(path),
+		})
+	case UploadOperateAction:
+		req, _ = s.cli.PutObjectRequest(&s3.PutObjectInput{
+			Bucket: aws.String(s.config.Bucket),
+			Key:    aws.String(path),
+		})
+	default:
+		return nil, "", errors.New("invalid action for get presigned url: " + string(action))
+	}
+	req.SetContext(kt.Ctx)
+
+	presigned, err := req.Presign(ttl)
+	if err != nil {
+		logs.Errorf("fail to get presigned url for action: %s, err: %v, ttl: %f, path: %s, rid: %s",
+			action, err, ttl.Seconds(), path, kt.Rid)
+		return nil, "", err
+	}
+	return nil, presigned, nil
+}
+
+func (s *S3) prependPrefix(path string) string {
+	return filepath.Join(s.prefix, path)
+}
@@ -169,7 +169,7 @@ func (t *TCloudCOS) Delete(kt *kit.Kit, path string) error {
 
 // GetPreSignedURL 获取预签名URL
 func (t *TCloudCOS) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.Duration,
-	path string) (tempCred *sts.Credentials, url string, err error) {
+	path string) (*TemporalCredential, string, error) {
 	var cosActions []CosAction
 	var httpMethod string
 	switch action {
@@ -183,7 +183,7 @@ func (t *TCloudCOS) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.
 		return nil, "", errors.New("invalid action for get presigned url: " + string(action))
 	}
 	path = t.prependPrefix(path)
-	tempCred, err = t.GetTemporalSecret(kt, t.config.CosBucketRegion, ttl, path, cosActions, nil)
+	tempCred, err := t.GetTemporalSecret(kt, t.config.CosBucketRegion, ttl, path, cosActions, nil)
 	if err != nil {
 		logs.Errorf("fail to get temporal secret for action: %s url, err: %s, ttl: %f, path: %s, rid: %s",
 			action, err.Error(), ttl.Seconds(), path, kt.Rid)
@@ -201,7 +201,11 @@ func (t *TCloudCOS) GetPreSignedURL(kt *kit.Kit, action OperateAction, ttl time.
 			action, err.Error(), httpMethod, ttl.Seconds(), path, kt.Rid)
 		return nil, "", err
 	}
-	return tempCred, presigned.String(), nil
+	cred := &TemporalCredential{
+		AK:    tempCred.TmpSecretID,
+		Token: tempCred.SessionToken,
+	}
+	return cred, presigned.String(), nil
 }
 
 // GetTemporalSecret 获取临时密钥
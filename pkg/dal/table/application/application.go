@@ -46,6 +46,7 @@ var ApplicationColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "content", NamedC: "content", Type: enumor.Json},
 	{Column: "delivery_detail", NamedC: "delivery_detail", Type: enumor.Json},
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "auto_approval_policy_id", NamedC: "auto_approval_policy_id", Type: enumor.String},
 
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
@@ -76,6 +77,8 @@ type ApplicationTable struct {
 	DeliveryDetail types.JsonField `db:"delivery_detail" json:"delivery_detail"`
 	// Memo 备注或申请理由
 	Memo *string `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// AutoApprovalPolicyID 匹配的自动审批策略ID，为空表示未自动审批
+	AutoApprovalPolicyID string `db:"auto_approval_policy_id" json:"auto_approval_policy_id" validate:"max=64"`
 
 	// TenantID 租户ID
 	TenantID string `db:"tenant_id" json:"tenant_id"`
@@ -149,5 +152,9 @@ func (a ApplicationTable) UpdateValidate() error {
 		return errors.New("creator can not update")
 	}
 
+	if len(a.AutoApprovalPolicyID) != 0 {
+		return errors.New("auto approval policy id can not update")
+	}
+
 	return nil
 }
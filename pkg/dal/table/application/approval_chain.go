@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ApprovalChainColumns defines all the approval chain table's columns.
+var ApprovalChainColumns = utils.MergeColumns(nil, ApprovalChainColumnDescriptor)
+
+// ApprovalChainColumnDescriptor is approval chain's column descriptors.
+var ApprovalChainColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "nodes", NamedC: "nodes", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ApprovalNodes 审批节点列表
+type ApprovalNodes []coreapproval.Node
+
+// Scan is used to decode raw message which is read from db into ApprovalNodes.
+func (n *ApprovalNodes) Scan(raw interface{}) error {
+	return types.Scan(raw, n)
+}
+
+// Value encode the ApprovalNodes to a json raw, so that it can be stored to db with json raw.
+func (n ApprovalNodes) Value() (driver.Value, error) {
+	return types.Value(n)
+}
+
+// ApprovalChainTable 内置审批引擎的审批链表
+type ApprovalChainTable struct {
+	ID string `db:"id" json:"id" validate:"max=64"`
+	// ApplicationType 申请单类型
+	ApplicationType enumor.ApplicationType `db:"application_type" json:"application_type" validate:"max=64"`
+	// BkBizID 业务ID，-1表示该申请单类型的默认审批链
+	BkBizID int64         `db:"bk_biz_id" json:"bk_biz_id"`
+	Nodes   ApprovalNodes `db:"nodes" json:"nodes"`
+	Memo    *string       `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// TenantID 租户ID
+	TenantID  string     `db:"tenant_id" json:"tenant_id"`
+	Creator   string     `db:"creator" json:"creator" validate:"max=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"max=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return approval chain table name.
+func (a ApprovalChainTable) TableName() table.Name {
+	return table.ApprovalChainTable
+}
+
+// InsertValidate approval chain table when insert.
+func (a ApprovalChainTable) InsertValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if err := a.ApplicationType.Validate(); err != nil {
+		return err
+	}
+
+	if err := coreapproval.ValidateNodes(a.Nodes); err != nil {
+		return err
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate approval chain table when update.
+func (a ApprovalChainTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ApplicationType) != 0 {
+		return errors.New("application type can not update")
+	}
+
+	if a.Nodes != nil {
+		if err := coreapproval.ValidateNodes(a.Nodes); err != nil {
+			return err
+		}
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	coreapproval "hcm/pkg/api/core/approval"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ApprovalTicketColumns defines all the approval ticket table's columns.
+var ApprovalTicketColumns = utils.MergeColumns(nil, ApprovalTicketColumnDescriptor)
+
+// ApprovalTicketColumnDescriptor is approval ticket's column descriptors.
+var ApprovalTicketColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "application_id", NamedC: "application_id", Type: enumor.String},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "title", NamedC: "title", Type: enumor.String},
+	{Column: "applicant", NamedC: "applicant", Type: enumor.String},
+	{Column: "chain_id", NamedC: "chain_id", Type: enumor.String},
+	{Column: "nodes", NamedC: "nodes", Type: enumor.Json},
+	{Column: "current_node", NamedC: "current_node", Type: enumor.Numeric},
+	{Column: "approvers", NamedC: "approvers", Type: enumor.Json},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "records", NamedC: "records", Type: enumor.Json},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "reminded_at", NamedC: "reminded_at", Type: enumor.Time},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ApprovalRecords 审批操作记录列表
+type ApprovalRecords []coreapproval.Record
+
+// Scan is used to decode raw message which is read from db into ApprovalRecords.
+func (r *ApprovalRecords) Scan(raw interface{}) error {
+	return types.Scan(raw, r)
+}
+
+// Value encode the ApprovalRecords to a json raw, so that it can be stored to db with json raw.
+func (r ApprovalRecords) Value() (driver.Value, error) {
+	return types.Value(r)
+}
+
+// ApprovalTicketTable 内置审批引擎的审批单表
+type ApprovalTicketTable struct {
+	ID              string                 `db:"id" json:"id" validate:"max=64"`
+	ApplicationID   string                 `db:"application_id" json:"application_id" validate:"max=64"`
+	ApplicationType enumor.ApplicationType `db:"application_type" json:"application_type" validate:"max=64"`
+	Title           string                 `db:"title" json:"title" validate:"max=255"`
+	Applicant       string                 `db:"applicant" json:"applicant" validate:"max=64"`
+	ChainID         string                 `db:"chain_id" json:"chain_id" validate:"max=64"`
+	// Nodes 创建审批单时审批链的快照
+	Nodes ApprovalNodes `db:"nodes" json:"nodes"`
+	// CurrentNode 当前审批节点下标
+	CurrentNode uint32 `db:"current_node" json:"current_node"`
+	// Approvers 当前节点的审批人
+	Approvers types.StringArray           `db:"approvers" json:"approvers"`
+	Status    enumor.ApprovalTicketStatus `db:"status" json:"status" validate:"max=32"`
+	Records   ApprovalRecords             `db:"records" json:"records"`
+	// Version 乐观锁版本号，每次审批操作后加一
+	Version uint32 `db:"version" json:"version"`
+	// RemindedAt 最近一次通知审批人的时间
+	RemindedAt string `db:"reminded_at" json:"reminded_at" validate:"max=64"`
+	// TenantID 租户ID
+	TenantID  string     `db:"tenant_id" json:"tenant_id"`
+	Creator   string     `db:"creator" json:"creator" validate:"max=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"max=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return approval ticket table name.
+func (a ApprovalTicketTable) TableName() table.Name {
+	return table.ApprovalTicketTable
+}
+
+// InsertValidate approval ticket table when insert.
+func (a ApprovalTicketTable) InsertValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ApplicationID) == 0 {
+		return errors.New("application id is required")
+	}
+
+	if len(a.Nodes) == 0 {
+		return errors.New("nodes is required")
+	}
+
+	if len(a.Approvers) == 0 {
+		return errors.New("approvers is required")
+	}
+
+	if err := a.Status.Validate(); err != nil {
+		return err
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate approval ticket table when update.
+func (a ApprovalTicketTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ApplicationID) != 0 || len(a.ApplicationType) != 0 || len(a.ChainID) != 0 || a.Nodes != nil {
+		return errors.New("application and chain info can not update")
+	}
+
+	if len(a.Status) != 0 {
+		if err := a.Status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
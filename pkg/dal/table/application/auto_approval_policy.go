@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package application
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AutoApprovalPolicyColumns defines all the auto approval policy table's columns.
+var AutoApprovalPolicyColumns = utils.MergeColumns(nil, AutoApprovalPolicyColumnDescriptor)
+
+// AutoApprovalPolicyColumnDescriptor is auto approval policy's column descriptors.
+var AutoApprovalPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "application_type", NamedC: "application_type", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "conditions", NamedC: "conditions", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AutoApprovalPolicyTable 申请单自动审批策略表
+type AutoApprovalPolicyTable struct {
+	ID   string `db:"id" json:"id" validate:"max=64"`
+	Name string `db:"name" json:"name" validate:"max=64"`
+	// ApplicationType 申请单类型
+	ApplicationType enumor.ApplicationType `db:"application_type" json:"application_type" validate:"max=64"`
+	// Priority 优先级，数值越小越优先匹配
+	Priority *uint32 `db:"priority" json:"priority"`
+	Enabled  *bool   `db:"enabled" json:"enabled"`
+	// Conditions 自动审批条件，对应 coreapproval.AutoApprovalCondition
+	Conditions types.JsonField `db:"conditions" json:"conditions"`
+	Memo       *string         `db:"memo" json:"memo" validate:"omitempty,max=255"`
+	// TenantID 租户ID
+	TenantID  string     `db:"tenant_id" json:"tenant_id"`
+	Creator   string     `db:"creator" json:"creator" validate:"max=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"max=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return auto approval policy table name.
+func (a AutoApprovalPolicyTable) TableName() table.Name {
+	return table.AutoApprovalPolicyTable
+}
+
+// InsertValidate auto approval policy table when insert.
+func (a AutoApprovalPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if err := a.ApplicationType.Validate(); err != nil {
+		return err
+	}
+
+	if a.Priority == nil {
+		return errors.New("priority is required")
+	}
+
+	if a.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if a.Conditions.IsEmpty() {
+		return errors.New("conditions is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate auto approval policy table when update.
+func (a AutoApprovalPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ApplicationType) != 0 {
+		return errors.New("application type can not update")
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
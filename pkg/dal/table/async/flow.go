@@ -41,6 +41,7 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
 	{Column: "share_data", NamedC: "share_data", Type: enumor.Json},
 	{Column: "worker", NamedC: "worker", Type: enumor.String},
+	{Column: "deadline", NamedC: "deadline", Type: enumor.Time},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -56,10 +57,12 @@ type AsyncFlowTable struct {
 	ShareData *ShareData       `db:"share_data" json:"share_data"`
 	Memo      string           `db:"memo" json:"memo"`
 	Worker    *string          `db:"worker" json:"worker"`
-	Creator   string           `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser   string           `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt types.Time       `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt types.Time       `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// Deadline 任务流截止时间，UTC时间的 constant.TimeStdFormat 格式，为空表示不限制
+	Deadline  *string    `db:"deadline" json:"deadline" validate:"omitempty,lte=64"`
+	Creator   string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 
 	// TenantID 租户ID
 	TenantID string `db:"tenant_id" json:"tenant_id"`
@@ -0,0 +1,142 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowScheduleColumns defines all the async_flow_schedule table's columns.
+var AsyncFlowScheduleColumns = utils.MergeColumns(nil, AsyncFlowScheduleTableColumnDescriptor)
+
+// AsyncFlowScheduleTableColumnDescriptor is async_flow_schedule's column descriptors.
+var AsyncFlowScheduleTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "flow_name", NamedC: "flow_name", Type: enumor.String},
+	{Column: "cron_expr", NamedC: "cron_expr", Type: enumor.String},
+	{Column: "time_zone", NamedC: "time_zone", Type: enumor.String},
+	{Column: "tasks", NamedC: "tasks", Type: enumor.Json},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "misfire_policy", NamedC: "misfire_policy", Type: enumor.String},
+	{Column: "next_fire_time", NamedC: "next_fire_time", Type: enumor.Time},
+	{Column: "last_fire_time", NamedC: "last_fire_time", Type: enumor.Time},
+	{Column: "last_flow_id", NamedC: "last_flow_id", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AsyncFlowScheduleTable define async_flow_schedule table, 按cron表达式周期性创建模版任务流。
+// next_fire_time、last_fire_time 统一使用UTC时间的 constant.TimeStdFormat 格式存储，以便按字符串比较。
+type AsyncFlowScheduleTable struct {
+	ID            string               `db:"id" json:"id" validate:"lte=64"`
+	Name          string               `db:"name" json:"name" validate:"lte=64"`
+	FlowName      enumor.FlowName      `db:"flow_name" json:"flow_name" validate:"lte=64"`
+	CronExpr      string               `db:"cron_expr" json:"cron_expr" validate:"lte=64"`
+	TimeZone      string               `db:"time_zone" json:"time_zone" validate:"lte=64"`
+	Tasks         types.JsonField      `db:"tasks" json:"tasks"`
+	Enabled       *bool                `db:"enabled" json:"enabled"`
+	MisfirePolicy enumor.MisfirePolicy `db:"misfire_policy" json:"misfire_policy" validate:"lte=16"`
+	NextFireTime  string               `db:"next_fire_time" json:"next_fire_time" validate:"lte=64"`
+	LastFireTime  string               `db:"last_fire_time" json:"last_fire_time" validate:"lte=64"`
+	LastFlowID    string               `db:"last_flow_id" json:"last_flow_id" validate:"lte=64"`
+	Memo          *string              `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator       string               `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser       string               `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt     types.Time           `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt     types.Time           `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+}
+
+// TableName return async_flow_schedule table name.
+func (a AsyncFlowScheduleTable) TableName() table.Name {
+	return table.AsyncFlowScheduleTable
+}
+
+// InsertValidate async_flow_schedule table when insert.
+func (a AsyncFlowScheduleTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(a.FlowName) == 0 {
+		return errors.New("flow_name is required")
+	}
+
+	if len(a.CronExpr) == 0 {
+		return errors.New("cron_expr is required")
+	}
+
+	if a.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(a.MisfirePolicy) == 0 {
+		return errors.New("misfire_policy is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate async_flow_schedule table when update.
+func (a AsyncFlowScheduleTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.FlowName) != 0 {
+		return errors.New("flow_name can not update")
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
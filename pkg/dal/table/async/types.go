@@ -31,6 +31,8 @@ type Reason struct {
 	PreState string `json:"pre_state,omitempty"`
 	// 改为rollback的次数
 	RollbackCount uint `json:"rollback_count,omitempty"`
+	// RollbackOnCancel 取消任务流时是否回滚执行中的任务，如任务流超过截止时间被取消
+	RollbackOnCancel bool `json:"rollback_on_cancel,omitempty"`
 }
 
 // Scan is used to decode raw message which is read from db into Reason.
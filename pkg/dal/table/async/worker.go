@@ -0,0 +1,48 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncWorkerColumns defines all the async_worker table's columns.
+var AsyncWorkerColumns = utils.MergeColumns(nil, AsyncWorkerTableColumnDescriptor)
+
+// AsyncWorkerTableColumnDescriptor is async_worker's column descriptors.
+var AsyncWorkerTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "worker", NamedC: "worker", Type: enumor.String},
+	{Column: "heartbeat_at", NamedC: "heartbeat_at", Type: enumor.Time},
+}
+
+// AsyncWorkerTable define async_worker table, 记录task-server节点心跳，
+// 使用MySQL选主时通过心跳时间判断节点是否存活。
+type AsyncWorkerTable struct {
+	Worker      string     `db:"worker" json:"worker"`
+	HeartbeatAt types.Time `db:"heartbeat_at" json:"heartbeat_at"`
+}
+
+// TableName return async_worker table name.
+func (a AsyncWorkerTable) TableName() table.Name {
+	return table.AsyncWorkerTable
+}
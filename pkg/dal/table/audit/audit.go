@@ -52,6 +52,9 @@ var AuditColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "rid", NamedC: "rid", Type: enumor.String},
 	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
 	{Column: "detail", NamedC: "detail", Type: enumor.Json},
+	{Column: "chain_seq", NamedC: "chain_seq", Type: enumor.Numeric},
+	{Column: "prev_hash", NamedC: "prev_hash", Type: enumor.String},
+	{Column: "hash", NamedC: "hash", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 }
 
@@ -71,6 +74,9 @@ type AuditTable struct {
 	Rid        string                   `db:"rid" json:"rid" validate:"lte=64"`
 	AppCode    string                   `db:"app_code" json:"app_code" validate:"lte=64"`
 	Detail     *BasicDetail             `db:"detail" json:"detail" validate:"-"`
+	ChainSeq   uint64                   `db:"chain_seq" json:"chain_seq"` // 租户内哈希链序号，由dao写入时生成
+	PrevHash   string                   `db:"prev_hash" json:"prev_hash"` // 哈希链上前一条审计记录的哈希
+	Hash       string                   `db:"hash" json:"hash"`           // 当前审计记录的哈希
 	CreatedAt  types.Time               `db:"created_at" json:"created_at"`
 	// TenantID 租户ID
 	TenantID string `db:"tenant_id" json:"tenant_id"`
@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AuditChainColumns defines all the audit chain table's columns.
+var AuditChainColumns = utils.MergeColumns(nil, AuditChainColumnDescriptor)
+
+// AuditChainColumnDescriptor is AuditChainTable's column descriptors.
+var AuditChainColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "tenant_id", NamedC: "tenant_id", Type: enumor.String},
+	{Column: "last_seq", NamedC: "last_seq", Type: enumor.Numeric},
+	{Column: "last_hash", NamedC: "last_hash", Type: enumor.String},
+	{Column: "export_seq", NamedC: "export_seq", Type: enumor.Numeric},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AuditChainTable is the head of each tenant's audit hash chain.
+type AuditChainTable struct {
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+	// LastSeq 哈希链最新序号
+	LastSeq uint64 `db:"last_seq" json:"last_seq"`
+	// LastHash 哈希链最新记录的哈希
+	LastHash string `db:"last_hash" json:"last_hash"`
+	// ExportSeq 已导出到外部的最大序号
+	ExportSeq uint64     `db:"export_seq" json:"export_seq"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName is the audit chain's database table name.
+func (a AuditChainTable) TableName() table.Name {
+	return table.AuditChainTable
+}
+
+// chainContent is the content of audit which is covered by the chain hash, id is not covered because it is assigned
+// by db after the hash is calculated, created_at is covered as unix seconds so that it's independent of time zone.
+type chainContent struct {
+	TenantID   string                   `json:"tenant_id"`
+	ChainSeq   uint64                   `json:"chain_seq"`
+	PrevHash   string                   `json:"prev_hash"`
+	ResID      string                   `json:"res_id"`
+	CloudResID string                   `json:"cloud_res_id"`
+	ResName    string                   `json:"res_name"`
+	ResType    enumor.AuditResourceType `json:"res_type"`
+	Action     enumor.AuditAction       `json:"action"`
+	BkBizID    int64                    `json:"bk_biz_id"`
+	Vendor     enumor.Vendor            `json:"vendor"`
+	AccountID  string                   `json:"account_id"`
+	Operator   string                   `json:"operator"`
+	Source     enumor.RequestSourceType `json:"source"`
+	Rid        string                   `json:"rid"`
+	AppCode    string                   `json:"app_code"`
+	Detail     json.RawMessage          `json:"detail"`
+	CreatedAt  int64                    `json:"created_at"`
+}
+
+// ChainHash calculate the hash of audit on the tenant's hash chain with its ChainSeq and PrevHash.
+func (a AuditTable) ChainHash(tenantID string) (string, error) {
+	detail, err := canonicalDetail(a.Detail)
+	if err != nil {
+		return "", err
+	}
+
+	createdAt, err := time.Parse(constant.TimeStdFormat, string(a.CreatedAt))
+	if err != nil {
+		return "", fmt.Errorf("parse audit created_at %s failed, err: %v", a.CreatedAt, err)
+	}
+
+	content := chainContent{
+		TenantID:   tenantID,
+		ChainSeq:   a.ChainSeq,
+		PrevHash:   a.PrevHash,
+		ResID:      a.ResID,
+		CloudResID: a.CloudResID,
+		ResName:    a.ResName,
+		ResType:    a.ResType,
+		Action:     a.Action,
+		BkBizID:    a.BkBizID,
+		Vendor:     a.Vendor,
+		AccountID:  a.AccountID,
+		Operator:   a.Operator,
+		Source:     a.Source,
+		Rid:        a.Rid,
+		AppCode:    a.AppCode,
+		Detail:     detail,
+		CreatedAt:  createdAt.Unix(),
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit chain content failed, err: %v", err)
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalDetail encode detail into the same json whether it is the typed detail to be inserted or the generic
+// detail decoded from db, json objects are re-encoded with sorted keys and numbers as float64.
+func canonicalDetail(detail *BasicDetail) (json.RawMessage, error) {
+	if detail == nil {
+		return json.RawMessage("null"), nil
+	}
+
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit detail failed, err: %v", err)
+	}
+
+	var generic interface{}
+	if err = json.Unmarshal(raw, &generic); err != nil {
+		return nil, fmt.Errorf("unmarshal audit detail failed, err: %v", err)
+	}
+
+	return json.Marshal(generic)
+}
@@ -0,0 +1,253 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountBillAllocationRuleColumns defines account_bill_allocation_rule's columns.
+var AccountBillAllocationRuleColumns = utils.MergeColumns(nil, AccountBillAllocationRuleColumnDescriptor)
+
+// AccountBillAllocationRuleColumnDescriptor is account_bill_allocation_rule's column descriptors.
+var AccountBillAllocationRuleColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "matcher", NamedC: "matcher", Type: enumor.Json},
+	{Column: "method", NamedC: "method", Type: enumor.String},
+	{Column: "config", NamedC: "config", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillAllocationRule 账单分摊规则表
+type AccountBillAllocationRule struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// Name 规则名称
+	Name string `db:"name" validate:"lte=255" json:"name"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// Priority 优先级，数值越小越先匹配
+	Priority *uint `db:"priority" json:"priority"`
+	// Enabled 是否启用
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// Matcher 账单明细匹配条件
+	Matcher types.JsonField `db:"matcher" json:"matcher"`
+	// Method 分摊方式
+	Method enumor.BillAllocationMethod `db:"method" json:"method"`
+	// Config 分摊方式配置
+	Config types.JsonField `db:"config" json:"config"`
+	// Memo 备注
+	Memo *string `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+	// Creator 创建人
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 修改人
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回账单分摊规则表名
+func (r *AccountBillAllocationRule) TableName() table.Name {
+	return table.AccountBillAllocationRuleTable
+}
+
+// InsertValidate validate allocation rule on insert
+func (r *AccountBillAllocationRule) InsertValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.Name) == 0 {
+		return errors.New("name is required")
+	}
+	if len(r.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+	if r.Priority == nil {
+		return errors.New("priority is required")
+	}
+	if r.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+	if len(r.Matcher) == 0 {
+		return errors.New("matcher is required")
+	}
+	if err := r.Method.Validate(); err != nil {
+		return err
+	}
+	if len(r.Config) == 0 {
+		return errors.New("config is required")
+	}
+	if len(r.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+	if len(r.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	return validator.Validate.Struct(r)
+}
+
+// UpdateValidate validate allocation rule on update
+func (r *AccountBillAllocationRule) UpdateValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.Method) != 0 {
+		if err := r.Method.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(r.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	if len(r.Creator) != 0 {
+		return errors.New("creator is not allowed")
+	}
+	return validator.Validate.Struct(r)
+}
+
+// AccountBillAllocationResultColumns defines account_bill_allocation_result's columns.
+var AccountBillAllocationResultColumns = utils.MergeColumns(nil, AccountBillAllocationResultColumnDescriptor)
+
+// AccountBillAllocationResultColumnDescriptor is account_bill_allocation_result's column descriptors.
+var AccountBillAllocationResultColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "rule_id", NamedC: "rule_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "root_account_id", NamedC: "root_account_id", Type: enumor.String},
+	{Column: "main_account_id", NamedC: "main_account_id", Type: enumor.String},
+	{Column: "bill_year", NamedC: "bill_year", Type: enumor.Numeric},
+	{Column: "bill_month", NamedC: "bill_month", Type: enumor.Numeric},
+	{Column: "bill_day", NamedC: "bill_day", Type: enumor.Numeric},
+	{Column: "version_id", NamedC: "version_id", Type: enumor.Numeric},
+	{Column: "source_bk_biz_id", NamedC: "source_bk_biz_id", Type: enumor.Numeric},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "cost", NamedC: "cost", Type: enumor.Numeric},
+	{Column: "item_count", NamedC: "item_count", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillAllocationResult 账单分摊结果表，按账单版本记录每条规则分摊到各业务的金额
+type AccountBillAllocationResult struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// RuleID 分摊规则ID
+	RuleID string `db:"rule_id" json:"rule_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// RootAccountID 一级账号ID
+	RootAccountID string `db:"root_account_id" json:"root_account_id"`
+	// MainAccountID 二级账号ID
+	MainAccountID string `db:"main_account_id" json:"main_account_id"`
+	// BillYear 账单年份
+	BillYear int `db:"bill_year" json:"bill_year"`
+	// BillMonth 账单月份
+	BillMonth int `db:"bill_month" json:"bill_month"`
+	// BillDay 账单天
+	BillDay int `db:"bill_day" json:"bill_day"`
+	// VersionID 账单汇总版本号
+	VersionID int `db:"version_id" json:"version_id"`
+	// SourceBkBizID 分摊前账单明细所属业务
+	SourceBkBizID int64 `db:"source_bk_biz_id" json:"source_bk_biz_id"`
+	// BkBizID 分摊后的业务
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Currency 币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// Cost 分摊金额
+	Cost *types.Decimal `db:"cost" json:"cost"`
+	// ItemCount 参与分摊的账单明细数
+	ItemCount uint `db:"item_count" json:"item_count"`
+
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+	// Creator 创建人
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 修改人
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回账单分摊结果表名
+func (r *AccountBillAllocationResult) TableName() table.Name {
+	return table.AccountBillAllocationResultTable
+}
+
+// InsertValidate validate allocation result on insert
+func (r *AccountBillAllocationResult) InsertValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.RuleID) == 0 {
+		return errors.New("rule id is required")
+	}
+	if len(r.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+	if len(r.RootAccountID) == 0 {
+		return errors.New("root account id is required")
+	}
+	if len(r.MainAccountID) == 0 {
+		return errors.New("main account id is required")
+	}
+	if r.BillYear == 0 {
+		return errors.New("bill year is required")
+	}
+	if r.BillMonth == 0 {
+		return errors.New("bill month is required")
+	}
+	if r.VersionID == 0 {
+		return errors.New("version id is required")
+	}
+	if r.Cost == nil {
+		return errors.New("cost is required")
+	}
+	if len(r.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+	if len(r.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DiskSnapshotPolicyColumns defines all the disk_snapshot_policy table's columns.
+var DiskSnapshotPolicyColumns = utils.MergeColumns(nil, DiskSnapshotPolicyColumnDescriptor)
+
+// DiskSnapshotPolicyColumnDescriptor is disk_snapshot_policy's column descriptors.
+var DiskSnapshotPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "disk_ids", NamedC: "disk_ids", Type: enumor.Json},
+	{Column: "cron_expr", NamedC: "cron_expr", Type: enumor.String},
+	{Column: "time_zone", NamedC: "time_zone", Type: enumor.String},
+	{Column: "retention_count", NamedC: "retention_count", Type: enumor.Numeric},
+	{Column: "retention_days", NamedC: "retention_days", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "schedule_id", NamedC: "schedule_id", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DiskSnapshotPolicyTable 云盘快照策略表. 策略按 cron 表达式定时为云盘创建快照，并按保留数量、保留天数清理过期快照，
+// 定时执行依赖异步任务的定时任务流，ScheduleID 为对应的定时任务流ID.
+type DiskSnapshotPolicyTable struct {
+	ID        string        `db:"id" json:"id" validate:"lte=64"`
+	Name      string        `db:"name" json:"name" validate:"lte=64"`
+	Vendor    enumor.Vendor `db:"vendor" json:"vendor" validate:"lte=16"`
+	AccountID string        `db:"account_id" json:"account_id" validate:"lte=64"`
+	BkBizID   int64         `db:"bk_biz_id" json:"bk_biz_id"`
+	// DiskIDs 应用该策略的云盘ID列表
+	DiskIDs  types.StringArray `db:"disk_ids" json:"disk_ids"`
+	CronExpr string            `db:"cron_expr" json:"cron_expr" validate:"lte=64"`
+	TimeZone string            `db:"time_zone" json:"time_zone" validate:"lte=64"`
+	// RetentionCount 每块云盘最多保留的快照数量，0表示不限制
+	RetentionCount *uint32 `db:"retention_count" json:"retention_count"`
+	// RetentionDays 快照保留天数，0表示不限制
+	RetentionDays *uint32    `db:"retention_days" json:"retention_days"`
+	Enabled       *bool      `db:"enabled" json:"enabled"`
+	ScheduleID    string     `db:"schedule_id" json:"schedule_id" validate:"lte=64"`
+	Memo          *string    `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator       string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser       string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt     types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt     types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+}
+
+// TableName return disk_snapshot_policy table name.
+func (t DiskSnapshotPolicyTable) TableName() table.Name {
+	return table.DiskSnapshotPolicyTable
+}
+
+// InsertValidate disk_snapshot_policy table when insert.
+func (t DiskSnapshotPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.CronExpr) == 0 {
+		return errors.New("cron_expr is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate disk_snapshot_policy table when update.
+func (t DiskSnapshotPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 || len(t.AccountID) != 0 {
+		return errors.New("vendor and account_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package idleresource ...
+package idleresource
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// IdleResourceColumns defines all the idle_resource table's columns.
+var IdleResourceColumns = utils.MergeColumns(nil, IdleResourceColumnDescriptor)
+
+// IdleResourceColumnDescriptor is idle_resource's column descriptors.
+var IdleResourceColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_res_id", NamedC: "cloud_res_id", Type: enumor.String},
+	{Column: "res_name", NamedC: "res_name", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "reason", NamedC: "reason", Type: enumor.String},
+	{Column: "cost", NamedC: "cost", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// IdleResourceTable 闲置资源表，由闲置资源分析任务定期刷新
+type IdleResourceTable struct {
+	ID         string                    `db:"id" json:"id" validate:"lte=64"`
+	Vendor     enumor.Vendor             `db:"vendor" json:"vendor" validate:"lte=16"`
+	ResType    enumor.CloudResourceType  `db:"res_type" json:"res_type" validate:"lte=64"`
+	ResID      string                    `db:"res_id" json:"res_id" validate:"lte=64"`
+	CloudResID string                    `db:"cloud_res_id" json:"cloud_res_id" validate:"lte=255"`
+	ResName    string                    `db:"res_name" json:"res_name" validate:"lte=255"`
+	AccountID  string                    `db:"account_id" json:"account_id" validate:"lte=64"`
+	BkBizID    int64                     `db:"bk_biz_id" json:"bk_biz_id"`
+	Region     string                    `db:"region" json:"region" validate:"lte=255"`
+	Reason     enumor.IdleResourceReason `db:"reason" json:"reason" validate:"lte=64"`
+	// Cost 统计周期内该资源的账单费用
+	Cost      *types.Decimal      `db:"cost" json:"cost"`
+	Currency  enumor.CurrencyCode `db:"currency" json:"currency" validate:"lte=16"`
+	Creator   string              `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string              `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time          `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time          `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+}
+
+// TableName return idle_resource table name.
+func (t IdleResourceTable) TableName() table.Name {
+	return table.IdleResourceTable
+}
+
+// InsertValidate idle_resource table when insert.
+func (t IdleResourceTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if err := t.Reason.Validate(); err != nil {
+		return err
+	}
+
+	if t.Cost == nil {
+		return errors.New("cost is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate idle_resource table when update.
+func (t IdleResourceTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Reason) != 0 {
+		if err := t.Reason.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.ResType) != 0 || len(t.ResID) != 0 {
+		return errors.New("res_type and res_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
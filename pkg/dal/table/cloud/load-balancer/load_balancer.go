@@ -66,6 +66,7 @@ var LoadBalancerColumnsDescriptor = utils.ColumnDescriptors{
 	{Column: "sync_time", NamedC: "sync_time", Type: enumor.String},
 	{Column: "tags", NamedC: "tags", Type: enumor.Json},
 	{Column: "extension", NamedC: "extension", Type: enumor.Json},
+	{Column: "recycle_status", NamedC: "recycle_status", Type: enumor.String},
 
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
@@ -103,6 +104,7 @@ type LoadBalancerTable struct {
 	SyncTime             string            `db:"sync_time" json:"sync_time"`
 	Tags                 types.StringMap   `db:"tags" json:"tags"`
 	Extension            types.JsonField   `db:"extension" json:"extension"`
+	RecycleStatus        string            `db:"recycle_status" json:"recycle_status,omitempty"`
 
 	Creator   string     `db:"creator" validate:"lte=64" json:"creator"`
 	Reviser   string     `db:"reviser" validate:"lte=64" json:"reviser"`
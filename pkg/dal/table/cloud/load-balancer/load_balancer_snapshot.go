@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablelb
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// LoadBalancerSnapshotColumns defines all the load_balancer_snapshot table's columns.
+var LoadBalancerSnapshotColumns = utils.MergeColumns(nil, LoadBalancerSnapshotColumnsDescriptor)
+
+// LoadBalancerSnapshotColumnsDescriptor is load_balancer_snapshot's column descriptors.
+var LoadBalancerSnapshotColumnsDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "lb_id", NamedC: "lb_id", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "source", NamedC: "source", Type: enumor.String},
+	{Column: "flow_id", NamedC: "flow_id", Type: enumor.String},
+	{Column: "spec", NamedC: "spec", Type: enumor.Json},
+
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// LoadBalancerSnapshotTable 负载均衡配置快照表
+type LoadBalancerSnapshotTable struct {
+	ID        string                  `db:"id" validate:"lte=64" json:"id"`
+	Vendor    enumor.Vendor           `db:"vendor" validate:"lte=16" json:"vendor"`
+	LbID      string                  `db:"lb_id" validate:"lte=64" json:"lb_id"`
+	AccountID string                  `db:"account_id" validate:"lte=64" json:"account_id"`
+	BkBizID   int64                   `db:"bk_biz_id" json:"bk_biz_id"`
+	Version   uint64                  `db:"version" json:"version"`
+	Source    enumor.LbSnapshotSource `db:"source" validate:"lte=64" json:"source"`
+	FlowID    string                  `db:"flow_id" validate:"lte=64" json:"flow_id"`
+	Spec      types.JsonField         `db:"spec" json:"spec"`
+
+	TenantID  string     `db:"tenant_id" json:"tenant_id"`
+	Creator   string     `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser   string     `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt types.Time `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return table name.
+func (t LoadBalancerSnapshotTable) TableName() table.Name {
+	return table.LoadBalancerSnapshotTable
+}
+
+// InsertValidate validate table when insert.
+func (t LoadBalancerSnapshotTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(t.LbID) == 0 {
+		return errors.New("lb_id is required")
+	}
+
+	if t.Version == 0 {
+		return errors.New("version is required")
+	}
+
+	if err := t.Source.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.Spec) == 0 {
+		return errors.New("spec is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate validate table when update.
+func (t LoadBalancerSnapshotTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser can not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablelb
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// TargetHealthEventColumns defines all the target_health_event table's columns.
+var TargetHealthEventColumns = utils.MergeColumns(nil, TargetHealthEventColumnsDescriptor)
+
+// TargetHealthEventColumnsDescriptor is target_health_event's column descriptors.
+var TargetHealthEventColumnsDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "lb_id", NamedC: "lb_id", Type: enumor.String},
+	{Column: "cloud_lb_id", NamedC: "cloud_lb_id", Type: enumor.String},
+	{Column: "cloud_lbl_id", NamedC: "cloud_lbl_id", Type: enumor.String},
+	{Column: "cloud_rule_id", NamedC: "cloud_rule_id", Type: enumor.String},
+	{Column: "cloud_inst_id", NamedC: "cloud_inst_id", Type: enumor.String},
+	{Column: "ip", NamedC: "ip", Type: enumor.String},
+	{Column: "port", NamedC: "port", Type: enumor.Numeric},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "prev_status", NamedC: "prev_status", Type: enumor.String},
+	{Column: "status_detail", NamedC: "status_detail", Type: enumor.String},
+
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// TargetHealthEventTable RS健康状态变化事件表，只记录状态发生变化以及首次采集到的RS，记录创建后不再修改
+type TargetHealthEventTable struct {
+	ID           string                    `db:"id" validate:"lte=64" json:"id"`
+	Vendor       enumor.Vendor             `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID    string                    `db:"account_id" validate:"lte=64" json:"account_id"`
+	BkBizID      int64                     `db:"bk_biz_id" json:"bk_biz_id"`
+	LbID         string                    `db:"lb_id" validate:"lte=64" json:"lb_id"`
+	CloudLbID    string                    `db:"cloud_lb_id" validate:"lte=255" json:"cloud_lb_id"`
+	CloudLblID   string                    `db:"cloud_lbl_id" validate:"lte=255" json:"cloud_lbl_id"`
+	CloudRuleID  string                    `db:"cloud_rule_id" validate:"lte=255" json:"cloud_rule_id"`
+	CloudInstID  string                    `db:"cloud_inst_id" validate:"lte=255" json:"cloud_inst_id"`
+	IP           string                    `db:"ip" validate:"lte=255" json:"ip"`
+	Port         int64                     `db:"port" json:"port"`
+	Status       enumor.TargetHealthStatus `db:"status" validate:"lte=16" json:"status"`
+	PrevStatus   enumor.TargetHealthStatus `db:"prev_status" validate:"lte=16" json:"prev_status"`
+	StatusDetail string                    `db:"status_detail" validate:"lte=64" json:"status_detail"`
+
+	TenantID  string     `db:"tenant_id" json:"tenant_id"`
+	Creator   string     `db:"creator" validate:"lte=64" json:"creator"`
+	CreatedAt types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return table name.
+func (t TargetHealthEventTable) TableName() table.Name {
+	return table.TargetHealthEventTable
+}
+
+// InsertValidate validate table when insert.
+func (t TargetHealthEventTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(t.LbID) == 0 {
+		return errors.New("lb_id is required")
+	}
+
+	if len(t.CloudLblID) == 0 {
+		return errors.New("cloud_lbl_id is required")
+	}
+
+	if len(t.IP) == 0 {
+		return errors.New("ip is required")
+	}
+
+	if err := t.Status.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.PrevStatus) != 0 {
+		if err := t.PrevStatus.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package sgcompliance ...
+package sgcompliance
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SGComplianceCheckColumns defines all the sg_compliance_check table's columns.
+var SGComplianceCheckColumns = utils.MergeColumns(nil, SGComplianceCheckColumnDescriptor)
+
+// SGComplianceCheckColumnDescriptor is sg_compliance_check's column descriptors.
+var SGComplianceCheckColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "severity", NamedC: "severity", Type: enumor.String},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "conditions", NamedC: "conditions", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// SGComplianceCheckTable 安全组规则自定义合规检查项表
+type SGComplianceCheckTable struct {
+	ID   string `db:"id" json:"id" validate:"lte=64"`
+	Name string `db:"name" json:"name" validate:"lte=64"`
+	// BkBizID 检查项生效的业务，-1 表示对所有业务生效
+	BkBizID  int64                       `db:"bk_biz_id" json:"bk_biz_id"`
+	Severity enumor.SGComplianceSeverity `db:"severity" json:"severity" validate:"lte=16"`
+	Enabled  *bool                       `db:"enabled" json:"enabled"`
+	// Conditions 命中条件，对应 sgcompliance.CheckCondition
+	Conditions types.JsonField `db:"conditions" json:"conditions"`
+	Memo       *string         `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator    string          `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser    string          `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt  types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt  types.Time      `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+}
+
+// TableName return sg_compliance_check table name.
+func (t SGComplianceCheckTable) TableName() table.Name {
+	return table.SGComplianceCheckTable
+}
+
+// InsertValidate sg_compliance_check table when insert.
+func (t SGComplianceCheckTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if err := t.Severity.Validate(); err != nil {
+		return err
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if t.Conditions.IsEmpty() {
+		return errors.New("conditions is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate sg_compliance_check table when update.
+func (t SGComplianceCheckTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Severity) != 0 {
+		if err := t.Severity.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("bk_biz_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// RecyclePolicyColumns defines all the recycle policy table's columns.
+var RecyclePolicyColumns = utils.MergeColumns(nil, RecyclePolicyColumnDescriptor)
+
+// RecyclePolicyColumnDescriptor is RecyclePolicyTable's column descriptors.
+var RecyclePolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "retention_hours", NamedC: "retention_hours", Type: enumor.Numeric},
+	{Column: "notify_before_hours", NamedC: "notify_before_hours", Type: enumor.Numeric},
+	{Column: "time_zone", NamedC: "time_zone", Type: enumor.String},
+	{Column: "purge_windows", NamedC: "purge_windows", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// RecyclePolicyTable 回收站保留策略表
+type RecyclePolicyTable struct {
+	ID string `db:"id" json:"id" validate:"lte=64"`
+	// BkBizID 策略生效的业务，-1 表示对所有业务生效
+	BkBizID int64                    `db:"bk_biz_id" json:"bk_biz_id"`
+	ResType enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	// RetentionHours 资源在回收站中的保留时长，单位小时
+	RetentionHours *uint `db:"retention_hours" json:"retention_hours"`
+	// NotifyBeforeHours 销毁前多少小时通知回收人，0表示不通知
+	NotifyBeforeHours *uint  `db:"notify_before_hours" json:"notify_before_hours"`
+	TimeZone          string `db:"time_zone" json:"time_zone" validate:"lte=64"`
+	// PurgeWindows 允许销毁资源的时间窗口，对应 recyclerecord.PurgeWindow 列表
+	PurgeWindows types.JsonField `db:"purge_windows" json:"purge_windows"`
+	Memo         *string         `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator      string          `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser      string          `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt    types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt    types.Time      `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	// TenantID 租户ID
+	TenantID string `db:"tenant_id" json:"tenant_id"`
+}
+
+// TableName return recycle policy table name.
+func (t RecyclePolicyTable) TableName() table.Name {
+	return table.RecyclePolicyTable
+}
+
+// InsertValidate recycle policy table when insert.
+func (t RecyclePolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID == 0 {
+		return errors.New("bk_biz_id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if t.RetentionHours == nil {
+		return errors.New("retention_hours is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate recycle policy table when update.
+func (t RecyclePolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 || len(t.ResType) != 0 {
+		return errors.New("bk_biz_id and res_type can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
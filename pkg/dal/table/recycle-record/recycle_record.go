@@ -53,6 +53,7 @@ var RecycleRecordColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
 	{Column: "recycled_at", NamedC: "recycled_at", Type: enumor.Time},
+	{Column: "notified", NamedC: "notified", Type: enumor.Boolean},
 }
 
 // RecycleRecordTable is used to save resource's recycle record information.
@@ -93,6 +94,8 @@ type RecycleRecordTable struct {
 	UpdatedAt types.Time `db:"updated_at" validate:"isdefault" json:"updated_at"`
 	// RecycledAt 回收时间
 	RecycledAt time.Time `db:"recycled_at" json:"recycled_at"`
+	// Notified 是否已发送销毁前通知
+	Notified *bool `db:"notified" json:"notified"`
 	// TenantID 租户ID
 	TenantID string `db:"tenant_id" json:"tenant_id"`
 }
@@ -157,7 +160,7 @@ func (r RecycleRecordTable) UpdateValidate() error {
 		return err
 	}
 
-	if len(r.Status) == 0 && len(r.Detail) == 0 {
+	if len(r.Status) == 0 && len(r.Detail) == 0 && r.Notified == nil {
 		return errors.New("one of the update fields must be set")
 	}
 
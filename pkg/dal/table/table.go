@@ -38,8 +38,12 @@ const (
 	IDGenerator Name = "id_generator"
 	// AuditTable is audit table's name
 	AuditTable Name = "audit"
+	// AuditChainTable is audit hash chain head table's name.
+	AuditChainTable Name = "audit_chain"
 	// RecycleRecordTable is recycle record table name
 	RecycleRecordTable Name = "recycle_record"
+	// RecyclePolicyTable is recycle bin retention policy table name
+	RecyclePolicyTable Name = "recycle_policy"
 	// AccountTable is account table's name.
 	AccountTable Name = "account"
 	// SubAccountTable is sub account table's name.
@@ -66,6 +70,8 @@ const (
 	HuaWeiSecurityGroupRuleTable = "huawei_security_group_rule"
 	// AzureSecurityGroupRuleTable is azure security group rule table's name.
 	AzureSecurityGroupRuleTable = "azure_security_group_rule"
+	// SGComplianceCheckTable is security group rule compliance check table's name.
+	SGComplianceCheckTable Name = "sg_compliance_check"
 	// SGNetworkInterfaceRelTable is security group and network interface rel table's name.
 	SGNetworkInterfaceRelTable = "security_group_network_interface_rel"
 	// GcpFirewallRuleTable is gcp firewall rule table's name.
@@ -82,6 +88,12 @@ const (
 	AzureRegionTable Name = "azure_region"
 	// DiskTable is disk table's name.
 	DiskTable Name = "disk"
+	// DiskSnapshotTable is disk snapshot table's name.
+	DiskSnapshotTable Name = "disk_snapshot"
+	// DiskSnapshotPolicyTable is disk snapshot policy table's name.
+	DiskSnapshotPolicyTable Name = "disk_snapshot_policy"
+	// IdleResourceTable is idle resource table's name.
+	IdleResourceTable Name = "idle_resource"
 	// TCloudRegionTable is tcloud region table's name.
 	TCloudRegionTable Name = "tcloud_region"
 	// AwsRegionTable is aws region table's name.
@@ -120,6 +132,12 @@ const (
 	ApplicationTable Name = "application"
 	// ApprovalProcessTable is approval process table name
 	ApprovalProcessTable Name = "approval_process"
+	// ApprovalChainTable is native approval chain table name
+	ApprovalChainTable Name = "approval_chain"
+	// ApprovalTicketTable is native approval ticket table name
+	ApprovalTicketTable Name = "approval_ticket"
+	// AutoApprovalPolicyTable is application auto approval policy table name
+	AutoApprovalPolicyTable Name = "auto_approval_policy"
 	// NetworkInterfaceTable is network interface table's name.
 	NetworkInterfaceTable Name = "network_interface"
 	// NetworkInterfaceCvmRelTable is network interface and cvm rel table's name.
@@ -138,6 +156,10 @@ const (
 	AsyncFlowTable Name = "async_flow"
 	// AsyncFlowTaskTable is async flow task table's name.
 	AsyncFlowTaskTable Name = "async_flow_task"
+	// AsyncFlowScheduleTable is async flow schedule table's name.
+	AsyncFlowScheduleTable Name = "async_flow_schedule"
+	// AsyncWorkerTable is async worker heartbeat table's name.
+	AsyncWorkerTable Name = "async_worker"
 
 	// CloudSelectionSchemeTable is cloud selection scheme table's name.
 	CloudSelectionSchemeTable Name = "cloud_selection_scheme"
@@ -170,6 +192,10 @@ const (
 	ResourceFlowRelTable Name = "resource_flow_rel"
 	// ResourceFlowLockTable is resource_flow_lock table's name.
 	ResourceFlowLockTable Name = "resource_flow_lock"
+	// LoadBalancerSnapshotTable is load_balancer_snapshot table's name.
+	LoadBalancerSnapshotTable Name = "load_balancer_snapshot"
+	// TargetHealthEventTable is target_health_event table's name.
+	TargetHealthEventTable Name = "target_health_event"
 
 	// MainAccountTable is main_account table's name
 	MainAccountTable Name = "main_account"
@@ -198,6 +224,10 @@ const (
 	AccountBillExchangeRateTable = "account_bill_exchange_rate"
 	// AccountBillSyncRecordTable 账单同步记录
 	AccountBillSyncRecordTable = "account_bill_sync_record"
+	// AccountBillAllocationRuleTable 账单分摊规则表
+	AccountBillAllocationRuleTable = "account_bill_allocation_rule"
+	// AccountBillAllocationResultTable 账单分摊结果表
+	AccountBillAllocationResultTable = "account_bill_allocation_result"
 	// TaskDetailTable 任务详情表
 	TaskDetailTable = "task_detail"
 	// TenantTable 租户表
@@ -234,6 +264,7 @@ type TableConfig struct {
 // Key是表名，Value是该表的配置信息
 var TableMap = map[Name]TableConfig{
 	AuditTable:                   {EnableTenant: true},
+	AuditChainTable:              {},
 	AccountTable:                 {EnableTenant: true},
 	SubAccountTable:              {},
 	AccountBizRelTable:           {},
@@ -249,6 +280,7 @@ var TableMap = map[Name]TableConfig{
 	AwsSecurityGroupRuleTable:    {},
 	HuaWeiSecurityGroupRuleTable: {},
 	AzureSecurityGroupRuleTable:  {},
+	SGComplianceCheckTable:       {EnableTenant: true},
 	SGNetworkInterfaceRelTable:   {},
 	GcpFirewallRuleTable:         {EnableTenant: true},
 	HuaWeiRegionTable:            {EnableTenant: true},
@@ -267,11 +299,18 @@ var TableMap = map[Name]TableConfig{
 	CvmTable:                     {EnableTenant: true},
 	ApplicationTable:             {EnableTenant: true},
 	ApprovalProcessTable:         {EnableTenant: true},
+	ApprovalChainTable:           {EnableTenant: true},
+	ApprovalTicketTable:          {EnableTenant: true},
+	AutoApprovalPolicyTable:      {EnableTenant: true},
 	NetworkInterfaceTable:        {EnableTenant: true},
 	NetworkInterfaceCvmRelTable:  {},
 	RecycleRecordTable:           {EnableTenant: true},
+	RecyclePolicyTable:           {EnableTenant: true},
 	EipTable:                     {EnableTenant: true},
 	DiskTable:                    {EnableTenant: true},
+	DiskSnapshotTable:            {EnableTenant: true},
+	DiskSnapshotPolicyTable:      {EnableTenant: true},
+	IdleResourceTable:            {EnableTenant: true},
 	ImageTable:                   {EnableTenant: true},
 	DiskCvmRelTableName:          {},
 	EipCvmRelTableName:           {},
@@ -286,8 +325,10 @@ var TableMap = map[Name]TableConfig{
 	// TODO: 临时方案
 	RecycleRecordTableTaskID: {},
 
-	AsyncFlowTable:     {EnableTenant: true},
-	AsyncFlowTaskTable: {EnableTenant: true},
+	AsyncFlowTable:         {EnableTenant: true},
+	AsyncFlowTaskTable:     {EnableTenant: true},
+	AsyncFlowScheduleTable: {EnableTenant: true},
+	AsyncWorkerTable:       {},
 
 	ArgumentTemplateTable: {EnableTenant: true},
 
@@ -312,6 +353,12 @@ var TableMap = map[Name]TableConfig{
 	ResourceFlowRelTable:            {},
 	ResourceFlowLockTable:           {},
 
+	AccountBillAllocationRuleTable:   {EnableTenant: true},
+	AccountBillAllocationResultTable: {EnableTenant: true},
+
+	LoadBalancerSnapshotTable: {EnableTenant: true},
+	TargetHealthEventTable:    {EnableTenant: true},
+
 	MainAccountTable: {EnableTenant: true},
 	RootAccountTable: {EnableTenant: true},
 
@@ -351,6 +351,12 @@ func (r *Request) Do() *Result {
 		return result
 	}
 
+	if isReadOnly(r.ctx) && !r.isReadRequest() {
+		result.Err = fmt.Errorf("%s %s is rejected, write request is not allowed in read only mode", r.verb,
+			r.WrapURL().Path)
+		return result
+	}
+
 	client := r.capability.Client
 	if client == nil {
 		client = http.DefaultClient
@@ -501,6 +507,61 @@ func isConnectionReset(err error) bool {
 	return false
 }
 
+// readOnlyCtxKey is the context key of read only mode.
+type readOnlyCtxKey struct{}
+
+// WithReadOnly returns a copy of ctx in which write requests are rejected by the rest client, it's used to make sure
+// that dry run operations do not modify any data.
+func WithReadOnly(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, readOnlyCtxKey{}, true)
+}
+
+func isReadOnly(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	readOnly, _ := ctx.Value(readOnlyCtxKey{}).(bool)
+	return readOnly
+}
+
+// readPostPathSuffixes is the allow-list of POST request paths that only query data, a POST request is allowed in
+// read only mode only when its path ends with one of them.
+var readPostPathSuffixes = []string{
+	// data-service query apis
+	"/list",
+	"/count",
+	"/list_with_extension",
+	"/list_by_cond",
+	"/list/all",
+	// cmdb query apis used by resource sync
+	"/list_hosts",
+	"/list_hosts_without_app",
+	"/list_resource_pool_hosts",
+	"/hosts/modules/read",
+}
+
+// isReadRequest check if request is read request, POST request is regarded as read request only if its path is in
+// the readPostPathSuffixes allow-list.
+func (r *Request) isReadRequest() bool {
+	switch r.verb {
+	case GET, HEAD:
+		return true
+	case POST:
+		path := strings.TrimRight(r.WrapURL().Path, "/")
+		for _, suffix := range readPostPathSuffixes {
+			if strings.HasSuffix(path, suffix) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
 // ridFromContext get request id from context.
 func ridFromContext(ctx context.Context) string {
 	if ctx == nil {
@@ -0,0 +1,83 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/kit"
+)
+
+// VerifyAuditChainFunc verify the audit hash chain of tenant from startSeq to endSeq.
+type VerifyAuditChainFunc func(kt *kit.Kit, tenantID string, startSeq, endSeq uint64) (interface{}, error)
+
+// WithVerifyAuditChain init and returns the verify audit chain command, it's used to detect the missing and
+// modified audits of tenant.
+func WithVerifyAuditChain(verify VerifyAuditChainFunc) Cmd {
+	cmd := &defaultCmd{
+		cmd: &Command{
+			Name:  "verify-audit-chain",
+			Usage: "verify the audit hash chain of tenant to detect the missing, duplicated and modified audits",
+			Parameters: []Parameter{
+				{
+					Name:    "tenant_id",
+					Usage:   "the tenant id of the audit hash chain",
+					Default: constant.DefaultTenantID,
+					Value:   new(string),
+				},
+				{
+					Name:    "start_seq",
+					Usage:   "the chain seq to start verifying from, 0 means from the first audit",
+					Default: uint64(0),
+					Value:   new(uint64),
+				},
+				{
+					Name:    "end_seq",
+					Usage:   "the chain seq to end verifying at, 0 means to the latest audit",
+					Default: uint64(0),
+					Value:   new(uint64),
+				},
+			},
+			FromURL: true,
+			Run: func(kt *kit.Kit, params map[string]interface{}) (interface{}, error) {
+				tenantID := constant.DefaultTenantID
+				switch val := params["tenant_id"].(type) {
+				case *string:
+					tenantID = *val
+				case string:
+					tenantID = val
+				}
+
+				return verify(kt, tenantID, uint64Param(params, "start_seq"), uint64Param(params, "end_seq"))
+			},
+		},
+	}
+
+	return cmd
+}
+
+func uint64Param(params map[string]interface{}, name string) uint64 {
+	switch val := params[name].(type) {
+	case *uint64:
+		return *val
+	case uint64:
+		return val
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cmd
+
+import (
+	"hcm/pkg/kit"
+)
+
+// ReEncryptSecretFunc re-encrypt stored secrets with the current key, only count the secrets to re-encrypt if dryRun.
+type ReEncryptSecretFunc func(kt *kit.Kit, dryRun bool) (interface{}, error)
+
+// WithReEncryptSecret init and returns the re-encrypt secret command, it's used after rotating the encryption key.
+func WithReEncryptSecret(reEncrypt ReEncryptSecretFunc) Cmd {
+	cmd := &defaultCmd{
+		cmd: &Command{
+			Name:  "re-encrypt-secret",
+			Usage: "re-encrypt stored account secrets and application contents with the current crypto key",
+			Parameters: []Parameter{{
+				Name:    "dry_run",
+				Usage:   "only count the secrets that need to be re-encrypted without updating them",
+				Default: false,
+				Value:   new(bool),
+			}},
+			FromURL: true,
+			Run: func(kt *kit.Kit, params map[string]interface{}) (interface{}, error) {
+				dryRun := false
+				switch val := params["dry_run"].(type) {
+				case *bool:
+					dryRun = *val
+				case bool:
+					dryRun = val
+				}
+
+				return reEncrypt(kt, dryRun)
+			},
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0042,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加定时任务流表 async_flow_schedule
+*/
+
+START TRANSACTION;
+
+--  1. 定时任务流表
+create table if not exists `async_flow_schedule` (
+    `id` varchar(64) NOT NULL COMMENT '唯一ID',
+    `name` varchar(64) NOT NULL COMMENT '定时任务名称',
+    `flow_name` varchar(64) NOT NULL COMMENT '任务流模版名称',
+    `cron_expr` varchar(64) NOT NULL COMMENT 'cron表达式(分 时 日 月 周)',
+    `time_zone` varchar(64) NOT NULL DEFAULT '' COMMENT 'cron表达式时区，为空时使用UTC',
+    `tasks` json DEFAULT NULL COMMENT '任务流模版任务参数',
+    `enabled` tinyint(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+    `misfire_policy` varchar(16) NOT NULL DEFAULT 'fire_once' COMMENT '错过触发时间的处理策略(fire_once、skip)',
+    `next_fire_time` varchar(64) NOT NULL DEFAULT '' COMMENT '下次触发时间(UTC)',
+    `last_fire_time` varchar(64) NOT NULL DEFAULT '' COMMENT '上次触发时间(UTC)',
+    `last_flow_id` varchar(64) NOT NULL DEFAULT '' COMMENT '上次触发创建的任务流ID',
+    `memo` varchar(255) DEFAULT '' COMMENT '备注',
+    `tenant_id` varchar(64) NOT NULL DEFAULT 'default' COMMENT '租户ID',
+    `creator` varchar(64) NOT NULL COMMENT '创建人',
+    `reviser` varchar(64) NOT NULL COMMENT '修改人',
+    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '该记录创建的时间',
+    `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx_uk_name_tenant_id` (`name`, `tenant_id`),
+    KEY `idx_enabled_next_fire_time` (`enabled`, `next_fire_time`)
+) ENGINE=InnoDB
+  DEFAULT CHARSET=utf8mb4
+  COLLATE=utf8mb4_bin COMMENT='定时任务流表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('async_flow_schedule', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0042' as `sql_ver`;
+
+COMMIT;
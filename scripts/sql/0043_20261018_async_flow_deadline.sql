@@ -0,0 +1,37 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0043,HCMVER=v1.8.7
+
+    Notes:
+    1. 修改`async_flow`表，增加`deadline`字段及(`state`, `deadline`)索引
+*/
+
+START TRANSACTION;
+
+alter table async_flow
+    add `deadline` varchar(64) DEFAULT NULL COMMENT '任务流截止时间(UTC)，超过后取消任务流并回滚' after `worker`;
+alter table async_flow
+    add index idx_state_deadline (state, deadline);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0043' as `sql_ver`;
+
+COMMIT;
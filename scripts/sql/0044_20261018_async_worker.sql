@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0044,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加异步任务节点心跳表 async_worker，用于MySQL选主模式下判断节点是否存活
+*/
+
+START TRANSACTION;
+
+create table if not exists `async_worker` (
+    `worker` varchar(64) NOT NULL COMMENT '节点唯一标识',
+    `heartbeat_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最近一次心跳时间',
+    PRIMARY KEY (`worker`),
+    KEY `idx_heartbeat_at` (`heartbeat_at`)
+) ENGINE=InnoDB
+  DEFAULT CHARSET=utf8mb4
+  COLLATE=utf8mb4_bin COMMENT='异步任务节点心跳表';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0044' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0045,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加云盘快照表 disk_snapshot
+    2. 添加云盘快照策略表 disk_snapshot_policy，策略通过异步任务的定时任务流执行
+*/
+
+START TRANSACTION;
+
+create table if not exists `disk_snapshot`
+(
+    `id`                 varchar(64)        not null COMMENT '唯一ID',
+    `vendor`             varchar(16)        not null COMMENT '云厂商',
+    `account_id`         varchar(64)        not null COMMENT '账号ID',
+    `cloud_id`           varchar(255)       not null COMMENT '云上快照ID',
+    `bk_biz_id`          bigint(1)          not null default -1 COMMENT '业务ID',
+    `name`               varchar(255)       not null COMMENT '快照名称',
+    `region`             varchar(128)       not null COMMENT '地域',
+    `zone`               varchar(128)       not null default '' COMMENT '可用区',
+    `disk_id`            varchar(64)        not null default '' COMMENT '源云盘ID，云盘未同步或已删除时为空',
+    `cloud_disk_id`      varchar(255)       not null default '' COMMENT '源云盘云上ID',
+    `disk_size`          bigint(1) unsigned not null default 0 COMMENT '源云盘大小(GB)',
+    `status`             varchar(64)        not null default '' COMMENT '快照状态',
+    `encrypted`          boolean                     default false COMMENT '是否加密',
+    `policy_id`          varchar(64)        not null default '' COMMENT '创建该快照的快照策略ID，手动创建时为空',
+    `memo`               varchar(255)                default '' COMMENT '备注',
+    `cloud_created_time` varchar(64)        not null default '' COMMENT '云上创建时间',
+    `extension`          json               not null COMMENT '云厂商差异字段',
+    `tenant_id`          varchar(64)        not null default 'default' COMMENT '租户ID',
+    `creator`            varchar(64)        not null COMMENT '创建人',
+    `reviser`            varchar(64)        not null COMMENT '修改人',
+    `created_at`         timestamp          not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`         timestamp          not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_cloud_id_vendor_tenant_id` (`cloud_id`, `vendor`, `tenant_id`),
+    key `idx_account_id_region` (`account_id`, `region`),
+    key `idx_disk_id` (`disk_id`),
+    key `idx_policy_id` (`policy_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='云盘快照表';
+
+create table if not exists `disk_snapshot_policy`
+(
+    `id`              varchar(64)  not null COMMENT '唯一ID',
+    `name`            varchar(64)  not null COMMENT '策略名称',
+    `vendor`          varchar(16)  not null COMMENT '云厂商',
+    `account_id`      varchar(64)  not null COMMENT '账号ID',
+    `bk_biz_id`       bigint(1)    not null default -1 COMMENT '业务ID',
+    `disk_ids`        json         not null COMMENT '应用该策略的云盘ID列表',
+    `cron_expr`       varchar(64)  not null COMMENT 'cron表达式(分 时 日 月 周)',
+    `time_zone`       varchar(64)  not null default '' COMMENT 'cron表达式时区，为空时使用UTC',
+    `retention_count` int unsigned not null default 0 COMMENT '每块云盘最多保留的快照数量，0表示不限制',
+    `retention_days`  int unsigned not null default 0 COMMENT '快照保留天数，0表示不限制',
+    `enabled`         tinyint(1)   not null default 1 COMMENT '是否启用',
+    `schedule_id`     varchar(64)  not null default '' COMMENT '关联的异步任务定时任务流ID',
+    `memo`            varchar(255)          default '' COMMENT '备注',
+    `tenant_id`       varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`         varchar(64)  not null COMMENT '创建人',
+    `reviser`         varchar(64)  not null COMMENT '修改人',
+    `created_at`      timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`      timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name_tenant_id` (`name`, `tenant_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='云盘快照策略表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('disk_snapshot', '0'),
+       ('disk_snapshot_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0045' as `sql_ver`;
+
+COMMIT;
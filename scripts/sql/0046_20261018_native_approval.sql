@@ -0,0 +1,81 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0046,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加内置审批引擎的审批链表 approval_chain
+    2. 添加内置审批引擎的审批单表 approval_ticket
+*/
+
+START TRANSACTION;
+
+create table if not exists `approval_chain`
+(
+    `id`               varchar(64)  not null COMMENT '唯一ID',
+    `application_type` varchar(64)  not null COMMENT '申请单类型',
+    `bk_biz_id`        bigint(1)    not null default -1 COMMENT '业务ID，-1表示该申请单类型的默认审批链',
+    `nodes`            json         not null COMMENT '审批节点列表',
+    `memo`             varchar(255)          default '' COMMENT '备注',
+    `tenant_id`        varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`          varchar(64)  not null COMMENT '创建人',
+    `reviser`          varchar(64)  not null COMMENT '修改人',
+    `created_at`       timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_application_type_bk_biz_id_tenant_id` (`application_type`, `bk_biz_id`, `tenant_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='内置审批链表';
+
+create table if not exists `approval_ticket`
+(
+    `id`               varchar(64)  not null COMMENT '唯一ID',
+    `application_id`   varchar(64)  not null COMMENT '申请单ID',
+    `application_type` varchar(64)  not null COMMENT '申请单类型',
+    `title`            varchar(255) not null default '' COMMENT '审批单标题',
+    `applicant`        varchar(64)  not null COMMENT '申请人',
+    `chain_id`         varchar(64)  not null COMMENT '审批链ID',
+    `nodes`            json         not null COMMENT '创建审批单时审批链节点的快照',
+    `current_node`     int unsigned not null default 0 COMMENT '当前审批节点下标',
+    `approvers`        json         not null COMMENT '当前节点的审批人',
+    `status`           varchar(32)  not null COMMENT '审批单状态',
+    `records`          json         not null COMMENT '审批操作记录',
+    `version`          int unsigned not null default 0 COMMENT '乐观锁版本号',
+    `reminded_at`      varchar(64)  not null default '' COMMENT '最近一次通知审批人的时间(UTC)',
+    `tenant_id`        varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`          varchar(64)  not null COMMENT '创建人',
+    `reviser`          varchar(64)  not null COMMENT '修改人',
+    `created_at`       timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_application_id` (`application_id`),
+    key `idx_status_reminded_at` (`status`, `reminded_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='内置审批单表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('approval_chain', '0'),
+       ('approval_ticket', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0046' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0047,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加申请单自动审批策略表 auto_approval_policy
+    2. 申请单表 application 添加匹配的自动审批策略ID字段 auto_approval_policy_id
+*/
+
+START TRANSACTION;
+
+create table if not exists `auto_approval_policy`
+(
+    `id`               varchar(64)  not null COMMENT '唯一ID',
+    `name`             varchar(64)  not null COMMENT '策略名称',
+    `application_type` varchar(64)  not null COMMENT '申请单类型',
+    `priority`         int unsigned not null default 0 COMMENT '优先级，数值越小越优先匹配',
+    `enabled`          boolean      not null default true COMMENT '是否启用',
+    `conditions`       json         not null COMMENT '自动审批条件',
+    `memo`             varchar(255)          default '' COMMENT '备注',
+    `tenant_id`        varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`          varchar(64)  not null COMMENT '创建人',
+    `reviser`          varchar(64)  not null COMMENT '修改人',
+    `created_at`       timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`       timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name_tenant_id` (`name`, `tenant_id`),
+    key `idx_application_type_enabled` (`application_type`, `enabled`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='申请单自动审批策略表';
+
+alter table `application`
+    add column `auto_approval_policy_id` varchar(64) not null default '' COMMENT '匹配的自动审批策略ID' after `memo`;
+
+insert into id_generator(`resource`, `max_id`)
+values ('auto_approval_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0047' as `sql_ver`;
+
+COMMIT;
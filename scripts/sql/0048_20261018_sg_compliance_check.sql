@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0048,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加安全组规则自定义合规检查项表 sg_compliance_check
+*/
+
+START TRANSACTION;
+
+create table if not exists `sg_compliance_check`
+(
+    `id`         varchar(64)  not null COMMENT '唯一ID',
+    `name`       varchar(64)  not null COMMENT '检查项名称',
+    `bk_biz_id`  bigint       not null default -1 COMMENT '生效的业务ID，-1表示对所有业务生效',
+    `severity`   varchar(16)  not null COMMENT '风险等级(low、medium、high)',
+    `enabled`    boolean      not null default true COMMENT '是否启用',
+    `conditions` json         not null COMMENT '命中条件',
+    `memo`       varchar(255)          default '' COMMENT '备注',
+    `tenant_id`  varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`    varchar(64)  not null COMMENT '创建人',
+    `reviser`    varchar(64)  not null COMMENT '修改人',
+    `created_at` timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name_tenant_id` (`name`, `tenant_id`),
+    key `idx_bk_biz_id_enabled` (`bk_biz_id`, `enabled`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='安全组规则自定义合规检查项表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('sg_compliance_check', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0048' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0049,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加回收站保留策略表 recycle_policy
+    2. 回收记录增加是否已发送销毁前通知字段 notified
+    3. 负载均衡、安全组增加回收状态 recycle_status 字段
+*/
+
+START TRANSACTION;
+
+create table if not exists `recycle_policy`
+(
+    `id`                  varchar(64)  not null COMMENT '唯一ID',
+    `bk_biz_id`           bigint       not null default -1 COMMENT '生效的业务ID，-1表示对所有业务生效',
+    `res_type`            varchar(64)  not null COMMENT '资源类型',
+    `retention_hours`     bigint       not null COMMENT '资源在回收站中的保留时长，单位小时',
+    `notify_before_hours` bigint       not null default 0 COMMENT '销毁前多少小时通知回收人，0表示不通知',
+    `time_zone`           varchar(64)           default '' COMMENT '销毁时间窗口使用的时区',
+    `purge_windows`       json                  default null COMMENT '允许销毁资源的时间窗口，为空表示不限制',
+    `memo`                varchar(255)          default '' COMMENT '备注',
+    `tenant_id`           varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`             varchar(64)  not null COMMENT '创建人',
+    `reviser`             varchar(64)  not null COMMENT '修改人',
+    `created_at`          timestamp    not null default current_timestamp COMMENT '该记录创建的时间',
+    `updated_at`          timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_res_type_tenant_id` (`bk_biz_id`, `res_type`, `tenant_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='回收站保留策略表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('recycle_policy', '0');
+
+alter table `recycle_record`
+    add column `notified` boolean not null default false COMMENT '是否已发送销毁前通知';
+
+alter table `load_balancer`
+    add column `recycle_status` varchar(32) default '';
+
+alter table `security_group`
+    add column `recycle_status` varchar(32) default '';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0049' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0050,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加闲置资源表 idle_resource
+*/
+
+START TRANSACTION;
+
+create table if not exists `idle_resource`
+(
+    `id`           varchar(64)     not null COMMENT '唯一ID',
+    `vendor`       varchar(16)     not null COMMENT '云厂商',
+    `res_type`     varchar(64)     not null COMMENT '资源类型(cvm、disk、eip、load_balancer)',
+    `res_id`       varchar(64)     not null COMMENT '资源ID',
+    `cloud_res_id` varchar(255)    not null COMMENT '云资源ID',
+    `res_name`     varchar(255)             default '' COMMENT '资源名称',
+    `account_id`   varchar(64)     not null COMMENT '账号ID',
+    `bk_biz_id`    bigint          not null default -1 COMMENT '业务ID',
+    `region`       varchar(255)             default '' COMMENT '地域',
+    `reason`       varchar(64)     not null COMMENT '闲置原因',
+    `cost`         decimal(38, 10) not null default 0 COMMENT '统计周期内的费用',
+    `currency`     varchar(16)              default '' COMMENT '币种',
+    `tenant_id`    varchar(64)     not null default 'default' COMMENT '租户ID',
+    `creator`      varchar(64)     not null COMMENT '创建人',
+    `reviser`      varchar(64)     not null COMMENT '修改人',
+    `created_at`   timestamp       not null default current_timestamp COMMENT '首次识别为闲置的时间',
+    `updated_at`   timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_res_id_tenant_id` (`res_type`, `res_id`, `tenant_id`),
+    key `idx_bk_biz_id_res_type` (`bk_biz_id`, `res_type`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='闲置资源表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('idle_resource', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0050' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0051,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加账单分摊规则表 account_bill_allocation_rule
+    2. 添加账单分摊结果表 account_bill_allocation_result
+*/
+
+START TRANSACTION;
+
+create table if not exists `account_bill_allocation_rule`
+(
+    `id`         varchar(64)  not null COMMENT '唯一ID',
+    `name`       varchar(255) not null COMMENT '规则名称',
+    `vendor`     varchar(16)  not null COMMENT '云厂商',
+    `priority`   int unsigned not null default 0 COMMENT '优先级，数值越小优先级越高',
+    `enabled`    tinyint(1)   not null default 1 COMMENT '是否启用',
+    `matcher`    json         not null COMMENT '账单明细匹配条件',
+    `method`     varchar(32)  not null COMMENT '分摊方式(fixed_ratio、res_usage、tag)',
+    `config`     json         not null COMMENT '分摊方式配置',
+    `memo`       varchar(255)          default '' COMMENT '备注',
+    `tenant_id`  varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`    varchar(64)  not null COMMENT '创建人',
+    `reviser`    varchar(64)  not null COMMENT '修改人',
+    `created_at` timestamp    not null default current_timestamp,
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name_tenant_id` (`name`, `tenant_id`),
+    key `idx_vendor_enabled` (`vendor`, `enabled`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='账单分摊规则表';
+
+create table if not exists `account_bill_allocation_result`
+(
+    `id`               varchar(64)     not null COMMENT '唯一ID',
+    `rule_id`          varchar(64)     not null COMMENT '分摊规则ID',
+    `vendor`           varchar(16)     not null COMMENT '云厂商',
+    `root_account_id`  varchar(64)     not null COMMENT '一级账号ID',
+    `main_account_id`  varchar(64)     not null COMMENT '二级账号ID',
+    `bill_year`        int             not null COMMENT '账单年份',
+    `bill_month`       tinyint         not null COMMENT '账单月份',
+    `bill_day`         tinyint         not null COMMENT '账单天',
+    `version_id`       int             not null COMMENT '账单版本号',
+    `source_bk_biz_id` bigint          not null default -1 COMMENT '分摊前业务ID',
+    `bk_biz_id`        bigint          not null default -1 COMMENT '分摊后业务ID',
+    `currency`         varchar(16)     not null COMMENT '币种',
+    `cost`             decimal(38, 10) not null COMMENT '分摊金额',
+    `item_count`       int unsigned    not null default 0 COMMENT '参与分摊的账单明细数',
+    `tenant_id`        varchar(64)     not null default 'default' COMMENT '租户ID',
+    `creator`          varchar(64)     not null COMMENT '创建人',
+    `reviser`          varchar(64)     not null COMMENT '修改人',
+    `created_at`       timestamp       not null default current_timestamp,
+    `updated_at`       timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_main_account_bill_date` (`main_account_id`, `bill_year`, `bill_month`, `bill_day`, `version_id`),
+    key `idx_rule_id` (`rule_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='账单分摊结果表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_allocation_rule', '0'),
+       ('account_bill_allocation_result', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0051' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0052,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加负载均衡配置快照表 load_balancer_snapshot
+*/
+
+START TRANSACTION;
+
+create table if not exists `load_balancer_snapshot`
+(
+    `id`         varchar(64)  not null COMMENT '唯一ID',
+    `vendor`     varchar(16)  not null COMMENT '云厂商',
+    `lb_id`      varchar(64)  not null COMMENT '负载均衡ID',
+    `account_id` varchar(64)  not null COMMENT '账号ID',
+    `bk_biz_id`  bigint       not null default -1 COMMENT '业务ID',
+    `version`    int unsigned not null COMMENT '快照版本号，同一负载均衡下递增',
+    `source`     varchar(64)  not null COMMENT '快照来源(batch_bind_rs、batch_unbind_rs、batch_modify_rs_weight、spec_apply、rollback)',
+    `flow_id`    varchar(64)           default '' COMMENT '快照后执行变更的异步任务ID',
+    `spec`       json         not null COMMENT '负载均衡监听器、规则、目标组、RS权重配置',
+    `tenant_id`  varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`    varchar(64)  not null COMMENT '创建人',
+    `reviser`    varchar(64)  not null COMMENT '修改人',
+    `created_at` timestamp    not null default current_timestamp,
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_lb_id_version` (`lb_id`, `version`),
+    key `idx_flow_id` (`flow_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='负载均衡配置快照表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('load_balancer_snapshot', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0052' as `sql_ver`;
+
+COMMIT;
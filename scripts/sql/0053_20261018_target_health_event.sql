@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0053,HCMVER=v1.8.7
+
+    Notes:
+    1. 添加RS健康状态变化事件表 target_health_event
+*/
+
+START TRANSACTION;
+
+create table if not exists `target_health_event`
+(
+    `id`            varchar(64)  not null COMMENT '唯一ID',
+    `vendor`        varchar(16)  not null COMMENT '云厂商',
+    `account_id`    varchar(64)  not null COMMENT '账号ID',
+    `bk_biz_id`     bigint       not null default -1 COMMENT '业务ID',
+    `lb_id`         varchar(64)  not null COMMENT '负载均衡ID',
+    `cloud_lb_id`   varchar(255) not null COMMENT '云负载均衡ID',
+    `cloud_lbl_id`  varchar(255) not null COMMENT '云监听器ID',
+    `cloud_rule_id` varchar(255)          default '' COMMENT '云规则ID，四层监听器为空',
+    `cloud_inst_id` varchar(255)          default '' COMMENT 'RS云实例ID',
+    `ip`            varchar(255) not null COMMENT 'RS的IP',
+    `port`          bigint       not null COMMENT 'RS的端口',
+    `status`        varchar(16)  not null COMMENT '变化后的健康状态(healthy、unhealthy)',
+    `prev_status`   varchar(16)           default '' COMMENT '变化前的健康状态，首次采集时为空',
+    `status_detail` varchar(64)           default '' COMMENT '健康状态详情',
+    `tenant_id`     varchar(64)  not null default 'default' COMMENT '租户ID',
+    `creator`       varchar(64)  not null COMMENT '创建人',
+    `created_at`    timestamp    not null default current_timestamp COMMENT '状态变化的采集时间',
+    primary key (`id`),
+    key `idx_bk_biz_id_created_at` (`bk_biz_id`, `created_at`),
+    key `idx_lb_id` (`lb_id`),
+    key `idx_created_at` (`created_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='RS健康状态变化事件表';
+
+insert into id_generator(`resource`, `max_id`)
+values ('target_health_event', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0053' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,50 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0054,HCMVER=v1.8.7
+
+    Notes:
+    1. 修改`audit`表，增加哈希链字段`chain_seq`、`prev_hash`、`hash`及(`tenant_id`, `chain_seq`)索引
+    2. 添加审计哈希链头表 audit_chain，记录每个租户哈希链的最新序号、哈希及外部导出进度
+*/
+
+START TRANSACTION;
+
+alter table `audit`
+    add column `chain_seq` bigint(1) unsigned not null default 0 COMMENT '租户内哈希链序号，从1开始连续递增，0表示未入链的历史记录',
+    add column `prev_hash` varchar(64)        not null default '' COMMENT '哈希链上前一条审计记录的哈希',
+    add column `hash`      varchar(64)        not null default '' COMMENT '当前审计记录的哈希',
+    add index `idx_tenant_id_chain_seq` (`tenant_id`, `chain_seq`);
+
+create table if not exists `audit_chain`
+(
+    `tenant_id`  varchar(64)        not null COMMENT '租户ID',
+    `last_seq`   bigint(1) unsigned not null default 0 COMMENT '哈希链最新序号',
+    `last_hash`  varchar(64)        not null default '' COMMENT '哈希链最新记录的哈希',
+    `export_seq` bigint(1) unsigned not null default 0 COMMENT '已导出到外部的最大序号',
+    `updated_at` timestamp          not null default current_timestamp on update current_timestamp COMMENT '更新时间',
+    primary key (`tenant_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin comment ='审计哈希链头表';
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.8.7' as `hcm_ver`, '0054' as `sql_ver`;
+
+COMMIT;
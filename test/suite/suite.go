@@ -128,6 +128,7 @@ func ClearData() error {
 		table.AccountSyncDetailTable,
 		table.AccountBizRelTable,
 		table.AuditTable,
+		table.AuditChainTable,
 		table.VpcTable,
 		table.SubnetTable,
 		table.RouteTableTable,